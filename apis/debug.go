@@ -0,0 +1,42 @@
+package apis
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimepprof "runtime/pprof"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerDebugRoutes 注册运行时诊断接口，需要在认证中间件之后注册，只有交易角色可以访问
+// /debug/pprof/ 为标准 pprof 接口，可直接用 go tool pprof 采集；/debug/vars 为 expvar；/debug/goroutines 输出所有goroutine堆栈
+func registerDebugRoutes(r *gin.Engine) {
+	debug := r.Group("/debug")
+	{
+		debug.GET("/pprof/", gin.WrapF(pprof.Index))          // pprof 首页和可用的 profile 列表
+		debug.GET("/pprof/cmdline", gin.WrapF(pprof.Cmdline)) // 进程启动参数
+		debug.GET("/pprof/profile", gin.WrapF(pprof.Profile)) // CPU profile，seconds 参数指定采集时长
+		debug.GET("/pprof/symbol", gin.WrapF(pprof.Symbol))   // 程序计数器转换为函数名
+		debug.POST("/pprof/symbol", gin.WrapF(pprof.Symbol))  // 程序计数器转换为函数名
+		debug.GET("/pprof/trace", gin.WrapF(pprof.Trace))     // 执行追踪，seconds 参数指定采集时长
+		debug.GET("/pprof/:profile", servePprofProfile)       // heap、goroutine、allocs 等命名 profile
+		debug.GET("/vars", gin.WrapH(expvar.Handler()))       // expvar 变量，包含 memstats 和启动参数
+		debug.GET("/goroutines", dumpGoroutines)              // 所有goroutine的完整堆栈
+	}
+}
+
+// servePprofProfile 输出命名 profile，debug 参数与标准 pprof 接口一致
+func servePprofProfile(c *gin.Context) {
+	pprof.Handler(c.Param("profile")).ServeHTTP(c.Writer, c.Request)
+}
+
+// dumpGoroutines 以文本输出所有goroutine的完整堆栈，首行为goroutine数量
+func dumpGoroutines(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "goroutines: %d\n\n", runtime.NumGoroutine())
+	runtimepprof.Lookup("goroutine").WriteTo(c.Writer, 2)
+}
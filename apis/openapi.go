@@ -0,0 +1,199 @@
+package apis
+
+import (
+	"net/http"
+	"strings"
+	"trading_assistant/controllers"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/logging"
+	"trading_assistant/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openAPIVersion 接口契约版本，接口有不兼容变更时递增
+const openAPIVersion = "1.0.0"
+
+// latencyResponse 执行延迟统计响应
+type latencyResponse struct {
+	Overall      models.LatencyStats            `json:"overall"`
+	ByActionType map[string]models.LatencyStats `json:"by_action_type"`
+	Recent       []*models.ExecutionLatency     `json:"recent"`
+}
+
+// pnlResponse 已实现盈亏统计响应
+type pnlResponse struct {
+	Source           string                    `json:"source"`
+	From             string                    `json:"from"`
+	To               string                    `json:"to"`
+	GroupBy          string                    `json:"group_by"`
+	Total            *models.PnLSummary        `json:"total"`
+	Groups           []*models.PnLSummary      `json:"groups"`
+	OpenFunding      []*models.PositionFunding `json:"open_funding"`
+	OpenFundingTotal float64                   `json:"open_funding_total"`
+}
+
+// equityResponse 账户权益曲线响应
+type equityResponse struct {
+	Source         string                   `json:"source"`
+	Latest         *models.EquitySnapshot   `json:"latest"`
+	MaxDrawdownPct float64                  `json:"max_drawdown_pct"`
+	Snapshots      []*models.EquitySnapshot `json:"snapshots"`
+}
+
+// openAPIOperations 对外提供契约的接口：价格预估、币种、统计和Freqtrade相关接口
+func openAPIOperations() []openapi.Operation {
+	limitParam := openapi.Param{Name: "limit", Type: "integer", Description: "最多读取的记录数"}
+	symbolParam := openapi.Param{Name: "symbol", Description: "币种 MarketID，如 BTCUSDT"}
+
+	return []openapi.Operation{
+		// 认证
+		{Method: "POST", Path: "/api/v1/auth/login", Tag: "auth", Summary: "用户登录", Body: controllers.LoginRequest{}, Response: controllers.LoginResponse{}, Public: true},
+
+		// 价格预估
+		{Method: "GET", Path: "/api/v1/estimates/all", Tag: "estimates", Summary: "获取所有价格预估", Query: []openapi.Param{symbolParam}, Response: []*models.PriceEstimate{}},
+		{Method: "POST", Path: "/api/v1/estimates", Tag: "estimates", Summary: "创建价格预估", Body: controllers.PriceEstimateRequest{}, Response: models.PriceEstimate{}},
+		{Method: "POST", Path: "/api/v1/estimates/preview", Tag: "estimates", Summary: "试算价格预估（不保存）", Body: controllers.PriceEstimateRequest{}, Response: models.EstimatePreview{}},
+		{Method: "PATCH", Path: "/api/v1/estimates/:id", Tag: "estimates", Summary: "编辑价格预估", Description: "携带 updated_at 做乐观并发检查，冲突时返回 409", Body: controllers.UpdatePriceEstimateRequest{}, Response: models.PriceEstimate{}},
+		{Method: "PUT", Path: "/api/v1/estimates/:id/toggle", Tag: "estimates", Summary: "切换价格预估监听状态", Description: "携带 updated_at 做乐观并发检查，冲突或预估已不在监听中时返回 409", Body: controllers.TogglePriceEstimateRequest{}, Response: models.PriceEstimate{}},
+		{Method: "GET", Path: "/api/v1/estimates/:id/events", Tag: "estimates", Summary: "获取价格预估的时间线", Description: "按时间顺序返回创建、启停、接近触发、跳过、触发、下单和持仓确认等事件，用于排查预估为何触发或未触发", Query: []openapi.Param{
+			{Name: "limit", Type: "integer", Description: "只返回最近的记录数，默认全部"},
+		}, Response: []*models.EstimateEvent{}, List: true},
+		{Method: "DELETE", Path: "/api/v1/estimates/:id", Tag: "estimates", Summary: "删除价格预估"},
+		{Method: "DELETE", Path: "/api/v1/estimates/clear", Tag: "estimates", Summary: "清理非监听中的价格预估"},
+		{Method: "POST", Path: "/api/v1/estimates/grid", Tag: "estimates", Summary: "在价格区间内生成网格预估", Body: controllers.GridEstimateRequest{}, Response: []*models.PriceEstimate{}, List: true},
+		{Method: "GET", Path: "/api/v1/estimates/grid/:grid_id", Tag: "estimates", Summary: "获取网格的所有档位", Response: []*models.PriceEstimate{}, List: true},
+		{Method: "PUT", Path: "/api/v1/estimates/grid/:grid_id/toggle", Tag: "estimates", Summary: "暂停或恢复整个网格"},
+		{Method: "DELETE", Path: "/api/v1/estimates/grid/:grid_id", Tag: "estimates", Summary: "删除整个网格"},
+		{Method: "POST", Path: "/api/v1/backtest/estimate", Tag: "estimates", Summary: "用历史K线回放价格预估", Description: "回放已缓存的K线判断预估会在何时触发，并计算触发后到窗口结束的假设收益，不会保存或下单", Body: controllers.BacktestEstimateRequest{}, Response: models.EstimateBacktest{}},
+
+		// 币种
+		{Method: "GET", Path: "/api/v1/coins", Tag: "coins", Summary: "获取所有币种", Query: []openapi.Param{
+			{Name: "selected", Type: "boolean", Description: "只返回选中的币种"},
+			{Name: "include_selection", Type: "boolean", Description: "附带选中状态"},
+		}, Response: []*models.Coin{}, List: true},
+		{Method: "GET", Path: "/api/v1/coins/selected", Tag: "coins", Summary: "获取选中的币种", Response: []controllers.CoinWithTier{}, List: true},
+		{Method: "POST", Path: "/api/v1/coins/select", Tag: "coins", Summary: "选中或取消选中币种", Body: controllers.SelectCoinRequest{}},
+		{Method: "POST", Path: "/api/v1/coins/bulk-select", Tag: "coins", Summary: "批量选中或取消选中币种", Body: controllers.BulkSelectCoinsRequest{}},
+		{Method: "POST", Path: "/api/v1/coins/select-by-filter", Tag: "coins", Summary: "按条件批量选币", Body: controllers.SelectCoinsByFilterRequest{}},
+		{Method: "PUT", Path: "/api/v1/coins/tier", Tag: "coins", Summary: "更新币种等级", Body: controllers.UpdateCoinTierRequest{}},
+		{Method: "POST", Path: "/api/v1/coins/sync", Tag: "coins", Summary: "同步币种"},
+		{Method: "POST", Path: "/api/v1/coins/whitelist/sync", Tag: "coins", Summary: "立即同步Freqtrade白名单", Query: []openapi.Param{
+			{Name: "dry_run", Type: "boolean", Description: "只返回差异，不触发Freqtrade重新加载"},
+		}, Response: core.WhitelistSyncResult{}},
+		{Method: "GET", Path: "/api/v1/coins/whitelist", Tag: "coins", Summary: "获取最近一次应用的Freqtrade白名单", Response: core.WhitelistSyncResult{}},
+		{Method: "POST", Path: "/api/v1/coins/auto-select/rebalance", Tag: "coins", Summary: "按自动选币策略立即调整选中币种", Query: []openapi.Param{
+			{Name: "dry_run", Type: "boolean", Description: "只返回差异，不修改选中币种"},
+		}, Response: models.CoinSelectionChange{}},
+		{Method: "GET", Path: "/api/v1/coins/auto-select/changes", Tag: "coins", Summary: "获取自动选币策略的调整记录", Query: []openapi.Param{
+			limitParam,
+		}, Response: []*models.CoinSelectionChange{}, List: true},
+		{Method: "GET", Path: "/api/v1/denylist", Tag: "coins", Summary: "获取禁止交易名单", Response: []*models.DenylistEntry{}, List: true},
+		{Method: "POST", Path: "/api/v1/denylist", Tag: "coins", Summary: "添加禁止交易名单条目", Description: "命中的币种不能被选中、不进入Freqtrade白名单，也不能开仓或加仓；已选中的命中币种会被取消选中", Body: controllers.DenylistEntryRequest{}},
+		{Method: "DELETE", Path: "/api/v1/denylist/:type/:value", Tag: "coins", Summary: "删除禁止交易名单条目"},
+
+		// 统计
+		{Method: "GET", Path: "/api/v1/analytics/latency", Tag: "analytics", Summary: "获取触发执行延迟统计", Query: []openapi.Param{
+			limitParam, symbolParam,
+			{Name: "action_type", Description: "操作类型", Enum: []string{models.ActionTypeOpen, models.ActionTypeAddition, models.ActionTypeTakeProfit, models.ActionTypeStopLoss}},
+		}, Response: latencyResponse{}, List: true},
+		{Method: "GET", Path: "/api/v1/analytics/pnl", Tag: "analytics", Summary: "获取已实现盈亏统计", Query: []openapi.Param{
+			{Name: "source", Description: "盈亏来源", Enum: []string{models.PnLSourceFreqtrade, models.PnLSourcePaper}},
+			{Name: "group_by", Description: "分组方式", Enum: []string{core.PnLGroupByDay, core.PnLGroupByWeek, core.PnLGroupBySymbol}},
+			{Name: "from", Description: "开始日期，格式 2006-01-02"},
+			{Name: "to", Description: "结束日期，格式 2006-01-02"},
+			symbolParam,
+		}, Response: pnlResponse{}, List: true},
+		{Method: "GET", Path: "/api/v1/analytics/equity", Tag: "analytics", Summary: "获取账户权益曲线", Query: []openapi.Param{
+			{Name: "source", Description: "权益来源，默认按当前交易模式", Enum: []string{models.PnLSourceFreqtrade, models.PnLSourcePaper}},
+			{Name: "hours", Type: "integer", Description: "最近多少小时，默认168"},
+			limitParam,
+		}, Response: equityResponse{}},
+		{Method: "GET", Path: "/api/v1/stats/:symbol", Tag: "analytics", Summary: "获取币种时段VWAP、24小时已实现波动率和ATR", Query: []openapi.Param{
+			{Name: "exchange", Description: "交易所，默认主交易所"},
+		}, Response: models.SymbolStats{}},
+		{Method: "GET", Path: "/api/v1/liquidations/:symbol", Tag: "analytics", Summary: "获取币种按分钟汇总的多空强平额", Query: []openapi.Param{
+			{Name: "exchange", Description: "交易所，默认主交易所"},
+			{Name: "minutes", Type: "integer", Description: "最近多少分钟，默认60，最大1440"},
+		}, Response: models.LiquidationSummary{}},
+		{Method: "GET", Path: "/api/v1/open-interest/:symbol", Tag: "analytics", Summary: "获取币种持仓量时间序列和5分钟/1小时变化率", Query: []openapi.Param{
+			{Name: "exchange", Description: "交易所，默认主交易所"},
+			{Name: "hours", Type: "integer", Description: "最近多少小时，默认24，最大168"},
+		}, Response: models.OpenInterestSummary{}},
+		{Method: "GET", Path: "/api/v1/screener", Tag: "analytics", Summary: "按行情指标对所有币种排序", Description: "只使用已缓存的行情数据，响应另带 total、page、pageSize、totalPages 分页字段", Query: []openapi.Param{
+			{Name: "exchange", Description: "交易所，默认主交易所"},
+			{Name: "sort", Description: "排序字段: change, volume, funding, from_high, from_low, oi_change，默认volume"},
+			{Name: "order", Description: "asc 或 desc，默认desc"},
+			{Name: "min_volume", Type: "number", Description: "最低24小时成交额"},
+			{Name: "selected", Type: "boolean", Description: "只返回选中币种"},
+			{Name: "page", Type: "integer", Description: "页码，默认1"},
+			{Name: "pageSize", Type: "integer", Description: "每页数量，默认50，最大200"},
+		}, Response: []*models.ScreenerRow{}},
+
+		// Freqtrade
+		{Method: "GET", Path: "/api/v1/freqtrade/bots", Tag: "freqtrade", Summary: "获取Freqtrade实例及连通性", Response: []*freqtrade.BotStatus{}, List: true},
+		{Method: "GET", Path: "/api/v1/freqtrade/health", Tag: "freqtrade", Summary: "获取Freqtrade健康检查和熔断状态", Response: []*core.FreqtradeHealthStatus{}, List: true},
+		{Method: "GET", Path: "/api/v1/killswitch", Tag: "freqtrade", Summary: "获取紧急停止状态", Response: models.KillSwitchState{}},
+		{Method: "POST", Path: "/api/v1/killswitch", Tag: "freqtrade", Summary: "开启紧急停止", Description: "立即停止所有价格预估的执行，cancel_open_entries 为 true 时撤销未成交的限价开仓单", Body: controllers.KillSwitchRequest{}, Response: models.KillSwitchState{}},
+		{Method: "POST", Path: "/api/v1/killswitch/release", Tag: "freqtrade", Summary: "解除紧急停止", Response: models.KillSwitchState{}},
+
+		// 系统状态
+		{Method: "GET", Path: "/api/v1/system/status", Tag: "system", Summary: "获取各子系统健康状态和整体状态", Description: "整体状态为 ok、degraded 或 down，取各子系统中最严重的状态；为 down 时返回503", Response: core.SystemStatus{}},
+		{Method: "GET", Path: "/api/v1/system/logging", Tag: "system", Summary: "获取默认和按模块的日志级别", Response: logging.Levels{}},
+		{Method: "PUT", Path: "/api/v1/system/logging", Tag: "system", Summary: "运行时修改日志级别", Description: "模块为包名或文件名第一个单词，级别为空时删除该模块的覆盖；修改不持久化，重启后恢复为 LOG_LEVEL/LOG_MODULE_LEVELS", Body: controllers.LogLevelsRequest{}, Response: logging.Levels{}},
+		{Method: "GET", Path: "/api/v1/system/connections", Tag: "system", Summary: "获取交易所推送连接延迟和时钟偏差", Description: "只包含当前节点建立的推送连接，高可用模式下逐笔成交和强平推送只在主节点连接", Response: []exchanges.StreamConnectionStats{}, List: true},
+	}
+}
+
+// buildOpenAPIDocument 生成OpenAPI文档，服务地址带上反向代理的路径前缀
+func buildOpenAPIDocument() *openapi.Document {
+	serverURL := ""
+	if config.GlobalConfig != nil && strings.Trim(config.GlobalConfig.BasePath, "/") != "" {
+		serverURL = "/" + strings.Trim(config.GlobalConfig.BasePath, "/")
+	}
+
+	doc := openapi.NewDocument("Trading Assistant API", "价格预估、币种、统计和Freqtrade接口", openAPIVersion, serverURL)
+	doc.AddTag("auth", "认证")
+	doc.AddTag("estimates", "价格预估")
+	doc.AddTag("coins", "币种管理")
+	doc.AddTag("analytics", "执行和盈亏统计")
+	doc.AddTag("freqtrade", "Freqtrade实例和紧急停止")
+	doc.AddTag("system", "系统状态")
+	for _, op := range openAPIOperations() {
+		doc.Add(op)
+	}
+	return doc
+}
+
+// swaggerUIPage 接口文档页面，通过相对路径加载同目录的 openapi.json，兼容路径前缀
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+  <meta charset="utf-8">
+  <title>Trading Assistant API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>`
+
+// registerDocsRoutes 注册接口文档路由，文档不包含敏感信息，不需要认证
+func registerDocsRoutes(r *gin.Engine) {
+	doc := buildOpenAPIDocument()
+
+	r.GET("/api/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, doc)
+	})
+	r.GET("/api/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+	})
+}
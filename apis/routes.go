@@ -4,8 +4,10 @@ import (
 	"path/filepath"
 	"trading_assistant/controllers"
 	"trading_assistant/core"
+	"trading_assistant/pkg/config"
 	"trading_assistant/pkg/exchange_factory"
 	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/metrics"
 	"trading_assistant/pkg/middleware"
 	"trading_assistant/pkg/websocket"
 
@@ -21,6 +23,26 @@ func SetupRoutes(r *gin.Engine, exchangeClient exchange_factory.ExchangeInterfac
 	klineController := controllers.NewKlineController(exchangeClient)
 	positionController := controllers.NewPositionController(freqtradeController)
 	analysisController := controllers.NewAnalysisController()
+	exchangeController := controllers.NewExchangeController(exchangeClient)
+	monitorController := controllers.NewMonitorController()
+	basisController := controllers.NewBasisController()
+	orderBookController := controllers.NewOrderBookController()
+	statsController := controllers.NewStatsController()
+	liquidationController := controllers.NewLiquidationController()
+	openInterestController := controllers.NewOpenInterestController()
+	screenerController := controllers.NewScreenerController()
+	denylistController := controllers.NewDenylistController()
+	telegramController := controllers.NewTelegramController(priceController)
+	webhookController := controllers.NewWebhookController(priceController)
+	spreadController := controllers.NewSpreadController(priceController)
+	templateController := controllers.NewTemplateController(priceController)
+	gridController := controllers.NewGridController(priceController)
+	backtestController := controllers.NewBacktestController(priceController)
+	analyticsController := controllers.NewAnalyticsController()
+	exportController := controllers.NewExportController()
+	paperController := controllers.NewPaperController()
+	freqtradeBotController := controllers.NewFreqtradeController()
+	riskController := controllers.NewRiskController()
 
 	// 初始化WebSocket管理器
 	wsManager := websocket.GetGlobalWebSocketManager()
@@ -42,12 +64,29 @@ func SetupRoutes(r *gin.Engine, exchangeClient exchange_factory.ExchangeInterfac
 		})
 	})
 
+	// Freqtrade RemotePairList 拉取选中交易对
+	r.GET("/pairlist", coinController.GetPairlist)
+
+	// TradingView 告警 Webhook（使用口令校验，不走JWT认证）
+	r.POST("/api/webhook/tradingview", webhookController.TradingView)
+
+	// OpenAPI接口文档（/api/docs 页面和 /api/openapi.json）
+	registerDocsRoutes(r)
+
 	// 添加认证中间件
 	r.Use(middleware.AuthMiddleware())
 
 	// WebSocket路由
 	r.GET("/ws", wsManager.HandleWebSocket)
 
+	// Prometheus 监控指标
+	r.GET("/metrics", metrics.Handler())
+
+	// 运行时诊断接口
+	if config.GlobalConfig.DebugEndpoints {
+		registerDebugRoutes(r)
+	}
+
 	// 认证路由
 	auth := r.Group("/api/v1/auth")
 	{
@@ -69,8 +108,16 @@ func SetupRoutes(r *gin.Engine, exchangeClient exchange_factory.ExchangeInterfac
 			coins.GET("/", coinController.GetCoins)                 // 获取币种列表
 			coins.GET("/selected", coinController.GetSelectedCoins) // 获取选中的币种
 			coins.POST("/select", coinController.SelectCoin)        // 筛选币种
+			coins.POST("/bulk-select", coinController.BulkSelectCoins)        // 批量选中或取消选中币种
+			coins.POST("/select-by-filter", coinController.SelectCoinsByFilter) // 按条件批量选币
+			coins.POST("/whitelist/sync", coinController.SyncWhitelist)         // 立即同步Freqtrade白名单（dry_run=true 只返回差异）
+			coins.GET("/whitelist", coinController.GetWhitelistResult)          // 获取最近一次应用的Freqtrade白名单
 			coins.POST("/sync", coinController.SyncCoins)           // 同步币种
 			coins.PUT("/tier", coinController.UpdateCoinTier)       // 更新币种等级
+			coins.GET("/auto-select/preview", coinController.PreviewAutoSelection)  // 获取自动选币预览
+			coins.POST("/auto-select/confirm", coinController.ConfirmAutoSelection) // 确认自动选币
+			coins.POST("/auto-select/rebalance", coinController.RebalanceAutoSelection) // 按自动选币策略立即调整选中币种（dry_run=true 只返回差异）
+			coins.GET("/auto-select/changes", coinController.GetAutoSelectionChanges)    // 获取自动选币策略的调整记录
 		}
 
 		// 价格预估路由
@@ -78,9 +125,40 @@ func SetupRoutes(r *gin.Engine, exchangeClient exchange_factory.ExchangeInterfac
 		{
 			estimates.GET("/all", priceController.GetAllPriceEstimates)       // 获取所有价格预估（Orders页面需要）
 			estimates.POST("", priceController.CreatePriceEstimate)           // 创建价格预估
+			estimates.POST("/preview", priceController.PreviewPriceEstimate)  // 试算价格预估（不保存）
 			estimates.DELETE("/clear", priceController.ClearNonListeningEstimates) // 清理非监听中的价格预估
 			estimates.DELETE("/:id", priceController.DeletePriceEstimate)     // 删除价格预估
 			estimates.PUT("/:id/toggle", priceController.TogglePriceEstimate) // 切换价格预估监听状态
+			estimates.GET("/:id/events", priceController.GetPriceEstimateEvents) // 获取价格预估的时间线
+			estimates.PATCH("/:id", priceController.UpdatePriceEstimate)      // 编辑价格预估（携带 updated_at 乐观并发检查）
+			estimates.POST("/grid", gridController.CreateGrid)                // 在价格区间内生成网格预估
+			estimates.GET("/grid/:grid_id", gridController.GetGrid)           // 获取网格的所有档位
+			estimates.PUT("/grid/:grid_id/toggle", gridController.ToggleGrid) // 暂停或恢复整个网格
+			estimates.DELETE("/grid/:grid_id", gridController.DeleteGrid)     // 删除整个网格
+		}
+
+		// 交易对杠杆路由
+		symbols := v1.Group("/symbols")
+		{
+			symbols.PUT("/:symbol/leverage", exchangeController.SetSymbolLeverage) // 设置交易对杠杆和保证金模式
+		}
+
+		// 价差监控路由
+		spreads := v1.Group("/spreads")
+		{
+			spreads.GET("", spreadController.GetSpreadMonitors)              // 获取价差监控及实时价差
+			spreads.POST("", spreadController.CreateSpreadMonitor)           // 创建价差监控
+			spreads.DELETE("/:id", spreadController.DeleteSpreadMonitor)     // 删除价差监控
+			spreads.PUT("/:id/toggle", spreadController.ToggleSpreadMonitor) // 切换价差监控监听状态
+		}
+
+		// 价格预估模板路由
+		templates := v1.Group("/templates")
+		{
+			templates.GET("", templateController.GetTemplates)                                // 获取所有预估模板
+			templates.POST("", templateController.SaveTemplate)                               // 保存预估模板（同名覆盖）
+			templates.DELETE("/:name", templateController.DeleteTemplate)                     // 删除预估模板
+			templates.POST("/:name/estimates", templateController.CreateEstimateFromTemplate) // 按模板+币种+价格创建预估
 		}
 
 		// K线分析路由
@@ -101,6 +179,122 @@ func SetupRoutes(r *gin.Engine, exchangeClient exchange_factory.ExchangeInterfac
 		{
 			positions.GET("", positionController.GetPositions)               // 获取所有持仓
 			positions.GET("/summary", positionController.GetPositionSummary) // 获取持仓摘要
+			positions.GET("/reconcile", positionController.GetReconcileReport) // 获取交易对账结果
+			positions.GET("/risk", positionController.GetPositionRisks)        // 获取持仓强平风险
+		}
+
+		// 交易所路由
+		exchanges := v1.Group("/exchanges")
+		{
+			exchanges.GET("/quality", exchangeController.GetDataQuality)          // 获取交易所数据质量评分
+			exchanges.PUT("/price-venue", exchangeController.SetPriceVenue)       // 手动切换价格监控交易所
+			exchanges.GET("/supported", exchangeController.GetSupportedExchanges) // 获取已注册的交易所适配器
+			exchanges.GET("/capabilities", exchangeController.GetCapabilities)    // 获取运行中交易所的能力矩阵
+		}
+
+		// 基差监控路由
+		basis := v1.Group("/basis")
+		{
+			basis.GET("/history", basisController.GetBasisHistory) // 获取基差历史
+			basis.GET("/alerts", basisController.GetBasisAlerts)   // 获取基差告警
+		}
+
+		// Telegram指令路由
+		telegram := v1.Group("/telegram")
+		{
+			telegram.POST("/preview", telegramController.PreviewCommand) // 预览指令将创建的价格预估
+			telegram.GET("/audit", telegramController.GetAudits)         // 获取指令审计记录
+		}
+
+		// 订单簿路由
+		v1.GET("/orderbook", orderBookController.GetOrderBook) // 获取订单簿快照
+
+		// 滚动统计路由
+		v1.GET("/stats/:symbol", statsController.GetSymbolStats) // 获取币种VWAP、波动率和ATR
+
+		// 强平监控路由
+		v1.GET("/liquidations/:symbol", liquidationController.GetLiquidations) // 获取币种按分钟汇总的多空强平额
+
+		// 持仓量路由
+		v1.GET("/open-interest/:symbol", openInterestController.GetOpenInterest) // 获取币种持仓量时间序列和变化率
+
+		// 选币器路由
+		v1.GET("/screener", screenerController.GetScreener) // 按行情指标对所有币种排序
+
+		// 预估回放路由
+		v1.POST("/backtest/estimate", backtestController.BacktestEstimate) // 用历史K线回放价格预估的触发条件
+
+		// 模拟交易路由
+		paper := v1.Group("/paper")
+		{
+			paper.GET("/account", paperController.GetAccount)     // 获取模拟账户汇总
+			paper.GET("/positions", paperController.GetPositions) // 获取模拟持仓
+			paper.GET("/fills", paperController.GetFills)         // 获取模拟成交记录
+			paper.POST("/reset", paperController.ResetAccount)    // 重置模拟账户
+		}
+
+		// 执行统计路由
+		analytics := v1.Group("/analytics")
+		{
+			analytics.GET("/latency", analyticsController.GetExecutionLatency) // 获取触发执行延迟统计
+			analytics.GET("/pnl", analyticsController.GetPnL)                  // 获取已实现盈亏统计
+			analytics.GET("/equity", analyticsController.GetEquity)            // 获取账户权益曲线
+		}
+
+		// 数据导出路由
+		export := v1.Group("/export")
+		{
+			export.GET("/estimates", exportController.ExportEstimates) // 导出价格预估（CSV/JSON）
+			export.GET("/triggers", exportController.ExportTriggers)   // 导出触发记录（CSV/JSON）
+		}
+
+		// 下单前风控限制路由
+		risk := v1.Group("/risk")
+		{
+			risk.GET("/limits", riskController.GetRiskLimits)    // 获取下单前风控限制
+			risk.PUT("/limits", riskController.UpdateRiskLimits) // 更新下单前风控限制
+		}
+
+		// 禁止交易名单路由
+		denylist := v1.Group("/denylist")
+		{
+			denylist.GET("", denylistController.GetDenylist)                         // 获取禁止交易名单
+			denylist.POST("", denylistController.AddDenylistEntry)                   // 添加条目并取消选中命中的币种
+			denylist.DELETE("/:type/:value", denylistController.DeleteDenylistEntry) // 删除条目
+		}
+
+		// 紧急停止路由
+		killSwitch := v1.Group("/killswitch")
+		{
+			killSwitch.GET("", riskController.GetKillSwitch)              // 获取紧急停止状态
+			killSwitch.POST("", riskController.ActivateKillSwitch)        // 开启紧急停止（可选撤销未成交开仓单）
+			killSwitch.POST("/release", riskController.ReleaseKillSwitch) // 解除紧急停止
+		}
+
+		// Freqtrade实例路由
+		freqtradeBots := v1.Group("/freqtrade")
+		{
+			freqtradeBots.GET("/bots", freqtradeBotController.GetBots)     // 获取Freqtrade实例及连通性
+			freqtradeBots.GET("/health", freqtradeBotController.GetHealth) // 获取Freqtrade健康检查和熔断状态
+		}
+
+		// 价格监控路由
+		monitor := v1.Group("/monitor")
+		{
+			monitor.GET("/scheduler", monitorController.GetSchedulerStats) // 获取监控调度统计
+			monitor.GET("/skips", monitorController.GetSkips)              // 获取被跳过的预估评估记录
+			monitor.GET("/stale", monitorController.GetStaleSymbols)       // 获取价格长时间未更新的币种
+			monitor.GET("/eventbus", monitorController.GetEventBusStats)   // 获取事件总线订阅者队列统计
+			monitor.GET("/leader", monitorController.GetLeaderStatus)      // 获取高可用主节点选举状态
+		}
+
+		// 系统状态路由
+		system := v1.Group("/system")
+		{
+			system.GET("/status", monitorController.GetSystemStatus)     // 获取各子系统健康状态和整体状态
+			system.GET("/connections", monitorController.GetConnections) // 获取交易所推送连接延迟和时钟偏差
+			system.GET("/logging", monitorController.GetLogLevels)       // 获取默认和按模块的日志级别
+			system.PUT("/logging", monitorController.UpdateLogLevels)    // 运行时修改日志级别（不持久化）
 		}
 
 		// 系统配置路由
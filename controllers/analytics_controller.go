@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// latencyRecentCount 执行延迟接口返回的最近记录条数
+const latencyRecentCount = 20
+
+// 盈亏统计查询区间
+const (
+	pnlDefaultDays = 30  // 未指定 from 时默认查询最近天数
+	pnlMaxDays     = 366 // 单次查询的最大天数
+)
+
+// AnalyticsController 执行统计控制器
+type AnalyticsController struct{}
+
+// NewAnalyticsController 创建执行统计控制器
+func NewAnalyticsController() *AnalyticsController {
+	return &AnalyticsController{}
+}
+
+// GetExecutionLatency 获取触发执行延迟的分位数统计，可按 symbol、action_type 过滤
+func (a *AnalyticsController) GetExecutionLatency(ctx *gin.Context) {
+	limit, err := strconv.ParseInt(ctx.DefaultQuery("limit", "1000"), 10, 64)
+	if err != nil || limit <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit参数格式错误",
+		})
+		return
+	}
+
+	records, err := redis.GlobalRedisClient.GetExecutionLatencies(limit)
+	if err != nil {
+		logrus.Errorf("获取执行延迟失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取执行延迟失败",
+		})
+		return
+	}
+
+	symbol := strings.ToUpper(ctx.Query("symbol"))
+	actionType := ctx.Query("action_type")
+
+	filtered := make([]*models.ExecutionLatency, 0, len(records))
+	byActionType := make(map[string][]*models.ExecutionLatency)
+	for _, record := range records {
+		if symbol != "" && record.Symbol != symbol {
+			continue
+		}
+		if actionType != "" && record.ActionType != actionType {
+			continue
+		}
+		filtered = append(filtered, record)
+		byActionType[record.ActionType] = append(byActionType[record.ActionType], record)
+	}
+
+	slo := config.GlobalConfig.ExecutionLatencySLO
+	actionStats := make(map[string]*models.LatencyStats, len(byActionType))
+	for action, list := range byActionType {
+		actionStats[action] = core.ComputeLatencyStats(list, slo)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"overall":        core.ComputeLatencyStats(filtered, slo),
+			"by_action_type": actionStats,
+			"recent":         filtered[:min(len(filtered), latencyRecentCount)],
+		},
+		"count": len(filtered),
+	})
+}
+
+// GetPnL 获取已实现盈亏统计，按 group_by（day、week、symbol）聚合，可按 source、symbol、from、to 过滤
+func (a *AnalyticsController) GetPnL(ctx *gin.Context) {
+	source := ctx.DefaultQuery("source", models.PnLSourceFreqtrade)
+	if source != models.PnLSourceFreqtrade && source != models.PnLSourcePaper {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "source参数必须是 freqtrade 或 paper",
+		})
+		return
+	}
+
+	groupBy := ctx.DefaultQuery("group_by", core.PnLGroupByDay)
+	if groupBy != core.PnLGroupByDay && groupBy != core.PnLGroupByWeek && groupBy != core.PnLGroupBySymbol {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "group_by参数必须是 day、week 或 symbol",
+		})
+		return
+	}
+
+	now := time.Now()
+	to, err := time.ParseInLocation(time.DateOnly, ctx.DefaultQuery("to", core.PnLDate(now)), time.Local)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "to参数格式错误，应为 YYYY-MM-DD",
+		})
+		return
+	}
+	from, err := time.ParseInLocation(time.DateOnly, ctx.DefaultQuery("from", core.PnLDate(to.AddDate(0, 0, -(pnlDefaultDays-1)))), time.Local)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "from参数格式错误，应为 YYYY-MM-DD",
+		})
+		return
+	}
+	if from.After(to) || to.Sub(from) > pnlMaxDays*24*time.Hour {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "查询区间无效，from 不能晚于 to 且不超过366天",
+		})
+		return
+	}
+
+	entries, err := core.LoadPnLEntries(source, core.PnLDates(from, to))
+	if err != nil {
+		logrus.Errorf("获取盈亏账本失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取盈亏账本失败",
+		})
+		return
+	}
+
+	if symbol := strings.ToUpper(ctx.Query("symbol")); symbol != "" {
+		filtered := make([]*models.PnLEntry, 0, len(entries))
+		for _, entry := range entries {
+			if entry.Symbol == symbol {
+				filtered = append(filtered, entry)
+			}
+		}
+		entries = filtered
+	}
+
+	// 未平仓持仓的资金费尚未计入账本，单独返回以便查看持仓成本
+	openFunding, openFundingTotal, err := core.OpenPositionFunding()
+	if err != nil {
+		logrus.Warnf("获取持仓资金费失败: %v", err)
+	}
+
+	groups := core.AggregatePnL(entries, groupBy)
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"source":             source,
+			"from":               core.PnLDate(from),
+			"to":                 core.PnLDate(to),
+			"group_by":           groupBy,
+			"total":              core.SummarizePnL(entries),
+			"groups":             groups,
+			"open_funding":       openFunding,
+			"open_funding_total": openFundingTotal,
+		},
+		"count": len(groups),
+	})
+}
+
+// GetEquity 获取权益曲线，可按 source、hours、limit 过滤
+func (a *AnalyticsController) GetEquity(ctx *gin.Context) {
+	source := ctx.Query("source")
+	if source == "" && core.GlobalEquityTracker != nil {
+		source = core.GlobalEquityTracker.Source()
+	}
+	if source != models.PnLSourceFreqtrade && source != models.PnLSourcePaper {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "source参数必须是 freqtrade 或 paper",
+		})
+		return
+	}
+
+	hours, err := strconv.Atoi(ctx.DefaultQuery("hours", "168"))
+	if err != nil || hours <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "hours参数格式错误",
+		})
+		return
+	}
+
+	limit, err := strconv.ParseInt(ctx.DefaultQuery("limit", "2000"), 10, 64)
+	if err != nil || limit <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit参数格式错误",
+		})
+		return
+	}
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour).UnixMilli()
+	snapshots, err := redis.GlobalRedisClient.GetEquitySnapshots(source, since, limit)
+	if err != nil {
+		logrus.Errorf("获取权益快照失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取权益快照失败",
+		})
+		return
+	}
+
+	var latest *models.EquitySnapshot
+	if len(snapshots) > 0 {
+		latest = snapshots[len(snapshots)-1]
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"source":           source,
+			"latest":           latest,
+			"max_drawdown_pct": core.MaxDrawdownPct(snapshots),
+			"snapshots":        snapshots,
+		},
+		"count": len(snapshots),
+	})
+}
@@ -21,6 +21,7 @@ type LoginRequest struct {
 type LoginResponse struct {
 	Token     string `json:"token"`
 	Username  string `json:"username"`
+	Role      string `json:"role"`
 	ExpiresIn int    `json:"expires_in"` // 过期时间（秒）
 }
 
@@ -46,7 +47,8 @@ func (a *AuthController) Login(ctx *gin.Context) {
 	}
 
 	// 验证用户名密码
-	if !auth.ValidateCredentials(req.Username, req.Password) {
+	role, ok := auth.ValidateCredentials(req.Username, req.Password)
+	if !ok {
 		logrus.Warnf("登录失败: 用户名或密码错误 - %s", req.Username)
 		ctx.JSON(http.StatusUnauthorized, gin.H{
 			"error": "用户名或密码错误",
@@ -56,7 +58,7 @@ func (a *AuthController) Login(ctx *gin.Context) {
 	}
 
 	// 生成JWT token
-	token, err := auth.GenerateToken(req.Username)
+	token, err := auth.GenerateToken(req.Username, role)
 	if err != nil {
 		logrus.Errorf("生成token失败: %v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
@@ -66,7 +68,7 @@ func (a *AuthController) Login(ctx *gin.Context) {
 		return
 	}
 
-	logrus.Infof("用户登录成功: %s", req.Username)
+	logrus.Infof("用户登录成功: %s (%s)", req.Username, role)
 
 	// 返回token
 	ctx.JSON(http.StatusOK, gin.H{
@@ -74,7 +76,8 @@ func (a *AuthController) Login(ctx *gin.Context) {
 		"data": LoginResponse{
 			Token:     token,
 			Username:  req.Username,
-			ExpiresIn: 24 * 3600, // 24小时
+			Role:      role,
+			ExpiresIn: int(config.GlobalConfig.AuthTokenTTL.Seconds()),
 		},
 	})
 }
@@ -82,11 +85,12 @@ func (a *AuthController) Login(ctx *gin.Context) {
 // GetProfile 获取用户信息
 func (a *AuthController) GetProfile(ctx *gin.Context) {
 	username := ctx.GetString("username")
+	role := ctx.GetString("role")
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"data": gin.H{
 			"username": username,
-			"role":     "admin",
+			"role":     role,
 		},
 	})
 }
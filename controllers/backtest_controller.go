@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"trading_assistant/core"
+	"trading_assistant/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// BacktestController 预估回放控制器
+type BacktestController struct {
+	priceController *PriceController
+}
+
+// NewBacktestController 创建预估回放控制器
+func NewBacktestController(priceController *PriceController) *BacktestController {
+	return &BacktestController{
+		priceController: priceController,
+	}
+}
+
+// BacktestEstimateRequest 预估回放请求，estimate 与创建预估的请求相同，有效期从 from 开始计算
+type BacktestEstimateRequest struct {
+	Estimate  PriceEstimateRequest `json:"estimate"`
+	Timeframe string               `json:"timeframe"` // K线周期，为空时使用 KLINE_TIMEFRAMES 的第一个
+	From      time.Time            `json:"from" binding:"required"`
+	To        *time.Time           `json:"to"` // 为空时回放到当前时间
+}
+
+// BacktestEstimate 用缓存的历史K线回放预估，返回会在何时触发以及触发后的假设收益，不会保存或下单
+func (b *BacktestController) BacktestEstimate(ctx *gin.Context) {
+	var req BacktestEstimateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	to := time.Now()
+	if req.To != nil {
+		to = *req.To
+	}
+	timeframe := req.Timeframe
+	if timeframe == "" && len(config.GlobalConfig.KlineTimeframes) > 0 {
+		timeframe = config.GlobalConfig.KlineTimeframes[0]
+	}
+
+	// 与创建接口走相同的校验和精度处理，到期时间按相对校验时刻的有效期换算到回放窗口
+	validatedAt := time.Now()
+	if err := b.priceController.validatePriceEstimateRequest(&req.Estimate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := b.priceController.formatPriceEstimatePrecision(&req.Estimate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	estimate := b.priceController.createPriceEstimateModel(&req.Estimate)
+	if estimate.ExpiresAt != nil {
+		expiresAt := req.From.Add(estimate.ExpiresAt.Sub(validatedAt))
+		estimate.ExpiresAt = &expiresAt
+	}
+
+	result, err := core.BacktestEstimate(estimate, timeframe, req.From, to)
+	if err != nil {
+		if errors.Is(err, core.ErrBacktestNoKlines) {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		logrus.Warnf("回放价格预估失败: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"trading_assistant/core"
+	"trading_assistant/pkg/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// BasisController 基差监控控制器
+type BasisController struct{}
+
+// NewBasisController 创建基差监控控制器
+func NewBasisController() *BasisController {
+	return &BasisController{}
+}
+
+// GetBasisHistory 获取币种的基差历史
+func (b *BasisController) GetBasisHistory(ctx *gin.Context) {
+	symbol := ctx.Query("symbol")
+	if symbol == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "symbol参数不能为空",
+		})
+		return
+	}
+
+	exchange := ctx.Query("exchange")
+	if !core.IsExchangeEnabled(exchange) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "交易所未启用: " + exchange,
+		})
+		return
+	}
+
+	hours, err := strconv.Atoi(ctx.DefaultQuery("hours", "24"))
+	if err != nil || hours <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "hours参数格式错误",
+		})
+		return
+	}
+
+	limit, err := strconv.ParseInt(ctx.DefaultQuery("limit", "1000"), 10, 64)
+	if err != nil || limit <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit参数格式错误",
+		})
+		return
+	}
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour).UnixMilli()
+	points, err := core.ExchangeStore(exchange).GetBasisHistory(symbol, since, limit)
+	if err != nil {
+		logrus.Errorf("获取基差历史失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取基差历史失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  points,
+		"count": len(points),
+	})
+}
+
+// GetBasisAlerts 获取最近的基差告警
+func (b *BasisController) GetBasisAlerts(ctx *gin.Context) {
+	limit, err := strconv.ParseInt(ctx.DefaultQuery("limit", "100"), 10, 64)
+	if err != nil || limit <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit参数格式错误",
+		})
+		return
+	}
+
+	alerts, err := redis.GlobalRedisClient.GetBasisAlerts(limit)
+	if err != nil {
+		logrus.Errorf("获取基差告警失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取基差告警失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  alerts,
+		"count": len(alerts),
+	})
+}
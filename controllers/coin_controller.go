@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 	"trading_assistant/core"
 	"trading_assistant/models"
+	"trading_assistant/pkg/config"
 	"trading_assistant/pkg/exchange_factory"
 	"trading_assistant/pkg/redis"
 
@@ -24,12 +26,15 @@ func NewCoinController(exchangeClient exchange_factory.ExchangeInterface, market
 	}
 }
 
+// SelectCoinRequest 选中或取消选中币种请求
+type SelectCoinRequest struct {
+	Symbol     string `json:"symbol" binding:"required"`
+	IsSelected bool   `json:"is_selected"`
+}
+
 // SelectCoin 筛选币种
 func (c *CoinController) SelectCoin(ctx *gin.Context) {
-	var req struct {
-		Symbol     string `json:"symbol" binding:"required"`
-		IsSelected bool   `json:"is_selected"`
-	}
+	var req SelectCoinRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		logrus.Warnf("币种选择参数错误: %v", err)
@@ -48,6 +53,16 @@ func (c *CoinController) SelectCoin(ctx *gin.Context) {
 		return
 	}
 
+	// 禁止交易名单中的币种不能被选中
+	if req.IsSelected {
+		if err := core.CheckSymbolAllowed(req.Symbol); err != nil {
+			ctx.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+
 	// 更新选择状态（使用专门的选择状态管理）
 	var status string
 	if req.IsSelected {
@@ -71,6 +86,7 @@ func (c *CoinController) SelectCoin(ctx *gin.Context) {
 	} else {
 		logrus.Infof("币种 %s 已取消选中", req.Symbol)
 	}
+	core.GlobalWhitelistSyncer.SyncAsync()
 
 	// 获取选择状态用于响应
 	selection, _ := redis.GlobalRedisClient.GetCoinSelection(req.Symbol)
@@ -236,12 +252,15 @@ func (c *CoinController) GetSelectedCoins(ctx *gin.Context) {
 	})
 }
 
+// UpdateCoinTierRequest 更新币种等级请求
+type UpdateCoinTierRequest struct {
+	Symbol string `json:"symbol" binding:"required"`
+	Tier   string `json:"tier"` // S, A, B, C 或空字符串
+}
+
 // UpdateCoinTier 更新币种等级
 func (c *CoinController) UpdateCoinTier(ctx *gin.Context) {
-	var req struct {
-		Symbol string `json:"symbol" binding:"required"`
-		Tier   string `json:"tier"` // S, A, B, C 或空字符串
-	}
+	var req UpdateCoinTierRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		logrus.Warnf("更新币种等级参数错误: %v", err)
@@ -281,4 +300,349 @@ func (c *CoinController) UpdateCoinTier(ctx *gin.Context) {
 	})
 }
 
+// PreviewAutoSelection 获取自动选币预览
+func (c *CoinController) PreviewAutoSelection(ctx *gin.Context) {
+	if c.marketManager == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "市场数据管理器未初始化",
+		})
+		return
+	}
+
+	selector := c.marketManager.GetAutoSelector()
+	preview := selector.GetLastPreview()
+
+	// 没有预览或要求刷新时按当前规则重新生成
+	if preview == nil || ctx.Query("refresh") == "true" {
+		var err error
+		preview, err = selector.Preview()
+		if err != nil {
+			logrus.Errorf("生成自动选币预览失败: %v", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "生成自动选币预览失败",
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  preview,
+		"count": len(preview.Candidates),
+	})
+}
+
+// ConfirmAutoSelection 确认自动选币预览并写入选中列表
+func (c *CoinController) ConfirmAutoSelection(ctx *gin.Context) {
+	if c.marketManager == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "市场数据管理器未初始化",
+		})
+		return
+	}
+
+	preview, err := c.marketManager.GetAutoSelector().Confirm()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	core.GlobalWhitelistSyncer.SyncAsync()
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "自动选币已应用",
+		"data":    preview,
+	})
+}
+
+// RebalanceAutoSelection 按自动选币策略立即调整选中币种，dry_run=true 时只返回差异
+func (c *CoinController) RebalanceAutoSelection(ctx *gin.Context) {
+	if core.GlobalCoinSelectionPolicy == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "自动选币策略未启用",
+		})
+		return
+	}
+
+	change, err := core.GlobalCoinSelectionPolicy.Rebalance(ctx.Query("dry_run") == "true")
+	if err != nil {
+		logrus.Errorf("自动选币调整失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "自动选币调整失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "自动选币调整完成",
+		"data":    change,
+	})
+}
+
+// GetAutoSelectionChanges 获取自动选币策略最近的调整记录
+func (c *CoinController) GetAutoSelectionChanges(ctx *gin.Context) {
+	limit, err := strconv.ParseInt(ctx.DefaultQuery("limit", "50"), 10, 64)
+	if err != nil || limit <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit参数格式错误",
+		})
+		return
+	}
+
+	changes, err := redis.GlobalRedisClient.GetCoinSelectionChanges(limit)
+	if err != nil {
+		logrus.Errorf("获取选币调整记录失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取选币调整记录失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  changes,
+		"count": len(changes),
+	})
+}
+
+// BulkSelectCoinsRequest 批量选中或取消选中币种请求
+type BulkSelectCoinsRequest struct {
+	Symbols    []string `json:"symbols" binding:"required"`
+	IsSelected bool     `json:"is_selected"`
+}
+
+// BulkSelectCoins 批量选中或取消选中币种
+func (c *CoinController) BulkSelectCoins(ctx *gin.Context) {
+	var req BulkSelectCoinsRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logrus.Warnf("批量选择币种参数错误: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	updated, notFound, denied := c.applySelection(req.Symbols, req.IsSelected)
+	if len(updated) > 0 {
+		core.GlobalWhitelistSyncer.SyncAsync()
+	}
+
+	logrus.Infof("批量更新币种选择状态: 成功 %d 个, 未找到 %d 个, 禁止交易 %d 个", len(updated), len(notFound), len(denied))
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "批量更新币种选择状态完成",
+		"data": gin.H{
+			"updated":     updated,
+			"not_found":   notFound,
+			"denied":      denied,
+			"is_selected": req.IsSelected,
+		},
+		"count": len(updated),
+	})
+}
+
+// SelectCoinsByFilterRequest 按条件批量选币请求
+type SelectCoinsByFilterRequest struct {
+	core.CoinFilter
+	Replace bool `json:"replace"` // 是否取消选中未匹配的币种
+	DryRun  bool `json:"dry_run"`
+}
+
+// SelectCoinsByFilter 按条件批量选币，dry_run 时仅返回匹配结果
+func (c *CoinController) SelectCoinsByFilter(ctx *gin.Context) {
+	var req SelectCoinsByFilterRequest
 
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logrus.Warnf("按条件选币参数错误: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	coins, err := redis.GlobalRedisClient.GetAllCoins()
+	if err != nil {
+		logrus.Errorf("获取币种列表失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取币种列表失败",
+		})
+		return
+	}
+
+	denylist, err := core.LoadDenylist()
+	if err != nil {
+		logrus.Errorf("获取禁止交易名单失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取禁止交易名单失败",
+		})
+		return
+	}
+
+	matched := core.FilterCoins(denylist.FilterCoins(coins), req.CoinFilter)
+	symbols := make([]string, 0, len(matched))
+	for _, coin := range matched {
+		symbols = append(symbols, coin.MarketID)
+	}
+
+	// 需要取消选中的币种
+	var deselect []string
+	if req.Replace {
+		selected, err := redis.GlobalRedisClient.GetSelectedCoinMarketIDs()
+		if err != nil {
+			logrus.Errorf("获取选中币种列表失败: %v", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "获取选中币种列表失败",
+			})
+			return
+		}
+		keep := make(map[string]bool, len(symbols))
+		for _, symbol := range symbols {
+			keep[symbol] = true
+		}
+		for _, symbol := range selected {
+			if !keep[symbol] {
+				deselect = append(deselect, symbol)
+			}
+		}
+	}
+
+	if req.DryRun {
+		ctx.JSON(http.StatusOK, gin.H{
+			"message": "筛选预览",
+			"data": gin.H{
+				"selected":   symbols,
+				"deselected": deselect,
+			},
+			"count": len(symbols),
+		})
+		return
+	}
+
+	updated, _, _ := c.applySelection(symbols, true)
+	removed, _, _ := c.applySelection(deselect, false)
+	if len(updated) > 0 || len(removed) > 0 {
+		core.GlobalWhitelistSyncer.SyncAsync()
+	}
+
+	logrus.Infof("按条件选币完成: 选中 %d 个, 取消选中 %d 个", len(updated), len(removed))
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "按条件选币完成",
+		"data": gin.H{
+			"selected":   updated,
+			"deselected": removed,
+		},
+		"count": len(updated),
+	})
+}
+
+// applySelection 批量更新选择状态，返回成功、未找到和因禁止交易未能选中的币种
+func (c *CoinController) applySelection(symbols []string, isSelected bool) (updated []string, notFound []string, denied []string) {
+	status := models.CoinSelectionInactive
+	if isSelected {
+		status = models.CoinSelectionActive
+	}
+
+	updated = make([]string, 0, len(symbols))
+	notFound = make([]string, 0)
+	denied = make([]string, 0)
+
+	// 选中时排除禁止交易名单中的币种，名单读取失败时不选中任何币种
+	denylist := &core.Denylist{}
+	if isSelected {
+		var err error
+		if denylist, err = core.LoadDenylist(); err != nil {
+			logrus.Errorf("获取禁止交易名单失败: %v", err)
+			return updated, notFound, append(denied, symbols...)
+		}
+	}
+
+	for _, symbol := range symbols {
+		coin, err := redis.GlobalRedisClient.GetCoin(symbol)
+		if err != nil {
+			notFound = append(notFound, symbol)
+			continue
+		}
+		if isSelected && denylist.MatchCoin(coin) != nil {
+			denied = append(denied, symbol)
+			continue
+		}
+		if err := redis.GlobalRedisClient.SetCoinSelection(symbol, status); err != nil {
+			logrus.Errorf("更新币种 %s 选择状态失败: %v", symbol, err)
+			continue
+		}
+		updated = append(updated, symbol)
+	}
+	return updated, notFound, denied
+}
+
+// GetPairlist 以 Freqtrade RemotePairList 格式返回白名单目标交易对（默认为选中币种）
+func (c *CoinController) GetPairlist(ctx *gin.Context) {
+	pairs, err := core.WhitelistPairs()
+	if err != nil {
+		logrus.Errorf("获取交易对列表失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取交易对列表失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"pairs":          pairs,
+		"refresh_period": config.GlobalConfig.FreqtradePairlistRefresh,
+	})
+}
+
+// SyncWhitelist 立即同步Freqtrade交易对白名单，dry_run=true 时只返回差异不重新加载
+func (c *CoinController) SyncWhitelist(ctx *gin.Context) {
+	if core.GlobalWhitelistSyncer == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "白名单同步器未初始化",
+		})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "dry_run参数格式错误",
+		})
+		return
+	}
+
+	result, err := core.GlobalWhitelistSyncer.Sync(dryRun)
+	if err != nil {
+		logrus.Errorf("同步Freqtrade白名单失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "同步Freqtrade白名单失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "白名单同步完成",
+		"data":    result,
+	})
+}
+
+// GetWhitelistResult 获取最近一次应用的Freqtrade白名单同步结果
+func (c *CoinController) GetWhitelistResult(ctx *gin.Context) {
+	if core.GlobalWhitelistSyncer == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "白名单同步器未初始化",
+		})
+		return
+	}
+
+	result := core.GlobalWhitelistSyncer.GetLastResult()
+	if result == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": "尚未同步过白名单",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
@@ -19,6 +19,7 @@ func NewConfigController() *ConfigController {
 type SystemConfigResponse struct {
 	ExchangeType string `json:"exchange_type"` // 交易所类型: binance, bybit, okx, mexc
 	MarketType   string `json:"market_type"`   // 市场类型: spot, future
+	DryRun       bool   `json:"dry_run"`       // 是否为模拟交易模式
 }
 
 // GetSystemConfig 获取系统配置
@@ -28,6 +29,7 @@ func (c *ConfigController) GetSystemConfig(ctx *gin.Context) {
 	response := SystemConfigResponse{
 		ExchangeType: cfg.ExchangeType,
 		MarketType:   cfg.MarketType,
+		DryRun:       cfg.DryRun,
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
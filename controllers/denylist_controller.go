@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"net/http"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// DenylistController 禁止交易名单控制器
+type DenylistController struct{}
+
+// NewDenylistController 创建禁止交易名单控制器
+func NewDenylistController() *DenylistController {
+	return &DenylistController{}
+}
+
+// DenylistEntryRequest 添加禁止交易名单条目请求
+type DenylistEntryRequest struct {
+	Type   string `json:"type" binding:"required"`  // symbol, base, pattern
+	Value  string `json:"value" binding:"required"` // MarketID、基础资产或通配符，如 *UPUSDT
+	Reason string `json:"reason"`
+}
+
+// GetDenylist 获取禁止交易名单
+func (d *DenylistController) GetDenylist(ctx *gin.Context) {
+	entries, err := redis.GlobalRedisClient.GetDenylist()
+	if err != nil {
+		logrus.Errorf("获取禁止交易名单失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取禁止交易名单失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  entries,
+		"count": len(entries),
+	})
+}
+
+// AddDenylistEntry 添加禁止交易名单条目，命中的已选中币种会被取消选中
+func (d *DenylistController) AddDenylistEntry(ctx *gin.Context) {
+	var req DenylistEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	entry := &models.DenylistEntry{
+		Type:      req.Type,
+		Value:     req.Value,
+		Reason:    req.Reason,
+		CreatedBy: requestOperator(ctx),
+	}
+	if err := core.NormalizeDenylistEntry(entry); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	deselected, err := core.AddDenylistEntry(entry)
+	if err != nil {
+		logrus.Errorf("添加禁止交易名单失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "添加禁止交易名单失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "禁止交易名单已添加",
+		"data": gin.H{
+			"entry":      entry,
+			"deselected": deselected,
+		},
+	})
+}
+
+// DeleteDenylistEntry 删除禁止交易名单条目，被取消选中的币种需要手动重新选中
+func (d *DenylistController) DeleteDenylistEntry(ctx *gin.Context) {
+	entry := &models.DenylistEntry{
+		Type:  ctx.Param("type"),
+		Value: ctx.Param("value"),
+	}
+	if err := core.NormalizeDenylistEntry(entry); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	deleted, err := redis.GlobalRedisClient.DeleteDenylistEntry(entry.Type, entry.Value)
+	if err != nil {
+		logrus.Errorf("删除禁止交易名单失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "删除禁止交易名单失败",
+		})
+		return
+	}
+	if !deleted {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": "禁止交易名单中没有该条目",
+		})
+		return
+	}
+
+	logrus.Infof("禁止交易名单已删除 %s:%s (%s)", entry.Type, entry.Value, requestOperator(ctx))
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "禁止交易名单已删除",
+	})
+}
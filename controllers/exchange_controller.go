@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"trading_assistant/core"
+	"trading_assistant/pkg/exchange_factory"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExchangeController 交易所控制器
+type ExchangeController struct {
+	exchangeClient exchange_factory.ExchangeInterface
+}
+
+// NewExchangeController 创建交易所控制器
+func NewExchangeController(exchangeClient exchange_factory.ExchangeInterface) *ExchangeController {
+	return &ExchangeController{
+		exchangeClient: exchangeClient,
+	}
+}
+
+// GetDataQuality 获取各交易所的数据质量评分
+func (c *ExchangeController) GetDataQuality(ctx *gin.Context) {
+	scores := core.GetDataQualityTracker().GetScores()
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"primary": c.exchangeClient.GetID(),            // 主交易所
+			"venue":   core.GetPriceFailover().GetStatus(), // 当前读取触发价格的交易所
+			"scores":  scores,
+		},
+	})
+}
+
+// SetPriceVenueRequest 手动切换价格监控交易所请求
+type SetPriceVenueRequest struct {
+	Exchange string `json:"exchange"` // 为空或为主交易所时恢复按数据质量自动选择
+}
+
+// SetPriceVenue 手动切换主交易所预估读取触发价格的交易所
+func (c *ExchangeController) SetPriceVenue(ctx *gin.Context) {
+	var req SetPriceVenueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	failover := core.GetPriceFailover()
+	if err := failover.SetManual(req.Exchange); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "价格监控交易所已更新",
+		"data":    failover.GetStatus(),
+	})
+}
+
+// GetSupportedExchanges 获取已注册的交易所适配器
+func (c *ExchangeController) GetSupportedExchanges(ctx *gin.Context) {
+	descriptors := exchange_factory.NewExchangeFactory().GetRegisteredExchanges()
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  descriptors,
+		"count": len(descriptors),
+	})
+}
+
+// GetCapabilities 获取运行中各交易所的能力矩阵（订单簿、下单、持仓、杠杆）
+func (c *ExchangeController) GetCapabilities(ctx *gin.Context) {
+	capabilities := core.ExchangeCapabilities()
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  capabilities,
+		"count": len(capabilities),
+	})
+}
+
+// SetLeverageRequest 设置杠杆请求
+type SetLeverageRequest struct {
+	Leverage   int    `json:"leverage" binding:"required"` // 杠杆倍数
+	MarginMode string `json:"margin_mode"`                 // CROSS, ISOLATED，为空时不修改
+}
+
+// SetSymbolLeverage 在主交易所上设置交易对的杠杆和保证金模式
+func (c *ExchangeController) SetSymbolLeverage(ctx *gin.Context) {
+	symbol := ctx.Param("symbol")
+
+	var req SetLeverageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+	if req.Leverage <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "杠杆倍数必须大于0",
+		})
+		return
+	}
+	if req.MarginMode != "" && req.MarginMode != types.MarginModeCross && req.MarginMode != types.MarginModeIsolated {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("保证金模式必须是 %s 或 %s", types.MarginModeCross, types.MarginModeIsolated),
+		})
+		return
+	}
+
+	if err := core.ApplyLeverage(symbol, req.Leverage, req.MarginMode); err != nil {
+		ctx.JSON(exchangeErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "杠杆设置成功",
+		"data": gin.H{
+			"symbol":      core.ResolveMarketID("", symbol),
+			"leverage":    req.Leverage,
+			"margin_mode": req.MarginMode,
+		},
+	})
+}
+
+// exchangeErrorStatus 交易所错误对应的HTTP状态码，功能不支持时返回501
+func exchangeErrorStatus(err error, fallback int) int {
+	if exchanges.IsNotSupported(err) {
+		return http.StatusNotImplemented
+	}
+	var authErr *exchanges.AuthenticationError
+	if errors.As(err, &authErr) {
+		return http.StatusUnauthorized
+	}
+	return fallback
+}
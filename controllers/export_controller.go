@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"trading_assistant/core"
+	"trading_assistant/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// 导出查询区间
+const (
+	exportDefaultDays = 30  // 未指定 from 时默认导出最近天数
+	exportMaxDays     = 366 // 单次导出的最大天数
+)
+
+// 导出格式
+const (
+	exportFormatCSV  = "csv"
+	exportFormatJSON = "json"
+)
+
+// estimateCSVHeader 价格预估导出的CSV表头
+var estimateCSVHeader = []string{
+	"id", "symbol", "exchange", "side", "action_type", "trigger_type", "target_price", "percentage",
+	"leverage", "order_type", "stake_amount", "amount", "status", "error_message", "tag",
+	"execution_latency_ms", "created_at", "updated_at",
+}
+
+// triggerCSVHeader 触发记录导出的CSV表头
+var triggerCSVHeader = []string{
+	"estimate_id", "symbol", "exchange", "action_type", "result", "detection_ms", "execution_ms", "total_ms", "triggered_at",
+}
+
+// ExportController 数据导出控制器
+type ExportController struct{}
+
+// NewExportController 创建数据导出控制器
+func NewExportController() *ExportController {
+	return &ExportController{}
+}
+
+// ExportEstimates 导出价格预估，支持 from、to、symbol 过滤，format 为 csv 或 json
+func (e *ExportController) ExportEstimates(ctx *gin.Context) {
+	filter, format, ok := parseExportQuery(ctx)
+	if !ok {
+		return
+	}
+
+	writer := newExportWriter(ctx, "estimates", format, estimateCSVHeader)
+	err := core.ExportEstimates(filter, func(estimate *models.PriceEstimate) error {
+		return writer.write(estimate, []string{
+			estimate.ID,
+			estimate.Symbol,
+			estimate.Exchange,
+			estimate.Side,
+			estimate.ActionType,
+			estimate.TriggerType,
+			csvFloat(estimate.TargetPrice),
+			csvFloat(estimate.Percentage),
+			strconv.Itoa(estimate.Leverage),
+			estimate.OrderType,
+			csvFloat(estimate.StakeAmount),
+			csvFloat(estimate.Amount),
+			estimate.Status,
+			estimate.ErrorMessage,
+			estimate.Tag,
+			strconv.FormatInt(estimate.ExecutionLatencyMs, 10),
+			estimate.CreatedAt.Format(time.RFC3339),
+			estimate.UpdatedAt.Format(time.RFC3339),
+		})
+	})
+	writer.close(err)
+}
+
+// ExportTriggers 导出触发记录，支持 from、to、symbol 过滤，format 为 csv 或 json
+func (e *ExportController) ExportTriggers(ctx *gin.Context) {
+	filter, format, ok := parseExportQuery(ctx)
+	if !ok {
+		return
+	}
+
+	writer := newExportWriter(ctx, "triggers", format, triggerCSVHeader)
+	err := core.ExportTriggers(filter, func(record *models.ExecutionLatency) error {
+		return writer.write(record, []string{
+			record.EstimateID,
+			record.Symbol,
+			record.Exchange,
+			record.ActionType,
+			record.Result,
+			strconv.FormatInt(record.DetectionMs, 10),
+			strconv.FormatInt(record.ExecutionMs, 10),
+			strconv.FormatInt(record.TotalMs, 10),
+			time.UnixMilli(record.Timestamp).Format(time.RFC3339),
+		})
+	})
+	writer.close(err)
+}
+
+// parseExportQuery 解析导出的查询参数，参数错误时直接返回400
+func parseExportQuery(ctx *gin.Context) (core.HistoryFilter, string, bool) {
+	format := strings.ToLower(ctx.DefaultQuery("format", exportFormatCSV))
+	if format != exportFormatCSV && format != exportFormatJSON {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "format参数必须是 csv 或 json",
+		})
+		return core.HistoryFilter{}, "", false
+	}
+
+	now := time.Now()
+	to, err := time.ParseInLocation(time.DateOnly, ctx.DefaultQuery("to", now.Format(time.DateOnly)), time.Local)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "to参数格式错误，应为 YYYY-MM-DD",
+		})
+		return core.HistoryFilter{}, "", false
+	}
+	from, err := time.ParseInLocation(time.DateOnly, ctx.DefaultQuery("from", to.AddDate(0, 0, -(exportDefaultDays-1)).Format(time.DateOnly)), time.Local)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "from参数格式错误，应为 YYYY-MM-DD",
+		})
+		return core.HistoryFilter{}, "", false
+	}
+	if from.After(to) || to.Sub(from) > exportMaxDays*24*time.Hour {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "查询区间无效，from 不能晚于 to 且不超过366天",
+		})
+		return core.HistoryFilter{}, "", false
+	}
+
+	return core.HistoryFilter{
+		From:   from,
+		To:     to.AddDate(0, 0, 1),
+		Symbol: strings.ToUpper(ctx.Query("symbol")),
+	}, format, true
+}
+
+// exportWriter 以CSV或JSON数组的形式逐条写出导出记录
+type exportWriter struct {
+	ctx    *gin.Context
+	format string
+	csv    *csv.Writer
+	count  int
+}
+
+// newExportWriter 写入下载响应头，CSV格式同时写入表头
+func newExportWriter(ctx *gin.Context, name, format string, header []string) *exportWriter {
+	filename := fmt.Sprintf("%s_%s.%s", name, time.Now().Format("20060102150405"), format)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Status(http.StatusOK)
+
+	w := &exportWriter{ctx: ctx, format: format}
+	if format == exportFormatCSV {
+		ctx.Header("Content-Type", "text/csv; charset=utf-8")
+		w.csv = csv.NewWriter(ctx.Writer)
+		_ = w.csv.Write(header)
+	} else {
+		ctx.Header("Content-Type", "application/json; charset=utf-8")
+		_, _ = ctx.Writer.WriteString("[")
+	}
+	return w
+}
+
+// write 写出一条记录，每100条刷新一次响应
+func (w *exportWriter) write(value interface{}, row []string) error {
+	if w.format == exportFormatCSV {
+		if err := w.csv.Write(row); err != nil {
+			return err
+		}
+	} else {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if w.count > 0 {
+			_, _ = w.ctx.Writer.WriteString(",")
+		}
+		if _, err := w.ctx.Writer.Write(data); err != nil {
+			return err
+		}
+	}
+
+	w.count++
+	if w.count%100 == 0 {
+		w.flush()
+	}
+	return nil
+}
+
+// close 结束导出，响应已开始写出，出错时只记录日志
+func (w *exportWriter) close(err error) {
+	if err != nil {
+		logrus.Errorf("导出数据失败，已导出 %d 条: %v", w.count, err)
+	}
+	if w.format == exportFormatJSON {
+		_, _ = w.ctx.Writer.WriteString("]")
+	}
+	w.flush()
+}
+
+// flush 将缓冲的数据写出到客户端
+func (w *exportWriter) flush() {
+	if w.csv != nil {
+		w.csv.Flush()
+	}
+	w.ctx.Writer.Flush()
+}
+
+// csvFloat 格式化CSV中的浮点数
+func csvFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
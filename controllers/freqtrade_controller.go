@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"net/http"
+	"trading_assistant/core"
+	"trading_assistant/pkg/freqtrade"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FreqtradeController Freqtrade实例控制器
+type FreqtradeController struct{}
+
+// NewFreqtradeController 创建Freqtrade实例控制器
+func NewFreqtradeController() *FreqtradeController {
+	return &FreqtradeController{}
+}
+
+// GetBots 获取所有Freqtrade实例及其连通性
+func (f *FreqtradeController) GetBots(ctx *gin.Context) {
+	if freqtrade.GlobalRegistry == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Freqtrade实例未初始化",
+		})
+		return
+	}
+
+	statuses := freqtrade.GlobalRegistry.Status(ctx.Request.Context())
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  statuses,
+		"count": len(statuses),
+	})
+}
+
+// GetHealth 获取Freqtrade健康检查和熔断状态
+func (f *FreqtradeController) GetHealth(ctx *gin.Context) {
+	if core.GlobalFreqtradeHealth == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Freqtrade健康检查未初始化",
+		})
+		return
+	}
+
+	statuses := core.GlobalFreqtradeHealth.GetStatuses()
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  statuses,
+		"count": len(statuses),
+	})
+}
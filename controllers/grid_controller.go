@@ -0,0 +1,336 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// 网格价格间距
+const (
+	GridSpacingArithmetic = "arithmetic" // 等差：相邻档位价差相同
+	GridSpacingGeometric  = "geometric"  // 等比：相邻档位涨跌幅相同
+)
+
+// 网格档位数量限制
+const (
+	minGridLevels = 2
+	maxGridLevels = 50
+)
+
+// GridController 网格预估控制器
+type GridController struct {
+	priceController *PriceController
+}
+
+// NewGridController 创建网格预估控制器
+func NewGridController(priceController *PriceController) *GridController {
+	return &GridController{
+		priceController: priceController,
+	}
+}
+
+// GridEstimateRequest 网格预估请求，在 low_price 与 high_price 之间生成 levels 个条件预估
+// action_type 为 open 时，最先触发的档位（做多为最高价，做空为最低价）开仓，其余档位按 percentage 加仓
+type GridEstimateRequest struct {
+	Symbol        string                `json:"symbol" binding:"required"`
+	Exchange      string                `json:"exchange"`
+	Side          string                `json:"side" binding:"required"`
+	ActionType    string                `json:"action_type" binding:"required"`
+	LowPrice      float64               `json:"low_price" binding:"required"`
+	HighPrice     float64               `json:"high_price" binding:"required"`
+	Levels        int                   `json:"levels" binding:"required"`
+	Spacing       string                `json:"spacing"` // arithmetic, geometric（默认 arithmetic）
+	Percentage    float64               `json:"percentage"`
+	Leverage      int                   `json:"leverage"`
+	OrderType     string                `json:"order_type"`
+	MarginMode    string                `json:"margin_mode"`
+	StakeAmount   float64               `json:"stake_amount"` // 开仓档位的保证金
+	Amount        float64               `json:"amount"`       // 止盈/止损档位每档的数量
+	TTLSeconds    int64                 `json:"ttl_seconds"`
+	Tag           interface{}           `json:"tag"`
+	OrderStrategy *models.OrderStrategy `json:"order_strategy"`
+}
+
+// gridPrices 计算网格档位价格，按价格升序
+func gridPrices(low, high float64, levels int, spacing string) []float64 {
+	prices := make([]float64, levels)
+	for i := 0; i < levels; i++ {
+		ratio := float64(i) / float64(levels-1)
+		if spacing == GridSpacingGeometric {
+			prices[i] = low * math.Pow(high/low, ratio)
+		} else {
+			prices[i] = low + (high-low)*ratio
+		}
+	}
+	return prices
+}
+
+// validateGridRequest 验证网格参数
+func validateGridRequest(req *GridEstimateRequest) error {
+	if req.Levels < minGridLevels || req.Levels > maxGridLevels {
+		return fmt.Errorf("网格档位数量必须在 %d-%d 之间", minGridLevels, maxGridLevels)
+	}
+	if req.LowPrice <= 0 || req.HighPrice <= req.LowPrice {
+		return fmt.Errorf("价格区间无效，必须满足 0 < low_price < high_price")
+	}
+	if req.Spacing == "" {
+		req.Spacing = GridSpacingArithmetic
+	}
+	if req.Spacing != GridSpacingArithmetic && req.Spacing != GridSpacingGeometric {
+		return fmt.Errorf("网格间距必须是 %s 或 %s", GridSpacingArithmetic, GridSpacingGeometric)
+	}
+	if req.ActionType == models.ActionTypeOpen && req.Percentage <= 0 {
+		return fmt.Errorf("开仓网格的后续档位按加仓执行，必须指定 percentage")
+	}
+	return nil
+}
+
+// firstTriggeredIndex 最先触发的档位：做多价格下跌时从高到低触发，做空相反
+func firstTriggeredIndex(side string, levels int) int {
+	if side == types.PositionSideShort {
+		return 0
+	}
+	return levels - 1
+}
+
+// CreateGrid 生成网格预估
+func (g *GridController) CreateGrid(ctx *gin.Context) {
+	var req GridEstimateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	if err := validateGridRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if redis.GlobalRedisClient == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Redis服务不可用",
+		})
+		return
+	}
+
+	if g.priceController.isSpotMode() {
+		req.Side = types.PositionSideLong
+	}
+	prices := gridPrices(req.LowPrice, req.HighPrice, req.Levels, req.Spacing)
+	first := firstTriggeredIndex(req.Side, req.Levels)
+	gridID := uuid.New().String()
+
+	// 先生成并校验全部档位，任一档位无效时不保存
+	estimates := make([]*models.PriceEstimate, 0, req.Levels)
+	for i, price := range prices {
+		level := &PriceEstimateRequest{
+			Symbol:        req.Symbol,
+			Exchange:      req.Exchange,
+			Side:          req.Side,
+			ActionType:    req.ActionType,
+			TargetPrice:   price,
+			Percentage:    req.Percentage,
+			Leverage:      req.Leverage,
+			OrderType:     req.OrderType,
+			MarginMode:    req.MarginMode,
+			TriggerType:   models.TriggerTypeCondition,
+			Tag:           req.Tag,
+			StakeAmount:   req.StakeAmount,
+			Amount:        req.Amount,
+			TTLSeconds:    req.TTLSeconds,
+			OrderStrategy: req.OrderStrategy,
+		}
+		if req.ActionType == models.ActionTypeOpen && i != first {
+			level.ActionType = models.ActionTypeAddition
+			level.StakeAmount = 0
+		}
+
+		if err := g.priceController.validatePriceEstimateRequest(level); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("第 %d 档无效: %v", i+1, err),
+			})
+			return
+		}
+		if err := g.priceController.formatPriceEstimatePrecision(level); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("第 %d 档格式化精度失败: %v", i+1, err),
+			})
+			return
+		}
+
+		estimate := g.priceController.createPriceEstimateModel(level)
+		estimate.GridID = gridID
+		estimates = append(estimates, estimate)
+	}
+
+	for i, estimate := range estimates {
+		if err := redis.GlobalRedisClient.SetPriceEstimate(estimate); err != nil {
+			logrus.Errorf("保存网格预估失败: %v", err)
+			// 已保存的档位一并删除，避免留下不完整的网格
+			for _, saved := range estimates[:i] {
+				_ = redis.GlobalRedisClient.DeletePriceEstimate(saved.ID)
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "保存网格预估失败",
+			})
+			return
+		}
+	}
+
+	symbol := estimates[0].Symbol
+	if !redis.GlobalRedisClient.IsCoinSelected(symbol) {
+		if err := redis.GlobalRedisClient.SetCoinSelection(symbol, models.CoinSelectionActive); err != nil {
+			logrus.Warnf("自动选中币种失败: %s, error: %v", symbol, err)
+		}
+	}
+
+	for _, estimate := range estimates {
+		core.RecordEstimateEvent(estimate.ID, models.EstimateEventCreated, "网格 "+gridID, nil)
+	}
+
+	formatter := core.Formatter(req.Exchange)
+	logrus.Infof("创建网格预估成功: %s %s %s %d档 %s-%s (%s)",
+		symbol, req.Side, req.ActionType, req.Levels, formatter.FormatPrice(symbol, req.LowPrice), formatter.FormatPrice(symbol, req.HighPrice), req.Spacing)
+
+	go core.ApplyEstimateLeverage(estimates[first])
+
+	// 通过WebSocket广播价格预估更新
+	go utils.BroadcastSymbolEstimatesUpdate()
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "网格预估创建成功",
+		"grid_id": gridID,
+		"data":    estimates,
+		"count":   len(estimates),
+	})
+}
+
+// GetGrid 获取网格的所有档位
+func (g *GridController) GetGrid(ctx *gin.Context) {
+	estimates, err := redis.GlobalRedisClient.GetEstimatesByGridID(ctx.Param("grid_id"))
+	if err != nil {
+		logrus.Errorf("获取网格预估失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取网格预估失败",
+		})
+		return
+	}
+	if len(estimates) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": "网格不存在",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  estimates,
+		"count": len(estimates),
+	})
+}
+
+// ToggleGrid 暂停或恢复网格中所有监听中的档位
+func (g *GridController) ToggleGrid(ctx *gin.Context) {
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	gridID := ctx.Param("grid_id")
+	estimates, err := redis.GlobalRedisClient.GetEstimatesByGridID(gridID)
+	if err != nil {
+		logrus.Errorf("获取网格预估失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取网格预估失败",
+		})
+		return
+	}
+	if len(estimates) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": "网格不存在",
+		})
+		return
+	}
+
+	updated := 0
+	for _, estimate := range estimates {
+		if estimate.Status != models.EstimateStatusListening {
+			continue
+		}
+		_, err := redis.GlobalRedisClient.UpdatePriceEstimate(estimate.ID, estimate.UpdatedAt, func(current *models.PriceEstimate) error {
+			current.Enabled = req.Enabled
+			return nil
+		})
+		if err != nil {
+			logrus.Warnf("切换网格档位 %s 失败: %v", estimate.ID, err)
+			continue
+		}
+		core.RecordEstimateToggled(estimate.ID, req.Enabled, "网格 "+gridID)
+		updated++
+	}
+
+	logrus.Infof("网格 %s 已切换 %d 个档位, enabled=%v", gridID, updated, req.Enabled)
+
+	go utils.BroadcastSymbolEstimatesUpdate()
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("已切换 %d 个档位", updated),
+		"count":   updated,
+	})
+}
+
+// DeleteGrid 删除网格的所有档位
+func (g *GridController) DeleteGrid(ctx *gin.Context) {
+	gridID := ctx.Param("grid_id")
+	estimates, err := redis.GlobalRedisClient.GetEstimatesByGridID(gridID)
+	if err != nil {
+		logrus.Errorf("获取网格预估失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取网格预估失败",
+		})
+		return
+	}
+
+	deleted := 0
+	for _, estimate := range estimates {
+		err := redis.GlobalRedisClient.DeletePriceEstimate(estimate.ID)
+		if errors.Is(err, redis.ErrEstimateNotFound) {
+			continue
+		}
+		if err != nil {
+			logrus.Errorf("删除网格档位 %s 失败: %v", estimate.ID, err)
+			continue
+		}
+		deleted++
+	}
+
+	logrus.Infof("网格 %s 已删除 %d 个档位", gridID, deleted)
+
+	if deleted > 0 {
+		go utils.BroadcastSymbolEstimatesUpdate()
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("已删除 %d 个档位", deleted),
+		"count":   deleted,
+	})
+}
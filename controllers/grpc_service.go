@@ -0,0 +1,327 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/grpcapi/assistantpb"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcStreamSeq 价格订阅流序号，用于区分事件总线订阅者
+var grpcStreamSeq atomic.Int64
+
+// AssistantGRPCService gRPC 接口实现，与 REST 接口共用价格预估的校验和保存逻辑
+type AssistantGRPCService struct {
+	assistantpb.UnimplementedAssistantServiceServer
+	priceController *PriceController
+}
+
+// NewAssistantGRPCService 创建 gRPC 接口实现
+func NewAssistantGRPCService(priceController *PriceController) *AssistantGRPCService {
+	return &AssistantGRPCService{priceController: priceController}
+}
+
+// ListEstimates 获取价格预估，可按币种过滤
+func (s *AssistantGRPCService) ListEstimates(ctx context.Context, req *assistantpb.ListEstimatesRequest) (*assistantpb.ListEstimatesResponse, error) {
+	if redis.GlobalRedisClient == nil {
+		return nil, status.Error(codes.Unavailable, "Redis服务不可用")
+	}
+
+	var estimates []*models.PriceEstimate
+	var err error
+	if symbol := strings.ToUpper(strings.TrimSpace(req.GetSymbol())); symbol != "" {
+		estimates, err = redis.GlobalRedisClient.GetAllEstimatesBySymbol(symbol)
+	} else {
+		estimates, err = redis.GlobalRedisClient.GetAllEstimates()
+	}
+	if err != nil {
+		logrus.Errorf("gRPC获取价格预估失败: %v", err)
+		return nil, status.Error(codes.Internal, "获取价格预估失败")
+	}
+
+	resp := &assistantpb.ListEstimatesResponse{Estimates: make([]*assistantpb.Estimate, 0, len(estimates))}
+	for _, estimate := range estimates {
+		resp.Estimates = append(resp.Estimates, estimateToProto(estimate))
+	}
+	return resp, nil
+}
+
+// GetEstimate 获取单个价格预估
+func (s *AssistantGRPCService) GetEstimate(ctx context.Context, req *assistantpb.GetEstimateRequest) (*assistantpb.Estimate, error) {
+	if redis.GlobalRedisClient == nil {
+		return nil, status.Error(codes.Unavailable, "Redis服务不可用")
+	}
+
+	estimate, err := redis.GlobalRedisClient.GetEstimateById(req.GetId())
+	if errors.Is(err, redis.ErrEstimateNotFound) {
+		return nil, status.Error(codes.NotFound, "价格预估不存在")
+	}
+	if err != nil {
+		logrus.Errorf("gRPC获取价格预估失败: %v", err)
+		return nil, status.Error(codes.Internal, "获取价格预估失败")
+	}
+	return estimateToProto(estimate), nil
+}
+
+// CreateEstimate 创建价格预估，校验和精度处理与 POST /api/v1/estimates 一致
+func (s *AssistantGRPCService) CreateEstimate(ctx context.Context, req *assistantpb.CreateEstimateRequest) (*assistantpb.Estimate, error) {
+	if req.GetSymbol() == "" || req.GetSide() == "" || req.GetActionType() == "" {
+		return nil, status.Error(codes.InvalidArgument, "symbol、side 和 action_type 不能为空")
+	}
+
+	estimateReq := &PriceEstimateRequest{
+		Symbol:      req.GetSymbol(),
+		Exchange:    req.GetExchange(),
+		Side:        req.GetSide(),
+		ActionType:  req.GetActionType(),
+		TargetPrice: req.GetTargetPrice(),
+		Percentage:  req.GetPercentage(),
+		Leverage:    int(req.GetLeverage()),
+		OrderType:   req.GetOrderType(),
+		MarginMode:  req.GetMarginMode(),
+		TriggerType: req.GetTriggerType(),
+		PriceSource: req.GetPriceSource(),
+		Condition:   req.GetCondition(),
+		StakeAmount: req.GetStakeAmount(),
+		Amount:      req.GetAmount(),
+		TTLSeconds:  req.GetTtlSeconds(),
+		BotName:     req.GetBotName(),
+	}
+	if req.GetTag() != "" {
+		estimateReq.Tag = req.GetTag()
+	}
+
+	if err := s.priceController.validatePriceEstimateRequest(estimateReq); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.priceController.formatPriceEstimatePrecision(estimateReq); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "格式化精度失败: %v", err)
+	}
+	if redis.GlobalRedisClient == nil {
+		return nil, status.Error(codes.Unavailable, "Redis服务不可用")
+	}
+
+	estimate := s.priceController.createPriceEstimateModel(estimateReq)
+	if err := s.priceController.savePriceEstimate(estimate); err != nil {
+		logrus.Errorf("gRPC保存价格预估失败: %v", err)
+		return nil, status.Error(codes.Internal, "保存价格预估失败")
+	}
+	return estimateToProto(estimate), nil
+}
+
+// UpdateEstimate 编辑价格预估，updated_at 与当前值不一致时返回 Aborted
+func (s *AssistantGRPCService) UpdateEstimate(ctx context.Context, req *assistantpb.UpdateEstimateRequest) (*assistantpb.Estimate, error) {
+	if req.GetUpdatedAt() == nil {
+		return nil, status.Error(codes.InvalidArgument, "必须携带 updated_at")
+	}
+	if redis.GlobalRedisClient == nil {
+		return nil, status.Error(codes.Unavailable, "Redis服务不可用")
+	}
+
+	updatedAt := req.GetUpdatedAt().AsTime()
+	updateReq := &UpdatePriceEstimateRequest{
+		UpdatedAt:   &updatedAt,
+		TargetPrice: req.TargetPrice,
+		Percentage:  req.Percentage,
+		StakeAmount: req.StakeAmount,
+		TTLSeconds:  req.TtlSeconds,
+		PriceSource: req.PriceSource,
+	}
+	if req.Leverage != nil {
+		leverage := int(req.GetLeverage())
+		updateReq.Leverage = &leverage
+	}
+
+	var validationErr error
+	estimate, err := redis.GlobalRedisClient.UpdatePriceEstimate(req.GetId(), updatedAt, func(estimate *models.PriceEstimate) error {
+		validationErr = s.priceController.applyEstimateUpdate(estimate, updateReq)
+		return validationErr
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, redis.ErrEstimateNotFound):
+		return nil, status.Error(codes.NotFound, "价格预估不存在")
+	case errors.Is(err, redis.ErrEstimateConflict):
+		return nil, status.Error(codes.Aborted, err.Error())
+	case err == validationErr:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	default:
+		logrus.Errorf("gRPC编辑价格预估失败: %v", err)
+		return nil, status.Error(codes.Internal, "编辑价格预估失败")
+	}
+
+	if req.Leverage != nil {
+		go core.ApplyEstimateLeverage(estimate)
+	}
+	go utils.BroadcastSymbolEstimatesUpdate()
+	return estimateToProto(estimate), nil
+}
+
+// DeleteEstimate 删除价格预估
+func (s *AssistantGRPCService) DeleteEstimate(ctx context.Context, req *assistantpb.DeleteEstimateRequest) (*assistantpb.DeleteEstimateResponse, error) {
+	if redis.GlobalRedisClient == nil {
+		return nil, status.Error(codes.Unavailable, "Redis服务不可用")
+	}
+
+	err := redis.GlobalRedisClient.DeletePriceEstimate(req.GetId())
+	if errors.Is(err, redis.ErrEstimateNotFound) {
+		return nil, status.Error(codes.NotFound, "价格预估不存在")
+	}
+	if err != nil {
+		logrus.Errorf("gRPC删除价格预估失败: %v", err)
+		return nil, status.Error(codes.Internal, "删除价格预估失败")
+	}
+	logrus.Infof("gRPC删除价格预估成功: %s", req.GetId())
+
+	go utils.BroadcastSymbolEstimatesUpdate()
+	return &assistantpb.DeleteEstimateResponse{}, nil
+}
+
+// GetMarkPrice 获取主交易所的最新标记价格
+func (s *AssistantGRPCService) GetMarkPrice(ctx context.Context, req *assistantpb.GetMarkPriceRequest) (*assistantpb.MarkPrice, error) {
+	if redis.GlobalRedisClient == nil {
+		return nil, status.Error(codes.Unavailable, "Redis服务不可用")
+	}
+
+	markPrice, err := redis.GlobalRedisClient.GetMarkPrice(strings.ToUpper(req.GetSymbol()))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "获取 %s 标记价格失败: %v", req.GetSymbol(), err)
+	}
+	return markPriceToProto("", markPrice), nil
+}
+
+// StreamMarkPrices 订阅标记价格，每轮价格获取完成后推送订阅的币种，客户端处理过慢时积压的价格按币种合并
+func (s *AssistantGRPCService) StreamMarkPrices(req *assistantpb.StreamMarkPricesRequest, stream assistantpb.AssistantService_StreamMarkPricesServer) error {
+	symbols := make(map[string]bool, len(req.GetSymbols()))
+	for _, symbol := range req.GetSymbols() {
+		symbols[strings.ToUpper(symbol)] = true
+	}
+
+	events := make(chan *eventbus.Event, 1)
+	name := fmt.Sprintf("grpc_stream_%d", grpcStreamSeq.Add(1))
+	unsubscribe := eventbus.GetBus().Subscribe(eventbus.TopicMarkPrice, name, config.GlobalConfig.EventBusBuffer, func(event *eventbus.Event) {
+		select {
+		case events <- event:
+		case <-stream.Context().Done():
+		}
+	})
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-events:
+			batch, ok := event.Payload.(*eventbus.MarkPriceBatch)
+			if !ok || (!batch.Primary && !req.GetIncludeSecondary()) {
+				continue
+			}
+			for symbol, markPrice := range batch.Prices {
+				if len(symbols) > 0 && !symbols[symbol] {
+					continue
+				}
+				if err := stream.Send(markPriceToProto(event.Exchange, markPrice)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// ListPositions 获取持仓缓存中的持仓
+func (s *AssistantGRPCService) ListPositions(ctx context.Context, req *assistantpb.ListPositionsRequest) (*assistantpb.ListPositionsResponse, error) {
+	if redis.GlobalRedisClient == nil {
+		return nil, status.Error(codes.Unavailable, "Redis服务不可用")
+	}
+
+	positions, err := redis.GlobalRedisClient.GetAllPositions()
+	if err != nil {
+		logrus.Errorf("gRPC获取持仓失败: %v", err)
+		return nil, status.Error(codes.Internal, "获取持仓失败")
+	}
+
+	resp := &assistantpb.ListPositionsResponse{Positions: make([]*assistantpb.Position, 0, len(positions))}
+	for _, position := range positions {
+		resp.Positions = append(resp.Positions, &assistantpb.Position{
+			Symbol:        position.Symbol,
+			Side:          position.Side,
+			Size:          position.Size,
+			EntryPrice:    position.EntryPrice,
+			MarkPrice:     position.MarkPrice,
+			UnrealizedPnl: position.UnrealizedPnl,
+			Leverage:      int32(position.Leverage),
+			MarginMode:    position.MarginMode,
+			Notional:      position.Notional,
+			UpdatedAt:     timestamppb.New(position.UpdatedAt),
+		})
+	}
+	return resp, nil
+}
+
+// estimateToProto 转换价格预估
+func estimateToProto(estimate *models.PriceEstimate) *assistantpb.Estimate {
+	pb := &assistantpb.Estimate{
+		Id:           estimate.ID,
+		Symbol:       estimate.Symbol,
+		Exchange:     estimate.Exchange,
+		Side:         estimate.Side,
+		ActionType:   estimate.ActionType,
+		TargetPrice:  estimate.TargetPrice,
+		Percentage:   estimate.Percentage,
+		Leverage:     int32(estimate.Leverage),
+		OrderType:    estimate.OrderType,
+		MarginMode:   estimate.MarginMode,
+		Status:       estimate.Status,
+		Enabled:      estimate.Enabled,
+		Tag:          estimate.Tag,
+		StakeAmount:  estimate.StakeAmount,
+		Amount:       estimate.Amount,
+		ErrorMessage: estimate.ErrorMessage,
+		TriggerType:  estimate.TriggerType,
+		PriceSource:  estimate.PriceSource,
+		Condition:    estimate.Condition,
+		GridId:       estimate.GridID,
+		BotName:      estimate.BotName,
+		CreatedAt:    timestamppb.New(estimate.CreatedAt),
+		UpdatedAt:    timestamppb.New(estimate.UpdatedAt),
+	}
+	if estimate.ExpiresAt != nil {
+		pb.ExpiresAt = timestamppb.New(*estimate.ExpiresAt)
+	}
+	return pb
+}
+
+// markPriceToProto 转换标记价格
+func markPriceToProto(exchange string, markPrice *types.WatchMarkPrice) *assistantpb.MarkPrice {
+	timestamp := markPrice.TimeStamp
+	if timestamp == 0 {
+		timestamp = time.Now().UnixMilli()
+	}
+	return &assistantpb.MarkPrice{
+		Symbol:      markPrice.Symbol,
+		Exchange:    exchange,
+		MarkPrice:   markPrice.MarkPrice,
+		IndexPrice:  markPrice.IndexPrice,
+		FundingRate: markPrice.FundingRate,
+		FundingTime: markPrice.FundingTime,
+		BidPrice:    markPrice.BidPrice,
+		AskPrice:    markPrice.AskPrice,
+		LastPrice:   markPrice.LastPrice,
+		Timestamp:   timestamp,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"trading_assistant/pkg/config"
 	"trading_assistant/pkg/exchange_factory"
 	"trading_assistant/pkg/exchanges/types"
 	"trading_assistant/pkg/redis"
@@ -72,6 +73,9 @@ func (k *KlineController) GetKlines(ctx *gin.Context) {
 	}
 
 	interval := ctx.DefaultQuery("interval", "5m")
+	if timeframe := ctx.Query("timeframe"); timeframe != "" {
+		interval = timeframe
+	}
 	limitStr := ctx.DefaultQuery("limit", "1000")
 
 	limit, err := strconv.Atoi(limitStr)
@@ -90,6 +94,41 @@ func (k *KlineController) GetKlines(ctx *gin.Context) {
 		}
 	}
 
+	// 已启用K线历史时优先从本地存储分页返回，before 为上一页返回的 next_before
+	if config.GlobalConfig.KlineStoreEnabled && since == 0 && redis.GlobalRedisClient != nil {
+		var before int64
+		if beforeStr := ctx.Query("before"); beforeStr != "" {
+			if parsed, err := strconv.ParseInt(beforeStr, 10, 64); err == nil {
+				before = parsed
+			}
+		}
+
+		stored, err := redis.GlobalRedisClient.GetStoredKlines(symbol, interval, before, int64(limit))
+		if err != nil {
+			logrus.Warnf("获取K线历史失败: %v", err)
+		} else if len(stored) > 0 {
+			var nextBefore int64
+			if len(stored) == limit {
+				nextBefore = stored[0].Timestamp
+			}
+			ctx.JSON(http.StatusOK, gin.H{
+				"success":     true,
+				"data":        stored,
+				"count":       len(stored),
+				"cached":      true,
+				"source":      "store",
+				"next_before": nextBefore,
+				"params": gin.H{
+					"symbol":   symbol,
+					"interval": interval,
+					"limit":    limit,
+					"before":   before,
+				},
+			})
+			return
+		}
+	}
+
 	// 构建缓存键
 	cacheKey := fmt.Sprintf("%s:%s:%s:%d:%d", redis.CacheKeyKLines, symbol, interval, limit, since)
 
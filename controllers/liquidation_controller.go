@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"trading_assistant/core"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxLiquidationMinutes 强平汇总单次查询的最大分钟数
+const maxLiquidationMinutes = 24 * 60
+
+// LiquidationController 强平监控控制器
+type LiquidationController struct{}
+
+// NewLiquidationController 创建强平监控控制器
+func NewLiquidationController() *LiquidationController {
+	return &LiquidationController{}
+}
+
+// GetLiquidations 获取币种最近一段时间按分钟汇总的多空强平额
+func (l *LiquidationController) GetLiquidations(ctx *gin.Context) {
+	exchange := ctx.Query("exchange")
+	if !core.IsExchangeEnabled(exchange) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "交易所未启用: " + exchange,
+		})
+		return
+	}
+
+	minutes, err := strconv.Atoi(ctx.DefaultQuery("minutes", "60"))
+	if err != nil || minutes <= 0 || minutes > maxLiquidationMinutes {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "minutes参数格式错误，范围 1-1440",
+		})
+		return
+	}
+
+	symbol := core.ResolveMarketID(exchange, ctx.Param("symbol"))
+	summary, err := core.GetLiquidationSummary(exchange, symbol, minutes)
+	if err != nil {
+		logrus.Errorf("获取强平汇总失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取强平汇总失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": summary,
+	})
+}
@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/logging"
+	"trading_assistant/pkg/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// MonitorController 价格监控控制器
+type MonitorController struct{}
+
+// NewMonitorController 创建价格监控控制器
+func NewMonitorController() *MonitorController {
+	return &MonitorController{}
+}
+
+// GetSchedulerStats 获取按币种的监控调度统计
+func (c *MonitorController) GetSchedulerStats(ctx *gin.Context) {
+	if core.GlobalPriceMonitor == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "价格监控未初始化",
+		})
+		return
+	}
+
+	stats := core.GlobalPriceMonitor.GetSchedulerStats()
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  stats,
+		"count": len(stats),
+	})
+}
+
+// GetStaleSymbols 获取价格长时间未更新的币种
+func (c *MonitorController) GetStaleSymbols(ctx *gin.Context) {
+	if core.GlobalPriceMonitor == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "价格监控未初始化",
+		})
+		return
+	}
+
+	stale := core.GlobalPriceMonitor.GetStaleSymbols()
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  stale,
+		"count": len(stale),
+	})
+}
+
+// GetConnections 获取交易所推送连接的ping往返延迟、事件延迟和时钟偏差
+func (c *MonitorController) GetConnections(ctx *gin.Context) {
+	connections := exchanges.GetStreamConnectionStats()
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  connections,
+		"count": len(connections),
+	})
+}
+
+// GetSystemStatus 获取各子系统健康状态，整体状态为 down 时返回503，便于外部可用性监控按状态码判断
+func (c *MonitorController) GetSystemStatus(ctx *gin.Context) {
+	status := core.GetSystemStatus(ctx.Request.Context())
+	code := http.StatusOK
+	if status.Status == core.SystemStatusDown {
+		code = http.StatusServiceUnavailable
+	}
+	ctx.JSON(code, gin.H{
+		"data": status,
+	})
+}
+
+// LogLevelsRequest 修改日志级别请求
+type LogLevelsRequest struct {
+	Level   string            `json:"level"`   // 默认日志级别，为空时不修改
+	Modules map[string]string `json:"modules"` // 模块 -> 日志级别，级别为空时删除该模块的覆盖
+}
+
+// GetLogLevels 获取默认日志级别和按模块覆盖的级别
+func (c *MonitorController) GetLogLevels(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": logging.GetLevels(),
+	})
+}
+
+// UpdateLogLevels 运行时修改日志级别，不持久化，重启后恢复为配置文件中的级别
+func (c *MonitorController) UpdateLogLevels(ctx *gin.Context) {
+	var req LogLevelsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	// 先校验全部级别，避免部分生效
+	levels := make([]string, 0, len(req.Modules)+1)
+	if req.Level != "" {
+		levels = append(levels, req.Level)
+	}
+	for _, level := range req.Modules {
+		if level != "" {
+			levels = append(levels, level)
+		}
+	}
+	for _, level := range levels {
+		if _, err := logrus.ParseLevel(level); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的日志级别: " + level,
+			})
+			return
+		}
+	}
+
+	if req.Level != "" {
+		if err := logging.SetLevel(req.Level); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+	for module, level := range req.Modules {
+		if err := logging.SetModuleLevel(module, level); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+
+	levelsNow := logging.GetLevels()
+	logrus.Warnf("日志级别已修改为 %s，模块覆盖: %v", levelsNow.Level, levelsNow.Modules)
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "日志级别已更新",
+		"data":    levelsNow,
+	})
+}
+
+// GetLeaderStatus 获取高可用模式下的主节点选举状态
+func (c *MonitorController) GetLeaderStatus(ctx *gin.Context) {
+	if core.GlobalLeaderElector == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "主节点选举未初始化",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": core.GlobalLeaderElector.GetStatus(),
+	})
+}
+
+// GetEventBusStats 获取事件总线各订阅者的队列、合并和丢弃统计
+func (c *MonitorController) GetEventBusStats(ctx *gin.Context) {
+	stats := eventbus.GetBus().Stats()
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  stats,
+		"count": len(stats),
+	})
+}
+
+// GetSkips 获取最近被跳过的预估评估记录，可按 symbol、reason 过滤
+func (c *MonitorController) GetSkips(ctx *gin.Context) {
+	limit, err := strconv.ParseInt(ctx.DefaultQuery("limit", "100"), 10, 64)
+	if err != nil || limit <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit参数格式错误",
+		})
+		return
+	}
+
+	symbol := strings.ToUpper(ctx.Query("symbol"))
+	reason := ctx.Query("reason")
+
+	// 有过滤条件时扫描整个记录流再截取
+	fetch := limit
+	if symbol != "" || reason != "" {
+		fetch = max(config.GlobalConfig.MonitorSkipLogMaxLen, limit)
+	}
+
+	skips, err := redis.GlobalRedisClient.GetEvaluationSkips(fetch)
+	if err != nil {
+		logrus.Errorf("获取跳过记录失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取跳过记录失败",
+		})
+		return
+	}
+
+	result := make([]*models.EvaluationSkip, 0, min(int64(len(skips)), limit))
+	for _, skip := range skips {
+		if symbol != "" && skip.Symbol != symbol {
+			continue
+		}
+		if reason != "" && skip.Reason != reason {
+			continue
+		}
+		result = append(result, skip)
+		if int64(len(result)) >= limit {
+			break
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  result,
+		"count": len(result),
+	})
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"trading_assistant/core"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxOpenInterestHours 持仓量时间序列单次查询的最大小时数
+const maxOpenInterestHours = 7 * 24
+
+// OpenInterestController 持仓量控制器
+type OpenInterestController struct{}
+
+// NewOpenInterestController 创建持仓量控制器
+func NewOpenInterestController() *OpenInterestController {
+	return &OpenInterestController{}
+}
+
+// GetOpenInterest 获取币种最近一段时间的持仓量时间序列和变化率
+func (o *OpenInterestController) GetOpenInterest(ctx *gin.Context) {
+	exchange := ctx.Query("exchange")
+	if !core.IsExchangeEnabled(exchange) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "交易所未启用: " + exchange,
+		})
+		return
+	}
+
+	hours, err := strconv.Atoi(ctx.DefaultQuery("hours", "24"))
+	if err != nil || hours <= 0 || hours > maxOpenInterestHours {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "hours参数格式错误，范围 1-168",
+		})
+		return
+	}
+
+	symbol := core.ResolveMarketID(exchange, ctx.Param("symbol"))
+	summary, err := core.GetOpenInterestSummary(exchange, symbol, hours)
+	if err != nil {
+		logrus.Errorf("获取持仓量失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取持仓量失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": summary,
+	})
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"trading_assistant/core"
+	"trading_assistant/pkg/exchange_factory"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrderBookController 订单簿控制器
+type OrderBookController struct{}
+
+// NewOrderBookController 创建订单簿控制器
+func NewOrderBookController() *OrderBookController {
+	return &OrderBookController{}
+}
+
+// GetOrderBook 获取缓存的订单簿快照，可通过depth截取前N档
+func (o *OrderBookController) GetOrderBook(ctx *gin.Context) {
+	symbol := ctx.Query("symbol")
+	if symbol == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "symbol参数不能为空",
+		})
+		return
+	}
+
+	exchange := ctx.Query("exchange")
+	if !core.IsExchangeEnabled(exchange) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "交易所未启用: " + exchange,
+		})
+		return
+	}
+
+	depth := 0
+	if value := ctx.Query("depth"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "depth参数格式错误",
+			})
+			return
+		}
+		depth = parsed
+	}
+
+	// 交易所不支持订单簿时直接告知，而不是提示数据不存在
+	if client, exists := core.ExchangeClient(exchange); exists {
+		if _, err := exchange_factory.AsOrderBookFetcher(client); err != nil {
+			ctx.JSON(exchangeErrorStatus(err, http.StatusBadRequest), gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+
+	book, err := core.ExchangeStore(exchange).GetOrderBook(symbol)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": "订单簿数据不存在，请确认已启用订单簿缓存且币种已选中",
+		})
+		return
+	}
+
+	if depth > 0 {
+		if len(book.Bids.Price) > depth {
+			book.Bids.Price = book.Bids.Price[:depth]
+			book.Bids.Size = book.Bids.Size[:depth]
+		}
+		if len(book.Asks.Price) > depth {
+			book.Asks.Price = book.Asks.Price[:depth]
+			book.Asks.Size = book.Asks.Size[:depth]
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": book,
+	})
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"trading_assistant/core"
+	"trading_assistant/pkg/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// PaperController 模拟交易控制器
+type PaperController struct{}
+
+// NewPaperController 创建模拟交易控制器
+func NewPaperController() *PaperController {
+	return &PaperController{}
+}
+
+// GetAccount 获取模拟账户汇总
+func (p *PaperController) GetAccount(ctx *gin.Context) {
+	summary, err := core.GetPaperEngine().GetSummary()
+	if err != nil {
+		logrus.Errorf("获取模拟账户失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取模拟账户失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": summary,
+	})
+}
+
+// GetPositions 获取模拟持仓
+func (p *PaperController) GetPositions(ctx *gin.Context) {
+	positions, err := core.GetPaperEngine().GetPositions()
+	if err != nil {
+		logrus.Errorf("获取模拟持仓失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取模拟持仓失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  positions,
+		"count": len(positions),
+	})
+}
+
+// GetFills 获取最近的模拟成交
+func (p *PaperController) GetFills(ctx *gin.Context) {
+	limit, err := strconv.ParseInt(ctx.DefaultQuery("limit", "100"), 10, 64)
+	if err != nil || limit <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit参数格式错误",
+		})
+		return
+	}
+
+	fills, err := redis.GlobalRedisClient.GetPaperFills(limit)
+	if err != nil {
+		logrus.Errorf("获取模拟成交失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取模拟成交失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  fills,
+		"count": len(fills),
+	})
+}
+
+// ResetAccount 重置模拟账户、持仓和成交记录
+func (p *PaperController) ResetAccount(ctx *gin.Context) {
+	if err := core.GetPaperEngine().Reset(); err != nil {
+		logrus.Errorf("重置模拟账户失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "重置模拟账户失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "模拟账户已重置",
+	})
+}
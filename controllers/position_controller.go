@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"trading_assistant/core"
 	"trading_assistant/pkg/freqtrade"
 
 	"github.com/gin-gonic/gin"
@@ -30,7 +31,7 @@ func (pc *PositionController) GetPositions(c *gin.Context) {
 	}
 
 	// 从freqtrade获取持仓数据
-	positions, err := pc.freqtradeController.GetPositions()
+	positions, err := pc.freqtradeController.GetPositions(c.Request.Context())
 	if err != nil {
 		logrus.Errorf("获取持仓数据失败: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -48,12 +49,14 @@ func (pc *PositionController) GetPositions(c *gin.Context) {
 		totalPnl += position.CurrentProfitAbs
 		totalStakeAmount += position.StakeAmount
 	}
+	totalFunding := core.AttachPositionFunding(positions)
 
 	response := gin.H{
 		"success": true,
 		"data": gin.H{
 			"positions":      positions,
 			"total_pnl":      totalPnl,
+			"total_funding":  totalFunding, // 本地跟踪的累计资金费，正为收到
 			"position_count": len(positions),
 			"total_stake":    totalStakeAmount,
 			"last_updated":   nil, // freqtrade会提供实时数据
@@ -74,7 +77,7 @@ func (pc *PositionController) GetPositionSummary(c *gin.Context) {
 	}
 
 	// 获取持仓数据
-	positions, err := pc.freqtradeController.GetPositions()
+	positions, err := pc.freqtradeController.GetPositions(c.Request.Context())
 	if err != nil {
 		logrus.Errorf("获取持仓摘要失败: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -104,6 +107,7 @@ func (pc *PositionController) GetPositionSummary(c *gin.Context) {
 		"data": gin.H{
 			"position_count":   len(positions),
 			"total_pnl":        totalPnl,
+			"total_funding":    core.AttachPositionFunding(positions),
 			"total_stake":      totalStakeAmount,
 			"profitable_count": profitableCount,
 			"loss_count":       len(positions) - profitableCount,
@@ -112,3 +116,53 @@ func (pc *PositionController) GetPositionSummary(c *gin.Context) {
 
 	c.JSON(http.StatusOK, summary)
 }
+
+// GetReconcileReport 获取交易对账结果，refresh=true 时立即执行一次对账
+func (pc *PositionController) GetReconcileReport(c *gin.Context) {
+	if pc.freqtradeController == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Freqtrade控制器未初始化"})
+		return
+	}
+
+	reconciler := pc.freqtradeController.GetReconciler()
+	if reconciler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "交易对账未启用"})
+		return
+	}
+
+	report := reconciler.GetLastReport()
+	if c.Query("refresh") == "true" || report == nil {
+		var err error
+		report, err = reconciler.Reconcile(c.Request.Context())
+		if err != nil {
+			logrus.Errorf("交易对账失败: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "交易对账失败: " + err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "获取对账结果成功",
+		"data":    report,
+		"count":   len(report.Discrepancies),
+	})
+}
+
+// GetPositionRisks 获取持仓强平风险，refresh=true 时立即检查一次
+func (pc *PositionController) GetPositionRisks(c *gin.Context) {
+	if core.GlobalRiskMonitor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "持仓风险监控未初始化"})
+		return
+	}
+
+	if c.Query("refresh") == "true" {
+		core.GlobalRiskMonitor.Check()
+	}
+
+	risks := core.GlobalRiskMonitor.GetRisks()
+	c.JSON(http.StatusOK, gin.H{
+		"message": "获取持仓风险成功",
+		"data":    risks,
+		"count":   len(risks),
+	})
+}
@@ -1,14 +1,17 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
-	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+	"trading_assistant/core"
 	"trading_assistant/models"
 	"trading_assistant/pkg/config"
 	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/freqtrade"
 	"trading_assistant/pkg/redis"
 	"trading_assistant/pkg/utils"
 
@@ -19,20 +22,32 @@ import (
 
 type PriceController struct{}
 
+// maxNotifyWithinPct 接近目标价格提醒范围上限 (%)
+const maxNotifyWithinPct = 50.0
+
 // PriceEstimateRequest 价格预估请求结构
 type PriceEstimateRequest struct {
-	Symbol      string      `json:"symbol" binding:"required"`
-	Side        string      `json:"side" binding:"required"`        // long, short
-	ActionType  string      `json:"action_type" binding:"required"` // open, close
-	TargetPrice float64     `json:"target_price"`
-	Percentage  float64     `json:"percentage"`                     // 仓位比例 (加仓时必填)
-	Leverage    int         `json:"leverage"`                       // 杠杆倍数
-	OrderType   string      `json:"order_type"`                     // 订单类型：market, limit
-	MarginMode  string      `json:"margin_mode"`                    // CROSS, ISOLATED (默认CROSS)
-	TriggerType string      `json:"trigger_type"`                   // 触发类型
-	Tag         interface{} `json:"tag"`                            // 交易标签（支持字符串和数字）
-	StakeAmount float64     `json:"stake_amount"`                   // 操作金额 (USDT 保证金)
-	Amount      float64     `json:"amount"`                         // 交易数量 (币的数量)
+	Symbol          string                  `json:"symbol" binding:"required"`
+	Exchange        string                  `json:"exchange"`                       // 价格来源交易所（为空使用主交易所）
+	Side            string                  `json:"side" binding:"required"`        // long, short
+	ActionType      string                  `json:"action_type" binding:"required"` // open, close
+	TargetPrice     float64                 `json:"target_price"`
+	Percentage      float64                 `json:"percentage"`        // 仓位比例 (加仓时必填)
+	Leverage        int                     `json:"leverage"`          // 杠杆倍数
+	OrderType       string                  `json:"order_type"`        // 订单类型：market, limit
+	MarginMode      string                  `json:"margin_mode"`       // CROSS, ISOLATED (默认CROSS)
+	TriggerType     string                  `json:"trigger_type"`      // 触发类型
+	PriceSource     string                  `json:"price_source"`      // 触发价格来源（为空按方向使用买卖价）
+	Condition       string                  `json:"condition"`         // 跨币种触发条件（可选），如 BTCUSDT > 70000 AND ETHUSDT < 3000
+	Tag             interface{}             `json:"tag"`               // 交易标签（支持字符串和数字）
+	StakeAmount     float64                 `json:"stake_amount"`      // 操作金额 (USDT 保证金)
+	Amount          float64                 `json:"amount"`            // 交易数量 (币的数量)
+	ExpiresAt       *time.Time              `json:"expires_at"`        // 到期时间（可选）
+	TTLSeconds      int64                   `json:"ttl_seconds"`       // 有效期秒数（可选，未指定 expires_at 时使用）
+	OrderStrategy   *models.OrderStrategy   `json:"order_strategy"`    // 拆单执行策略（可选）
+	VolatilityGuard *models.VolatilityGuard `json:"volatility_guard"`  // 波动保护（可选）
+	BotName         string                  `json:"bot_name"`          // 下单的Freqtrade实例（可选，为空时按方向路由）
+	NotifyWithinPct float64                 `json:"notify_within_pct"` // 价格距目标价格在该百分比以内时提前提醒（可选）
 }
 
 // isSpotMode 判断是否为现货模式
@@ -42,6 +57,15 @@ func (p *PriceController) isSpotMode() bool {
 
 // validatePriceEstimateRequest 验证价格预估请求
 func (p *PriceController) validatePriceEstimateRequest(req *PriceEstimateRequest) error {
+	// 验证价格来源交易所
+	req.Exchange = strings.ToLower(strings.TrimSpace(req.Exchange))
+	if !core.IsExchangeEnabled(req.Exchange) {
+		return fmt.Errorf("交易所 %s 未启用", req.Exchange)
+	}
+
+	// 统一转换为该交易所的MarketID，支持 BTC/USDT、BTC-USDT-SWAP 等写法
+	req.Symbol = core.ResolveMarketID(req.Exchange, req.Symbol)
+
 	// 现货模式特殊处理
 	if p.isSpotMode() {
 		// 现货模式强制使用 long 方向
@@ -64,6 +88,7 @@ func (p *PriceController) validatePriceEstimateRequest(req *PriceEstimateRequest
 		models.ActionTypeOpen,
 		models.ActionTypeAddition,
 		models.ActionTypeTakeProfit,
+		models.ActionTypeStopLoss,
 	}
 	isValidActionType := false
 	for i := range validActionTypes {
@@ -77,6 +102,13 @@ func (p *PriceController) validatePriceEstimateRequest(req *PriceEstimateRequest
 		return fmt.Errorf("操作类型必须是: %v", validActionTypes)
 	}
 
+	// 禁止交易名单中的币种不能开仓或加仓，止盈止损不受限制以便平掉已有持仓
+	if req.ActionType == models.ActionTypeOpen || req.ActionType == models.ActionTypeAddition {
+		if err := core.CheckSymbolAllowed(req.Symbol); err != nil {
+			return err
+		}
+	}
+
 	// 设置默认值并验证保证金模式
 	if req.MarginMode == "" {
 		req.MarginMode = types.MarginModeCross // 默认全仓
@@ -109,29 +141,167 @@ func (p *PriceController) validatePriceEstimateRequest(req *PriceEstimateRequest
 			return fmt.Errorf("加仓操作必须指定有效的 Percentage (>0)，当前值: %.2f", req.Percentage)
 		}
 	case models.ActionTypeTakeProfit:
-		// 止盈必须指定 Amount
-		if req.Amount <= 0 {
-			return fmt.Errorf("止盈操作必须指定 Amount > 0")
+		// 止盈必须指定 Amount，或按 Percentage 部分平仓
+		if req.Amount <= 0 && (req.Percentage <= 0 || req.Percentage > 100) {
+			return fmt.Errorf("止盈操作必须指定 Amount > 0 或 0 < Percentage <= 100")
+		}
+	case models.ActionTypeStopLoss:
+		// 止损与止盈相同，按 Amount 或 Percentage 平仓
+		if req.Amount <= 0 && (req.Percentage <= 0 || req.Percentage > 100) {
+			return fmt.Errorf("止损操作必须指定 Amount > 0 或 0 < Percentage <= 100")
 		}
 	}
 
-	// 条件触发时必须指定目标价格
-	if req.TriggerType == models.TriggerTypeCondition && req.TargetPrice <= 0 {
+	// 跨币种条件只用于条件触发，保存规范化后的表达式
+	if req.Condition != "" {
+		if req.TriggerType != models.TriggerTypeCondition {
+			return fmt.Errorf("跨币种条件只能用于条件触发")
+		}
+		condition, err := core.ParseCrossCondition(req.Exchange, req.Condition)
+		if err != nil {
+			return err
+		}
+		req.Condition = condition.String()
+	}
+
+	// 条件触发时必须指定目标价格或跨币种条件
+	if req.TriggerType == models.TriggerTypeCondition && req.TargetPrice <= 0 && req.Condition == "" {
 		return fmt.Errorf("条件触发必须指定有效的目标价格 (target_price > 0)")
 	}
 
+	if err := p.validatePriceSource(req.PriceSource); err != nil {
+		return err
+	}
+
+	if err := validateOrderStrategy(req); err != nil {
+		return err
+	}
+
+	if err := validateVolatilityGuard(req); err != nil {
+		return err
+	}
+
+	if err := validateBotName(req.BotName); err != nil {
+		return err
+	}
+
+	if err := validateNotifyWithinPct(req.NotifyWithinPct, req.TriggerType, req.Condition); err != nil {
+		return err
+	}
+
+	return p.resolveExpiration(req)
+}
+
+// validateBotName 验证下单的Freqtrade实例已配置
+func validateBotName(name string) error {
+	if name == "" || freqtrade.GlobalRegistry == nil {
+		return nil
+	}
+	if _, exists := freqtrade.GlobalRegistry.Get(name); !exists {
+		return fmt.Errorf("Freqtrade实例 %s 不存在", name)
+	}
+	return nil
+}
+
+// validateNotifyWithinPct 验证接近目标价格提醒范围，只有按目标价格触发的预估可以设置
+func validateNotifyWithinPct(pct float64, triggerType, condition string) error {
+	if pct < 0 || pct > maxNotifyWithinPct {
+		return fmt.Errorf("notify_within_pct 必须在 0-%g 之间", maxNotifyWithinPct)
+	}
+	if pct > 0 && (triggerType != models.TriggerTypeCondition || condition != "") {
+		return fmt.Errorf("接近目标价格提醒只能用于按目标价格触发的预估")
+	}
+	return nil
+}
+
+// validatePriceSource 验证触发价格来源，现货没有标记价格和指数价格
+func (p *PriceController) validatePriceSource(source string) error {
+	if !models.IsValidPriceSource(source) {
+		return fmt.Errorf("price_source 必须是 mark_price、last_price、index_price、bid 或 ask")
+	}
+	if p.isSpotMode() && (source == models.PriceSourceMark || source == models.PriceSourceIndex) {
+		return fmt.Errorf("现货模式不支持 %s 作为触发价格来源", source)
+	}
+	return nil
+}
+
+// validateOrderStrategy 验证拆单执行策略
+func validateOrderStrategy(req *PriceEstimateRequest) error {
+	strategy := req.OrderStrategy
+	if strategy == nil || strategy.Type == "" {
+		req.OrderStrategy = nil
+		return nil
+	}
+
+	if strategy.Type != models.OrderStrategyTWAP && strategy.Type != models.OrderStrategyIceberg {
+		return fmt.Errorf("拆单策略必须是 %s 或 %s", models.OrderStrategyTWAP, models.OrderStrategyIceberg)
+	}
+	if strategy.Slices < 2 || strategy.Slices > 50 {
+		return fmt.Errorf("拆单笔数必须在 2-50 之间")
+	}
+	if strategy.IntervalSeconds < 1 || strategy.IntervalSeconds > 3600 {
+		return fmt.Errorf("拆单间隔必须在 1-3600 秒之间")
+	}
+	if strategy.MaxChasePct < 0 {
+		return fmt.Errorf("追价上限不能为负数")
+	}
+	if req.ActionType == models.ActionTypeOpen && req.StakeAmount <= 0 {
+		return fmt.Errorf("拆单开仓必须指定 stake_amount")
+	}
+	return nil
+}
+
+// validateVolatilityGuard 验证波动保护，未设置任何阈值时视为不启用
+func validateVolatilityGuard(req *PriceEstimateRequest) error {
+	guard := req.VolatilityGuard
+	if guard == nil {
+		return nil
+	}
+	if guard.MaxVolatilityPct < 0 || guard.MaxSpreadPct < 0 {
+		return fmt.Errorf("波动保护阈值不能为负数")
+	}
+	if guard.DeferSeconds < 0 || guard.DeferSeconds > 3600 {
+		return fmt.Errorf("波动保护推迟秒数必须在 0-3600 之间")
+	}
+	if !guard.IsEnabled() {
+		req.VolatilityGuard = nil
+	}
+	return nil
+}
+
+// resolveExpiration 确定预估的到期时间：expires_at 优先，其次 ttl_seconds，条件预估使用默认有效期
+func (p *PriceController) resolveExpiration(req *PriceEstimateRequest) error {
+	if req.TTLSeconds < 0 {
+		return fmt.Errorf("ttl_seconds 不能为负数")
+	}
+
+	now := time.Now()
+	if req.ExpiresAt == nil {
+		ttl := time.Duration(req.TTLSeconds) * time.Second
+		if ttl == 0 && req.TriggerType == models.TriggerTypeCondition && config.GlobalConfig != nil {
+			ttl = config.GlobalConfig.EstimateDefaultTTL
+		}
+		if ttl > 0 {
+			expiresAt := now.Add(ttl)
+			req.ExpiresAt = &expiresAt
+		}
+		return nil
+	}
+
+	if !req.ExpiresAt.After(now) {
+		return fmt.Errorf("到期时间必须晚于当前时间")
+	}
 	return nil
 }
 
 // formatPriceEstimatePrecision 格式化价格预估的精度
 func (p *PriceController) formatPriceEstimatePrecision(req *PriceEstimateRequest) error {
 	// 获取币种信息 (req.Symbol现在存储的就是MarketID)
-	coin, err := redis.GlobalRedisClient.GetCoin(req.Symbol)
+	coin, err := core.ExchangeStore(req.Exchange).GetCoin(req.Symbol)
 	if err != nil {
 		logrus.Warnf("获取币种信息失败，使用默认精度: %s, error: %v", req.Symbol, err)
 		// 使用默认精度
 		req.Percentage = parseFloat(fmt.Sprintf("%.2f", req.Percentage))
-		req.TargetPrice = parseFloat(fmt.Sprintf("%.4f", req.TargetPrice))
 		return nil
 	}
 
@@ -140,67 +310,22 @@ func (p *PriceController) formatPriceEstimatePrecision(req *PriceEstimateRequest
 		req.Percentage = parseFloat(fmt.Sprintf("%.2f", req.Percentage))
 	}
 
-	// 格式化数量精度
+	// 按步长取整数量并校验数量限制
 	if req.Amount > 0 {
-		quantityPrecision := coin.GetQuantityPrecisionFromStepSize()
-		if quantityPrecision > 0 {
-			quantityFormat := fmt.Sprintf("%%.%df", quantityPrecision)
-			req.Amount = parseFloat(fmt.Sprintf(quantityFormat, req.Amount))
-		}
-		// 验证最小数量
-		if coin.MinQty != "" {
-			minQty := parseFloat(coin.MinQty)
-			if minQty > 0 && req.Amount < minQty {
-				return fmt.Errorf("交易数量 %.6f 小于最小数量 %.6f", req.Amount, minQty)
-			}
-		}
-		// 验证最大数量
-		if coin.MaxQty != "" {
-			maxQty := parseFloat(coin.MaxQty)
-			if maxQty > 0 && req.Amount > maxQty {
-				return fmt.Errorf("交易数量 %.6f 大于最大数量 %.6f", req.Amount, maxQty)
-			}
-		}
-		// 验证数量步长
-		if coin.StepSize != "" {
-			stepSize := parseFloat(coin.StepSize)
-			if stepSize > 0 {
-				// 使用 epsilon 避免浮点数计算误差
-				epsilon := 1e-9
-				steps := req.Amount / stepSize
-				// 检查 steps 是否接近整数
-				if math.Abs(steps-math.Round(steps)) > epsilon {
-					adjustedQty := math.Floor(steps) * stepSize
-					req.Amount = parseFloat(fmt.Sprintf("%.*f", quantityPrecision, adjustedQty))
-				}
-			}
+		req.Amount = core.RoundQuantity(coin, req.Amount)
+		if violations := core.CheckCoinLimits(coin, req.Amount, 0, false); len(violations) > 0 {
+			return errors.New(violations[0])
 		}
 	}
 
-	// 格式化价格精度
-	pricePrecision := coin.GetPricePrecisionFromTickSize()
-	if pricePrecision > 0 {
-		priceFormat := fmt.Sprintf("%%.%df", pricePrecision)
-		req.TargetPrice = parseFloat(fmt.Sprintf(priceFormat, req.TargetPrice))
-
-		// 验证最小价格（立即触发时跳过验证，因为 target_price 可以为 0）
-		if coin.MinPrice != "" && req.TriggerType != models.TriggerTypeImmediate {
-			minPrice := parseFloat(coin.MinPrice)
-			if minPrice > 0 && req.TargetPrice < minPrice {
-				return fmt.Errorf("目标价格 %.6f 小于最小价格 %.6f", req.TargetPrice, minPrice)
-			}
-		}
+	// 按价格步长取整目标价格
+	req.TargetPrice = core.RoundPriceToTick(coin, req.TargetPrice)
 
-		// 验证价格步长
-		if coin.TickSize != "" {
-			tickSize := parseFloat(coin.TickSize)
-			if tickSize > 0 {
-				steps := req.TargetPrice / tickSize
-				if steps != float64(int(steps)) {
-					adjustedPrice := float64(int(steps)) * tickSize
-					req.TargetPrice = parseFloat(fmt.Sprintf(priceFormat, adjustedPrice))
-				}
-			}
+	// 验证最小价格（立即触发时跳过验证，因为 target_price 可以为 0）
+	if coin.MinPrice != "" && req.TriggerType != models.TriggerTypeImmediate {
+		minPrice := parseFloat(coin.MinPrice)
+		if minPrice > 0 && req.TargetPrice < minPrice {
+			return fmt.Errorf("目标价格 %s 小于最小价格 %s", core.FormatCoinPrice(coin, req.TargetPrice), core.FormatCoinPrice(coin, minPrice))
 		}
 	}
 
@@ -232,23 +357,31 @@ func (p *PriceController) createPriceEstimateModel(req *PriceEstimateRequest) *m
 
 	// 初始状态为已启用，自动开始监听
 	return &models.PriceEstimate{
-		ID:          uuid.New().String(),
-		Symbol:      req.Symbol,
-		Side:        req.Side,
-		ActionType:  req.ActionType,
-		TargetPrice: req.TargetPrice,
-		Percentage:  req.Percentage, // 恢复 Percentage 字段
-		Leverage:    req.Leverage,
-		OrderType:   req.OrderType,
-		MarginMode:  req.MarginMode,
-		TriggerType: req.TriggerType,
-		Tag:         tagStr,                         // 交易标签（转换为字符串）
-		StakeAmount: req.StakeAmount,                // 操作金额 (USDT 保证金)
-		Amount:      req.Amount,                     // 交易数量 (币的数量)
-		Status:      models.EstimateStatusListening, // 初始状态为监听状态
-		Enabled:     true,                           // 默认启用，自动开始监听
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:              uuid.New().String(),
+		Symbol:          req.Symbol,
+		Exchange:        req.Exchange,
+		Side:            req.Side,
+		ActionType:      req.ActionType,
+		TargetPrice:     req.TargetPrice,
+		Percentage:      req.Percentage, // 恢复 Percentage 字段
+		Leverage:        req.Leverage,
+		OrderType:       req.OrderType,
+		MarginMode:      req.MarginMode,
+		TriggerType:     req.TriggerType,
+		PriceSource:     req.PriceSource,
+		Condition:       req.Condition,
+		Tag:             tagStr,                         // 交易标签（转换为字符串）
+		StakeAmount:     req.StakeAmount,                // 操作金额 (USDT 保证金)
+		Amount:          req.Amount,                     // 交易数量 (币的数量)
+		ExpiresAt:       req.ExpiresAt,                  // 到期时间
+		OrderStrategy:   req.OrderStrategy,              // 拆单执行策略
+		VolatilityGuard: req.VolatilityGuard,            // 波动保护
+		BotName:         req.BotName,                    // 下单的Freqtrade实例
+		NotifyWithinPct: req.NotifyWithinPct,            // 接近目标价格提醒范围
+		Status:          models.EstimateStatusListening, // 初始状态为监听状态
+		Enabled:         true,                           // 默认启用，自动开始监听
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
 	}
 }
 
@@ -292,7 +425,7 @@ func (p *PriceController) CreatePriceEstimate(ctx *gin.Context) {
 		return
 	}
 
-	if err := redis.GlobalRedisClient.SetPriceEstimate(estimate); err != nil {
+	if err := p.savePriceEstimate(estimate); err != nil {
 		logrus.Errorf("保存价格预估失败: %v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error": "保存价格预估失败",
@@ -300,27 +433,90 @@ func (p *PriceController) CreatePriceEstimate(ctx *gin.Context) {
 		return
 	}
 
-	// 自动选中币种（如果还未选中）
-	if !redis.GlobalRedisClient.IsCoinSelected(req.Symbol) {
-		err := redis.GlobalRedisClient.SetCoinSelection(req.Symbol, models.CoinSelectionActive)
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "价格预估创建成功",
+		"data":    estimate,
+	})
+}
+
+// PreviewPriceEstimate 试算价格预估：返回当前价格、触发距离、取整后的下单数量和所需保证金，不会保存
+func (p *PriceController) PreviewPriceEstimate(ctx *gin.Context) {
+	var req PriceEstimateRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	if err := p.validatePriceEstimateRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// 精度校验失败时仍然返回试算结果，由 violations 说明原因
+	precisionErr := p.formatPriceEstimatePrecision(&req)
+
+	preview, err := core.PreviewEstimate(p.createPriceEstimateModel(&req))
+	if err != nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if precisionErr != nil {
+		preview.Sizing.Violations = append(preview.Sizing.Violations, precisionErr.Error())
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": preview,
+	})
+}
+
+// savePriceEstimate 保存价格预估，自动选中币种并广播更新
+func (p *PriceController) savePriceEstimate(estimate *models.PriceEstimate) error {
+	if err := redis.GlobalRedisClient.SetPriceEstimate(estimate); err != nil {
+		return err
+	}
+	core.RecordEstimateEvent(estimate.ID, models.EstimateEventCreated, "", nil)
+
+	// 自动选中币种（如果还未选中），跨币种条件引用的币种也需要获取价格
+	symbols := []string{estimate.Symbol}
+	if estimate.Condition != "" {
+		if condition, err := core.ParseCrossCondition(estimate.Exchange, estimate.Condition); err == nil {
+			symbols = append(symbols, condition.Symbols()...)
+		}
+	}
+	for _, symbol := range symbols {
+		if redis.GlobalRedisClient.IsCoinSelected(symbol) {
+			continue
+		}
+		if err := core.CheckSymbolAllowed(symbol); err != nil {
+			logrus.Warnf("不自动选中币种 %s: %v", symbol, err)
+			continue
+		}
+		err := redis.GlobalRedisClient.SetCoinSelection(symbol, models.CoinSelectionActive)
 		if err != nil {
-			logrus.Warnf("自动选中币种失败: %s, error: %v", req.Symbol, err)
+			logrus.Warnf("自动选中币种失败: %s, error: %v", symbol, err)
 			// 不影响价格预估的创建，继续执行
 		} else {
-			logrus.Infof("币种 %s 已自动选中", req.Symbol)
+			logrus.Infof("币种 %s 已自动选中", symbol)
 		}
 	}
 
-	logrus.Infof("创建价格预估成功: %s %s %s %.4f",
-		estimate.Symbol, estimate.Side, estimate.ActionType, estimate.TargetPrice)
+	logrus.Infof("创建价格预估成功: %s %s %s %s",
+		estimate.Symbol, estimate.Side, estimate.ActionType, core.Formatter(estimate.Exchange).FormatPrice(estimate.Symbol, estimate.TargetPrice))
+
+	// 提前将开仓杠杆和保证金模式同步到交易所，避免触发时才设置
+	go core.ApplyEstimateLeverage(estimate)
 
 	// 通过WebSocket广播价格预估更新
 	go utils.BroadcastSymbolEstimatesUpdate()
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"message": "价格预估创建成功",
-		"data":    estimate,
-	})
+	return nil
 }
 
 // DeletePriceEstimate 删除价格预估
@@ -336,6 +532,12 @@ func (p *PriceController) DeletePriceEstimate(ctx *gin.Context) {
 
 	// 直接删除预估记录
 	err := redis.GlobalRedisClient.DeletePriceEstimate(id)
+	if errors.Is(err, redis.ErrEstimateNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": "价格预估不存在",
+		})
+		return
+	}
 	if err != nil {
 		logrus.Errorf("删除价格预估失败: %v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
@@ -381,7 +583,7 @@ func (p *PriceController) ClearNonListeningEstimates(ctx *gin.Context) {
 		// 注意: EstimateStatusListening = "listening"
 		if estimate.Status != models.EstimateStatusListening {
 			err := redis.GlobalRedisClient.DeletePriceEstimate(estimate.ID)
-			if err != nil {
+			if err != nil && !errors.Is(err, redis.ErrEstimateNotFound) {
 				logrus.Errorf("清理价格预估失败 ID: %s, Error: %v", estimate.ID, err)
 				errorCount++
 			} else {
@@ -404,18 +606,21 @@ func (p *PriceController) ClearNonListeningEstimates(ctx *gin.Context) {
 	})
 }
 
+// TogglePriceEstimateRequest 切换价格预估监听状态请求
+type TogglePriceEstimateRequest struct {
+	Enabled   bool       `json:"enabled"`
+	UpdatedAt *time.Time `json:"updated_at" binding:"required"` // 客户端读取时的更新时间，用于乐观并发检查
+}
+
 // TogglePriceEstimate 切换价格预估监听状态
 func (p *PriceController) TogglePriceEstimate(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	var req struct {
-		Enabled bool `json:"enabled"`
-	}
-
+	var req TogglePriceEstimateRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		logrus.Warnf("价格预估切换参数错误: %v", err)
 		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "请求参数格式错误",
+			"error": "请求参数格式错误，必须携带 updated_at",
 		})
 		return
 	}
@@ -427,19 +632,31 @@ func (p *PriceController) TogglePriceEstimate(ctx *gin.Context) {
 		return
 	}
 
-	// 获取价格预估
-	estimate, err := redis.GlobalRedisClient.GetEstimateById(id)
-	if err != nil {
+	// 乐观并发写入，避免覆盖客户端读取之后触发、执行或编辑产生的状态
+	var statusErr error
+	estimate, err := redis.GlobalRedisClient.UpdatePriceEstimate(id, *req.UpdatedAt, func(estimate *models.PriceEstimate) error {
+		if estimate.Status != models.EstimateStatusListening {
+			statusErr = fmt.Errorf("只能切换监听中的价格预估，当前状态: %s", estimate.Status)
+			return statusErr
+		}
+		estimate.Enabled = req.Enabled
+		return nil
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, redis.ErrEstimateNotFound):
 		ctx.JSON(http.StatusNotFound, gin.H{
 			"error": "价格预估不存在",
 		})
 		return
-	}
-
-	estimate.Enabled = req.Enabled
-	estimate.UpdatedAt = time.Now()
-
-	if err := redis.GlobalRedisClient.SetPriceEstimate(estimate); err != nil {
+	case errors.Is(err, redis.ErrEstimateConflict), err == statusErr:
+		latest, _ := redis.GlobalRedisClient.GetEstimateById(id)
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+			"data":  latest,
+		})
+		return
+	default:
 		logrus.Errorf("更新价格预估状态失败: %v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error": "更新价格预估状态失败",
@@ -453,6 +670,7 @@ func (p *PriceController) TogglePriceEstimate(ctx *gin.Context) {
 	}
 
 	logrus.Infof("价格预估状态已更新: %s -> %s", id, statusText)
+	core.RecordEstimateToggled(id, req.Enabled, "")
 
 	// 通过WebSocket广播价格预估更新
 	go utils.BroadcastSymbolEstimatesUpdate()
@@ -463,6 +681,230 @@ func (p *PriceController) TogglePriceEstimate(ctx *gin.Context) {
 	})
 }
 
+// GetPriceEstimateEvents 获取价格预估的时间线（按时间顺序）
+func (p *PriceController) GetPriceEstimateEvents(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	limit, err := strconv.ParseInt(ctx.DefaultQuery("limit", "0"), 10, 64)
+	if err != nil || limit < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit参数格式错误",
+		})
+		return
+	}
+
+	if redis.GlobalRedisClient == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Redis服务不可用",
+		})
+		return
+	}
+
+	events, err := redis.GlobalRedisClient.GetEstimateEvents(id, limit)
+	if err != nil {
+		logrus.Errorf("获取预估时间线失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取预估时间线失败",
+		})
+		return
+	}
+	if len(events) == 0 {
+		if _, err := redis.GlobalRedisClient.GetEstimateById(id); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"error": "价格预估不存在",
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  events,
+		"count": len(events),
+	})
+}
+
+// UpdatePriceEstimateRequest 编辑价格预估请求，只修改传入的字段
+type UpdatePriceEstimateRequest struct {
+	UpdatedAt       *time.Time `json:"updated_at" binding:"required"` // 客户端读取时的更新时间，用于乐观并发检查
+	TargetPrice     *float64   `json:"target_price"`
+	Percentage      *float64   `json:"percentage"`
+	Leverage        *int       `json:"leverage"`
+	StakeAmount     *float64   `json:"stake_amount"`
+	ExpiresAt       *time.Time `json:"expires_at"`        // 新的到期时间
+	TTLSeconds      *int64     `json:"ttl_seconds"`       // 从现在起的有效期秒数，0 表示取消到期时间
+	PriceSource     *string    `json:"price_source"`      // 触发价格来源，空字符串表示按方向使用买卖价
+	NotifyWithinPct *float64   `json:"notify_within_pct"` // 接近目标价格提醒范围 (%)，0 表示不提醒
+}
+
+// applyEstimateUpdate 校验并应用编辑内容，价格按交易对精度格式化
+func (p *PriceController) applyEstimateUpdate(estimate *models.PriceEstimate, req *UpdatePriceEstimateRequest) error {
+	if estimate.Status != models.EstimateStatusListening {
+		return fmt.Errorf("只能编辑监听中的价格预估，当前状态: %s", estimate.Status)
+	}
+
+	if req.TargetPrice != nil {
+		if *req.TargetPrice < 0 || (*req.TargetPrice == 0 && estimate.TriggerType == models.TriggerTypeCondition) {
+			return fmt.Errorf("条件触发必须指定有效的目标价格 (target_price > 0)")
+		}
+		estimate.TargetPrice = *req.TargetPrice
+	}
+
+	if req.Percentage != nil {
+		percentage := *req.Percentage
+		switch estimate.ActionType {
+		case models.ActionTypeAddition:
+			if percentage <= 0 {
+				return fmt.Errorf("加仓操作必须指定有效的 Percentage (>0)")
+			}
+		case models.ActionTypeTakeProfit, models.ActionTypeStopLoss:
+			if percentage < 0 || percentage > 100 || (percentage == 0 && estimate.Amount <= 0) {
+				return fmt.Errorf("平仓比例必须满足 0 < Percentage <= 100")
+			}
+		default:
+			if percentage < 0 {
+				return fmt.Errorf("percentage 不能为负数")
+			}
+		}
+		estimate.Percentage = percentage
+	}
+
+	if req.Leverage != nil {
+		if *req.Leverage < 1 {
+			return fmt.Errorf("杠杆倍数必须大于0")
+		}
+		if p.isSpotMode() && *req.Leverage != 1 {
+			return fmt.Errorf("现货模式杠杆固定为1")
+		}
+		estimate.Leverage = *req.Leverage
+	}
+
+	if req.StakeAmount != nil {
+		if *req.StakeAmount < 0 {
+			return fmt.Errorf("stake_amount 不能为负数")
+		}
+		if *req.StakeAmount == 0 && estimate.OrderStrategy != nil && estimate.ActionType == models.ActionTypeOpen {
+			return fmt.Errorf("拆单开仓必须指定 stake_amount")
+		}
+		estimate.StakeAmount = *req.StakeAmount
+	}
+
+	if req.PriceSource != nil {
+		if err := p.validatePriceSource(*req.PriceSource); err != nil {
+			return err
+		}
+		estimate.PriceSource = *req.PriceSource
+	}
+
+	if req.NotifyWithinPct != nil {
+		if err := validateNotifyWithinPct(*req.NotifyWithinPct, estimate.TriggerType, estimate.Condition); err != nil {
+			return err
+		}
+		estimate.NotifyWithinPct = *req.NotifyWithinPct
+	}
+
+	switch {
+	case req.ExpiresAt != nil:
+		if !req.ExpiresAt.After(time.Now()) {
+			return fmt.Errorf("到期时间必须晚于当前时间")
+		}
+		estimate.ExpiresAt = req.ExpiresAt
+	case req.TTLSeconds != nil:
+		if *req.TTLSeconds < 0 {
+			return fmt.Errorf("ttl_seconds 不能为负数")
+		}
+		estimate.ExpiresAt = nil
+		if *req.TTLSeconds > 0 {
+			expiresAt := time.Now().Add(time.Duration(*req.TTLSeconds) * time.Second)
+			estimate.ExpiresAt = &expiresAt
+		}
+	}
+
+	// 复用创建时的精度处理
+	precision := PriceEstimateRequest{
+		Symbol:      estimate.Symbol,
+		Exchange:    estimate.Exchange,
+		TargetPrice: estimate.TargetPrice,
+		Percentage:  estimate.Percentage,
+		Amount:      estimate.Amount,
+		TriggerType: estimate.TriggerType,
+	}
+	if err := p.formatPriceEstimatePrecision(&precision); err != nil {
+		return err
+	}
+	estimate.TargetPrice = precision.TargetPrice
+	estimate.Percentage = precision.Percentage
+	return nil
+}
+
+// UpdatePriceEstimate 编辑监听中的价格预估（目标价、比例、杠杆、金额、到期时间）
+func (p *PriceController) UpdatePriceEstimate(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var req UpdatePriceEstimateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logrus.Warnf("价格预估编辑参数错误: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误，必须携带 updated_at",
+		})
+		return
+	}
+
+	if redis.GlobalRedisClient == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Redis服务不可用",
+		})
+		return
+	}
+
+	var validationErr error
+	estimate, err := redis.GlobalRedisClient.UpdatePriceEstimate(id, *req.UpdatedAt, func(estimate *models.PriceEstimate) error {
+		validationErr = p.applyEstimateUpdate(estimate, &req)
+		return validationErr
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, redis.ErrEstimateNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": "价格预估不存在",
+		})
+		return
+	case errors.Is(err, redis.ErrEstimateConflict):
+		current, _ := redis.GlobalRedisClient.GetEstimateById(id)
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+			"data":  current,
+		})
+		return
+	case err == validationErr:
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	default:
+		logrus.Errorf("编辑价格预估失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "编辑价格预估失败",
+		})
+		return
+	}
+
+	logrus.Infof("编辑价格预估成功: %s %s %s %s",
+		estimate.Symbol, estimate.Side, estimate.ActionType, core.Formatter(estimate.Exchange).FormatPrice(estimate.Symbol, estimate.TargetPrice))
+	core.RecordEstimateEvent(estimate.ID, models.EstimateEventUpdated, "", nil)
+
+	if req.Leverage != nil {
+		go core.ApplyEstimateLeverage(estimate)
+	}
+
+	// 通过WebSocket广播价格预估更新
+	go utils.BroadcastSymbolEstimatesUpdate()
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "价格预估编辑成功",
+		"data":    estimate,
+	})
+}
+
 // GetAllPriceEstimates 获取所有价格预估
 func (p *PriceController) GetAllPriceEstimates(ctx *gin.Context) {
 	symbol := ctx.Query("symbol")
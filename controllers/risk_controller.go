@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RiskController 下单前风控限制控制器
+type RiskController struct{}
+
+// NewRiskController 创建下单前风控限制控制器
+func NewRiskController() *RiskController {
+	return &RiskController{}
+}
+
+// validateRiskLimit 验证一组风控限制
+func validateRiskLimit(scope string, limit models.RiskLimit) error {
+	if limit.MaxOpenNotional < 0 || limit.MaxPositions < 0 || limit.MaxLeverage < 0 || limit.MaxStakePerTrade < 0 {
+		return fmt.Errorf("%s的风控限制不能为负数", scope)
+	}
+	return nil
+}
+
+// GetRiskLimits 获取下单前风控限制
+func (r *RiskController) GetRiskLimits(ctx *gin.Context) {
+	limits, err := redis.GlobalRedisClient.GetRiskLimits()
+	if err != nil {
+		logrus.Errorf("获取风控限制失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取风控限制失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": limits,
+	})
+}
+
+// UpdateRiskLimits 整体替换下单前风控限制，0 表示不限制，币种按 MarketID 配置
+func (r *RiskController) UpdateRiskLimits(ctx *gin.Context) {
+	var req models.RiskLimits
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	if err := validateRiskLimit("全局", req.Global); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	symbols := make(map[string]models.RiskLimit, len(req.Symbols))
+	for symbol, limit := range req.Symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "币种不能为空",
+			})
+			return
+		}
+		if err := validateRiskLimit(symbol, limit); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		symbols[symbol] = limit
+	}
+	req.Symbols = symbols
+	req.UpdatedAt = time.Now()
+
+	if err := redis.GlobalRedisClient.SetRiskLimits(&req); err != nil {
+		logrus.Errorf("保存风控限制失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "保存风控限制失败",
+		})
+		return
+	}
+
+	logrus.Infof("下单前风控限制已更新: 全局 %+v, 币种 %d 个", req.Global, len(req.Symbols))
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "风控限制已更新",
+		"data":    &req,
+	})
+}
+
+// KillSwitchRequest 开启紧急停止请求
+type KillSwitchRequest struct {
+	Reason            string `json:"reason"`
+	CancelOpenEntries bool   `json:"cancel_open_entries"` // 是否撤销Freqtrade未成交的限价开仓单
+}
+
+// requestOperator 请求的操作人，未登录时记为 api
+func requestOperator(ctx *gin.Context) string {
+	if username := ctx.GetString("username"); username != "" {
+		return username
+	}
+	return "api"
+}
+
+// GetKillSwitch 获取紧急停止状态
+func (r *RiskController) GetKillSwitch(ctx *gin.Context) {
+	state, err := redis.GlobalRedisClient.GetKillSwitch()
+	if err != nil {
+		logrus.Errorf("获取紧急停止状态失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取紧急停止状态失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": state,
+	})
+}
+
+// ActivateKillSwitch 开启紧急停止，立即停止所有价格预估的执行，需要手动解除
+func (r *RiskController) ActivateKillSwitch(ctx *gin.Context) {
+	var req KillSwitchRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "请求参数格式错误",
+			})
+			return
+		}
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		reason = "手动紧急停止"
+	}
+
+	state, err := core.ActivateKillSwitch(ctx.Request.Context(), reason, requestOperator(ctx), req.CancelOpenEntries)
+	if err != nil {
+		logrus.Errorf("开启紧急停止失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "开启紧急停止失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "紧急停止已开启",
+		"data":    state,
+	})
+}
+
+// ReleaseKillSwitch 解除紧急停止
+func (r *RiskController) ReleaseKillSwitch(ctx *gin.Context) {
+	state, err := core.ReleaseKillSwitch(requestOperator(ctx))
+	if err != nil {
+		logrus.Errorf("解除紧急停止失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "解除紧急停止失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "紧急停止已解除",
+		"data":    state,
+	})
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"trading_assistant/core"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxScreenerPageSize 选币器每页最多返回的币种数
+const maxScreenerPageSize = 200
+
+// ScreenerController 选币器控制器
+type ScreenerController struct{}
+
+// NewScreenerController 创建选币器控制器
+func NewScreenerController() *ScreenerController {
+	return &ScreenerController{}
+}
+
+// GetScreener 按涨跌幅、成交额、资金费率、距高低点距离或持仓量变化对币种排序并分页返回
+func (s *ScreenerController) GetScreener(ctx *gin.Context) {
+	exchange := ctx.Query("exchange")
+	if !core.IsExchangeEnabled(exchange) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "交易所未启用: " + exchange,
+		})
+		return
+	}
+
+	query := core.ScreenerQuery{
+		Exchange:     exchange,
+		SortBy:       ctx.DefaultQuery("sort", "volume"),
+		SelectedOnly: ctx.Query("selected") == "true",
+	}
+	if !core.IsScreenerSortField(query.SortBy) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "sort参数错误，可选值: change, volume, funding, from_high, from_low, oi_change",
+		})
+		return
+	}
+	switch ctx.DefaultQuery("order", "desc") {
+	case "asc":
+		query.Ascending = true
+	case "desc":
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "order参数错误，可选值: asc, desc",
+		})
+		return
+	}
+	if minVolume := ctx.Query("min_volume"); minVolume != "" {
+		value, err := strconv.ParseFloat(minVolume, 64)
+		if err != nil || value < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "min_volume参数格式错误",
+			})
+			return
+		}
+		query.MinQuoteVolume = value
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 50
+	}
+	if pageSize > maxScreenerPageSize {
+		pageSize = maxScreenerPageSize
+	}
+
+	rows, err := core.RunScreener(query)
+	if err != nil {
+		logrus.Errorf("选币器计算失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "选币器计算失败",
+		})
+		return
+	}
+
+	total := len(rows)
+	start := min((page-1)*pageSize, total)
+	end := min(start+pageSize, total)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       rows[start:end],
+		"total":      total,
+		"page":       page,
+		"pageSize":   pageSize,
+		"totalPages": int(math.Ceil(float64(total) / float64(pageSize))),
+	})
+}
@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// SpreadController 价差监控控制器
+type SpreadController struct {
+	priceController *PriceController
+}
+
+// NewSpreadController 创建价差监控控制器
+func NewSpreadController(priceController *PriceController) *SpreadController {
+	return &SpreadController{
+		priceController: priceController,
+	}
+}
+
+// SpreadMonitorRequest 创建价差监控请求
+type SpreadMonitorRequest struct {
+	Name        string           `json:"name"`
+	Exchange    string           `json:"exchange"`
+	LegA        models.SpreadLeg `json:"leg_a" binding:"required"`
+	LegB        models.SpreadLeg `json:"leg_b" binding:"required"`
+	Mode        string           `json:"mode"`        // ratio, difference（默认 ratio）
+	HedgeRatio  float64          `json:"hedge_ratio"` // difference 模式下的系数 k（默认 1）
+	Condition   string           `json:"condition" binding:"required"`
+	TargetValue float64          `json:"target_value"`
+	Tag         string           `json:"tag"`
+}
+
+// GetSpreadMonitors 获取所有价差监控及实时价差
+func (s *SpreadController) GetSpreadMonitors(ctx *gin.Context) {
+	monitors, err := redis.GlobalRedisClient.GetAllSpreadMonitors()
+	if err != nil {
+		logrus.Errorf("获取价差监控失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取价差监控失败",
+		})
+		return
+	}
+
+	result := make([]gin.H, 0, len(monitors))
+	for _, monitor := range monitors {
+		item := gin.H{"monitor": monitor}
+		if snapshot, err := core.GetSpreadSnapshot(monitor); err == nil {
+			item["current"] = snapshot
+		}
+		result = append(result, item)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "获取价差监控成功",
+		"data":    result,
+		"count":   len(result),
+	})
+}
+
+// CreateSpreadMonitor 创建价差监控
+func (s *SpreadController) CreateSpreadMonitor(ctx *gin.Context) {
+	var req SpreadMonitorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	if err := s.validateSpreadRequest(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	monitor := &models.SpreadMonitor{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Exchange:    req.Exchange,
+		LegA:        req.LegA,
+		LegB:        req.LegB,
+		Mode:        req.Mode,
+		HedgeRatio:  req.HedgeRatio,
+		Condition:   req.Condition,
+		TargetValue: req.TargetValue,
+		Tag:         req.Tag,
+		Status:      models.EstimateStatusListening,
+		Enabled:     true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if monitor.Name == "" {
+		monitor.Name = monitor.LegA.Symbol + "/" + monitor.LegB.Symbol
+	}
+
+	if err := redis.GlobalRedisClient.SetSpreadMonitor(monitor); err != nil {
+		logrus.Errorf("保存价差监控失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "保存价差监控失败",
+		})
+		return
+	}
+
+	// 两腿都需要价格数据，自动选中币种
+	for _, symbol := range []string{monitor.LegA.Symbol, monitor.LegB.Symbol} {
+		if !redis.GlobalRedisClient.IsCoinSelected(symbol) {
+			if err := redis.GlobalRedisClient.SetCoinSelection(symbol, models.CoinSelectionActive); err != nil {
+				logrus.Warnf("自动选中币种失败: %s, error: %v", symbol, err)
+			}
+		}
+	}
+
+	logrus.Infof("创建价差监控成功: %s %s %s %f", monitor.Name, monitor.Mode, monitor.Condition, monitor.TargetValue)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "价差监控创建成功",
+		"data":    monitor,
+	})
+}
+
+// DeleteSpreadMonitor 删除价差监控
+func (s *SpreadController) DeleteSpreadMonitor(ctx *gin.Context) {
+	if err := redis.GlobalRedisClient.DeleteSpreadMonitor(ctx.Param("id")); err != nil {
+		logrus.Errorf("删除价差监控失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "删除价差监控失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "价差监控删除成功",
+	})
+}
+
+// ToggleSpreadMonitor 切换价差监控监听状态，已触发或失败的监控重新开始监听
+func (s *SpreadController) ToggleSpreadMonitor(ctx *gin.Context) {
+	monitor, err := redis.GlobalRedisClient.GetSpreadMonitor(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": "价差监控不存在",
+		})
+		return
+	}
+
+	if monitor.Status != models.EstimateStatusListening {
+		monitor.Status = models.EstimateStatusListening
+		monitor.Enabled = true
+		monitor.ErrorMessage = ""
+	} else {
+		monitor.Enabled = !monitor.Enabled
+	}
+	monitor.UpdatedAt = time.Now()
+
+	if err := redis.GlobalRedisClient.SetSpreadMonitor(monitor); err != nil {
+		logrus.Errorf("更新价差监控失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "更新价差监控失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "价差监控状态已更新",
+		"data":    monitor,
+	})
+}
+
+// validateSpreadRequest 验证价差监控请求，两腿按立即执行的价格预估校验
+func (s *SpreadController) validateSpreadRequest(req *SpreadMonitorRequest) error {
+	req.Exchange = strings.ToLower(strings.TrimSpace(req.Exchange))
+
+	if req.Mode == "" {
+		req.Mode = models.SpreadModeRatio
+	}
+	switch req.Mode {
+	case models.SpreadModeRatio:
+	case models.SpreadModeDifference:
+		if req.HedgeRatio == 0 {
+			req.HedgeRatio = 1
+		}
+	default:
+		return fmt.Errorf("价差计算方式必须是 %s 或 %s", models.SpreadModeRatio, models.SpreadModeDifference)
+	}
+
+	if req.Condition != models.SpreadConditionAbove && req.Condition != models.SpreadConditionBelow {
+		return fmt.Errorf("触发条件必须是 %s 或 %s", models.SpreadConditionAbove, models.SpreadConditionBelow)
+	}
+
+	req.LegA.Symbol = strings.ToUpper(req.LegA.Symbol)
+	req.LegB.Symbol = strings.ToUpper(req.LegB.Symbol)
+	if req.LegA.Symbol == req.LegB.Symbol {
+		return fmt.Errorf("两腿币种不能相同")
+	}
+
+	for _, leg := range []*models.SpreadLeg{&req.LegA, &req.LegB} {
+		if err := s.validateSpreadLeg(req.Exchange, leg); err != nil {
+			return fmt.Errorf("%s: %w", leg.Symbol, err)
+		}
+	}
+	return nil
+}
+
+// validateSpreadLeg 验证单腿配置并写回默认值和精度
+func (s *SpreadController) validateSpreadLeg(exchange string, leg *models.SpreadLeg) error {
+	if _, err := core.ExchangeStore(exchange).GetCoin(leg.Symbol); err != nil {
+		return fmt.Errorf("币种不存在")
+	}
+
+	legReq := &PriceEstimateRequest{
+		Symbol:      leg.Symbol,
+		Exchange:    exchange,
+		Side:        leg.Side,
+		ActionType:  leg.ActionType,
+		OrderType:   types.OrderTypeMarket,
+		TriggerType: models.TriggerTypeImmediate,
+		StakeAmount: leg.StakeAmount,
+		Percentage:  leg.Percentage,
+		Amount:      leg.Amount,
+		Leverage:    leg.Leverage,
+	}
+	if err := s.priceController.validatePriceEstimateRequest(legReq); err != nil {
+		return err
+	}
+	if err := s.priceController.formatPriceEstimatePrecision(legReq); err != nil {
+		return err
+	}
+
+	leg.Side = legReq.Side
+	leg.Leverage = legReq.Leverage
+	leg.Percentage = legReq.Percentage
+	leg.Amount = legReq.Amount
+	return nil
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"net/http"
+	"trading_assistant/core"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// StatsController 币种滚动统计控制器
+type StatsController struct{}
+
+// NewStatsController 创建滚动统计控制器
+func NewStatsController() *StatsController {
+	return &StatsController{}
+}
+
+// GetSymbolStats 获取币种的时段VWAP、24小时已实现波动率和ATR，缓存不存在时根据已保存的K线即时计算
+func (s *StatsController) GetSymbolStats(ctx *gin.Context) {
+	exchange := ctx.Query("exchange")
+	if !core.IsExchangeEnabled(exchange) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "交易所未启用: " + exchange,
+		})
+		return
+	}
+
+	symbol := core.ResolveMarketID(exchange, ctx.Param("symbol"))
+	stats, err := core.GetSymbolStatsService().Get(exchange, symbol)
+	if err != nil {
+		logrus.Debugf("获取 %s 滚动统计失败: %v", symbol, err)
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": "统计数据不存在，请确认已启用K线历史并选中该币种",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": stats,
+	})
+}
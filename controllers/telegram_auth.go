@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"trading_assistant/models"
+)
+
+// telegramViewerCommands 查看者角色可以执行的指令，其他指令需要交易员角色
+var telegramViewerCommands = map[string]bool{
+	"/start":     true,
+	"/help":      true,
+	"/positions": true,
+	"/show":      true,
+}
+
+// telegramCommandRole 执行指令所需的最低角色
+func telegramCommandRole(name string) string {
+	if telegramViewerCommands[name] {
+		return models.TelegramRoleViewer
+	}
+	return models.TelegramRoleTrader
+}
+
+// telegramRoleAllows 判断角色是否满足指令要求
+func telegramRoleAllows(role, required string) bool {
+	if role == models.TelegramRoleTrader {
+		return true
+	}
+	return role == required
+}
+
+// ParseTelegramUsers 解析授权用户列表，格式为 ID:角色，角色省略时为 viewer
+// defaultChatID 不为0时作为交易员加入，兼容只配置单个会话的用法
+func ParseTelegramUsers(entries []string, defaultChatID int64) (map[int64]string, error) {
+	users := make(map[int64]string, len(entries)+1)
+	if defaultChatID != 0 {
+		users[defaultChatID] = models.TelegramRoleTrader
+	}
+
+	for _, entry := range entries {
+		idText, role, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		id, err := strconv.ParseInt(strings.TrimSpace(idText), 10, 64)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("无效的Telegram用户ID: %s", entry)
+		}
+
+		role = strings.ToLower(strings.TrimSpace(role))
+		if role == "" {
+			role = models.TelegramRoleViewer
+		}
+		if role != models.TelegramRoleViewer && role != models.TelegramRoleTrader {
+			return nil, fmt.Errorf("无效的Telegram用户角色: %s，必须是 %s 或 %s", entry, models.TelegramRoleViewer, models.TelegramRoleTrader)
+		}
+		users[id] = role
+	}
+	return users, nil
+}
@@ -0,0 +1,319 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/telegram"
+
+	"github.com/sirupsen/logrus"
+)
+
+// telegramPollTimeout 长轮询等待时长
+const telegramPollTimeout = 30 * time.Second
+
+// TelegramBot 通过长轮询接收Telegram指令并回复到发起指令的会话
+type TelegramBot struct {
+	client              *telegram.Client
+	users               map[int64]string // 用户或会话ID -> 角色
+	priceController     *PriceController
+	templateController  *TemplateController
+	freqtradeController *freqtrade.Controller
+	cancel              context.CancelFunc
+	done                chan struct{}
+}
+
+// NewTelegramBot 创建Telegram指令机器人，只处理 users 中已授权用户的消息
+func NewTelegramBot(token string, users map[int64]string, priceController *PriceController, freqtradeController *freqtrade.Controller) *TelegramBot {
+	return &TelegramBot{
+		client:              telegram.NewClient(token),
+		users:               users,
+		priceController:     priceController,
+		templateController:  NewTemplateController(priceController),
+		freqtradeController: freqtradeController,
+	}
+}
+
+// Start 启动指令轮询
+func (b *TelegramBot) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.done = make(chan struct{})
+	go b.poll(ctx)
+	logrus.Info("Telegram指令机器人已启动")
+}
+
+// Stop 停止指令轮询
+func (b *TelegramBot) Stop(ctx context.Context) error {
+	if b.cancel == nil {
+		return nil
+	}
+	b.cancel()
+
+	select {
+	case <-b.done:
+		logrus.Info("Telegram指令机器人已停止")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// poll 长轮询获取消息
+func (b *TelegramBot) poll(ctx context.Context) {
+	defer close(b.done)
+
+	var offset int64
+	for {
+		updates, err := b.client.GetUpdates(ctx, offset, telegramPollTimeout)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logrus.Warnf("获取Telegram消息失败: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+
+		for i := range updates {
+			update := &updates[i]
+			offset = update.UpdateID + 1
+			if update.Message == nil || update.Message.Text == "" {
+				continue
+			}
+			b.handleMessage(ctx, update.Message)
+		}
+	}
+}
+
+// handleMessage 处理单条消息，校验权限、记录审计并回复到原会话
+func (b *TelegramBot) handleMessage(ctx context.Context, message *telegram.Message) {
+	name := telegramCommandName(message.Text)
+	if name == "" {
+		return
+	}
+
+	audit := &models.TelegramAudit{
+		ChatID:    message.Chat.ID,
+		Command:   message.Text,
+		Timestamp: time.Now().UnixMilli(),
+	}
+	if message.From != nil {
+		audit.UserID = message.From.ID
+		audit.Username = message.From.Username
+	}
+	audit.Role = b.roleFor(message)
+
+	var replies []string
+	switch {
+	case audit.Role == "":
+		// 未授权的会话不回复，避免暴露机器人
+		logrus.Warnf("忽略未授权用户 %d (会话 %d) 的Telegram指令: %s", audit.UserID, audit.ChatID, name)
+		audit.Result = i18n.T("telegram.unauthorized")
+	case !telegramRoleAllows(audit.Role, telegramCommandRole(name)):
+		replies = []string{i18n.T("telegram.permission_denied", audit.Role, name)}
+	default:
+		audit.Allowed = true
+		replies = b.handleCommand(name, message.Text)
+	}
+	if len(replies) > 0 {
+		audit.Result = replies[0]
+	}
+
+	if redis.GlobalRedisClient != nil {
+		if err := redis.GlobalRedisClient.AddTelegramAudit(audit); err != nil {
+			logrus.Errorf("记录Telegram指令审计失败: %v", err)
+		}
+	}
+
+	for _, reply := range replies {
+		if err := b.client.SendMessage(ctx, message.Chat.ID, reply); err != nil {
+			logrus.Errorf("回复Telegram消息失败: %v", err)
+			return
+		}
+	}
+}
+
+// roleFor 获取消息发送者的角色，优先按用户ID匹配，其次按会话ID匹配
+func (b *TelegramBot) roleFor(message *telegram.Message) string {
+	if message.From != nil {
+		if role, exists := b.users[message.From.ID]; exists {
+			return role
+		}
+	}
+	return b.users[message.Chat.ID]
+}
+
+// telegramCommandName 提取指令名，非指令消息返回空
+func telegramCommandName(text string) string {
+	fields := strings.Fields(strings.TrimSpace(text))
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return ""
+	}
+
+	name := strings.ToLower(fields[0])
+	// 兼容群组中的 /positions@BotName 写法
+	if idx := strings.Index(name, "@"); idx > 0 {
+		name = name[:idx]
+	}
+	return name
+}
+
+// handleCommand 执行指令，返回需要回复的消息
+func (b *TelegramBot) handleCommand(name, text string) []string {
+	switch name {
+	case "/start", "/help":
+		return []string{i18n.T("telegram.help")}
+	case "/positions":
+		return b.positionsReply()
+	case "/show":
+		return b.showReply(text)
+	case "/tpl":
+		return []string{b.templateReply(text)}
+	case "/panic":
+		return []string{b.panicReply(text)}
+	case "/resume":
+		return []string{b.resumeReply()}
+	default:
+		return []string{b.createEstimate(text)}
+	}
+}
+
+// createEstimate 将交易指令转换为价格预估并保存
+func (b *TelegramBot) createEstimate(text string) string {
+	command, err := ParseTelegramCommand(text)
+	if err != nil {
+		return i18n.T("telegram.error", err)
+	}
+	req := command.Request
+
+	// 与创建接口走相同的校验和精度处理
+	if err := b.priceController.validatePriceEstimateRequest(req); err != nil {
+		return i18n.T("telegram.error", err)
+	}
+	if err := b.priceController.formatPriceEstimatePrecision(req); err != nil {
+		return i18n.T("telegram.format_failed", err)
+	}
+	if redis.GlobalRedisClient == nil {
+		return i18n.T("telegram.redis_unavailable")
+	}
+
+	estimate := b.priceController.createPriceEstimateModel(req)
+	if err := b.priceController.savePriceEstimate(estimate); err != nil {
+		logrus.Errorf("保存Telegram价格预估失败: %v", err)
+		return i18n.T("telegram.save_failed")
+	}
+
+	if estimate.TargetPrice > 0 {
+		return i18n.T("telegram.created", estimate.Symbol, estimate.Side, estimate.ActionType, formatTargetPrice(estimate, command.PriceExpr))
+	}
+	return i18n.T("telegram.created_immediate", estimate.Symbol, estimate.Side, estimate.ActionType)
+}
+
+// panicReply 处理 /panic 指令，开启紧急停止
+// 格式: /panic [cancel] [原因]
+func (b *TelegramBot) panicReply(text string) string {
+	if redis.GlobalRedisClient == nil {
+		return i18n.T("telegram.redis_unavailable")
+	}
+
+	fields := strings.Fields(strings.TrimSpace(text))[1:]
+	cancelEntries := len(fields) > 0 && strings.EqualFold(fields[0], "cancel")
+	if cancelEntries {
+		fields = fields[1:]
+	}
+	reason := strings.Join(fields, " ")
+	if reason == "" {
+		reason = i18n.T("telegram.panic_reason")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	state, err := core.ActivateKillSwitch(ctx, reason, "telegram", cancelEntries)
+	if err != nil {
+		logrus.Errorf("Telegram开启紧急停止失败: %v", err)
+		return i18n.T("telegram.panic_failed")
+	}
+	if cancelEntries {
+		return i18n.T("telegram.panic_cancelled", state.CancelledOrders)
+	}
+	return i18n.T("telegram.panic_activated")
+}
+
+// resumeReply 处理 /resume 指令，解除紧急停止
+func (b *TelegramBot) resumeReply() string {
+	if redis.GlobalRedisClient == nil {
+		return i18n.T("telegram.redis_unavailable")
+	}
+	if _, err := core.ReleaseKillSwitch("telegram"); err != nil {
+		logrus.Errorf("Telegram解除紧急停止失败: %v", err)
+		return i18n.T("telegram.resume_failed")
+	}
+	return i18n.T("telegram.resumed")
+}
+
+// templateReply 处理 /tpl 指令：不带参数时列出模板，否则按模板创建价格预估
+// 格式: /tpl <模板> <币种> [价格|m]
+func (b *TelegramBot) templateReply(text string) string {
+	fields := strings.Fields(strings.TrimSpace(text))
+	if redis.GlobalRedisClient == nil {
+		return i18n.T("telegram.redis_unavailable")
+	}
+
+	if len(fields) == 1 {
+		templates, err := redis.GlobalRedisClient.GetAllEstimateTemplates()
+		if err != nil {
+			return i18n.T("telegram.template_failed")
+		}
+		if len(templates) == 0 {
+			return i18n.T("telegram.template_empty")
+		}
+		lines := []string{i18n.T("telegram.template_header")}
+		for _, template := range templates {
+			lines = append(lines, fmt.Sprintf("%s: %s %s %dx", template.Name, template.Side, template.ActionType, template.Leverage))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	if len(fields) < 3 || len(fields) > 4 {
+		return i18n.T("telegram.template_usage")
+	}
+
+	symbol, err := resolveCommandSymbol(fields[2])
+	if err != nil {
+		return i18n.T("telegram.error", err)
+	}
+
+	var price float64
+	var priceExpr string
+	if len(fields) == 4 && !strings.EqualFold(fields[3], "m") {
+		var relative bool
+		price, relative, err = resolveCommandPrice(symbol, fields[3])
+		if err != nil {
+			return i18n.T("telegram.error", err)
+		}
+		if relative {
+			priceExpr = fields[3]
+		}
+	}
+
+	estimate, err := b.templateController.CreateFromTemplate(fields[1], "", symbol, price)
+	if err != nil {
+		return i18n.T("telegram.error", err)
+	}
+
+	if estimate.TargetPrice > 0 {
+		return i18n.T("telegram.template_created", fields[1], estimate.Symbol, estimate.Side, estimate.ActionType, formatTargetPrice(estimate, priceExpr))
+	}
+	return i18n.T("telegram.template_created_immediate", fields[1], estimate.Symbol, estimate.Side, estimate.ActionType)
+}
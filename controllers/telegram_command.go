@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/i18n"
+)
+
+// telegramCommandSpec Telegram交易指令定义
+type telegramCommandSpec struct {
+	side       string
+	actionType string
+	valueName  string // 第二个参数的含义
+}
+
+// telegramCommands 支持的交易指令
+// 格式: /<指令> <币种> <数值> [价格|m] [杠杆]，价格省略或为 m 时立即按市价执行
+// 价格可以相对当前标记价格表示：+2%、-2%（按比例）、+150、-150（按差值）或 mp（当前标记价格）
+var telegramCommands = map[string]telegramCommandSpec{
+	"/ol": {side: types.PositionSideLong, actionType: models.ActionTypeOpen, valueName: "stake"},         // 开多，数值为保证金
+	"/os": {side: types.PositionSideShort, actionType: models.ActionTypeOpen, valueName: "stake"},        // 开空，数值为保证金
+	"/al": {side: types.PositionSideLong, actionType: models.ActionTypeAddition, valueName: "percent"},   // 多单加仓，数值为仓位比例
+	"/as": {side: types.PositionSideShort, actionType: models.ActionTypeAddition, valueName: "percent"},  // 空单加仓，数值为仓位比例
+	"/tl": {side: types.PositionSideLong, actionType: models.ActionTypeTakeProfit, valueName: "amount"},  // 多单止盈，数值为币的数量
+	"/ts": {side: types.PositionSideShort, actionType: models.ActionTypeTakeProfit, valueName: "amount"}, // 空单止盈，数值为币的数量
+	"/sl": {side: types.PositionSideLong, actionType: models.ActionTypeStopLoss, valueName: "amount"},    // 多单止损，数值为币的数量
+	"/ss": {side: types.PositionSideShort, actionType: models.ActionTypeStopLoss, valueName: "amount"},   // 空单止损，数值为币的数量
+}
+
+// TelegramCommand 解析后的Telegram交易指令
+type TelegramCommand struct {
+	Request   *PriceEstimateRequest
+	PriceExpr string // 相对价格表达式（如 +2%、-150、mp），使用绝对价格时为空
+}
+
+// ParseTelegramCommand 将Telegram交易指令解析为价格预估请求，相对价格在解析时按当前标记价格换算
+func ParseTelegramCommand(command string) (*TelegramCommand, error) {
+	fields := strings.Fields(strings.TrimSpace(command))
+	if len(fields) < 3 {
+		return nil, errors.New(i18n.T("telegram.command_usage"))
+	}
+
+	name := strings.ToLower(fields[0])
+	// 兼容群组中的 /ol@BotName 写法
+	if idx := strings.Index(name, "@"); idx > 0 {
+		name = name[:idx]
+	}
+	spec, exists := telegramCommands[name]
+	if !exists {
+		return nil, errors.New(i18n.T("telegram.command_unsupported", fields[0]))
+	}
+
+	symbol, err := resolveCommandSymbol(fields[1])
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil || value <= 0 {
+		return nil, errors.New(i18n.T("telegram.invalid_value", fields[2]))
+	}
+
+	req := &PriceEstimateRequest{
+		Symbol:      symbol,
+		Side:        spec.side,
+		ActionType:  spec.actionType,
+		OrderType:   types.OrderTypeMarket,
+		TriggerType: models.TriggerTypeImmediate,
+	}
+	switch spec.valueName {
+	case "stake":
+		req.StakeAmount = value
+	case "percent":
+		req.Percentage = value
+	case "amount":
+		req.Amount = value
+	}
+
+	parsed := &TelegramCommand{Request: req}
+	if len(fields) > 3 && !strings.EqualFold(fields[3], "m") {
+		price, relative, err := resolveCommandPrice(symbol, fields[3])
+		if err != nil {
+			return nil, err
+		}
+		if relative {
+			parsed.PriceExpr = fields[3]
+		}
+		req.TargetPrice = price
+		req.TriggerType = models.TriggerTypeCondition
+		// 止损触发后按市价离场，避免价格继续不利时限价单无法成交
+		if spec.actionType != models.ActionTypeStopLoss {
+			req.OrderType = types.OrderTypeLimit
+		}
+	}
+
+	if len(fields) > 4 {
+		leverage, err := strconv.Atoi(fields[4])
+		if err != nil || leverage <= 0 {
+			return nil, errors.New(i18n.T("telegram.invalid_leverage", fields[4]))
+		}
+		req.Leverage = leverage
+	}
+
+	if len(fields) > 5 {
+		return nil, errors.New(i18n.T("telegram.command_too_many"))
+	}
+
+	return parsed, nil
+}
+
+// resolveCommandPrice 解析指令中的价格，relative 表示价格是相对当前标记价格换算的
+// 支持绝对价格、mp（当前标记价格）、+2%/-2%（按比例偏移）和 +150/-150（按差值偏移）
+func resolveCommandPrice(symbol, expr string) (float64, bool, error) {
+	if !strings.EqualFold(expr, "mp") && !strings.HasPrefix(expr, "+") && !strings.HasPrefix(expr, "-") {
+		price, err := strconv.ParseFloat(expr, 64)
+		if err != nil || price <= 0 {
+			return 0, false, errors.New(i18n.T("telegram.invalid_price", expr))
+		}
+		return price, false, nil
+	}
+
+	markPrice, err := core.ExchangeStore("").GetMarkPrice(symbol)
+	if err != nil || markPrice.MarkPrice <= 0 {
+		return 0, true, errors.New(i18n.T("telegram.mark_price_unavailable", symbol, expr))
+	}
+	price, err := relativeCommandPrice(markPrice.MarkPrice, expr)
+	return price, true, err
+}
+
+// relativeCommandPrice 按当前价格换算相对价格表达式（mp、+2%/-2%、+150/-150）
+func relativeCommandPrice(current float64, expr string) (float64, error) {
+	if strings.EqualFold(expr, "mp") {
+		return current, nil
+	}
+
+	percent := strings.HasSuffix(expr, "%")
+	offset, err := strconv.ParseFloat(strings.TrimSuffix(expr, "%"), 64)
+	if err != nil || offset == 0 {
+		return 0, errors.New(i18n.T("telegram.invalid_price", expr))
+	}
+
+	price := current + offset
+	if percent {
+		price = current * (1 + offset/100)
+	}
+	if price <= 0 {
+		return 0, errors.New(i18n.T("telegram.relative_price_invalid", expr, strconv.FormatFloat(price, 'f', -1, 64)))
+	}
+	return price, nil
+}
+
+// resolveCommandSymbol 将指令中的币种（如 BTC、btcusdt、BTC/USDT）解析为主交易所的MarketID
+func resolveCommandSymbol(input string) (string, error) {
+	mapper := core.SymbolMapper("")
+	if market, ok := mapper.Resolve(input); ok {
+		return market.ID, nil
+	}
+	if market, ok := mapper.Resolve(input + "USDT"); ok {
+		return market.ID, nil
+	}
+
+	// 市场数据未加载时按MarketID直接查找
+	symbol := strings.ToUpper(strings.ReplaceAll(input, "/", ""))
+	store := core.ExchangeStore("")
+
+	if _, err := store.GetCoin(symbol); err == nil {
+		return symbol, nil
+	}
+	if _, err := store.GetCoin(symbol + "USDT"); err == nil {
+		return symbol + "USDT", nil
+	}
+	return "", errors.New(i18n.T("telegram.unknown_symbol", input))
+}
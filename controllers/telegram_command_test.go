@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRelativeCommandPrice(t *testing.T) {
+	tests := []struct {
+		name    string
+		current float64
+		expr    string
+		want    float64
+		wantErr bool
+	}{
+		{name: "标记价格", current: 50000, expr: "mp", want: 50000},
+		{name: "标记价格大写", current: 50000, expr: "MP", want: 50000},
+		{name: "向上按比例", current: 50000, expr: "+2%", want: 51000},
+		{name: "向下按比例", current: 50000, expr: "-2.5%", want: 48750},
+		{name: "向上按差值", current: 3000, expr: "+150", want: 3150},
+		{name: "向下按差值", current: 3000, expr: "-150.5", want: 2849.5},
+		{name: "偏移为零", current: 3000, expr: "+0", wantErr: true},
+		{name: "比例为零", current: 3000, expr: "-0%", wantErr: true},
+		{name: "缺少数值", current: 3000, expr: "+", wantErr: true},
+		{name: "缺少比例数值", current: 3000, expr: "+%", wantErr: true},
+		{name: "非数字", current: 3000, expr: "+abc", wantErr: true},
+		{name: "换算后为负", current: 100, expr: "-150", wantErr: true},
+		{name: "向下全部比例", current: 100, expr: "-100%", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := relativeCommandPrice(tt.current, tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望换算失败，实际得到 %v", price)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("换算失败: %v", err)
+			}
+			if math.Abs(price-tt.want) > 1e-9 {
+				t.Errorf("价格 = %v，期望 %v", price, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveCommandPriceAbsolute(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    float64
+		wantErr bool
+	}{
+		{name: "整数", expr: "70000", want: 70000},
+		{name: "小数", expr: "0.0523", want: 0.0523},
+		{name: "零", expr: "0", wantErr: true},
+		{name: "非数字", expr: "abc", wantErr: true},
+		{name: "空", expr: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, relative, err := resolveCommandPrice("BTCUSDT", tt.expr)
+			if relative {
+				t.Fatalf("%q 不应按相对价格处理", tt.expr)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望解析失败，实际得到 %v", price)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			if price != tt.want {
+				t.Errorf("价格 = %v，期望 %v", price, tt.want)
+			}
+		})
+	}
+}
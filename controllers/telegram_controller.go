@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// TelegramController Telegram指令控制器
+type TelegramController struct {
+	priceController *PriceController
+}
+
+// NewTelegramController 创建Telegram指令控制器
+func NewTelegramController(priceController *PriceController) *TelegramController {
+	return &TelegramController{
+		priceController: priceController,
+	}
+}
+
+// TelegramPreviewRequest 指令预览请求
+type TelegramPreviewRequest struct {
+	Command string `json:"command" binding:"required"` // 原始指令，如 "/ol BTC 100 50000"
+}
+
+// PreviewCommand 预览指令将创建的价格预估（不保存）
+func (t *TelegramController) PreviewCommand(ctx *gin.Context) {
+	var req TelegramPreviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	command, err := ParseTelegramCommand(req.Command)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	estimateReq := command.Request
+
+	// 与创建接口走相同的校验和精度处理
+	if err := t.priceController.validatePriceEstimateRequest(estimateReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := t.priceController.formatPriceEstimatePrecision(estimateReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "格式化精度失败: " + err.Error(),
+		})
+		return
+	}
+
+	estimate := t.priceController.createPriceEstimateModel(estimateReq)
+
+	// 参考价格：条件单为目标价，市价单为当前标记价格
+	referencePrice := estimate.TargetPrice
+	if referencePrice <= 0 {
+		if markPrice, err := core.ExchangeStore(estimate.Exchange).GetMarkPrice(estimate.Symbol); err == nil {
+			referencePrice = markPrice.MarkPrice
+		}
+	}
+
+	preview := gin.H{
+		"estimate":        estimate,
+		"reference_price": referencePrice,
+	}
+	if command.PriceExpr != "" {
+		preview["price_expression"] = command.PriceExpr
+	}
+	if estimate.StakeAmount > 0 && referencePrice > 0 {
+		notional := estimate.StakeAmount * float64(estimate.Leverage)
+		preview["notional"] = notional
+		preview["estimated_quantity"] = notional / referencePrice
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "指令解析成功",
+		"data":    preview,
+	})
+}
+
+// GetAudits 获取Telegram指令审计记录，可按 user_id 过滤
+func (t *TelegramController) GetAudits(ctx *gin.Context) {
+	limit, err := strconv.ParseInt(ctx.DefaultQuery("limit", "100"), 10, 64)
+	if err != nil || limit <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit参数格式错误",
+		})
+		return
+	}
+
+	var userID int64
+	if raw := ctx.Query("user_id"); raw != "" {
+		userID, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "user_id参数格式错误",
+			})
+			return
+		}
+	}
+
+	records, err := redis.GlobalRedisClient.GetTelegramAudits(limit)
+	if err != nil {
+		logrus.Errorf("获取Telegram指令审计记录失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取指令审计记录失败",
+		})
+		return
+	}
+
+	if userID != 0 {
+		filtered := make([]*models.TelegramAudit, 0, len(records))
+		for _, record := range records {
+			if record.UserID == userID {
+				filtered = append(filtered, record)
+			}
+		}
+		records = filtered
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  records,
+		"count": len(records),
+	})
+}
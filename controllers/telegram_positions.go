@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/telegram"
+	"trading_assistant/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// positionsReply 生成 /positions 指令的回复
+func (b *TelegramBot) positionsReply() []string {
+	trades, err := b.freqtradeController.GetTradeStatus(context.Background())
+	stale := false
+	if err != nil {
+		// Freqtrade暂不可用时使用上次缓存的交易状态
+		logrus.Warnf("获取Freqtrade交易状态失败，使用缓存: %v", err)
+		trades = b.freqtradeController.TradeStatus
+		stale = true
+	}
+
+	lines := renderPositionLines(trades)
+	if stale {
+		lines = append(lines, i18n.T("telegram.positions_stale"))
+	}
+	return telegram.SplitMessage(lines, telegram.MaxMessageLength)
+}
+
+// renderPositionLines 将未平仓交易渲染为消息行：每个持仓一行，最后一行为汇总
+func renderPositionLines(trades []models.TradePosition) []string {
+	store := core.ExchangeStore("")
+	formatter := core.Formatter("")
+	lines := make([]string, 0, len(trades)+2)
+
+	count := 0
+	totalStake := 0.0
+	totalPnl := 0.0
+	for i := range trades {
+		trade := &trades[i]
+		if !trade.IsOpen {
+			continue
+		}
+		count++
+
+		marketID := utils.ConvertSymbolToMarketID(trade.Pair)
+		leverage := 1.0
+		if trade.Leverage != nil && *trade.Leverage > 0 {
+			leverage = *trade.Leverage
+		}
+
+		// 优先使用实时标记价格，没有时使用Freqtrade的当前价格
+		markPrice := trade.CurrentRate
+		if data, err := store.GetMarkPrice(marketID); err == nil && data != nil && data.MarkPrice > 0 {
+			markPrice = data.MarkPrice
+		}
+
+		direction := 1.0
+		sideText := i18n.T("telegram.long")
+		if trade.IsShort {
+			direction = -1.0
+			sideText = i18n.T("telegram.short")
+		}
+
+		pnl := trade.CurrentProfitAbs
+		pnlPct := trade.CurrentProfitPct
+		if trade.OpenRate > 0 && markPrice > 0 {
+			pnl = (markPrice - trade.OpenRate) * trade.Amount * direction
+			pnlPct = (markPrice - trade.OpenRate) / trade.OpenRate * direction * leverage * 100
+		}
+		totalStake += trade.StakeAmount
+		totalPnl += pnl
+
+		lines = append(lines, i18n.T("telegram.positions_line",
+			marketID, sideText, leverage, formatter.FormatPrice(marketID, trade.OpenRate), formatter.FormatPrice(marketID, markPrice), pnlPct, pnl))
+	}
+
+	if count == 0 {
+		return []string{i18n.T("telegram.positions_empty")}
+	}
+
+	totalPct := 0.0
+	if totalStake > 0 {
+		totalPct = totalPnl / totalStake * 100
+	}
+	header := i18n.T("telegram.positions_header", count)
+	summary := i18n.T("telegram.positions_summary", totalStake, totalPnl, totalPct)
+	return append(append([]string{header}, lines...), summary)
+}
+
+// formatTargetPrice 按币种价格精度格式化预估目标价格，相对价格同时显示原始表达式
+func formatTargetPrice(estimate *models.PriceEstimate, expr string) string {
+	price := core.Formatter(estimate.Exchange).FormatPrice(estimate.Symbol, estimate.TargetPrice)
+	if expr == "" {
+		return price
+	}
+	return fmt.Sprintf("%s（%s）", price, expr)
+}
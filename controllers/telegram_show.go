@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"strings"
+	"time"
+	"trading_assistant/core"
+	"trading_assistant/models"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/telegram"
+
+	"github.com/sirupsen/logrus"
+)
+
+// telegramShowEventLimit /show 指令显示的最近事件数
+const telegramShowEventLimit = 20
+
+// showReply 处理 /show 指令，显示预估和最近的时间线事件
+// 格式: /show <预估ID或ID前缀>
+func (b *TelegramBot) showReply(text string) []string {
+	fields := strings.Fields(strings.TrimSpace(text))
+	if len(fields) != 2 {
+		return []string{i18n.T("telegram.show_usage")}
+	}
+	if redis.GlobalRedisClient == nil {
+		return []string{i18n.T("telegram.redis_unavailable")}
+	}
+
+	estimate := findEstimateByIDPrefix(fields[1])
+	if estimate == nil {
+		return []string{i18n.T("telegram.show_not_found", fields[1])}
+	}
+
+	target := core.Formatter(estimate.Exchange).FormatPrice(estimate.Symbol, estimate.TargetPrice)
+	lines := []string{
+		i18n.T("telegram.show_header", estimate.Symbol, estimate.Side, estimate.ActionType, target, estimate.Status, estimate.Enabled),
+		estimate.ID,
+	}
+	if estimate.ErrorMessage != "" {
+		lines = append(lines, i18n.T("telegram.error", estimate.ErrorMessage))
+	}
+
+	events, err := redis.GlobalRedisClient.GetEstimateEvents(estimate.ID, telegramShowEventLimit)
+	if err != nil {
+		logrus.Errorf("获取预估 %s 时间线失败: %v", estimate.ID, err)
+	}
+	if len(events) == 0 {
+		lines = append(lines, i18n.T("telegram.show_no_events"))
+	}
+	for _, event := range events {
+		line := time.UnixMilli(event.Timestamp).Format("01-02 15:04:05") + " " + event.Type
+		if event.Message != "" {
+			line += ": " + event.Message
+		}
+		lines = append(lines, line)
+	}
+	return telegram.SplitMessage(lines, telegram.MaxMessageLength)
+}
+
+// findEstimateByIDPrefix 按完整ID查找预估，找不到时按ID前缀匹配，前缀匹配到多个预估时返回nil
+func findEstimateByIDPrefix(id string) *models.PriceEstimate {
+	if estimate, err := redis.GlobalRedisClient.GetEstimateById(id); err == nil {
+		return estimate
+	}
+
+	estimates, err := redis.GlobalRedisClient.GetAllEstimates()
+	if err != nil {
+		logrus.Errorf("获取价格预估失败: %v", err)
+		return nil
+	}
+	var found *models.PriceEstimate
+	for _, estimate := range estimates {
+		if !strings.HasPrefix(estimate.ID, id) {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = estimate
+	}
+	return found
+}
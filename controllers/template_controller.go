@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// templateNamePattern 模板名只允许小写字母、数字、下划线和横线，便于在Telegram指令中输入
+var templateNamePattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// TemplateController 价格预估模板控制器
+type TemplateController struct {
+	priceController *PriceController
+}
+
+// NewTemplateController 创建价格预估模板控制器
+func NewTemplateController(priceController *PriceController) *TemplateController {
+	return &TemplateController{
+		priceController: priceController,
+	}
+}
+
+// TemplateEstimateRequest 从模板创建价格预估请求
+type TemplateEstimateRequest struct {
+	Symbol      string  `json:"symbol" binding:"required"`
+	Exchange    string  `json:"exchange"`     // 价格来源交易所（为空使用主交易所）
+	TargetPrice float64 `json:"target_price"` // 目标价格，为0时立即执行
+}
+
+// validateTemplate 验证模板配置，币种和价格相关的校验在创建预估时进行
+func validateTemplate(template *models.EstimateTemplate) error {
+	template.Name = strings.ToLower(strings.TrimSpace(template.Name))
+	if !templateNamePattern.MatchString(template.Name) {
+		return fmt.Errorf("模板名只能包含小写字母、数字、下划线和横线，长度1-32")
+	}
+
+	switch template.ActionType {
+	case models.ActionTypeOpen, models.ActionTypeAddition, models.ActionTypeTakeProfit, models.ActionTypeStopLoss:
+	default:
+		return fmt.Errorf("无效的操作类型: %s", template.ActionType)
+	}
+	if template.Side != types.PositionSideLong && template.Side != types.PositionSideShort {
+		return fmt.Errorf("交易方向必须是 %s 或 %s", types.PositionSideLong, types.PositionSideShort)
+	}
+	if template.TriggerType != "" && template.TriggerType != models.TriggerTypeCondition && template.TriggerType != models.TriggerTypeImmediate {
+		return fmt.Errorf("触发类型必须是 %s 或 %s", models.TriggerTypeCondition, models.TriggerTypeImmediate)
+	}
+	if template.Leverage < 0 || template.Percentage < 0 || template.StakeAmount < 0 || template.Amount < 0 || template.TTLSeconds < 0 {
+		return fmt.Errorf("杠杆、比例、金额、数量和有效期不能为负数")
+	}
+
+	// 拆单策略与创建预估使用相同的校验
+	return validateOrderStrategy(&PriceEstimateRequest{
+		ActionType:    template.ActionType,
+		StakeAmount:   template.StakeAmount,
+		OrderStrategy: template.OrderStrategy,
+	})
+}
+
+// templateToRequest 将模板与币种、价格合并为价格预估请求
+func templateToRequest(template *models.EstimateTemplate, exchange, symbol string, price float64) *PriceEstimateRequest {
+	req := &PriceEstimateRequest{
+		Symbol:        symbol,
+		Exchange:      exchange,
+		Side:          template.Side,
+		ActionType:    template.ActionType,
+		TargetPrice:   price,
+		Percentage:    template.Percentage,
+		Leverage:      template.Leverage,
+		OrderType:     template.OrderType,
+		MarginMode:    template.MarginMode,
+		TriggerType:   template.TriggerType,
+		StakeAmount:   template.StakeAmount,
+		Amount:        template.Amount,
+		TTLSeconds:    template.TTLSeconds,
+		OrderStrategy: template.OrderStrategy,
+	}
+	if template.Tag != "" {
+		req.Tag = template.Tag
+	}
+
+	// 未指定价格时立即按市价执行
+	if price <= 0 {
+		req.TriggerType = models.TriggerTypeImmediate
+		req.OrderType = types.OrderTypeMarket
+	} else if req.TriggerType == "" || req.TriggerType == models.TriggerTypeImmediate {
+		req.TriggerType = models.TriggerTypeCondition
+	}
+	return req
+}
+
+// CreateFromTemplate 使用模板为指定币种和价格创建价格预估，与创建接口走相同的校验和精度处理
+func (t *TemplateController) CreateFromTemplate(name, exchange, symbol string, price float64) (*models.PriceEstimate, error) {
+	if redis.GlobalRedisClient == nil {
+		return nil, fmt.Errorf("redis服务不可用")
+	}
+
+	template, err := redis.GlobalRedisClient.GetEstimateTemplate(strings.ToLower(name))
+	if err != nil {
+		return nil, fmt.Errorf("模板 %s 不存在", name)
+	}
+
+	req := templateToRequest(template, exchange, symbol, price)
+	if err := t.priceController.validatePriceEstimateRequest(req); err != nil {
+		return nil, err
+	}
+	if err := t.priceController.formatPriceEstimatePrecision(req); err != nil {
+		return nil, fmt.Errorf("格式化精度失败: %w", err)
+	}
+
+	estimate := t.priceController.createPriceEstimateModel(req)
+	if err := t.priceController.savePriceEstimate(estimate); err != nil {
+		logrus.Errorf("保存模板价格预估失败: %v", err)
+		return nil, fmt.Errorf("保存价格预估失败")
+	}
+	return estimate, nil
+}
+
+// GetTemplates 获取所有价格预估模板
+func (t *TemplateController) GetTemplates(ctx *gin.Context) {
+	templates, err := redis.GlobalRedisClient.GetAllEstimateTemplates()
+	if err != nil {
+		logrus.Errorf("获取价格预估模板失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取价格预估模板失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "获取价格预估模板成功",
+		"data":    templates,
+		"count":   len(templates),
+	})
+}
+
+// SaveTemplate 保存价格预估模板，同名模板会被覆盖
+func (t *TemplateController) SaveTemplate(ctx *gin.Context) {
+	var template models.EstimateTemplate
+	if err := ctx.ShouldBindJSON(&template); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	if err := validateTemplate(&template); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	template.CreatedAt = now
+	if existing, err := redis.GlobalRedisClient.GetEstimateTemplate(template.Name); err == nil {
+		template.CreatedAt = existing.CreatedAt
+	}
+	template.UpdatedAt = now
+
+	if err := redis.GlobalRedisClient.SetEstimateTemplate(&template); err != nil {
+		logrus.Errorf("保存价格预估模板失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "保存价格预估模板失败",
+		})
+		return
+	}
+
+	logrus.Infof("保存价格预估模板成功: %s %s %s", template.Name, template.Side, template.ActionType)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "价格预估模板保存成功",
+		"data":    template,
+	})
+}
+
+// DeleteTemplate 删除价格预估模板
+func (t *TemplateController) DeleteTemplate(ctx *gin.Context) {
+	if err := redis.GlobalRedisClient.DeleteEstimateTemplate(ctx.Param("name")); err != nil {
+		logrus.Errorf("删除价格预估模板失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "删除价格预估模板失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "价格预估模板删除成功",
+	})
+}
+
+// CreateEstimateFromTemplate 使用模板一键创建价格预估
+func (t *TemplateController) CreateEstimateFromTemplate(ctx *gin.Context) {
+	var req TemplateEstimateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+	if req.TargetPrice < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "目标价格不能为负数",
+		})
+		return
+	}
+
+	estimate, err := t.CreateFromTemplate(ctx.Param("name"), req.Exchange, req.Symbol, req.TargetPrice)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "价格预估创建成功",
+		"data":    estimate,
+	})
+}
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// WebhookController 外部信号Webhook控制器
+type WebhookController struct {
+	priceController *PriceController
+}
+
+// NewWebhookController 创建Webhook控制器
+func NewWebhookController(priceController *PriceController) *WebhookController {
+	return &WebhookController{
+		priceController: priceController,
+	}
+}
+
+// TradingViewAlert TradingView 告警消息体
+// 价格为空或为0时立即按市价执行，否则创建条件单在到达价格时触发
+type TradingViewAlert struct {
+	Passphrase  string  `json:"passphrase"`
+	Symbol      string  `json:"symbol"`   // 如 BTCUSDT、BINANCE:BTCUSDT.P、BTC
+	Side        string  `json:"side"`     // long, short, buy, sell
+	Action      string  `json:"action"`   // open, addition, take_profit, stop_loss
+	Price       float64 `json:"price"`    // 触发价格
+	Trigger     string  `json:"trigger"`  // immediate, condition（为空时按价格判断）
+	Exchange    string  `json:"exchange"` // 价格来源交易所（为空使用主交易所）
+	StakeAmount float64 `json:"stake_amount"`
+	Percentage  float64 `json:"percentage"`
+	Amount      float64 `json:"amount"`
+	Leverage    int     `json:"leverage"`
+	Tag         string  `json:"tag"`
+}
+
+// TradingView 接收 TradingView 告警并转换为价格预估
+func (w *WebhookController) TradingView(ctx *gin.Context) {
+	secret := config.GlobalConfig.TradingViewWebhookSecret
+	if secret == "" {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "TradingView Webhook 未启用",
+		})
+		return
+	}
+
+	var alert TradingViewAlert
+	if err := ctx.ShouldBindJSON(&alert); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数格式错误",
+		})
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(alert.Passphrase), []byte(secret)) != 1 {
+		logrus.Warnf("TradingView Webhook 口令错误，来源: %s", ctx.ClientIP())
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"error": "口令错误",
+		})
+		return
+	}
+
+	req, err := parseTradingViewAlert(&alert)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// 与创建接口走相同的校验和精度处理
+	if err := w.priceController.validatePriceEstimateRequest(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := w.priceController.formatPriceEstimatePrecision(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "格式化精度失败: " + err.Error(),
+		})
+		return
+	}
+
+	if redis.GlobalRedisClient == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Redis服务不可用",
+		})
+		return
+	}
+
+	estimate := w.priceController.createPriceEstimateModel(req)
+	if err := w.priceController.savePriceEstimate(estimate); err != nil {
+		logrus.Errorf("保存TradingView价格预估失败: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "保存价格预估失败",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "TradingView 告警已转换为价格预估",
+		"data":    estimate,
+	})
+}
+
+// parseTradingViewAlert 将 TradingView 告警转换为价格预估请求
+func parseTradingViewAlert(alert *TradingViewAlert) (*PriceEstimateRequest, error) {
+	symbol, err := resolveCommandSymbol(normalizeTradingViewTicker(alert.Symbol))
+	if err != nil {
+		return nil, err
+	}
+
+	var side string
+	switch strings.ToLower(strings.TrimSpace(alert.Side)) {
+	case types.PositionSideLong, "buy":
+		side = types.PositionSideLong
+	case types.PositionSideShort, "sell":
+		side = types.PositionSideShort
+	default:
+		return nil, fmt.Errorf("不支持的方向: %s", alert.Side)
+	}
+
+	action := strings.ToLower(strings.TrimSpace(alert.Action))
+	if action == "" {
+		action = models.ActionTypeOpen
+	}
+
+	trigger := strings.ToLower(strings.TrimSpace(alert.Trigger))
+	if trigger == "" {
+		trigger = models.TriggerTypeImmediate
+		if alert.Price > 0 {
+			trigger = models.TriggerTypeCondition
+		}
+	}
+
+	req := &PriceEstimateRequest{
+		Symbol:      symbol,
+		Exchange:    alert.Exchange,
+		Side:        side,
+		ActionType:  action,
+		TriggerType: trigger,
+		OrderType:   types.OrderTypeMarket,
+		StakeAmount: alert.StakeAmount,
+		Percentage:  alert.Percentage,
+		Amount:      alert.Amount,
+		Leverage:    alert.Leverage,
+	}
+	if alert.Tag != "" {
+		req.Tag = alert.Tag
+	}
+
+	if trigger == models.TriggerTypeCondition {
+		if alert.Price <= 0 {
+			return nil, fmt.Errorf("条件触发需要提供价格")
+		}
+		req.TargetPrice = alert.Price
+		req.OrderType = types.OrderTypeLimit
+	}
+
+	return req, nil
+}
+
+// normalizeTradingViewTicker 去掉 TradingView 代码中的交易所前缀和永续合约后缀，如 BINANCE:BTCUSDT.P -> BTCUSDT
+func normalizeTradingViewTicker(ticker string) string {
+	ticker = strings.TrimSpace(ticker)
+	if idx := strings.LastIndex(ticker, ":"); idx >= 0 {
+		ticker = ticker[idx+1:]
+	}
+	return strings.TrimSuffix(strings.ToUpper(ticker), ".P")
+}
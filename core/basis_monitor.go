@@ -0,0 +1,114 @@
+package core
+
+import (
+	"math"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/websocket"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AlertTypeBasis 基差告警类型
+const AlertTypeBasis = "basis"
+
+// BasisMonitor 基差（标记价格 - 指数价格）监控器
+type BasisMonitor struct {
+	threshold float64
+	cooldown  time.Duration
+	retention time.Duration
+
+	mu        sync.Mutex
+	lastAlert map[string]time.Time // exchange:symbol -> 最后告警时间
+}
+
+var (
+	GlobalBasisMonitor *BasisMonitor
+	basisMonitorOnce   sync.Once
+)
+
+// GetBasisMonitor 获取全局基差监控器
+func GetBasisMonitor() *BasisMonitor {
+	basisMonitorOnce.Do(func() {
+		GlobalBasisMonitor = &BasisMonitor{
+			threshold: config.GlobalConfig.BasisAlertThreshold,
+			cooldown:  config.GlobalConfig.BasisAlertCooldown,
+			retention: config.GlobalConfig.BasisHistoryRetention,
+			lastAlert: make(map[string]time.Time),
+		}
+	})
+	return GlobalBasisMonitor
+}
+
+// Record 记录一次基差并检查是否需要告警
+func (bm *BasisMonitor) Record(store *redis.Client, exchange string, markPrice *types.WatchMarkPrice) {
+	if markPrice.IndexPrice <= 0 || markPrice.MarkPrice <= 0 {
+		return
+	}
+
+	basis := markPrice.MarkPrice - markPrice.IndexPrice
+	point := &models.BasisPoint{
+		Symbol:     markPrice.Symbol,
+		Exchange:   exchange,
+		MarkPrice:  markPrice.MarkPrice,
+		IndexPrice: markPrice.IndexPrice,
+		Basis:      basis,
+		BasisRate:  basis / markPrice.IndexPrice,
+		Timestamp:  markPrice.TimeStamp,
+	}
+
+	if err := store.AddBasisPoint(point, bm.retention); err != nil {
+		logrus.Errorf("保存 %s 基差数据失败: %v", markPrice.Symbol, err)
+	}
+
+	bm.checkAlert(point)
+}
+
+// checkAlert 基差率超过阈值时告警
+func (bm *BasisMonitor) checkAlert(point *models.BasisPoint) {
+	if bm.threshold <= 0 || math.Abs(point.BasisRate) < bm.threshold {
+		return
+	}
+
+	key := point.Exchange + ":" + point.Symbol
+	now := time.Now()
+
+	bm.mu.Lock()
+	if last, exists := bm.lastAlert[key]; exists && now.Sub(last) < bm.cooldown {
+		bm.mu.Unlock()
+		return
+	}
+	bm.lastAlert[key] = now
+	bm.mu.Unlock()
+
+	direction := models.BasisDirectionPremium
+	if point.BasisRate < 0 {
+		direction = models.BasisDirectionDiscount
+	}
+
+	alert := &models.BasisAlert{
+		Symbol:     point.Symbol,
+		Exchange:   point.Exchange,
+		Direction:  direction,
+		BasisRate:  point.BasisRate,
+		Threshold:  bm.threshold,
+		MarkPrice:  point.MarkPrice,
+		IndexPrice: point.IndexPrice,
+		Timestamp:  point.Timestamp,
+	}
+
+	logrus.Warnf("基差异常: %s %s %s 基差率 %.4f%% (阈值 %.4f%%)",
+		point.Exchange, point.Symbol, direction, point.BasisRate*100, bm.threshold*100)
+
+	if err := redis.GlobalRedisClient.AddBasisAlert(alert); err != nil {
+		logrus.Errorf("保存基差告警失败: %v", err)
+	}
+
+	if wsManager := websocket.GetGlobalWebSocketManager(); wsManager != nil {
+		wsManager.BroadcastAlert(AlertTypeBasis, alert)
+	}
+}
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 自动选币模式
+const (
+	AutoSelectModePreview = "preview" // 仅生成预览，等待确认
+	AutoSelectModeApply   = "apply"   // 直接写入选中列表
+)
+
+// CoinSelectionRule 自动选币规则
+type CoinSelectionRule struct {
+	TopN           int      `json:"top_n"`            // 按24小时成交额取前N个
+	MinQuoteVolume float64  `json:"min_quote_volume"` // 最低24小时成交额
+	Blacklist      []string `json:"blacklist"`        // 排除的币种MarketID
+}
+
+// CoinSelectionCandidate 自动选币候选
+type CoinSelectionCandidate struct {
+	MarketID    string  `json:"market_id"`
+	BaseAsset   string  `json:"base_asset"`
+	QuoteVolume float64 `json:"quote_volume"`
+	Rank        int     `json:"rank"`
+}
+
+// CoinSelectionPreview 自动选币预览
+type CoinSelectionPreview struct {
+	Rule        CoinSelectionRule         `json:"rule"`
+	Candidates  []*CoinSelectionCandidate `json:"candidates"`
+	GeneratedAt time.Time                 `json:"generated_at"`
+	Applied     bool                      `json:"applied"`
+	AppliedAt   *time.Time                `json:"applied_at,omitempty"`
+}
+
+// CoinAutoSelector 启动自动选币器
+type CoinAutoSelector struct {
+	rule        CoinSelectionRule
+	mode        string
+	onlyIfEmpty bool
+
+	mu          sync.RWMutex
+	lastPreview *CoinSelectionPreview
+}
+
+// NewCoinAutoSelectorFromConfig 从全局配置创建自动选币器
+func NewCoinAutoSelectorFromConfig() *CoinAutoSelector {
+	cfg := config.GlobalConfig
+	mode := strings.ToLower(strings.TrimSpace(cfg.AutoSelectMode))
+	if mode != AutoSelectModeApply {
+		mode = AutoSelectModePreview
+	}
+
+	return &CoinAutoSelector{
+		rule: CoinSelectionRule{
+			TopN:           cfg.AutoSelectTopN,
+			MinQuoteVolume: cfg.AutoSelectMinQuoteVolume,
+			Blacklist:      cfg.AutoSelectBlacklist,
+		},
+		mode:        mode,
+		onlyIfEmpty: cfg.AutoSelectOnlyWhenEmpty,
+	}
+}
+
+// GetRule 获取选币规则
+func (s *CoinAutoSelector) GetRule() CoinSelectionRule {
+	return s.rule
+}
+
+// RunAtStartup 启动时执行自动选币
+func (s *CoinAutoSelector) RunAtStartup() error {
+	if s.onlyIfEmpty {
+		selected, err := redis.GlobalRedisClient.GetSelectedCoinMarketIDs()
+		if err != nil {
+			return fmt.Errorf("获取已选币种失败: %w", err)
+		}
+		if len(selected) > 0 {
+			logrus.Infof("已有 %d 个选中币种，跳过自动选币", len(selected))
+			return nil
+		}
+	}
+
+	preview, err := s.Preview()
+	if err != nil {
+		return err
+	}
+
+	if s.mode == AutoSelectModeApply {
+		_, err = s.Confirm()
+		return err
+	}
+
+	logrus.Infof("自动选币预览已生成，共 %d 个候选币种，等待确认", len(preview.Candidates))
+	return nil
+}
+
+// Preview 根据规则生成选币预览
+func (s *CoinAutoSelector) Preview() (*CoinSelectionPreview, error) {
+	coins, err := redis.GlobalRedisClient.GetAllCoins()
+	if err != nil {
+		return nil, fmt.Errorf("获取币种列表失败: %w", err)
+	}
+	denylist, err := LoadDenylist()
+	if err != nil {
+		return nil, fmt.Errorf("获取禁止交易名单失败: %w", err)
+	}
+
+	preview := &CoinSelectionPreview{
+		Rule:        s.rule,
+		Candidates:  rankCoinsByRule(denylist.FilterCoins(coins), s.rule),
+		GeneratedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.lastPreview = preview
+	s.mu.Unlock()
+
+	return preview, nil
+}
+
+// GetLastPreview 获取最近一次生成的预览
+func (s *CoinAutoSelector) GetLastPreview() *CoinSelectionPreview {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastPreview
+}
+
+// Confirm 将最近一次预览写入选中列表
+func (s *CoinAutoSelector) Confirm() (*CoinSelectionPreview, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastPreview == nil {
+		return nil, fmt.Errorf("没有可确认的选币预览")
+	}
+	if s.lastPreview.Applied {
+		return s.lastPreview, nil
+	}
+
+	var appliedCount int
+	for _, candidate := range s.lastPreview.Candidates {
+		if err := redis.GlobalRedisClient.SetCoinSelection(candidate.MarketID, models.CoinSelectionActive); err != nil {
+			logrus.Errorf("自动选中币种 %s 失败: %v", candidate.MarketID, err)
+			continue
+		}
+		appliedCount++
+	}
+
+	now := time.Now()
+	s.lastPreview.Applied = true
+	s.lastPreview.AppliedAt = &now
+
+	logrus.Infof("自动选币已应用，选中 %d/%d 个币种", appliedCount, len(s.lastPreview.Candidates))
+	return s.lastPreview, nil
+}
+
+// rankCoinsByRule 按24小时成交额排序并应用规则
+func rankCoinsByRule(coins []*models.Coin, rule CoinSelectionRule) []*CoinSelectionCandidate {
+	blacklist := make(map[string]bool, len(rule.Blacklist))
+	for _, symbol := range rule.Blacklist {
+		blacklist[strings.ToUpper(symbol)] = true
+	}
+
+	candidates := make([]*CoinSelectionCandidate, 0, len(coins))
+	for _, coin := range coins {
+		if coin.Status != "" && coin.Status != "active" {
+			continue
+		}
+		if blacklist[strings.ToUpper(coin.MarketID)] || blacklist[strings.ToUpper(coin.BaseAsset)] {
+			continue
+		}
+
+		quoteVolume, err := strconv.ParseFloat(coin.QuoteVolume, 64)
+		if err != nil || quoteVolume < rule.MinQuoteVolume {
+			continue
+		}
+
+		candidates = append(candidates, &CoinSelectionCandidate{
+			MarketID:    coin.MarketID,
+			BaseAsset:   coin.BaseAsset,
+			QuoteVolume: quoteVolume,
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].QuoteVolume > candidates[j].QuoteVolume
+	})
+
+	if rule.TopN > 0 && len(candidates) > rule.TopN {
+		candidates = candidates[:rule.TopN]
+	}
+	for i := range candidates {
+		candidates[i].Rank = i + 1
+	}
+
+	return candidates
+}
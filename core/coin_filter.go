@@ -0,0 +1,60 @@
+package core
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"trading_assistant/models"
+)
+
+// CoinFilter 批量选币筛选条件
+type CoinFilter struct {
+	QuoteAsset     string  `json:"quote_asset"`      // 计价资产，如 USDT
+	MinQuoteVolume float64 `json:"min_quote_volume"` // 最低24小时成交额
+	TopGainers     int     `json:"top_gainers"`      // 按24小时涨幅取前N个，0表示不限
+	TopN           int     `json:"top_n"`            // 按24小时成交额取前N个，0表示不限
+}
+
+// FilterCoins 按条件筛选币种，TopGainers 优先于 TopN 排序
+func FilterCoins(coins []*models.Coin, filter CoinFilter) []*models.Coin {
+	type rankedCoin struct {
+		coin          *models.Coin
+		quoteVolume   float64
+		changePercent float64
+	}
+
+	ranked := make([]rankedCoin, 0, len(coins))
+	for _, coin := range coins {
+		if coin.Status != "" && coin.Status != "active" {
+			continue
+		}
+		if filter.QuoteAsset != "" && !strings.EqualFold(coin.QuoteAsset, filter.QuoteAsset) {
+			continue
+		}
+
+		quoteVolume, _ := strconv.ParseFloat(coin.QuoteVolume, 64)
+		if quoteVolume < filter.MinQuoteVolume {
+			continue
+		}
+		changePercent, _ := strconv.ParseFloat(coin.PriceChangePercent, 64)
+
+		ranked = append(ranked, rankedCoin{coin: coin, quoteVolume: quoteVolume, changePercent: changePercent})
+	}
+
+	limit := filter.TopN
+	if filter.TopGainers > 0 {
+		limit = filter.TopGainers
+		sort.Slice(ranked, func(i, j int) bool { return ranked[i].changePercent > ranked[j].changePercent })
+	} else {
+		sort.Slice(ranked, func(i, j int) bool { return ranked[i].quoteVolume > ranked[j].quoteVolume })
+	}
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	result := make([]*models.Coin, 0, len(ranked))
+	for _, item := range ranked {
+		result = append(result, item.coin)
+	}
+	return result
+}
@@ -0,0 +1,217 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CoinSelectionPolicy 自动选币策略
+// 定时按自动选币规则（成交额前N、黑名单）维护选中币种：补充新进入前N的币种，取消跌出前N的币种。
+// 有持仓或监听中预估的币种不会被取消选中。价格订阅按选中币种自动跟随，白名单在调整后同步。只在主节点运行
+type CoinSelectionPolicy struct {
+	selector *CoinAutoSelector
+	interval time.Duration
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	running bool
+}
+
+var GlobalCoinSelectionPolicy *CoinSelectionPolicy
+
+// InitCoinSelectionPolicy 初始化自动选币策略，未启用时不创建
+func InitCoinSelectionPolicy(selector *CoinAutoSelector) {
+	cfg := config.GlobalConfig
+	if !cfg.AutoSelectPolicyEnabled {
+		return
+	}
+
+	interval := cfg.AutoSelectPolicyInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	GlobalCoinSelectionPolicy = &CoinSelectionPolicy{
+		selector: selector,
+		interval: interval,
+	}
+}
+
+// Start 启动自动选币策略
+func (p *CoinSelectionPolicy) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.running = true
+
+	go p.run(ctx)
+	logrus.Infof("自动选币策略已启动，前 %d 个币种，每 %v 调整一次", p.selector.GetRule().TopN, p.interval)
+}
+
+// Stop 停止自动选币策略
+func (p *CoinSelectionPolicy) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.running {
+		return
+	}
+	p.cancel()
+	p.running = false
+	logrus.Info("自动选币策略已停止")
+}
+
+// run 主运行循环，启动后立即调整一次
+func (p *CoinSelectionPolicy) run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Rebalance(false); err != nil {
+			logrus.Errorf("自动选币调整失败: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rebalance 按规则调整选中币种，dryRun 时只计算差异
+func (p *CoinSelectionPolicy) Rebalance(dryRun bool) (*models.CoinSelectionChange, error) {
+	preview, err := p.selector.Preview()
+	if err != nil {
+		return nil, err
+	}
+	selected, err := redis.GlobalRedisClient.GetSelectedCoinMarketIDs()
+	if err != nil {
+		return nil, fmt.Errorf("获取选中币种失败: %w", err)
+	}
+	protected, err := protectedSelectionSymbols()
+	if err != nil {
+		return nil, err
+	}
+
+	target := make(map[string]bool, len(preview.Candidates))
+	for _, candidate := range preview.Candidates {
+		target[candidate.MarketID] = true
+	}
+	current := make(map[string]bool, len(selected))
+	for _, marketID := range selected {
+		current[marketID] = true
+	}
+
+	change := &models.CoinSelectionChange{
+		TopN:      preview.Rule.TopN,
+		Added:     []string{},
+		Removed:   []string{},
+		Kept:      []string{},
+		DryRun:    dryRun,
+		ChangedAt: time.Now(),
+	}
+	for _, candidate := range preview.Candidates {
+		if !current[candidate.MarketID] {
+			change.Added = append(change.Added, candidate.MarketID)
+		}
+	}
+	for _, marketID := range selected {
+		if target[marketID] {
+			continue
+		}
+		if protected[marketID] {
+			change.Kept = append(change.Kept, marketID)
+		} else {
+			change.Removed = append(change.Removed, marketID)
+		}
+	}
+	sort.Strings(change.Removed)
+	sort.Strings(change.Kept)
+	change.Selected = len(selected) + len(change.Added) - len(change.Removed)
+
+	if dryRun || len(change.Added)+len(change.Removed) == 0 {
+		return change, nil
+	}
+
+	for _, marketID := range change.Added {
+		if err := redis.GlobalRedisClient.SetCoinSelection(marketID, models.CoinSelectionActive); err != nil {
+			logrus.Errorf("自动选中币种 %s 失败: %v", marketID, err)
+		}
+	}
+	for _, marketID := range change.Removed {
+		if err := redis.GlobalRedisClient.SetCoinSelection(marketID, models.CoinSelectionInactive); err != nil {
+			logrus.Errorf("自动取消选中币种 %s 失败: %v", marketID, err)
+		}
+	}
+	GlobalWhitelistSyncer.SyncAsync()
+
+	if err := redis.GlobalRedisClient.AddCoinSelectionChange(change); err != nil {
+		logrus.Errorf("保存选币调整记录失败: %v", err)
+	}
+	p.notifyChange(change)
+	return change, nil
+}
+
+// notifyChange 发送选中币种调整通知
+func (p *CoinSelectionPolicy) notifyChange(change *models.CoinSelectionChange) {
+	var lines []string
+	if len(change.Added) > 0 {
+		lines = append(lines, fmt.Sprintf("新增 %d: %s", len(change.Added), strings.Join(change.Added, ", ")))
+	}
+	if len(change.Removed) > 0 {
+		lines = append(lines, fmt.Sprintf("移除 %d: %s", len(change.Removed), strings.Join(change.Removed, ", ")))
+	}
+	if len(change.Kept) > 0 {
+		lines = append(lines, fmt.Sprintf("保留（有持仓或预估） %d: %s", len(change.Kept), strings.Join(change.Kept, ", ")))
+	}
+	lines = append(lines, fmt.Sprintf("当前选中 %d 个币种", change.Selected))
+	message := strings.Join(lines, "\n")
+	logrus.Infof("自动选币调整: %s", strings.ReplaceAll(message, "\n", "; "))
+
+	notify.Send(notify.EventSelection, i18n.T("notify.selection.title"), message, map[string]interface{}{
+		"added":    change.Added,
+		"removed":  change.Removed,
+		"kept":     change.Kept,
+		"selected": change.Selected,
+	})
+}
+
+// protectedSelectionSymbols 有持仓或监听中预估的币种，取消选中会导致无法继续监控
+func protectedSelectionSymbols() (map[string]bool, error) {
+	protected := make(map[string]bool)
+
+	positions, err := redis.GlobalRedisClient.GetAllPositions()
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+	for _, position := range positions {
+		if position.Size > 0 {
+			protected[position.Symbol] = true
+		}
+	}
+
+	estimates, err := redis.GlobalRedisClient.GetActiveEstimates()
+	if err != nil {
+		return nil, fmt.Errorf("获取监听中的预估失败: %w", err)
+	}
+	for _, estimate := range estimates {
+		protected[estimate.Symbol] = true
+	}
+	return protected, nil
+}
@@ -0,0 +1,157 @@
+package core
+
+import (
+	"math"
+	"sync"
+	"time"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/metrics"
+	"trading_assistant/pkg/notify"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ConnectionMonitor 交易所推送连接延迟和时钟偏差监控
+// 定时读取各推送连接的ping往返延迟和事件时间延迟，导出监控指标；
+// 延迟或时钟偏差超过阈值时发送一次告警，恢复后发送恢复通知。使用1秒级价格的触发条件对两者都很敏感
+type ConnectionMonitor struct {
+	interval     time.Duration
+	latencyAlert time.Duration
+	skewAlert    time.Duration
+	stopChan     chan struct{}
+	mu           sync.Mutex
+	alerted      map[string]bool // 连接ID -> 是否处于告警状态
+	known        map[string]bool // 上次检查时存在的连接ID
+}
+
+var GlobalConnectionMonitor *ConnectionMonitor
+
+// InitConnectionMonitor 初始化推送连接监控，检查间隔为0时不创建
+func InitConnectionMonitor() {
+	cfg := config.GlobalConfig
+	if cfg.ConnectionCheckInterval <= 0 {
+		return
+	}
+	GlobalConnectionMonitor = &ConnectionMonitor{
+		interval:     cfg.ConnectionCheckInterval,
+		latencyAlert: cfg.ConnectionLatencyAlert,
+		skewAlert:    cfg.ConnectionSkewAlert,
+		alerted:      make(map[string]bool),
+		known:        make(map[string]bool),
+	}
+}
+
+// Start 启动定时检查
+func (cm *ConnectionMonitor) Start() {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.stopChan != nil {
+		return
+	}
+
+	cm.stopChan = make(chan struct{})
+	go cm.loop(cm.stopChan)
+	logrus.Infof("推送连接延迟监控已启动，检查间隔: %v, 延迟阈值: %v, 时钟偏差阈值: %v", cm.interval, cm.latencyAlert, cm.skewAlert)
+}
+
+// Stop 停止定时检查
+func (cm *ConnectionMonitor) Stop() {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.stopChan == nil {
+		return
+	}
+	close(cm.stopChan)
+	cm.stopChan = nil
+}
+
+// loop 定时检查所有推送连接
+func (cm *ConnectionMonitor) loop(stopChan chan struct{}) {
+	ticker := time.NewTicker(cm.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			cm.Check()
+		}
+	}
+}
+
+// Check 导出各连接的延迟指标，并按阈值发送告警和恢复通知
+func (cm *ConnectionMonitor) Check() {
+	connections := exchanges.GetStreamConnectionStats()
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	// 已注销的连接删除对应指标和告警状态
+	current := make(map[string]bool, len(connections))
+	for _, conn := range connections {
+		current[conn.ID] = true
+	}
+	for id := range cm.known {
+		if !current[id] {
+			cm.deleteMetrics(id)
+			delete(cm.alerted, id)
+		}
+	}
+	cm.known = current
+
+	for _, conn := range connections {
+		if !conn.Connected || conn.PingAt == nil {
+			continue
+		}
+		metrics.ExchangeStreamPingRTT.WithLabelValues(conn.Exchange, conn.ID).Set(conn.PingRTTMs / 1000)
+		metrics.ExchangeStreamEventLag.WithLabelValues(conn.Exchange, conn.ID).Set(conn.EventLagMs / 1000)
+		metrics.ExchangeStreamClockSkew.WithLabelValues(conn.Exchange, conn.ID).Set(conn.ClockSkewMs / 1000)
+
+		exceeded := cm.exceeds(conn)
+		switch {
+		case exceeded && !cm.alerted[conn.ID]:
+			cm.alerted[conn.ID] = true
+			logrus.Warnf("%s 推送连接 %s 延迟过高，ping往返 %.0fms，时钟偏差 %.0fms", conn.Exchange, conn.ID, conn.PingRTTMs, conn.ClockSkewMs)
+			notify.Send(notify.EventConnection, i18n.T("notify.conn_slow.title"),
+				i18n.T("notify.conn_slow.message", conn.Exchange, conn.ID, conn.PingRTTMs, conn.ClockSkewMs),
+				connectionNotifyData(conn))
+		case !exceeded && cm.alerted[conn.ID]:
+			delete(cm.alerted, conn.ID)
+			logrus.Infof("%s 推送连接 %s 延迟已恢复", conn.Exchange, conn.ID)
+			notify.Send(notify.EventConnection, i18n.T("notify.conn_recovered.title"),
+				i18n.T("notify.conn_recovered.message", conn.Exchange, conn.ID, conn.PingRTTMs, conn.ClockSkewMs),
+				connectionNotifyData(conn))
+		}
+	}
+}
+
+// exceeds ping往返延迟或时钟偏差是否超过阈值
+func (cm *ConnectionMonitor) exceeds(conn exchanges.StreamConnectionStats) bool {
+	if cm.latencyAlert > 0 && conn.PingRTTMs > float64(cm.latencyAlert.Milliseconds()) {
+		return true
+	}
+	return cm.skewAlert > 0 && math.Abs(conn.ClockSkewMs) > float64(cm.skewAlert.Milliseconds())
+}
+
+// deleteMetrics 删除已注销连接的指标
+func (cm *ConnectionMonitor) deleteMetrics(id string) {
+	labels := map[string]string{"connection": id}
+	metrics.ExchangeStreamPingRTT.DeletePartialMatch(labels)
+	metrics.ExchangeStreamEventLag.DeletePartialMatch(labels)
+	metrics.ExchangeStreamClockSkew.DeletePartialMatch(labels)
+}
+
+// connectionNotifyData 通知附带的连接数据
+func connectionNotifyData(conn exchanges.StreamConnectionStats) map[string]interface{} {
+	return map[string]interface{}{
+		"exchange":      conn.Exchange,
+		"connection":    conn.ID,
+		"feature":       conn.Feature,
+		"ping_rtt_ms":   conn.PingRTTMs,
+		"event_lag_ms":  conn.EventLagMs,
+		"clock_skew_ms": conn.ClockSkewMs,
+	}
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// 跨币种条件的逻辑连接符
+const (
+	conditionLogicAnd = "AND"
+	conditionLogicOr  = "OR"
+)
+
+var (
+	// conditionClausePattern 单个子句：交易对 比较符 价格，如 BTCUSDT > 70000
+	conditionClausePattern = regexp.MustCompile(`^([A-Za-z0-9_/:\-]+)\s*(>=|<=|>|<)\s*([0-9]+(?:\.[0-9]+)?)$`)
+	// conditionLogicPattern 子句之间的连接符
+	conditionLogicPattern = regexp.MustCompile(`(?i)\s+(AND|OR)\s+`)
+)
+
+// conditionClause 跨币种条件的单个子句
+type conditionClause struct {
+	Symbol   string
+	Operator string
+	Price    float64
+}
+
+// match 判断价格是否满足子句
+func (c conditionClause) match(price float64) bool {
+	switch c.Operator {
+	case ">":
+		return price > c.Price
+	case ">=":
+		return price >= c.Price
+	case "<":
+		return price < c.Price
+	case "<=":
+		return price <= c.Price
+	default:
+		return false
+	}
+}
+
+// CrossCondition 跨币种触发条件，最多两个子句，以 AND 或 OR 连接
+type CrossCondition struct {
+	clauses []conditionClause
+	logic   string
+}
+
+// ParseCrossCondition 解析跨币种条件表达式，如 "BTCUSDT > 70000" 或 "BTCUSDT > 70000 AND ETHUSDT < 3000"
+// 交易对按 exchange 解析为MarketID
+func ParseCrossCondition(exchange, expr string) (*CrossCondition, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("条件表达式为空")
+	}
+
+	parts := conditionLogicPattern.Split(expr, -1)
+	logics := conditionLogicPattern.FindAllStringSubmatch(expr, -1)
+	if len(parts) > 2 {
+		return nil, fmt.Errorf("条件表达式最多支持两个子句")
+	}
+
+	condition := &CrossCondition{logic: conditionLogicAnd}
+	if len(logics) == 1 {
+		condition.logic = strings.ToUpper(logics[0][1])
+	}
+	for _, part := range parts {
+		match := conditionClausePattern.FindStringSubmatch(strings.TrimSpace(part))
+		if match == nil {
+			return nil, fmt.Errorf("无法解析条件子句 %q，格式应为 交易对 比较符(>, >=, <, <=) 价格", part)
+		}
+		price, err := strconv.ParseFloat(match[3], 64)
+		if err != nil || price <= 0 {
+			return nil, fmt.Errorf("条件子句 %q 的价格无效", part)
+		}
+		condition.clauses = append(condition.clauses, conditionClause{
+			Symbol:   ResolveMarketID(exchange, match[1]),
+			Operator: match[2],
+			Price:    price,
+		})
+	}
+	return condition, nil
+}
+
+// Symbols 条件引用的交易对
+func (c *CrossCondition) Symbols() []string {
+	symbols := make([]string, 0, len(c.clauses))
+	for _, clause := range c.clauses {
+		symbols = append(symbols, clause.Symbol)
+	}
+	return symbols
+}
+
+// String 规范化后的条件表达式
+func (c *CrossCondition) String() string {
+	clauses := make([]string, 0, len(c.clauses))
+	for _, clause := range c.clauses {
+		clauses = append(clauses, fmt.Sprintf("%s %s %s", clause.Symbol, clause.Operator, strconv.FormatFloat(clause.Price, 'f', -1, 64)))
+	}
+	return strings.Join(clauses, " "+c.logic+" ")
+}
+
+// Evaluate 按各交易对的标记价格判断条件是否满足，价格缺失时返回错误
+func (c *CrossCondition) Evaluate(prices map[string]*types.WatchMarkPrice) (bool, error) {
+	result := c.logic == conditionLogicAnd
+	for _, clause := range c.clauses {
+		markPrice, ok := prices[clause.Symbol]
+		if !ok || markPrice == nil || markPrice.MarkPrice <= 0 {
+			return false, fmt.Errorf("未找到 %s 的价格数据", clause.Symbol)
+		}
+		matched := clause.match(markPrice.MarkPrice)
+		if c.logic == conditionLogicAnd {
+			result = result && matched
+		} else {
+			result = result || matched
+		}
+	}
+	return result, nil
+}
+
+// evaluateCrossCondition 评估预估的跨币种条件，引用的价格缺失或过期时记录跳过并视为未满足
+func (pm *PriceMonitor) evaluateCrossCondition(estimate *models.PriceEstimate, now time.Time) bool {
+	condition, err := ParseCrossCondition(estimate.Exchange, estimate.Condition)
+	if err != nil {
+		pm.recordSkip(estimate, models.SkipReasonInvalidPrice, "跨币种条件无效: %v", err)
+		return false
+	}
+
+	prices, err := ExchangeStore(estimate.Exchange).GetMarkPrices(condition.Symbols())
+	if err != nil {
+		pm.recordSkip(estimate, models.SkipReasonStalePrice, "%v", err)
+		return false
+	}
+	for symbol, markPrice := range prices {
+		if isStalePrice(markPrice, config.GlobalConfig.MonitorStalePriceThreshold, now) {
+			pm.recordSkip(estimate, models.SkipReasonStalePrice, "条件引用的 %s 价格数据已过期", symbol)
+			return false
+		}
+	}
+
+	matched, err := condition.Evaluate(prices)
+	if err != nil {
+		pm.recordSkip(estimate, models.SkipReasonStalePrice, "%v", err)
+		return false
+	}
+	return matched
+}
@@ -0,0 +1,54 @@
+package core
+
+import "testing"
+
+func TestParseCrossCondition(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    string
+		symbols []string
+		wantErr bool
+	}{
+		{name: "单个子句", expr: "BTCUSDT > 70000", want: "BTCUSDT > 70000", symbols: []string{"BTCUSDT"}},
+		{name: "无空格", expr: "ETHUSDT<=3000.5", want: "ETHUSDT <= 3000.5", symbols: []string{"ETHUSDT"}},
+		{name: "AND连接", expr: "BTCUSDT >= 70000 AND ETHUSDT < 3000", want: "BTCUSDT >= 70000 AND ETHUSDT < 3000", symbols: []string{"BTCUSDT", "ETHUSDT"}},
+		{name: "小写or连接", expr: "  BTCUSDT < 60000 or ETHUSDT > 4000  ", want: "BTCUSDT < 60000 OR ETHUSDT > 4000", symbols: []string{"BTCUSDT", "ETHUSDT"}},
+		{name: "空表达式", expr: "", wantErr: true},
+		{name: "只有空白", expr: "   ", wantErr: true},
+		{name: "不支持的比较符", expr: "BTCUSDT == 70000", wantErr: true},
+		{name: "不等号", expr: "BTCUSDT != 70000", wantErr: true},
+		{name: "缺少价格", expr: "BTCUSDT >", wantErr: true},
+		{name: "价格为零", expr: "BTCUSDT > 0", wantErr: true},
+		{name: "负数价格", expr: "BTCUSDT > -1", wantErr: true},
+		{name: "超过两个子句", expr: "BTCUSDT > 1 AND ETHUSDT > 1 AND SOLUSDT > 1", wantErr: true},
+		{name: "连接符缺少子句", expr: "BTCUSDT > 70000 AND ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := ParseCrossCondition("binance", tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望解析失败，实际得到 %q", condition.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			if got := condition.String(); got != tt.want {
+				t.Errorf("规范化表达式 = %q，期望 %q", got, tt.want)
+			}
+			symbols := condition.Symbols()
+			if len(symbols) != len(tt.symbols) {
+				t.Fatalf("交易对 = %v，期望 %v", symbols, tt.symbols)
+			}
+			for i := range symbols {
+				if symbols[i] != tt.symbols[i] {
+					t.Errorf("交易对 = %v，期望 %v", symbols, tt.symbols)
+				}
+			}
+		})
+	}
+}
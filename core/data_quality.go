@@ -0,0 +1,248 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/metrics"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/websocket"
+)
+
+// 数据质量事件类型
+const (
+	qualityEventFetchSuccess   = "fetch_success"
+	qualityEventFetchError     = "fetch_error"
+	qualityEventGap            = "gap"
+	qualityEventReconnect      = "reconnect"
+	qualityEventPriceAccepted  = "price_accepted"
+	qualityEventPriceRejected  = "price_rejected"
+	defaultQualityWindow       = time.Hour
+	qualityGapPenalty          = 5.0  // 每次数据断档扣分
+	qualityReconnectPenalty    = 5.0  // 每次重连扣分
+	qualityMaxGapPenalty       = 20.0 // 断档最大扣分
+	qualityMaxReconnectPenalty = 20.0 // 重连最大扣分
+	qualityErrorRateWeight     = 40.0 // REST错误率权重
+	qualityRejectRateWeight    = 20.0 // 价格校验拒绝率权重
+)
+
+// qualityEvent 数据质量事件
+type qualityEvent struct {
+	at   time.Time
+	kind string
+}
+
+// exchangeQuality 单个交易所的数据质量统计
+type exchangeQuality struct {
+	events      []qualityEvent
+	lastSuccess time.Time
+}
+
+// DataQualityScore 交易所数据质量评分
+type DataQualityScore struct {
+	Exchange      string  `json:"exchange"`
+	Score         float64 `json:"score"`           // 0-100，越高越好
+	FetchSuccess  int     `json:"fetch_success"`   // 窗口内REST成功次数
+	FetchErrors   int     `json:"fetch_errors"`    // 窗口内REST失败次数
+	ErrorRate     float64 `json:"error_rate"`      // REST错误率
+	Gaps          int     `json:"gaps"`            // 窗口内数据断档次数
+	Reconnects    int     `json:"reconnects"`      // 窗口内重连次数
+	PriceAccepted int     `json:"price_accepted"`  // 通过校验的价格数
+	PriceRejected int     `json:"price_rejected"`  // 被校验拒绝的价格数
+	RejectRate    float64 `json:"reject_rate"`     // 价格校验拒绝率
+	LastSuccessAt int64   `json:"last_success_at"` // 最后一次成功获取时间（毫秒）
+	WindowSeconds int64   `json:"window_seconds"`  // 统计窗口
+	UpdatedAt     int64   `json:"updated_at"`      // 评分计算时间（毫秒）
+}
+
+// DataQualityTracker 交易所数据质量跟踪器
+type DataQualityTracker struct {
+	mu        sync.Mutex
+	window    time.Duration
+	exchanges map[string]*exchangeQuality
+}
+
+var (
+	GlobalDataQualityTracker *DataQualityTracker
+	dataQualityOnce          sync.Once
+)
+
+// NewDataQualityTracker 创建数据质量跟踪器
+func NewDataQualityTracker(window time.Duration) *DataQualityTracker {
+	if window <= 0 {
+		window = defaultQualityWindow
+	}
+	return &DataQualityTracker{
+		window:    window,
+		exchanges: make(map[string]*exchangeQuality),
+	}
+}
+
+// GetDataQualityTracker 获取全局数据质量跟踪器
+func GetDataQualityTracker() *DataQualityTracker {
+	dataQualityOnce.Do(func() {
+		GlobalDataQualityTracker = NewDataQualityTracker(defaultQualityWindow)
+	})
+	return GlobalDataQualityTracker
+}
+
+// RecordFetch 记录一次REST获取结果，expectedInterval 用于判断数据断档
+func (t *DataQualityTracker) RecordFetch(exchange string, err error, expectedInterval time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	eq := t.getExchange(exchange)
+	now := time.Now()
+	if err != nil {
+		t.addEvent(eq, now, qualityEventFetchError)
+		return
+	}
+
+	// 两次成功获取间隔超过预期两倍视为断档
+	if !eq.lastSuccess.IsZero() && expectedInterval > 0 && now.Sub(eq.lastSuccess) > 2*expectedInterval {
+		t.addEvent(eq, now, qualityEventGap)
+	}
+	eq.lastSuccess = now
+	t.addEvent(eq, now, qualityEventFetchSuccess)
+}
+
+// RecordReconnect 记录一次数据流重连
+func (t *DataQualityTracker) RecordReconnect(exchange string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.addEvent(t.getExchange(exchange), time.Now(), qualityEventReconnect)
+	metrics.ExchangeReconnects.WithLabelValues(exchange).Inc()
+	notify.Send(notify.EventReconnect, i18n.T("notify.reconnect.title"), i18n.T("notify.reconnect.message", exchange), nil)
+}
+
+// RecordPriceCheck 记录价格校验结果
+func (t *DataQualityTracker) RecordPriceCheck(exchange string, accepted bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	kind := qualityEventPriceRejected
+	if accepted {
+		kind = qualityEventPriceAccepted
+	}
+	t.addEvent(t.getExchange(exchange), time.Now(), kind)
+}
+
+// GetScore 获取单个交易所的数据质量评分
+func (t *DataQualityTracker) GetScore(exchange string) (*DataQualityScore, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	eq, exists := t.exchanges[exchange]
+	if !exists {
+		return nil, false
+	}
+	return t.computeScore(exchange, eq), true
+}
+
+// GetScores 获取所有交易所的数据质量评分，按评分从高到低排序
+func (t *DataQualityTracker) GetScores() []*DataQualityScore {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	scores := make([]*DataQualityScore, 0, len(t.exchanges))
+	for exchange, eq := range t.exchanges {
+		scores = append(scores, t.computeScore(exchange, eq))
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score == scores[j].Score {
+			return scores[i].Exchange < scores[j].Exchange
+		}
+		return scores[i].Score > scores[j].Score
+	})
+	return scores
+}
+
+// Broadcast 通过WebSocket推送最新评分
+func (t *DataQualityTracker) Broadcast() {
+	wsManager := websocket.GetGlobalWebSocketManager()
+	if wsManager == nil {
+		return
+	}
+	wsManager.BroadcastQuality(t.GetScores())
+}
+
+// getExchange 获取或创建交易所统计（调用方需持有锁）
+func (t *DataQualityTracker) getExchange(exchange string) *exchangeQuality {
+	eq, exists := t.exchanges[exchange]
+	if !exists {
+		eq = &exchangeQuality{}
+		t.exchanges[exchange] = eq
+	}
+	return eq
+}
+
+// addEvent 添加事件并清理窗口外的旧事件（调用方需持有锁）
+func (t *DataQualityTracker) addEvent(eq *exchangeQuality, at time.Time, kind string) {
+	eq.events = append(eq.events, qualityEvent{at: at, kind: kind})
+	t.prune(eq, at)
+}
+
+// prune 清理窗口外的事件（调用方需持有锁）
+func (t *DataQualityTracker) prune(eq *exchangeQuality, now time.Time) {
+	cutoff := now.Add(-t.window)
+	idx := 0
+	for idx < len(eq.events) && eq.events[idx].at.Before(cutoff) {
+		idx++
+	}
+	if idx > 0 {
+		eq.events = append(eq.events[:0], eq.events[idx:]...)
+	}
+}
+
+// computeScore 计算数据质量评分（调用方需持有锁）
+func (t *DataQualityTracker) computeScore(exchange string, eq *exchangeQuality) *DataQualityScore {
+	now := time.Now()
+	t.prune(eq, now)
+
+	score := &DataQualityScore{
+		Exchange:      exchange,
+		WindowSeconds: int64(t.window.Seconds()),
+		UpdatedAt:     now.UnixMilli(),
+	}
+	if !eq.lastSuccess.IsZero() {
+		score.LastSuccessAt = eq.lastSuccess.UnixMilli()
+	}
+
+	for _, event := range eq.events {
+		switch event.kind {
+		case qualityEventFetchSuccess:
+			score.FetchSuccess++
+		case qualityEventFetchError:
+			score.FetchErrors++
+		case qualityEventGap:
+			score.Gaps++
+		case qualityEventReconnect:
+			score.Reconnects++
+		case qualityEventPriceAccepted:
+			score.PriceAccepted++
+		case qualityEventPriceRejected:
+			score.PriceRejected++
+		}
+	}
+
+	if total := score.FetchSuccess + score.FetchErrors; total > 0 {
+		score.ErrorRate = float64(score.FetchErrors) / float64(total)
+	}
+	if total := score.PriceAccepted + score.PriceRejected; total > 0 {
+		score.RejectRate = float64(score.PriceRejected) / float64(total)
+	}
+
+	value := 100.0
+	value -= score.ErrorRate * qualityErrorRateWeight
+	value -= score.RejectRate * qualityRejectRateWeight
+	value -= min(float64(score.Gaps)*qualityGapPenalty, qualityMaxGapPenalty)
+	value -= min(float64(score.Reconnects)*qualityReconnectPenalty, qualityMaxReconnectPenalty)
+	if value < 0 {
+		value = 0
+	}
+	score.Score = value
+
+	return score
+}
@@ -0,0 +1,172 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrSymbolDenied 币种在禁止交易名单中
+var ErrSymbolDenied = errors.New("币种在禁止交易名单中")
+
+// Denylist 禁止交易名单快照，检查多个币种时只读取一次Redis
+type Denylist struct {
+	entries []*models.DenylistEntry
+}
+
+// LoadDenylist 读取当前的禁止交易名单
+func LoadDenylist() (*Denylist, error) {
+	entries, err := redis.GlobalRedisClient.GetDenylist()
+	if err != nil {
+		return nil, err
+	}
+	return &Denylist{entries: entries}, nil
+}
+
+// Match 返回命中的名单条目，未命中时返回nil；baseAsset 为空时只按 MarketID 匹配
+func (d *Denylist) Match(marketID, baseAsset string) *models.DenylistEntry {
+	marketID = strings.ToUpper(marketID)
+	baseAsset = strings.ToUpper(baseAsset)
+	for _, entry := range d.entries {
+		switch entry.Type {
+		case models.DenylistTypeSymbol:
+			if marketID == entry.Value {
+				return entry
+			}
+		case models.DenylistTypeBase:
+			if baseAsset != "" && baseAsset == entry.Value {
+				return entry
+			}
+		case models.DenylistTypePattern:
+			if matched, _ := path.Match(entry.Value, marketID); matched {
+				return entry
+			}
+		}
+	}
+	return nil
+}
+
+// MatchCoin 检查币种是否命中名单
+func (d *Denylist) MatchCoin(coin *models.Coin) *models.DenylistEntry {
+	return d.Match(coin.MarketID, coin.BaseAsset)
+}
+
+// MatchMarketID 检查 MarketID 是否命中名单，基础资产从币种数据或符号转换表获取
+func (d *Denylist) MatchMarketID(marketID string) *models.DenylistEntry {
+	return d.Match(marketID, baseAssetOf(marketID))
+}
+
+// FilterCoins 去掉命中名单的币种
+func (d *Denylist) FilterCoins(coins []*models.Coin) []*models.Coin {
+	allowed := make([]*models.Coin, 0, len(coins))
+	for _, coin := range coins {
+		if d.MatchCoin(coin) == nil {
+			allowed = append(allowed, coin)
+		}
+	}
+	return allowed
+}
+
+// FilterMarketIDs 去掉命中名单的 MarketID
+func (d *Denylist) FilterMarketIDs(marketIDs []string) []string {
+	allowed := make([]string, 0, len(marketIDs))
+	for _, marketID := range marketIDs {
+		if d.MatchMarketID(marketID) == nil {
+			allowed = append(allowed, marketID)
+		}
+	}
+	return allowed
+}
+
+// CheckSymbolAllowed 币种命中禁止交易名单时返回 ErrSymbolDenied，名单读取失败时按禁止处理
+func CheckSymbolAllowed(marketID string) error {
+	denylist, err := LoadDenylist()
+	if err != nil {
+		return fmt.Errorf("无法读取禁止交易名单: %w", err)
+	}
+	if entry := denylist.MatchMarketID(marketID); entry != nil {
+		return deniedError(marketID, entry)
+	}
+	return nil
+}
+
+// deniedError 生成包含命中条目的禁止交易错误
+func deniedError(marketID string, entry *models.DenylistEntry) error {
+	if entry.Reason != "" {
+		return fmt.Errorf("%w: %s (%s:%s，%s)", ErrSymbolDenied, marketID, entry.Type, entry.Value, entry.Reason)
+	}
+	return fmt.Errorf("%w: %s (%s:%s)", ErrSymbolDenied, marketID, entry.Type, entry.Value)
+}
+
+// NormalizeDenylistEntry 校验名单条目并统一为大写
+func NormalizeDenylistEntry(entry *models.DenylistEntry) error {
+	entry.Type = strings.ToLower(strings.TrimSpace(entry.Type))
+	entry.Value = strings.ToUpper(strings.TrimSpace(entry.Value))
+	if entry.Value == "" {
+		return fmt.Errorf("名单值不能为空")
+	}
+
+	switch entry.Type {
+	case models.DenylistTypeSymbol, models.DenylistTypeBase:
+	case models.DenylistTypePattern:
+		if _, err := path.Match(entry.Value, ""); err != nil {
+			return fmt.Errorf("通配符格式错误: %s", entry.Value)
+		}
+	default:
+		return fmt.Errorf("名单类型必须是 %s、%s 或 %s", models.DenylistTypeSymbol, models.DenylistTypeBase, models.DenylistTypePattern)
+	}
+	return nil
+}
+
+// AddDenylistEntry 添加禁止交易名单条目，并取消选中命中的币种，返回被取消选中的币种
+func AddDenylistEntry(entry *models.DenylistEntry) ([]string, error) {
+	if err := NormalizeDenylistEntry(entry); err != nil {
+		return nil, err
+	}
+	entry.CreatedAt = time.Now()
+	if err := redis.GlobalRedisClient.SetDenylistEntry(entry); err != nil {
+		return nil, err
+	}
+	logrus.Infof("禁止交易名单已添加 %s:%s (%s)", entry.Type, entry.Value, entry.CreatedBy)
+
+	selected, err := redis.GlobalRedisClient.GetSelectedCoinMarketIDs()
+	if err != nil {
+		return nil, fmt.Errorf("获取选中币种失败: %w", err)
+	}
+	single := &Denylist{entries: []*models.DenylistEntry{entry}}
+	deselected := make([]string, 0)
+	for _, marketID := range selected {
+		if single.MatchMarketID(marketID) == nil {
+			continue
+		}
+		if err := redis.GlobalRedisClient.SetCoinSelection(marketID, models.CoinSelectionInactive); err != nil {
+			logrus.Errorf("取消选中禁止交易币种 %s 失败: %v", marketID, err)
+			continue
+		}
+		deselected = append(deselected, marketID)
+	}
+	if len(deselected) > 0 {
+		logrus.Infof("已取消选中禁止交易的币种: %s", strings.Join(deselected, ", "))
+		GlobalWhitelistSyncer.SyncAsync()
+	}
+	return deselected, nil
+}
+
+// baseAssetOf 获取 MarketID 的基础资产，币种数据不存在时从统一交易对格式中解析
+func baseAssetOf(marketID string) string {
+	if coin, err := redis.GlobalRedisClient.GetCoin(marketID); err == nil && coin.BaseAsset != "" {
+		return coin.BaseAsset
+	}
+	if pair := SymbolMapper("").Unified(marketID); pair != "" {
+		if base, _, found := strings.Cut(pair, "/"); found {
+			return base
+		}
+	}
+	return ""
+}
@@ -0,0 +1,202 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// paperEquityCurrency 模拟账户的计价货币
+const paperEquityCurrency = "USDT"
+
+// EquityTracker 账户权益快照和回撤告警
+// 当前没有交易所账户接口，实盘权益取各Freqtrade实例 /balance 的总权益之和，模拟交易模式下取模拟账户权益
+type EquityTracker struct {
+	bots             *freqtrade.Registry
+	interval         time.Duration
+	retention        time.Duration
+	drawdownAlertPct float64
+	stopChan         chan struct{}
+
+	mu      sync.Mutex
+	alerted bool // 当前回撤是否已告警，回撤回到阈值以下后重置
+}
+
+var GlobalEquityTracker *EquityTracker
+
+// InitEquityTracker 初始化权益跟踪器
+func InitEquityTracker(bots *freqtrade.Registry) {
+	GlobalEquityTracker = &EquityTracker{
+		bots:             bots,
+		interval:         config.GlobalConfig.EquitySnapshotInterval,
+		retention:        config.GlobalConfig.EquityRetention,
+		drawdownAlertPct: config.GlobalConfig.EquityDrawdownAlertPct,
+	}
+}
+
+// Start 启动权益快照
+func (et *EquityTracker) Start() {
+	if et.interval <= 0 {
+		logrus.Info("权益快照未启用")
+		return
+	}
+	if et.stopChan != nil {
+		return
+	}
+
+	et.stopChan = make(chan struct{})
+	go et.loop(et.stopChan)
+	logrus.Infof("权益快照已启动，间隔: %v, 回撤告警阈值: %.2f%%", et.interval, et.drawdownAlertPct)
+}
+
+// Stop 停止权益快照
+func (et *EquityTracker) Stop() {
+	if et.stopChan == nil {
+		return
+	}
+	close(et.stopChan)
+	et.stopChan = nil
+}
+
+// loop 定时记录权益快照
+func (et *EquityTracker) loop(stopChan chan struct{}) {
+	ticker := time.NewTicker(et.interval)
+	defer ticker.Stop()
+
+	et.snapshotAndLog()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			et.snapshotAndLog()
+		}
+	}
+}
+
+// snapshotAndLog 记录快照，失败时只记录日志
+func (et *EquityTracker) snapshotAndLog() {
+	if _, err := et.Snapshot(context.Background()); err != nil {
+		logrus.Warnf("记录权益快照失败: %v", err)
+	}
+}
+
+// Source 当前权益来源，与盈亏账本来源一致
+func (et *EquityTracker) Source() string {
+	if config.GlobalConfig.DryRun {
+		return models.PnLSourcePaper
+	}
+	return models.PnLSourceFreqtrade
+}
+
+// Snapshot 读取当前权益，计算距峰值回撤并保存快照
+func (et *EquityTracker) Snapshot(ctx context.Context) (*models.EquitySnapshot, error) {
+	snapshot := &models.EquitySnapshot{
+		Source:    et.Source(),
+		Timestamp: time.Now().UnixMilli(),
+	}
+
+	if snapshot.Source == models.PnLSourcePaper {
+		summary, err := GetPaperEngine().GetSummary()
+		if err != nil {
+			return nil, fmt.Errorf("获取模拟账户失败: %w", err)
+		}
+		snapshot.Equity = summary.Equity
+		snapshot.Currency = paperEquityCurrency
+	} else {
+		if err := et.loadFreqtradeEquity(ctx, snapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	previous, err := redis.GlobalRedisClient.GetLatestEquitySnapshot(snapshot.Source)
+	if err != nil {
+		logrus.Warnf("获取上次权益快照失败，从当前权益重新计算峰值: %v", err)
+	}
+	snapshot.Peak = snapshot.Equity
+	if previous != nil && previous.Peak > snapshot.Peak {
+		snapshot.Peak = previous.Peak
+	}
+	if snapshot.Peak > 0 {
+		snapshot.DrawdownPct = (snapshot.Peak - snapshot.Equity) / snapshot.Peak * 100
+	}
+
+	if err := redis.GlobalRedisClient.AddEquitySnapshot(snapshot, et.retention); err != nil {
+		return nil, err
+	}
+
+	et.checkDrawdown(snapshot)
+	return snapshot, nil
+}
+
+// loadFreqtradeEquity 汇总所有Freqtrade实例的权益，任一实例失败时不记录快照，避免权益曲线出现假回撤
+func (et *EquityTracker) loadFreqtradeEquity(ctx context.Context, snapshot *models.EquitySnapshot) error {
+	if et.bots == nil {
+		return fmt.Errorf("freqtrade客户端未初始化")
+	}
+
+	snapshot.Bots = make(map[string]float64)
+	for _, bot := range et.bots.All() {
+		balance, err := bot.GetBalance(ctx)
+		if err != nil {
+			return fmt.Errorf("获取Freqtrade实例 %s 余额失败: %w", bot.Name, err)
+		}
+		snapshot.Bots[bot.Name] = balance.Total
+		snapshot.Equity += balance.Total
+		if snapshot.Currency == "" {
+			snapshot.Currency = balance.Stake
+		}
+	}
+	return nil
+}
+
+// checkDrawdown 回撤超过阈值时告警，每次回撤只告警一次
+func (et *EquityTracker) checkDrawdown(snapshot *models.EquitySnapshot) {
+	if et.drawdownAlertPct <= 0 {
+		return
+	}
+
+	et.mu.Lock()
+	exceeded := snapshot.DrawdownPct >= et.drawdownAlertPct
+	shouldAlert := exceeded && !et.alerted
+	et.alerted = exceeded
+	et.mu.Unlock()
+
+	if !shouldAlert {
+		return
+	}
+
+	logrus.Warnf("账户权益回撤 %.2f%% 超过阈值 %.2f%%", snapshot.DrawdownPct, et.drawdownAlertPct)
+	notify.Send(notify.EventRisk, i18n.T("notify.drawdown.title"),
+		i18n.T("notify.drawdown.message",
+			snapshot.Equity, snapshot.Currency, snapshot.Peak, snapshot.DrawdownPct, et.drawdownAlertPct),
+		map[string]interface{}{
+			"source":       snapshot.Source,
+			"equity":       snapshot.Equity,
+			"peak":         snapshot.Peak,
+			"drawdown_pct": snapshot.DrawdownPct,
+		})
+}
+
+// MaxDrawdownPct 计算快照区间内的最大回撤百分比（按区间内峰值计算）
+func MaxDrawdownPct(snapshots []*models.EquitySnapshot) float64 {
+	var peak, maxDrawdown float64
+	for _, snapshot := range snapshots {
+		if snapshot.Equity > peak {
+			peak = snapshot.Equity
+		}
+		if peak > 0 {
+			maxDrawdown = max(maxDrawdown, (peak-snapshot.Equity)/peak*100)
+		}
+	}
+	return maxDrawdown
+}
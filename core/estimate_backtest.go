@@ -0,0 +1,183 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/redis"
+)
+
+// maxBacktestBars 单次回放最多读取的K线数量
+const maxBacktestBars = 20000
+
+// ErrBacktestNoKlines 回放窗口内没有缓存的K线
+var ErrBacktestNoKlines = errors.New("回放窗口内没有缓存的K线，需要开启 KLINE_STORE_ENABLED 并保存该周期")
+
+// BacktestEstimate 用缓存的历史K线回放预估的触发条件，返回会在何时触发以及触发后到窗口结束的假设收益
+func BacktestEstimate(estimate *models.PriceEstimate, timeframe string, from, to time.Time) (*models.EstimateBacktest, error) {
+	step, err := timeframeDuration(timeframe)
+	if err != nil {
+		return nil, err
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("开始时间必须早于结束时间")
+	}
+	if to.Sub(from)/step > maxBacktestBars {
+		return nil, fmt.Errorf("回放窗口过大，%s 周期最多回放 %d 根K线", timeframe, maxBacktestBars)
+	}
+
+	store := ExchangeStore(estimate.Exchange)
+	klines, err := store.GetKlinesBetween(estimate.Symbol, timeframe, from.UnixMilli(), to.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	if len(klines) == 0 {
+		return nil, ErrBacktestNoKlines
+	}
+
+	result := &models.EstimateBacktest{
+		Estimate:  estimate,
+		Timeframe: timeframe,
+		From:      from,
+		To:        to,
+		Bars:      len(klines),
+		Notes: []string{
+			"K线为最新成交价，不区分价格来源和买卖价",
+			"K线内先到达开盘价，开盘已满足条件时按开盘价成交，否则按目标价成交",
+			"不模拟波动保护、资金费率检查和拆单执行",
+		},
+	}
+
+	var condition *CrossCondition
+	var conditionCloses map[string]map[int64]float64
+	if estimate.Condition != "" && estimate.TriggerType == models.TriggerTypeCondition {
+		if condition, err = ParseCrossCondition(estimate.Exchange, estimate.Condition); err != nil {
+			return nil, err
+		}
+		if conditionCloses, err = loadBacktestCloses(store, condition.Symbols(), timeframe, from, to); err != nil {
+			return nil, err
+		}
+		result.Notes = append(result.Notes, "跨币种条件按各交易对的K线收盘价判断，满足时按本币种收盘价成交")
+	}
+
+	triggerIndex := -1
+	for i, kline := range klines {
+		if estimate.IsExpired(time.UnixMilli(kline.Timestamp)) {
+			result.Expired = true
+			break
+		}
+
+		var fillPrice float64
+		var triggered bool
+		if condition != nil {
+			fillPrice, triggered = backtestConditionFill(condition, conditionCloses, kline)
+		} else {
+			fillPrice, triggered = backtestPriceFill(estimate, kline)
+		}
+		if triggered {
+			triggerIndex = i
+			result.FillPrice = fillPrice
+			break
+		}
+	}
+	if triggerIndex < 0 {
+		return result, nil
+	}
+
+	triggeredAt := time.UnixMilli(klines[triggerIndex].Timestamp)
+	result.Triggered = true
+	result.TriggeredAt = &triggeredAt
+	applyBacktestOutcome(result, estimate, klines[triggerIndex:])
+	return result, nil
+}
+
+// backtestPriceFill 判断单根K线是否满足目标价条件，返回假设的成交价格
+func backtestPriceFill(estimate *models.PriceEstimate, kline *types.Kline) (float64, bool) {
+	shouldTrigger := func(price float64) bool {
+		if estimate.Side == types.PositionSideShort {
+			return shouldTriggerShort(estimate.ActionType, estimate.TriggerType, price, estimate.TargetPrice)
+		}
+		return shouldTriggerLong(estimate.ActionType, estimate.TriggerType, price, estimate.TargetPrice)
+	}
+
+	switch {
+	case shouldTrigger(kline.Open):
+		// 立即触发或跳空越过目标价，按开盘价成交
+		return kline.Open, true
+	case shouldTrigger(kline.Low) || shouldTrigger(kline.High):
+		return estimate.TargetPrice, true
+	default:
+		return 0, false
+	}
+}
+
+// backtestConditionFill 按各交易对同一根K线的收盘价判断跨币种条件，缺少K线时视为未满足
+func backtestConditionFill(condition *CrossCondition, closes map[string]map[int64]float64, kline *types.Kline) (float64, bool) {
+	prices := make(map[string]*types.WatchMarkPrice, len(closes))
+	for symbol, series := range closes {
+		if price, ok := series[kline.Timestamp]; ok {
+			prices[symbol] = &types.WatchMarkPrice{MarkPrice: price}
+		}
+	}
+	matched, err := condition.Evaluate(prices)
+	if err != nil || !matched {
+		return 0, false
+	}
+	return kline.Close, true
+}
+
+// loadBacktestCloses 读取条件引用交易对在窗口内的收盘价，按开盘时间索引
+func loadBacktestCloses(store *redis.Client, symbols []string, timeframe string, from, to time.Time) (map[string]map[int64]float64, error) {
+	closes := make(map[string]map[int64]float64, len(symbols))
+	for _, symbol := range symbols {
+		klines, err := store.GetKlinesBetween(symbol, timeframe, from.UnixMilli(), to.UnixMilli())
+		if err != nil {
+			return nil, err
+		}
+		if len(klines) == 0 {
+			return nil, fmt.Errorf("条件引用的 %s 在回放窗口内没有缓存的K线", symbol)
+		}
+		series := make(map[int64]float64, len(klines))
+		for _, kline := range klines {
+			series[kline.Timestamp] = kline.Close
+		}
+		closes[symbol] = series
+	}
+	return closes, nil
+}
+
+// applyBacktestOutcome 计算触发后到窗口结束按持仓方向的收益和最大有利/不利波动
+// 触发所在的K线无法确定成交后的路径，只使用其收盘价
+func applyBacktestOutcome(result *models.EstimateBacktest, estimate *models.PriceEstimate, klines []*types.Kline) {
+	direction := 1.0
+	if estimate.Side == types.PositionSideShort {
+		direction = -1.0
+	}
+	fill := result.FillPrice
+	if fill <= 0 {
+		return
+	}
+	move := func(price float64) float64 {
+		return (price - fill) / fill * direction * 100
+	}
+
+	result.ExitPrice = klines[len(klines)-1].Close
+	result.ReturnPct = move(result.ExitPrice)
+	result.MaxFavorablePct = math.Max(0, move(klines[0].Close))
+	result.MaxAdversePct = math.Min(0, move(klines[0].Close))
+	for _, kline := range klines[1:] {
+		for _, price := range []float64{kline.High, kline.Low} {
+			result.MaxFavorablePct = math.Max(result.MaxFavorablePct, move(price))
+			result.MaxAdversePct = math.Min(result.MaxAdversePct, move(price))
+		}
+	}
+
+	leverage := float64(estimate.Leverage)
+	if leverage < 1 {
+		leverage = 1
+	}
+	result.LeveragedPct = result.ReturnPct * leverage
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"fmt"
+	"trading_assistant/models"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// PreviewEstimate 按当前行情试算预估：触发距离、是否会立即触发以及下单数量和保证金，不会保存或下单
+func PreviewEstimate(estimate *models.PriceEstimate) (*models.EstimatePreview, error) {
+	markPrice, err := ExchangeStore(estimate.Exchange).GetMarkPrice(estimate.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("获取 %s 当前价格失败: %v", estimate.Symbol, err)
+	}
+
+	currentPrice := estimateTriggerPrice(estimate, markPrice)
+	if currentPrice <= 0 {
+		return nil, fmt.Errorf("%s 当前价格无效", estimate.Symbol)
+	}
+
+	preview := &models.EstimatePreview{
+		Estimate:     estimate,
+		MarkPrice:    markPrice.MarkPrice,
+		CurrentPrice: currentPrice,
+	}
+	switch {
+	case estimate.Condition != "" && estimate.TriggerType == models.TriggerTypeCondition:
+		condition, err := ParseCrossCondition(estimate.Exchange, estimate.Condition)
+		if err != nil {
+			return nil, err
+		}
+		prices, err := ExchangeStore(estimate.Exchange).GetMarkPrices(condition.Symbols())
+		if err != nil {
+			return nil, err
+		}
+		if preview.WouldTrigger, err = condition.Evaluate(prices); err != nil {
+			return nil, err
+		}
+	case estimate.Side == types.PositionSideShort:
+		preview.WouldTrigger = shouldTriggerShort(estimate.ActionType, estimate.TriggerType, currentPrice, estimate.TargetPrice)
+	default:
+		preview.WouldTrigger = shouldTriggerLong(estimate.ActionType, estimate.TriggerType, currentPrice, estimate.TargetPrice)
+	}
+
+	// 条件触发按目标价计算下单数量，立即触发按当前价计算
+	orderPrice := currentPrice
+	if estimate.TriggerType != models.TriggerTypeImmediate && estimate.TargetPrice > 0 {
+		preview.DistancePct = (estimate.TargetPrice - currentPrice) / currentPrice * 100
+		orderPrice = estimate.TargetPrice
+	}
+	preview.Sizing = ComputeOrderSizing(estimate, orderPrice)
+	return preview, nil
+}
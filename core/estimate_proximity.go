@@ -0,0 +1,139 @@
+package core
+
+import (
+	"math"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/websocket"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AlertTypeProximity 价格接近预估目标价格提醒类型
+const AlertTypeProximity = "proximity"
+
+// proximityRearmFactor 价格离开提醒范围该倍数后才重新提醒，避免价格在边界附近来回时反复通知
+const proximityRearmFactor = 1.5
+
+// ProximityAlert 价格接近预估目标价格提醒
+type ProximityAlert struct {
+	EstimateID      string  `json:"estimate_id"`
+	Symbol          string  `json:"symbol"`
+	Exchange        string  `json:"exchange,omitempty"`
+	Side            string  `json:"side"`
+	ActionType      string  `json:"action_type"`
+	TargetPrice     float64 `json:"target_price"`
+	Price           float64 `json:"price"`             // 触发价格来源的当前价格
+	DistancePct     float64 `json:"distance_pct"`      // 当前价格距目标价格的百分比
+	NotifyWithinPct float64 `json:"notify_within_pct"` // 预估设置的提醒范围
+	Timestamp       int64   `json:"timestamp"`
+}
+
+// proximityTracker 记录价格已进入提醒范围的预估，每次进入范围只提醒一次
+type proximityTracker struct {
+	mu     sync.Mutex
+	inBand map[string]bool // 预估ID -> 价格是否处于提醒范围内
+}
+
+// newProximityTracker 创建接近目标价格提醒状态
+func newProximityTracker() *proximityTracker {
+	return &proximityTracker{inBand: make(map[string]bool)}
+}
+
+// enter 按当前距离更新状态，返回价格是否刚进入提醒范围
+func (t *proximityTracker) enter(estimateID string, distance, band float64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case distance <= band:
+		if t.inBand[estimateID] {
+			return false
+		}
+		t.inBand[estimateID] = true
+		return true
+	case distance > band*proximityRearmFactor:
+		delete(t.inBand, estimateID)
+	}
+	return false
+}
+
+// forget 删除预估的提醒状态
+func (t *proximityTracker) forget(estimateID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.inBand, estimateID)
+}
+
+// retain 只保留仍在监听中的预估的提醒状态，删除或暂停的预估不会再调用 forget
+func (t *proximityTracker) retain(estimates []*models.PriceEstimate) {
+	active := make(map[string]bool, len(estimates))
+	for _, estimate := range estimates {
+		active[estimate.ID] = true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for estimateID := range t.inBand {
+		if !active[estimateID] {
+			delete(t.inBand, estimateID)
+		}
+	}
+}
+
+// targetDistancePct 当前价格距目标价格的百分比，跨币种条件和立即执行的预估没有目标价格可比较
+func targetDistancePct(estimate *models.PriceEstimate, currentPrice float64) (float64, bool) {
+	if estimate.TargetPrice <= 0 || estimate.Condition != "" || estimate.TriggerType == models.TriggerTypeImmediate {
+		return 0, false
+	}
+	return math.Abs(currentPrice-estimate.TargetPrice) / estimate.TargetPrice * 100, true
+}
+
+// checkProximity 价格进入预估设置的提醒范围时发送一次通知并推送给前端，离开范围后重新计算
+func (pm *PriceMonitor) checkProximity(estimate *models.PriceEstimate, currentPrice float64, now time.Time) {
+	if estimate.NotifyWithinPct <= 0 || pm.proximity == nil {
+		return
+	}
+	distance, ok := targetDistancePct(estimate, currentPrice)
+	if !ok || !pm.proximity.enter(estimate.ID, distance, estimate.NotifyWithinPct) {
+		return
+	}
+
+	formatter := Formatter(estimate.Exchange)
+	price := formatter.FormatPrice(estimate.Symbol, currentPrice)
+	target := formatter.FormatPrice(estimate.Symbol, estimate.TargetPrice)
+	logrus.Infof("价格接近目标: %s %s %s, 当前%s: %s, 目标价格: %s, 距离: %.2f%%",
+		estimate.Symbol, estimate.Side, estimate.ActionType, priceSourceText(estimate), price, target, distance)
+
+	alert := &ProximityAlert{
+		EstimateID:      estimate.ID,
+		Symbol:          estimate.Symbol,
+		Exchange:        estimate.Exchange,
+		Side:            estimate.Side,
+		ActionType:      estimate.ActionType,
+		TargetPrice:     estimate.TargetPrice,
+		Price:           currentPrice,
+		DistancePct:     distance,
+		NotifyWithinPct: estimate.NotifyWithinPct,
+		Timestamp:       now.UnixMilli(),
+	}
+	if wsManager := websocket.GetGlobalWebSocketManager(); wsManager != nil {
+		wsManager.BroadcastAlert(AlertTypeProximity, alert)
+	}
+
+	message := i18n.T("notify.proximity.message",
+		estimate.Symbol, getActionText(estimate.ActionType), getPositionText(estimate.Side), price, target, distance)
+	notify.Send(notify.EventProximity, i18n.T("notify.proximity.title"), message,
+		map[string]interface{}{"estimate_id": estimate.ID})
+
+	RecordEstimateEvent(estimate.ID, models.EstimateEventNearTrigger, message,
+		map[string]interface{}{
+			"price":        currentPrice,
+			"target_price": estimate.TargetPrice,
+			"distance_pct": distance,
+			"notified":     true,
+		})
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"fmt"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sweepExpiredEstimates 将到期仍在监听的预估标记为已过期
+func (pm *PriceMonitor) sweepExpiredEstimates() {
+	estimates, err := redis.GlobalRedisClient.GetAllEstimates()
+	if err != nil {
+		logrus.Errorf("获取价格预估失败: %v", err)
+		return
+	}
+
+	now := time.Now()
+	expired := 0
+	for _, estimate := range estimates {
+		if estimate.Status != models.EstimateStatusListening || !estimate.IsExpired(now) {
+			continue
+		}
+
+		// 读取之后可能被延长到期时间或已触发，按最新内容重新判断
+		updated, err := updateEstimate(estimate, func(current *models.PriceEstimate) error {
+			if current.Status != models.EstimateStatusListening || !current.IsExpired(now) {
+				return errEstimateUnchanged
+			}
+			current.Status = models.EstimateStatusExpired
+			current.Enabled = false
+			current.ErrorMessage = fmt.Sprintf("已于 %s 过期，未触发", current.ExpiresAt.Format(time.DateTime))
+			return nil
+		})
+		if err != nil {
+			logrus.Errorf("更新过期预估 %s 失败: %v", estimate.ID, err)
+			continue
+		}
+		if updated == nil {
+			continue
+		}
+		estimate = updated
+		expired++
+		RecordEstimateEvent(estimate.ID, models.EstimateEventExpired, estimate.ErrorMessage, nil)
+
+		targetPrice := Formatter(estimate.Exchange).FormatPrice(estimate.Symbol, estimate.TargetPrice)
+		logrus.Infof("价格预估已过期: %s %s %s, 目标价: %s", estimate.Symbol, estimate.Side, estimate.ActionType, targetPrice)
+		notify.Send(notify.EventExpired, i18n.T("notify.expired.title"),
+			i18n.T("notify.expired.message",
+				estimate.Symbol, getActionText(estimate.ActionType), getPositionText(estimate.Side), targetPrice),
+			map[string]interface{}{"estimate_id": estimate.ID})
+	}
+
+	if expired > 0 {
+		go utils.BroadcastSymbolEstimatesUpdate()
+	}
+}
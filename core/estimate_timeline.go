@@ -0,0 +1,63 @@
+package core
+
+import (
+	"fmt"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RecordEstimateEvent 追加一条预估时间线事件，保存失败只记录日志，不影响预估的正常流程
+func RecordEstimateEvent(estimateID, eventType, message string, data map[string]interface{}) {
+	if redis.GlobalRedisClient == nil || estimateID == "" {
+		return
+	}
+
+	event := &models.EstimateEvent{
+		EstimateID: estimateID,
+		Type:       eventType,
+		Message:    message,
+		Data:       data,
+		Timestamp:  time.Now().UnixMilli(),
+	}
+	cfg := config.GlobalConfig
+	if err := redis.GlobalRedisClient.AddEstimateEvent(event, cfg.EstimateEventMaxLen, cfg.EstimateEventRetention); err != nil {
+		logrus.Errorf("记录预估 %s 事件 %s 失败: %v", estimateID, eventType, err)
+	}
+}
+
+// RecordEstimateToggled 记录预估启用或暂停监听
+func RecordEstimateToggled(estimateID string, enabled bool, message string) {
+	eventType := models.EstimateEventDisabled
+	if enabled {
+		eventType = models.EstimateEventEnabled
+	}
+	RecordEstimateEvent(estimateID, eventType, message, nil)
+}
+
+// recordNearTrigger 价格进入目标价格附近时记录接近触发事件，同一预估在冷却时间内只记录一次
+// 预估设置了接近目标价格提醒时由 checkProximity 记录
+func (pm *PriceMonitor) recordNearTrigger(estimate *models.PriceEstimate, currentPrice float64, now time.Time) {
+	threshold := config.GlobalConfig.EstimateNearTriggerPct
+	if threshold <= 0 || pm.nearLog == nil || estimate.NotifyWithinPct > 0 {
+		return
+	}
+
+	distance, ok := targetDistancePct(estimate, currentPrice)
+	if !ok || distance > threshold || !pm.nearLog.shouldLog(estimate.ID, now) {
+		return
+	}
+
+	formatter := Formatter(estimate.Exchange)
+	RecordEstimateEvent(estimate.ID, models.EstimateEventNearTrigger,
+		fmt.Sprintf("%s %s 距目标价格 %s 仅 %.2f%%", priceSourceText(estimate),
+			formatter.FormatPrice(estimate.Symbol, currentPrice), formatter.FormatPrice(estimate.Symbol, estimate.TargetPrice), distance),
+		map[string]interface{}{
+			"price":        currentPrice,
+			"target_price": estimate.TargetPrice,
+			"distance_pct": distance,
+		})
+}
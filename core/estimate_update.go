@@ -0,0 +1,36 @@
+package core
+
+import (
+	"errors"
+	"trading_assistant/models"
+	"trading_assistant/pkg/redis"
+)
+
+// estimateUpdateRetries 后台更新预估遇到并发修改时的最大重试次数
+const estimateUpdateRetries = 3
+
+// errEstimateUnchanged update 按最新内容判断无需再更新时返回
+var errEstimateUnchanged = errors.New("价格预估无需更新")
+
+// updateEstimate 以读取时的 UpdatedAt 乐观更新预估，读取之后被编辑、切换等操作修改时重新读取，在最新内容上再次执行 update
+// update 可能执行多次，需要按传入的最新内容重新判断；返回 errEstimateUnchanged 时放弃更新并返回 (nil, nil)
+func updateEstimate(estimate *models.PriceEstimate, update func(current *models.PriceEstimate) error) (*models.PriceEstimate, error) {
+	expected := estimate.UpdatedAt
+	for attempt := 0; ; attempt++ {
+		updated, err := redis.GlobalRedisClient.UpdatePriceEstimate(estimate.ID, expected, update)
+		switch {
+		case err == nil:
+			return updated, nil
+		case errors.Is(err, errEstimateUnchanged):
+			return nil, nil
+		case !errors.Is(err, redis.ErrEstimateConflict) || attempt >= estimateUpdateRetries:
+			return nil, err
+		}
+
+		current, err := redis.GlobalRedisClient.GetEstimateById(estimate.ID)
+		if err != nil {
+			return nil, err
+		}
+		expected = current.UpdatedAt
+	}
+}
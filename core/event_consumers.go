@@ -0,0 +1,225 @@
+package core
+
+import (
+	"sort"
+	"strconv"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+	"trading_assistant/pkg/websocket"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RegisterEventConsumers 注册内部事件订阅者，新增消费者只需在此订阅对应主题
+func RegisterEventConsumers() {
+	bus := eventbus.GetBus()
+	buffer := config.GlobalConfig.EventBusBuffer
+
+	bus.Subscribe(eventbus.TopicMarkPrice, "basis", buffer, recordBasis)
+	bus.Subscribe(eventbus.TopicMarkPrice, "hub", buffer, broadcastPrices)
+	bus.Subscribe(eventbus.TopicMarkPrice, "volatility", buffer, GetVolatilityTracker().OnMarkPrices)
+	bus.Subscribe(eventbus.TopicEstimateTriggered, "hub", buffer, func(*eventbus.Event) {
+		utils.BroadcastSymbolEstimatesUpdate()
+	})
+	bus.Subscribe(eventbus.TopicPosition, "hub", buffer, broadcastPositions)
+	bus.Subscribe(eventbus.TopicOrder, "hub", buffer, broadcastOrder)
+
+	wsManager := websocket.GetGlobalWebSocketManager()
+	wsManager.RegisterDataType(websocket.DataTypePositions, positionsSnapshot)
+	wsManager.RegisterDataType(websocket.DataTypeOrders, ordersSnapshot)
+	wsManager.RegisterDataType(websocket.DataTypeAccount, accountSnapshot)
+	wsManager.RegisterDataType(websocket.DataTypeKillSwitch, killSwitchSnapshot)
+
+	// 滚动统计依赖已保存的K线
+	if config.GlobalConfig.StatsEnabled {
+		bus.Subscribe(eventbus.TopicKline, "stats", buffer, GetSymbolStatsService().OnKlines)
+	}
+
+	// 现货没有资金费结算时间，跟踪器不会产生记录
+	if config.GlobalConfig.FundingTrackerEnabled {
+		tracker := GetFundingTracker()
+		bus.Subscribe(eventbus.TopicMarkPrice, "funding", buffer, tracker.OnMarkPrices)
+		bus.Subscribe(eventbus.TopicPosition, "funding", buffer, tracker.OnPositions)
+	}
+}
+
+// recordBasis 记录基差（仅期货模式有指数价格）
+func recordBasis(event *eventbus.Event) {
+	batch, ok := event.Payload.(*eventbus.MarkPriceBatch)
+	if !ok {
+		return
+	}
+
+	store := ExchangeStore(event.Exchange)
+	for _, price := range batch.Prices {
+		GetBasisMonitor().Record(store, event.Exchange, price)
+	}
+}
+
+// broadcastPrices 广播价格数据给前端（仅主交易所，避免不同交易所价格相互覆盖）
+func broadcastPrices(event *eventbus.Event) {
+	batch, ok := event.Payload.(*eventbus.MarkPriceBatch)
+	if !ok || !batch.Primary {
+		return
+	}
+
+	wsManager := websocket.GetGlobalWebSocketManager()
+	if wsManager == nil {
+		logrus.Debug("WebSocket管理器未初始化")
+		return
+	}
+
+	store := ExchangeStore(event.Exchange)
+	pricesData := make(map[string]interface{}, len(batch.Prices))
+	for symbol, price := range batch.Prices {
+		// 获取价格变化信息用于广播
+		priceChange := 0.0
+		priceChangePercent := 0.0
+		if coin, err := store.GetCoin(symbol); err == nil {
+			if change, parseErr := strconv.ParseFloat(coin.PriceChange, 64); parseErr == nil {
+				priceChange = change
+			}
+			if changePercent, parseErr := strconv.ParseFloat(coin.PriceChangePercent, 64); parseErr == nil {
+				priceChangePercent = changePercent
+			}
+		}
+
+		// 构建广播数据（包含实时买卖价）
+		pricesData[symbol] = map[string]interface{}{
+			"symbol":             symbol,
+			"bidPrice":           price.BidPrice,    // 实时买价
+			"askPrice":           price.AskPrice,    // 实时卖价
+			"markPrice":          price.MarkPrice,   // 标记价格（参考）
+			"indexPrice":         price.IndexPrice,  // 指数价格
+			"fundingRate":        price.FundingRate, // 资金费率
+			"fundingTime":        price.FundingTime, // 下次资金费时间
+			"updateTime":         price.TimeStamp,   // 更新时间
+			"priceChange":        priceChange,
+			"priceChangePercent": priceChangePercent,
+		}
+	}
+
+	wsManager.BroadcastPrices(pricesData)
+	logrus.Debugf("通过WebSocket广播价格数据，包含 %d 个币种", len(pricesData))
+}
+
+// recentOrdersWindow 订单初始数据包含的时间范围
+const recentOrdersWindow = 24 * time.Hour
+
+// broadcastPositions 持仓缓存刷新后推送全部持仓和账户概览
+func broadcastPositions(event *eventbus.Event) {
+	positions, ok := event.Payload.([]*models.Position)
+	if !ok {
+		return
+	}
+
+	wsManager := websocket.GetGlobalWebSocketManager()
+	wsManager.BroadcastPositions(groupPositions(positions))
+	wsManager.BroadcastAccount(buildAccountSnapshot(positions))
+}
+
+// broadcastOrder 推送新提交的订单和账户概览，订单与初始数据格式一致按币种分组
+func broadcastOrder(event *eventbus.Event) {
+	estimate, ok := event.Payload.(*models.PriceEstimate)
+	if !ok {
+		return
+	}
+
+	wsManager := websocket.GetGlobalWebSocketManager()
+	wsManager.BroadcastOrders(map[string]interface{}{
+		estimate.Symbol: []*models.PriceEstimate{estimate},
+	})
+	if account, err := accountSnapshot(); err != nil {
+		logrus.Warnf("获取账户概览失败: %v", err)
+	} else {
+		wsManager.BroadcastAccount(account)
+	}
+}
+
+// groupPositions 按币种分组持仓，便于客户端按币种过滤
+func groupPositions(positions []*models.Position) map[string]interface{} {
+	grouped := make(map[string][]*models.Position)
+	for _, position := range positions {
+		grouped[position.Symbol] = append(grouped[position.Symbol], position)
+	}
+
+	data := make(map[string]interface{}, len(grouped))
+	for symbol, list := range grouped {
+		data[symbol] = list
+	}
+	return data
+}
+
+// buildAccountSnapshot 汇总持仓生成账户概览
+func buildAccountSnapshot(positions []*models.Position) *models.AccountSnapshot {
+	account := &models.AccountSnapshot{
+		Positions: len(positions),
+		UpdatedAt: time.Now(),
+	}
+	for _, position := range positions {
+		account.Notional += position.Notional
+		account.InitialMargin += position.InitialMargin
+		account.UnrealizedPnl += position.UnrealizedPnl
+	}
+
+	if _, total, err := OpenPositionFunding(); err == nil {
+		account.OpenFunding = total
+	}
+	if paper, err := redis.GlobalRedisClient.GetPaperAccount(); err != nil {
+		logrus.Warnf("获取模拟账户失败: %v", err)
+	} else {
+		account.Paper = paper
+	}
+	return account
+}
+
+// positionsSnapshot 持仓初始数据，来自持仓缓存
+func positionsSnapshot() (interface{}, error) {
+	positions, err := redis.GlobalRedisClient.GetAllPositions()
+	if err != nil {
+		return nil, err
+	}
+	return groupPositions(positions), nil
+}
+
+// accountSnapshot 账户概览初始数据
+func accountSnapshot() (interface{}, error) {
+	positions, err := redis.GlobalRedisClient.GetAllPositions()
+	if err != nil {
+		return nil, err
+	}
+	return buildAccountSnapshot(positions), nil
+}
+
+// ordersSnapshot 订单初始数据，最近24小时已提交的订单按币种分组，组内按更新时间从新到旧排序
+func ordersSnapshot() (interface{}, error) {
+	estimates, err := redis.GlobalRedisClient.GetAllEstimates()
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-recentOrdersWindow)
+	grouped := make(map[string][]*models.PriceEstimate)
+	for _, estimate := range estimates {
+		switch estimate.Status {
+		case models.EstimateStatusTriggered, models.EstimateStatusVerified, models.EstimateStatusExecutionMismatch:
+		default:
+			continue
+		}
+		if estimate.UpdatedAt.Before(since) {
+			continue
+		}
+		grouped[estimate.Symbol] = append(grouped[estimate.Symbol], estimate)
+	}
+
+	data := make(map[string]interface{}, len(grouped))
+	for symbol, orders := range grouped {
+		sort.Slice(orders, func(i, j int) bool { return orders[i].UpdatedAt.After(orders[j].UpdatedAt) })
+		data[symbol] = orders
+	}
+	return data, nil
+}
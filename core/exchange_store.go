@@ -0,0 +1,93 @@
+package core
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchange_factory"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/redis"
+)
+
+var (
+	exchangeClients      = make(map[string]exchange_factory.ExchangeInterface)
+	exchangeClientsMutex sync.RWMutex
+)
+
+// ExchangeNamespace 获取交易所在Redis中的命名空间，主交易所为空以保持原有键名
+func ExchangeNamespace(exchange string) string {
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	if exchange == "" || exchange == strings.ToLower(config.GlobalConfig.ExchangeType) {
+		return ""
+	}
+	return exchange
+}
+
+// ExchangeStore 获取按交易所隔离市场和价格数据的Redis客户端
+func ExchangeStore(exchange string) *redis.Client {
+	return redis.GlobalRedisClient.ForExchange(ExchangeNamespace(exchange))
+}
+
+// SymbolMapper 获取交易所的符号转换器，交易所为空时使用主交易所
+func SymbolMapper(exchange string) *exchanges.SymbolMapper {
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	if exchange == "" {
+		exchange = config.GlobalConfig.ExchangeType
+	}
+	return exchanges.SymbolMapperFor(exchange)
+}
+
+// ResolveMarketID 将任意格式的币种符号转换为交易所MarketID，无法识别时原样返回
+func ResolveMarketID(exchange, symbol string) string {
+	return SymbolMapper(exchange).MarketID(symbol)
+}
+
+// IsExchangeEnabled 检查交易所是否在运行（主交易所或已配置的其他交易所）
+func IsExchangeEnabled(exchange string) bool {
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	if exchange == "" || exchange == strings.ToLower(config.GlobalConfig.ExchangeType) {
+		return true
+	}
+	for _, secondary := range config.GlobalConfig.SecondaryExchanges {
+		if exchange == strings.ToLower(strings.TrimSpace(secondary)) {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterExchangeClient 登记运行中的交易所客户端，供按交易所查询能力
+func RegisterExchangeClient(client exchange_factory.ExchangeInterface) {
+	exchangeClientsMutex.Lock()
+	defer exchangeClientsMutex.Unlock()
+	exchangeClients[strings.ToLower(client.GetID())] = client
+}
+
+// ExchangeClient 获取运行中的交易所客户端，交易所为空时返回主交易所
+func ExchangeClient(exchange string) (exchange_factory.ExchangeInterface, bool) {
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	if exchange == "" {
+		exchange = strings.ToLower(config.GlobalConfig.ExchangeType)
+	}
+
+	exchangeClientsMutex.RLock()
+	defer exchangeClientsMutex.RUnlock()
+	client, exists := exchangeClients[exchange]
+	return client, exists
+}
+
+// ExchangeCapabilities 获取所有运行中交易所的能力矩阵
+func ExchangeCapabilities() []exchange_factory.Capabilities {
+	exchangeClientsMutex.RLock()
+	defer exchangeClientsMutex.RUnlock()
+
+	result := make([]exchange_factory.Capabilities, 0, len(exchangeClients))
+	for _, client := range exchangeClients {
+		result = append(result, exchange_factory.GetCapabilities(client))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Exchange < result[j].Exchange
+	})
+	return result
+}
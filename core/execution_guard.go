@@ -0,0 +1,199 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// estimateExecution 一次持有执行令牌的预估执行
+type estimateExecution struct {
+	mu     sync.Mutex
+	record *models.EstimateExecution
+}
+
+// beginEstimateExecution 取得预估的执行权并将预估切换为执行中，未取得时返回nil
+// 执行权通过 SETNX 保证同一预估在多个实例或重复触发时只会下单一次
+func (pm *PriceMonitor) beginEstimateExecution(estimate *models.PriceEstimate) *estimateExecution {
+	now := time.Now()
+	record := &models.EstimateExecution{
+		EstimateID: estimate.ID,
+		Token:      uuid.New().String(),
+		Owner:      instanceID,
+		State:      models.ExecutionStateExecuting,
+		StartedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	acquired, err := redis.GlobalRedisClient.AcquireEstimateExecution(record, config.GlobalConfig.ExecutionRecordTTL)
+	if err != nil {
+		logrus.Errorf("获取预估 %s 执行权失败，放弃本次触发: %v", estimate.ID, err)
+		return nil
+	}
+	if !acquired {
+		pm.recordSkip(estimate, models.SkipReasonDuplicate, "预估已有执行记录")
+		return nil
+	}
+
+	updated, err := redis.GlobalRedisClient.UpdatePriceEstimate(estimate.ID, estimate.UpdatedAt, func(current *models.PriceEstimate) error {
+		if current.Status != models.EstimateStatusListening {
+			return fmt.Errorf("预估状态已变为 %s", current.Status)
+		}
+		current.Status = models.EstimateStatusExecuting
+		return nil
+	})
+	switch {
+	case errors.Is(err, redis.ErrEstimateNotFound):
+		// 价差腿、自动减仓等立即执行的预估在触发时才首次保存
+		estimate.Status = models.EstimateStatusExecuting
+		estimate.UpdatedAt = now
+		err = redis.GlobalRedisClient.SetPriceEstimate(estimate)
+	case err == nil:
+		*estimate = *updated
+	}
+	if err != nil {
+		logrus.Warnf("预估 %s 切换为执行中失败，放弃本次触发: %v", estimate.ID, err)
+		if err := redis.GlobalRedisClient.ReleaseEstimateExecution(estimate.ID, record.Token); err != nil {
+			logrus.Errorf("释放预估 %s 执行权失败: %v", estimate.ID, err)
+		}
+		return nil
+	}
+
+	return &estimateExecution{record: record}
+}
+
+// recordResponse 保存Freqtrade下单响应，进程中断后据此判断订单是否已提交
+func (e *estimateExecution) recordResponse(path string, body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	e.record.Responses = append(e.record.Responses, models.ExecutionResponse{
+		Path:       path,
+		Body:       string(body),
+		ReceivedAt: now,
+	})
+	e.record.UpdatedAt = now
+	e.save()
+}
+
+// finish 记录执行结果
+func (e *estimateExecution) finish(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	e.record.State = models.ExecutionStateSucceeded
+	if err != nil {
+		e.record.State = models.ExecutionStateFailed
+		e.record.Error = err.Error()
+	}
+	e.record.FinishedAt = &now
+	e.record.UpdatedAt = now
+	e.save()
+}
+
+// save 保存执行记录（调用方需持有锁）
+func (e *estimateExecution) save() {
+	if err := redis.GlobalRedisClient.UpdateEstimateExecution(e.record, config.GlobalConfig.ExecutionRecordTTL); err != nil {
+		logrus.Errorf("保存预估 %s 执行记录失败: %v", e.record.EstimateID, err)
+	}
+}
+
+// recoverInterruptedExecutions 处理进程中断后停留在执行中的预估
+// 根据执行记录恢复最终状态，无法确认是否已下单时标记为失败并告警，不会重新下单
+func (pm *PriceMonitor) recoverInterruptedExecutions() {
+	estimates, err := redis.GlobalRedisClient.GetAllEstimates()
+	if err != nil {
+		logrus.Errorf("获取价格预估失败: %v", err)
+		return
+	}
+
+	timeout := config.GlobalConfig.ExecutionTimeout
+	now := time.Now()
+	recovered := 0
+	for _, estimate := range estimates {
+		if estimate.Status != models.EstimateStatusExecuting {
+			continue
+		}
+
+		record, err := redis.GlobalRedisClient.GetEstimateExecution(estimate.ID)
+		if err != nil {
+			logrus.Errorf("获取预估 %s 执行记录失败: %v", estimate.ID, err)
+			continue
+		}
+
+		var status, errorMessage string
+		interrupted := true
+		switch {
+		case record == nil:
+			status = models.EstimateStatusFailed
+			errorMessage = "执行中断且执行记录已过期，无法确认是否已下单，请核对持仓"
+		case record.State == models.ExecutionStateSucceeded:
+			status, interrupted = models.EstimateStatusTriggered, false
+		case record.State == models.ExecutionStateFailed:
+			status, interrupted = models.EstimateStatusFailed, false
+			errorMessage = record.Error
+		case now.Sub(record.UpdatedAt) < timeout:
+			// 仍在执行中
+			continue
+		case len(record.Responses) > 0:
+			status = models.EstimateStatusTriggered
+			errorMessage = "执行中断，Freqtrade已返回下单响应，按已下单处理"
+		default:
+			status = models.EstimateStatusFailed
+			errorMessage = "执行中断，未收到Freqtrade下单响应，无法确认是否已下单，请核对持仓"
+		}
+
+		if interrupted && record != nil {
+			record.State = models.ExecutionStateFailed
+			if status == models.EstimateStatusTriggered {
+				record.State = models.ExecutionStateSucceeded
+			}
+			record.Error = errorMessage
+			record.FinishedAt = &now
+			record.UpdatedAt = now
+			if err := redis.GlobalRedisClient.UpdateEstimateExecution(record, config.GlobalConfig.ExecutionRecordTTL); err != nil {
+				logrus.Warnf("更新预估 %s 执行记录失败，等待下次检查: %v", estimate.ID, err)
+				continue
+			}
+		}
+
+		_, err = redis.GlobalRedisClient.UpdatePriceEstimate(estimate.ID, estimate.UpdatedAt, func(current *models.PriceEstimate) error {
+			if current.Status != models.EstimateStatusExecuting {
+				return fmt.Errorf("预估状态已变为 %s", current.Status)
+			}
+			current.Status = status
+			current.ErrorMessage = errorMessage
+			return nil
+		})
+		if err != nil {
+			logrus.Warnf("恢复预估 %s 状态失败: %v", estimate.ID, err)
+			continue
+		}
+		recovered++
+
+		if !interrupted {
+			logrus.Infof("预估 %s (%s) 已按执行记录恢复为 %s", estimate.ID, estimate.Symbol, status)
+			continue
+		}
+		logrus.Warnf("预估 %s (%s) 执行中断，已恢复为 %s: %s", estimate.ID, estimate.Symbol, status, errorMessage)
+		notify.Send(notify.EventFailure, i18n.T("notify.execution_aborted.title"),
+			i18n.T("notify.execution_aborted.message", estimate.Symbol, getActionText(estimate.ActionType), getPositionText(estimate.Side), errorMessage),
+			map[string]interface{}{"estimate_id": estimate.ID})
+	}
+
+	if recovered > 0 {
+		go utils.BroadcastSymbolEstimatesUpdate()
+	}
+}
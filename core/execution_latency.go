@@ -0,0 +1,87 @@
+package core
+
+import (
+	"math"
+	"sort"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/metrics"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// recordExecutionLatency 记录一次触发的执行延迟，超过SLO时告警
+func recordExecutionLatency(estimate *models.PriceEstimate, detectedAt, execStart, execEnd time.Time, err error) {
+	total := execEnd.Sub(detectedAt)
+	result := metrics.ResultLabel(err)
+	estimate.ExecutionLatencyMs = total.Milliseconds()
+
+	metrics.ExecutionLatency.WithLabelValues(estimate.ActionType, result).Observe(total.Seconds())
+
+	record := &models.ExecutionLatency{
+		EstimateID:  estimate.ID,
+		Symbol:      estimate.Symbol,
+		Exchange:    estimate.Exchange,
+		ActionType:  estimate.ActionType,
+		Result:      result,
+		DetectionMs: execStart.Sub(detectedAt).Milliseconds(),
+		ExecutionMs: execEnd.Sub(execStart).Milliseconds(),
+		TotalMs:     total.Milliseconds(),
+		Timestamp:   execEnd.UnixMilli(),
+	}
+	if err := redis.GlobalRedisClient.AddExecutionLatency(record); err != nil {
+		logrus.Errorf("记录执行延迟失败: %v", err)
+	}
+
+	slo := config.GlobalConfig.ExecutionLatencySLO
+	if slo <= 0 || total <= slo {
+		return
+	}
+
+	metrics.ExecutionLatencySLOViolations.WithLabelValues(estimate.ActionType).Inc()
+	message := i18n.T("notify.latency.message",
+		estimate.Symbol, getActionText(estimate.ActionType), getPositionText(estimate.Side),
+		total.Round(time.Millisecond), slo, record.DetectionMs, record.ExecutionMs)
+	logrus.Warn(message)
+	notify.Send(notify.EventLatency, i18n.T("notify.latency.title"), message, map[string]interface{}{
+		"estimate_id": estimate.ID,
+		"total_ms":    record.TotalMs,
+	})
+}
+
+// ComputeLatencyStats 计算执行延迟分位数
+func ComputeLatencyStats(records []*models.ExecutionLatency, slo time.Duration) *models.LatencyStats {
+	stats := &models.LatencyStats{Count: len(records), SLOMs: slo.Milliseconds()}
+	if len(records) == 0 {
+		return stats
+	}
+
+	values := make([]int64, len(records))
+	var sum int64
+	for i, record := range records {
+		values[i] = record.TotalMs
+		sum += record.TotalMs
+		if slo > 0 && record.TotalMs > stats.SLOMs {
+			stats.Over++
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	stats.P50 = percentile(values, 0.50)
+	stats.P90 = percentile(values, 0.90)
+	stats.P99 = percentile(values, 0.99)
+	stats.Max = values[len(values)-1]
+	stats.Mean = sum / int64(len(values))
+	return stats
+}
+
+// percentile 按最近秩法计算已排序数据的分位数
+func percentile(sorted []int64, p float64) int64 {
+	idx := int(math.Ceil(float64(len(sorted))*p)) - 1
+	idx = min(max(idx, 0), len(sorted)-1)
+	return sorted[idx]
+}
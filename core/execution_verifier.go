@@ -0,0 +1,229 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/metrics"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+	"trading_assistant/pkg/websocket"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AlertTypeExecution 执行结果不一致告警类型
+const AlertTypeExecution = "execution"
+
+// amountEpsilon 持仓数量比较的误差
+const amountEpsilon = 1e-9
+
+// positionSnapshot 下单前的持仓快照
+type positionSnapshot struct {
+	found   bool
+	tradeID int
+	amount  float64
+}
+
+// ExecutionMismatchAlert 执行结果不一致告警
+type ExecutionMismatchAlert struct {
+	EstimateID   string  `json:"estimate_id"`
+	Symbol       string  `json:"symbol"`
+	Side         string  `json:"side"`
+	ActionType   string  `json:"action_type"`
+	BeforeAmount float64 `json:"before_amount"` // 下单前持仓数量
+	AfterAmount  float64 `json:"after_amount"`  // 验证窗口结束时持仓数量
+	Reason       string  `json:"reason"`
+	Timestamp    int64   `json:"timestamp"`
+}
+
+// ExecutionVerifier 下单结果验证器
+// 下单后在验证窗口内轮询Freqtrade持仓，确认预期的持仓变化已发生
+type ExecutionVerifier struct {
+	window   time.Duration
+	interval time.Duration
+}
+
+// NewExecutionVerifier 创建下单结果验证器
+func NewExecutionVerifier() *ExecutionVerifier {
+	interval := config.GlobalConfig.ExecutionVerifyInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return &ExecutionVerifier{
+		window:   config.GlobalConfig.ExecutionVerifyWindow,
+		interval: interval,
+	}
+}
+
+// Enabled 是否启用验证
+func (ev *ExecutionVerifier) Enabled() bool {
+	return ev.window > 0
+}
+
+// Snapshot 记录下单实例上的下单前持仓
+func (ev *ExecutionVerifier) Snapshot(client *freqtrade.Controller, pair, side string) positionSnapshot {
+	trades, err := client.GetTradeStatus(context.Background())
+	if err != nil {
+		logrus.Warnf("获取下单前持仓失败: %v", err)
+		return positionSnapshot{}
+	}
+	return snapshotTrade(matchTrade(trades, pair, side))
+}
+
+// Watch 异步验证下单后的持仓变化
+func (ev *ExecutionVerifier) Watch(client *freqtrade.Controller, estimate *models.PriceEstimate, pair string, before positionSnapshot) {
+	go ev.verify(client, estimate.ID, estimate.ActionType, estimate.Side, pair, before)
+}
+
+// verify 在验证窗口内轮询持仓，直到观察到预期变化或超时
+func (ev *ExecutionVerifier) verify(client *freqtrade.Controller, estimateID, actionType, side, pair string, before positionSnapshot) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("验证下单结果时发生异常: %v", r)
+		}
+	}()
+
+	deadline := time.Now().Add(ev.window)
+	ticker := time.NewTicker(ev.interval)
+	defer ticker.Stop()
+
+	var after positionSnapshot
+	for range ticker.C {
+		trades, err := client.GetTradeStatus(context.Background())
+		if err != nil {
+			logrus.Debugf("验证下单结果时获取持仓失败: %v", err)
+		} else {
+			after = snapshotTrade(matchTrade(trades, pair, side))
+			if positionChanged(actionType, before, after) {
+				ev.finish(estimateID, before, after, "")
+				return
+			}
+		}
+
+		if time.Now().After(deadline) {
+			break
+		}
+	}
+
+	reason := fmt.Sprintf("%v 内未观察到预期的持仓变化 (下单前数量 %.8f, 当前数量 %.8f)", ev.window, before.amount, after.amount)
+	ev.finish(estimateID, before, after, reason)
+}
+
+// finish 更新预估状态，验证失败时告警
+func (ev *ExecutionVerifier) finish(estimateID string, before, after positionSnapshot, reason string) {
+	estimate, err := redis.GlobalRedisClient.GetEstimateById(estimateID)
+	if err != nil {
+		logrus.Debugf("预估 %s 已不存在，跳过验证结果更新", estimateID)
+		return
+	}
+	// 只更新仍处于已触发状态的预估，避免覆盖用户的修改
+	if estimate.Status != models.EstimateStatusTriggered {
+		return
+	}
+
+	status := models.EstimateStatusVerified
+	if reason != "" {
+		status = models.EstimateStatusExecutionMismatch
+	}
+	updated, err := updateEstimate(estimate, func(current *models.PriceEstimate) error {
+		if current.Status != models.EstimateStatusTriggered {
+			return errEstimateUnchanged
+		}
+		current.Status = status
+		if reason != "" {
+			current.ErrorMessage = reason
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("更新预估验证状态失败: %v", err)
+		return
+	}
+	if updated == nil {
+		return
+	}
+	estimate = updated
+
+	result := "ok"
+	if reason == "" {
+		logrus.Infof("预估 %s (%s %s %s) 持仓变化已确认", estimate.ID, estimate.Symbol, estimate.Side, estimate.ActionType)
+	} else {
+		result = "mismatch"
+		logrus.Warnf("预估 %s (%s %s %s) 执行结果不一致: %s", estimate.ID, estimate.Symbol, estimate.Side, estimate.ActionType, reason)
+
+		if wsManager := websocket.GetGlobalWebSocketManager(); wsManager != nil {
+			wsManager.BroadcastAlert(AlertTypeExecution, &ExecutionMismatchAlert{
+				EstimateID:   estimate.ID,
+				Symbol:       estimate.Symbol,
+				Side:         estimate.Side,
+				ActionType:   estimate.ActionType,
+				BeforeAmount: before.amount,
+				AfterAmount:  after.amount,
+				Reason:       reason,
+				Timestamp:    time.Now().UnixMilli(),
+			})
+		}
+
+		notify.Send(notify.EventFailure, i18n.T("notify.verify_mismatch.title"),
+			fmt.Sprintf("%s %s %s: %s", estimate.Symbol, estimate.Side, estimate.ActionType, reason), nil)
+	}
+	metrics.ExecutionVerifications.WithLabelValues(estimate.ActionType, result).Inc()
+
+	eventType := models.EstimateEventOrderFilled
+	if reason != "" {
+		eventType = models.EstimateEventOrderFailed
+	}
+	RecordEstimateEvent(estimate.ID, eventType, reason, map[string]interface{}{
+		"before_amount": before.amount,
+		"after_amount":  after.amount,
+	})
+	go utils.BroadcastSymbolEstimatesUpdate()
+}
+
+// positionChanged 判断持仓变化是否符合操作类型的预期
+func positionChanged(actionType string, before, after positionSnapshot) bool {
+	switch actionType {
+	case models.ActionTypeOpen:
+		// 出现新的交易
+		return after.found && (!before.found || after.tradeID != before.tradeID)
+	case models.ActionTypeAddition:
+		// 同一交易的持仓数量增加
+		return after.found && after.tradeID == before.tradeID && after.amount > before.amount+amountEpsilon
+	case models.ActionTypeTakeProfit, models.ActionTypeStopLoss:
+		// 交易被平仓或持仓数量减少
+		return before.found && (!after.found || after.tradeID != before.tradeID || after.amount < before.amount-amountEpsilon)
+	default:
+		return false
+	}
+}
+
+// matchTrade 查找交易对和方向匹配的未平仓交易
+func matchTrade(trades []models.TradePosition, pair, side string) *models.TradePosition {
+	isEstimateLong := side == types.PositionSideLong
+	for i := range trades {
+		trade := &trades[i]
+		if trade.Pair != pair || !trade.IsOpen {
+			continue
+		}
+		isLongPosition := trade.TradeDirection == "long" || !trade.IsShort
+		if isLongPosition == isEstimateLong {
+			return trade
+		}
+	}
+	return nil
+}
+
+// snapshotTrade 生成持仓快照
+func snapshotTrade(trade *models.TradePosition) positionSnapshot {
+	if trade == nil {
+		return positionSnapshot{}
+	}
+	return positionSnapshot{found: true, tradeID: trade.TradeId, amount: trade.Amount}
+}
@@ -0,0 +1,246 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/metrics"
+	"trading_assistant/pkg/notify"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FreqtradeHealthStatus Freqtrade实例健康状态
+type FreqtradeHealthStatus struct {
+	Bot                 string     `json:"bot"`
+	BaseURL             string     `json:"base_url"`
+	Healthy             bool       `json:"healthy"`
+	State               string     `json:"state,omitempty"` // show_config 返回的机器人状态
+	Strategy            string     `json:"strategy,omitempty"`
+	CircuitOpen         bool       `json:"circuit_open"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Error               string     `json:"error,omitempty"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`        // 熔断开始时间
+	QueuedEstimates     []string   `json:"queued_estimates,omitempty"` // 熔断期间满足触发条件、等待恢复后重试的预估
+	CheckedAt           time.Time  `json:"checked_at"`
+}
+
+// FreqtradeHealthMonitor Freqtrade健康检查和熔断器
+// 定时请求 /api/v1/ping 和 /api/v1/show_config，连续失败达到阈值时熔断该实例，
+// 熔断期间满足触发条件的预估保持监听状态并加入重试队列，恢复后由价格监控在下一轮重新评估并下单
+type FreqtradeHealthMonitor struct {
+	bots      *freqtrade.Registry
+	interval  time.Duration
+	threshold int
+	stopChan  chan struct{}
+
+	mu       sync.RWMutex
+	statuses map[string]*FreqtradeHealthStatus
+	queued   map[string]map[string]bool // 实例名称 -> 排队的预估ID
+}
+
+var GlobalFreqtradeHealth *FreqtradeHealthMonitor
+
+// InitFreqtradeHealthMonitor 初始化Freqtrade健康检查
+func InitFreqtradeHealthMonitor(bots *freqtrade.Registry) {
+	threshold := config.GlobalConfig.FreqtradeHealthFailures
+	if threshold <= 0 {
+		threshold = 1
+	}
+	GlobalFreqtradeHealth = &FreqtradeHealthMonitor{
+		bots:      bots,
+		interval:  config.GlobalConfig.FreqtradeHealthInterval,
+		threshold: threshold,
+		statuses:  make(map[string]*FreqtradeHealthStatus),
+		queued:    make(map[string]map[string]bool),
+	}
+}
+
+// Start 启动健康检查
+func (hm *FreqtradeHealthMonitor) Start() {
+	if hm.interval <= 0 || hm.bots == nil {
+		logrus.Info("Freqtrade健康检查未启用")
+		return
+	}
+	if hm.stopChan != nil {
+		return
+	}
+
+	hm.stopChan = make(chan struct{})
+	go hm.loop(hm.stopChan)
+	logrus.Infof("Freqtrade健康检查已启动，检查间隔: %v, 熔断阈值: 连续失败 %d 次", hm.interval, hm.threshold)
+}
+
+// Stop 停止健康检查
+func (hm *FreqtradeHealthMonitor) Stop() {
+	if hm.stopChan == nil {
+		return
+	}
+	close(hm.stopChan)
+	hm.stopChan = nil
+}
+
+// loop 定时检查所有实例
+func (hm *FreqtradeHealthMonitor) loop(stopChan chan struct{}) {
+	ticker := time.NewTicker(hm.interval)
+	defer ticker.Stop()
+
+	hm.CheckAll()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			hm.CheckAll()
+		}
+	}
+}
+
+// CheckAll 并发检查所有实例
+func (hm *FreqtradeHealthMonitor) CheckAll() {
+	var wg sync.WaitGroup
+	for _, bot := range hm.bots.All() {
+		wg.Add(1)
+		go func(bot *freqtrade.Controller) {
+			defer wg.Done()
+			hm.check(bot)
+		}(bot)
+	}
+	wg.Wait()
+}
+
+// check 检查单个实例并更新熔断状态
+func (hm *FreqtradeHealthMonitor) check(bot *freqtrade.Controller) {
+	ctx, cancel := context.WithTimeout(context.Background(), hm.interval)
+	defer cancel()
+
+	var showConfig *models.FreqtradeShowConfig
+	err := bot.Ping(ctx)
+	if err == nil {
+		showConfig, err = bot.ShowConfig(ctx)
+	}
+	if err == nil && showConfig.State != "" && showConfig.State != "running" {
+		err = fmt.Errorf("Freqtrade状态为 %s", showConfig.State)
+	}
+
+	hm.mu.Lock()
+	status, exists := hm.statuses[bot.Name]
+	if !exists {
+		status = &FreqtradeHealthStatus{Bot: bot.Name, BaseURL: bot.BaseUrl}
+		hm.statuses[bot.Name] = status
+	}
+	status.CheckedAt = time.Now()
+	if showConfig != nil {
+		status.State = showConfig.State
+		status.Strategy = showConfig.Strategy
+	}
+
+	var opened, closed bool
+	var queued int
+	if err != nil {
+		status.Healthy = false
+		status.Error = err.Error()
+		status.ConsecutiveFailures++
+		if !status.CircuitOpen && status.ConsecutiveFailures >= hm.threshold {
+			openedAt := status.CheckedAt
+			status.CircuitOpen = true
+			status.OpenedAt = &openedAt
+			opened = true
+		}
+	} else {
+		status.Healthy = true
+		status.Error = ""
+		status.ConsecutiveFailures = 0
+		if status.CircuitOpen {
+			status.CircuitOpen = false
+			status.OpenedAt = nil
+			queued = len(hm.queued[bot.Name])
+			delete(hm.queued, bot.Name)
+			closed = true
+		}
+	}
+	failures := status.ConsecutiveFailures
+	hm.mu.Unlock()
+
+	if err != nil {
+		logrus.Warnf("Freqtrade实例 %s 健康检查失败 (连续 %d 次): %v", bot.Name, failures, err)
+	}
+
+	if opened {
+		metrics.FreqtradeCircuitOpen.WithLabelValues(bot.Name).Set(1)
+		logrus.Errorf("Freqtrade实例 %s 已熔断，暂停下单", bot.Name)
+		notify.Send(notify.EventFreqtrade, i18n.T("notify.breaker_open.title"),
+			i18n.T("notify.breaker_open.message", bot.Name, failures, err), nil)
+	}
+	if closed {
+		metrics.FreqtradeCircuitOpen.WithLabelValues(bot.Name).Set(0)
+		logrus.Infof("Freqtrade实例 %s 已恢复，%d 个排队的预估将在下一轮监控中重试", bot.Name, queued)
+		notify.Send(notify.EventFreqtrade, i18n.T("notify.breaker_closed.title"),
+			i18n.T("notify.breaker_closed.message", bot.Name, queued), nil)
+	}
+}
+
+// IsOpen 实例是否处于熔断状态
+func (hm *FreqtradeHealthMonitor) IsOpen(botName string) bool {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+	status, exists := hm.statuses[botName]
+	return exists && status.CircuitOpen
+}
+
+// Queue 将熔断期间满足触发条件的预估加入重试队列
+func (hm *FreqtradeHealthMonitor) Queue(botName, estimateID string) {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	if hm.queued[botName] == nil {
+		hm.queued[botName] = make(map[string]bool)
+	}
+	hm.queued[botName][estimateID] = true
+}
+
+// GetStatuses 获取所有实例最近一次的健康状态，按实例名称排序
+func (hm *FreqtradeHealthMonitor) GetStatuses() []*FreqtradeHealthStatus {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+
+	statuses := make([]*FreqtradeHealthStatus, 0, len(hm.statuses))
+	for name, status := range hm.statuses {
+		snapshot := *status
+		snapshot.QueuedEstimates = make([]string, 0, len(hm.queued[name]))
+		for estimateID := range hm.queued[name] {
+			snapshot.QueuedEstimates = append(snapshot.QueuedEstimates, estimateID)
+		}
+		sort.Strings(snapshot.QueuedEstimates)
+		statuses = append(statuses, &snapshot)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Bot < statuses[j].Bot
+	})
+	return statuses
+}
+
+// checkFreqtradeCircuit 下单实例熔断时暂停触发，预估保持监听并加入重试队列
+func (pm *PriceMonitor) checkFreqtradeCircuit(estimate *models.PriceEstimate) bool {
+	if GlobalFreqtradeHealth == nil || config.GlobalConfig.DryRun {
+		return true
+	}
+
+	bot, err := pm.orderExecutor.bot(estimate)
+	if err != nil {
+		// 实例不存在时交给下单流程报错
+		return true
+	}
+	if !GlobalFreqtradeHealth.IsOpen(bot.Name) {
+		return true
+	}
+
+	GlobalFreqtradeHealth.Queue(bot.Name, estimate.ID)
+	pm.recordSkip(estimate, models.SkipReasonCircuitOpen, "Freqtrade实例 %s 熔断中，恢复后重试", bot.Name)
+	return false
+}
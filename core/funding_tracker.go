@@ -0,0 +1,170 @@
+package core
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/eventbus"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fundingSample 币种最近一次看到的资金费率和下次结算时间
+type fundingSample struct {
+	rate        float64
+	fundingTime int64 // 毫秒
+}
+
+// FundingTracker 持仓资金费跟踪器
+// 标记价格中的下次结算时间前移时，按结算前最后一次看到的资金费率为持仓缓存中的持仓累计资金费
+type FundingTracker struct {
+	mu      sync.Mutex
+	samples map[string]fundingSample // MarketID -> 最近采样
+}
+
+var (
+	globalFundingTracker *FundingTracker
+	fundingTrackerOnce   sync.Once
+)
+
+// GetFundingTracker 获取全局资金费跟踪器
+func GetFundingTracker() *FundingTracker {
+	fundingTrackerOnce.Do(func() {
+		globalFundingTracker = &FundingTracker{
+			samples: make(map[string]fundingSample),
+		}
+	})
+	return globalFundingTracker
+}
+
+// OnMarkPrices 采样主交易所的资金费率，发现结算时间已过时为持仓结算资金费
+func (ft *FundingTracker) OnMarkPrices(event *eventbus.Event) {
+	batch, ok := event.Payload.(*eventbus.MarkPriceBatch)
+	if !ok || !batch.Primary {
+		return
+	}
+
+	now := time.Now().UnixMilli()
+	settled := make(map[string]fundingSample)
+	ft.mu.Lock()
+	for symbol, price := range batch.Prices {
+		if price.FundingTime <= 0 {
+			continue
+		}
+		previous, exists := ft.samples[symbol]
+		if exists && price.FundingTime > previous.fundingTime && previous.fundingTime <= now {
+			settled[symbol] = previous
+		}
+		ft.samples[symbol] = fundingSample{rate: price.FundingRate, fundingTime: price.FundingTime}
+	}
+	ft.mu.Unlock()
+
+	if len(settled) > 0 {
+		ft.settle(settled, batch.Prices)
+	}
+}
+
+// settle 为结算币种的持仓累计资金费，多头在费率为正时支付，空头相反
+func (ft *FundingTracker) settle(settled map[string]fundingSample, prices map[string]*types.WatchMarkPrice) {
+	positions, err := redis.GlobalRedisClient.GetAllPositions()
+	if err != nil {
+		logrus.Warnf("获取持仓缓存失败，跳过资金费结算: %v", err)
+		return
+	}
+
+	for _, position := range positions {
+		sample, exists := settled[position.Symbol]
+		price := prices[position.Symbol]
+		if !exists || price == nil || price.MarkPrice <= 0 || position.Size <= 0 {
+			continue
+		}
+
+		amount := -position.Size * price.MarkPrice * sample.rate
+		if strings.EqualFold(position.Side, types.PositionSideShort) {
+			amount = -amount
+		}
+
+		funding, err := redis.GlobalRedisClient.AddFundingPayment(position.Symbol, position.Side, amount, sample.rate, sample.fundingTime)
+		if err != nil {
+			logrus.Errorf("记录 %s %s 资金费失败: %v", position.Symbol, position.Side, err)
+			continue
+		}
+		if funding != nil {
+			logrus.Infof("资金费结算: %s %s 费率 %.4f%%, 本次 %+.4f, 累计 %+.4f",
+				position.Symbol, position.Side, sample.rate*100, amount, funding.Total)
+		}
+	}
+}
+
+// OnPositions 持仓缓存刷新后清理已平仓持仓的资金费记录
+func (ft *FundingTracker) OnPositions(event *eventbus.Event) {
+	positions, ok := event.Payload.([]*models.Position)
+	if !ok {
+		return
+	}
+
+	open := make(map[string]bool, len(positions))
+	for _, position := range positions {
+		open[position.Symbol+":"+strings.ToUpper(position.Side)] = true
+	}
+
+	fundings, err := redis.GlobalRedisClient.GetAllPositionFunding()
+	if err != nil {
+		logrus.Warnf("%v", err)
+		return
+	}
+	for key, funding := range fundings {
+		if open[key] {
+			continue
+		}
+		if err := redis.GlobalRedisClient.DeletePositionFunding(funding.Symbol, funding.Side); err != nil {
+			logrus.Errorf("清理 %s 资金费记录失败: %v", key, err)
+		}
+	}
+}
+
+// AttachPositionFunding 为Freqtrade持仓附加本地跟踪的累计资金费，返回所有持仓的资金费合计
+func AttachPositionFunding(positions []models.TradePosition) float64 {
+	fundings, err := redis.GlobalRedisClient.GetAllPositionFunding()
+	if err != nil {
+		logrus.Warnf("%v", err)
+		return 0
+	}
+
+	total := 0.0
+	for i := range positions {
+		position := &positions[i]
+		side := types.PositionSideLong
+		if position.IsShort || position.TradeDirection == types.PositionSideShort {
+			side = types.PositionSideShort
+		}
+		key := utils.ConvertSymbolToMarketID(position.Pair) + ":" + strings.ToUpper(side)
+		if funding, exists := fundings[key]; exists {
+			position.FundingTracked = funding
+			total += funding.Total
+		}
+	}
+	return total
+}
+
+// OpenPositionFunding 所有未平仓持仓的累计资金费，按币种从支付最多到收到最多排序
+func OpenPositionFunding() ([]*models.PositionFunding, float64, error) {
+	fundings, err := redis.GlobalRedisClient.GetAllPositionFunding()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	list := make([]*models.PositionFunding, 0, len(fundings))
+	total := 0.0
+	for _, funding := range fundings {
+		list = append(list, funding)
+		total += funding.Total
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Total < list[j].Total })
+	return list, total, nil
+}
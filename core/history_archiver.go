@@ -0,0 +1,345 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/database"
+	dbmodels "trading_assistant/pkg/models"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm/clause"
+)
+
+// historyBatchSize 批量写入数据库的记录数
+const historyBatchSize = 200
+
+// archivableEstimateStatuses 已结束、可以归档的预估状态
+var archivableEstimateStatuses = map[string]bool{
+	models.EstimateStatusTriggered:         true,
+	models.EstimateStatusFailed:            true,
+	models.EstimateStatusVerified:          true,
+	models.EstimateStatusExecutionMismatch: true,
+	models.EstimateStatusExpired:           true,
+}
+
+// HistoryArchiver 将Redis中超过保留期的记录归档到MySQL，归档后从Redis删除
+// 归档已结束的预估、触发记录、Telegram指令审计和每日盈亏账本
+type HistoryArchiver struct {
+	enabled           bool
+	interval          time.Duration
+	estimateRetention time.Duration
+	triggerRetention  time.Duration
+	auditRetention    time.Duration
+	pnlRetention      time.Duration
+
+	stopChan chan struct{}
+}
+
+// GlobalHistoryArchiver 全局历史归档器
+var GlobalHistoryArchiver *HistoryArchiver
+
+// InitHistoryArchiver 初始化历史归档器
+func InitHistoryArchiver() {
+	GlobalHistoryArchiver = &HistoryArchiver{
+		enabled:           config.GlobalConfig.HistoryArchiveEnabled,
+		interval:          config.GlobalConfig.HistoryArchiveInterval,
+		estimateRetention: config.GlobalConfig.HistoryEstimateRetention,
+		triggerRetention:  config.GlobalConfig.HistoryTriggerRetention,
+		auditRetention:    config.GlobalConfig.HistoryAuditRetention,
+		pnlRetention:      config.GlobalConfig.HistoryPnLRetention,
+	}
+}
+
+// historyEnabled 是否启用了数据库历史存储
+func historyEnabled() bool {
+	return config.GlobalConfig.HistoryArchiveEnabled && database.GetDB() != nil
+}
+
+// Start 执行数据库迁移并启动定时归档
+func (a *HistoryArchiver) Start() {
+	if !a.enabled || a.interval <= 0 {
+		logrus.Info("历史归档未启用")
+		return
+	}
+	if a.stopChan != nil {
+		return
+	}
+	if err := database.RunMigrations(database.GetDB()); err != nil {
+		logrus.Errorf("数据库迁移失败，历史归档未启动: %v", err)
+		return
+	}
+
+	a.stopChan = make(chan struct{})
+	go a.loop(a.stopChan)
+	logrus.Infof("历史归档已启动，归档间隔: %v", a.interval)
+}
+
+// Stop 停止定时归档
+func (a *HistoryArchiver) Stop() {
+	if a.stopChan == nil {
+		return
+	}
+	close(a.stopChan)
+	a.stopChan = nil
+}
+
+// loop 定时归档
+func (a *HistoryArchiver) loop(stopChan chan struct{}) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	a.Archive()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			a.Archive()
+		}
+	}
+}
+
+// Archive 执行一次归档，保留期为0的记录类型不归档
+func (a *HistoryArchiver) Archive() {
+	now := time.Now()
+	if a.estimateRetention > 0 {
+		a.archiveEstimates(now.Add(-a.estimateRetention))
+	}
+	if a.triggerRetention > 0 {
+		a.archiveTriggers(now.Add(-a.triggerRetention))
+	}
+	if a.auditRetention > 0 {
+		a.archiveTelegramAudits(now.Add(-a.auditRetention))
+	}
+	if a.pnlRetention > 0 {
+		a.archivePnL(now.Add(-a.pnlRetention))
+	}
+}
+
+// archiveEstimates 归档在 cutoff 之前结束的价格预估
+func (a *HistoryArchiver) archiveEstimates(cutoff time.Time) {
+	estimates, err := redis.GlobalRedisClient.GetAllEstimates()
+	if err != nil {
+		logrus.Errorf("获取价格预估失败: %v", err)
+		return
+	}
+
+	var rows []dbmodels.EstimateHistory
+	for _, estimate := range estimates {
+		if !archivableEstimateStatuses[estimate.Status] || estimate.UpdatedAt.After(cutoff) {
+			continue
+		}
+		data, err := json.Marshal(estimate)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, dbmodels.EstimateHistory{
+			EstimateID:        estimate.ID,
+			Symbol:            estimate.Symbol,
+			Exchange:          estimate.Exchange,
+			Side:              estimate.Side,
+			ActionType:        estimate.ActionType,
+			TriggerType:       estimate.TriggerType,
+			TargetPrice:       estimate.TargetPrice,
+			Percentage:        estimate.Percentage,
+			Status:            estimate.Status,
+			ErrorMessage:      estimate.ErrorMessage,
+			Tag:               estimate.Tag,
+			Data:              data,
+			EstimateCreatedAt: estimate.CreatedAt,
+			EstimateUpdatedAt: estimate.UpdatedAt,
+		})
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	// 重复归档的记录忽略，Redis删除失败时下次归档不会产生重复数据
+	err = database.GetDB().Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, historyBatchSize).Error
+	if err != nil {
+		logrus.Errorf("归档价格预估失败: %v", err)
+		return
+	}
+	for _, row := range rows {
+		err := redis.GlobalRedisClient.DeletePriceEstimate(row.EstimateID)
+		if err != nil && !errors.Is(err, redis.ErrEstimateNotFound) {
+			logrus.Errorf("删除已归档的价格预估 %s 失败: %v", row.EstimateID, err)
+		}
+	}
+	logrus.Infof("已归档 %d 个价格预估", len(rows))
+}
+
+// archiveTriggers 归档在 cutoff 之前的触发记录，附带仍在Redis中的执行记录
+func (a *HistoryArchiver) archiveTriggers(cutoff time.Time) {
+	records, err := redis.GlobalRedisClient.GetExecutionLatencies(0)
+	if err != nil {
+		logrus.Errorf("%v", err)
+		return
+	}
+
+	// 记录新的在前，从末尾找出所有过期记录
+	count := 0
+	for i := len(records) - 1; i >= 0 && records[i].Timestamp < cutoff.UnixMilli(); i-- {
+		count++
+	}
+	if count == 0 {
+		return
+	}
+
+	rows := make([]dbmodels.TriggerEvent, 0, count)
+	for _, record := range records[len(records)-count:] {
+		row := dbmodels.TriggerEvent{
+			EstimateID:  record.EstimateID,
+			Symbol:      record.Symbol,
+			Exchange:    record.Exchange,
+			ActionType:  record.ActionType,
+			Result:      record.Result,
+			DetectionMs: record.DetectionMs,
+			ExecutionMs: record.ExecutionMs,
+			TotalMs:     record.TotalMs,
+			TriggeredAt: time.UnixMilli(record.Timestamp),
+		}
+		if execution, err := redis.GlobalRedisClient.GetEstimateExecution(record.EstimateID); err == nil && execution != nil {
+			row.Execution, _ = json.Marshal(execution)
+		}
+		rows = append(rows, row)
+	}
+
+	err = database.GetDB().Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, historyBatchSize).Error
+	if err != nil {
+		logrus.Errorf("归档触发记录失败: %v", err)
+		return
+	}
+	if err := redis.GlobalRedisClient.TrimOldestExecutionLatencies(count); err != nil {
+		logrus.Errorf("删除已归档的触发记录失败: %v", err)
+		return
+	}
+	logrus.Infof("已归档 %d 条触发记录", count)
+}
+
+// archiveTelegramAudits 归档在 cutoff 之前的Telegram指令审计记录
+func (a *HistoryArchiver) archiveTelegramAudits(cutoff time.Time) {
+	records, err := redis.GlobalRedisClient.GetTelegramAudits(0)
+	if err != nil {
+		logrus.Errorf("%v", err)
+		return
+	}
+
+	count := 0
+	for i := len(records) - 1; i >= 0 && records[i].Timestamp < cutoff.UnixMilli(); i-- {
+		count++
+	}
+	if count == 0 {
+		return
+	}
+
+	rows := make([]dbmodels.TelegramAuditHistory, 0, count)
+	for _, record := range records[len(records)-count:] {
+		rows = append(rows, dbmodels.TelegramAuditHistory{
+			ChatID:     record.ChatID,
+			UserID:     record.UserID,
+			Username:   record.Username,
+			Role:       record.Role,
+			Command:    record.Command,
+			Allowed:    record.Allowed,
+			Result:     record.Result,
+			ExecutedAt: time.UnixMilli(record.Timestamp),
+		})
+	}
+
+	err = database.GetDB().Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, historyBatchSize).Error
+	if err != nil {
+		logrus.Errorf("归档指令审计记录失败: %v", err)
+		return
+	}
+	if err := redis.GlobalRedisClient.TrimOldestTelegramAudits(count); err != nil {
+		logrus.Errorf("删除已归档的指令审计记录失败: %v", err)
+		return
+	}
+	logrus.Infof("已归档 %d 条指令审计记录", count)
+}
+
+// archivePnL 归档 cutoff 之前日期的盈亏账本，同一日期重复归档时覆盖
+func (a *HistoryArchiver) archivePnL(cutoff time.Time) {
+	cutoffDate := PnLDate(cutoff)
+	for _, source := range []string{models.PnLSourceFreqtrade, models.PnLSourcePaper} {
+		dates, err := redis.GlobalRedisClient.GetPnLLedgerDates(source)
+		if err != nil {
+			logrus.Errorf("%v", err)
+			continue
+		}
+
+		for _, date := range dates {
+			if date >= cutoffDate {
+				continue
+			}
+			entries, err := redis.GlobalRedisClient.GetPnLEntries(source, []string{date})
+			if err != nil {
+				logrus.Errorf("%v", err)
+				continue
+			}
+
+			rows := make([]dbmodels.PnLDaily, 0, len(entries))
+			for _, entry := range entries {
+				rows = append(rows, dbmodels.PnLDaily{
+					Source:      entry.Source,
+					Date:        entry.Date,
+					Symbol:      entry.Symbol,
+					RealizedPnl: entry.RealizedPnl,
+					Fees:        entry.Fees,
+					FundingFees: entry.FundingFees,
+					Trades:      entry.Trades,
+					Wins:        entry.Wins,
+					Losses:      entry.Losses,
+				})
+			}
+			if len(rows) > 0 {
+				err = database.GetDB().Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "source"}, {Name: "date"}, {Name: "symbol"}},
+					DoUpdates: clause.AssignmentColumns([]string{"realized_pnl", "fees", "funding_fees", "trades", "wins", "losses", "updated_at"}),
+				}).CreateInBatches(rows, historyBatchSize).Error
+				if err != nil {
+					logrus.Errorf("归档 %s %s 盈亏账本失败: %v", source, date, err)
+					continue
+				}
+			}
+			if err := redis.GlobalRedisClient.DeletePnLLedger(source, date); err != nil {
+				logrus.Errorf("删除已归档的 %s %s 盈亏账本失败: %v", source, date, err)
+				continue
+			}
+			logrus.Infof("已归档 %s %s 盈亏账本，%d 个币种", source, date, len(rows))
+		}
+	}
+}
+
+// LoadPnLEntries 获取若干日期内的账本记录，启用历史存储时合并已归档到数据库的日期
+func LoadPnLEntries(source string, dates []string) ([]*models.PnLEntry, error) {
+	entries, err := redis.GlobalRedisClient.GetPnLEntries(source, dates)
+	if err != nil || !historyEnabled() || len(dates) == 0 {
+		return entries, err
+	}
+
+	var rows []dbmodels.PnLDaily
+	if err := database.GetDB().Where("source = ? AND date IN ?", source, dates).Find(&rows).Error; err != nil {
+		logrus.Warnf("获取已归档的盈亏账本失败: %v", err)
+		return entries, nil
+	}
+	for _, row := range rows {
+		entries = append(entries, &models.PnLEntry{
+			Source:      row.Source,
+			Date:        row.Date,
+			Symbol:      row.Symbol,
+			RealizedPnl: row.RealizedPnl,
+			Fees:        row.Fees,
+			FundingFees: row.FundingFees,
+			Trades:      row.Trades,
+			Wins:        row.Wins,
+			Losses:      row.Losses,
+		})
+	}
+	return entries, nil
+}
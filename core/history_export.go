@@ -0,0 +1,120 @@
+package core
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/database"
+	dbmodels "trading_assistant/pkg/models"
+	"trading_assistant/pkg/redis"
+
+	"gorm.io/gorm"
+)
+
+// HistoryFilter 导出历史记录的过滤条件，时间区间为 [From, To)
+type HistoryFilter struct {
+	From   time.Time
+	To     time.Time
+	Symbol string
+}
+
+// match 判断记录是否满足过滤条件
+func (f HistoryFilter) match(symbol string, at time.Time) bool {
+	if f.Symbol != "" && !strings.EqualFold(symbol, f.Symbol) {
+		return false
+	}
+	return !at.Before(f.From) && at.Before(f.To)
+}
+
+// scope 数据库查询的过滤条件
+func (f HistoryFilter) scope(timeColumn string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where(timeColumn+" >= ? AND "+timeColumn+" < ?", f.From, f.To)
+		if f.Symbol != "" {
+			db = db.Where("symbol = ?", strings.ToUpper(f.Symbol))
+		}
+		return db
+	}
+}
+
+// ExportEstimates 按创建时间遍历区间内的价格预估，先输出Redis中的记录，启用历史存储时再输出已归档的记录
+func ExportEstimates(filter HistoryFilter, fn func(*models.PriceEstimate) error) error {
+	estimates, err := redis.GlobalRedisClient.GetAllEstimates()
+	if err != nil {
+		return err
+	}
+	sort.Slice(estimates, func(i, j int) bool {
+		return estimates[i].CreatedAt.Before(estimates[j].CreatedAt)
+	})
+	for _, estimate := range estimates {
+		if !filter.match(estimate.Symbol, estimate.CreatedAt) {
+			continue
+		}
+		if err := fn(estimate); err != nil {
+			return err
+		}
+	}
+
+	if !historyEnabled() {
+		return nil
+	}
+	var rows []dbmodels.EstimateHistory
+	return database.GetDB().Scopes(filter.scope("estimate_created_at")).Order("estimate_created_at").
+		FindInBatches(&rows, historyBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, row := range rows {
+				var estimate models.PriceEstimate
+				if err := json.Unmarshal(row.Data, &estimate); err != nil {
+					continue
+				}
+				if err := fn(&estimate); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// ExportTriggers 按触发时间遍历区间内的触发记录，先输出Redis中的记录，启用历史存储时再输出已归档的记录
+func ExportTriggers(filter HistoryFilter, fn func(*models.ExecutionLatency) error) error {
+	records, err := redis.GlobalRedisClient.GetExecutionLatencies(0)
+	if err != nil {
+		return err
+	}
+	// Redis中的记录新的在前，按时间正序输出
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if !filter.match(record.Symbol, time.UnixMilli(record.Timestamp)) {
+			continue
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	if !historyEnabled() {
+		return nil
+	}
+	var rows []dbmodels.TriggerEvent
+	return database.GetDB().Scopes(filter.scope("triggered_at")).Order("triggered_at").
+		FindInBatches(&rows, historyBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, row := range rows {
+				err := fn(&models.ExecutionLatency{
+					EstimateID:  row.EstimateID,
+					Symbol:      row.Symbol,
+					Exchange:    row.Exchange,
+					ActionType:  row.ActionType,
+					Result:      row.Result,
+					DetectionMs: row.DetectionMs,
+					ExecutionMs: row.ExecutionMs,
+					TotalMs:     row.TotalMs,
+					Timestamp:   row.TriggeredAt.UnixMilli(),
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
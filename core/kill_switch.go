@@ -0,0 +1,133 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/websocket"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrKillSwitchActive 紧急停止开启时拒绝下单
+var ErrKillSwitchActive = errors.New("紧急停止已开启，拒绝下单")
+
+// IsKillSwitchActive 紧急停止是否开启，状态保存在Redis中，多实例共享
+// 读取失败时按已开启处理，宁可暂停也不在状态未知时下单
+func IsKillSwitchActive() bool {
+	state, err := redis.GlobalRedisClient.GetKillSwitch()
+	if err != nil {
+		logrus.Errorf("读取紧急停止状态失败，暂停执行: %v", err)
+		return true
+	}
+	return state.Active
+}
+
+// ActivateKillSwitch 开启紧急停止，cancelEntries 为true时撤销所有Freqtrade实例未成交的限价开仓单
+func ActivateKillSwitch(ctx context.Context, reason, activatedBy string, cancelEntries bool) (*models.KillSwitchState, error) {
+	now := time.Now()
+	state := &models.KillSwitchState{
+		Active:      true,
+		Reason:      reason,
+		ActivatedBy: activatedBy,
+		ActivatedAt: &now,
+	}
+	// 先保存状态，确保撤单过程中不会再有新的下单
+	if err := redis.GlobalRedisClient.SetKillSwitch(state); err != nil {
+		return nil, err
+	}
+	logrus.Warnf("紧急停止已开启 (%s): %s", activatedBy, reason)
+
+	if cancelEntries {
+		state.CancelledOrders = cancelOpenEntryOrders(ctx)
+		if err := redis.GlobalRedisClient.SetKillSwitch(state); err != nil {
+			logrus.Errorf("保存撤单数量失败: %v", err)
+		}
+	}
+
+	message := i18n.T("notify.kill_switch.message", activatedBy, reason)
+	if cancelEntries {
+		message += i18n.T("notify.kill_switch.cancelled", state.CancelledOrders)
+	}
+	notify.Send(notify.EventRisk, i18n.T("notify.kill_switch.title"), message, nil)
+	websocket.GetGlobalWebSocketManager().BroadcastKillSwitch(state)
+	return state, nil
+}
+
+// ReleaseKillSwitch 解除紧急停止
+func ReleaseKillSwitch(releasedBy string) (*models.KillSwitchState, error) {
+	state, err := redis.GlobalRedisClient.GetKillSwitch()
+	if err != nil {
+		return nil, err
+	}
+	if !state.Active {
+		return state, nil
+	}
+
+	now := time.Now()
+	state.Active = false
+	state.ReleasedBy = releasedBy
+	state.ReleasedAt = &now
+	if err := redis.GlobalRedisClient.SetKillSwitch(state); err != nil {
+		return nil, err
+	}
+	logrus.Infof("紧急停止已解除 (%s)", releasedBy)
+
+	notify.Send(notify.EventRisk, i18n.T("notify.kill_release.title"), i18n.T("notify.kill_release.message", releasedBy), nil)
+	websocket.GetGlobalWebSocketManager().BroadcastKillSwitch(state)
+	return state, nil
+}
+
+// cancelOpenEntryOrders 撤销所有Freqtrade实例未成交的限价开仓单，返回撤销数量
+func cancelOpenEntryOrders(ctx context.Context) int {
+	if freqtrade.GlobalRegistry == nil {
+		return 0
+	}
+
+	cancelled := 0
+	for _, bot := range freqtrade.GlobalRegistry.All() {
+		trades, err := bot.GetTradeStatus(ctx)
+		if err != nil {
+			logrus.Errorf("获取Freqtrade实例 %s 持仓失败，无法撤销开仓单: %v", bot.Name, err)
+			continue
+		}
+		for i := range trades {
+			if !hasOpenLimitEntry(&trades[i]) {
+				continue
+			}
+			if err := bot.CancelOpenOrder(ctx, trades[i].TradeId); err != nil {
+				logrus.Errorf("撤销Freqtrade实例 %s 交易 %d (%s) 的开仓单失败: %v", bot.Name, trades[i].TradeId, trades[i].Pair, err)
+				continue
+			}
+			cancelled++
+		}
+	}
+	return cancelled
+}
+
+// hasOpenLimitEntry 交易是否有未成交的限价开仓单（做多为买单，做空为卖单）
+func hasOpenLimitEntry(trade *models.TradePosition) bool {
+	if !trade.HasOpenOrders {
+		return false
+	}
+	entrySide := "buy"
+	if trade.IsShort {
+		entrySide = "sell"
+	}
+	for _, order := range trade.Orders {
+		if order.IsOpen && order.FtOrderSide == entrySide && order.OrderType == "limit" {
+			return true
+		}
+	}
+	return false
+}
+
+// killSwitchSnapshot 紧急停止状态初始数据
+func killSwitchSnapshot() (interface{}, error) {
+	return redis.GlobalRedisClient.GetKillSwitch()
+}
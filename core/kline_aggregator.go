@@ -0,0 +1,163 @@
+package core
+
+import (
+	"sync"
+	"time"
+	"trading_assistant/pkg/eventbus"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/redis"
+)
+
+// klineBaseTimeframe 本地聚合使用的源K线周期
+const klineBaseTimeframe = "1m"
+
+// KlineAggregator K线聚合器
+// 由已保存的1m K线在本地生成 5m/15m/1h/4h/1d 等周期的K线，保存到同一个有序集合，
+// 并以 Synthetic 标记发布已收盘的K线，订阅者无需区分来源
+type KlineAggregator struct {
+	store      *redis.Client
+	exchangeID string
+	timeframes []string
+	steps      map[string]int64 // 周期 -> 毫秒数
+	retention  time.Duration
+
+	mu          sync.Mutex
+	lastEmitted map[string]int64 // symbol:timeframe -> 最近发布的已收盘K线开盘时间
+}
+
+// NewKlineAggregator 创建K线聚合器，timeframes 需先经过 canAggregateTimeframe 过滤
+func NewKlineAggregator(store *redis.Client, exchangeID string, timeframes []string, retention time.Duration) *KlineAggregator {
+	steps := make(map[string]int64, len(timeframes))
+	for _, timeframe := range timeframes {
+		step, _ := timeframeDuration(timeframe)
+		steps[timeframe] = step.Milliseconds()
+	}
+
+	return &KlineAggregator{
+		store:       store,
+		exchangeID:  exchangeID,
+		timeframes:  timeframes,
+		steps:       steps,
+		retention:   retention,
+		lastEmitted: make(map[string]int64),
+	}
+}
+
+// canAggregateTimeframe 判断周期是否可以由1m K线聚合
+// 分钟、小时和日线按UTC时间对齐；周线和月线的起点与交易所规则有关，仍直接拉取
+func canAggregateTimeframe(timeframe string) bool {
+	if timeframe == klineBaseTimeframe {
+		return false
+	}
+	step, err := timeframeDuration(timeframe)
+	if err != nil || step%time.Minute != 0 {
+		return false
+	}
+	switch timeframe[len(timeframe)-1] {
+	case 'm', 'h', 'd':
+		return true
+	default:
+		return false
+	}
+}
+
+// Aggregate 用新保存的1m K线重建所覆盖的各周期K线
+// 每个受影响的周期K线都从存储中读取完整的1m数据重新计算，重复拉取的1m K线不会导致成交量重复累加
+func (ka *KlineAggregator) Aggregate(symbol string, minutes []*types.Kline) error {
+	if len(minutes) == 0 {
+		return nil
+	}
+	ka.mu.Lock()
+	defer ka.mu.Unlock()
+
+	first := minutes[0].Timestamp
+	last := minutes[len(minutes)-1].Timestamp
+	now := time.Now().UnixMilli()
+
+	for _, timeframe := range ka.timeframes {
+		step := ka.steps[timeframe]
+		from := first - first%step
+		to := last - last%step + step
+
+		source, err := ka.store.GetKlinesBetween(symbol, klineBaseTimeframe, from, to)
+		if err != nil {
+			return err
+		}
+		candles := buildCandles(symbol, timeframe, step, source, now)
+		if len(candles) == 0 {
+			continue
+		}
+		if err := ka.store.SaveKlines(symbol, timeframe, candles, ka.retention); err != nil {
+			return err
+		}
+
+		// 只发布新收盘的K线，未收盘的K线只保存
+		key := symbol + ":" + timeframe
+		closed := make([]*types.Kline, 0, len(candles))
+		for _, candle := range candles {
+			if candle.IsClosed && candle.Timestamp > ka.lastEmitted[key] {
+				closed = append(closed, candle)
+			}
+		}
+		if len(closed) == 0 {
+			continue
+		}
+		ka.lastEmitted[key] = closed[len(closed)-1].Timestamp
+		eventbus.GetBus().Publish(eventbus.TopicKline, ka.exchangeID, &eventbus.KlineBatch{
+			Symbol:    symbol,
+			Timeframe: timeframe,
+			Klines:    closed,
+			Synthetic: true,
+		})
+	}
+	return nil
+}
+
+// buildCandles 按周期起点分组合并1m K线，source 需按开盘时间正序
+// 周期结束时间已过，且已有该周期最后一分钟或之后的1m K线时视为已收盘
+func buildCandles(symbol, timeframe string, step int64, source []*types.Kline, now int64) []*types.Kline {
+	if len(source) == 0 {
+		return nil
+	}
+	minute := time.Minute.Milliseconds()
+	latest := source[len(source)-1].Timestamp
+
+	var candles []*types.Kline
+	var current *types.Kline
+	var lastMinute int64
+	finish := func() {
+		if current == nil {
+			return
+		}
+		end := current.Timestamp + step
+		current.IsClosed = end <= now && (lastMinute == end-minute || end <= latest)
+		candles = append(candles, current)
+	}
+
+	for _, kline := range source {
+		start := kline.Timestamp - kline.Timestamp%step
+		if current == nil || current.Timestamp != start {
+			finish()
+			current = &types.Kline{
+				Symbol:    symbol,
+				Timeframe: timeframe,
+				Timestamp: start,
+				Open:      kline.Open,
+				High:      kline.High,
+				Low:       kline.Low,
+				Close:     kline.Close,
+			}
+		}
+		if kline.High > current.High {
+			current.High = kline.High
+		}
+		if kline.Low < current.Low {
+			current.Low = kline.Low
+		}
+		current.Close = kline.Close
+		current.Volume += kline.Volume
+		lastMinute = kline.Timestamp
+	}
+	finish()
+	return candles
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"testing"
+	"time"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// minuteKlines 从 start 开始生成连续的1m K线，第 i 根的价格为 base+i，成交量为1
+func minuteKlines(start int64, count int, base float64) []*types.Kline {
+	minute := time.Minute.Milliseconds()
+	klines := make([]*types.Kline, 0, count)
+	for i := 0; i < count; i++ {
+		price := base + float64(i)
+		klines = append(klines, &types.Kline{
+			Timestamp: start + int64(i)*minute,
+			Open:      price,
+			High:      price + 0.5,
+			Low:       price - 0.5,
+			Close:     price + 0.25,
+			Volume:    1,
+		})
+	}
+	return klines
+}
+
+func TestCanAggregateTimeframe(t *testing.T) {
+	tests := []struct {
+		timeframe string
+		want      bool
+	}{
+		{"1m", false},
+		{"5m", true},
+		{"15m", true},
+		{"1h", true},
+		{"4h", true},
+		{"1d", true},
+		{"1w", false},
+		{"1M", false},
+		{"", false},
+		{"abc", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timeframe, func(t *testing.T) {
+			if got := canAggregateTimeframe(tt.timeframe); got != tt.want {
+				t.Errorf("canAggregateTimeframe(%q) = %v，期望 %v", tt.timeframe, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildCandles(t *testing.T) {
+	minute := time.Minute.Milliseconds()
+	step := 5 * minute
+	start := int64(1700000100000) // 5分钟对齐
+	gapped := append(minuteKlines(start, 3, 100), minuteKlines(start+4*minute, 1, 103)...)
+
+	tests := []struct {
+		name   string
+		source []*types.Kline
+		now    int64
+		want   []types.Kline
+	}{
+		{
+			name:   "无数据",
+			source: nil,
+			now:    start + step,
+		},
+		{
+			name:   "完整周期已收盘",
+			source: minuteKlines(start, 5, 100),
+			now:    start + step,
+			want: []types.Kline{
+				{Timestamp: start, Open: 100, High: 104.5, Low: 99.5, Close: 104.25, Volume: 5, IsClosed: true},
+			},
+		},
+		{
+			name:   "周期未结束",
+			source: minuteKlines(start, 3, 100),
+			now:    start + 3*minute,
+			want: []types.Kline{
+				{Timestamp: start, Open: 100, High: 102.5, Low: 99.5, Close: 102.25, Volume: 3},
+			},
+		},
+		{
+			name:   "时间已过但缺少最后一分钟",
+			source: minuteKlines(start, 4, 100),
+			now:    start + 2*step,
+			want: []types.Kline{
+				{Timestamp: start, Open: 100, High: 103.5, Low: 99.5, Close: 103.25, Volume: 4},
+			},
+		},
+		{
+			name:   "中间缺少一分钟仍收盘",
+			source: gapped,
+			now:    start + step,
+			want: []types.Kline{
+				{Timestamp: start, Open: 100, High: 103.5, Low: 99.5, Close: 103.25, Volume: 4, IsClosed: true},
+			},
+		},
+		{
+			name:   "跨周期",
+			source: minuteKlines(start+3*minute, 4, 100),
+			now:    start + step + 2*minute,
+			want: []types.Kline{
+				{Timestamp: start, Open: 100, High: 101.5, Low: 99.5, Close: 101.25, Volume: 2, IsClosed: true},
+				{Timestamp: start + step, Open: 102, High: 103.5, Low: 101.5, Close: 103.25, Volume: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candles := buildCandles("BTCUSDT", "5m", step, tt.source, tt.now)
+			if len(candles) != len(tt.want) {
+				t.Fatalf("K线数量 = %d，期望 %d", len(candles), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := candles[i]
+				want.Symbol, want.Timeframe = "BTCUSDT", "5m"
+				if *got != want {
+					t.Errorf("第 %d 根K线 = %+v，期望 %+v", i, *got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
+	"trading_assistant/pkg/exchange_factory"
+	"trading_assistant/pkg/metrics"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// klineFetchLimit 单次请求的K线数量
+const klineFetchLimit = 1000
+
+// KlineManager K线历史管理器
+// 启动时为选中币种回填N天K线并保存到Redis有序集合，之后定时增量拉取最新K线
+// 启用本地聚合时只拉取1m K线，可聚合的周期由 KlineAggregator 生成
+type KlineManager struct {
+	exchangeClient exchange_factory.ExchangeInterface
+	store          *redis.Client
+	timeframes     []string         // 需要从交易所拉取的周期
+	aggregator     *KlineAggregator // 未启用本地聚合时为nil
+	backfillDays   int
+	updateInterval time.Duration
+
+	mu      sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running bool
+}
+
+// NewKlineManager 创建K线历史管理器
+func NewKlineManager(exchangeClient exchange_factory.ExchangeInterface) *KlineManager {
+	cfg := config.GlobalConfig
+
+	timeframes := make([]string, 0, len(cfg.KlineTimeframes))
+	var aggregated []string
+	for _, timeframe := range cfg.KlineTimeframes {
+		if _, err := timeframeDuration(timeframe); err != nil {
+			logrus.Warnf("忽略无效的K线周期 %s: %v", timeframe, err)
+			continue
+		}
+		if cfg.KlineAggregate && canAggregateTimeframe(timeframe) {
+			aggregated = append(aggregated, timeframe)
+			continue
+		}
+		if timeframe != klineBaseTimeframe {
+			timeframes = append(timeframes, timeframe)
+		}
+	}
+	if len(aggregated) > 0 || slices.Contains(cfg.KlineTimeframes, klineBaseTimeframe) {
+		timeframes = append([]string{klineBaseTimeframe}, timeframes...)
+	}
+
+	interval := cfg.KlineUpdateInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	km := &KlineManager{
+		exchangeClient: exchangeClient,
+		store:          ExchangeStore(exchangeClient.GetID()),
+		timeframes:     timeframes,
+		backfillDays:   cfg.KlineBackfillDays,
+		updateInterval: interval,
+	}
+	if len(aggregated) > 0 {
+		retention := time.Duration(cfg.KlineBackfillDays) * 24 * time.Hour
+		km.aggregator = NewKlineAggregator(km.store, exchangeClient.GetID(), aggregated, retention)
+	}
+	return km
+}
+
+// Start 启动K线回填和增量更新
+func (km *KlineManager) Start() {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	if km.running || len(km.timeframes) == 0 {
+		return
+	}
+	km.ctx, km.cancel = context.WithCancel(context.Background())
+	km.running = true
+
+	go km.run(km.ctx)
+	logrus.Infof("K线历史管理器已启动，周期: %v，回填天数: %d", km.timeframes, km.backfillDays)
+	if km.aggregator != nil {
+		logrus.Infof("K线本地聚合已启用，由1m K线生成: %v", km.aggregator.timeframes)
+	}
+}
+
+// Stop 停止K线更新
+func (km *KlineManager) Stop() {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	if !km.running {
+		return
+	}
+	km.cancel()
+	km.running = false
+	logrus.Info("K线历史管理器已停止")
+}
+
+// run 主运行循环，每轮对所有选中币种从最新已保存的K线继续拉取
+func (km *KlineManager) run(ctx context.Context) {
+	ticker := time.NewTicker(km.updateInterval)
+	defer ticker.Stop()
+
+	km.syncOnce(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			km.syncOnce(ctx)
+		}
+	}
+}
+
+// syncOnce 同步一轮所有选中币种和周期的K线
+func (km *KlineManager) syncOnce(ctx context.Context) {
+	symbols, err := redis.GlobalRedisClient.GetSelectedCoinMarketIDs()
+	if err != nil {
+		logrus.Errorf("获取选中币种列表失败: %v", err)
+		return
+	}
+
+	for _, symbol := range symbols {
+		for _, timeframe := range km.timeframes {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := km.syncSymbol(ctx, symbol, timeframe); err != nil {
+				logrus.Warnf("同步 %s %s K线失败: %v", symbol, timeframe, err)
+			}
+		}
+	}
+}
+
+// syncSymbol 从最新已保存的K线（没有时从回填起点）开始分页拉取到当前时间
+func (km *KlineManager) syncSymbol(ctx context.Context, symbol, timeframe string) error {
+	step, err := timeframeDuration(timeframe)
+	if err != nil {
+		return err
+	}
+	retention := time.Duration(km.backfillDays) * 24 * time.Hour
+
+	since, err := km.store.GetLatestKlineTime(symbol, timeframe)
+	if err != nil {
+		return err
+	}
+	if since == 0 {
+		since = time.Now().Add(-retention).UnixMilli()
+	}
+
+	exchangeID := km.exchangeClient.GetID()
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		klines, err := km.exchangeClient.FetchKlines(fetchCtx, symbol, timeframe, since, klineFetchLimit, nil)
+		cancel()
+		metrics.ExchangeRequests.WithLabelValues(exchangeID, "klines", metrics.ResultLabel(err)).Inc()
+		if err != nil {
+			return err
+		}
+		if len(klines) == 0 {
+			return nil
+		}
+
+		if err := km.store.SaveKlines(symbol, timeframe, klines, retention); err != nil {
+			return err
+		}
+		eventbus.GetBus().Publish(eventbus.TopicKline, exchangeID, &eventbus.KlineBatch{
+			Symbol:    symbol,
+			Timeframe: timeframe,
+			Klines:    klines,
+		})
+		if timeframe == klineBaseTimeframe && km.aggregator != nil {
+			if err := km.aggregator.Aggregate(symbol, klines); err != nil {
+				logrus.Warnf("聚合 %s K线失败: %v", symbol, err)
+			}
+		}
+
+		// 最后一根K线未收盘或不足一页时说明已追上最新数据
+		last := klines[len(klines)-1]
+		if len(klines) < klineFetchLimit || last.Timestamp+step.Milliseconds() > time.Now().UnixMilli() {
+			return nil
+		}
+		since = last.Timestamp + step.Milliseconds()
+	}
+}
+
+// timeframeDuration 将K线周期转换为时长，如 5m、1h、1d、1w、1M
+func timeframeDuration(timeframe string) (time.Duration, error) {
+	if len(timeframe) < 2 {
+		return 0, fmt.Errorf("无效的K线周期: %s", timeframe)
+	}
+
+	value, err := strconv.Atoi(timeframe[:len(timeframe)-1])
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("无效的K线周期: %s", timeframe)
+	}
+
+	unit := time.Duration(value)
+	switch timeframe[len(timeframe)-1] {
+	case 'm':
+		return unit * time.Minute, nil
+	case 'h':
+		return unit * time.Hour, nil
+	case 'd':
+		return unit * 24 * time.Hour, nil
+	case 'w':
+		return unit * 7 * 24 * time.Hour, nil
+	case 'M':
+		return unit * 30 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("无效的K线周期: %s", timeframe)
+	}
+}
@@ -0,0 +1,260 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// instanceID 当前实例标识，hostname:pid
+var instanceID = func() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+}()
+
+// leaderTask 仅在主节点运行的任务
+type leaderTask struct {
+	name    string
+	start   func()
+	stop    func()
+	running bool
+}
+
+// LeaderStatus 主节点选举状态
+type LeaderStatus struct {
+	Enabled       bool     `json:"enabled"`
+	Instance      string   `json:"instance"`
+	Leader        bool     `json:"leader"`
+	Since         int64    `json:"since,omitempty"`          // 成为主节点的时间（毫秒）
+	CurrentLeader string   `json:"current_leader,omitempty"` // 当前持有租约的实例
+	LeaseTTL      int64    `json:"lease_ttl,omitempty"`      // 租约剩余有效期（毫秒）
+	Tasks         []string `json:"tasks"`                    // 仅在主节点运行的任务
+}
+
+// LeaderElector 基于Redis租约的主节点选举
+// 多个实例同时运行时只有持有租约的主节点运行价格监控和下单等任务，所有实例都提供只读的HTTP和WebSocket服务
+// 主节点停止续期后租约过期，其他实例在下一次检查时接管
+type LeaderElector struct {
+	enabled       bool
+	leaseTTL      time.Duration
+	renewInterval time.Duration
+
+	mu       sync.Mutex
+	leader   bool
+	since    time.Time
+	tasks    []*leaderTask
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+// GlobalLeaderElector 全局主节点选举器
+var GlobalLeaderElector *LeaderElector
+
+// InitLeaderElector 初始化主节点选举器
+func InitLeaderElector() {
+	leaseTTL := config.GlobalConfig.HALeaseTTL
+	renewInterval := config.GlobalConfig.HARenewInterval
+	// 续期间隔必须小于租约有效期，否则租约会在续期前过期
+	if renewInterval <= 0 || renewInterval >= leaseTTL {
+		renewInterval = leaseTTL / 3
+	}
+
+	GlobalLeaderElector = &LeaderElector{
+		enabled:       config.GlobalConfig.HAEnabled,
+		leaseTTL:      leaseTTL,
+		renewInterval: renewInterval,
+	}
+}
+
+// Start 启动选举，未启用高可用模式时本实例直接作为主节点
+func (le *LeaderElector) Start() {
+	if !le.enabled {
+		le.mu.Lock()
+		le.becomeLeader()
+		le.mu.Unlock()
+		return
+	}
+	if le.stopChan != nil {
+		return
+	}
+
+	le.stopChan = make(chan struct{})
+	le.done = make(chan struct{})
+	logrus.Infof("高可用模式已启用，实例: %s, 租约有效期: %v, 续期间隔: %v", instanceID, le.leaseTTL, le.renewInterval)
+
+	// 启动时立即参与一次选举，单实例运行时无需等待
+	le.elect()
+	go le.loop(le.stopChan, le.done)
+}
+
+// Stop 停止选举，停止所有主节点任务并释放租约，其他实例可以立即接管
+func (le *LeaderElector) Stop() {
+	if le.stopChan != nil {
+		close(le.stopChan)
+		<-le.done
+		le.stopChan = nil
+	}
+
+	le.mu.Lock()
+	wasLeader := le.leader
+	le.stepDown()
+	le.mu.Unlock()
+
+	if le.enabled && wasLeader {
+		if err := redis.GlobalRedisClient.ReleaseLeaderLease(instanceID); err != nil {
+			logrus.Errorf("释放主节点租约失败: %v", err)
+		}
+	}
+}
+
+// AddTask 注册仅在主节点运行的任务，本实例已是主节点时立即启动
+func (le *LeaderElector) AddTask(name string, start, stop func()) {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+
+	task := &leaderTask{name: name, start: start, stop: stop}
+	le.tasks = append(le.tasks, task)
+	if le.leader {
+		task.start()
+		task.running = true
+	}
+}
+
+// RemoveTask 停止并移除主节点任务
+func (le *LeaderElector) RemoveTask(name string) {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+
+	for i, task := range le.tasks {
+		if task.name != name {
+			continue
+		}
+		if task.running {
+			task.stop()
+		}
+		le.tasks = append(le.tasks[:i], le.tasks[i+1:]...)
+		return
+	}
+}
+
+// IsLeader 本实例是否为主节点
+func (le *LeaderElector) IsLeader() bool {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+	return le.leader
+}
+
+// GetStatus 获取选举状态
+func (le *LeaderElector) GetStatus() LeaderStatus {
+	le.mu.Lock()
+	status := LeaderStatus{
+		Enabled:  le.enabled,
+		Instance: instanceID,
+		Leader:   le.leader,
+		Tasks:    make([]string, 0, len(le.tasks)),
+	}
+	if le.leader {
+		status.Since = le.since.UnixMilli()
+	}
+	for _, task := range le.tasks {
+		status.Tasks = append(status.Tasks, task.name)
+	}
+	le.mu.Unlock()
+
+	if le.enabled {
+		owner, ttl, err := redis.GlobalRedisClient.GetLeaderLease()
+		if err != nil {
+			logrus.Warnf("获取主节点租约失败: %v", err)
+		}
+		status.CurrentLeader = owner
+		status.LeaseTTL = ttl.Milliseconds()
+	}
+	return status
+}
+
+// loop 定时续期或竞选
+func (le *LeaderElector) loop(stopChan, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(le.renewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			le.elect()
+		}
+	}
+}
+
+// elect 主节点续期租约，续期失败时立即降级；从节点尝试获取租约
+func (le *LeaderElector) elect() {
+	if le.IsLeader() {
+		renewed, err := redis.GlobalRedisClient.RenewLeaderLease(instanceID, le.leaseTTL)
+		if err == nil && renewed {
+			return
+		}
+
+		le.mu.Lock()
+		le.stepDown()
+		le.mu.Unlock()
+		logrus.Errorf("主节点租约续期失败，本实例 %s 已降级为从节点: renewed=%v, err=%v", instanceID, renewed, err)
+		notify.Send(notify.EventFailover, i18n.T("notify.step_down.title"),
+			i18n.T("notify.step_down.message", instanceID),
+			map[string]interface{}{"instance": instanceID})
+		return
+	}
+
+	acquired, err := redis.GlobalRedisClient.AcquireLeaderLease(instanceID, le.leaseTTL)
+	if err != nil {
+		logrus.Warnf("竞选主节点失败: %v", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	le.mu.Lock()
+	le.becomeLeader()
+	le.mu.Unlock()
+	logrus.Warnf("本实例 %s 已成为主节点", instanceID)
+	notify.Send(notify.EventFailover, i18n.T("notify.leader.title"),
+		i18n.T("notify.leader.message", instanceID),
+		map[string]interface{}{"instance": instanceID})
+}
+
+// becomeLeader 成为主节点并按注册顺序启动任务（调用方需持有锁）
+func (le *LeaderElector) becomeLeader() {
+	if le.leader {
+		return
+	}
+	le.leader = true
+	le.since = time.Now()
+	for _, task := range le.tasks {
+		task.start()
+		task.running = true
+	}
+}
+
+// stepDown 按注册的相反顺序停止任务（调用方需持有锁）
+func (le *LeaderElector) stepDown() {
+	if !le.leader {
+		return
+	}
+	le.leader = false
+	for i := len(le.tasks) - 1; i >= 0; i-- {
+		if task := le.tasks[i]; task.running {
+			task.stop()
+			task.running = false
+		}
+	}
+}
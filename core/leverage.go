@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/exchange_factory"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// leverageRequestTimeout 设置杠杆请求超时时间
+const leverageRequestTimeout = 10 * time.Second
+
+// appliedLeverage 已在交易所生效的杠杆设置，symbol -> "杠杆|保证金模式"，避免重复调用
+var appliedLeverage sync.Map
+
+// ApplyLeverage 在主交易所上设置交易对的杠杆和保证金模式（Freqtrade在主交易所下单）
+// marginMode 为空时只设置杠杆；交易所不支持时返回 NotSupported 错误
+func ApplyLeverage(symbol string, leverage int, marginMode string) error {
+	if leverage <= 0 {
+		return fmt.Errorf("杠杆倍数必须大于0")
+	}
+
+	client, exists := ExchangeClient("")
+	if !exists {
+		return fmt.Errorf("主交易所客户端未初始化")
+	}
+
+	marketID := ResolveMarketID("", symbol)
+	key := fmt.Sprintf("%d|%s", leverage, marginMode)
+	if applied, ok := appliedLeverage.Load(marketID); ok && applied == key {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), leverageRequestTimeout)
+	defer cancel()
+
+	// 先切换保证金模式，部分交易所切换模式时会同时设置杠杆
+	if marginMode != "" {
+		setter, err := exchange_factory.AsMarginModeSetter(client)
+		if err != nil {
+			return err
+		}
+		if err := setter.SetMarginMode(ctx, marketID, marginMode, leverage); err != nil {
+			return fmt.Errorf("设置保证金模式失败: %w", err)
+		}
+	}
+
+	setter, err := exchange_factory.AsLeverageSetter(client)
+	if err != nil {
+		return err
+	}
+	if err := setter.SetLeverage(ctx, marketID, leverage); err != nil {
+		return fmt.Errorf("设置杠杆失败: %w", err)
+	}
+
+	appliedLeverage.Store(marketID, key)
+	logrus.Infof("已在 %s 设置 %s 杠杆 %dx %s", client.GetName(), marketID, leverage, marginMode)
+	return nil
+}
+
+// ApplyEstimateLeverage 在触发前将开仓预估的杠杆和保证金模式同步到交易所
+// 交易所不支持时静默跳过，失败只记录日志，由Freqtrade下单时的杠杆兜底
+func ApplyEstimateLeverage(estimate *models.PriceEstimate) {
+	if estimate.ActionType != models.ActionTypeOpen || estimate.Leverage <= 0 {
+		return
+	}
+	if client, exists := ExchangeClient(""); !exists || client.GetMarketType() == types.MarketTypeSpot {
+		return
+	}
+
+	err := ApplyLeverage(estimate.Symbol, estimate.Leverage, estimate.MarginMode)
+	switch {
+	case err == nil:
+	case exchanges.IsNotSupported(err):
+		logrus.Debugf("跳过杠杆同步: %v", err)
+	default:
+		logrus.Warnf("同步 %s 杠杆到交易所失败: %v", estimate.Symbol, err)
+	}
+}
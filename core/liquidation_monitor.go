@@ -0,0 +1,215 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchange_factory"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/websocket"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AlertTypeLiquidation 强平告警类型
+const AlertTypeLiquidation = "liquidation"
+
+// LiquidationMonitor 强平订单监控
+// 订阅选中币种的强平推送，按分钟和方向汇总成交额保存到Redis；
+// 单分钟单方向成交额超过阈值时推送告警，用于判断轧空/多杀多行情。只在主节点运行
+type LiquidationMonitor struct {
+	exchangeID string
+	streamer   exchange_factory.LiquidationStreamer
+	store      *redis.Client
+	threshold  float64
+	retention  time.Duration
+	symbols    []string // 固定监控的币种，为空时监控所有选中币种
+
+	mu      sync.Mutex
+	buckets map[string]*models.LiquidationBucket // symbol -> 当前分钟汇总
+	alerted map[string]int64                     // symbol:side -> 最近告警的分钟
+	cancel  context.CancelFunc
+	running bool
+}
+
+var GlobalLiquidationMonitor *LiquidationMonitor
+
+// InitLiquidationMonitor 初始化强平监控，未启用或交易所不支持强平推送时不创建
+func InitLiquidationMonitor(exchangeClient exchange_factory.ExchangeInterface) {
+	cfg := config.GlobalConfig
+	if !cfg.LiquidationMonitorEnabled {
+		return
+	}
+
+	streamer, err := exchange_factory.AsLiquidationStreamer(exchangeClient)
+	if err != nil {
+		logrus.Warnf("%v，跳过强平监控", err)
+		return
+	}
+
+	symbols := make([]string, 0, len(cfg.LiquidationSymbols))
+	for _, symbol := range cfg.LiquidationSymbols {
+		symbols = append(symbols, ResolveMarketID(exchangeClient.GetID(), symbol))
+	}
+	GlobalLiquidationMonitor = &LiquidationMonitor{
+		exchangeID: strings.ToLower(exchangeClient.GetID()),
+		streamer:   streamer,
+		store:      ExchangeStore(exchangeClient.GetID()),
+		threshold:  cfg.LiquidationAlertNotional,
+		retention:  cfg.LiquidationHistoryRetention,
+		symbols:    symbols,
+		buckets:    make(map[string]*models.LiquidationBucket),
+		alerted:    make(map[string]int64),
+	}
+}
+
+// Start 启动强平监控
+func (lm *LiquidationMonitor) Start() {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	if lm.running {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	lm.cancel = cancel
+	lm.running = true
+
+	stream := &symbolStream{
+		name:    "强平推送",
+		symbols: lm.symbols,
+		subscribe: func(ctx context.Context, symbols []string) error {
+			liquidations, err := lm.streamer.WatchLiquidations(ctx, symbols)
+			if err != nil {
+				return err
+			}
+			go lm.consume(liquidations)
+			return nil
+		},
+	}
+	go stream.run(ctx)
+	logrus.Infof("强平监控已启动，单分钟告警阈值: %.0f USDT", lm.threshold)
+}
+
+// Stop 停止强平监控并断开强平推送
+func (lm *LiquidationMonitor) Stop() {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	if !lm.running {
+		return
+	}
+	lm.cancel()
+	lm.running = false
+	logrus.Info("强平监控已停止")
+}
+
+// consume 处理强平推送直到订阅取消
+func (lm *LiquidationMonitor) consume(liquidations <-chan *types.Liquidation) {
+	for liquidation := range liquidations {
+		lm.record(liquidation)
+	}
+}
+
+// record 累加到当前分钟汇总并保存，超过阈值时告警
+func (lm *LiquidationMonitor) record(liquidation *types.Liquidation) {
+	minute := liquidation.Timestamp - liquidation.Timestamp%time.Minute.Milliseconds()
+
+	lm.mu.Lock()
+	bucket := lm.buckets[liquidation.Symbol]
+	if bucket == nil || bucket.Minute != minute {
+		bucket = &models.LiquidationBucket{Symbol: liquidation.Symbol, Minute: minute}
+		lm.buckets[liquidation.Symbol] = bucket
+	}
+
+	side := models.LiquidationSideLong
+	notional := liquidation.Notional
+	if liquidation.Side == "buy" {
+		side = models.LiquidationSideShort
+		bucket.ShortNotional += notional
+		bucket.ShortCount++
+	} else {
+		bucket.LongNotional += notional
+		bucket.LongCount++
+	}
+	snapshot := *bucket
+
+	var alert *models.LiquidationAlert
+	alertKey := liquidation.Symbol + ":" + side
+	sideNotional, sideCount := snapshot.LongNotional, snapshot.LongCount
+	if side == models.LiquidationSideShort {
+		sideNotional, sideCount = snapshot.ShortNotional, snapshot.ShortCount
+	}
+	if lm.threshold > 0 && sideNotional >= lm.threshold && lm.alerted[alertKey] != minute {
+		lm.alerted[alertKey] = minute
+		alert = &models.LiquidationAlert{
+			Symbol:    liquidation.Symbol,
+			Exchange:  lm.exchangeID,
+			Side:      side,
+			Minute:    minute,
+			Notional:  sideNotional,
+			Count:     sideCount,
+			Threshold: lm.threshold,
+		}
+	}
+	lm.mu.Unlock()
+
+	if err := lm.store.SaveLiquidationBucket(&snapshot, lm.retention); err != nil {
+		logrus.Errorf("保存 %s 强平汇总失败: %v", liquidation.Symbol, err)
+	}
+	if alert != nil {
+		lm.alert(alert)
+	}
+}
+
+// alert 推送强平告警到前端和通知渠道
+func (lm *LiquidationMonitor) alert(alert *models.LiquidationAlert) {
+	direction := "多头"
+	if alert.Side == models.LiquidationSideShort {
+		direction = "空头"
+	}
+	message := fmt.Sprintf("%s 1分钟内%s被强平 %.0f USDT（%d 笔），超过阈值 %.0f USDT",
+		alert.Symbol, direction, alert.Notional, alert.Count, alert.Threshold)
+	logrus.Warnf("强平告警: %s", message)
+
+	if wsManager := websocket.GetGlobalWebSocketManager(); wsManager != nil {
+		wsManager.BroadcastAlert(AlertTypeLiquidation, alert)
+	}
+	notify.Send(notify.EventLiquidation, i18n.T("notify.liquidation.title"), message, map[string]interface{}{
+		"symbol":   alert.Symbol,
+		"exchange": alert.Exchange,
+		"side":     alert.Side,
+		"notional": alert.Notional,
+		"count":    alert.Count,
+	})
+}
+
+// GetLiquidationSummary 获取最近 minutes 分钟的强平汇总
+func GetLiquidationSummary(exchange, symbol string, minutes int) (*models.LiquidationSummary, error) {
+	since := time.Now().Add(-time.Duration(minutes) * time.Minute).Truncate(time.Minute).UnixMilli()
+	buckets, err := ExchangeStore(exchange).GetLiquidationBuckets(symbol, since)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.LiquidationSummary{
+		Symbol:   symbol,
+		Exchange: strings.ToLower(exchange),
+		Minutes:  minutes,
+		Buckets:  buckets,
+	}
+	for _, bucket := range buckets {
+		summary.LongNotional += bucket.LongNotional
+		summary.ShortNotional += bucket.ShortNotional
+		summary.LongCount += bucket.LongCount
+		summary.ShortCount += bucket.ShortCount
+	}
+	return summary, nil
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 	"trading_assistant/models"
+	"trading_assistant/pkg/config"
 	"trading_assistant/pkg/exchange_factory"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
 	"trading_assistant/pkg/redis"
 
 	"github.com/sirupsen/logrus"
@@ -16,14 +20,44 @@ import (
 type MarketManager struct {
 	exchangeClient exchange_factory.ExchangeInterface
 	priceManager   *PriceManager
+	orderBook      *OrderBookManager // 未启用或交易所不支持时为nil
+	klineManager   *KlineManager     // 未启用K线历史时为nil
+	autoSelector   *CoinAutoSelector
+	store          *redis.Client // 按交易所隔离的市场数据存储
 }
 
 // NewMarketManager 创建市场数据管理器
 func NewMarketManager(exchangeClient exchange_factory.ExchangeInterface) *MarketManager {
-	return &MarketManager{
+	RegisterExchangeClient(exchangeClient)
+
+	mm := &MarketManager{
 		exchangeClient: exchangeClient,
 		priceManager:   NewPriceManager(exchangeClient),
+		autoSelector:   NewCoinAutoSelectorFromConfig(),
+		store:          ExchangeStore(exchangeClient.GetID()),
+	}
+	if config.GlobalConfig.OrderBookEnabled {
+		mm.orderBook = NewOrderBookManager(exchangeClient)
+	}
+	if config.GlobalConfig.KlineStoreEnabled {
+		mm.klineManager = NewKlineManager(exchangeClient)
 	}
+	return mm
+}
+
+// RunAutoSelection 执行启动自动选币
+func (mm *MarketManager) RunAutoSelection() error {
+	return mm.autoSelector.RunAtStartup()
+}
+
+// GetExchangeID 获取管理的交易所ID
+func (mm *MarketManager) GetExchangeID() string {
+	return mm.exchangeClient.GetID()
+}
+
+// GetAutoSelector 获取自动选币器
+func (mm *MarketManager) GetAutoSelector() *CoinAutoSelector {
+	return mm.autoSelector
 }
 
 // StartPriceSubscriptions 启动全局markPrice订阅
@@ -35,12 +69,28 @@ func (mm *MarketManager) StartPriceSubscriptions() error {
 		return fmt.Errorf("启动价格管理器失败: %v", err)
 	}
 
+	// 启动订单簿同步
+	if mm.orderBook != nil {
+		mm.orderBook.Start()
+	}
+
+	// 启动K线历史回填
+	if mm.klineManager != nil {
+		mm.klineManager.Start()
+	}
+
 	logrus.Info("markPrice订阅启动完成")
 	return nil
 }
 
 // StopPriceSubscriptions 停止全局markPrice订阅
 func (mm *MarketManager) StopPriceSubscriptions() {
+	if mm.orderBook != nil {
+		mm.orderBook.Stop()
+	}
+	if mm.klineManager != nil {
+		mm.klineManager.Stop()
+	}
 	if mm.priceManager != nil {
 		mm.priceManager.Stop()
 		logrus.Info("全局价格订阅已停止")
@@ -60,18 +110,36 @@ func (mm *MarketManager) GetPriceSubscriptionStatus() map[string]interface{} {
 
 // SyncMarketAndPriceData 同步市场数据和价格数据
 func (mm *MarketManager) SyncMarketAndPriceData() error {
+	_, err := mm.syncMarketAndPriceData()
+	return err
+}
+
+// SyncMarketChanges 增量同步市场数据和价格数据，有新上市或下架的币种时发送通知
+func (mm *MarketManager) SyncMarketChanges() (*MarketChanges, error) {
+	changes, err := mm.syncMarketAndPriceData()
+	if err != nil {
+		return nil, err
+	}
+	notifyMarketChanges(changes)
+	return changes, nil
+}
+
+// syncMarketAndPriceData 同步市场数据和价格数据，下架币种的预估和选中状态在同步时处理
+func (mm *MarketManager) syncMarketAndPriceData() (*MarketChanges, error) {
 	logrus.Info("开始同步市场数据和价格数据...")
 
-	if err := mm.syncMarketData(); err != nil {
-		return fmt.Errorf("同步市场数据失败: %w", err)
+	changes, err := mm.syncMarketData()
+	if err != nil {
+		return nil, fmt.Errorf("同步市场数据失败: %w", err)
 	}
+	mm.handleDelisted(changes)
 
 	if err := mm.syncPriceData(); err != nil {
-		return fmt.Errorf("同步价格数据失败: %w", err)
+		return nil, fmt.Errorf("同步价格数据失败: %w", err)
 	}
 
 	logrus.Info("市场数据和价格数据同步完成")
-	return nil
+	return changes, nil
 }
 
 // parseOnboardDate 从 market.Info 中安全提取上市时间戳
@@ -117,23 +185,53 @@ func parseTimestamp(value interface{}) int64 {
 }
 
 // syncMarketData 同步市场数据
-func (mm *MarketManager) syncMarketData() error {
+func (mm *MarketManager) syncMarketData() (*MarketChanges, error) {
 	logrus.Info("开始同步市场数据...")
 
 	// 获取市场类型
 	marketType := mm.exchangeClient.GetMarketType()
 	isSpotMode := marketType == "spot"
 
+	// 计价货币默认USDT，部分交易所（如 Hyperliquid）以USDC计价
+	quoteAsset := "USDT"
+	if provider, ok := mm.exchangeClient.(exchange_factory.QuoteAssetProvider); ok {
+		quoteAsset = provider.GetQuoteAsset()
+	}
+
 	// 获取所有USDT交易对
 	markets, err := mm.exchangeClient.FetchMarkets(context.Background(), nil)
 	if err != nil {
-		return fmt.Errorf("获取市场数据失败: %v", err)
+		return nil, fmt.Errorf("获取市场数据失败: %v", err)
+	}
+
+	// 同步前已有的币种，用于识别新上市和下架的币种
+	existingCoins, err := mm.store.GetAllCoins()
+	if err != nil {
+		return nil, fmt.Errorf("获取已有币种失败: %v", err)
+	}
+	existing := make(map[string]bool, len(existingCoins))
+	for _, coin := range existingCoins {
+		existing[coin.Symbol] = true
+	}
+	changes := &MarketChanges{
+		Exchange: strings.ToLower(mm.GetExchangeID()),
+		Listed:   []string{},
+		Delisted: []string{},
+		SyncedAt: time.Now(),
+	}
+
+	// 禁止交易的币种仍然保存市场数据（平仓需要精度），状态标记为 denied
+	denylist, err := LoadDenylist()
+	if err != nil {
+		logrus.Warnf("获取禁止交易名单失败，本次同步不标记禁止交易币种: %v", err)
+		denylist = &Denylist{}
 	}
 
 	// 统计计数器
 	var syncedCount int
 	var usdtCount int
 	validSymbols := make(map[string]bool) // 记录有效的symbol
+	validMarkets := make([]*types.Market, 0, len(markets))
 
 	for i := range markets {
 		market := markets[i]
@@ -141,14 +239,14 @@ func (mm *MarketManager) syncMarketData() error {
 		// 根据市场类型筛选
 		if isSpotMode {
 			// 现货模式：只处理活跃的USDT现货交易对
-			if !market.Active || market.Quote != "USDT" || !market.Spot {
+			if !market.Active || market.Quote != quoteAsset || !market.Spot {
 				logrus.Debugf("跳过非现货交易对: %s (Active: %v, Quote: %s, Spot: %v)",
 					market.ID, market.Active, market.Quote, market.Spot)
 				continue
 			}
 		} else {
 			// 期货模式：只处理活跃的USDT永续合约
-			if !market.Active || market.Quote != "USDT" || !market.Swap {
+			if !market.Active || market.Quote != quoteAsset || !market.Swap {
 				logrus.Debugf("跳过非永续合约: %s (Active: %v, Quote: %s, Swap: %v)",
 					market.ID, market.Active, market.Quote, market.Swap)
 				continue
@@ -159,6 +257,11 @@ func (mm *MarketManager) syncMarketData() error {
 
 		// 使用MarketID作为有效标识符
 		validSymbols[market.ID] = true
+		validMarkets = append(validMarkets, market)
+		// 首次同步时所有币种都是新的，不算新上市
+		if len(existing) > 0 && !existing[market.ID] {
+			changes.Listed = append(changes.Listed, market.ID)
+		}
 
 		// 创建币种信息（统一使用MarketID）
 		coin := &models.Coin{
@@ -173,10 +276,14 @@ func (mm *MarketManager) syncMarketData() error {
 			MaxPrice:    fmt.Sprintf("%.8f", market.Limits.Price.Max),
 			MinQty:      fmt.Sprintf("%.8f", market.Limits.Amount.Min),
 			MaxQty:      fmt.Sprintf("%.8f", market.Limits.Amount.Max),
+			MinNotional: fmt.Sprintf("%.8f", market.Limits.Cost.Min),
 			OnboardDate: parseOnboardDate(market.Info),
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		}
+		if denylist.MatchCoin(coin) != nil {
+			coin.Status = models.CoinStatusDenied
+		}
 
 		// 计算并设置正确的精度值
 		// 优先从 Limits.Price.Step 计算，如果没有则从 Precision.Price 获取
@@ -200,7 +307,7 @@ func (mm *MarketManager) syncMarketData() error {
 		}).Debug("币种精度计算完成")
 
 		// 保存到Redis
-		if err := redis.GlobalRedisClient.SetCoin(coin); err != nil {
+		if err := mm.store.SetCoin(coin); err != nil {
 			logrus.Errorf("保存币种 %s 失败: %v", market.ID, err)
 			continue
 		}
@@ -208,46 +315,48 @@ func (mm *MarketManager) syncMarketData() error {
 		syncedCount++
 	}
 
-	if err := mm.cleanupInvalidCoins(validSymbols); err != nil {
-		logrus.Warnf("清理无效币种失败: %v", err)
+	// 更新符号转换表，使其他格式的符号也能对应到本交易所的MarketID
+	exchanges.SymbolMapperFor(mm.exchangeClient.GetID()).Load(validMarkets)
+
+	// 交易所返回的市场列表异常缩水时不清理，避免误判为大面积下架
+	if len(existingCoins) > 0 && float64(len(validSymbols)) < float64(len(existingCoins))*minValidMarketRatio {
+		logrus.Warnf("本次获取的有效市场 %d 个，远少于已有的 %d 个，跳过下架清理", len(validSymbols), len(existingCoins))
+	} else {
+		changes.Delisted = mm.cleanupInvalidCoins(existingCoins, validSymbols)
 	}
 
 	logrus.WithFields(logrus.Fields{
 		"total_markets": len(markets),
 		"usdt_markets":  usdtCount,
 		"synced_count":  syncedCount,
+		"listed":        len(changes.Listed),
+		"delisted":      len(changes.Delisted),
 	}).Info("市场数据同步完成")
 
-	return nil
+	return changes, nil
 }
 
-// cleanupInvalidCoins 清理不再有效的币种
-func (mm *MarketManager) cleanupInvalidCoins(validSymbols map[string]bool) error {
-	// 获取所有现有币种
-	existingCoins, err := redis.GlobalRedisClient.GetAllCoins()
-	if err != nil {
-		return err
-	}
-
-	var deletedCount int
+// cleanupInvalidCoins 清理不再有效的币种，返回被删除的币种
+func (mm *MarketManager) cleanupInvalidCoins(existingCoins []*models.Coin, validSymbols map[string]bool) []string {
+	deleted := make([]string, 0)
 	for _, coin := range existingCoins {
 		if !validSymbols[coin.Symbol] {
 			// 这个币种不再有效，删除它
-			if err := redis.GlobalRedisClient.DeleteCoin(coin.Symbol); err != nil {
+			if err := mm.store.DeleteCoin(coin.Symbol); err != nil {
 				logrus.Errorf("删除无效币种 %s 失败: %v", coin.Symbol, err)
 			} else {
-				deletedCount++
+				deleted = append(deleted, coin.Symbol)
 			}
 		}
 	}
 
-	if deletedCount > 0 {
+	if len(deleted) > 0 {
 		logrus.WithFields(logrus.Fields{
-			"deleted_count": deletedCount,
+			"deleted_count": len(deleted),
 		}).Info("清理无效币种完成")
 	}
 
-	return nil
+	return deleted
 }
 
 // syncPriceData 同步价格数据
@@ -255,7 +364,7 @@ func (mm *MarketManager) syncPriceData() error {
 	logrus.Info("开始同步价格数据...")
 
 	// 获取所有币种列表
-	coins, err := redis.GlobalRedisClient.GetAllCoins()
+	coins, err := mm.store.GetAllCoins()
 	if err != nil {
 		return fmt.Errorf("获取币种列表失败: %v", err)
 	}
@@ -311,6 +420,8 @@ func (mm *MarketManager) syncPriceData() error {
 		coin.Price = fmt.Sprintf("%.8f", ticker.Last)
 		coin.PriceChange = fmt.Sprintf("%.8f", ticker.Change)
 		coin.PriceChangePercent = fmt.Sprintf("%.2f", ticker.Percentage)
+		coin.HighPrice = fmt.Sprintf("%.8f", ticker.High)
+		coin.LowPrice = fmt.Sprintf("%.8f", ticker.Low)
 		coin.Volume = fmt.Sprintf("%.8f", ticker.BaseVolume)
 		coin.QuoteVolume = fmt.Sprintf("%.8f", ticker.QuoteVolume)
 		coin.UpdatedAt = now
@@ -324,7 +435,7 @@ func (mm *MarketManager) syncPriceData() error {
 		}
 
 		// 保存更新后的币种信息
-		if err := redis.GlobalRedisClient.SetCoin(coin); err != nil {
+		if err := mm.store.SetCoin(coin); err != nil {
 			logrus.Errorf("保存 %s 价格数据失败: %v", coin.Symbol, err)
 			errorCount++
 			continue
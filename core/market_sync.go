@@ -0,0 +1,188 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// minValidMarketRatio 本次有效市场数量低于已有币种的该比例时视为交易所返回异常，不做下架清理
+const minValidMarketRatio = 0.5
+
+// MarketChanges 一次市场同步发现的新上市和下架币种
+type MarketChanges struct {
+	Exchange          string    `json:"exchange"`
+	Listed            []string  `json:"listed"`
+	Delisted          []string  `json:"delisted"`
+	DeselectedCoins   []string  `json:"deselected_coins"`   // 因下架取消选中的币种
+	DisabledEstimates int       `json:"disabled_estimates"` // 因下架停用的监听中预估数量
+	SyncedAt          time.Time `json:"synced_at"`
+}
+
+// handleDelisted 处理下架币种：删除标记价格缓存，停用以该交易所为价格来源的监听中预估；
+// 主交易所下架时取消选中，价格、订单簿、K线等订阅随选中币种自动退订
+func (mm *MarketManager) handleDelisted(changes *MarketChanges) {
+	if len(changes.Delisted) == 0 {
+		return
+	}
+
+	isPrimary := ExchangeNamespace(mm.GetExchangeID()) == ""
+	delisted := make(map[string]bool, len(changes.Delisted))
+	for _, symbol := range changes.Delisted {
+		delisted[symbol] = true
+		if err := mm.store.DeleteMarkPrice(symbol); err != nil {
+			logrus.Warnf("删除下架币种 %s 标记价格失败: %v", symbol, err)
+		}
+		if isPrimary && redis.GlobalRedisClient.IsCoinSelected(symbol) {
+			if err := redis.GlobalRedisClient.SetCoinSelection(symbol, models.CoinSelectionInactive); err != nil {
+				logrus.Errorf("取消选中下架币种 %s 失败: %v", symbol, err)
+				continue
+			}
+			changes.DeselectedCoins = append(changes.DeselectedCoins, symbol)
+		}
+	}
+	if len(changes.DeselectedCoins) > 0 {
+		GlobalWhitelistSyncer.SyncAsync()
+	}
+
+	estimates, err := redis.GlobalRedisClient.GetActiveEstimates()
+	if err != nil {
+		logrus.Errorf("获取监听中的预估失败: %v", err)
+		return
+	}
+	errorMessage := fmt.Sprintf("%s 已下架该币种，预估已停用", changes.Exchange)
+	for _, estimate := range estimates {
+		if !delisted[estimate.Symbol] || ExchangeNamespace(estimate.Exchange) != ExchangeNamespace(mm.GetExchangeID()) {
+			continue
+		}
+
+		updated, err := updateEstimate(estimate, func(current *models.PriceEstimate) error {
+			if !current.Enabled || current.Status != models.EstimateStatusListening {
+				return errEstimateUnchanged
+			}
+			current.Enabled = false
+			current.ErrorMessage = errorMessage
+			return nil
+		})
+		if err != nil {
+			logrus.Errorf("停用下架币种预估 %s 失败: %v", estimate.ID, err)
+			continue
+		}
+		if updated == nil {
+			continue
+		}
+		changes.DisabledEstimates++
+		RecordEstimateToggled(estimate.ID, false, errorMessage)
+	}
+	if changes.DisabledEstimates > 0 {
+		go utils.BroadcastSymbolEstimatesUpdate()
+	}
+
+	logrus.Warnf("%s 下架币种: %s，取消选中 %d 个，停用预估 %d 个",
+		changes.Exchange, strings.Join(changes.Delisted, ", "), len(changes.DeselectedCoins), changes.DisabledEstimates)
+}
+
+// notifyMarketChanges 发送新上市和下架通知
+func notifyMarketChanges(changes *MarketChanges) {
+	if len(changes.Listed) > 0 {
+		notify.Send(notify.EventListing, i18n.T("notify.listed.title"),
+			i18n.T("notify.listed.message", changes.Exchange, len(changes.Listed), strings.Join(changes.Listed, ", ")),
+			map[string]interface{}{"exchange": changes.Exchange, "listed": changes.Listed})
+	}
+	if len(changes.Delisted) > 0 {
+		message := i18n.T("notify.delisted.message", changes.Exchange, len(changes.Delisted), strings.Join(changes.Delisted, ", "))
+		if changes.DisabledEstimates > 0 {
+			message += i18n.T("notify.delisted.estimates", changes.DisabledEstimates)
+		}
+		if len(changes.DeselectedCoins) > 0 {
+			message += i18n.T("notify.delisted.deselected", strings.Join(changes.DeselectedCoins, ", "))
+		}
+		notify.Send(notify.EventListing, i18n.T("notify.delisted.title"), message, map[string]interface{}{
+			"exchange":           changes.Exchange,
+			"delisted":           changes.Delisted,
+			"disabled_estimates": changes.DisabledEstimates,
+		})
+	}
+}
+
+// MarketSyncScheduler 定时增量同步各交易所的市场和价格数据，只在主节点运行，避免重复通知
+type MarketSyncScheduler struct {
+	managers []*MarketManager
+	interval time.Duration
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	running bool
+}
+
+var GlobalMarketSync *MarketSyncScheduler
+
+// InitMarketSync 初始化市场数据定时同步，间隔为0时不创建
+func InitMarketSync(managers ...*MarketManager) {
+	interval := config.GlobalConfig.MarketSyncInterval
+	if interval <= 0 {
+		return
+	}
+	GlobalMarketSync = &MarketSyncScheduler{
+		managers: managers,
+		interval: interval,
+	}
+}
+
+// Start 启动定时同步，启动时已完成一次全量同步，首次在一个间隔后执行
+func (ms *MarketSyncScheduler) Start() {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if ms.running {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ms.cancel = cancel
+	ms.running = true
+
+	go ms.run(ctx)
+	logrus.Infof("市场数据定时同步已启动，间隔: %v", ms.interval)
+}
+
+// Stop 停止定时同步
+func (ms *MarketSyncScheduler) Stop() {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if !ms.running {
+		return
+	}
+	ms.cancel()
+	ms.running = false
+	logrus.Info("市场数据定时同步已停止")
+}
+
+// run 主运行循环
+func (ms *MarketSyncScheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(ms.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, manager := range ms.managers {
+				if _, err := manager.SyncMarketChanges(); err != nil {
+					logrus.Errorf("定时同步 %s 市场数据失败: %v", manager.GetExchangeID(), err)
+				}
+			}
+		}
+	}
+}
@@ -1,12 +1,18 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 	"trading_assistant/models"
 	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
 	"trading_assistant/pkg/exchanges/types"
 	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/metrics"
+	"trading_assistant/pkg/notify"
 	"trading_assistant/pkg/redis"
 	"trading_assistant/pkg/utils"
 
@@ -18,17 +24,39 @@ type PriceMonitor struct {
 	stopChan      chan bool
 	tickInterval  time.Duration
 	orderExecutor *OrderExecutor
+	scheduler     *monitorScheduler
+	skipLog       *skipLog
+	nearLog       *skipLog // 接近触发事件记录器
+	proximity     *proximityTracker
+	watchdog      *priceWatchdog
+	lastTickAt    atomic.Int64 // 最近一轮监控开始时间（毫秒）
+	tickLag       atomic.Int64 // 最近一轮监控开始时相对定时器触发的延迟（微秒）
 }
 
 var GlobalPriceMonitor *PriceMonitor
 
 // InitPriceMonitor 初始化价格监控器
-func InitPriceMonitor(freqtradeClient *freqtrade.Controller) {
+func InitPriceMonitor(bots *freqtrade.Registry) {
 	GlobalPriceMonitor = &PriceMonitor{
 		running:       false,
 		stopChan:      make(chan bool),
 		tickInterval:  500 * time.Millisecond,
-		orderExecutor: NewOrderExecutor(freqtradeClient),
+		orderExecutor: NewOrderExecutor(bots),
+		scheduler: newMonitorScheduler(
+			config.GlobalConfig.MonitorSymbolBudget,
+			config.GlobalConfig.MonitorSymbolBatch,
+			config.GlobalConfig.MonitorLatencySLO,
+		),
+		skipLog: newSkipLog(
+			config.GlobalConfig.MonitorSkipLogMaxLen,
+			config.GlobalConfig.MonitorSkipLogCooldown,
+		),
+		nearLog:   newSkipLog(0, config.GlobalConfig.EstimateNearTriggerCooldown),
+		proximity: newProximityTracker(),
+		watchdog: newPriceWatchdog(
+			config.GlobalConfig.MonitorStalePriceThreshold,
+			config.GlobalConfig.MonitorResubscribeCooldown,
+		),
 	}
 }
 
@@ -53,6 +81,8 @@ func (pm *PriceMonitor) Stop() {
 
 	pm.running = false
 	pm.stopChan <- true
+	// 停止后台拆单，避免降级或关闭后继续下单
+	pm.orderExecutor.stopSlices()
 	logrus.Info("价格监控已停止")
 }
 
@@ -61,23 +91,71 @@ func (pm *PriceMonitor) IsRunning() bool {
 	return pm.running
 }
 
+// GetSchedulerStats 获取按币种的调度统计
+func (pm *PriceMonitor) GetSchedulerStats() []SymbolSchedulerStats {
+	return pm.scheduler.GetStats()
+}
+
+// recordTick 记录一轮监控的开始时间和相对定时器触发的延迟，上一轮耗时过长时延迟增大
+func (pm *PriceMonitor) recordTick(tickAt time.Time) {
+	now := time.Now()
+	pm.lastTickAt.Store(now.UnixMilli())
+	pm.tickLag.Store(now.Sub(tickAt).Microseconds())
+}
+
+// GetLoopStatus 获取最近一轮监控的开始时间和循环延迟，尚未运行时开始时间为零值
+func (pm *PriceMonitor) GetLoopStatus() (lastTickAt time.Time, lag time.Duration) {
+	if millis := pm.lastTickAt.Load(); millis > 0 {
+		lastTickAt = time.UnixMilli(millis)
+	}
+	return lastTickAt, time.Duration(pm.tickLag.Load()) * time.Microsecond
+}
+
 // monitorLoop 监控循环
 func (pm *PriceMonitor) monitorLoop() {
 	ticker := time.NewTicker(pm.tickInterval)
 	defer ticker.Stop()
+
+	// 过期预估清理
+	sweepInterval := config.GlobalConfig.EstimateSweepInterval
+	if sweepInterval <= 0 {
+		sweepInterval = 30 * time.Second
+	}
+	sweepTicker := time.NewTicker(sweepInterval)
+	defer sweepTicker.Stop()
+
+	// 价格新鲜度检查
+	watchdogTicker := time.NewTicker(watchdogCheckInterval)
+	defer watchdogTicker.Stop()
+
+	// 恢复上次进程中断时停留在执行中的预估
+	pm.recoverInterruptedExecutions()
 	
 	for {
 		select {
 		case <-pm.stopChan:
 			return
-		case <-ticker.C:
+		case tickAt := <-ticker.C:
+			pm.recordTick(tickAt)
 			pm.checkPriceTargets()
+			pm.checkSpreadMonitors()
+		case <-sweepTicker.C:
+			pm.sweepExpiredEstimates()
+			pm.recoverInterruptedExecutions()
+		case <-watchdogTicker.C:
+			pm.checkPriceFreshness()
+			GetPriceFailover().Evaluate()
 		}
 	}
 }
 
 // checkPriceTargets 检查价格目标
 func (pm *PriceMonitor) checkPriceTargets() {
+	// 紧急停止期间不评估任何预估，需要手动解除
+	if IsKillSwitchActive() {
+		return
+	}
+
 	// 获取所有待处理的价格预估
 	estimates, err := redis.GlobalRedisClient.GetActiveEstimates()
 	if err != nil {
@@ -85,56 +163,73 @@ func (pm *PriceMonitor) checkPriceTargets() {
 		return
 	}
 
+	// 已过期的预估不再评估，等待清理
+	now := time.Now()
+	active := estimates[:0]
+	for _, estimate := range estimates {
+		if !estimate.IsExpired(now) {
+			active = append(active, estimate)
+		}
+	}
+	estimates = active
+
+	// 清理已删除、暂停或过期预估的提醒状态
+	pm.proximity.retain(estimates)
+
 	if len(estimates) == 0 {
 		return
 	}
 
 	logrus.Debugf("检查 %d 个价格预估", len(estimates))
 
-	for i := range estimates {
-		estimate := estimates[i]
-		pm.checkSingleEstimate(estimate)
-	}
-}
-
-// checkSingleEstimate 检查单个价格预估
-func (pm *PriceMonitor) checkSingleEstimate(estimate *models.PriceEstimate) {
-	// 获取价格数据 (estimate.Symbol现在存储的就是MarketID)
-	markPriceData, err := redis.GlobalRedisClient.GetMarkPrice(estimate.Symbol)
-	if err != nil {
-		logrus.Debugf("未找到 %s 的价格数据", estimate.Symbol)
-		return
-	}
+	// 按币种公平轮询，同一轮内每个币种只读取一次价格
+	markPrices := make(map[string]*types.WatchMarkPrice)
+	pm.scheduler.Run(estimates, func(key string, batch []*models.PriceEstimate) {
+		markPriceData, loaded := markPrices[key]
+		if !loaded {
+			// 从预估指定的交易所获取价格数据，符号按该交易所的MarketID解析；主交易所数据质量下降时从切换后的交易所读取
+			venue := GetPriceFailover().Venue(batch[0].Exchange)
+			data, err := ExchangeStore(venue).GetMarkPrice(ResolveMarketID(venue, batch[0].Symbol))
+			if err != nil {
+				logrus.Debugf("未找到 %s 的价格数据", key)
+			}
+			markPriceData = data
+			markPrices[key] = data
+		}
 
-	if markPriceData == nil {
-		logrus.Debugf("价格数据为空 %s", estimate.Symbol)
-		return
-	}
+		if markPriceData == nil {
+			logrus.Debugf("价格数据为空 %s", key)
+			for _, estimate := range batch {
+				pm.recordSkip(estimate, models.SkipReasonStalePrice, "未找到价格数据")
+			}
+			return
+		}
 
-	// 根据交易方向选择合适的实时价格
-	// long（做多）- 需要买入，使用卖价（askPrice）
-	// short（做空）- 需要卖出，使用买价（bidPrice）
-	var currentPrice float64
-	switch estimate.Side {
-	case types.PositionSideLong:
-		currentPrice = markPriceData.AskPrice // 做多使用卖价（买入时的成本）
-		if currentPrice <= 0 {
-			// 降级到标记价格
-			currentPrice = markPriceData.MarkPrice
-			logrus.Debugf("%s 卖价无效，降级使用标记价格: %f", estimate.Symbol, currentPrice)
+		if isStalePrice(markPriceData, config.GlobalConfig.MonitorStalePriceThreshold, now) {
+			age := now.Sub(time.UnixMilli(markPriceData.TimeStamp)).Round(time.Second)
+			logrus.Debugf("价格数据已过期 %s, 距上次更新 %v", key, age)
+			for _, estimate := range batch {
+				pm.recordSkip(estimate, models.SkipReasonStalePrice, "价格数据已 %v 未更新", age)
+			}
+			return
 		}
-	case types.PositionSideShort:
-		currentPrice = markPriceData.BidPrice // 做空使用买价（卖出时的价格）
-		if currentPrice <= 0 {
-			// 降级到标记价格
-			currentPrice = markPriceData.MarkPrice
-			logrus.Debugf("%s 买价无效，降级使用标记价格: %f", estimate.Symbol, currentPrice)
+
+		for i := range batch {
+			pm.checkSingleEstimate(batch[i], markPriceData, now)
 		}
-	}
+	})
+}
+
+// checkSingleEstimate 检查单个价格预估，tickAt 为本轮监控开始时间
+func (pm *PriceMonitor) checkSingleEstimate(estimate *models.PriceEstimate, markPriceData *types.WatchMarkPrice, tickAt time.Time) {
+	// 按预估的价格来源选择触发价格，默认做多使用卖价、做空使用买价
+	currentPrice := estimateTriggerPrice(estimate, markPriceData)
 
 	if currentPrice <= 0 {
 		logrus.Errorf("无效的价格 %s: bid=%f, ask=%f, mark=%f",
 			estimate.Symbol, markPriceData.BidPrice, markPriceData.AskPrice, markPriceData.MarkPrice)
+		pm.recordSkip(estimate, models.SkipReasonInvalidPrice, "bid=%f, ask=%f, mark=%f",
+			markPriceData.BidPrice, markPriceData.AskPrice, markPriceData.MarkPrice)
 		return
 	}
 
@@ -154,88 +249,140 @@ func (pm *PriceMonitor) checkSingleEstimate(estimate *models.PriceEstimate) {
 		return
 	}
 
+	// 跨币种条件替代目标价格判断
+	if estimate.Condition != "" && triggerType == models.TriggerTypeCondition {
+		shouldTrigger = pm.evaluateCrossCondition(estimate, tickAt)
+	}
+
+	if !shouldTrigger {
+		pm.checkProximity(estimate, currentPrice, tickAt)
+		pm.recordNearTrigger(estimate, currentPrice, tickAt)
+	}
+
 	if shouldTrigger {
-		// 根据交易方向确定价格类型描述
-		var priceType string
-		switch estimate.Side {
-		case types.PositionSideLong:
-			priceType = "卖价(ask)"
-		case types.PositionSideShort:
-			priceType = "买价(bid)"
-		default:
-			priceType = "未知价格"
-		}
+		priceType := priceSourceText(estimate)
 
 		logrus.Infof("价格目标触发: %s %s %s, 当前%s: %f, 目标价格: %f",
 			estimate.Symbol, estimate.Side, actionType, priceType, currentPrice, estimate.TargetPrice)
 
-		// 对于做空场景，检查资金费率
-		if estimate.Side == types.PositionSideShort {
+		// 波动率或买卖价差过大时推迟触发，避免在插针时成交
+		if !pm.checkVolatilityGuard(estimate, markPriceData, tickAt) {
+			return
+		}
+
+		// 做空开仓和加仓检查资金费率，止盈止损等平仓操作不受限制，避免保护性止损被拦截
+		if estimate.Side == types.PositionSideShort && isIncreaseAction(actionType) {
 			if !pm.checkFundingRateForShort(estimate, markPriceData) {
+				pm.recordSkip(estimate, models.SkipReasonGuardBlocked, "%s", estimate.ErrorMessage)
 				return
 			}
 		}
 
-		pm.triggerEstimate(estimate, currentPrice)
+		// 下单实例熔断时暂停执行，预估保持监听，恢复后重试
+		if !pm.checkFreqtradeCircuit(estimate) {
+			return
+		}
+
+		pm.triggerEstimate(estimate, currentPrice, tickAt)
 	}
 }
 
-// triggerEstimate 触发价格预估
-func (pm *PriceMonitor) triggerEstimate(estimate *models.PriceEstimate, currentPrice float64) {
+// triggerEstimate 触发价格预估，detectedAt 为满足触发条件的时间，用于统计执行延迟
+func (pm *PriceMonitor) triggerEstimate(estimate *models.PriceEstimate, currentPrice float64, detectedAt time.Time) {
+	// 取得执行权，避免重复触发或多实例同时下单
+	execution := pm.beginEstimateExecution(estimate)
+	if execution == nil {
+		return
+	}
+	pm.proximity.forget(estimate.ID)
+	formatter := Formatter(estimate.Exchange)
+	RecordEstimateEvent(estimate.ID, models.EstimateEventTriggered,
+		fmt.Sprintf("%s %s, 目标价格 %s", priceSourceText(estimate),
+			formatter.FormatPrice(estimate.Symbol, currentPrice), formatter.FormatPrice(estimate.Symbol, estimate.TargetPrice)),
+		map[string]interface{}{
+			"price":        currentPrice,
+			"target_price": estimate.TargetPrice,
+			"detected_at":  detectedAt.UnixMilli(),
+		})
+
 	// 执行自动下单
-	err := pm.orderExecutor.ExecuteOrder(estimate, currentPrice)
+	execStart := time.Now()
+	ctx := freqtrade.WithResponseRecorder(context.Background(), execution.recordResponse)
+	err := pm.orderExecutor.ExecuteOrder(ctx, estimate, currentPrice)
+	execution.finish(err)
+	recordExecutionLatency(estimate, detectedAt, execStart, time.Now(), err)
+	metrics.EstimateTriggers.WithLabelValues(estimate.ActionType, metrics.ResultLabel(err)).Inc()
 	if err != nil {
 		logrus.Errorf("订单执行失败: %v", err)
 
 		// 记录错误信息到日志
 		actionText := getActionText(estimate.ActionType)
 		positionText := getPositionText(estimate.Side)
-		logrus.Errorf("订单执行失败: %s %s %s, 比例: %.2f%%, 目标价: %.4f, 当前价: %.6f, 错误: %v",
-			estimate.Symbol, actionText, positionText, estimate.Percentage, estimate.TargetPrice, currentPrice, err)
+		logrus.Errorf("订单执行失败: %s %s %s, 比例: %.2f%%, 目标价: %s, 当前价: %s, 错误: %v",
+			estimate.Symbol, actionText, positionText, estimate.Percentage,
+			formatter.FormatPrice(estimate.Symbol, estimate.TargetPrice), formatter.FormatPrice(estimate.Symbol, currentPrice), err)
 
 		// 更新预估状态为失败，并保存错误信息
 		estimate.Status = models.EstimateStatusFailed
 		estimate.ErrorMessage = err.Error() // 保存失败原因
+		RecordEstimateEvent(estimate.ID, models.EstimateEventOrderFailed, err.Error(), nil)
+
+		notify.Send(notify.EventFailure, i18n.T("notify.failure.title"),
+			i18n.T("notify.failure.message", estimate.Symbol, actionText, positionText, formatter.FormatPrice(estimate.Symbol, currentPrice), err), nil)
 	} else {
 		// 更新预估状态为已触发，清空错误信息
 		estimate.Status = models.EstimateStatusTriggered
 		estimate.ErrorMessage = "" // 清空之前的错误信息（如果有）
+		RecordEstimateEvent(estimate.ID, models.EstimateEventOrderSubmitted, "", map[string]interface{}{
+			"execution_ms": time.Since(execStart).Milliseconds(),
+		})
+
+		notify.Send(notify.EventTrigger, i18n.T("notify.trigger.title"),
+			i18n.T("notify.trigger.message",
+				estimate.Symbol, getActionText(estimate.ActionType), getPositionText(estimate.Side),
+				formatter.FormatPrice(estimate.Symbol, estimate.TargetPrice), formatter.FormatPrice(estimate.Symbol, currentPrice)), nil)
 	}
 
-	estimate.UpdatedAt = time.Now()
-	err = redis.GlobalRedisClient.SetPriceEstimate(estimate)
+	updated, err := updateEstimate(estimate, func(current *models.PriceEstimate) error {
+		applyExecutionResult(current, estimate)
+		current.ErrorMessage = estimate.ErrorMessage
+		return nil
+	})
 	if err != nil {
 		logrus.Errorf("更新价格预估状态失败: %v", err)
 		return
 	}
+	estimate = updated
 
-	// 广播价格预估更新
-	go utils.BroadcastSymbolEstimatesUpdate()
+	// 发布触发事件，由订阅者负责广播等后续处理
+	eventbus.GetBus().Publish(eventbus.TopicEstimateTriggered, "", estimate)
 }
 
-// getActionText 获取操作类型的中文描述
+// getActionText 获取操作类型的描述，按配置的语言输出
 func getActionText(actionType string) string {
 	switch actionType {
 	case models.ActionTypeOpen:
-		return "开仓"
+		return i18n.T("action.open")
 	case models.ActionTypeAddition:
-		return "加仓"
+		return i18n.T("action.addition")
 	case models.ActionTypeTakeProfit:
-		return "止盈"
+		return i18n.T("action.take_profit")
+	case models.ActionTypeStopLoss:
+		return i18n.T("action.stop_loss")
 	default:
-		return "交易"
+		return i18n.T("action.other")
 	}
 }
 
-// getPositionText 获取仓位方向的中文描述
+// getPositionText 获取仓位方向的描述，按配置的语言输出
 func getPositionText(side string) string {
 	switch side {
 	case types.PositionSideLong:
-		return "做多"
+		return i18n.T("side.long")
 	case types.PositionSideShort:
-		return "做空"
+		return i18n.T("side.short")
 	default:
-		return "未知"
+		return i18n.T("side.unknown")
 	}
 }
 
@@ -254,13 +401,19 @@ func (pm *PriceMonitor) checkFundingRateForShort(estimate *models.PriceEstimate,
 		errorMsg := fmt.Sprintf("资金费率过低: 当前%.4f%% < 阈值%.4f%%，不允许开空仓",
 			currentFundingRate*100, threshold*100)
 
-		// 更新预估状态为失败，并保存错误信息
-		estimate.Status = models.EstimateStatusFailed
-		estimate.ErrorMessage = errorMsg
-		estimate.UpdatedAt = time.Now()
-		err := redis.GlobalRedisClient.SetPriceEstimate(estimate)
+		// 更新预估状态为失败，并保存错误信息；读取之后已被触发或停用的预估不再覆盖
+		updated, err := updateEstimate(estimate, func(current *models.PriceEstimate) error {
+			if current.Status != models.EstimateStatusListening {
+				return errEstimateUnchanged
+			}
+			current.Status = models.EstimateStatusFailed
+			current.ErrorMessage = errorMsg
+			return nil
+		})
 		if err != nil {
 			logrus.Errorf("更新价格预估状态失败: %v", err)
+		} else if updated != nil {
+			*estimate = *updated
 		}
 
 		// 记录资金费率检查失败信息到日志
@@ -270,6 +423,7 @@ func (pm *PriceMonitor) checkFundingRateForShort(estimate *models.PriceEstimate,
 
 		// 通过WebSocket广播失败事件
 		go pm.broadcastFundingRateFailEvent(estimate, currentFundingRate, threshold)
+		notify.Send(notify.EventFailure, i18n.T("notify.short_failure.title"), estimate.Symbol+" "+errorMsg, nil)
 
 		// 广播预估更新
 		go utils.BroadcastSymbolEstimatesUpdate()
@@ -0,0 +1,221 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SymbolSchedulerStats 单个币种的调度统计
+type SymbolSchedulerStats struct {
+	Symbol         string `json:"symbol"`
+	Evaluations    int64  `json:"evaluations"`     // 累计评估次数
+	Deferred       int64  `json:"deferred"`        // 累计顺延到下一轮的预估数
+	BudgetExceeded int64  `json:"budget_exceeded"` // 时间预算耗尽次数
+	SLOViolations  int64  `json:"slo_violations"`  // 评估延迟超过SLO次数
+	LastLatencyMs  int64  `json:"last_latency_ms"` // 最近一轮开始评估的延迟
+	MaxLatencyMs   int64  `json:"max_latency_ms"`  // 最大评估延迟
+}
+
+// monitorScheduler 按币种公平轮询的预估调度器
+// 每个币种一个队列，按轮次从各队列各取一批评估，单币种超出时间预算后剩余预估顺延到下一轮
+type monitorScheduler struct {
+	symbolBudget time.Duration
+	batchSize    int
+	latencySLO   time.Duration
+
+	mu       sync.Mutex
+	rrOffset int                              // 每轮起始币种偏移，避免固定顺序
+	cursors  map[string]int                   // 每个币种下一轮的起始位置
+	stats    map[string]*SymbolSchedulerStats // 每个币种的调度统计
+}
+
+// symbolQueue 单个币种的待评估队列
+type symbolQueue struct {
+	symbol    string
+	estimates []*models.PriceEstimate
+	next      int           // 已取出的数量
+	spent     time.Duration // 本轮已用时间
+	started   bool          // 本轮是否已开始评估
+	exhausted bool          // 本轮时间预算是否耗尽
+}
+
+// newMonitorScheduler 创建调度器
+func newMonitorScheduler(symbolBudget time.Duration, batchSize int, latencySLO time.Duration) *monitorScheduler {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	return &monitorScheduler{
+		symbolBudget: symbolBudget,
+		batchSize:    batchSize,
+		latencySLO:   latencySLO,
+		cursors:      make(map[string]int),
+		stats:        make(map[string]*SymbolSchedulerStats),
+	}
+}
+
+// Run 执行一轮调度，evaluate 负责评估单个队列的一批预估（同一批预估的交易所和币种相同）
+func (s *monitorScheduler) Run(estimates []*models.PriceEstimate, evaluate func(key string, batch []*models.PriceEstimate)) {
+	tickStart := time.Now()
+	queues := s.buildQueues(estimates)
+
+	for remaining := len(queues); remaining > 0; {
+		remaining = 0
+		for _, queue := range queues {
+			if queue.exhausted || queue.next >= len(queue.estimates) {
+				continue
+			}
+
+			if !queue.started {
+				queue.started = true
+				s.recordLatency(queue.symbol, time.Since(tickStart))
+			}
+
+			end := min(queue.next+s.batchSize, len(queue.estimates))
+			batch := queue.estimates[queue.next:end]
+
+			start := time.Now()
+			evaluate(queue.symbol, batch)
+			queue.spent += time.Since(start)
+			queue.next = end
+
+			if queue.next < len(queue.estimates) {
+				if s.symbolBudget > 0 && queue.spent >= s.symbolBudget {
+					queue.exhausted = true
+					continue
+				}
+				remaining++
+			}
+		}
+	}
+
+	s.finish(queues)
+}
+
+// buildQueues 按币种分组并确定本轮的评估顺序
+func (s *monitorScheduler) buildQueues(estimates []*models.PriceEstimate) []*symbolQueue {
+	grouped := make(map[string][]*models.PriceEstimate)
+	for _, estimate := range estimates {
+		key := scheduleKey(estimate)
+		grouped[key] = append(grouped[key], estimate)
+	}
+
+	symbols := make([]string, 0, len(grouped))
+	for symbol := range grouped {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queues := make([]*symbolQueue, 0, len(symbols))
+	if len(symbols) == 0 {
+		return queues
+	}
+
+	offset := s.rrOffset % len(symbols)
+	s.rrOffset++
+
+	for i := range symbols {
+		symbol := symbols[(offset+i)%len(symbols)]
+		list := grouped[symbol]
+
+		// 保证同一币种内部稳定排序，再从上轮中断的位置继续
+		sort.Slice(list, func(a, b int) bool { return list[a].ID < list[b].ID })
+		if cursor := s.cursors[symbol] % len(list); cursor > 0 {
+			rotated := make([]*models.PriceEstimate, 0, len(list))
+			rotated = append(rotated, list[cursor:]...)
+			list = append(rotated, list[:cursor]...)
+		}
+
+		queues = append(queues, &symbolQueue{symbol: symbol, estimates: list})
+	}
+
+	// 清理已没有预估的币种游标
+	for symbol := range s.cursors {
+		if _, exists := grouped[symbol]; !exists {
+			delete(s.cursors, symbol)
+		}
+	}
+
+	return queues
+}
+
+// scheduleKey 获取预估所属的调度队列，不同交易所的同名币种分开调度
+func scheduleKey(estimate *models.PriceEstimate) string {
+	if namespace := ExchangeNamespace(estimate.Exchange); namespace != "" {
+		return namespace + ":" + estimate.Symbol
+	}
+	return estimate.Symbol
+}
+
+// recordLatency 记录币种在本轮开始评估的延迟
+func (s *monitorScheduler) recordLatency(symbol string, latency time.Duration) {
+	metrics.MonitorSymbolLatency.WithLabelValues(symbol).Observe(latency.Seconds())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.getStats(symbol)
+	stats.LastLatencyMs = latency.Milliseconds()
+	if stats.LastLatencyMs > stats.MaxLatencyMs {
+		stats.MaxLatencyMs = stats.LastLatencyMs
+	}
+
+	if s.latencySLO > 0 && latency > s.latencySLO {
+		stats.SLOViolations++
+		metrics.MonitorSLOViolations.WithLabelValues(symbol).Inc()
+		logrus.Warnf("币种 %s 评估延迟 %v 超过SLO %v", symbol, latency, s.latencySLO)
+	}
+}
+
+// finish 更新游标和统计
+func (s *monitorScheduler) finish(queues []*symbolQueue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, queue := range queues {
+		stats := s.getStats(queue.symbol)
+		stats.Evaluations += int64(queue.next)
+
+		deferred := len(queue.estimates) - queue.next
+		if deferred > 0 {
+			// 下一轮从未评估的预估开始
+			s.cursors[queue.symbol] = (s.cursors[queue.symbol] + queue.next) % len(queue.estimates)
+			stats.Deferred += int64(deferred)
+			stats.BudgetExceeded++
+			metrics.MonitorSymbolDeferred.WithLabelValues(queue.symbol).Add(float64(deferred))
+			logrus.Debugf("币种 %s 时间预算耗尽，%d 个预估顺延到下一轮", queue.symbol, deferred)
+		} else {
+			s.cursors[queue.symbol] = 0
+		}
+	}
+}
+
+// getStats 获取或创建币种统计（调用方需持有锁）
+func (s *monitorScheduler) getStats(symbol string) *SymbolSchedulerStats {
+	stats, exists := s.stats[symbol]
+	if !exists {
+		stats = &SymbolSchedulerStats{Symbol: symbol}
+		s.stats[symbol] = stats
+	}
+	return stats
+}
+
+// GetStats 获取所有币种的调度统计
+func (s *monitorScheduler) GetStats() []SymbolSchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]SymbolSchedulerStats, 0, len(s.stats))
+	for _, stats := range s.stats {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Symbol < result[j].Symbol })
+	return result
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/metrics"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// skipLog 预估跳过记录器，同一预估同一原因在冷却时间内只记录一次
+type skipLog struct {
+	maxLen   int64
+	cooldown time.Duration
+
+	mu         sync.Mutex
+	lastLogged map[string]time.Time // estimateID:reason -> 最后记录时间
+}
+
+// newSkipLog 创建跳过记录器
+func newSkipLog(maxLen int64, cooldown time.Duration) *skipLog {
+	return &skipLog{
+		maxLen:     maxLen,
+		cooldown:   cooldown,
+		lastLogged: make(map[string]time.Time),
+	}
+}
+
+// shouldLog 判断是否需要记录，并清理冷却已结束的条目
+func (l *skipLog) shouldLog(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if last, exists := l.lastLogged[key]; exists && now.Sub(last) < l.cooldown {
+		return false
+	}
+	l.lastLogged[key] = now
+
+	if len(l.lastLogged) > 1000 {
+		for k, t := range l.lastLogged {
+			if now.Sub(t) >= l.cooldown {
+				delete(l.lastLogged, k)
+			}
+		}
+	}
+	return true
+}
+
+// recordSkip 记录一次满足评估条件但被跳过的预估
+func (pm *PriceMonitor) recordSkip(estimate *models.PriceEstimate, reason, format string, args ...interface{}) {
+	metrics.MonitorSkips.WithLabelValues(reason).Inc()
+
+	now := time.Now()
+	if pm.skipLog == nil || !pm.skipLog.shouldLog(estimate.ID+":"+reason, now) {
+		return
+	}
+
+	skip := &models.EvaluationSkip{
+		EstimateID:  estimate.ID,
+		Symbol:      estimate.Symbol,
+		Exchange:    estimate.Exchange,
+		Side:        estimate.Side,
+		ActionType:  estimate.ActionType,
+		TargetPrice: estimate.TargetPrice,
+		Reason:      reason,
+		Detail:      fmt.Sprintf(format, args...),
+		Timestamp:   now.UnixMilli(),
+	}
+	if err := redis.GlobalRedisClient.AddEvaluationSkip(skip, pm.skipLog.maxLen); err != nil {
+		logrus.Errorf("记录预估跳过失败: %v", err)
+	}
+	RecordEstimateEvent(estimate.ID, models.EstimateEventSkipped, skip.Detail, map[string]interface{}{"reason": reason})
+}
+
+// isStalePrice 判断价格数据是否已超过过期阈值
+func isStalePrice(markPriceData *types.WatchMarkPrice, threshold time.Duration, now time.Time) bool {
+	if threshold <= 0 || markPriceData.TimeStamp <= 0 {
+		return false
+	}
+	return now.Sub(time.UnixMilli(markPriceData.TimeStamp)) > threshold
+}
@@ -1,6 +1,57 @@
 package core
 
-import "trading_assistant/models"
+import (
+	"trading_assistant/models"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// estimateTriggerPrice 按预估的价格来源选择触发价格，来源价格缺失时降级使用标记价格
+// 未指定来源时做多使用卖价（买入成本），做空使用买价（卖出价格）
+func estimateTriggerPrice(estimate *models.PriceEstimate, markPrice *types.WatchMarkPrice) float64 {
+	var price float64
+	switch estimate.PriceSource {
+	case models.PriceSourceMark:
+		price = markPrice.MarkPrice
+	case models.PriceSourceLast:
+		price = markPrice.LastPrice
+	case models.PriceSourceIndex:
+		price = markPrice.IndexPrice
+	case models.PriceSourceBid:
+		price = markPrice.BidPrice
+	case models.PriceSourceAsk:
+		price = markPrice.AskPrice
+	default:
+		price = markPrice.AskPrice
+		if estimate.Side == types.PositionSideShort {
+			price = markPrice.BidPrice
+		}
+	}
+
+	if price <= 0 {
+		price = markPrice.MarkPrice
+	}
+	return price
+}
+
+// priceSourceText 获取触发价格来源的描述
+func priceSourceText(estimate *models.PriceEstimate) string {
+	switch estimate.PriceSource {
+	case models.PriceSourceMark:
+		return "标记价格"
+	case models.PriceSourceLast:
+		return "最新价"
+	case models.PriceSourceIndex:
+		return "指数价格"
+	case models.PriceSourceBid:
+		return "买价(bid)"
+	case models.PriceSourceAsk:
+		return "卖价(ask)"
+	}
+	if estimate.Side == types.PositionSideShort {
+		return "买价(bid)"
+	}
+	return "卖价(ask)"
+}
 
 // shouldTriggerLong 判断多头是否应该触发
 func shouldTriggerLong(actionType, triggerType string, currentPrice, targetPrice float64) bool {
@@ -20,6 +71,9 @@ func shouldTriggerLong(actionType, triggerType string, currentPrice, targetPrice
 	case models.ActionTypeTakeProfit:
 		// 止盈：当前价格 >= 目标价格时触发（高价卖出获利）
 		return currentPrice >= targetPrice
+	case models.ActionTypeStopLoss:
+		// 止损：当前价格 <= 目标价格时触发（价格不利时卖出止损）
+		return currentPrice <= targetPrice
 	default:
 		return false
 	}
@@ -43,6 +97,9 @@ func shouldTriggerShort(actionType, triggerType string, currentPrice, targetPric
 	case models.ActionTypeTakeProfit:
 		// 止盈：当前价格 <= 目标价格时触发（低价买入获利）
 		return currentPrice <= targetPrice
+	case models.ActionTypeStopLoss:
+		// 止损：当前价格 >= 目标价格时触发（价格不利时买入止损）
+		return currentPrice >= targetPrice
 	default:
 		return false
 	}
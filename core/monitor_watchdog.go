@@ -0,0 +1,229 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// watchdogCheckInterval 价格新鲜度检查间隔
+const watchdogCheckInterval = 5 * time.Second
+
+// watchdogFallbackTimeout REST补拉单个币种价格的超时
+const watchdogFallbackTimeout = 5 * time.Second
+
+// StaleSymbol 价格长时间未更新的币种
+type StaleSymbol struct {
+	Exchange       string `json:"exchange,omitempty"`
+	Symbol         string `json:"symbol"` // MarketID
+	Since          int64  `json:"since"`  // 首次发现过期的时间（毫秒）
+	Fallbacks      int    `json:"fallbacks"`
+	LastFallbackAt int64  `json:"last_fallback_at"` // 最近一次REST补拉写入的价格时间戳（毫秒）
+	ResubscribedAt int64  `json:"resubscribed_at,omitempty"`
+	Alerted        bool   `json:"alerted"`
+}
+
+// priceWatchdog 按币种跟踪有监听预估的价格新鲜度
+// 价格超过阈值未更新时先用REST补拉单个币种，同时重启该交易所的价格订阅，重启后仍未恢复则告警
+type priceWatchdog struct {
+	threshold           time.Duration
+	resubscribeCooldown time.Duration
+
+	mu              sync.Mutex
+	stale           map[string]*StaleSymbol // exchange|symbol -> 状态
+	lastResubscribe map[string]time.Time    // exchange -> 最近一次重启订阅时间
+}
+
+// newPriceWatchdog 创建价格新鲜度看门狗，threshold 为0时不检查
+func newPriceWatchdog(threshold, resubscribeCooldown time.Duration) *priceWatchdog {
+	return &priceWatchdog{
+		threshold:           threshold,
+		resubscribeCooldown: resubscribeCooldown,
+		stale:               make(map[string]*StaleSymbol),
+		lastResubscribe:     make(map[string]time.Time),
+	}
+}
+
+// GetStaleSymbols 获取当前价格过期的币种，按发现时间排序
+func (pm *PriceMonitor) GetStaleSymbols() []*StaleSymbol {
+	w := pm.watchdog
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	result := make([]*StaleSymbol, 0, len(w.stale))
+	for _, stale := range w.stale {
+		copied := *stale
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Since < result[j].Since })
+	return result
+}
+
+// checkPriceFreshness 检查所有有监听预估的币种价格是否仍在更新
+func (pm *PriceMonitor) checkPriceFreshness() {
+	w := pm.watchdog
+	// 回放模式下不使用REST补拉和重启订阅，避免实时价格混入回放
+	if w.threshold <= 0 || GlobalStreamReplayer != nil {
+		return
+	}
+
+	estimates, err := redis.GlobalRedisClient.GetActiveEstimates()
+	if err != nil {
+		logrus.Errorf("获取价格预估失败: %v", err)
+		return
+	}
+
+	now := time.Now()
+	watched := make(map[string]bool)
+	for _, estimate := range estimates {
+		symbol := ResolveMarketID(estimate.Exchange, estimate.Symbol)
+		key := ExchangeNamespace(estimate.Exchange) + "|" + symbol
+		if watched[key] {
+			continue
+		}
+		watched[key] = true
+		w.check(estimate.Exchange, symbol, key, now)
+	}
+
+	// 没有监听预估的币种不再跟踪
+	w.mu.Lock()
+	for key := range w.stale {
+		if !watched[key] {
+			delete(w.stale, key)
+		}
+	}
+	w.mu.Unlock()
+}
+
+// check 检查单个币种，价格流恢复更新时清除过期标记
+func (w *priceWatchdog) check(exchange, symbol, key string, now time.Time) {
+	data, _ := ExchangeStore(exchange).GetMarkPrice(symbol)
+
+	w.mu.Lock()
+	stale := w.stale[key]
+	w.mu.Unlock()
+
+	if data != nil && !isStalePrice(data, w.threshold, now) {
+		// 只有价格订阅写入的新数据才算恢复，REST补拉的数据不算
+		if stale != nil && data.TimeStamp > stale.LastFallbackAt {
+			w.markRecovered(key, stale, now)
+		}
+		return
+	}
+
+	if stale == nil {
+		stale = &StaleSymbol{Exchange: ExchangeNamespace(exchange), Symbol: symbol, Since: now.UnixMilli()}
+		w.mu.Lock()
+		w.stale[key] = stale
+		w.mu.Unlock()
+		logrus.Warnf("%s 价格已超过 %v 未更新，使用REST补拉并重启价格订阅", symbol, w.threshold)
+	}
+
+	w.fallback(exchange, stale)
+	w.resubscribe(exchange, stale, now)
+
+	// 重启订阅后仍未恢复时告警一次
+	w.mu.Lock()
+	alert := !stale.Alerted && stale.ResubscribedAt > 0 && now.Sub(time.UnixMilli(stale.ResubscribedAt)) > w.threshold
+	if alert {
+		stale.Alerted = true
+	}
+	w.mu.Unlock()
+	if alert {
+		notify.Send(notify.EventStale, i18n.T("notify.stale.title"),
+			i18n.T("notify.stale.message", symbol, i18n.FormatMillis(stale.Since), stale.Fallbacks),
+			map[string]interface{}{"exchange": stale.Exchange, "symbol": symbol})
+	}
+}
+
+// fallback 通过REST获取单个币种的价格写入缓存，保证预估仍能被评估
+func (w *priceWatchdog) fallback(exchange string, stale *StaleSymbol) {
+	client, exists := ExchangeClient(exchange)
+	if !exists {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), watchdogFallbackTimeout)
+	defer cancel()
+
+	tickers, err := client.FetchBookTickers(ctx, []string{stale.Symbol}, nil)
+	if err != nil {
+		logrus.Warnf("REST补拉 %s 买卖价失败: %v", stale.Symbol, err)
+	}
+	var markPrice *types.MarkPrice
+	if client.GetMarketType() != types.MarketTypeSpot {
+		if markPrice, err = client.FetchMarkPrice(ctx, stale.Symbol); err != nil {
+			logrus.Warnf("REST补拉 %s 标记价格失败: %v", stale.Symbol, err)
+		}
+	}
+
+	ticker := tickers[stale.Symbol]
+	if ticker == nil && markPrice == nil {
+		return
+	}
+	watchMarkPrice := buildWatchMarkPrice(stale.Symbol, ticker, markPrice)
+	if watchMarkPrice.BidPrice <= 0 || watchMarkPrice.AskPrice <= 0 {
+		return
+	}
+	if err := ExchangeStore(exchange).SetMarkPrice(watchMarkPrice); err != nil {
+		logrus.Errorf("保存 %s 补拉价格失败: %v", stale.Symbol, err)
+		return
+	}
+
+	w.mu.Lock()
+	stale.Fallbacks++
+	stale.LastFallbackAt = watchMarkPrice.TimeStamp
+	w.mu.Unlock()
+}
+
+// resubscribe 重启交易所的价格订阅，同一交易所在冷却时间内只重启一次
+func (w *priceWatchdog) resubscribe(exchange string, stale *StaleSymbol, now time.Time) {
+	namespace := ExchangeNamespace(exchange)
+
+	w.mu.Lock()
+	if stale.ResubscribedAt > 0 {
+		w.mu.Unlock()
+		return
+	}
+	last := w.lastResubscribe[namespace]
+	stale.ResubscribedAt = now.UnixMilli()
+	if now.Sub(last) < w.resubscribeCooldown {
+		w.mu.Unlock()
+		return
+	}
+	w.lastResubscribe[namespace] = now
+	w.mu.Unlock()
+
+	priceManager, exists := PriceManagerFor(exchange)
+	if !exists {
+		return
+	}
+	if err := priceManager.Restart(); err != nil {
+		logrus.Errorf("重启 %s 价格订阅失败: %v", priceManager.exchangeClient.GetID(), err)
+		return
+	}
+	GetDataQualityTracker().RecordReconnect(priceManager.exchangeClient.GetID())
+	logrus.Warnf("%s 价格长时间未更新，已重启 %s 价格订阅", stale.Symbol, priceManager.exchangeClient.GetID())
+}
+
+// markRecovered 价格订阅恢复更新，清除过期标记
+func (w *priceWatchdog) markRecovered(key string, stale *StaleSymbol, now time.Time) {
+	w.mu.Lock()
+	delete(w.stale, key)
+	w.mu.Unlock()
+
+	duration := now.Sub(time.UnixMilli(stale.Since)).Round(time.Second)
+	logrus.Infof("%s 价格已恢复更新，过期持续 %v", stale.Symbol, duration)
+	if stale.Alerted {
+		notify.Send(notify.EventStale, i18n.T("notify.stale_recovered.title"),
+			i18n.T("notify.stale_recovered.message", stale.Symbol, duration),
+			map[string]interface{}{"exchange": stale.Exchange, "symbol": stale.Symbol})
+	}
+}
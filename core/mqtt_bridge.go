@@ -0,0 +1,156 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/sirupsen/logrus"
+)
+
+// mqttPublishTimeout 单条消息等待broker确认的时长（QoS>0）
+const mqttPublishTimeout = 5 * time.Second
+
+// MQTTBridge 将标记价格和价格预估触发事件发布到MQTT broker
+// 主题: {prefix}prices/{exchange}/{symbol}（保留消息可配置）和 {prefix}triggers/{symbol}
+// 只在主节点运行，避免多个实例重复发布
+type MQTTBridge struct {
+	client        mqtt.Client
+	prefix        string
+	qos           byte
+	retainPrices  bool
+	retainEvents  bool
+	symbols       map[string]bool // 为空时发布所有币种的价格
+	primary       string          // 主交易所名称，用于价格主题
+	unsubscribers []func()
+}
+
+var GlobalMQTTBridge *MQTTBridge
+
+// InitMQTTBridge 初始化MQTT桥接，未启用时不创建
+func InitMQTTBridge() {
+	cfg := config.GlobalConfig
+	if !cfg.MQTTEnabled {
+		return
+	}
+
+	qos := cfg.MQTTQoS
+	if qos < 0 || qos > 2 {
+		logrus.Warnf("MQTT_QOS=%d 无效，使用 0", qos)
+		qos = 0
+	}
+	symbols := make(map[string]bool, len(cfg.MQTTPriceSymbols))
+	for _, symbol := range cfg.MQTTPriceSymbols {
+		symbols[strings.ToUpper(strings.TrimSpace(symbol))] = true
+	}
+
+	options := mqtt.NewClientOptions().
+		AddBroker(cfg.MQTTBroker).
+		SetClientID(cfg.MQTTClientID).
+		SetUsername(cfg.MQTTUsername).
+		SetPassword(cfg.MQTTPassword).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logrus.Warnf("MQTT连接断开，自动重连中: %v", err)
+		}).
+		SetOnConnectHandler(func(mqtt.Client) {
+			logrus.Infof("MQTT已连接: %s", cfg.MQTTBroker)
+		})
+
+	GlobalMQTTBridge = &MQTTBridge{
+		client:       mqtt.NewClient(options),
+		prefix:       cfg.MQTTTopicPrefix,
+		qos:          byte(qos),
+		retainPrices: cfg.MQTTRetainPrices,
+		retainEvents: cfg.MQTTRetainEvents,
+		symbols:      symbols,
+		primary:      strings.ToLower(cfg.ExchangeType),
+	}
+}
+
+// Start 连接broker并订阅价格和触发事件，broker不可用时后台重试连接
+func (mb *MQTTBridge) Start() {
+	if mb == nil || len(mb.unsubscribers) > 0 {
+		return
+	}
+
+	// SetConnectRetry 使首次连接失败时在后台重试，这里不阻塞主节点任务启动
+	mb.client.Connect()
+
+	bus := eventbus.GetBus()
+	buffer := config.GlobalConfig.EventBusBuffer
+	mb.unsubscribers = []func(){
+		bus.Subscribe(eventbus.TopicMarkPrice, "mqtt", buffer, mb.publishPrices),
+		bus.Subscribe(eventbus.TopicEstimateTriggered, "mqtt", buffer, mb.publishTrigger),
+	}
+	logrus.Infof("MQTT桥接已启动，QoS: %d, 价格保留消息: %v", mb.qos, mb.retainPrices)
+}
+
+// Stop 取消订阅并断开连接
+func (mb *MQTTBridge) Stop() {
+	if mb == nil || len(mb.unsubscribers) == 0 {
+		return
+	}
+
+	for _, unsubscribe := range mb.unsubscribers {
+		unsubscribe()
+	}
+	mb.unsubscribers = nil
+	mb.client.Disconnect(uint(mqttPublishTimeout.Milliseconds()))
+	logrus.Info("MQTT桥接已停止")
+}
+
+// publishPrices 发布一轮价格
+func (mb *MQTTBridge) publishPrices(event *eventbus.Event) {
+	batch, ok := event.Payload.(*eventbus.MarkPriceBatch)
+	if !ok || !mb.client.IsConnectionOpen() {
+		return
+	}
+
+	exchange := strings.ToLower(event.Exchange)
+	if exchange == "" {
+		exchange = mb.primary
+	}
+	for symbol, price := range batch.Prices {
+		if len(mb.symbols) > 0 && !mb.symbols[symbol] {
+			continue
+		}
+		mb.publish(fmt.Sprintf("prices/%s/%s", exchange, symbol), mb.retainPrices, price)
+	}
+}
+
+// publishTrigger 发布价格预估触发结果
+func (mb *MQTTBridge) publishTrigger(event *eventbus.Event) {
+	estimate, ok := event.Payload.(*models.PriceEstimate)
+	if !ok || !mb.client.IsConnectionOpen() {
+		return
+	}
+	mb.publish(fmt.Sprintf("triggers/%s", estimate.Symbol), mb.retainEvents, estimate)
+}
+
+// publish 序列化并发布消息，QoS 0 不等待确认
+func (mb *MQTTBridge) publish(topic string, retain bool, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		logrus.Errorf("序列化MQTT消息失败: %v", err)
+		return
+	}
+
+	token := mb.client.Publish(mb.prefix+topic, mb.qos, retain, data)
+	if mb.qos == 0 {
+		return
+	}
+	if !token.WaitTimeout(mqttPublishTimeout) {
+		logrus.Warnf("MQTT发布超时: %s", mb.prefix+topic)
+		return
+	}
+	if err := token.Error(); err != nil {
+		logrus.Warnf("MQTT发布失败 %s: %v", mb.prefix+topic, err)
+	}
+}
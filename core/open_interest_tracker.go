@@ -0,0 +1,324 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchange_factory"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/metrics"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/websocket"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AlertTypeOpenInterest 持仓量告警类型
+const AlertTypeOpenInterest = "open_interest"
+
+// openInterestFetchConcurrency 单轮并发获取持仓量的数量
+const openInterestFetchConcurrency = 5
+
+// openInterestWindow 持仓量变化统计窗口
+type openInterestWindow struct {
+	name      string
+	duration  time.Duration
+	threshold float64 // 变化绝对值告警阈值(%)，0 表示不告警
+}
+
+// OpenInterestTracker 合约持仓量跟踪器
+// 定时拉取选中币种在各交易所的持仓量保存为时间序列，5分钟/1小时变化超过阈值时告警。只在主节点运行
+type OpenInterestTracker struct {
+	fetchers  map[string]exchange_factory.OpenInterestFetcher // exchangeID -> 持仓量查询能力
+	interval  time.Duration
+	retention time.Duration
+	cooldown  time.Duration
+	windows   []openInterestWindow
+
+	mu        sync.Mutex
+	lastAlert map[string]time.Time // exchange:symbol:window -> 最后告警时间
+	cancel    context.CancelFunc
+	running   bool
+}
+
+var GlobalOpenInterestTracker *OpenInterestTracker
+
+// InitOpenInterestTracker 初始化持仓量跟踪器，未启用或所有交易所都不支持持仓量查询时不创建
+func InitOpenInterestTracker(clients ...exchange_factory.ExchangeInterface) {
+	cfg := config.GlobalConfig
+	if !cfg.OpenInterestEnabled {
+		return
+	}
+
+	fetchers := make(map[string]exchange_factory.OpenInterestFetcher)
+	for _, client := range clients {
+		fetcher, err := exchange_factory.AsOpenInterestFetcher(client)
+		if err != nil {
+			logrus.Warnf("%v，跳过该交易所的持仓量跟踪", err)
+			continue
+		}
+		fetchers[strings.ToLower(client.GetID())] = fetcher
+	}
+	if len(fetchers) == 0 {
+		return
+	}
+
+	interval := cfg.OpenInterestInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	GlobalOpenInterestTracker = &OpenInterestTracker{
+		fetchers:  fetchers,
+		interval:  interval,
+		retention: cfg.OpenInterestRetention,
+		cooldown:  cfg.OpenInterestAlertCooldown,
+		windows: []openInterestWindow{
+			{name: "5m", duration: 5 * time.Minute, threshold: cfg.OpenInterestAlertPct5m},
+			{name: "1h", duration: time.Hour, threshold: cfg.OpenInterestAlertPct1h},
+		},
+		lastAlert: make(map[string]time.Time),
+	}
+}
+
+// Start 启动持仓量跟踪
+func (ot *OpenInterestTracker) Start() {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+
+	if ot.running {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ot.cancel = cancel
+	ot.running = true
+
+	go ot.run(ctx)
+	logrus.Infof("持仓量跟踪器已启动，采样间隔: %v", ot.interval)
+}
+
+// Stop 停止持仓量跟踪
+func (ot *OpenInterestTracker) Stop() {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+
+	if !ot.running {
+		return
+	}
+	ot.cancel()
+	ot.running = false
+	logrus.Info("持仓量跟踪器已停止")
+}
+
+// run 主运行循环
+func (ot *OpenInterestTracker) run(ctx context.Context) {
+	ticker := time.NewTicker(ot.interval)
+	defer ticker.Stop()
+
+	ot.pollOnce(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ot.pollOnce(ctx)
+		}
+	}
+}
+
+// pollOnce 拉取一轮所有交易所选中币种的持仓量
+func (ot *OpenInterestTracker) pollOnce(ctx context.Context) {
+	symbols, err := redis.GlobalRedisClient.GetSelectedCoinMarketIDs()
+	if err != nil {
+		logrus.Errorf("获取选中币种列表失败: %v", err)
+		return
+	}
+
+	sem := make(chan struct{}, openInterestFetchConcurrency)
+	var wg sync.WaitGroup
+	for exchange, fetcher := range ot.fetchers {
+		for _, symbol := range symbols {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(exchange string, fetcher exchange_factory.OpenInterestFetcher, symbol string) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				ot.pollSymbol(ctx, exchange, fetcher, symbol)
+			}(exchange, fetcher, symbol)
+		}
+	}
+	wg.Wait()
+}
+
+// pollSymbol 拉取单个币种的持仓量，保存采样并检查变化率
+func (ot *OpenInterestTracker) pollSymbol(ctx context.Context, exchange string, fetcher exchange_factory.OpenInterestFetcher, symbol string) {
+	fetchCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	openInterest, err := fetcher.FetchOpenInterest(fetchCtx, symbol)
+	metrics.ExchangeRequests.WithLabelValues(exchange, "open_interest", metrics.ResultLabel(err)).Inc()
+	if err != nil {
+		logrus.Debugf("获取 %s %s 持仓量失败: %v", exchange, symbol, err)
+		return
+	}
+
+	store := ExchangeStore(exchange)
+	point := &models.OpenInterestPoint{
+		Timestamp:    openInterest.Timestamp,
+		OpenInterest: openInterest.OpenInterest,
+		Value:        openInterest.Value,
+	}
+	if markPrice, err := store.GetMarkPrice(symbol); err == nil {
+		point.Price = markPrice.MarkPrice
+		if point.Price <= 0 {
+			point.Price = markPrice.LastPrice
+		}
+	}
+	if point.Value <= 0 {
+		point.Value = point.OpenInterest * point.Price
+	}
+	if err := store.AddOpenInterestPoint(symbol, point, ot.retention); err != nil {
+		logrus.Errorf("保存 %s 持仓量失败: %v", symbol, err)
+		return
+	}
+
+	ot.checkAlerts(store, exchange, symbol, point)
+}
+
+// checkAlerts 各统计窗口内持仓量变化超过阈值时告警
+func (ot *OpenInterestTracker) checkAlerts(store *redis.Client, exchange, symbol string, latest *models.OpenInterestPoint) {
+	var longest time.Duration
+	for _, window := range ot.windows {
+		if window.threshold > 0 && window.duration > longest {
+			longest = window.duration
+		}
+	}
+	if longest == 0 {
+		return
+	}
+
+	// 多取一个采样间隔，保证窗口起点之前有采样
+	since := latest.Timestamp - (longest + 2*ot.interval).Milliseconds()
+	points, err := store.GetOpenInterestPoints(symbol, since)
+	if err != nil {
+		logrus.Debugf("获取 %s 持仓量历史失败: %v", symbol, err)
+		return
+	}
+
+	for _, window := range ot.windows {
+		if window.threshold <= 0 {
+			continue
+		}
+		change, ok := openInterestChange(points, latest, window.duration, ot.interval)
+		if !ok || math.Abs(change) < window.threshold {
+			continue
+		}
+
+		key := exchange + ":" + symbol + ":" + window.name
+		now := time.Now()
+		ot.mu.Lock()
+		if last, exists := ot.lastAlert[key]; exists && now.Sub(last) < ot.cooldown {
+			ot.mu.Unlock()
+			continue
+		}
+		ot.lastAlert[key] = now
+		ot.mu.Unlock()
+
+		ot.alert(&models.OpenInterestAlert{
+			Symbol:       symbol,
+			Exchange:     exchange,
+			Window:       window.name,
+			ChangePct:    change,
+			Threshold:    window.threshold,
+			OpenInterest: latest.OpenInterest,
+			Value:        latest.Value,
+			Price:        latest.Price,
+			Timestamp:    latest.Timestamp,
+		})
+	}
+}
+
+// alert 推送持仓量告警到前端和通知渠道
+func (ot *OpenInterestTracker) alert(alert *models.OpenInterestAlert) {
+	direction := "增加"
+	if alert.ChangePct < 0 {
+		direction = "减少"
+	}
+	message := fmt.Sprintf("%s %s 持仓量 %s 内%s %.2f%%，当前 %.0f USDT",
+		alert.Exchange, alert.Symbol, alert.Window, direction, math.Abs(alert.ChangePct), alert.Value)
+	logrus.Warnf("持仓量告警: %s", message)
+
+	if wsManager := websocket.GetGlobalWebSocketManager(); wsManager != nil {
+		wsManager.BroadcastAlert(AlertTypeOpenInterest, alert)
+	}
+	notify.Send(notify.EventOpenInterest, i18n.T("notify.open_interest.title"), message, map[string]interface{}{
+		"symbol":     alert.Symbol,
+		"exchange":   alert.Exchange,
+		"window":     alert.Window,
+		"change_pct": alert.ChangePct,
+		"value":      alert.Value,
+	})
+}
+
+// openInterestChange 计算最新采样相对窗口起点的持仓量变化(%)，按合约数量计算以排除价格影响
+// 取窗口起点及之前最近的采样，起点之前超过 tolerance 没有采样时视为历史不足
+func openInterestChange(points []*models.OpenInterestPoint, latest *models.OpenInterestPoint, window, tolerance time.Duration) (float64, bool) {
+	target := latest.Timestamp - window.Milliseconds()
+	var base *models.OpenInterestPoint
+	for _, point := range points {
+		if point.Timestamp > target {
+			break
+		}
+		base = point
+	}
+	if base == nil || base.OpenInterest <= 0 || target-base.Timestamp > (2*tolerance).Milliseconds() {
+		return 0, false
+	}
+	return (latest.OpenInterest - base.OpenInterest) / base.OpenInterest * 100, true
+}
+
+// GetOpenInterestSummary 获取最近 hours 小时的持仓量时间序列和5分钟/1小时变化率
+func GetOpenInterestSummary(exchange, symbol string, hours int) (*models.OpenInterestSummary, error) {
+	interval := config.GlobalConfig.OpenInterestInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour).UnixMilli()
+	// 变化率至少需要1小时的历史
+	historySince := min(since, time.Now().Add(-time.Hour-2*interval).UnixMilli())
+	history, err := ExchangeStore(exchange).GetOpenInterestPoints(symbol, historySince)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.OpenInterestSummary{
+		Symbol:   symbol,
+		Exchange: strings.ToLower(exchange),
+		Points:   make([]*models.OpenInterestPoint, 0, len(history)),
+	}
+	for _, point := range history {
+		if point.Timestamp >= since {
+			summary.Points = append(summary.Points, point)
+		}
+	}
+	if len(history) == 0 {
+		return summary, nil
+	}
+
+	summary.Latest = history[len(history)-1]
+	if change, ok := openInterestChange(history, summary.Latest, 5*time.Minute, interval); ok {
+		summary.Change5mPct = &change
+	}
+	if change, ok := openInterestChange(history, summary.Latest, time.Hour, interval); ok {
+		summary.Change1hPct = &change
+	}
+	return summary, nil
+}
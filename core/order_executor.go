@@ -1,13 +1,14 @@
 package core
 
 import (
+	"context"
 	"fmt"
-	"time"
+	"sync"
 	"trading_assistant/models"
 	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
 	"trading_assistant/pkg/exchanges/types"
 	"trading_assistant/pkg/freqtrade"
-	"trading_assistant/pkg/redis"
 	"trading_assistant/pkg/utils"
 
 	"github.com/sirupsen/logrus"
@@ -15,14 +16,31 @@ import (
 
 // OrderExecutor 订单执行器
 type OrderExecutor struct {
-	freqtradeClient *freqtrade.Controller
+	bots     *freqtrade.Registry
+	verifier *ExecutionVerifier
+
+	sliceMu      sync.Mutex
+	sliceCtx     context.Context // 后台拆单的停止信号，价格监控停止时取消
+	cancelSlices context.CancelFunc
 }
 
 // NewOrderExecutor 创建订单执行器
-func NewOrderExecutor(freqtradeClient *freqtrade.Controller) *OrderExecutor {
+func NewOrderExecutor(bots *freqtrade.Registry) *OrderExecutor {
+	sliceCtx, cancelSlices := context.WithCancel(context.Background())
 	return &OrderExecutor{
-		freqtradeClient: freqtradeClient,
+		bots:         bots,
+		verifier:     NewExecutionVerifier(),
+		sliceCtx:     sliceCtx,
+		cancelSlices: cancelSlices,
+	}
+}
+
+// bot 选择预估下单使用的Freqtrade实例
+func (oe *OrderExecutor) bot(estimate *models.PriceEstimate) (*freqtrade.Controller, error) {
+	if oe.bots == nil {
+		return nil, fmt.Errorf("freqtrade客户端未初始化")
 	}
+	return oe.bots.Resolve(estimate.BotName, estimate.Side)
 }
 
 // getMarketType 获取当前市场类型
@@ -33,18 +51,50 @@ func (oe *OrderExecutor) getMarketType() string {
 	return types.MarketTypeFuture // 默认期货
 }
 
-// convertSymbol 根据市场类型转换 MarketID 为 Symbol
+// convertSymbol 转换 MarketID 为 Freqtrade 交易对，市场数据未加载时按市场类型推断
 func (oe *OrderExecutor) convertSymbol(marketID string) string {
+	if pair := SymbolMapper("").Unified(marketID); pair != "" {
+		return pair
+	}
 	return utils.ConvertMarketIDToSymbol(marketID, oe.getMarketType())
 }
 
-// ExecuteOrder 执行订单
-func (oe *OrderExecutor) ExecuteOrder(estimate *models.PriceEstimate, currentPrice float64) error {
-	if oe.freqtradeClient == nil {
-		return fmt.Errorf("freqtrade客户端未初始化")
+// ExecuteOrder 执行订单，ctx 携带 freqtrade.WithResponseRecorder 时回调Freqtrade下单响应
+func (oe *OrderExecutor) ExecuteOrder(ctx context.Context, estimate *models.PriceEstimate, currentPrice float64) error {
+	// 紧急停止期间拒绝所有下单，防止已进入下单流程的预估继续执行
+	if IsKillSwitchActive() {
+		return ErrKillSwitchActive
+	}
+
+	// 禁止交易名单中的币种只允许减仓，防止绕过创建校验的预估开仓
+	if isIncreaseAction(estimate.ActionType) {
+		if err := CheckSymbolAllowed(estimate.Symbol); err != nil {
+			return err
+		}
+	}
+
+	// 下单前按币种限制检查数量，避免被Freqtrade或交易所静默拒绝
+	if err := applyOrderSizing(estimate, currentPrice); err != nil {
+		return err
+	}
+
+	// 开仓和加仓按持仓名义价值、持仓数量、杠杆和单笔保证金限制检查
+	if err := checkRiskLimits(ctx, estimate, currentPrice); err != nil {
+		return err
+	}
+
+	// 模拟交易模式下不向Freqtrade下单
+	if config.GlobalConfig != nil && config.GlobalConfig.DryRun {
+		return oe.executePaperOrder(estimate, currentPrice)
+	}
+
+	client, err := oe.bot(estimate)
+	if err != nil {
+		return err
 	}
 
 	logrus.WithFields(logrus.Fields{
+		"bot":           client.Name,
 		"symbol":        estimate.Symbol,
 		"action_type":   estimate.ActionType,
 		"side":          estimate.Side,
@@ -53,16 +103,29 @@ func (oe *OrderExecutor) ExecuteOrder(estimate *models.PriceEstimate, currentPri
 		"current_price": currentPrice,
 	}).Info("开始执行Freqtrade订单")
 
+	// 记录下单前持仓，用于验证执行结果
+	pair := oe.convertSymbol(estimate.Symbol)
+	var before positionSnapshot
+	if oe.verifier.Enabled() {
+		before = oe.verifier.Snapshot(client, pair, estimate.Side)
+	}
+
 	// 执行下单
-	err := oe.executeFreqtradeOrder(estimate, currentPrice)
+	err = oe.executeFreqtradeOrder(ctx, client, estimate, currentPrice)
 	if err != nil {
 		return fmt.Errorf("freqtrade下单失败: %v", err)
 	}
 
+	// 验证窗口内确认持仓变化
+	if oe.verifier.Enabled() {
+		oe.verifier.Watch(client, estimate, pair, before)
+	}
+
 	// 更新预估状态
 	if err := oe.updateEstimateStatus(estimate, "triggered"); err != nil {
 		logrus.Errorf("更新预估状态失败: %v", err)
 	}
+	eventbus.GetBus().Publish(eventbus.TopicOrder, "", estimate)
 
 	logrus.WithFields(logrus.Fields{
 		"symbol":        estimate.Symbol,
@@ -75,26 +138,58 @@ func (oe *OrderExecutor) ExecuteOrder(estimate *models.PriceEstimate, currentPri
 	return nil
 }
 
+// executePaperOrder 模拟成交
+func (oe *OrderExecutor) executePaperOrder(estimate *models.PriceEstimate, currentPrice float64) error {
+	fill, err := GetPaperEngine().Execute(estimate, currentPrice)
+	if err != nil {
+		return fmt.Errorf("模拟下单失败: %v", err)
+	}
+
+	if err := oe.updateEstimateStatus(estimate, "triggered"); err != nil {
+		logrus.Errorf("更新预估状态失败: %v", err)
+	}
+	eventbus.GetBus().Publish(eventbus.TopicOrder, "", estimate)
+
+	logrus.WithFields(logrus.Fields{
+		"symbol":       estimate.Symbol,
+		"action_type":  estimate.ActionType,
+		"side":         estimate.Side,
+		"price":        fill.Price,
+		"amount":       fill.Amount,
+		"fee":          fill.Fee,
+		"realized_pnl": fill.RealizedPnl,
+	}).Info("模拟订单成交")
+
+	return nil
+}
+
 // executeFreqtradeOrder 执行下单
-func (oe *OrderExecutor) executeFreqtradeOrder(estimate *models.PriceEstimate, currentPrice float64) error {
+func (oe *OrderExecutor) executeFreqtradeOrder(ctx context.Context, client *freqtrade.Controller, estimate *models.PriceEstimate, currentPrice float64) error {
+	// 配置了拆单策略时分多笔执行
+	if estimate.OrderStrategy.IsSliced() {
+		return oe.executeSliced(ctx, client, estimate, currentPrice)
+	}
+
 	switch estimate.ActionType {
 	case models.ActionTypeOpen:
-		return oe.executeOpenPosition(estimate, currentPrice)
+		return oe.executeOpenPosition(ctx, client, estimate, currentPrice)
 	case models.ActionTypeAddition:
-		return oe.executeAddPosition(estimate, currentPrice)
+		return oe.executeAddPosition(ctx, client, estimate, currentPrice)
 	case models.ActionTypeTakeProfit:
-		return oe.executeTakeProfit(estimate, currentPrice)
+		return oe.executeTakeProfit(ctx, client, estimate, currentPrice)
+	case models.ActionTypeStopLoss:
+		return oe.executeStopLoss(ctx, client, estimate, currentPrice)
 	default:
 		return fmt.Errorf("不支持的操作类型: %s", estimate.ActionType)
 	}
 }
 
 // executeOpenPosition 开仓
-func (oe *OrderExecutor) executeOpenPosition(estimate *models.PriceEstimate, currentPrice float64) error {
+func (oe *OrderExecutor) executeOpenPosition(ctx context.Context, client *freqtrade.Controller, estimate *models.PriceEstimate, currentPrice float64) error {
 	symbol := oe.convertSymbol(estimate.Symbol)
 
 	// 检查是否可以开仓
-	if !oe.freqtradeClient.CheckForceBuy(symbol) {
+	if !client.CheckForceBuy(ctx, symbol) {
 		return fmt.Errorf("无法开仓: 达到最大持仓数量或交易对已存在持仓")
 	}
 
@@ -103,7 +198,8 @@ func (oe *OrderExecutor) executeOpenPosition(estimate *models.PriceEstimate, cur
 		orderType = "limit"
 	}
 
-	orderPrice := currentPrice
+	// 下单价格按价格步长取整
+	orderPrice := Formatter(estimate.Exchange).RoundToTick(estimate.Symbol, currentPrice)
 
 	entryTag := estimate.Tag
 	if entryTag == "" {
@@ -145,12 +241,15 @@ func (oe *OrderExecutor) executeOpenPosition(estimate *models.PriceEstimate, cur
 		"target_price":  estimate.TargetPrice,
 	}).Info("执行开仓订单")
 
-	return oe.freqtradeClient.ForceBuy(payload)
+	// 确保交易所杠杆与预估一致（已同步过时不会重复请求）
+	ApplyEstimateLeverage(estimate)
+
+	return client.ForceBuy(ctx, payload)
 }
 
 // executeAddPosition 加仓
-func (oe *OrderExecutor) executeAddPosition(estimate *models.PriceEstimate, currentPrice float64) error {
-	positions, err := oe.freqtradeClient.GetPositions()
+func (oe *OrderExecutor) executeAddPosition(ctx context.Context, client *freqtrade.Controller, estimate *models.PriceEstimate, currentPrice float64) error {
+	positions, err := client.GetPositions(ctx)
 	if err != nil {
 		return fmt.Errorf("获取仓位信息失败: %v", err)
 	}
@@ -184,7 +283,8 @@ func (oe *OrderExecutor) executeAddPosition(estimate *models.PriceEstimate, curr
 	// freqtrade 下单时候的初始仓位
 	stakeCost := *cost  * (estimate.Percentage / 100.0) / *existingPosition.Leverage
 
-	orderPrice := currentPrice
+	// 下单价格按价格步长取整
+	orderPrice := Formatter(estimate.Exchange).RoundToTick(estimate.Symbol, currentPrice)
 
 	logrus.WithFields(logrus.Fields{
 		"symbol":            estimate.Symbol,
@@ -208,7 +308,7 @@ func (oe *OrderExecutor) executeAddPosition(estimate *models.PriceEstimate, curr
 		entryTag = fmt.Sprintf("add_%s", estimate.Side)
 	}
 
-	return oe.freqtradeClient.ForceAdjustBuy(
+	return client.ForceAdjustBuy(ctx,
 		symbol,
 		orderPrice,
 		side,
@@ -218,14 +318,19 @@ func (oe *OrderExecutor) executeAddPosition(estimate *models.PriceEstimate, curr
 }
 
 // executeTakeProfit 止盈
-func (oe *OrderExecutor) executeTakeProfit(estimate *models.PriceEstimate, currentPrice float64) error {
-	return oe.executeSellOperation(estimate, currentPrice, "take_profit")
+func (oe *OrderExecutor) executeTakeProfit(ctx context.Context, client *freqtrade.Controller, estimate *models.PriceEstimate, currentPrice float64) error {
+	return oe.executeSellOperation(ctx, client, estimate, currentPrice, "take_profit")
+}
+
+// executeStopLoss 止损
+func (oe *OrderExecutor) executeStopLoss(ctx context.Context, client *freqtrade.Controller, estimate *models.PriceEstimate, currentPrice float64) error {
+	return oe.executeSellOperation(ctx, client, estimate, currentPrice, "stop_loss")
 }
 
 // executeSellOperation 执行卖出操作
-func (oe *OrderExecutor) executeSellOperation(estimate *models.PriceEstimate, currentPrice float64, operation string) error {
+func (oe *OrderExecutor) executeSellOperation(ctx context.Context, client *freqtrade.Controller, estimate *models.PriceEstimate, currentPrice float64, operation string) error {
 	// 获取当前交易状态
-	trades, err := oe.freqtradeClient.GetTradeStatus()
+	trades, err := client.GetTradeStatus(ctx)
 	if err != nil {
 		return fmt.Errorf("获取交易状态失败: %v", err)
 	}
@@ -259,35 +364,72 @@ func (oe *OrderExecutor) executeSellOperation(estimate *models.PriceEstimate, cu
 		orderType = "limit"
 	}
 
-	var sellAmount float64
-	if estimate.Amount > 0 {
-		sellAmount = estimate.Amount
-	} else if estimate.StakeAmount > 0 {
-		// 仍然支持旧的逻辑（虽然这里 StakeAmount 是 USDT，但旧逻辑可能直接透传了）
-		sellAmount = estimate.StakeAmount
-	} else {
-		return fmt.Errorf("止盈操作必须指定 amount 或 stake_amount")
+	sellAmount, err := oe.calculateExitAmount(estimate, targetTrade)
+	if err != nil {
+		return err
 	}
 
 	logrus.WithFields(logrus.Fields{
 		"symbol":          estimate.Symbol,
 		"side":            estimate.Side,
 		"operation":       operation,
-		"position_amount": targetTrade.StakeAmount,
+		"position_amount": targetTrade.Amount,
 		"amount":          estimate.Amount,
-		"stake_amount":    estimate.StakeAmount,
+		"percentage":      estimate.Percentage,
+		"exit_amount":     sellAmount,
 		"leverage":        estimate.Leverage,
 		"trade_id":        targetTrade.TradeId,
 		"current_price":   currentPrice,
 		"order_type":      orderType,
 	}).Info("执行卖出操作")
 
-	// 使用 %.8f 保证数量精度
-	return oe.freqtradeClient.ForceSell(
-		fmt.Sprintf("%d", targetTrade.TradeId),
-		orderType,
-		fmt.Sprintf("%.8f", sellAmount),
-	)
+	return client.ForceExit(ctx, targetTrade.TradeId, orderType, sellAmount)
+}
+
+// calculateExitAmount 计算平仓数量，返回0表示全部平仓
+// 优先使用 Amount（币的数量），其次按 Percentage 计算持仓比例
+func (oe *OrderExecutor) calculateExitAmount(estimate *models.PriceEstimate, trade *models.TradePosition) (float64, error) {
+	var amount float64
+	switch {
+	case estimate.Amount > 0:
+		amount = estimate.Amount
+	case estimate.Percentage >= 100:
+		return 0, nil
+	case estimate.Percentage > 0:
+		amount = trade.Amount * estimate.Percentage / 100.0
+	case estimate.StakeAmount > 0:
+		// 兼容旧数据：StakeAmount 曾被直接当作平仓数量透传
+		amount = estimate.StakeAmount
+	default:
+		return 0, fmt.Errorf("止盈操作必须指定 amount 或 percentage")
+	}
+
+	// 超过持仓数量时全部平仓
+	if amount >= trade.Amount {
+		return 0, nil
+	}
+
+	amount = floorToStepSize(estimate, amount)
+	if amount <= 0 {
+		return 0, fmt.Errorf("平仓数量小于最小步长")
+	}
+
+	return amount, nil
+}
+
+// floorToStepSize 按币种数量步长向下取整，获取不到步长时原样返回
+func floorToStepSize(estimate *models.PriceEstimate, amount float64) float64 {
+	return Formatter(estimate.Exchange).RoundAmount(estimate.Symbol, amount)
+}
+
+// applyExecutionResult 将执行过程中确定的状态、取整后的数量和拆单进度写入最新读取的预估
+// 后台拆单可能已保存更新的进度，拆单数只增不减
+func applyExecutionResult(current, executed *models.PriceEstimate) {
+	current.Status = executed.Status
+	current.Amount = executed.Amount
+	if executed.ExecutedSlices > current.ExecutedSlices {
+		current.ExecutedSlices = executed.ExecutedSlices
+	}
 }
 
 // updateEstimateStatus 更新预估状态
@@ -299,12 +441,14 @@ func (oe *OrderExecutor) updateEstimateStatus(estimate *models.PriceEstimate, st
 	}).Debug("更新预估状态")
 
 	estimate.Status = status
-	estimate.UpdatedAt = time.Now()
-
-	err := redis.GlobalRedisClient.SetPriceEstimate(estimate)
+	updated, err := updateEstimate(estimate, func(current *models.PriceEstimate) error {
+		applyExecutionResult(current, estimate)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
+	*estimate = *updated
 
 	// 广播价格预估更新
 	go utils.BroadcastSymbolEstimatesUpdate()
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"trading_assistant/models"
+	"trading_assistant/pkg/metrics"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 下单数量检查结果
+const (
+	sizingResultAdjusted = "adjusted" // 数量按步长取整
+	sizingResultRejected = "rejected" // 不满足币种限制，拒绝下单
+)
+
+// ComputeOrderSizing 按价格计算预估的下单数量、名义价值和保证金，并检查币种的数量和名义价值限制
+// 开仓按 stake_amount × 杠杆 计算，加仓和平仓优先使用 amount，其次按当前持仓的比例计算
+func ComputeOrderSizing(estimate *models.PriceEstimate, price float64) *models.OrderSizing {
+	sizing := &models.OrderSizing{
+		Price:      price,
+		Leverage:   estimate.Leverage,
+		Violations: []string{},
+	}
+	if price <= 0 {
+		sizing.Note = "价格无效，无法计算下单数量"
+		return sizing
+	}
+
+	position, _ := redis.GlobalRedisClient.GetPosition(estimate.Symbol, estimate.Side)
+	if sizing.Leverage <= 0 && position != nil {
+		sizing.Leverage = position.Leverage
+	}
+	if sizing.Leverage <= 0 {
+		sizing.Leverage = 1
+	}
+
+	switch {
+	case estimate.Amount > 0:
+		sizing.RawQuantity = estimate.Amount
+	case estimate.ActionType == models.ActionTypeOpen:
+		if estimate.StakeAmount <= 0 {
+			sizing.Note = "未指定 stake_amount，由Freqtrade按默认金额下单"
+			return sizing
+		}
+		sizing.RawQuantity = estimate.StakeAmount * float64(sizing.Leverage) / price
+	case position == nil:
+		sizing.Note = "当前没有持仓，无法按比例计算数量"
+		return sizing
+	case estimate.Percentage >= 100:
+		sizing.RawQuantity = position.Size
+	default:
+		sizing.RawQuantity = position.Size * estimate.Percentage / 100.0
+	}
+
+	coin, err := ExchangeStore(estimate.Exchange).GetCoin(estimate.Symbol)
+	if err != nil {
+		sizing.Quantity = sizing.RawQuantity
+		sizing.Note = "获取币种信息失败，未按步长取整"
+	} else {
+		// 平仓为只减仓订单，交易所不限制最小名义价值
+		sizing.Quantity = RoundQuantity(coin, sizing.RawQuantity)
+		sizing.Violations = CheckCoinLimits(coin, sizing.Quantity, price, isIncreaseAction(estimate.ActionType))
+	}
+
+	sizing.Notional = sizing.Quantity * price
+	sizing.Margin = sizing.Notional / float64(sizing.Leverage)
+	return sizing
+}
+
+// applyOrderSizing 触发时按币种限制检查下单数量：amount 按步长取整后写回预估，不满足限制时返回错误拒绝下单
+func applyOrderSizing(estimate *models.PriceEstimate, price float64) error {
+	sizing := ComputeOrderSizing(estimate, price)
+	if len(sizing.Violations) > 0 {
+		metrics.OrderSizingChecks.WithLabelValues(estimate.ActionType, sizingResultRejected).Inc()
+		return fmt.Errorf("下单数量不满足 %s 的交易限制: %s", estimate.Symbol, strings.Join(sizing.Violations, "; "))
+	}
+
+	if estimate.Amount > 0 && sizing.Quantity != estimate.Amount {
+		metrics.OrderSizingChecks.WithLabelValues(estimate.ActionType, sizingResultAdjusted).Inc()
+		logrus.WithFields(logrus.Fields{
+			"estimate_id": estimate.ID,
+			"symbol":      estimate.Symbol,
+			"amount":      estimate.Amount,
+			"adjusted":    sizing.Quantity,
+		}).Warn("下单数量已按步长取整")
+		estimate.Amount = sizing.Quantity
+	}
+	return nil
+}
+
+// isIncreaseAction 是否为增加持仓的操作
+func isIncreaseAction(actionType string) bool {
+	return actionType == models.ActionTypeOpen || actionType == models.ActionTypeAddition
+}
+
+// RoundQuantity 按币种数量步长向下取整，步长无效时原样返回
+func RoundQuantity(coin *models.Coin, quantity float64) float64 {
+	step := parseLimit(coin.StepSize)
+	if step <= 0 {
+		return quantity
+	}
+	rounded := math.Floor(quantity/step+1e-9) * step
+	if precision := coin.GetQuantityPrecisionFromStepSize(); precision > 0 {
+		// 去除浮点误差，如 0.30000000000000004
+		rounded, _ = strconv.ParseFloat(strconv.FormatFloat(rounded, 'f', precision, 64), 64)
+	}
+	return rounded
+}
+
+// CheckCoinLimits 检查数量和名义价值是否满足币种限制，price 为0或 checkNotional 为false时不检查名义价值
+func CheckCoinLimits(coin *models.Coin, quantity, price float64, checkNotional bool) []string {
+	violations := []string{}
+	if quantity <= 0 {
+		return append(violations, fmt.Sprintf("按步长 %s 取整后数量为0", coin.StepSize))
+	}
+	if minQty := parseLimit(coin.MinQty); minQty > 0 && quantity < minQty {
+		violations = append(violations, fmt.Sprintf("交易数量 %g 小于最小数量 %g", quantity, minQty))
+	}
+	if maxQty := parseLimit(coin.MaxQty); maxQty > 0 && quantity > maxQty {
+		violations = append(violations, fmt.Sprintf("交易数量 %g 大于最大数量 %g", quantity, maxQty))
+	}
+	if minNotional := parseLimit(coin.MinNotional); checkNotional && price > 0 && minNotional > 0 && quantity*price < minNotional {
+		violations = append(violations, fmt.Sprintf("名义价值 %.4f 小于最小名义价值 %g", quantity*price, minNotional))
+	}
+	return violations
+}
+
+// parseLimit 解析币种限制，空值或格式错误时返回0
+func parseLimit(value string) float64 {
+	limit, _ := strconv.ParseFloat(value, 64)
+	return limit
+}
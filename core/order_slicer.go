@@ -0,0 +1,267 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// slicePlacer 下单第 index 笔拆单
+type slicePlacer func(index int, price float64, orderType string) error
+
+// executeSliced 按拆单策略执行：第一笔同步下单，其余按间隔在后台执行
+func (oe *OrderExecutor) executeSliced(ctx context.Context, client *freqtrade.Controller, estimate *models.PriceEstimate, currentPrice float64) error {
+	strategy := *estimate.OrderStrategy
+	place, err := oe.buildSlicePlacer(ctx, client, estimate, strategy.Slices)
+	if err != nil {
+		return err
+	}
+
+	orderType := sliceOrderType(estimate)
+	if err := place(0, currentPrice, orderType); err != nil {
+		return fmt.Errorf("第 1/%d 笔下单失败: %w", strategy.Slices, err)
+	}
+	estimate.ExecutedSlices = 1
+
+	logrus.Infof("拆单执行 %s %s %s: 第 1/%d 笔已下单，策略 %s，间隔 %ds",
+		estimate.Symbol, estimate.Side, estimate.ActionType, strategy.Slices, strategy.Type, strategy.IntervalSeconds)
+
+	target := *estimate
+	go oe.runRemainingSlices(oe.sliceContext(), &target, strategy, currentPrice, orderType, place)
+	return nil
+}
+
+// sliceContext 当前后台拆单的停止信号
+func (oe *OrderExecutor) sliceContext() context.Context {
+	oe.sliceMu.Lock()
+	defer oe.sliceMu.Unlock()
+	return oe.sliceCtx
+}
+
+// stopSlices 取消所有后台拆单，之后启动的拆单使用新的停止信号
+func (oe *OrderExecutor) stopSlices() {
+	oe.sliceMu.Lock()
+	defer oe.sliceMu.Unlock()
+	oe.cancelSlices()
+	oe.sliceCtx, oe.cancelSlices = context.WithCancel(context.Background())
+}
+
+// buildSlicePlacer 根据操作类型计算每笔数量并生成下单函数
+func (oe *OrderExecutor) buildSlicePlacer(ctx context.Context, client *freqtrade.Controller, estimate *models.PriceEstimate, slices int) (slicePlacer, error) {
+	pair := oe.convertSymbol(estimate.Symbol)
+	side := "long"
+	if estimate.Side == types.PositionSideShort {
+		side = "short"
+	}
+	entryTag := estimate.Tag
+	if entryTag == "" {
+		entryTag = fmt.Sprintf("%s_%s", estimate.ActionType, estimate.Side)
+	}
+
+	switch estimate.ActionType {
+	case models.ActionTypeOpen:
+		if estimate.StakeAmount <= 0 {
+			return nil, fmt.Errorf("拆单开仓必须指定 stake_amount")
+		}
+		if !client.CheckForceBuy(ctx, pair) {
+			return nil, fmt.Errorf("无法开仓: 达到最大持仓数量或交易对已存在持仓")
+		}
+
+		sliceStake := estimate.StakeAmount / float64(slices)
+		return func(index int, price float64, orderType string) error {
+			// 第一笔开仓，其余在该仓位上加仓
+			if index > 0 {
+				return client.ForceAdjustBuy(ctx, pair, price, side, sliceStake, entryTag)
+			}
+			payload := models.ForceBuyPayload{
+				Pair:        pair,
+				OrderType:   orderType,
+				EntryTag:    entryTag,
+				Side:        side,
+				Leverage:    estimate.Leverage,
+				StakeAmount: &sliceStake,
+			}
+			if orderType == "limit" {
+				payload.Price = price
+			}
+			return client.ForceBuy(ctx, payload)
+		}, nil
+
+	case models.ActionTypeAddition:
+		positions, err := client.GetPositions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("获取仓位信息失败: %v", err)
+		}
+		trade := matchTrade(positions, pair, estimate.Side)
+		if trade == nil {
+			return nil, fmt.Errorf("未找到对应的仓位用于加仓 %s %s", estimate.Symbol, estimate.Side)
+		}
+		if len(trade.Orders) == 0 || trade.Orders[0].Cost == nil || *trade.Orders[0].Cost <= 0 || trade.Leverage == nil {
+			return nil, fmt.Errorf("获取不到原始投入金额")
+		}
+
+		stakeCost := *trade.Orders[0].Cost * (estimate.Percentage / 100.0) / *trade.Leverage
+		sliceStake := stakeCost / float64(slices)
+		return func(index int, price float64, orderType string) error {
+			return client.ForceAdjustBuy(ctx, pair, price, side, sliceStake, entryTag)
+		}, nil
+
+	case models.ActionTypeTakeProfit, models.ActionTypeStopLoss:
+		trades, err := client.GetTradeStatus(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("获取交易状态失败: %v", err)
+		}
+		trade := matchTrade(trades, pair, estimate.Side)
+		if trade == nil {
+			return nil, fmt.Errorf("未找到对应的仓位用于%s %s %s", estimate.ActionType, estimate.Symbol, estimate.Side)
+		}
+
+		amount, err := oe.calculateExitAmount(estimate, trade)
+		if err != nil {
+			return nil, err
+		}
+		fullExit := amount == 0
+		if fullExit {
+			amount = trade.Amount
+		}
+
+		sliceAmount := floorToStepSize(estimate, amount/float64(slices))
+		if sliceAmount <= 0 {
+			return nil, fmt.Errorf("拆单后每笔平仓数量小于最小步长")
+		}
+		tradeID := trade.TradeId
+		return func(index int, price float64, orderType string) error {
+			if index < slices-1 {
+				return client.ForceExit(ctx, tradeID, orderType, sliceAmount)
+			}
+			// 最后一笔平掉剩余数量
+			if fullExit {
+				return client.ForceExit(ctx, tradeID, orderType, 0)
+			}
+			return client.ForceExit(ctx, tradeID, orderType, floorToStepSize(estimate, amount-sliceAmount*float64(slices-1)))
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("不支持的操作类型: %s", estimate.ActionType)
+	}
+}
+
+// runRemainingSlices 按间隔执行剩余拆单，冰山单每笔按最新盘口追价，偏离触发价过多时停止
+// 每笔下单前检查停止信号、紧急停止和主节点身份，任一不满足时取消剩余拆单
+func (oe *OrderExecutor) runRemainingSlices(ctx context.Context, estimate *models.PriceEstimate, strategy models.OrderStrategy, triggerPrice float64, orderType string, place slicePlacer) {
+	interval := time.Duration(strategy.IntervalSeconds) * time.Second
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for index := 1; index < strategy.Slices; index++ {
+		if index > 1 {
+			timer.Reset(interval)
+		}
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+
+		if reason := sliceStopReason(ctx); reason != "" {
+			message := fmt.Sprintf("%s，剩余 %d 笔已取消", reason, strategy.Slices-index)
+			logrus.Warnf("拆单停止 %s: %s", estimate.Symbol, message)
+			saveSliceProgress(estimate.ID, index, message)
+			return
+		}
+
+		price := sliceExecutionPrice(estimate)
+		if price <= 0 {
+			price = triggerPrice
+		}
+
+		if strategy.MaxChasePct > 0 && triggerPrice > 0 {
+			if drift := math.Abs(price-triggerPrice) / triggerPrice * 100; drift > strategy.MaxChasePct {
+				message := fmt.Sprintf("最新价 %.6f 偏离触发价 %.6f 达 %.2f%%，超过追价上限 %.2f%%，剩余 %d 笔已取消",
+					price, triggerPrice, drift, strategy.MaxChasePct, strategy.Slices-index)
+				logrus.Warnf("拆单停止 %s: %s", estimate.Symbol, message)
+				saveSliceProgress(estimate.ID, index, message)
+				return
+			}
+		}
+
+		if err := place(index, price, orderType); err != nil {
+			message := fmt.Sprintf("第 %d/%d 笔下单失败: %v", index+1, strategy.Slices, err)
+			logrus.Errorf("拆单执行 %s: %s", estimate.Symbol, message)
+			saveSliceProgress(estimate.ID, index, message)
+			return
+		}
+
+		logrus.Infof("拆单执行 %s %s %s: 第 %d/%d 笔已下单，价格 %.6f",
+			estimate.Symbol, estimate.Side, estimate.ActionType, index+1, strategy.Slices, price)
+		saveSliceProgress(estimate.ID, index+1, "")
+	}
+}
+
+// sliceStopReason 后台拆单需要停止的原因，可以继续下单时返回空字符串
+func sliceStopReason(ctx context.Context) string {
+	if ctx.Err() != nil {
+		return "价格监控已停止"
+	}
+	if IsKillSwitchActive() {
+		return "紧急停止已开启"
+	}
+	if GlobalLeaderElector != nil && !GlobalLeaderElector.IsLeader() {
+		return "本实例已不是主节点"
+	}
+	return ""
+}
+
+// sliceOrderType 拆单使用的订单类型，冰山单始终使用限价单
+func sliceOrderType(estimate *models.PriceEstimate) string {
+	if estimate.OrderStrategy.Type == models.OrderStrategyIceberg || estimate.OrderType == types.OrderTypeLimit {
+		return "limit"
+	}
+	return "market"
+}
+
+// sliceExecutionPrice 获取拆单下单价格：买入使用卖一价，卖出使用买一价，无效时使用标记价格
+func sliceExecutionPrice(estimate *models.PriceEstimate) float64 {
+	data, err := ExchangeStore(estimate.Exchange).GetMarkPrice(estimate.Symbol)
+	if err != nil || data == nil {
+		return 0
+	}
+
+	isBuy := !models.IsExitAction(estimate.ActionType) == (estimate.Side == types.PositionSideLong)
+	price := data.BidPrice
+	if isBuy {
+		price = data.AskPrice
+	}
+	if price <= 0 {
+		price = data.MarkPrice
+	}
+	return price
+}
+
+// saveSliceProgress 保存拆单进度
+func saveSliceProgress(estimateID string, executed int, errorMessage string) {
+	estimate, err := redis.GlobalRedisClient.GetEstimateById(estimateID)
+	if err != nil || estimate == nil {
+		logrus.Errorf("获取预估 %s 失败，无法保存拆单进度: %v", estimateID, err)
+		return
+	}
+
+	_, err = updateEstimate(estimate, func(current *models.PriceEstimate) error {
+		current.ExecutedSlices = executed
+		current.ErrorMessage = errorMessage
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("保存拆单进度失败: %v", err)
+		return
+	}
+
+	go utils.BroadcastSymbolEstimatesUpdate()
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchange_factory"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/metrics"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// orderBookFetchConcurrency 单轮并发获取订单簿的数量
+const orderBookFetchConcurrency = 5
+
+// OrderBookManager 订单簿管理器
+// 定时拉取选中币种的订单簿快照，截取前N档写入Redis供HTTP接口和前端使用
+type OrderBookManager struct {
+	exchangeClient exchange_factory.ExchangeInterface
+	fetcher        exchange_factory.OrderBookFetcher
+	store          *redis.Client
+	depth          int
+	updateInterval time.Duration
+
+	mu      sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running bool
+}
+
+// NewOrderBookManager 创建订单簿管理器，交易所不支持订单簿时返回nil
+func NewOrderBookManager(exchangeClient exchange_factory.ExchangeInterface) *OrderBookManager {
+	fetcher, err := exchange_factory.AsOrderBookFetcher(exchangeClient)
+	if err != nil {
+		logrus.Warnf("%v，跳过订单簿缓存", err)
+		return nil
+	}
+
+	depth := config.GlobalConfig.OrderBookDepth
+	if depth <= 0 {
+		depth = 20
+	}
+	interval := config.GlobalConfig.OrderBookUpdateInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	return &OrderBookManager{
+		exchangeClient: exchangeClient,
+		fetcher:        fetcher,
+		store:          ExchangeStore(exchangeClient.GetID()),
+		depth:          depth,
+		updateInterval: interval,
+	}
+}
+
+// Start 启动订单簿定时同步
+func (om *OrderBookManager) Start() {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+
+	if om.running {
+		return
+	}
+	om.ctx, om.cancel = context.WithCancel(context.Background())
+	om.running = true
+
+	go om.run(om.ctx)
+	logrus.Infof("订单簿管理器已启动，深度: %d，更新间隔: %v", om.depth, om.updateInterval)
+}
+
+// Stop 停止订单簿同步
+func (om *OrderBookManager) Stop() {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+
+	if !om.running {
+		return
+	}
+	om.cancel()
+	om.running = false
+	logrus.Info("订单簿管理器已停止")
+}
+
+// run 主运行循环
+func (om *OrderBookManager) run(ctx context.Context) {
+	ticker := time.NewTicker(om.updateInterval)
+	defer ticker.Stop()
+
+	om.syncOnce(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			om.syncOnce(ctx)
+		}
+	}
+}
+
+// syncOnce 同步一轮选中币种的订单簿
+func (om *OrderBookManager) syncOnce(ctx context.Context) {
+	symbols, err := redis.GlobalRedisClient.GetSelectedCoinMarketIDs()
+	if err != nil {
+		logrus.Errorf("获取选中币种列表失败: %v", err)
+		return
+	}
+
+	sem := make(chan struct{}, orderBookFetchConcurrency)
+	var wg sync.WaitGroup
+	for _, symbol := range symbols {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(symbol string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			om.syncSymbol(ctx, symbol)
+		}(symbol)
+	}
+	wg.Wait()
+}
+
+// syncSymbol 拉取单个币种的订单簿快照并写入缓存
+func (om *OrderBookManager) syncSymbol(ctx context.Context, symbol string) {
+	fetchCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	exchangeID := om.exchangeClient.GetID()
+	book, err := om.fetcher.FetchOrderBook(fetchCtx, symbol, om.depth)
+	metrics.ExchangeRequests.WithLabelValues(exchangeID, "order_book", metrics.ResultLabel(err)).Inc()
+	if err != nil {
+		logrus.Debugf("获取 %s 订单簿失败: %v", symbol, err)
+		return
+	}
+
+	truncateOrderBook(book, om.depth)
+
+	// 缓存过期时间为3个更新周期，停止更新后自动失效
+	if err := om.store.SetOrderBook(book, 3*om.updateInterval); err != nil {
+		logrus.Errorf("保存 %s 订单簿失败: %v", symbol, err)
+	}
+}
+
+// truncateOrderBook 截取订单簿前depth档
+func truncateOrderBook(book *types.OrderBook, depth int) {
+	if len(book.Bids.Price) > depth {
+		book.Bids.Price = book.Bids.Price[:depth]
+		book.Bids.Size = book.Bids.Size[:depth]
+	}
+	if len(book.Asks.Price) > depth {
+		book.Asks.Price = book.Asks.Price[:depth]
+		book.Asks.Size = book.Asks.Size[:depth]
+	}
+}
@@ -0,0 +1,287 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/websocket"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// AlertTypePaper 模拟成交推送类型
+const AlertTypePaper = "paper"
+
+// paperAmountEpsilon 模拟持仓数量比较误差
+const paperAmountEpsilon = 1e-12
+
+// PaperAccountSummary 模拟账户汇总
+type PaperAccountSummary struct {
+	*models.PaperAccount
+	UsedMargin    float64 `json:"used_margin"`    // 持仓占用保证金
+	UnrealizedPnl float64 `json:"unrealized_pnl"` // 未实现盈亏
+	Equity        float64 `json:"equity"`         // 权益 = 余额 + 保证金 + 未实现盈亏
+	PositionCount int     `json:"position_count"`
+}
+
+// PaperEngine 模拟成交引擎，DRY_RUN 时按实时价格模拟成交，持仓和盈亏保存在Redis
+type PaperEngine struct {
+	initialBalance float64
+	feeRate        float64
+	defaultStake   float64
+
+	mu sync.Mutex
+}
+
+var (
+	GlobalPaperEngine *PaperEngine
+	paperEngineOnce   sync.Once
+)
+
+// GetPaperEngine 获取全局模拟成交引擎
+func GetPaperEngine() *PaperEngine {
+	paperEngineOnce.Do(func() {
+		cfg := config.GlobalConfig
+		GlobalPaperEngine = &PaperEngine{
+			initialBalance: cfg.PaperInitialBalance,
+			feeRate:        cfg.PaperFeeRate,
+			defaultStake:   cfg.PaperDefaultStake,
+		}
+	})
+	return GlobalPaperEngine
+}
+
+// Execute 按成交价模拟执行价格预估
+func (pe *PaperEngine) Execute(estimate *models.PriceEstimate, price float64) (*models.PaperFill, error) {
+	if price <= 0 {
+		return nil, fmt.Errorf("模拟成交价格无效: %f", price)
+	}
+
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	account, err := pe.loadAccount()
+	if err != nil {
+		return nil, err
+	}
+	position, err := redis.GlobalRedisClient.GetPaperPosition(estimate.Symbol, estimate.Side)
+	if err != nil {
+		return nil, fmt.Errorf("获取模拟持仓失败: %v", err)
+	}
+
+	now := time.Now()
+	fill := &models.PaperFill{
+		ID:         uuid.New().String(),
+		EstimateID: estimate.ID,
+		Symbol:     estimate.Symbol,
+		Side:       estimate.Side,
+		ActionType: estimate.ActionType,
+		Price:      price,
+		Timestamp:  now.UnixMilli(),
+	}
+
+	switch estimate.ActionType {
+	case models.ActionTypeOpen:
+		if position != nil {
+			return nil, fmt.Errorf("模拟账户已存在 %s %s 持仓", estimate.Symbol, estimate.Side)
+		}
+		stake := estimate.StakeAmount
+		if stake <= 0 {
+			stake = pe.defaultStake
+		}
+		position = &models.PaperPosition{
+			Symbol:        estimate.Symbol,
+			Exchange:      estimate.Exchange,
+			Side:          estimate.Side,
+			Leverage:      max(estimate.Leverage, 1),
+			InitialMargin: stake,
+			OpenedAt:      now,
+		}
+		if err := pe.fillEntry(account, position, fill, stake, price); err != nil {
+			return nil, err
+		}
+
+	case models.ActionTypeAddition:
+		if position == nil {
+			return nil, fmt.Errorf("未找到对应的模拟持仓用于加仓 %s %s", estimate.Symbol, estimate.Side)
+		}
+		stake := position.InitialMargin * estimate.Percentage / 100.0
+		if stake <= 0 {
+			return nil, fmt.Errorf("加仓金额无效")
+		}
+		if err := pe.fillEntry(account, position, fill, stake, price); err != nil {
+			return nil, err
+		}
+
+	case models.ActionTypeTakeProfit, models.ActionTypeStopLoss:
+		if position == nil {
+			return nil, fmt.Errorf("未找到对应的模拟持仓用于%s %s %s", getActionText(estimate.ActionType), estimate.Symbol, estimate.Side)
+		}
+		amount, err := paperExitAmount(estimate, position)
+		if err != nil {
+			return nil, err
+		}
+		pe.fillExit(account, position, fill, amount, price)
+
+	default:
+		return nil, fmt.Errorf("不支持的操作类型: %s", estimate.ActionType)
+	}
+
+	position.UpdatedAt = now
+	account.Fees += fill.Fee
+	account.RealizedPnl += fill.RealizedPnl
+	account.TradeCount++
+	account.UpdatedAt = now
+
+	if err := redis.GlobalRedisClient.SetPaperPosition(position); err != nil {
+		return nil, fmt.Errorf("保存模拟持仓失败: %v", err)
+	}
+	if err := redis.GlobalRedisClient.SetPaperAccount(account); err != nil {
+		return nil, fmt.Errorf("保存模拟账户失败: %v", err)
+	}
+	if err := redis.GlobalRedisClient.AddPaperFill(fill); err != nil {
+		logrus.Errorf("%v", err)
+	}
+	recordPaperFillPnL(fill)
+
+	if wsManager := websocket.GetGlobalWebSocketManager(); wsManager != nil {
+		wsManager.BroadcastAlert(AlertTypePaper, fill)
+	}
+	return fill, nil
+}
+
+// fillEntry 模拟开仓/加仓成交（调用方需持有锁）
+func (pe *PaperEngine) fillEntry(account *models.PaperAccount, position *models.PaperPosition, fill *models.PaperFill, stake, price float64) error {
+	notional := stake * float64(position.Leverage)
+	amount := notional / price
+	fee := notional * pe.feeRate
+	if account.Balance < stake+fee {
+		return fmt.Errorf("模拟账户余额不足: 需要 %.2f, 可用 %.2f", stake+fee, account.Balance)
+	}
+
+	position.EntryPrice = (position.EntryPrice*position.Amount + price*amount) / (position.Amount + amount)
+	position.Amount += amount
+	position.Margin += stake
+	account.Balance -= stake + fee
+
+	fill.Amount = amount
+	fill.Notional = notional
+	fill.Fee = fee
+	return nil
+}
+
+// fillExit 模拟平仓成交（调用方需持有锁）
+func (pe *PaperEngine) fillExit(account *models.PaperAccount, position *models.PaperPosition, fill *models.PaperFill, amount, price float64) {
+	released := position.Margin * amount / position.Amount
+	pnl := paperPnl(position.Side, position.EntryPrice, price, amount)
+	notional := amount * price
+	fee := notional * pe.feeRate
+
+	position.Amount -= amount
+	position.Margin -= released
+	position.RealizedPnl += pnl
+	if position.Amount <= paperAmountEpsilon {
+		position.Amount = 0
+	}
+	account.Balance += released + pnl - fee
+
+	fill.Amount = amount
+	fill.Notional = notional
+	fill.Fee = fee
+	fill.RealizedPnl = pnl
+}
+
+// paperExitAmount 计算模拟平仓数量，规则与实盘一致：Amount 优先，其次 Percentage
+func paperExitAmount(estimate *models.PriceEstimate, position *models.PaperPosition) (float64, error) {
+	var amount float64
+	switch {
+	case estimate.Amount > 0:
+		amount = estimate.Amount
+	case estimate.Percentage > 0:
+		amount = position.Amount * estimate.Percentage / 100.0
+	case estimate.StakeAmount > 0:
+		amount = estimate.StakeAmount
+	default:
+		return 0, fmt.Errorf("止盈操作必须指定 amount 或 percentage")
+	}
+	return min(amount, position.Amount), nil
+}
+
+// paperPnl 计算盈亏
+func paperPnl(side string, entryPrice, price, amount float64) float64 {
+	if side == types.PositionSideShort {
+		return (entryPrice - price) * amount
+	}
+	return (price - entryPrice) * amount
+}
+
+// loadAccount 获取模拟账户，不存在时按初始资金创建（调用方需持有锁）
+func (pe *PaperEngine) loadAccount() (*models.PaperAccount, error) {
+	account, err := redis.GlobalRedisClient.GetPaperAccount()
+	if err != nil {
+		return nil, fmt.Errorf("获取模拟账户失败: %v", err)
+	}
+	if account == nil {
+		now := time.Now()
+		account = &models.PaperAccount{
+			InitialBalance: pe.initialBalance,
+			Balance:        pe.initialBalance,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+	}
+	return account, nil
+}
+
+// GetPositions 获取模拟持仓，并按实时标记价格计算未实现盈亏
+func (pe *PaperEngine) GetPositions() ([]*models.PaperPosition, error) {
+	positions, err := redis.GlobalRedisClient.GetAllPaperPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, position := range positions {
+		markPrice, err := ExchangeStore(position.Exchange).GetMarkPrice(position.Symbol)
+		if err != nil || markPrice == nil || markPrice.MarkPrice <= 0 {
+			continue
+		}
+		position.MarkPrice = markPrice.MarkPrice
+		position.UnrealizedPnl = paperPnl(position.Side, position.EntryPrice, markPrice.MarkPrice, position.Amount)
+	}
+	return positions, nil
+}
+
+// GetSummary 获取模拟账户汇总
+func (pe *PaperEngine) GetSummary() (*PaperAccountSummary, error) {
+	pe.mu.Lock()
+	account, err := pe.loadAccount()
+	pe.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := pe.GetPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &PaperAccountSummary{PaperAccount: account, PositionCount: len(positions)}
+	for _, position := range positions {
+		summary.UsedMargin += position.Margin
+		summary.UnrealizedPnl += position.UnrealizedPnl
+	}
+	summary.Equity = account.Balance + summary.UsedMargin + summary.UnrealizedPnl
+	return summary, nil
+}
+
+// Reset 重置模拟账户
+func (pe *PaperEngine) Reset() error {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+	return redis.GlobalRedisClient.ResetPaperTrading()
+}
@@ -0,0 +1,352 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 盈亏聚合维度
+const (
+	PnLGroupByDay    = "day"
+	PnLGroupByWeek   = "week"
+	PnLGroupBySymbol = "symbol"
+)
+
+// pnlSyncTradeLimit 每次从Freqtrade拉取的最近交易数量
+const pnlSyncTradeLimit = 200
+
+// pnlReportCheckInterval 检查是否到达日报发送时间的间隔
+const pnlReportCheckInterval = time.Minute
+
+// PnLLedger 盈亏账本，按来源、币种和日期累计已实现盈亏，并定时发送盈亏日报/周报
+// 当前没有交易所用户数据流，实盘盈亏以Freqtrade已平仓交易为准，模拟成交在成交时实时写入
+type PnLLedger struct {
+	freqtradeClient *freqtrade.Controller
+	syncInterval    time.Duration
+	dailyReport     bool
+	weeklyReport    bool
+	reportTime      time.Duration // 日报发送时间（距当天零点）
+	stopChan        chan struct{}
+}
+
+var GlobalPnLLedger *PnLLedger
+
+// InitPnLLedger 初始化盈亏账本
+func InitPnLLedger(freqtradeClient *freqtrade.Controller) {
+	cfg := config.GlobalConfig
+	reportTime, err := time.Parse("15:04", cfg.PnLDailyReportTime)
+	if err != nil {
+		logrus.Warnf("无效的日报发送时间 %s，使用 00:05", cfg.PnLDailyReportTime)
+		reportTime, _ = time.Parse("15:04", "00:05")
+	}
+
+	GlobalPnLLedger = &PnLLedger{
+		freqtradeClient: freqtradeClient,
+		syncInterval:    cfg.PnLSyncInterval,
+		dailyReport:     cfg.PnLDailyReportEnabled,
+		weeklyReport:    cfg.PnLWeeklyReportEnabled,
+		reportTime:      time.Duration(reportTime.Hour())*time.Hour + time.Duration(reportTime.Minute())*time.Minute,
+	}
+}
+
+// Start 启动Freqtrade交易同步和日报发送
+func (l *PnLLedger) Start() {
+	syncEnabled := l.syncInterval > 0 && l.freqtradeClient != nil
+	if !syncEnabled && !l.dailyReport {
+		logrus.Info("盈亏统计未启用")
+		return
+	}
+	if l.stopChan != nil {
+		return
+	}
+
+	l.stopChan = make(chan struct{})
+	go l.loop(l.stopChan, syncEnabled)
+	logrus.Infof("盈亏统计已启动，同步间隔: %v, 日报: %v", l.syncInterval, l.dailyReport)
+}
+
+// Stop 停止盈亏统计
+func (l *PnLLedger) Stop() {
+	if l.stopChan == nil {
+		return
+	}
+	close(l.stopChan)
+	l.stopChan = nil
+}
+
+// loop 定时同步已平仓交易并检查日报发送时间
+func (l *PnLLedger) loop(stopChan chan struct{}, syncEnabled bool) {
+	var syncTick <-chan time.Time
+	if syncEnabled {
+		syncTicker := time.NewTicker(l.syncInterval)
+		defer syncTicker.Stop()
+		syncTick = syncTicker.C
+		l.SyncFreqtradeTrades(context.Background())
+	}
+
+	var reportTick <-chan time.Time
+	if l.dailyReport {
+		reportTicker := time.NewTicker(pnlReportCheckInterval)
+		defer reportTicker.Stop()
+		reportTick = reportTicker.C
+		l.checkReport(time.Now())
+	}
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-syncTick:
+			l.SyncFreqtradeTrades(context.Background())
+		case now := <-reportTick:
+			l.checkReport(now)
+		}
+	}
+}
+
+// SyncFreqtradeTrades 将Freqtrade最近的已平仓交易计入账本，已计入的交易会被跳过
+func (l *PnLLedger) SyncFreqtradeTrades(ctx context.Context) {
+	trades, err := l.freqtradeClient.GetRecentTrades(ctx, pnlSyncTradeLimit)
+	if err != nil {
+		logrus.Warnf("获取Freqtrade交易记录失败，跳过盈亏同步: %v", err)
+		return
+	}
+
+	recorded := 0
+	for i := range trades {
+		record := freqtradeTradeRecord(&trades[i])
+		if record == nil {
+			continue
+		}
+		ok, err := redis.GlobalRedisClient.RecordPnL(record)
+		if err != nil {
+			logrus.Errorf("记录交易 %s 盈亏失败: %v", record.ID, err)
+			continue
+		}
+		if ok {
+			recorded++
+		}
+	}
+	if recorded > 0 {
+		logrus.Infof("已将 %d 笔Freqtrade平仓交易计入盈亏账本", recorded)
+	}
+}
+
+// freqtradeTradeRecord 将已平仓交易转换为账本记录，未平仓时返回nil
+// close_profit_abs 已扣除开平仓手续费
+func freqtradeTradeRecord(trade *models.TradePosition) *models.PnLRecord {
+	if trade.IsOpen || trade.CloseProfitAbs == nil || trade.CloseTimestamp == nil {
+		return nil
+	}
+
+	record := &models.PnLRecord{
+		Source:      models.PnLSourceFreqtrade,
+		ID:          strconv.Itoa(trade.TradeId),
+		Symbol:      utils.ConvertSymbolToMarketID(trade.Pair),
+		Date:        PnLDate(time.UnixMilli(*trade.CloseTimestamp)),
+		RealizedPnl: *trade.CloseProfitAbs,
+		Closed:      true,
+	}
+	if trade.FeeOpenCost != nil {
+		record.Fees += *trade.FeeOpenCost
+	}
+	if trade.FeeCloseCost != nil {
+		record.Fees += *trade.FeeCloseCost
+	}
+	if trade.FundingFees != nil {
+		record.FundingFees = *trade.FundingFees
+	}
+	return record
+}
+
+// recordPaperFillPnL 将模拟成交计入账本，开仓/加仓只计手续费，平仓计入笔数和胜率
+func recordPaperFillPnL(fill *models.PaperFill) {
+	closed := fill.ActionType == models.ActionTypeTakeProfit || fill.ActionType == models.ActionTypeStopLoss
+	record := &models.PnLRecord{
+		Source:      models.PnLSourcePaper,
+		ID:          fill.ID,
+		Symbol:      fill.Symbol,
+		Date:        PnLDate(time.UnixMilli(fill.Timestamp)),
+		RealizedPnl: fill.RealizedPnl - fill.Fee,
+		Fees:        fill.Fee,
+		Closed:      closed,
+	}
+	if _, err := redis.GlobalRedisClient.RecordPnL(record); err != nil {
+		logrus.Errorf("记录模拟成交 %s 盈亏失败: %v", fill.ID, err)
+	}
+}
+
+// ========== 聚合 ==========
+
+// PnLDate 账本日期（服务器时区）
+func PnLDate(t time.Time) string {
+	return t.Local().Format(time.DateOnly)
+}
+
+// PnLDates 返回 from 到 to（含）之间的所有账本日期
+func PnLDates(from, to time.Time) []string {
+	var dates []string
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		dates = append(dates, PnLDate(day))
+	}
+	return dates
+}
+
+// pnlGroupKey 记录在聚合维度下的分组键
+func pnlGroupKey(entry *models.PnLEntry, groupBy string) string {
+	switch groupBy {
+	case PnLGroupBySymbol:
+		return entry.Symbol
+	case PnLGroupByWeek:
+		day, err := time.ParseInLocation(time.DateOnly, entry.Date, time.Local)
+		if err != nil {
+			return entry.Date
+		}
+		year, week := day.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	default:
+		return entry.Date
+	}
+}
+
+// addPnLEntry 将账本记录累加到汇总
+func addPnLEntry(summary *models.PnLSummary, entry *models.PnLEntry) {
+	summary.RealizedPnl += entry.RealizedPnl
+	summary.Fees += entry.Fees
+	summary.FundingFees += entry.FundingFees
+	summary.Trades += entry.Trades
+	summary.Wins += entry.Wins
+	summary.Losses += entry.Losses
+	if summary.Trades > 0 {
+		summary.WinRate = float64(summary.Wins) / float64(summary.Trades)
+	}
+}
+
+// SummarizePnL 汇总全部账本记录
+func SummarizePnL(entries []*models.PnLEntry) *models.PnLSummary {
+	summary := &models.PnLSummary{}
+	for _, entry := range entries {
+		addPnLEntry(summary, entry)
+	}
+	return summary
+}
+
+// AggregatePnL 按日、周或币种聚合账本记录，日/周按时间升序，币种按盈亏从高到低
+func AggregatePnL(entries []*models.PnLEntry, groupBy string) []*models.PnLSummary {
+	groups := make(map[string]*models.PnLSummary)
+	for _, entry := range entries {
+		key := pnlGroupKey(entry, groupBy)
+		summary, exists := groups[key]
+		if !exists {
+			summary = &models.PnLSummary{Key: key}
+			groups[key] = summary
+		}
+		addPnLEntry(summary, entry)
+	}
+
+	result := make([]*models.PnLSummary, 0, len(groups))
+	for _, summary := range groups {
+		result = append(result, summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if groupBy == PnLGroupBySymbol && result[i].RealizedPnl != result[j].RealizedPnl {
+			return result[i].RealizedPnl > result[j].RealizedPnl
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// ========== 日报/周报 ==========
+
+// checkReport 到达发送时间且当天尚未发送时发送日报，每周一追加上周周报
+func (l *PnLLedger) checkReport(now time.Time) {
+	today := PnLDate(now)
+	midnight, err := time.ParseInLocation(time.DateOnly, today, time.Local)
+	if err != nil || now.Before(midnight.Add(l.reportTime)) {
+		return
+	}
+
+	lastReport, err := redis.GlobalRedisClient.GetPnLLastReport()
+	if err != nil {
+		logrus.Warnf("获取日报发送记录失败: %v", err)
+		return
+	}
+	if lastReport == today {
+		return
+	}
+	if err := redis.GlobalRedisClient.SetPnLLastReport(today); err != nil {
+		logrus.Warnf("保存日报发送记录失败: %v", err)
+		return
+	}
+
+	yesterday := midnight.AddDate(0, 0, -1)
+	l.sendReport(i18n.T("notify.pnl_daily.title", PnLDate(yesterday)), yesterday, yesterday)
+	if l.weeklyReport && now.Weekday() == time.Monday {
+		weekStart := midnight.AddDate(0, 0, -7)
+		l.sendReport(i18n.T("notify.pnl_weekly.title", PnLDate(weekStart), PnLDate(yesterday)), weekStart, yesterday)
+	}
+}
+
+// sendReport 统计区间内各来源的已实现盈亏并通过通知渠道发送
+func (l *PnLLedger) sendReport(title string, from, to time.Time) {
+	dates := PnLDates(from, to)
+	var sections []string
+	data := make(map[string]interface{})
+	for _, source := range []string{models.PnLSourceFreqtrade, models.PnLSourcePaper} {
+		entries, err := LoadPnLEntries(source, dates)
+		if err != nil {
+			logrus.Errorf("获取盈亏账本失败: %v", err)
+			return
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		summary := SummarizePnL(entries)
+		sections = append(sections, formatPnLSection(source, summary, AggregatePnL(entries, PnLGroupBySymbol)))
+		data[source] = summary
+	}
+
+	message := "区间内无已实现盈亏"
+	if len(sections) > 0 {
+		message = strings.Join(sections, "\n\n")
+	}
+	notify.Send(notify.EventPnL, title, message, data)
+}
+
+// formatPnLSection 渲染单个来源的盈亏汇总
+func formatPnLSection(source string, summary *models.PnLSummary, bySymbol []*models.PnLSummary) string {
+	name := "Freqtrade"
+	if source == models.PnLSourcePaper {
+		name = "模拟交易"
+	}
+
+	lines := []string{
+		fmt.Sprintf("[%s] 已实现盈亏: %+.2f USDT", name, summary.RealizedPnl),
+		fmt.Sprintf("平仓: %d 笔, 胜率: %.1f%% (%d胜%d负)", summary.Trades, summary.WinRate*100, summary.Wins, summary.Losses),
+		fmt.Sprintf("手续费: %.2f USDT, 资金费: %+.2f USDT", summary.Fees, summary.FundingFees),
+	}
+	if len(bySymbol) == 0 {
+		return strings.Join(lines, "\n")
+	}
+	if best := bySymbol[0]; best.RealizedPnl > 0 {
+		lines = append(lines, fmt.Sprintf("盈利最多: %s %+.2f", best.Key, best.RealizedPnl))
+	}
+	if worst := bySymbol[len(bySymbol)-1]; worst.RealizedPnl < 0 {
+		lines = append(lines, fmt.Sprintf("亏损最多: %s %+.2f", worst.Key, worst.RealizedPnl))
+	}
+	return strings.Join(lines, "\n")
+}
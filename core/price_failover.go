@@ -0,0 +1,191 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+
+	"github.com/sirupsen/logrus"
+)
+
+// priceFailoverHysteresis 主交易所评分恢复到阈值以上该分数后才切回，避免在阈值附近来回切换
+const priceFailoverHysteresis = 10.0
+
+// PriceVenueStatus 价格监控交易所状态
+type PriceVenueStatus struct {
+	Primary   string  `json:"primary"`          // 主交易所
+	Active    string  `json:"active"`           // 当前读取触发价格的交易所
+	Manual    bool    `json:"manual"`           // 是否为手动指定
+	Threshold float64 `json:"threshold"`        // 自动切换的评分阈值，0 表示关闭自动切换
+	Since     int64   `json:"since,omitempty"`  // 切换到当前交易所的时间（毫秒）
+	Reason    string  `json:"reason,omitempty"` // 切换原因
+}
+
+// PriceFailover 主交易所数据质量下降时将价格监控切换到评分更高的其他交易所，评分恢复后切回
+// 只影响使用主交易所的预估读取触发价格，下单仍通过Freqtrade执行
+type PriceFailover struct {
+	mu        sync.Mutex
+	threshold float64
+	manual    string // 手动指定的交易所，优先于自动切换
+	active    string // 自动切换到的交易所，为空时使用主交易所
+	since     time.Time
+	reason    string
+}
+
+var (
+	globalPriceFailover *PriceFailover
+	priceFailoverOnce   sync.Once
+)
+
+// GetPriceFailover 获取全局价格监控交易所切换器
+func GetPriceFailover() *PriceFailover {
+	priceFailoverOnce.Do(func() {
+		globalPriceFailover = &PriceFailover{threshold: config.GlobalConfig.PriceFailoverScore}
+	})
+	return globalPriceFailover
+}
+
+// primaryExchangeID 主交易所ID，与数据质量评分使用的ID一致
+func primaryExchangeID() string {
+	if client, ok := ExchangeClient(""); ok {
+		return client.GetID()
+	}
+	return strings.ToLower(config.GlobalConfig.ExchangeType)
+}
+
+// Venue 预估读取触发价格使用的交易所，指定了其他交易所的预估不受切换影响
+func (f *PriceFailover) Venue(exchange string) string {
+	if ExchangeNamespace(exchange) != "" {
+		return exchange
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.manual != "" {
+		return f.manual
+	}
+	if f.active != "" {
+		return f.active
+	}
+	return exchange
+}
+
+// SetManual 手动指定价格监控交易所，exchange 为空或为主交易所时恢复自动选择
+func (f *PriceFailover) SetManual(exchange string) error {
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	if ExchangeNamespace(exchange) == "" {
+		exchange = ""
+	} else if _, ok := PriceManagerFor(exchange); !ok {
+		return fmt.Errorf("交易所 %s 未运行价格获取，请先在 SECONDARY_EXCHANGES 中配置", exchange)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.manual == exchange {
+		return nil
+	}
+	f.manual = exchange
+	f.since = time.Now()
+	f.reason = "手动指定"
+	if exchange == "" {
+		f.reason = "恢复自动选择"
+	}
+	logrus.Warnf("价格监控交易所已手动切换为 %s", f.venueLocked())
+	return nil
+}
+
+// GetStatus 获取价格监控交易所状态
+func (f *PriceFailover) GetStatus() PriceVenueStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	status := PriceVenueStatus{
+		Primary:   primaryExchangeID(),
+		Active:    f.venueLocked(),
+		Manual:    f.manual != "",
+		Threshold: f.threshold,
+		Reason:    f.reason,
+	}
+	if !f.since.IsZero() {
+		status.Since = f.since.UnixMilli()
+	}
+	return status
+}
+
+// venueLocked 当前使用的交易所ID（调用方需持有锁）
+func (f *PriceFailover) venueLocked() string {
+	switch {
+	case f.manual != "":
+		return f.manual
+	case f.active != "":
+		return f.active
+	default:
+		return primaryExchangeID()
+	}
+}
+
+// Evaluate 按数据质量评分决定是否切换：主交易所低于阈值时切到评分最高且达到阈值的其他交易所，恢复后切回
+func (f *PriceFailover) Evaluate() {
+	if f.threshold <= 0 {
+		return
+	}
+
+	tracker := GetDataQualityTracker()
+	primary := primaryExchangeID()
+	primaryScore, ok := tracker.GetScore(primary)
+	if !ok {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.manual != "" {
+		return
+	}
+
+	target := f.active
+	switch {
+	case f.active != "" && primaryScore.Score >= f.threshold+priceFailoverHysteresis:
+		target = ""
+	case primaryScore.Score < f.threshold:
+		target = ""
+		best := primaryScore.Score
+		for _, score := range tracker.GetScores() {
+			if strings.EqualFold(score.Exchange, primary) || score.Score < f.threshold || score.Score <= best {
+				continue
+			}
+			if _, running := PriceManagerFor(score.Exchange); !running {
+				continue
+			}
+			target, best = score.Exchange, score.Score
+		}
+		// 没有更好的交易所时保持当前选择
+		if target == "" && f.active != "" {
+			if current, exists := tracker.GetScore(f.active); exists && current.Score > primaryScore.Score {
+				target = f.active
+			}
+		}
+	}
+	if target == f.active {
+		return
+	}
+
+	from := f.venueLocked()
+	f.active = target
+	f.since = time.Now()
+	to := f.venueLocked()
+	if target == "" {
+		f.reason = fmt.Sprintf("%s 数据质量评分恢复到 %.0f", primary, primaryScore.Score)
+	} else {
+		f.reason = fmt.Sprintf("%s 数据质量评分 %.0f 低于阈值 %.0f", primary, primaryScore.Score, f.threshold)
+	}
+
+	logrus.Warnf("价格监控交易所切换: %s -> %s (%s)", from, to, f.reason)
+	notify.Send(notify.EventFailover, i18n.T("notify.price_failover.title"),
+		i18n.T("notify.price_failover.message", from, to, f.reason),
+		map[string]interface{}{"from": from, "to": to})
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"math"
+	"strconv"
+	"trading_assistant/models"
+)
+
+// PriceFormatter 按交易所缓存的币种精度格式化价格和数量，币种信息按 MarketID 读取
+type PriceFormatter struct {
+	exchange string
+}
+
+// Formatter 获取指定交易所的格式化器，exchange 为空时使用主交易所
+func Formatter(exchange string) *PriceFormatter {
+	return &PriceFormatter{exchange: exchange}
+}
+
+// FormatPrice 按价格步长精度格式化价格，获取不到币种时去掉多余的0
+func (f *PriceFormatter) FormatPrice(marketID string, price float64) string {
+	return FormatCoinPrice(f.coin(marketID), price)
+}
+
+// FormatAmount 按数量步长精度格式化数量，获取不到币种时去掉多余的0
+func (f *PriceFormatter) FormatAmount(marketID string, amount float64) string {
+	return FormatCoinAmount(f.coin(marketID), amount)
+}
+
+// RoundToTick 将价格取整到最近的价格步长，获取不到币种时原样返回
+func (f *PriceFormatter) RoundToTick(marketID string, price float64) float64 {
+	return RoundPriceToTick(f.coin(marketID), price)
+}
+
+// RoundAmount 按数量步长向下取整，获取不到币种时原样返回
+func (f *PriceFormatter) RoundAmount(marketID string, amount float64) float64 {
+	coin := f.coin(marketID)
+	if coin == nil {
+		return amount
+	}
+	return RoundQuantity(coin, amount)
+}
+
+// coin 读取币种信息，失败时返回nil
+func (f *PriceFormatter) coin(marketID string) *models.Coin {
+	coin, err := ExchangeStore(f.exchange).GetCoin(marketID)
+	if err != nil {
+		return nil
+	}
+	return coin
+}
+
+// FormatCoinPrice 按币种价格步长精度格式化价格，coin 为nil或步长无效时去掉多余的0
+func FormatCoinPrice(coin *models.Coin, price float64) string {
+	if coin == nil || parseLimit(coin.TickSize) <= 0 {
+		return strconv.FormatFloat(price, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(price, 'f', coin.GetPricePrecisionFromTickSize(), 64)
+}
+
+// FormatCoinAmount 按币种数量步长精度格式化数量，coin 为nil或步长无效时去掉多余的0
+func FormatCoinAmount(coin *models.Coin, amount float64) string {
+	if coin == nil || parseLimit(coin.StepSize) <= 0 {
+		return strconv.FormatFloat(amount, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(amount, 'f', coin.GetQuantityPrecisionFromStepSize(), 64)
+}
+
+// RoundPriceToTick 将价格取整到最近的价格步长，coin 为nil或步长无效时原样返回
+func RoundPriceToTick(coin *models.Coin, price float64) float64 {
+	if coin == nil {
+		return price
+	}
+	tick := parseLimit(coin.TickSize)
+	if tick <= 0 {
+		return price
+	}
+	rounded := math.Round(price/tick) * tick
+	// 去除浮点误差，如 0.30000000000000004
+	rounded, _ = strconv.ParseFloat(strconv.FormatFloat(rounded, 'f', coin.GetPricePrecisionFromTickSize(), 64), 64)
+	return rounded
+}
@@ -3,18 +3,20 @@ package core
 import (
 	"context"
 	"fmt"
-	"strconv"
+	"strings"
+	"sync"
 	"time"
 	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
 	"trading_assistant/pkg/exchange_factory"
 	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/metrics"
 	"trading_assistant/pkg/redis"
-	"trading_assistant/pkg/websocket"
 
 	"github.com/sirupsen/logrus"
 )
 
-// PriceManager REST API 定时价格管理器
+// PriceManager 价格管理器，交易所支持推送时订阅价格推送，推送未覆盖的币种由REST定时轮询补齐
 type PriceManager struct {
 	exchangeClient exchange_factory.ExchangeInterface
 	ctx            context.Context
@@ -25,18 +27,46 @@ type PriceManager struct {
 	lastFetchTime  time.Time     // 最后获取时间
 	fetchCount     int64         // 获取次数
 	updateInterval time.Duration // 更新间隔
+	store          *redis.Client // 按交易所隔离的价格数据存储
+
+	streamMu   sync.Mutex
+	streamedAt map[string]time.Time // 各币种最后一次收到推送的时间
 }
 
+var (
+	priceManagers      = make(map[string]*PriceManager)
+	priceManagersMutex sync.RWMutex
+)
+
 // NewPriceManager 创建价格管理器
 func NewPriceManager(exchangeClient exchange_factory.ExchangeInterface) *PriceManager {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &PriceManager{
+	pm := &PriceManager{
 		exchangeClient: exchangeClient,
 		ctx:            ctx,
 		cancel:         cancel,
 		updateInterval: config.GlobalConfig.PriceUpdateInterval,
+		store:          ExchangeStore(exchangeClient.GetID()),
 	}
+
+	priceManagersMutex.Lock()
+	priceManagers[strings.ToLower(exchangeClient.GetID())] = pm
+	priceManagersMutex.Unlock()
+	return pm
+}
+
+// PriceManagerFor 获取交易所的价格管理器，交易所为空时返回主交易所
+func PriceManagerFor(exchange string) (*PriceManager, bool) {
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	if exchange == "" {
+		exchange = strings.ToLower(config.GlobalConfig.ExchangeType)
+	}
+
+	priceManagersMutex.RLock()
+	defer priceManagersMutex.RUnlock()
+	pm, exists := priceManagers[exchange]
+	return pm, exists
 }
 
 // Start 启动定时价格获取
@@ -54,8 +84,14 @@ func (pm *PriceManager) Start() error {
 
 	// 启动定时器
 	pm.ticker = time.NewTicker(pm.updateInterval)
-	go pm.run()
+	go pm.run(pm.ctx, pm.ticker)
 
+	// 不支持推送的交易所（如MEXC）只依赖REST轮询
+	if streamer, err := exchange_factory.AsMarkPriceStreamer(pm.exchangeClient); err == nil {
+		pm.startStream(pm.ctx, streamer)
+	} else {
+		logrus.Infof("%s 不支持价格推送，使用REST轮询获取价格", pm.exchangeClient.GetID())
+	}
 	logrus.Infof("价格管理器已启动，更新间隔: %v", pm.updateInterval)
 	return nil
 }
@@ -80,6 +116,13 @@ func (pm *PriceManager) Stop() {
 	logrus.Info("价格管理器已停止")
 }
 
+// Restart 重新启动定时价格获取，用于价格长时间未更新时重新订阅
+func (pm *PriceManager) Restart() error {
+	pm.Stop()
+	pm.ctx, pm.cancel = context.WithCancel(context.Background())
+	return pm.Start()
+}
+
 // IsRunning 检查管理器是否在运行
 func (pm *PriceManager) IsRunning() bool {
 	return pm.isRunning
@@ -93,13 +136,15 @@ func (pm *PriceManager) GetStatus() map[string]interface{} {
 		"last_fetch_time": pm.lastFetchTime.Unix(),
 		"fetch_count":     pm.fetchCount,
 		"update_interval": pm.updateInterval.String(),
-		"mode":            "rest_api_timer",
+		"mode":            pm.mode(),
+		"streaming":       exchange_factory.SupportsStreaming(pm.exchangeClient),
 		"exchange":        pm.exchangeClient.GetName(),
+		"primary":         pm.isPrimary(),
 	}
 }
 
-// run 主运行循环
-func (pm *PriceManager) run() {
+// run 主运行循环，ctx 和 ticker 在启动时传入，重启后旧循环自行退出
+func (pm *PriceManager) run(ctx context.Context, ticker *time.Ticker) {
 	defer func() {
 		if r := recover(); r != nil {
 			logrus.Errorf("价格管理器运行时发生异常: %v", r)
@@ -108,10 +153,10 @@ func (pm *PriceManager) run() {
 
 	for {
 		select {
-		case <-pm.ctx.Done():
+		case <-ctx.Done():
 			logrus.Info("价格管理器收到停止信号")
 			return
-		case <-pm.ticker.C:
+		case <-ticker.C:
 			pm.fetchPricesOnce()
 		}
 	}
@@ -140,6 +185,13 @@ func (pm *PriceManager) fetchPricesOnce() {
 		return
 	}
 
+	// 推送正常的币种不再轮询
+	selectedSymbols = pm.unstreamedSymbols(selectedSymbols)
+	if len(selectedSymbols) == 0 {
+		pm.lastFetchTime = time.Now()
+		return
+	}
+
 	// 获取实时买卖价（bookTicker）和资金费率（premiumIndex）
 	ctx, cancel := context.WithTimeout(pm.ctx, 10*time.Second)
 	defer cancel()
@@ -149,7 +201,13 @@ func (pm *PriceManager) fetchPricesOnce() {
 	isSpotMode := marketType == "spot"
 
 	// 1. 获取实时BookTicker数据（只包含bid/ask价格，权重更低）
+	qualityTracker := GetDataQualityTracker()
+	exchangeID := pm.exchangeClient.GetID()
+	defer qualityTracker.Broadcast()
+
 	tickers, err := pm.exchangeClient.FetchBookTickers(ctx, selectedSymbols, nil)
+	qualityTracker.RecordFetch(exchangeID, err, pm.updateInterval)
+	metrics.ExchangeRequests.WithLabelValues(exchangeID, "book_tickers", metrics.ResultLabel(err)).Inc()
 	if err != nil {
 		logrus.Errorf("获取BookTicker数据失败: %v", err)
 		return
@@ -159,6 +217,8 @@ func (pm *PriceManager) fetchPricesOnce() {
 	var markPrices map[string]*types.MarkPrice
 	if !isSpotMode {
 		markPrices, err = pm.exchangeClient.FetchMarkPrices(ctx, selectedSymbols)
+		qualityTracker.RecordFetch(exchangeID, err, 0)
+		metrics.ExchangeRequests.WithLabelValues(exchangeID, "mark_prices", metrics.ResultLabel(err)).Inc()
 		if err != nil {
 			logrus.Warnf("获取标记价格失败: %v", err)
 			// 期货模式下标记价格获取失败，继续处理（使用ticker数据）
@@ -166,8 +226,7 @@ func (pm *PriceManager) fetchPricesOnce() {
 	}
 
 	pm.lastFetchTime = time.Now()
-	processedCount := 0
-	pricesData := make(map[string]interface{}) // 用于广播的价格数据
+	prices := make(map[string]*types.WatchMarkPrice, len(selectedSymbols)) // 本轮有效的价格数据
 
 	// 3. 合并两个数据源
 	for _, symbol := range selectedSymbols {
@@ -184,90 +243,34 @@ func (pm *PriceManager) fetchPricesOnce() {
 			continue
 		}
 
-		// 构建完整的价格数据结构
-		watchMarkPrice := &types.WatchMarkPrice{
-			Symbol:    symbol,
-			TimeStamp: time.Now().UnixMilli(),
-		}
-
-		// 从 Ticker 获取实时买卖价（优先使用）
-		if ticker != nil {
-			watchMarkPrice.BidPrice = ticker.Bid // 最优买价（实时）
-			watchMarkPrice.AskPrice = ticker.Ask // 最优卖价（实时）
-			// 获取参考价格：优先使用 Last，如果为 0 则用 Bid/Ask 中间价
-			if ticker.Last > 0 {
-				watchMarkPrice.MarkPrice = ticker.Last
-			} else if ticker.Bid > 0 && ticker.Ask > 0 {
-				watchMarkPrice.MarkPrice = (ticker.Bid + ticker.Ask) / 2
-			} else if ticker.Bid > 0 {
-				watchMarkPrice.MarkPrice = ticker.Bid
-			} else if ticker.Ask > 0 {
-				watchMarkPrice.MarkPrice = ticker.Ask
-			}
-		}
-
-		// 从 MarkPrice 获取资金费率等信息（仅期货模式）
-		if markPrice != nil {
-			watchMarkPrice.MarkPrice = markPrice.MarkPrice         // 标记价格（作为参考）
-			watchMarkPrice.IndexPrice = markPrice.IndexPrice       // 指数价格
-			watchMarkPrice.FundingRate = markPrice.FundingRate     // 资金费率
-			watchMarkPrice.FundingTime = markPrice.NextFundingTime // 下次资金费时间
-		}
-
-		// 如果没有bid/ask，降级使用标记价格或最新价
-		if watchMarkPrice.BidPrice <= 0 && watchMarkPrice.MarkPrice > 0 {
-			watchMarkPrice.BidPrice = watchMarkPrice.MarkPrice
-		}
-		if watchMarkPrice.AskPrice <= 0 && watchMarkPrice.MarkPrice > 0 {
-			watchMarkPrice.AskPrice = watchMarkPrice.MarkPrice
-		}
+		watchMarkPrice := buildWatchMarkPrice(symbol, ticker, markPrice)
 
 		// 验证数据有效性
 		if watchMarkPrice.BidPrice <= 0 || watchMarkPrice.AskPrice <= 0 {
 			logrus.Warnf("跳过 %s: 买卖价无效 (bid=%f, ask=%f)", symbol, watchMarkPrice.BidPrice, watchMarkPrice.AskPrice)
+			qualityTracker.RecordPriceCheck(exchangeID, false)
 			continue
 		}
+		qualityTracker.RecordPriceCheck(exchangeID, true)
 
 		// 保存到Redis缓存
 		if err := pm.saveToCache(watchMarkPrice); err != nil {
 			logrus.Errorf("保存 %s 价格数据到缓存失败: %v", symbol, err)
 		}
 
-		// 获取价格变化信息用于广播
-		priceChange := 0.0
-		priceChangePercent := 0.0
-		if coin, err := redis.GlobalRedisClient.GetCoin(symbol); err == nil {
-			if change, parseErr := strconv.ParseFloat(coin.PriceChange, 64); parseErr == nil {
-				priceChange = change
-			}
-			if changePercent, parseErr := strconv.ParseFloat(coin.PriceChangePercent, 64); parseErr == nil {
-				priceChangePercent = changePercent
-			}
-		}
-
-		// 构建广播数据（包含实时买卖价）
-		pricesData[symbol] = map[string]interface{}{
-			"symbol":             symbol,
-			"bidPrice":           watchMarkPrice.BidPrice,    // 实时买价
-			"askPrice":           watchMarkPrice.AskPrice,    // 实时卖价
-			"markPrice":          watchMarkPrice.MarkPrice,   // 标记价格（参考）
-			"indexPrice":         watchMarkPrice.IndexPrice,  // 指数价格
-			"fundingRate":        watchMarkPrice.FundingRate, // 资金费率
-			"fundingTime":        watchMarkPrice.FundingTime, // 下次资金费时间
-			"updateTime":         watchMarkPrice.TimeStamp,   // 更新时间
-			"priceChange":        priceChange,
-			"priceChangePercent": priceChangePercent,
-		}
-
-		processedCount++
+		prices[symbol] = watchMarkPrice
 	}
 
+	processedCount := len(prices)
 	duration := time.Since(startTime)
 	logrus.Debugf("获取价格完成: %d/%d 个币种，耗时: %v", processedCount, len(selectedSymbols), duration)
 
-	// 直接广播已获取的价格数据给前端
+	// 发布价格事件，由基差监控、前端广播等订阅者各自处理
 	if processedCount > 0 {
-		go pm.broadcastPrices(pricesData)
+		eventbus.GetBus().Publish(eventbus.TopicMarkPrice, exchangeID, &eventbus.MarkPriceBatch{
+			Primary: pm.isPrimary(),
+			Prices:  prices,
+		})
 	}
 
 	// 每100次获取记录一次统计日志
@@ -277,23 +280,177 @@ func (pm *PriceManager) fetchPricesOnce() {
 	}
 }
 
+// buildWatchMarkPrice 合并BookTicker和标记价格，bid/ask缺失时降级使用标记价格
+func buildWatchMarkPrice(symbol string, ticker *types.Ticker, markPrice *types.MarkPrice) *types.WatchMarkPrice {
+	watchMarkPrice := &types.WatchMarkPrice{
+		Symbol:    symbol,
+		TimeStamp: time.Now().UnixMilli(),
+	}
+
+	// 从 Ticker 获取实时买卖价（优先使用）
+	if ticker != nil {
+		watchMarkPrice.BidPrice = ticker.Bid // 最优买价（实时）
+		watchMarkPrice.AskPrice = ticker.Ask // 最优卖价（实时）
+		watchMarkPrice.LastPrice = ticker.Last
+		// 获取参考价格：优先使用 Last，如果为 0 则用 Bid/Ask 中间价
+		if ticker.Last > 0 {
+			watchMarkPrice.MarkPrice = ticker.Last
+		} else if ticker.Bid > 0 && ticker.Ask > 0 {
+			watchMarkPrice.MarkPrice = (ticker.Bid + ticker.Ask) / 2
+		} else if ticker.Bid > 0 {
+			watchMarkPrice.MarkPrice = ticker.Bid
+		} else if ticker.Ask > 0 {
+			watchMarkPrice.MarkPrice = ticker.Ask
+		}
+	}
+
+	// 从 MarkPrice 获取资金费率等信息（仅期货模式）
+	if markPrice != nil {
+		watchMarkPrice.MarkPrice = markPrice.MarkPrice         // 标记价格（作为参考）
+		watchMarkPrice.IndexPrice = markPrice.IndexPrice       // 指数价格
+		watchMarkPrice.FundingRate = markPrice.FundingRate     // 资金费率
+		watchMarkPrice.FundingTime = markPrice.NextFundingTime // 下次资金费时间
+	}
+
+	// 如果没有bid/ask，降级使用标记价格或最新价
+	if watchMarkPrice.BidPrice <= 0 && watchMarkPrice.MarkPrice > 0 {
+		watchMarkPrice.BidPrice = watchMarkPrice.MarkPrice
+	}
+	if watchMarkPrice.AskPrice <= 0 && watchMarkPrice.MarkPrice > 0 {
+		watchMarkPrice.AskPrice = watchMarkPrice.MarkPrice
+	}
+
+	return watchMarkPrice
+}
+
 // saveToCache 保存价格数据到Redis缓存
 func (pm *PriceManager) saveToCache(markPrice *types.WatchMarkPrice) error {
-	if redis.GlobalRedisClient == nil {
+	if pm.store == nil {
 		return fmt.Errorf("redis客户端未初始化")
 	}
 
-	return redis.GlobalRedisClient.SetMarkPrice(markPrice)
+	return pm.store.SetMarkPrice(markPrice)
 }
 
-// broadcastPrices 广播价格数据给前端
-func (pm *PriceManager) broadcastPrices(pricesData map[string]interface{}) {
-	wsManager := websocket.GetGlobalWebSocketManager()
-	if wsManager == nil {
-		logrus.Debug("WebSocket管理器未初始化")
-		return
+// isPrimary 是否为主交易所
+func (pm *PriceManager) isPrimary() bool {
+	return pm.store.GetNamespace() == ""
+}
+
+// startStream 订阅选中币种的价格推送，推送断开或未覆盖的币种由 fetchPricesOnce 轮询
+func (pm *PriceManager) startStream(ctx context.Context, streamer exchange_factory.MarkPriceStreamer) {
+	stream := &symbolStream{
+		name: "价格推送",
+		subscribe: func(ctx context.Context, symbols []string) error {
+			prices, err := streamer.WatchMarkPrices(ctx, symbols)
+			if err != nil {
+				return err
+			}
+			go pm.consumeStream(ctx, prices)
+			return nil
+		},
 	}
+	go stream.run(ctx)
+}
 
-	wsManager.BroadcastPrices(pricesData)
-	logrus.Debugf("通过WebSocket广播价格数据，包含 %d 个币种", len(pricesData))
+// consumeStream 处理价格推送直到订阅取消，按更新间隔合并发布价格事件
+func (pm *PriceManager) consumeStream(ctx context.Context, updates <-chan *types.WatchMarkPrice) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("处理价格推送时发生异常: %v", r)
+		}
+	}()
+
+	exchangeID := pm.exchangeClient.GetID()
+	qualityTracker := GetDataQualityTracker()
+	ticker := time.NewTicker(pm.updateInterval)
+	defer ticker.Stop()
+
+	pending := make(map[string]*types.WatchMarkPrice)
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		eventbus.GetBus().Publish(eventbus.TopicMarkPrice, exchangeID, &eventbus.MarkPriceBatch{
+			Primary: pm.isPrimary(),
+			Prices:  pending,
+		})
+		pending = make(map[string]*types.WatchMarkPrice)
+		qualityTracker.Broadcast()
+	}
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				flush()
+				if ctx.Err() == nil {
+					logrus.Warnf("%s 价格推送已断开，改用REST轮询", exchangeID)
+				}
+				return
+			}
+			if update.BidPrice <= 0 && update.MarkPrice > 0 {
+				update.BidPrice = update.MarkPrice
+			}
+			if update.AskPrice <= 0 && update.MarkPrice > 0 {
+				update.AskPrice = update.MarkPrice
+			}
+			if update.BidPrice <= 0 || update.AskPrice <= 0 {
+				qualityTracker.RecordPriceCheck(exchangeID, false)
+				continue
+			}
+			qualityTracker.RecordPriceCheck(exchangeID, true)
+
+			if err := pm.saveToCache(update); err != nil {
+				logrus.Errorf("保存 %s 推送价格到缓存失败: %v", update.Symbol, err)
+				continue
+			}
+			pm.markStreamed(update.Symbol)
+			pending[update.Symbol] = update
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// markStreamed 记录币种收到推送的时间
+func (pm *PriceManager) markStreamed(symbol string) {
+	pm.streamMu.Lock()
+	defer pm.streamMu.Unlock()
+	if pm.streamedAt == nil {
+		pm.streamedAt = make(map[string]time.Time)
+	}
+	pm.streamedAt[symbol] = time.Now()
+}
+
+// unstreamedSymbols 过滤出两个更新间隔内没有收到推送的币种
+func (pm *PriceManager) unstreamedSymbols(symbols []string) []string {
+	pm.streamMu.Lock()
+	defer pm.streamMu.Unlock()
+	if len(pm.streamedAt) == 0 {
+		return symbols
+	}
+
+	cutoff := time.Now().Add(-2 * pm.updateInterval)
+	result := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		if pm.streamedAt[symbol].Before(cutoff) {
+			result = append(result, symbol)
+		}
+	}
+	return result
+}
+
+// mode 当前价格获取方式，有币种推送正常时为推送模式
+func (pm *PriceManager) mode() string {
+	pm.streamMu.Lock()
+	defer pm.streamMu.Unlock()
+
+	cutoff := time.Now().Add(-2 * pm.updateInterval)
+	for _, at := range pm.streamedAt {
+		if at.After(cutoff) {
+			return "websocket_stream"
+		}
+	}
+	return "rest_api_timer"
 }
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/redis"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// deriskPolicy 危险告警时的自动减仓策略
+type deriskPolicy struct {
+	enabled    bool
+	percentage float64            // 默认减仓比例 (0-100)
+	overrides  map[string]float64 // MarketID -> 减仓比例
+}
+
+// newDeriskPolicyFromConfig 从全局配置创建自动减仓策略
+func newDeriskPolicyFromConfig() deriskPolicy {
+	cfg := config.GlobalConfig
+	return deriskPolicy{
+		enabled:    cfg.RiskAutoDeriskEnabled,
+		percentage: cfg.RiskAutoDeriskPercentage,
+		overrides:  parseDeriskOverrides(cfg.RiskAutoDeriskOverrides),
+	}
+}
+
+// parseDeriskOverrides 解析按币种覆盖的减仓比例，格式 SYMBOL:PERCENT
+func parseDeriskOverrides(items []string) map[string]float64 {
+	overrides := make(map[string]float64, len(items))
+	for _, item := range items {
+		symbol, value, found := strings.Cut(item, ":")
+		if !found {
+			logrus.Warnf("忽略无效的自动减仓配置: %s", item)
+			continue
+		}
+		percentage, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || percentage < 0 || percentage > 100 {
+			logrus.Warnf("忽略无效的自动减仓比例: %s", item)
+			continue
+		}
+		overrides[strings.ToUpper(strings.TrimSpace(symbol))] = percentage
+	}
+	return overrides
+}
+
+// percentageFor 获取币种的减仓比例，0 表示不减仓
+func (p deriskPolicy) percentageFor(symbol string) float64 {
+	if !p.enabled {
+		return 0
+	}
+	if percentage, exists := p.overrides[strings.ToUpper(symbol)]; exists {
+		return percentage
+	}
+	return min(max(p.percentage, 0), 100)
+}
+
+// derisk 持仓进入危险等级时创建市价减仓预估并立即执行
+func (rm *RiskMonitor) derisk(risk *PositionRisk) {
+	percentage := rm.deriskPolicy.percentageFor(risk.Symbol)
+	if percentage <= 0 || GlobalPriceMonitor == nil {
+		return
+	}
+
+	now := time.Now()
+	estimate := &models.PriceEstimate{
+		ID:          uuid.New().String(),
+		Symbol:      risk.Symbol,
+		Side:        risk.Side,
+		ActionType:  models.ActionTypeTakeProfit,
+		Percentage:  percentage,
+		Leverage:    int(risk.Leverage),
+		OrderType:   types.OrderTypeMarket,
+		MarginMode:  types.MarginModeCross,
+		TriggerType: models.TriggerTypeImmediate,
+		Tag:         fmt.Sprintf("derisk:%d", risk.TradeID),
+		Status:      models.EstimateStatusListening,
+		Enabled:     true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := redis.GlobalRedisClient.SetPriceEstimate(estimate); err != nil {
+		logrus.Errorf("保存自动减仓预估失败: %v", err)
+		return
+	}
+	RecordEstimateEvent(estimate.ID, models.EstimateEventCreated, "自动减仓", nil)
+
+	logrus.Warnf("自动减仓: %s %s 距强平 %.2f%%, 市价减仓 %.0f%%",
+		risk.Symbol, getPositionText(risk.Side), risk.Distance*100, percentage)
+	notify.Send(notify.EventRisk, i18n.T("notify.derisk.title"),
+		i18n.T("notify.derisk.message", risk.Symbol, getPositionText(risk.Side), risk.Distance*100, percentage),
+		map[string]interface{}{"trade_id": risk.TradeID, "estimate_id": estimate.ID})
+
+	GlobalPriceMonitor.triggerEstimate(estimate, risk.MarkPrice, now)
+}
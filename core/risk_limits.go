@@ -0,0 +1,238 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/metrics"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 风控限制名称，用于指标标签
+const (
+	riskLimitOpenNotional = "max_open_notional"
+	riskLimitPositions    = "max_positions"
+	riskLimitLeverage     = "max_leverage"
+	riskLimitStake        = "max_stake_per_trade"
+)
+
+// riskExposure 计入风控限制的一个持仓
+type riskExposure struct {
+	symbol   string
+	side     string
+	notional float64
+}
+
+// riskViolation 违反的风控限制
+type riskViolation struct {
+	limit   string
+	message string
+}
+
+// riskExposureTimeout 触发时读取各Freqtrade实例持仓的超时时间
+const riskExposureTimeout = 5 * time.Second
+
+// checkRiskLimits 开仓和加仓触发时按全局和币种限制检查持仓名义价值、持仓数量、杠杆和单笔保证金，违反时返回错误拒绝下单
+// 实盘持仓在触发时从所有Freqtrade实例实时读取，模拟交易模式下取模拟持仓
+func checkRiskLimits(ctx context.Context, estimate *models.PriceEstimate, price float64) error {
+	if !isIncreaseAction(estimate.ActionType) {
+		return nil
+	}
+
+	limits, err := redis.GlobalRedisClient.GetRiskLimits()
+	if err != nil {
+		return fmt.Errorf("无法读取风控限制，拒绝下单: %w", err)
+	}
+	if limits.IsEmpty() {
+		return nil
+	}
+
+	exposures, err := loadRiskExposures(ctx)
+	if err != nil {
+		return fmt.Errorf("无法读取当前持仓，拒绝下单: %w", err)
+	}
+
+	symbol := strings.ToUpper(estimate.Symbol)
+	sizing := ComputeOrderSizing(estimate, price)
+
+	// 开仓未指定金额时由Freqtrade按配置的 stake_amount 下单，按该金额计算名义价值和保证金
+	if estimate.ActionType == models.ActionTypeOpen && estimate.Amount <= 0 && estimate.StakeAmount <= 0 {
+		stake, err := defaultOpenStake(ctx, estimate)
+		if err != nil {
+			if limitsSizeBound(limits.Global) || limitsSizeBound(limits.Symbols[symbol]) {
+				return fmt.Errorf("无法确定默认投入金额，已配置名义价值或单笔保证金限制，拒绝下单: %w", err)
+			}
+		} else {
+			sized := *estimate
+			sized.StakeAmount = stake
+			sizing = ComputeOrderSizing(&sized, price)
+		}
+	}
+
+	violations := evaluateRiskLimit("全局", limits.Global, estimate, sizing, exposures)
+	if limit, exists := limits.Symbols[symbol]; exists {
+		symbolExposures := make([]riskExposure, 0)
+		for _, exposure := range exposures {
+			if exposure.symbol == symbol {
+				symbolExposures = append(symbolExposures, exposure)
+			}
+		}
+		violations = append(violations, evaluateRiskLimit(symbol, limit, estimate, sizing, symbolExposures)...)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		metrics.RiskLimitBlocks.WithLabelValues(violation.limit).Inc()
+		messages = append(messages, violation.message)
+	}
+	logrus.WithFields(logrus.Fields{
+		"estimate_id": estimate.ID,
+		"symbol":      estimate.Symbol,
+		"action_type": estimate.ActionType,
+	}).Warnf("下单被风控限制拦截: %s", strings.Join(messages, "; "))
+	return fmt.Errorf("风控限制拦截: %s", strings.Join(messages, "; "))
+}
+
+// defaultOpenStake 未指定 stake_amount 的开仓实际使用的投入金额：模拟交易取模拟默认金额，实盘取Freqtrade配置
+func defaultOpenStake(ctx context.Context, estimate *models.PriceEstimate) (float64, error) {
+	if config.GlobalConfig != nil && config.GlobalConfig.DryRun {
+		if stake := GetPaperEngine().defaultStake; stake > 0 {
+			return stake, nil
+		}
+		return 0, fmt.Errorf("未配置模拟交易默认投入金额")
+	}
+	if freqtrade.GlobalRegistry == nil {
+		return 0, fmt.Errorf("未配置Freqtrade实例")
+	}
+	bot, err := freqtrade.GlobalRegistry.Resolve(estimate.BotName, estimate.Side)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, riskExposureTimeout)
+	defer cancel()
+	return bot.DefaultStakeAmount(ctx)
+}
+
+// limitsSizeBound 限制是否依赖本次下单金额（持仓名义价值或单笔保证金）
+func limitsSizeBound(limit models.RiskLimit) bool {
+	return limit.MaxOpenNotional > 0 || limit.MaxStakePerTrade > 0
+}
+
+// evaluateRiskLimit 按一组限制检查本次下单，exposures 为该限制范围内的现有持仓
+func evaluateRiskLimit(scope string, limit models.RiskLimit, estimate *models.PriceEstimate, sizing *models.OrderSizing, exposures []riskExposure) []riskViolation {
+	var violations []riskViolation
+
+	if limit.MaxLeverage > 0 && sizing.Leverage > limit.MaxLeverage {
+		violations = append(violations, riskViolation{riskLimitLeverage,
+			fmt.Sprintf("%s杠杆上限 %dx，本次 %dx", scope, limit.MaxLeverage, sizing.Leverage)})
+	}
+
+	if limit.MaxStakePerTrade > 0 && sizing.Margin > limit.MaxStakePerTrade {
+		violations = append(violations, riskViolation{riskLimitStake,
+			fmt.Sprintf("%s单笔保证金上限 %.2f，本次 %.2f", scope, limit.MaxStakePerTrade, sizing.Margin)})
+	}
+
+	if limit.MaxOpenNotional > 0 {
+		var openNotional float64
+		for _, exposure := range exposures {
+			openNotional += exposure.notional
+		}
+		if openNotional+sizing.Notional > limit.MaxOpenNotional {
+			violations = append(violations, riskViolation{riskLimitOpenNotional,
+				fmt.Sprintf("%s持仓名义价值上限 %.2f，当前 %.2f，本次 %.2f", scope, limit.MaxOpenNotional, openNotional, sizing.Notional)})
+		}
+	}
+
+	// 只有开新仓才会增加持仓数量
+	if limit.MaxPositions > 0 && estimate.ActionType == models.ActionTypeOpen {
+		symbol := strings.ToUpper(estimate.Symbol)
+		count := len(exposures)
+		exists := false
+		for _, exposure := range exposures {
+			if exposure.symbol == symbol && strings.EqualFold(exposure.side, estimate.Side) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			count++
+		}
+		if count > limit.MaxPositions {
+			violations = append(violations, riskViolation{riskLimitPositions,
+				fmt.Sprintf("%s持仓数量上限 %d，当前 %d", scope, limit.MaxPositions, len(exposures))})
+		}
+	}
+
+	return violations
+}
+
+// loadRiskExposures 读取当前持仓，任一Freqtrade实例读取失败时返回错误，不能把账户当作空仓
+func loadRiskExposures(ctx context.Context) ([]riskExposure, error) {
+	if config.GlobalConfig != nil && config.GlobalConfig.DryRun {
+		positions, err := GetPaperEngine().GetPositions()
+		if err != nil {
+			return nil, err
+		}
+		exposures := make([]riskExposure, 0, len(positions))
+		for _, position := range positions {
+			price := position.MarkPrice
+			if price <= 0 {
+				price = position.EntryPrice
+			}
+			exposures = append(exposures, riskExposure{
+				symbol:   strings.ToUpper(position.Symbol),
+				side:     position.Side,
+				notional: position.Amount * price,
+			})
+		}
+		return exposures, nil
+	}
+
+	if freqtrade.GlobalRegistry == nil {
+		return nil, fmt.Errorf("未配置Freqtrade实例")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, riskExposureTimeout)
+	defer cancel()
+
+	var exposures []riskExposure
+	for _, bot := range freqtrade.GlobalRegistry.All() {
+		trades, err := bot.GetTradeStatus(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("获取Freqtrade实例 %s 持仓失败: %w", bot.Name, err)
+		}
+		for i := range trades {
+			trade := &trades[i]
+			if !trade.IsOpen {
+				continue
+			}
+			price := trade.CurrentRate
+			if price <= 0 {
+				price = trade.OpenRate
+			}
+			side := types.PositionSideLong
+			if trade.IsShort || trade.TradeDirection == types.PositionSideShort {
+				side = types.PositionSideShort
+			}
+			exposures = append(exposures, riskExposure{
+				symbol:   utils.ConvertSymbolToMarketID(trade.Pair),
+				side:     side,
+				notional: math.Abs(trade.Amount) * price,
+			})
+		}
+	}
+	return exposures, nil
+}
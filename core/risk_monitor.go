@@ -0,0 +1,249 @@
+package core
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/utils"
+	"trading_assistant/pkg/websocket"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AlertTypeRisk 持仓强平风险告警类型
+const AlertTypeRisk = "risk"
+
+// 持仓风险等级
+const (
+	RiskLevelNormal   = "normal"
+	RiskLevelWarning  = "warning"  // 距强平价不足预警距离
+	RiskLevelCritical = "critical" // 距强平价不足危险距离
+)
+
+// riskLevelRank 风险等级排序，用于判断是否升级
+var riskLevelRank = map[string]int{
+	RiskLevelNormal:   0,
+	RiskLevelWarning:  1,
+	RiskLevelCritical: 2,
+}
+
+// PositionRisk 单个持仓的强平风险
+type PositionRisk struct {
+	TradeID          int     `json:"trade_id"`
+	Symbol           string  `json:"symbol"` // MarketID
+	Side             string  `json:"side"`   // long, short
+	Leverage         float64 `json:"leverage"`
+	EntryPrice       float64 `json:"entry_price"`
+	MarkPrice        float64 `json:"mark_price"`
+	LiquidationPrice float64 `json:"liquidation_price"`
+	Distance         float64 `json:"distance"` // 标记价格距强平价的比例，相对强平价计算
+	Level            string  `json:"level"`
+	Timestamp        int64   `json:"timestamp"`
+}
+
+// RiskMonitor 持仓强平风险监控器
+// 当前没有交易所用户数据流，定时从Freqtrade持仓读取强平价，结合Redis中的实时标记价格计算距强平距离
+type RiskMonitor struct {
+	freqtradeClient  *freqtrade.Controller
+	interval         time.Duration
+	warningDistance  float64
+	criticalDistance float64
+	deriskPolicy     deriskPolicy
+	stopChan         chan struct{}
+
+	mu     sync.RWMutex
+	levels map[int]string // tradeID -> 最近一次的风险等级
+	risks  []*PositionRisk
+}
+
+var GlobalRiskMonitor *RiskMonitor
+
+// InitRiskMonitor 初始化持仓风险监控器
+func InitRiskMonitor(freqtradeClient *freqtrade.Controller) {
+	GlobalRiskMonitor = &RiskMonitor{
+		freqtradeClient:  freqtradeClient,
+		interval:         config.GlobalConfig.RiskMonitorInterval,
+		warningDistance:  config.GlobalConfig.RiskWarningDistance,
+		criticalDistance: config.GlobalConfig.RiskCriticalDistance,
+		deriskPolicy:     newDeriskPolicyFromConfig(),
+		levels:           make(map[int]string),
+	}
+}
+
+// Start 启动风险监控
+func (rm *RiskMonitor) Start() {
+	if rm.interval <= 0 || rm.freqtradeClient == nil {
+		logrus.Info("持仓风险监控未启用")
+		return
+	}
+	if rm.stopChan != nil {
+		return
+	}
+
+	rm.stopChan = make(chan struct{})
+	go rm.loop(rm.stopChan)
+	logrus.Infof("持仓风险监控已启动，检查间隔: %v, 预警距离: %.2f%%, 危险距离: %.2f%%",
+		rm.interval, rm.warningDistance*100, rm.criticalDistance*100)
+}
+
+// Stop 停止风险监控
+func (rm *RiskMonitor) Stop() {
+	if rm.stopChan == nil {
+		return
+	}
+	close(rm.stopChan)
+	rm.stopChan = nil
+}
+
+// loop 定时检查持仓风险，stopChan 在启动时传入，避免快速重启时读到下一轮的通道
+func (rm *RiskMonitor) loop(stopChan chan struct{}) {
+	ticker := time.NewTicker(rm.interval)
+	defer ticker.Stop()
+
+	rm.Check()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			rm.Check()
+		}
+	}
+}
+
+// GetRisks 获取最近一次检查的持仓风险，按距强平距离从近到远排序
+func (rm *RiskMonitor) GetRisks() []*PositionRisk {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.risks
+}
+
+// Check 检查所有持仓的强平风险，风险等级升级时发出告警，升级到危险等级时按配置自动减仓
+func (rm *RiskMonitor) Check() {
+	trades, err := rm.freqtradeClient.GetTradeStatus(context.Background())
+	if err != nil {
+		logrus.Warnf("获取持仓失败，跳过风险检查: %v", err)
+		return
+	}
+
+	now := time.Now()
+	risks := make([]*PositionRisk, 0, len(trades))
+	for i := range trades {
+		if risk := rm.evaluate(&trades[i], now); risk != nil {
+			risks = append(risks, risk)
+		}
+	}
+	sort.Slice(risks, func(i, j int) bool { return risks[i].Distance < risks[j].Distance })
+
+	var escalated []*PositionRisk
+	rm.mu.Lock()
+	active := make(map[int]bool, len(risks))
+	for _, risk := range risks {
+		active[risk.TradeID] = true
+		previous, exists := rm.levels[risk.TradeID]
+		if !exists {
+			previous = RiskLevelNormal
+		}
+		if riskLevelRank[risk.Level] > riskLevelRank[previous] {
+			escalated = append(escalated, risk)
+		}
+		rm.levels[risk.TradeID] = risk.Level
+	}
+	// 清理已平仓的持仓
+	for tradeID := range rm.levels {
+		if !active[tradeID] {
+			delete(rm.levels, tradeID)
+		}
+	}
+	rm.risks = risks
+	rm.mu.Unlock()
+
+	for _, risk := range escalated {
+		rm.alert(risk)
+		if risk.Level == RiskLevelCritical {
+			rm.derisk(risk)
+		}
+	}
+}
+
+// evaluate 计算单个持仓的强平风险，没有强平价的持仓返回nil
+func (rm *RiskMonitor) evaluate(trade *models.TradePosition, now time.Time) *PositionRisk {
+	if !trade.IsOpen || trade.LiquidationPrice == nil || *trade.LiquidationPrice <= 0 {
+		return nil
+	}
+
+	side := types.PositionSideLong
+	if trade.IsShort {
+		side = types.PositionSideShort
+	}
+	marketID := utils.ConvertSymbolToMarketID(trade.Pair)
+
+	// 优先使用实时标记价格，取不到时使用Freqtrade的当前价格
+	markPrice := trade.CurrentRate
+	if data, err := ExchangeStore("").GetMarkPrice(marketID); err == nil && data != nil && data.MarkPrice > 0 {
+		markPrice = data.MarkPrice
+	}
+	if markPrice <= 0 {
+		return nil
+	}
+
+	position := &types.Position{
+		Side:             side,
+		MarkPrice:        markPrice,
+		LiquidationPrice: *trade.LiquidationPrice,
+	}
+
+	level := RiskLevelNormal
+	switch {
+	case position.IsLiquidationRisk(rm.criticalDistance):
+		level = RiskLevelCritical
+	case position.IsLiquidationRisk(rm.warningDistance):
+		level = RiskLevelWarning
+	}
+
+	leverage := 1.0
+	if trade.Leverage != nil {
+		leverage = *trade.Leverage
+	}
+
+	return &PositionRisk{
+		TradeID:          trade.TradeId,
+		Symbol:           marketID,
+		Side:             side,
+		Leverage:         leverage,
+		EntryPrice:       trade.OpenRate,
+		MarkPrice:        markPrice,
+		LiquidationPrice: position.LiquidationPrice,
+		Distance:         math.Abs(markPrice-position.LiquidationPrice) / position.LiquidationPrice,
+		Level:            level,
+		Timestamp:        now.UnixMilli(),
+	}
+}
+
+// alert 通过通知分发器和WebSocket推送风险告警
+func (rm *RiskMonitor) alert(risk *PositionRisk) {
+	title := i18n.T("notify.risk_warning.title")
+	if risk.Level == RiskLevelCritical {
+		title = i18n.T("notify.risk_critical.title")
+	}
+	message := i18n.T("notify.risk.message",
+		risk.Symbol, getPositionText(risk.Side), risk.Leverage, risk.MarkPrice, risk.LiquidationPrice, risk.Distance*100)
+	logrus.Warnf("%s: %s", title, message)
+
+	if wsManager := websocket.GetGlobalWebSocketManager(); wsManager != nil {
+		wsManager.BroadcastAlert(AlertTypeRisk, risk)
+	}
+
+	notify.Send(notify.EventRisk, title, message, map[string]interface{}{
+		"trade_id": risk.TradeID,
+		"level":    risk.Level,
+	})
+}
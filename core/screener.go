@@ -0,0 +1,154 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/redis"
+)
+
+// screenerSortFields 选币器支持的排序字段，取值函数返回 false 表示该币种没有这个指标
+var screenerSortFields = map[string]func(row *models.ScreenerRow) (float64, bool){
+	"change":    func(row *models.ScreenerRow) (float64, bool) { return row.ChangePct, true },
+	"volume":    func(row *models.ScreenerRow) (float64, bool) { return row.QuoteVolume, true },
+	"funding":   func(row *models.ScreenerRow) (float64, bool) { return optionalValue(row.FundingRate) },
+	"from_high": func(row *models.ScreenerRow) (float64, bool) { return optionalValue(row.FromHighPct) },
+	"from_low":  func(row *models.ScreenerRow) (float64, bool) { return optionalValue(row.FromLowPct) },
+	"oi_change": func(row *models.ScreenerRow) (float64, bool) { return optionalValue(row.OIChange1hPct) },
+}
+
+// ScreenerQuery 选币器查询条件
+type ScreenerQuery struct {
+	Exchange       string
+	SortBy         string // change, volume, funding, from_high, from_low, oi_change
+	Ascending      bool
+	MinQuoteVolume float64
+	SelectedOnly   bool
+}
+
+// IsScreenerSortField 检查排序字段是否支持
+func IsScreenerSortField(field string) bool {
+	_, ok := screenerSortFields[field]
+	return ok
+}
+
+// RunScreener 按条件对交易所所有已缓存币种计算排序指标并排序，缺少排序指标的币种排在最后
+func RunScreener(query ScreenerQuery) ([]*models.ScreenerRow, error) {
+	value, ok := screenerSortFields[query.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("不支持的排序字段: %s", query.SortBy)
+	}
+
+	store := ExchangeStore(query.Exchange)
+	coins, err := store.GetAllCoins()
+	if err != nil {
+		return nil, fmt.Errorf("获取币种列表失败: %v", err)
+	}
+	denylist, err := LoadDenylist()
+	if err != nil {
+		return nil, fmt.Errorf("获取禁止交易名单失败: %v", err)
+	}
+	coins = denylist.FilterCoins(coins)
+	selected, err := redis.GlobalRedisClient.GetSelectedCoinMarketIDs()
+	if err != nil {
+		return nil, fmt.Errorf("获取选中币种失败: %v", err)
+	}
+	selectedSet := make(map[string]bool, len(selected))
+	for _, marketID := range selected {
+		selectedSet[marketID] = true
+	}
+
+	rows := make([]*models.ScreenerRow, 0, len(coins))
+	marketIDs := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		if coin.Status != "" && coin.Status != "active" {
+			continue
+		}
+		quoteVolume, _ := strconv.ParseFloat(coin.QuoteVolume, 64)
+		if quoteVolume < query.MinQuoteVolume {
+			continue
+		}
+		if query.SelectedOnly && !selectedSet[coin.MarketID] {
+			continue
+		}
+
+		row := &models.ScreenerRow{
+			Symbol:      coin.MarketID,
+			QuoteVolume: quoteVolume,
+			IsSelected:  selectedSet[coin.MarketID],
+		}
+		row.Price, _ = strconv.ParseFloat(coin.Price, 64)
+		row.ChangePct, _ = strconv.ParseFloat(coin.PriceChangePercent, 64)
+		row.High, _ = strconv.ParseFloat(coin.HighPrice, 64)
+		row.Low, _ = strconv.ParseFloat(coin.LowPrice, 64)
+		rows = append(rows, row)
+		marketIDs = append(marketIDs, coin.MarketID)
+	}
+
+	markPrices, err := store.GetMarkPrices(marketIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if markPrice, ok := markPrices[row.Symbol]; ok {
+			fundingRate := markPrice.FundingRate
+			row.FundingRate = &fundingRate
+			if markPrice.LastPrice > 0 {
+				row.Price = markPrice.LastPrice
+			}
+		}
+		if row.Price > 0 && row.High > 0 {
+			fromHigh := (row.High - row.Price) / row.High * 100
+			row.FromHighPct = &fromHigh
+		}
+		if row.Price > 0 && row.Low > 0 {
+			fromLow := (row.Price - row.Low) / row.Low * 100
+			row.FromLowPct = &fromLow
+		}
+		if row.IsSelected {
+			row.OIChange1hPct = openInterestChange1h(store, row.Symbol)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		vi, oki := value(rows[i])
+		vj, okj := value(rows[j])
+		if oki != okj {
+			return oki
+		}
+		if query.Ascending {
+			return vi < vj
+		}
+		return vi > vj
+	})
+	return rows, nil
+}
+
+// openInterestChange1h 由保存的持仓量时间序列计算1小时变化率，没有足够历史时返回nil
+func openInterestChange1h(store *redis.Client, symbol string) *float64 {
+	interval := config.GlobalConfig.OpenInterestInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	points, err := store.GetOpenInterestPoints(symbol, time.Now().Add(-time.Hour-2*interval).UnixMilli())
+	if err != nil || len(points) == 0 {
+		return nil
+	}
+	change, ok := openInterestChange(points, points[len(points)-1], time.Hour, interval)
+	if !ok {
+		return nil
+	}
+	return &change
+}
+
+// optionalValue 读取可选指标
+func optionalValue(value *float64) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	return *value, true
+}
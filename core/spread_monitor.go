@@ -0,0 +1,190 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+	"trading_assistant/pkg/websocket"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// AlertTypeSpread 价差触发告警类型
+const AlertTypeSpread = "spread"
+
+// SpreadSnapshot 价差监控的实时价差
+type SpreadSnapshot struct {
+	PriceA float64 `json:"price_a"`
+	PriceB float64 `json:"price_b"`
+	Value  float64 `json:"value"`
+}
+
+// ComputeSpread 根据两腿价格计算价差
+func ComputeSpread(mode string, hedgeRatio, priceA, priceB float64) (float64, error) {
+	if priceA <= 0 || priceB <= 0 {
+		return 0, fmt.Errorf("价格无效: A=%f, B=%f", priceA, priceB)
+	}
+
+	switch mode {
+	case models.SpreadModeRatio:
+		return priceA / priceB, nil
+	case models.SpreadModeDifference:
+		return priceA - hedgeRatio*priceB, nil
+	default:
+		return 0, fmt.Errorf("不支持的价差计算方式: %s", mode)
+	}
+}
+
+// GetSpreadSnapshot 读取两腿最新价格并计算价差
+func GetSpreadSnapshot(monitor *models.SpreadMonitor) (*SpreadSnapshot, error) {
+	store := ExchangeStore(monitor.Exchange)
+
+	markA, err := store.GetMarkPrice(monitor.LegA.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("获取 %s 价格失败: %w", monitor.LegA.Symbol, err)
+	}
+	markB, err := store.GetMarkPrice(monitor.LegB.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("获取 %s 价格失败: %w", monitor.LegB.Symbol, err)
+	}
+
+	snapshot := &SpreadSnapshot{
+		PriceA: spreadLegPrice(markA),
+		PriceB: spreadLegPrice(markB),
+	}
+	snapshot.Value, err = ComputeSpread(monitor.Mode, monitor.HedgeRatio, snapshot.PriceA, snapshot.PriceB)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// spreadLegPrice 单腿参考价格：优先买卖中间价，缺失时使用标记价格
+func spreadLegPrice(markPrice *types.WatchMarkPrice) float64 {
+	if markPrice.BidPrice > 0 && markPrice.AskPrice > 0 {
+		return (markPrice.BidPrice + markPrice.AskPrice) / 2
+	}
+	return markPrice.MarkPrice
+}
+
+// shouldTriggerSpread 判断价差是否达到目标
+func shouldTriggerSpread(condition string, value, target float64) bool {
+	switch condition {
+	case models.SpreadConditionAbove:
+		return value >= target
+	case models.SpreadConditionBelow:
+		return value <= target
+	default:
+		return false
+	}
+}
+
+// checkSpreadMonitors 检查所有监听中的价差监控
+func (pm *PriceMonitor) checkSpreadMonitors() {
+	monitors, err := redis.GlobalRedisClient.GetAllSpreadMonitors()
+	if err != nil {
+		logrus.Errorf("获取价差监控失败: %v", err)
+		return
+	}
+
+	for _, monitor := range monitors {
+		if !monitor.Enabled || monitor.Status != models.EstimateStatusListening {
+			continue
+		}
+
+		snapshot, err := GetSpreadSnapshot(monitor)
+		if err != nil {
+			logrus.Debugf("价差监控 %s 计算失败: %v", monitor.ID, err)
+			continue
+		}
+
+		if shouldTriggerSpread(monitor.Condition, snapshot.Value, monitor.TargetValue) {
+			logrus.Infof("价差目标触发: %s %s/%s 当前 %f, 目标 %s %f",
+				monitor.Name, monitor.LegA.Symbol, monitor.LegB.Symbol, snapshot.Value, monitor.Condition, monitor.TargetValue)
+			pm.triggerSpread(monitor, snapshot)
+		}
+	}
+}
+
+// triggerSpread 同时执行两腿价格预估
+func (pm *PriceMonitor) triggerSpread(monitor *models.SpreadMonitor, snapshot *SpreadSnapshot) {
+	detectedAt := time.Now()
+	legs := []struct {
+		leg   models.SpreadLeg
+		price float64
+	}{
+		{monitor.LegA, snapshot.PriceA},
+		{monitor.LegB, snapshot.PriceB},
+	}
+
+	estimates := make([]*models.PriceEstimate, len(legs))
+	var wg sync.WaitGroup
+	for i := range legs {
+		estimates[i] = newSpreadLegEstimate(monitor, legs[i].leg)
+
+		wg.Add(1)
+		go func(estimate *models.PriceEstimate, price float64) {
+			defer wg.Done()
+			pm.triggerEstimate(estimate, price, detectedAt)
+		}(estimates[i], legs[i].price)
+	}
+	wg.Wait()
+
+	now := time.Now()
+	monitor.Status = models.EstimateStatusTriggered
+	monitor.ErrorMessage = ""
+	monitor.TriggerValue = snapshot.Value
+	monitor.TriggeredAt = &now
+	monitor.UpdatedAt = now
+	monitor.EstimateIDs = monitor.EstimateIDs[:0]
+	for _, estimate := range estimates {
+		monitor.EstimateIDs = append(monitor.EstimateIDs, estimate.ID)
+		if estimate.Status == models.EstimateStatusFailed {
+			monitor.Status = models.EstimateStatusFailed
+			monitor.ErrorMessage = fmt.Sprintf("%s: %s", estimate.Symbol, estimate.ErrorMessage)
+		}
+	}
+
+	if err := redis.GlobalRedisClient.SetSpreadMonitor(monitor); err != nil {
+		logrus.Errorf("更新价差监控状态失败: %v", err)
+	}
+
+	if wsManager := websocket.GetGlobalWebSocketManager(); wsManager != nil {
+		wsManager.BroadcastAlert(AlertTypeSpread, monitor)
+	}
+	go utils.BroadcastSymbolEstimatesUpdate()
+}
+
+// newSpreadLegEstimate 根据单腿配置创建立即执行的价格预估
+func newSpreadLegEstimate(monitor *models.SpreadMonitor, leg models.SpreadLeg) *models.PriceEstimate {
+	tag := monitor.Tag
+	if tag == "" {
+		tag = "spread:" + monitor.ID
+	}
+
+	now := time.Now()
+	return &models.PriceEstimate{
+		ID:          uuid.New().String(),
+		Symbol:      leg.Symbol,
+		Exchange:    monitor.Exchange,
+		Side:        leg.Side,
+		ActionType:  leg.ActionType,
+		Percentage:  leg.Percentage,
+		Leverage:    leg.Leverage,
+		OrderType:   types.OrderTypeMarket,
+		MarginMode:  types.MarginModeCross,
+		TriggerType: models.TriggerTypeImmediate,
+		Tag:         tag,
+		StakeAmount: leg.StakeAmount,
+		Amount:      leg.Amount,
+		Status:      models.EstimateStatusListening,
+		Enabled:     true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
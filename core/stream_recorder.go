@@ -0,0 +1,332 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
+	"trading_assistant/pkg/exchanges/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// streamRecordExt 录制文件扩展名，每行一条价格更新
+const streamRecordExt = ".jsonl"
+
+// priceRing 单个交易对的价格环形缓冲区，写满后覆盖最早的记录
+type priceRing struct {
+	items []*types.WatchMarkPrice
+	next  int
+	full  bool
+}
+
+// push 追加一条记录
+func (r *priceRing) push(price *types.WatchMarkPrice) {
+	r.items[r.next] = price
+	r.next = (r.next + 1) % len(r.items)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot 按写入顺序返回缓冲区内的记录
+func (r *priceRing) snapshot() []*types.WatchMarkPrice {
+	if !r.full {
+		return append([]*types.WatchMarkPrice(nil), r.items[:r.next]...)
+	}
+	result := make([]*types.WatchMarkPrice, 0, len(r.items))
+	result = append(result, r.items[r.next:]...)
+	return append(result, r.items[:r.next]...)
+}
+
+// StreamRecorder 价格流录制器
+// 订阅进入价格流水线的价格更新，每个交易对在内存中保留最新的N条，定时写入 <目录>/<交易所>/<交易对>.jsonl，供回放模式重放
+// 所有实例都可以录制，不依赖主节点
+type StreamRecorder struct {
+	dir           string
+	maxPerSymbol  int
+	flushInterval time.Duration
+
+	mu          sync.Mutex
+	rings       map[string]map[string]*priceRing // 交易所 -> 交易对 -> 缓冲区
+	dirty       map[string]map[string]bool       // 上次写入后有新记录的交易对
+	unsubscribe func()
+	stopChan    chan struct{}
+	done        chan struct{}
+	running     bool
+}
+
+var GlobalStreamRecorder *StreamRecorder
+
+// InitStreamRecorder 初始化价格流录制器，未启用或处于回放模式时不创建
+func InitStreamRecorder() {
+	cfg := config.GlobalConfig
+	if !cfg.StreamRecordEnabled {
+		return
+	}
+	if cfg.StreamReplayDir != "" {
+		logrus.Warn("回放模式下不录制价格流")
+		return
+	}
+
+	maxPerSymbol := cfg.StreamRecordMaxPerSymbol
+	if maxPerSymbol <= 0 {
+		maxPerSymbol = 10000
+	}
+	flushInterval := cfg.StreamRecordFlushInterval
+	if flushInterval <= 0 {
+		flushInterval = 30 * time.Second
+	}
+	GlobalStreamRecorder = &StreamRecorder{
+		dir:           cfg.StreamRecordDir,
+		maxPerSymbol:  maxPerSymbol,
+		flushInterval: flushInterval,
+		rings:         make(map[string]map[string]*priceRing),
+		dirty:         make(map[string]map[string]bool),
+	}
+}
+
+// Start 加载已有的录制文件并开始录制，重启后继续追加到原有记录之后
+func (sr *StreamRecorder) Start() {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if sr.running {
+		return
+	}
+
+	recordings, err := loadStreamRecordings(sr.dir)
+	if err != nil {
+		logrus.Warnf("读取已有的价格流录制失败: %v", err)
+	}
+	for exchange, symbols := range recordings {
+		for symbol, prices := range symbols {
+			for _, price := range prices {
+				sr.ringFor(exchange, symbol).push(price)
+			}
+		}
+	}
+
+	sr.unsubscribe = eventbus.GetBus().Subscribe(eventbus.TopicMarkPrice, "stream_recorder", config.GlobalConfig.EventBusBuffer, sr.onMarkPrices)
+	sr.stopChan = make(chan struct{})
+	sr.done = make(chan struct{})
+	sr.running = true
+	go sr.loop(sr.stopChan, sr.done)
+	logrus.Infof("价格流录制已启动，目录: %s，每个交易对保留 %d 条", sr.dir, sr.maxPerSymbol)
+}
+
+// Stop 停止录制并把剩余记录写入磁盘
+func (sr *StreamRecorder) Stop() {
+	sr.mu.Lock()
+	if !sr.running {
+		sr.mu.Unlock()
+		return
+	}
+	sr.unsubscribe()
+	close(sr.stopChan)
+	done := sr.done
+	sr.running = false
+	sr.mu.Unlock()
+
+	<-done
+	logrus.Info("价格流录制已停止")
+}
+
+// loop 定时写入磁盘，停止时再写入一次
+func (sr *StreamRecorder) loop(stopChan, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(sr.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			sr.Flush()
+			return
+		case <-ticker.C:
+			sr.Flush()
+		}
+	}
+}
+
+// onMarkPrices 记录一轮价格更新
+func (sr *StreamRecorder) onMarkPrices(event *eventbus.Event) {
+	batch, ok := event.Payload.(*eventbus.MarkPriceBatch)
+	if !ok {
+		return
+	}
+	exchange := strings.ToLower(event.Exchange)
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	for symbol, price := range batch.Prices {
+		if price == nil {
+			continue
+		}
+		copied := *price
+		sr.ringFor(exchange, symbol).push(&copied)
+		if sr.dirty[exchange] == nil {
+			sr.dirty[exchange] = make(map[string]bool)
+		}
+		sr.dirty[exchange][symbol] = true
+	}
+}
+
+// ringFor 获取交易对的缓冲区，不存在时创建，调用方需持有锁
+func (sr *StreamRecorder) ringFor(exchange, symbol string) *priceRing {
+	symbols := sr.rings[exchange]
+	if symbols == nil {
+		symbols = make(map[string]*priceRing)
+		sr.rings[exchange] = symbols
+	}
+	ring := symbols[symbol]
+	if ring == nil {
+		ring = &priceRing{items: make([]*types.WatchMarkPrice, sr.maxPerSymbol)}
+		symbols[symbol] = ring
+	}
+	return ring
+}
+
+// Flush 把有新记录的交易对写入磁盘，整个文件先写临时文件再替换，避免回放读到写了一半的文件
+func (sr *StreamRecorder) Flush() {
+	sr.mu.Lock()
+	pending := make(map[string]map[string][]*types.WatchMarkPrice)
+	for exchange, symbols := range sr.dirty {
+		for symbol := range symbols {
+			if pending[exchange] == nil {
+				pending[exchange] = make(map[string][]*types.WatchMarkPrice)
+			}
+			pending[exchange][symbol] = sr.rings[exchange][symbol].snapshot()
+		}
+	}
+	sr.dirty = make(map[string]map[string]bool)
+	sr.mu.Unlock()
+
+	written := 0
+	for exchange, symbols := range pending {
+		for symbol, prices := range symbols {
+			if err := writeStreamRecording(sr.dir, exchange, symbol, prices); err != nil {
+				logrus.Errorf("写入 %s %s 价格流录制失败: %v", exchange, symbol, err)
+				continue
+			}
+			written++
+		}
+	}
+	if written > 0 {
+		logrus.Debugf("价格流录制已写入 %d 个交易对", written)
+	}
+}
+
+// streamRecordingPath 录制文件路径，交易对中的 / 和 : 替换为 _
+func streamRecordingPath(dir, exchange, symbol string) string {
+	name := strings.NewReplacer("/", "_", ":", "_").Replace(symbol)
+	return filepath.Join(dir, exchange, name+streamRecordExt)
+}
+
+// writeStreamRecording 写入单个交易对的录制文件
+func writeStreamRecording(dir, exchange, symbol string, prices []*types.WatchMarkPrice) error {
+	path := streamRecordingPath(dir, exchange, symbol)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".record-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for _, price := range prices {
+		if err := encoder.Encode(price); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// loadStreamRecordings 读取目录下所有交易所的录制文件，返回 交易所 -> 交易对 -> 按时间排序的记录
+// 交易对以记录中的 symbol 为准，目录不存在时返回空结果
+func loadStreamRecordings(dir string) (map[string]map[string][]*types.WatchMarkPrice, error) {
+	recordings := make(map[string]map[string][]*types.WatchMarkPrice)
+	exchanges, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return recordings, nil
+		}
+		return nil, err
+	}
+
+	for _, entry := range exchanges {
+		if !entry.IsDir() {
+			continue
+		}
+		exchange := strings.ToLower(entry.Name())
+		files, err := filepath.Glob(filepath.Join(dir, entry.Name(), "*"+streamRecordExt))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			prices, err := readStreamRecording(file)
+			if err != nil {
+				return nil, fmt.Errorf("读取 %s 失败: %v", file, err)
+			}
+			if len(prices) == 0 {
+				continue
+			}
+			if recordings[exchange] == nil {
+				recordings[exchange] = make(map[string][]*types.WatchMarkPrice)
+			}
+			recordings[exchange][prices[0].Symbol] = prices
+		}
+	}
+	return recordings, nil
+}
+
+// readStreamRecording 读取单个录制文件，跳过无法解析的行
+func readStreamRecording(path string) ([]*types.WatchMarkPrice, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var prices []*types.WatchMarkPrice
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var price types.WatchMarkPrice
+		if err := json.Unmarshal(line, &price); err != nil || price.Symbol == "" {
+			logrus.Debugf("跳过无法解析的录制记录 %s: %s", path, line)
+			continue
+		}
+		prices = append(prices, &price)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(prices, func(i, j int) bool { return prices[i].TimeStamp < prices[j].TimeStamp })
+	return prices, nil
+}
@@ -0,0 +1,209 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
+	"trading_assistant/pkg/exchanges/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// replayBatchWindow 同一交易所在该时间窗口内录制的价格合并为一轮价格更新回放
+// 录制时一轮获取中每个交易对的时间戳相差几毫秒，合并后与实时获取的批次一致
+const replayBatchWindow = 500 * time.Millisecond
+
+// replayBatch 回放的一轮价格更新
+type replayBatch struct {
+	exchange  string
+	timestamp int64 // 录制时的时间戳（毫秒）
+	prices    []*types.WatchMarkPrice
+}
+
+// StreamReplayer 价格流回放器
+// 按录制时间顺序把录制的价格写入缓存并发布价格事件，替代实时价格获取，走与实时价格相同的完整流水线
+// 回放时价格时间戳改为当前时间，避免被价格监控视为过期；回放期间不应连接实盘下单，需要开启 DRY_RUN
+type StreamReplayer struct {
+	dir   string
+	speed float64 // 回放速度倍数，0为不等待
+	loop  bool
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	running bool
+	rounds  int // 已完成的回放轮数
+}
+
+var GlobalStreamReplayer *StreamReplayer
+
+// InitStreamReplayer 初始化价格流回放器，未配置回放目录时不创建
+func InitStreamReplayer() {
+	cfg := config.GlobalConfig
+	if cfg.StreamReplayDir == "" {
+		return
+	}
+	speed := cfg.StreamReplaySpeed
+	if speed < 0 {
+		speed = 0
+	}
+	GlobalStreamReplayer = &StreamReplayer{
+		dir:   cfg.StreamReplayDir,
+		speed: speed,
+		loop:  cfg.StreamReplayLoop,
+	}
+}
+
+// Start 读取录制文件并开始回放
+func (sr *StreamReplayer) Start() error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if sr.running {
+		return nil
+	}
+
+	recordings, err := loadStreamRecordings(sr.dir)
+	if err != nil {
+		return fmt.Errorf("读取价格流录制失败: %v", err)
+	}
+	batches := buildReplayBatches(recordings)
+	if len(batches) == 0 {
+		return fmt.Errorf("%s 下没有可回放的价格流录制", sr.dir)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sr.cancel = cancel
+	sr.running = true
+	go sr.run(ctx, batches)
+
+	span := time.Duration(batches[len(batches)-1].timestamp-batches[0].timestamp) * time.Millisecond
+	logrus.Warnf("价格流回放模式已启动，目录: %s，%d 轮价格更新，录制时长 %v，速度 %gx", sr.dir, len(batches), span, sr.speed)
+	return nil
+}
+
+// Stop 停止回放
+func (sr *StreamReplayer) Stop() {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if !sr.running {
+		return
+	}
+	sr.cancel()
+	sr.running = false
+	logrus.Info("价格流回放已停止")
+}
+
+// run 按录制间隔依次回放，开启循环时回放结束后从头开始
+func (sr *StreamReplayer) run(ctx context.Context, batches []*replayBatch) {
+	for {
+		for i, batch := range batches {
+			if i > 0 && sr.speed > 0 {
+				wait := time.Duration(float64(batch.timestamp-batches[i-1].timestamp)/sr.speed) * time.Millisecond
+				if wait > 0 {
+					timer := time.NewTimer(wait)
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return
+					case <-timer.C:
+					}
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			sr.publish(batch)
+		}
+
+		sr.mu.Lock()
+		sr.rounds++
+		rounds := sr.rounds
+		sr.mu.Unlock()
+		if !sr.loop {
+			logrus.Warnf("价格流回放已结束，共回放 %d 轮价格更新", len(batches))
+			return
+		}
+		logrus.Infof("价格流回放第 %d 轮结束，从头开始", rounds)
+	}
+}
+
+// publish 把一轮价格写入缓存并发布价格事件，时间戳改为当前时间
+func (sr *StreamReplayer) publish(batch *replayBatch) {
+	store := ExchangeStore(batch.exchange)
+	now := time.Now().UnixMilli()
+
+	prices := make(map[string]*types.WatchMarkPrice, len(batch.prices))
+	for _, recorded := range batch.prices {
+		price := *recorded
+		price.TimeStamp = now
+		if err := store.SetMarkPrice(&price); err != nil {
+			logrus.Errorf("保存 %s 回放价格失败: %v", price.Symbol, err)
+			continue
+		}
+		prices[price.Symbol] = &price
+	}
+	if len(prices) == 0 {
+		return
+	}
+
+	eventbus.GetBus().Publish(eventbus.TopicMarkPrice, batch.exchange, &eventbus.MarkPriceBatch{
+		Primary: store.GetNamespace() == "",
+		Prices:  prices,
+	})
+}
+
+// buildReplayBatches 按时间合并所有交易对的录制，同一交易所在 replayBatchWindow 内的价格合并为一轮
+// 同一交易对在窗口内再次出现时开始新的一轮
+func buildReplayBatches(recordings map[string]map[string][]*types.WatchMarkPrice) []*replayBatch {
+	type frame struct {
+		exchange string
+		price    *types.WatchMarkPrice
+	}
+	var frames []frame
+	for exchange, symbols := range recordings {
+		for _, prices := range symbols {
+			for _, price := range prices {
+				frames = append(frames, frame{exchange: exchange, price: price})
+			}
+		}
+	}
+	sort.SliceStable(frames, func(i, j int) bool {
+		if frames[i].price.TimeStamp != frames[j].price.TimeStamp {
+			return frames[i].price.TimeStamp < frames[j].price.TimeStamp
+		}
+		if frames[i].exchange != frames[j].exchange {
+			return frames[i].exchange < frames[j].exchange
+		}
+		return frames[i].price.Symbol < frames[j].price.Symbol
+	})
+
+	var batches []*replayBatch
+	open := make(map[string]*replayBatch) // 交易所 -> 正在合并的一轮
+	for _, f := range frames {
+		batch := open[f.exchange]
+		if batch == nil || f.price.TimeStamp-batch.timestamp > replayBatchWindow.Milliseconds() || batchHasSymbol(batch, f.price.Symbol) {
+			batch = &replayBatch{exchange: f.exchange, timestamp: f.price.TimeStamp}
+			open[f.exchange] = batch
+			batches = append(batches, batch)
+		}
+		batch.prices = append(batch.prices, f.price)
+	}
+	return batches
+}
+
+// batchHasSymbol 一轮中是否已包含该交易对
+func batchHasSymbol(batch *replayBatch, symbol string) bool {
+	for _, price := range batch.prices {
+		if price.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
+	"trading_assistant/pkg/exchanges/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// symbolStatsTTL 统计缓存有效期，K线停止更新后统计自动过期
+const symbolStatsTTL = 10 * time.Minute
+
+// symbolStatsWindow 已实现波动率的统计窗口
+const symbolStatsWindow = 24 * time.Hour
+
+// SymbolStatsService 币种滚动统计服务
+// 订阅已保存的K线，按币种计算时段VWAP、24小时已实现波动率和ATR并缓存到Redis
+type SymbolStatsService struct {
+	timeframe    string
+	atrTimeframe string
+	atrPeriod    int
+}
+
+var (
+	GlobalSymbolStats *SymbolStatsService
+	symbolStatsOnce   sync.Once
+)
+
+// GetSymbolStatsService 获取全局滚动统计服务
+func GetSymbolStatsService() *SymbolStatsService {
+	symbolStatsOnce.Do(func() {
+		cfg := config.GlobalConfig
+		period := cfg.StatsATRPeriod
+		if period <= 0 {
+			period = 14
+		}
+		GlobalSymbolStats = &SymbolStatsService{
+			timeframe:    cfg.StatsTimeframe,
+			atrTimeframe: cfg.StatsATRTimeframe,
+			atrPeriod:    period,
+		}
+	})
+	return GlobalSymbolStats
+}
+
+// OnKlines K线保存后重新计算对应币种的统计，只处理统计使用的周期
+func (ss *SymbolStatsService) OnKlines(event *eventbus.Event) {
+	batch, ok := event.Payload.(*eventbus.KlineBatch)
+	if !ok || (batch.Timeframe != ss.timeframe && batch.Timeframe != ss.atrTimeframe) {
+		return
+	}
+
+	if _, err := ss.Refresh(event.Exchange, batch.Symbol); err != nil {
+		logrus.Debugf("计算 %s 滚动统计失败: %v", batch.Symbol, err)
+	}
+}
+
+// Get 获取币种统计，缓存不存在时根据已保存的K线即时计算
+func (ss *SymbolStatsService) Get(exchange, symbol string) (*models.SymbolStats, error) {
+	stats, err := ExchangeStore(exchange).GetSymbolStats(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if stats != nil {
+		return stats, nil
+	}
+	return ss.Refresh(exchange, symbol)
+}
+
+// Refresh 根据已保存的K线计算币种统计并写入缓存
+func (ss *SymbolStatsService) Refresh(exchange, symbol string) (*models.SymbolStats, error) {
+	store := ExchangeStore(exchange)
+	now := time.Now()
+
+	step, err := timeframeDuration(ss.timeframe)
+	if err != nil {
+		return nil, err
+	}
+	klines, err := store.GetKlinesBetween(symbol, ss.timeframe, now.Add(-symbolStatsWindow).UnixMilli(), now.Add(step).UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	if len(klines) == 0 {
+		return nil, fmt.Errorf("没有 %s 的 %s K线数据", symbol, ss.timeframe)
+	}
+
+	last := klines[len(klines)-1]
+	stats := &models.SymbolStats{
+		Symbol:       symbol,
+		Exchange:     strings.ToLower(exchange),
+		Timeframe:    ss.timeframe,
+		SessionStart: time.UnixMilli(last.Timestamp).UTC().Truncate(24 * time.Hour),
+		ATRTimeframe: ss.atrTimeframe,
+		ATRPeriod:    ss.atrPeriod,
+		LastClose:    last.Close,
+		UpdatedAt:    now,
+	}
+	stats.VWAP, stats.SessionVolume = sessionVWAP(klines, stats.SessionStart.UnixMilli())
+	stats.RealizedVolatilityPct, stats.VolatilitySamples = realizedVolatility(klines)
+
+	atrKlines, err := store.GetStoredKlines(symbol, ss.atrTimeframe, 0, int64(ss.atrPeriod+1))
+	if err != nil {
+		return nil, err
+	}
+	if atr, ok := averageTrueRange(atrKlines, ss.atrPeriod); ok {
+		stats.ATR = atr
+		if last.Close > 0 {
+			stats.ATRPct = atr / last.Close * 100
+		}
+	}
+
+	if err := store.SetSymbolStats(stats, symbolStatsTTL); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// sessionVWAP 计算时段开始后K线的成交量加权均价，没有成交量时返回 0
+func sessionVWAP(klines []*types.Kline, sessionStart int64) (float64, float64) {
+	var turnover, volume float64
+	for _, kline := range klines {
+		if kline.Timestamp < sessionStart || kline.Volume <= 0 {
+			continue
+		}
+		typical := (kline.High + kline.Low + kline.Close) / 3
+		turnover += typical * kline.Volume
+		volume += kline.Volume
+	}
+	if volume == 0 {
+		return 0, 0
+	}
+	return turnover / volume, volume
+}
+
+// realizedVolatility 相邻收盘价对数收益率平方和的平方根(%)，与短时波动保护的计算方式一致
+func realizedVolatility(klines []*types.Kline) (float64, int) {
+	var sum float64
+	samples := 0
+	for i := 1; i < len(klines); i++ {
+		prev, curr := klines[i-1].Close, klines[i].Close
+		if prev <= 0 || curr <= 0 {
+			continue
+		}
+		r := math.Log(curr / prev)
+		sum += r * r
+		samples++
+	}
+	return math.Sqrt(sum) * 100, samples
+}
+
+// averageTrueRange 最近 period 根K线真实波幅的简单平均，K线不足 period+1 根时返回 false
+func averageTrueRange(klines []*types.Kline, period int) (float64, bool) {
+	if period <= 0 || len(klines) < period+1 {
+		return 0, false
+	}
+
+	var sum float64
+	for i := len(klines) - period; i < len(klines); i++ {
+		prevClose := klines[i-1].Close
+		trueRange := math.Max(klines[i].High-klines[i].Low, math.Max(math.Abs(klines[i].High-prevClose), math.Abs(klines[i].Low-prevClose)))
+		sum += trueRange
+	}
+	return sum / float64(period), true
+}
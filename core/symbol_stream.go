@@ -0,0 +1,75 @@
+package core
+
+import (
+	"context"
+	"slices"
+	"time"
+	"trading_assistant/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// symbolStreamRefreshInterval 检查选中币种变化的间隔，变化时重新订阅
+const symbolStreamRefreshInterval = time.Minute
+
+// symbolStream 按币种订阅的交易所推送，监控的币种变化时取消旧订阅并重新订阅
+type symbolStream struct {
+	name      string   // 日志中的推送名称
+	symbols   []string // 固定订阅的币种，为空时订阅所有选中币种
+	subscribe func(ctx context.Context, symbols []string) error
+}
+
+// run 维持订阅直到 ctx 取消，subscribe 返回后推送在 ctx 取消前持续处理
+func (s *symbolStream) run(ctx context.Context) {
+	ticker := time.NewTicker(symbolStreamRefreshInterval)
+	defer ticker.Stop()
+
+	var subscribed []string
+	cancelSubscription := func() {}
+	defer func() { cancelSubscription() }()
+
+	for {
+		symbols, err := s.watchedSymbols()
+		if err != nil {
+			logrus.Errorf("获取%s币种失败: %v", s.name, err)
+		} else if !slices.Equal(symbols, subscribed) {
+			cancelSubscription()
+			cancelSubscription = func() {}
+			subscribed = symbols
+
+			if len(symbols) > 0 {
+				subCtx, cancel := context.WithCancel(ctx)
+				if err := s.subscribe(subCtx, symbols); err != nil {
+					cancel()
+					logrus.Errorf("订阅%s失败: %v", s.name, err)
+					subscribed = nil
+				} else {
+					cancelSubscription = cancel
+					logrus.Infof("已订阅 %d 个币种的%s", len(symbols), s.name)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchedSymbols 获取需要订阅的币种（已排序）
+func (s *symbolStream) watchedSymbols() ([]string, error) {
+	symbols := s.symbols
+	if len(symbols) == 0 {
+		selected, err := redis.GlobalRedisClient.GetSelectedCoinMarketIDs()
+		if err != nil {
+			return nil, err
+		}
+		symbols = selected
+	}
+
+	sorted := slices.Clone(symbols)
+	slices.Sort(sorted)
+	return slices.Compact(sorted), nil
+}
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/websocket"
+)
+
+// 系统状态，按严重程度递增
+const (
+	SystemStatusOK       = "ok"
+	SystemStatusDegraded = "degraded" // 部分功能异常，核心流程仍可运行
+	SystemStatusDown     = "down"     // 核心依赖不可用，无法正常监控和下单
+)
+
+const (
+	systemStatusRedisTimeout  = 2 * time.Second
+	systemStatusExchangeStale = 5 * time.Minute // 超过该时长没有成功的REST请求视为异常
+	systemStatusLoopStale     = 5 * time.Second // 主节点价格监控超过该时长没有运行视为停止
+	systemStatusLoopLag       = time.Second     // 价格监控循环延迟超过该值视为处理不过来
+)
+
+// processStartedAt 进程启动时间
+var processStartedAt = time.Now()
+
+// SystemComponentStatus 子系统健康状态
+type SystemComponentStatus struct {
+	Name    string      `json:"name"`
+	Status  string      `json:"status"`
+	Message string      `json:"message,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// SystemStatus 系统整体健康状态，整体状态取各子系统中最严重的状态
+type SystemStatus struct {
+	Status        string                   `json:"status"`
+	Instance      string                   `json:"instance"`
+	Leader        bool                     `json:"leader"` // 高可用模式下只有主节点运行价格监控和推送订阅
+	Goroutines    int                      `json:"goroutines"`
+	UptimeSeconds int64                    `json:"uptime_seconds"`
+	Components    []*SystemComponentStatus `json:"components"`
+	CheckedAt     time.Time                `json:"checked_at"`
+}
+
+// GetSystemStatus 汇总Redis、交易所REST和推送连接、Freqtrade、WebSocket Hub和价格监控循环的健康状态
+func GetSystemStatus(ctx context.Context) *SystemStatus {
+	leader := GlobalLeaderElector == nil || GlobalLeaderElector.IsLeader()
+	status := &SystemStatus{
+		Status:        SystemStatusOK,
+		Instance:      instanceID,
+		Leader:        leader,
+		Goroutines:    runtime.NumGoroutine(),
+		UptimeSeconds: int64(time.Since(processStartedAt).Seconds()),
+		CheckedAt:     time.Now(),
+	}
+
+	status.Components = append(status.Components, redisStatus(ctx))
+	status.Components = append(status.Components, exchangeStatuses()...)
+	status.Components = append(status.Components, freqtradeStatus(), hubStatus(), priceMonitorStatus(leader))
+
+	for _, component := range status.Components {
+		if systemStatusSeverity(component.Status) > systemStatusSeverity(status.Status) {
+			status.Status = component.Status
+		}
+	}
+	return status
+}
+
+// systemStatusSeverity 状态的严重程度
+func systemStatusSeverity(status string) int {
+	switch status {
+	case SystemStatusDown:
+		return 2
+	case SystemStatusDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// redisStatus Redis连接状态，预估、价格和持仓都保存在Redis中，不可用时整体不可用
+func redisStatus(ctx context.Context) *SystemComponentStatus {
+	component := &SystemComponentStatus{Name: "redis", Status: SystemStatusOK}
+	if redis.GlobalRedisClient == nil {
+		component.Status = SystemStatusDown
+		component.Message = "Redis未初始化"
+		return component
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, systemStatusRedisTimeout)
+	defer cancel()
+	latency, err := redis.GlobalRedisClient.Ping(ctx)
+	if err != nil {
+		component.Status = SystemStatusDown
+		component.Message = err.Error()
+	}
+	component.Details = map[string]interface{}{"latency_ms": latency.Milliseconds()}
+	return component
+}
+
+// exchangeStatuses 每个运行中交易所的REST请求和推送连接状态
+func exchangeStatuses() []*SystemComponentStatus {
+	exchangeClientsMutex.RLock()
+	ids := make([]string, 0, len(exchangeClients))
+	for id := range exchangeClients {
+		ids = append(ids, id)
+	}
+	exchangeClientsMutex.RUnlock()
+	sort.Strings(ids)
+
+	streams := make(map[string][]exchanges.StreamConnectionStats)
+	for _, conn := range exchanges.GetStreamConnectionStats() {
+		streams[conn.Exchange] = append(streams[conn.Exchange], conn)
+	}
+
+	components := make([]*SystemComponentStatus, 0, len(ids))
+	for _, id := range ids {
+		component := &SystemComponentStatus{Name: "exchange:" + id, Status: SystemStatusOK}
+		score, _ := GetDataQualityTracker().GetScore(id)
+		switch {
+		case score == nil || score.LastSuccessAt == 0:
+			component.Status = SystemStatusDegraded
+			component.Message = "尚无成功的REST请求"
+		case time.Since(time.UnixMilli(score.LastSuccessAt)) > systemStatusExchangeStale:
+			component.Status = SystemStatusDegraded
+			component.Message = fmt.Sprintf("超过 %v 没有成功的REST请求", systemStatusExchangeStale)
+		}
+
+		disconnected := 0
+		for _, conn := range streams[id] {
+			if !conn.Connected {
+				disconnected++
+			}
+		}
+		if disconnected > 0 {
+			component.Status = SystemStatusDegraded
+			component.Message = joinStatusMessage(component.Message, fmt.Sprintf("%d 个推送连接断开", disconnected))
+		}
+
+		component.Details = map[string]interface{}{
+			"rest":    score,
+			"streams": streams[id],
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// freqtradeStatus Freqtrade实例状态，有实例熔断时部分预估无法下单
+func freqtradeStatus() *SystemComponentStatus {
+	component := &SystemComponentStatus{Name: "freqtrade", Status: SystemStatusOK}
+	if GlobalFreqtradeHealth == nil {
+		return component
+	}
+
+	statuses := GlobalFreqtradeHealth.GetStatuses()
+	open := 0
+	for _, status := range statuses {
+		if status.CircuitOpen {
+			open++
+		}
+	}
+	if open > 0 {
+		component.Status = SystemStatusDegraded
+		component.Message = fmt.Sprintf("%d/%d 个实例已熔断", open, len(statuses))
+	}
+	component.Details = statuses
+	return component
+}
+
+// hubStatus 前端WebSocket Hub客户端数
+func hubStatus() *SystemComponentStatus {
+	component := &SystemComponentStatus{Name: "hub", Status: SystemStatusOK}
+	if wsManager := websocket.GetGlobalWebSocketManager(); wsManager != nil {
+		component.Details = wsManager.GetHub().GetStats()
+	}
+	return component
+}
+
+// priceMonitorStatus 价格监控循环状态，从节点不运行价格监控
+func priceMonitorStatus(leader bool) *SystemComponentStatus {
+	component := &SystemComponentStatus{Name: "price_monitor", Status: SystemStatusOK}
+	if !leader {
+		component.Message = "从节点不运行价格监控"
+		return component
+	}
+	if GlobalPriceMonitor == nil || !GlobalPriceMonitor.IsRunning() {
+		component.Status = SystemStatusDown
+		component.Message = "价格监控未运行"
+		return component
+	}
+
+	lastTickAt, lag := GlobalPriceMonitor.GetLoopStatus()
+	details := map[string]interface{}{"loop_lag_ms": lag.Milliseconds()}
+	if !lastTickAt.IsZero() {
+		details["last_tick_at"] = lastTickAt
+	}
+	component.Details = details
+
+	switch {
+	case lastTickAt.IsZero():
+	case time.Since(lastTickAt) > systemStatusLoopStale:
+		component.Status = SystemStatusDown
+		component.Message = fmt.Sprintf("价格监控已 %v 没有运行", time.Since(lastTickAt).Truncate(time.Second))
+	case lag > systemStatusLoopLag:
+		component.Status = SystemStatusDegraded
+		component.Message = fmt.Sprintf("价格监控循环延迟 %v", lag.Truncate(time.Millisecond))
+	}
+	return component
+}
+
+// joinStatusMessage 合并多条状态说明
+func joinStatusMessage(message, extra string) string {
+	if message == "" {
+		return extra
+	}
+	return message + "；" + extra
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/exchange_factory"
+	"trading_assistant/pkg/exchanges/types"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/websocket"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AlertTypeLargeTrade 大额成交告警类型
+const AlertTypeLargeTrade = "large_trade"
+
+// TradeTapeMonitor 逐笔成交监控
+// 订阅选中币种的成交推送，单笔成交额超过阈值时推送告警到前端和通知渠道；只在主节点运行，避免重复告警
+type TradeTapeMonitor struct {
+	exchangeID string
+	streamer   exchange_factory.TradeStreamer
+	threshold  float64
+	symbols    []string // 固定监控的币种，为空时监控所有选中币种
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	running bool
+}
+
+var GlobalTradeTape *TradeTapeMonitor
+
+// InitTradeTape 初始化逐笔成交监控，未启用或交易所不支持成交推送时不创建
+func InitTradeTape(exchangeClient exchange_factory.ExchangeInterface) {
+	cfg := config.GlobalConfig
+	if !cfg.TradeTapeEnabled {
+		return
+	}
+
+	streamer, err := exchange_factory.AsTradeStreamer(exchangeClient)
+	if err != nil {
+		logrus.Warnf("%v，跳过逐笔成交监控", err)
+		return
+	}
+
+	symbols := make([]string, 0, len(cfg.LargeTradeSymbols))
+	for _, symbol := range cfg.LargeTradeSymbols {
+		symbols = append(symbols, ResolveMarketID(exchangeClient.GetID(), symbol))
+	}
+	GlobalTradeTape = &TradeTapeMonitor{
+		exchangeID: strings.ToLower(exchangeClient.GetID()),
+		streamer:   streamer,
+		threshold:  cfg.LargeTradeNotional,
+		symbols:    symbols,
+	}
+}
+
+// Start 启动成交监控
+func (tm *TradeTapeMonitor) Start() {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if tm.running {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	tm.cancel = cancel
+	tm.running = true
+
+	stream := &symbolStream{
+		name:    "成交推送",
+		symbols: tm.symbols,
+		subscribe: func(ctx context.Context, symbols []string) error {
+			trades, err := tm.streamer.WatchTrades(ctx, symbols)
+			if err != nil {
+				return err
+			}
+			go tm.consume(trades)
+			return nil
+		},
+	}
+	go stream.run(ctx)
+	logrus.Infof("逐笔成交监控已启动，大额成交阈值: %.0f USDT", tm.threshold)
+}
+
+// Stop 停止成交监控并断开成交推送
+func (tm *TradeTapeMonitor) Stop() {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if !tm.running {
+		return
+	}
+	tm.cancel()
+	tm.running = false
+	logrus.Info("逐笔成交监控已停止")
+}
+
+// consume 处理成交推送直到订阅取消
+func (tm *TradeTapeMonitor) consume(trades <-chan *types.Trade) {
+	for trade := range trades {
+		notional := trade.Cost
+		if notional <= 0 {
+			notional = trade.Price * trade.Amount
+		}
+		if notional < tm.threshold {
+			continue
+		}
+
+		tm.alert(&models.LargeTradeAlert{
+			Symbol:    trade.Symbol,
+			Exchange:  tm.exchangeID,
+			TradeID:   trade.ID,
+			Side:      trade.Side,
+			Price:     trade.Price,
+			Amount:    trade.Amount,
+			Notional:  notional,
+			Threshold: tm.threshold,
+			Timestamp: trade.Timestamp,
+		})
+	}
+}
+
+// alert 推送大额成交告警到前端和通知渠道
+func (tm *TradeTapeMonitor) alert(alert *models.LargeTradeAlert) {
+	direction := "买入"
+	if alert.Side == "sell" {
+		direction = "卖出"
+	}
+	message := fmt.Sprintf("%s 主动%s %.0f USDT，价格 %v，数量 %v", alert.Symbol, direction, alert.Notional, alert.Price, alert.Amount)
+	logrus.Infof("大额成交: %s", message)
+
+	if wsManager := websocket.GetGlobalWebSocketManager(); wsManager != nil {
+		wsManager.BroadcastAlert(AlertTypeLargeTrade, alert)
+	}
+	notify.Send(notify.EventLargeTrade, i18n.T("notify.large_trade.title"), message, map[string]interface{}{
+		"symbol":   alert.Symbol,
+		"exchange": alert.Exchange,
+		"side":     alert.Side,
+		"price":    alert.Price,
+		"amount":   alert.Amount,
+		"notional": alert.Notional,
+	})
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"math"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// priceSample 滚动窗口中的一个价格样本
+type priceSample struct {
+	at    int64 // 毫秒
+	price float64
+}
+
+// VolatilityTracker 按币种保存短时间的价格滚动窗口，用于计算已实现波动率
+type VolatilityTracker struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	samples   map[string][]priceSample // exchange:symbol -> 按时间排序的样本
+	deferrals map[string]time.Time     // estimateID -> 推迟评估到的时间
+}
+
+var (
+	GlobalVolatilityTracker *VolatilityTracker
+	volatilityTrackerOnce   sync.Once
+)
+
+// GetVolatilityTracker 获取全局波动率跟踪器
+func GetVolatilityTracker() *VolatilityTracker {
+	volatilityTrackerOnce.Do(func() {
+		GlobalVolatilityTracker = &VolatilityTracker{
+			window:    config.GlobalConfig.VolatilityWindow,
+			samples:   make(map[string][]priceSample),
+			deferrals: make(map[string]time.Time),
+		}
+	})
+	return GlobalVolatilityTracker
+}
+
+// OnMarkPrices 记录一轮价格获取的结果
+func (vt *VolatilityTracker) OnMarkPrices(event *eventbus.Event) {
+	batch, ok := event.Payload.(*eventbus.MarkPriceBatch)
+	if !ok {
+		return
+	}
+
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	for symbol, price := range batch.Prices {
+		vt.record(event.Exchange+":"+symbol, price)
+	}
+}
+
+// record 追加样本并移除窗口外的样本，时间戳未变化的价格不重复记录
+func (vt *VolatilityTracker) record(key string, markPrice *types.WatchMarkPrice) {
+	price := markPrice.LastPrice
+	if price <= 0 {
+		price = markPrice.MarkPrice
+	}
+	if price <= 0 {
+		return
+	}
+
+	samples := vt.samples[key]
+	if n := len(samples); n > 0 && samples[n-1].at >= markPrice.TimeStamp {
+		return
+	}
+	samples = append(samples, priceSample{at: markPrice.TimeStamp, price: price})
+
+	cutoff := markPrice.TimeStamp - vt.window.Milliseconds()
+	start := 0
+	for start < len(samples) && samples[start].at < cutoff {
+		start++
+	}
+	vt.samples[key] = append(samples[:0], samples[start:]...)
+}
+
+// RealizedVolatility 窗口内的已实现波动率(%)：对数收益率平方和的平方根，样本不足时返回 false
+func (vt *VolatilityTracker) RealizedVolatility(exchange, symbol string) (float64, bool) {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+
+	samples := vt.samples[exchange+":"+symbol]
+	if len(samples) < 2 {
+		return 0, false
+	}
+
+	var sum float64
+	for i := 1; i < len(samples); i++ {
+		r := math.Log(samples[i].price / samples[i-1].price)
+		sum += r * r
+	}
+	return math.Sqrt(sum) * 100, true
+}
+
+// DeferredUntil 获取预估被推迟评估到的时间，未推迟时返回零值
+func (vt *VolatilityTracker) DeferredUntil(estimateID string, now time.Time) time.Time {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+
+	until, ok := vt.deferrals[estimateID]
+	if ok && !now.Before(until) {
+		delete(vt.deferrals, estimateID)
+		return time.Time{}
+	}
+	return until
+}
+
+// Defer 推迟预估的评估，同时清理已到期的推迟记录（对应预估可能已删除）
+func (vt *VolatilityTracker) Defer(estimateID string, until time.Time) {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+
+	now := time.Now()
+	for id, deferredUntil := range vt.deferrals {
+		if !now.Before(deferredUntil) {
+			delete(vt.deferrals, id)
+		}
+	}
+	vt.deferrals[estimateID] = until
+}
+
+// spreadPct 买卖价差占中间价的比例(%)，买卖价无效时返回 false
+func spreadPct(markPrice *types.WatchMarkPrice) (float64, bool) {
+	bid, ask := markPrice.BidPrice, markPrice.AskPrice
+	if bid <= 0 || ask <= 0 || ask < bid {
+		return 0, false
+	}
+	mid := (bid + ask) / 2
+	return (ask - bid) / mid * 100, true
+}
+
+// checkVolatilityGuard 检查预估的波动保护，推迟期内或超过阈值时记录跳过并返回 false
+func (pm *PriceMonitor) checkVolatilityGuard(estimate *models.PriceEstimate, markPrice *types.WatchMarkPrice, now time.Time) bool {
+	guard := estimate.VolatilityGuard
+	if !guard.IsEnabled() {
+		return true
+	}
+
+	tracker := GetVolatilityTracker()
+	if until := tracker.DeferredUntil(estimate.ID, now); !until.IsZero() {
+		pm.recordSkip(estimate, models.SkipReasonVolatility, "波动保护推迟评估至 %s", until.Format(time.TimeOnly))
+		return false
+	}
+
+	var reason string
+	if guard.MaxVolatilityPct > 0 {
+		if volatility, ok := tracker.RealizedVolatility(estimate.Exchange, markPrice.Symbol); ok && volatility > guard.MaxVolatilityPct {
+			reason = "波动率"
+			pm.recordSkip(estimate, models.SkipReasonVolatility, "已实现波动率 %.4f%% 超过阈值 %.4f%%", volatility, guard.MaxVolatilityPct)
+		}
+	}
+	if reason == "" && guard.MaxSpreadPct > 0 {
+		if spread, ok := spreadPct(markPrice); ok && spread > guard.MaxSpreadPct {
+			reason = "价差"
+			pm.recordSkip(estimate, models.SkipReasonVolatility, "买卖价差 %.4f%% 超过阈值 %.4f%%", spread, guard.MaxSpreadPct)
+		}
+	}
+	if reason == "" {
+		return true
+	}
+
+	deferFor := time.Duration(guard.DeferSeconds) * time.Second
+	if deferFor <= 0 {
+		deferFor = config.GlobalConfig.VolatilityGuardDefer
+	}
+	tracker.Defer(estimate.ID, now.Add(deferFor))
+	return false
+}
@@ -0,0 +1,304 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/redis"
+	"trading_assistant/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 白名单同步模式
+const (
+	WhitelistModeMirror = "mirror" // 与选中币种保持一致
+	WhitelistModeMerge  = "merge"  // 只补充缺少的交易对，保留Freqtrade已有的交易对
+	WhitelistModeVolume = "volume" // 按24小时成交额取前N个交易对
+)
+
+// WhitelistSyncResult 白名单同步结果，Added/Removed 为所有实例的汇总
+type WhitelistSyncResult struct {
+	Mode     string                `json:"mode"`     // 同步模式
+	DryRun   bool                  `json:"dry_run"`  // 是否只计算差异
+	Pairs    []string              `json:"pairs"`    // 目标交易对（/pairlist 返回的列表）
+	Added    []string              `json:"added"`    // Freqtrade白名单中缺少的交易对
+	Removed  []string              `json:"removed"`  // Freqtrade白名单中多出的交易对
+	Reloaded bool                  `json:"reloaded"` // 是否触发了Freqtrade重新加载
+	Bots     []*BotWhitelistResult `json:"bots"`     // 各实例的同步结果
+	SyncedAt time.Time             `json:"synced_at"`
+}
+
+// BotWhitelistResult 单个Freqtrade实例的白名单同步结果
+type BotWhitelistResult struct {
+	Bot      string   `json:"bot"`
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+	Reloaded bool     `json:"reloaded"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// WhitelistSyncer Freqtrade交易对白名单同步器
+// Freqtrade通过 RemotePairList 拉取 /pairlist，目标列表变化后调用 reload_config 使其立即生效
+type WhitelistSyncer struct {
+	bots     *freqtrade.Registry
+	mode     string
+	topN     int
+	dryRun   bool
+	interval time.Duration
+	stopChan chan struct{}
+
+	mu         sync.Mutex
+	lastResult *WhitelistSyncResult
+}
+
+var GlobalWhitelistSyncer *WhitelistSyncer
+
+// InitWhitelistSyncer 初始化白名单同步器
+func InitWhitelistSyncer(bots *freqtrade.Registry) {
+	mode := strings.ToLower(strings.TrimSpace(config.GlobalConfig.FreqtradeWhitelistMode))
+	if mode != WhitelistModeMerge && mode != WhitelistModeVolume {
+		mode = WhitelistModeMirror
+	}
+	GlobalWhitelistSyncer = &WhitelistSyncer{
+		bots:     bots,
+		mode:     mode,
+		topN:     config.GlobalConfig.FreqtradeWhitelistTopN,
+		dryRun:   config.GlobalConfig.FreqtradeWhitelistDryRun,
+		interval: config.GlobalConfig.FreqtradeWhitelistSyncInterval,
+	}
+}
+
+// Start 启动定时同步
+func (ws *WhitelistSyncer) Start() {
+	if ws.interval <= 0 {
+		return
+	}
+	if ws.stopChan != nil {
+		return
+	}
+
+	ws.stopChan = make(chan struct{})
+	go ws.loop(ws.stopChan)
+	logrus.Infof("Freqtrade白名单定时同步已启动，模式: %s, 间隔: %v, dry-run: %v", ws.mode, ws.interval, ws.dryRun)
+}
+
+// Stop 停止定时同步
+func (ws *WhitelistSyncer) Stop() {
+	if ws.stopChan == nil {
+		return
+	}
+	close(ws.stopChan)
+	ws.stopChan = nil
+}
+
+// loop 定时同步白名单
+func (ws *WhitelistSyncer) loop(stopChan chan struct{}) {
+	ticker := time.NewTicker(ws.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			if _, err := ws.Sync(false); err != nil {
+				logrus.Errorf("定时同步Freqtrade白名单失败: %v", err)
+			}
+		}
+	}
+}
+
+// SelectedPairs 获取选中币种对应的Freqtrade交易对，不包含禁止交易的币种
+func SelectedPairs() ([]string, error) {
+	marketIDs, err := redis.GlobalRedisClient.GetSelectedCoinMarketIDs()
+	if err != nil {
+		return nil, fmt.Errorf("获取选中币种失败: %w", err)
+	}
+	denylist, err := LoadDenylist()
+	if err != nil {
+		return nil, fmt.Errorf("获取禁止交易名单失败: %w", err)
+	}
+	return marketIDsToPairs(denylist.FilterMarketIDs(marketIDs)), nil
+}
+
+// TopVolumePairs 按24小时成交额取前N个交易对，不包含禁止交易的币种
+func TopVolumePairs(topN int) ([]string, error) {
+	coins, err := redis.GlobalRedisClient.GetAllCoins()
+	if err != nil {
+		return nil, fmt.Errorf("获取币种列表失败: %w", err)
+	}
+	denylist, err := LoadDenylist()
+	if err != nil {
+		return nil, fmt.Errorf("获取禁止交易名单失败: %w", err)
+	}
+
+	candidates := rankCoinsByRule(denylist.FilterCoins(coins), CoinSelectionRule{TopN: topN})
+	marketIDs := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		marketIDs = append(marketIDs, candidate.MarketID)
+	}
+	return marketIDsToPairs(marketIDs), nil
+}
+
+// WhitelistPairs 获取 /pairlist 返回给Freqtrade的目标交易对
+func WhitelistPairs() ([]string, error) {
+	if ws := GlobalWhitelistSyncer; ws != nil && ws.mode == WhitelistModeVolume {
+		return TopVolumePairs(ws.topN)
+	}
+	return SelectedPairs()
+}
+
+// marketIDsToPairs 将MarketID转换为排序后的Freqtrade交易对
+func marketIDsToPairs(marketIDs []string) []string {
+	marketType := config.GlobalConfig.MarketType
+	mapper := SymbolMapper("")
+	pairs := make([]string, 0, len(marketIDs))
+	for _, marketID := range marketIDs {
+		if pair := mapper.Unified(marketID); pair != "" {
+			pairs = append(pairs, pair)
+			continue
+		}
+		pairs = append(pairs, utils.ConvertMarketIDToSymbol(marketID, marketType))
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// Sync 对比目标交易对和各Freqtrade实例的白名单，有差异的实例触发重新加载
+// dryRun 或配置了 FREQTRADE_WHITELIST_DRY_RUN 时只记录差异，单个实例失败记录在该实例的结果中，所有实例都失败时返回错误
+func (ws *WhitelistSyncer) Sync(dryRun bool) (*WhitelistSyncResult, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	dryRun = dryRun || ws.dryRun
+	pairs, err := WhitelistPairs()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WhitelistSyncResult{
+		Mode:     ws.mode,
+		DryRun:   dryRun,
+		Pairs:    pairs,
+		Added:    make([]string, 0),
+		Removed:  make([]string, 0),
+		Bots:     make([]*BotWhitelistResult, 0),
+		SyncedAt: time.Now(),
+	}
+
+	var lastErr error
+	added := make(map[string]bool)
+	removed := make(map[string]bool)
+	for _, bot := range ws.bots.All() {
+		botResult, err := ws.syncBot(bot, pairs, dryRun)
+		if err != nil {
+			lastErr = err
+			botResult.Error = err.Error()
+			logrus.Errorf("Freqtrade实例 %s 白名单同步失败: %v", bot.Name, err)
+		}
+		result.Bots = append(result.Bots, botResult)
+		result.Reloaded = result.Reloaded || botResult.Reloaded
+		for _, pair := range botResult.Added {
+			if !added[pair] {
+				added[pair] = true
+				result.Added = append(result.Added, pair)
+			}
+		}
+		for _, pair := range botResult.Removed {
+			if !removed[pair] {
+				removed[pair] = true
+				result.Removed = append(result.Removed, pair)
+			}
+		}
+	}
+
+	if lastErr != nil && allFailed(result.Bots) {
+		return nil, lastErr
+	}
+
+	// 只保留实际应用的结果，dry-run 结果直接返回给调用方
+	if !dryRun {
+		ws.lastResult = result
+	}
+	return result, nil
+}
+
+// syncBot 同步单个实例的白名单，merge 模式不移除Freqtrade已有的交易对
+func (ws *WhitelistSyncer) syncBot(bot *freqtrade.Controller, pairs []string, dryRun bool) (*BotWhitelistResult, error) {
+	result := &BotWhitelistResult{Bot: bot.Name, Removed: make([]string, 0)}
+
+	current, err := bot.GetWhitelist(context.Background())
+	if err != nil {
+		return result, fmt.Errorf("获取Freqtrade白名单失败: %w", err)
+	}
+	result.Added = diffPairs(pairs, current)
+	if ws.mode != WhitelistModeMerge {
+		result.Removed = diffPairs(current, pairs)
+	}
+
+	if len(result.Added) == 0 && len(result.Removed) == 0 {
+		return result, nil
+	}
+	if dryRun {
+		logrus.Infof("[dry-run] Freqtrade实例 %s 白名单差异 (%s): 新增 %v, 移除 %v", bot.Name, ws.mode, result.Added, result.Removed)
+		return result, nil
+	}
+
+	if err := bot.ReloadConfig(context.Background()); err != nil {
+		return result, fmt.Errorf("Freqtrade重新加载失败: %w", err)
+	}
+	result.Reloaded = true
+	logrus.Infof("Freqtrade实例 %s 白名单已同步 (%s): 新增 %v, 移除 %v", bot.Name, ws.mode, result.Added, result.Removed)
+	return result, nil
+}
+
+// allFailed 是否所有实例都同步失败
+func allFailed(results []*BotWhitelistResult) bool {
+	for _, result := range results {
+		if result.Error == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// SyncAsync 启用自动同步时在后台同步白名单
+func (ws *WhitelistSyncer) SyncAsync() {
+	if ws == nil || !config.GlobalConfig.FreqtradeWhitelistSync {
+		return
+	}
+	go func() {
+		if _, err := ws.Sync(false); err != nil {
+			logrus.Errorf("同步Freqtrade白名单失败: %v", err)
+		}
+	}()
+}
+
+// GetLastResult 获取最近一次实际应用的同步结果
+func (ws *WhitelistSyncer) GetLastResult() *WhitelistSyncResult {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.lastResult
+}
+
+// diffPairs 返回在a中但不在b中的交易对
+func diffPairs(a, b []string) []string {
+	exists := make(map[string]bool, len(b))
+	for _, pair := range b {
+		exists[pair] = true
+	}
+
+	diff := make([]string, 0)
+	for _, pair := range a {
+		if !exists[pair] {
+			diff = append(diff, pair)
+		}
+	}
+	return diff
+}
@@ -1,13 +1,22 @@
 package main
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
+	"trading_assistant/controllers"
 	"trading_assistant/core"
+	"trading_assistant/pkg/auth"
 	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/eventbus"
 	"trading_assistant/pkg/exchange_factory"
+	"trading_assistant/pkg/exchanges/types"
 	"trading_assistant/pkg/freqtrade"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/lifecycle"
+	"trading_assistant/pkg/logging"
+	"trading_assistant/pkg/notify"
 	"trading_assistant/pkg/redis"
 	"trading_assistant/servers"
 
@@ -22,6 +31,34 @@ func main() {
 	// 加载配置
 	config.LoadConfig()
 
+	// 设置日志格式、按模块的日志级别和日志文件
+	if err := logging.Init(logging.Config{
+		Level:        config.GlobalConfig.LogLevel,
+		Format:       config.GlobalConfig.LogFormat,
+		ModuleLevels: config.GlobalConfig.LogModuleLevels,
+		File:         config.GlobalConfig.LogFile,
+		MaxSizeMB:    config.GlobalConfig.LogFileMaxSizeMB,
+		MaxBackups:   config.GlobalConfig.LogFileMaxBackups,
+	}); err != nil {
+		logrus.Fatalf("日志配置错误: %v", err)
+	}
+	if config.GlobalConfig.DryRun {
+		logrus.Warn("模拟交易模式已启用，触发的价格预估将模拟成交，不会向 Freqtrade 下单")
+	}
+	if config.GlobalConfig.StreamReplayDir != "" && !config.GlobalConfig.DryRun {
+		logrus.Fatal("价格流回放模式必须同时开启 DRY_RUN，避免按回放价格向 Freqtrade 下单")
+	}
+
+	// 设置输出语言和显示时区，需在启动其他组件前完成
+	if err := i18n.Init(config.GlobalConfig.DisplayLocale, config.GlobalConfig.DisplayTimezone); err != nil {
+		logrus.Fatalf("DISPLAY_LOCALE/DISPLAY_TIMEZONE 配置错误: %v", err)
+	}
+
+	// 加载接口密钥
+	if err := auth.InitAPIKeys(config.GlobalConfig.APIKeys); err != nil {
+		logrus.Fatalf("API_KEYS 配置错误: %v", err)
+	}
+
 	// 初始化Redis
 	if err := redis.InitRedis(); err != nil {
 		logrus.Fatalf("Redis init fail: %v", err)
@@ -34,6 +71,9 @@ func main() {
 		logrus.Fatalf("交易所客户端初始化失败: %v", err)
 	}
 	logrus.Infof("%s 客户端已初始化", exchangeClient.GetName())
+	if exchangeClient.IsTestnet() {
+		logrus.Warnf("%s 使用测试网或模拟交易账户，实际下单由 Freqtrade 完成，请确认 Freqtrade 也连接到对应的测试环境", exchangeClient.GetName())
+	}
 
 	// 初始化市场数据管理器并同步数据
 	marketManager := core.NewMarketManager(exchangeClient)
@@ -41,6 +81,28 @@ func main() {
 		logrus.Errorf("同步市场数据和价格数据失败: %v", err)
 	}
 
+	// 初始化同时运行的其他交易所，市场和价格数据按交易所隔离
+	secondaryExchanges, err := factory.CreateSecondaryFromConfig()
+	if err != nil {
+		logrus.Fatalf("其他交易所客户端初始化失败: %v", err)
+	}
+	var secondaryManagers []*core.MarketManager
+	for _, exchange := range secondaryExchanges {
+		manager := core.NewMarketManager(exchange)
+		if err := manager.SyncMarketAndPriceData(); err != nil {
+			logrus.Errorf("同步 %s 市场数据和价格数据失败: %v", exchange.GetName(), err)
+		}
+		secondaryManagers = append(secondaryManagers, manager)
+		logrus.Infof("%s 客户端已初始化", exchange.GetName())
+	}
+
+	// 启动自动选币
+	if config.GlobalConfig.AutoSelectEnabled {
+		if err := marketManager.RunAutoSelection(); err != nil {
+			logrus.Errorf("自动选币失败: %v", err)
+		}
+	}
+
 	// 初始化 Freqtrade 控制器
 	if config.GlobalConfig.FreqtradeBaseURL == "" || config.GlobalConfig.FreqtradeUsername == "" || config.GlobalConfig.FreqtradePassword == "" {
 		logrus.Fatal("Freqtrade 已启用但配置不完整，请检查 FREQTRADE_BASE_URL, FREQTRADE_USERNAME, FREQTRADE_PASSWORD")
@@ -52,12 +114,21 @@ func main() {
 		config.GlobalConfig.FreqtradePassword,
 		redis.GlobalRedisClient,
 	)
+	freqtradeController.SetRequestOptions(freqtrade.RequestOptions{
+		Timeout:          config.GlobalConfig.FreqtradeRequestTimeout,
+		EndpointTimeouts: freqtrade.ParseEndpointTimeouts(config.GlobalConfig.FreqtradeEndpointTimeouts),
+		MaxRetries:       config.GlobalConfig.FreqtradeMaxRetries,
+		RetryBackoff:     config.GlobalConfig.FreqtradeRetryBackoff,
+	})
+
+	// 初始化通知分发器
+	notify.InitDispatcher()
 
 	// 创建消息通道用于 freqtrade 通知
 	freqtradeMessageChan := make(chan string, 100)
 	go func() {
-		for range freqtradeMessageChan {
-			// Telegram通知已移除
+		for message := range freqtradeMessageChan {
+			notify.Send(notify.EventFreqtrade, "", message, nil)
 		}
 	}()
 
@@ -67,54 +138,306 @@ func main() {
 	}
 	logrus.Info("Freqtrade 控制器已初始化")
 
-	// 初始化核心组件
-	core.InitPriceMonitor(freqtradeController)
-
-	// 启动价格订阅
-	if err := marketManager.StartPriceSubscriptions(); err != nil {
-		logrus.Errorf("启动价格订阅失败: %v", err)
-	}
+	// 初始化其他 Freqtrade 实例
+	bots := initFreqtradeBots(freqtradeController, freqtradeMessageChan)
 
-	// 启动价格监控
-	core.GlobalPriceMonitor.Start()
+	// 初始化核心组件
+	core.InitPriceMonitor(bots)
+	core.InitWhitelistSyncer(bots)
+	core.InitFreqtradeHealthMonitor(bots)
+	core.InitRiskMonitor(freqtradeController)
+	core.InitPnLLedger(freqtradeController)
+	core.InitEquityTracker(bots)
+	core.InitHistoryArchiver()
+	core.InitMQTTBridge()
+	core.InitTradeTape(exchangeClient)
+	core.InitLiquidationMonitor(exchangeClient)
+	core.InitConnectionMonitor()
+	core.InitMarketSync(append([]*core.MarketManager{marketManager}, secondaryManagers...)...)
+	core.InitCoinSelectionPolicy(marketManager.GetAutoSelector())
+	core.InitOpenInterestTracker(append([]exchange_factory.ExchangeInterface{exchangeClient}, secondaryExchanges...)...)
+	core.InitStreamRecorder()
+	core.InitStreamReplayer()
+	core.InitLeaderElector()
 
 	// 创建HTTP服务器
 	server := servers.NewHTTPServer(exchangeClient, marketManager, freqtradeController)
-	go func() {
-		server.Start()
-	}()
+
+	// 按依赖关系启动各组件
+	lifecycleManager := lifecycle.NewManager()
+	registerComponents(lifecycleManager, server, marketManager, secondaryManagers, freqtradeController)
+	if err := lifecycleManager.StartAll(config.GlobalConfig.ShutdownTimeout); err != nil {
+		logrus.Fatalf("启动失败: %v", err)
+	}
 
 	logrus.Info("交易助手启动完成!")
 
 	// 优雅关闭
-	gracefulShutdown(server, exchangeClient, marketManager, freqtradeController)
+	gracefulShutdown(lifecycleManager)
 }
 
-// gracefulShutdown 优雅关闭
-func gracefulShutdown(server *servers.HTTPServer, exchangeClient exchange_factory.ExchangeInterface, marketManager *core.MarketManager, freqtradeController *freqtrade.Controller) {
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+// initFreqtradeBots 初始化 FREQTRADE_BOTS 配置的其他实例，并与主实例一起注册到实例注册表
+// 未在URL中配置凭据的实例使用主实例的用户名和密码，多个实例共用同一个通知通道
+func initFreqtradeBots(primary *freqtrade.Controller, messageChan chan string) *freqtrade.Registry {
+	bots := freqtrade.InitRegistry(primary)
 
-	logrus.Info("正在关闭交易助手...")
+	botConfigs, err := freqtrade.ParseBotConfigs(config.GlobalConfig.FreqtradeBots)
+	if err != nil {
+		logrus.Fatalf("Freqtrade 实例配置错误: %v", err)
+	}
+	for _, bc := range botConfigs {
+		username, password := bc.Username, bc.Password
+		if username == "" {
+			username, password = config.GlobalConfig.FreqtradeUsername, config.GlobalConfig.FreqtradePassword
+		}
+		bot := freqtrade.NewController(bc.BaseURL, username, password, redis.GlobalRedisClient)
+		bot.SetRequestOptions(freqtrade.RequestOptions{
+			Timeout:          config.GlobalConfig.FreqtradeRequestTimeout,
+			EndpointTimeouts: freqtrade.ParseEndpointTimeouts(config.GlobalConfig.FreqtradeEndpointTimeouts),
+			MaxRetries:       config.GlobalConfig.FreqtradeMaxRetries,
+			RetryBackoff:     config.GlobalConfig.FreqtradeRetryBackoff,
+		})
+		if err := bot.Init(messageChan); err != nil {
+			logrus.Fatalf("Freqtrade 实例 %s 初始化失败: %v", bc.Name, err)
+		}
+		bots.Add(bc.Name, bot)
+		logrus.Infof("Freqtrade 实例 %s 已初始化: %s", bc.Name, bc.BaseURL)
+	}
 
-	// 停止HTTP服务器 (当前实现没有优雅关闭，直接退出)
-	logrus.Info("HTTP服务器将随程序退出关闭")
+	if name := config.GlobalConfig.FreqtradeLongBot; name != "" {
+		if err := bots.SetSideBot(types.PositionSideLong, name); err != nil {
+			logrus.Fatalf("FREQTRADE_LONG_BOT 配置错误: %v", err)
+		}
+	}
+	if name := config.GlobalConfig.FreqtradeShortBot; name != "" {
+		if err := bots.SetSideBot(types.PositionSideShort, name); err != nil {
+			logrus.Fatalf("FREQTRADE_SHORT_BOT 配置错误: %v", err)
+		}
+	}
+	return bots
+}
+
+// registerComponents 注册需要启动和关闭的组件
+// 关闭顺序与启动相反：HTTP服务器（含WebSocket）-> 监控 -> 主节点选举 -> 行情订阅 / Freqtrade -> 事件总线 -> 通知
+// 价格监控、风险监控和盈亏统计只在主节点运行，未启用高可用模式时本实例始终是主节点
+func registerComponents(manager *lifecycle.Manager, server *servers.HTTPServer, marketManager *core.MarketManager, secondaryManagers []*core.MarketManager, freqtradeController *freqtrade.Controller) {
+	components := []lifecycle.Component{
+		{
+			Name: "notify",
+			Stop: func(ctx context.Context) error {
+				// 发送剩余通知
+				if notify.GlobalDispatcher != nil {
+					notify.GlobalDispatcher.Stop()
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "event_bus",
+			DependsOn: []string{"notify"},
+			Start: func() error {
+				core.RegisterEventConsumers()
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				// 等待订阅者处理完已入队的事件
+				eventbus.GetBus().Close()
+				return nil
+			},
+		},
+		{
+			Name:      "freqtrade",
+			DependsOn: []string{"event_bus"},
+			Start: func() error {
+				// 交易对账只在主节点运行，见 freqtrade_reconciler
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				freqtrade.GlobalRegistry.Stop()
+				return nil
+			},
+		},
+		{
+			Name:      "market_data",
+			DependsOn: []string{"event_bus"},
+			Start: func() error {
+				// 回放模式下由录制的价格流替代实时价格、订单簿和K线
+				if core.GlobalStreamReplayer != nil {
+					return core.GlobalStreamReplayer.Start()
+				}
+
+				// 启动价格订阅
+				if err := marketManager.StartPriceSubscriptions(); err != nil {
+					logrus.Errorf("启动价格订阅失败: %v", err)
+				}
+				for _, manager := range secondaryManagers {
+					if err := manager.StartPriceSubscriptions(); err != nil {
+						logrus.Errorf("启动 %s 价格订阅失败: %v", manager.GetExchangeID(), err)
+					}
+				}
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				if core.GlobalStreamReplayer != nil {
+					core.GlobalStreamReplayer.Stop()
+					return nil
+				}
+				marketManager.StopPriceSubscriptions()
+				for _, manager := range secondaryManagers {
+					manager.StopPriceSubscriptions()
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "leader_election",
+			DependsOn: []string{"market_data", "freqtrade"},
+			Start: func() error {
+				core.GlobalLeaderElector.Start()
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				// 释放租约，其他实例可以立即接管
+				core.GlobalLeaderElector.Stop()
+				return nil
+			},
+		},
+		leaderTask("price_monitor", core.GlobalPriceMonitor.Start, core.GlobalPriceMonitor.Stop),
+		leaderTask("freqtrade_health", core.GlobalFreqtradeHealth.Start, core.GlobalFreqtradeHealth.Stop),
+		leaderTask("whitelist_sync", core.GlobalWhitelistSyncer.Start, core.GlobalWhitelistSyncer.Stop),
+		leaderTask("risk_monitor", core.GlobalRiskMonitor.Start, core.GlobalRiskMonitor.Stop),
+		leaderTask("pnl_ledger", core.GlobalPnLLedger.Start, core.GlobalPnLLedger.Stop),
+		leaderTask("equity_tracker", core.GlobalEquityTracker.Start, core.GlobalEquityTracker.Stop),
+		leaderTask("history_archiver", core.GlobalHistoryArchiver.Start, core.GlobalHistoryArchiver.Stop),
+		// 交易对账只在主节点运行，避免多个实例重复发送差异通知
+		leaderTask("freqtrade_reconciler", func() {
+			freqtradeController.StartReconciler(config.GlobalConfig.FreqtradeReconcileInterval)
+		}, freqtradeController.StopReconciler),
+		{
+			Name:      "http_server",
+			DependsOn: []string{"price_monitor", "risk_monitor"},
+			Start: func() error {
+				go server.Start()
+				return nil
+			},
+			// 停止接收新请求并等待进行中的请求完成，同时关闭WebSocket连接
+			Stop: server.Shutdown,
+		},
+	}
+
+	// gRPC服务，与HTTP接口共用价格预估、行情和持仓数据
+	if config.GlobalConfig.GRPCEnabled {
+		grpcServer := servers.NewGRPCServer(&controllers.PriceController{})
+		components = append(components, lifecycle.Component{
+			Name:      "grpc_server",
+			DependsOn: []string{"price_monitor", "risk_monitor"},
+			Start:     grpcServer.Start,
+			Stop:      grpcServer.Shutdown,
+		})
+	}
+
+	// MQTT桥接，只在主节点发布，避免多个实例重复发布
+	if core.GlobalMQTTBridge != nil {
+		components = append(components, leaderTask("mqtt_bridge", core.GlobalMQTTBridge.Start, core.GlobalMQTTBridge.Stop))
+	}
 
-	// 停止 Freqtrade 控制器
-	if freqtradeController != nil {
-		freqtradeController.Stop()
+	// 价格流录制，所有实例都可以录制，停止时写入剩余记录
+	if core.GlobalStreamRecorder != nil {
+		components = append(components, lifecycle.Component{
+			Name:      "stream_recorder",
+			DependsOn: []string{"event_bus"},
+			Start: func() error {
+				core.GlobalStreamRecorder.Start()
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				core.GlobalStreamRecorder.Stop()
+				return nil
+			},
+		})
 	}
 
-	// 停止价格订阅
-	if marketManager != nil {
-		marketManager.StopPriceSubscriptions()
+	// 逐笔成交监控，只在主节点订阅，避免重复告警
+	if core.GlobalTradeTape != nil {
+		components = append(components, leaderTask("trade_tape", core.GlobalTradeTape.Start, core.GlobalTradeTape.Stop))
 	}
 
-	// 停止核心组件
-	if core.GlobalPriceMonitor != nil {
-		core.GlobalPriceMonitor.Stop()
+	// 强平监控，只在主节点订阅和汇总，避免重复累加
+	if core.GlobalLiquidationMonitor != nil {
+		components = append(components, leaderTask("liquidation_monitor", core.GlobalLiquidationMonitor.Start, core.GlobalLiquidationMonitor.Stop))
 	}
 
+	// 推送连接延迟监控，逐笔成交和强平推送只在主节点连接
+	if core.GlobalConnectionMonitor != nil {
+		components = append(components, leaderTask("connection_monitor", core.GlobalConnectionMonitor.Start, core.GlobalConnectionMonitor.Stop))
+	}
+
+	// 市场数据定时同步，只在主节点执行，避免重复发送上新/下架通知
+	if core.GlobalMarketSync != nil {
+		components = append(components, leaderTask("market_sync", core.GlobalMarketSync.Start, core.GlobalMarketSync.Stop))
+	}
+
+	// 自动选币策略，只在主节点调整选中币种，避免重复通知
+	if core.GlobalCoinSelectionPolicy != nil {
+		components = append(components, leaderTask("coin_selection_policy", core.GlobalCoinSelectionPolicy.Start, core.GlobalCoinSelectionPolicy.Stop))
+	}
+
+	// 持仓量跟踪，只在主节点拉取，避免重复采样和告警
+	if core.GlobalOpenInterestTracker != nil {
+		components = append(components, leaderTask("open_interest", core.GlobalOpenInterestTracker.Start, core.GlobalOpenInterestTracker.Stop))
+	}
+
+	// Telegram指令机器人，只在主节点轮询，同一个Token被多个实例轮询时Telegram返回409且指令随机落到某个实例
+	if config.GlobalConfig.TelegramBotEnabled {
+		users, err := controllers.ParseTelegramUsers(config.GlobalConfig.TelegramUsers, config.GlobalConfig.TelegramChatID)
+		if err != nil {
+			logrus.Fatalf("Telegram 授权用户配置错误: %v", err)
+		}
+		if config.GlobalConfig.TelegramBotToken == "" || len(users) == 0 {
+			logrus.Fatal("Telegram 指令机器人已启用但配置不完整，请检查 TELEGRAM_BOT_TOKEN, TELEGRAM_CHAT_ID, TELEGRAM_USERS")
+		}
+		bot := controllers.NewTelegramBot(config.GlobalConfig.TelegramBotToken, users, &controllers.PriceController{}, freqtradeController)
+		components = append(components, leaderTask("telegram_bot", bot.Start, func() {
+			ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.ShutdownTimeout)
+			defer cancel()
+			if err := bot.Stop(ctx); err != nil {
+				logrus.Warnf("停止Telegram指令机器人超时: %v", err)
+			}
+		}))
+	}
+
+	for _, component := range components {
+		if err := manager.Register(component); err != nil {
+			logrus.Fatalf("注册组件失败: %v", err)
+		}
+	}
+}
+
+// leaderTask 仅在主节点运行的组件，由主节点选举在成为主节点时启动、降级时停止
+func leaderTask(name string, start, stop func()) lifecycle.Component {
+	return lifecycle.Component{
+		Name:      name,
+		DependsOn: []string{"leader_election"},
+		Start: func() error {
+			core.GlobalLeaderElector.AddTask(name, start, stop)
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			core.GlobalLeaderElector.RemoveTask(name)
+			return nil
+		},
+	}
+}
+
+// gracefulShutdown 优雅关闭
+func gracefulShutdown(manager *lifecycle.Manager) {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logrus.Info("正在关闭交易助手...")
+
+	manager.StopAll(config.GlobalConfig.ShutdownTimeout)
+
 	logrus.Info("交易助手已关闭")
 }
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// EstimateBacktest 价格预估在历史K线上的回放结果，不会保存或下单
+type EstimateBacktest struct {
+	Estimate    *PriceEstimate `json:"estimate"`     // 精度格式化后的预估，到期时间已换算到回放窗口
+	Timeframe   string         `json:"timeframe"`    // 回放使用的K线周期
+	From        time.Time      `json:"from"`         // 回放窗口开始时间
+	To          time.Time      `json:"to"`           // 回放窗口结束时间
+	Bars        int            `json:"bars"`         // 回放的K线数量
+	Triggered   bool           `json:"triggered"`    // 窗口内是否会触发
+	TriggeredAt *time.Time     `json:"triggered_at"` // 触发所在K线的开盘时间
+	FillPrice   float64        `json:"fill_price"`   // 假设的成交价格
+	Expired     bool           `json:"expired"`      // 到期前未触发
+	// 以下为触发后到窗口结束按持仓方向计算的价格变化(%)，止盈止损时表示平仓后行情的走向
+	ExitPrice       float64  `json:"exit_price"`        // 窗口结束时的收盘价
+	ReturnPct       float64  `json:"return_pct"`        // 成交价到窗口结束的收益率
+	LeveragedPct    float64  `json:"leveraged_pct"`     // 按杠杆放大的收益率
+	MaxFavorablePct float64  `json:"max_favorable_pct"` // 最大有利波动
+	MaxAdversePct   float64  `json:"max_adverse_pct"`   // 最大不利波动（负数）
+	Notes           []string `json:"notes"`             // 回放的简化假设
+}
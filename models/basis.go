@@ -0,0 +1,30 @@
+package models
+
+// BasisPoint 基差数据点（标记价格 - 指数价格）
+type BasisPoint struct {
+	Symbol     string  `json:"symbol"`
+	Exchange   string  `json:"exchange,omitempty"`
+	MarkPrice  float64 `json:"mark_price"`
+	IndexPrice float64 `json:"index_price"`
+	Basis      float64 `json:"basis"`      // 标记价格 - 指数价格
+	BasisRate  float64 `json:"basis_rate"` // 基差率 = 基差 / 指数价格
+	Timestamp  int64   `json:"timestamp"`  // 毫秒时间戳
+}
+
+// BasisAlert 基差异常告警
+type BasisAlert struct {
+	Symbol     string  `json:"symbol"`
+	Exchange   string  `json:"exchange,omitempty"`
+	Direction  string  `json:"direction"`  // premium(溢价), discount(折价)
+	BasisRate  float64 `json:"basis_rate"` // 触发时的基差率
+	Threshold  float64 `json:"threshold"`  // 告警阈值
+	MarkPrice  float64 `json:"mark_price"`
+	IndexPrice float64 `json:"index_price"`
+	Timestamp  int64   `json:"timestamp"`
+}
+
+// 基差告警方向常量
+const (
+	BasisDirectionPremium  = "premium"  // 溢价
+	BasisDirectionDiscount = "discount" // 折价
+)
@@ -9,19 +9,49 @@ const (
 	ActionTypeOpen       = "open"        // 开仓
 	ActionTypeAddition   = "addition"    // 加仓
 	ActionTypeTakeProfit = "take_profit" // 止盈
+	ActionTypeStopLoss   = "stop_loss"   // 止损
 )
 
+// IsExitAction 是否为减仓/平仓类操作（止盈、止损）
+func IsExitAction(actionType string) bool {
+	return actionType == ActionTypeTakeProfit || actionType == ActionTypeStopLoss
+}
+
 // 触发类型常量
 const (
 	TriggerTypeImmediate = "immediate" // 立即执行
 	TriggerTypeCondition = "condition" // 条件触发
 )
 
+// 触发价格来源常量，为空时按方向使用实时买卖价（做多用卖价，做空用买价）
+const (
+	PriceSourceMark  = "mark_price"  // 标记价格
+	PriceSourceLast  = "last_price"  // 最新成交价
+	PriceSourceIndex = "index_price" // 指数价格
+	PriceSourceBid   = "bid"         // 最优买价
+	PriceSourceAsk   = "ask"         // 最优卖价
+)
+
+// IsValidPriceSource 是否为有效的触发价格来源
+func IsValidPriceSource(source string) bool {
+	switch source {
+	case "", PriceSourceMark, PriceSourceLast, PriceSourceIndex, PriceSourceBid, PriceSourceAsk:
+		return true
+	default:
+		return false
+	}
+}
+
 // 价格预估状态常量
 const (
 	EstimateStatusListening = "listening" // 监听状态（默认状态）
 	EstimateStatusTriggered = "triggered" // 已触发成功
 	EstimateStatusFailed    = "failed"    // 触发失败
+	EstimateStatusExecuting = "executing" // 已取得执行权，正在下单
+
+	EstimateStatusVerified          = "verified"           // 执行后已确认持仓变化
+	EstimateStatusExecutionMismatch = "execution_mismatch" // 验证窗口内未观察到预期的持仓变化
+	EstimateStatusExpired           = "expired"            // 到期仍未触发
 )
 
 // 币种选择状态常量
@@ -51,11 +81,14 @@ type Coin struct {
 	QuantityPrecision int    `json:"quantity_precision"` // 数量小数位数（从StepSize自动计算）
 	MinQty            string `json:"min_qty"`            // 最小数量
 	MaxQty            string `json:"max_qty"`            // 最大数量
+	MinNotional       string `json:"min_notional"`       // 最小名义价值（数量×价格）
 
 	// ========== 实时价格信息 ==========
 	Price              string `json:"price"`                // 当前价格
 	PriceChange        string `json:"price_change"`         // 24小时价格变化金额
 	PriceChangePercent string `json:"price_change_percent"` // 24小时涨跌幅（如"-2.71"）
+	HighPrice          string `json:"high_price"`           // 24小时最高价
+	LowPrice           string `json:"low_price"`            // 24小时最低价
 
 	// ========== 交易量信息（核心指标）==========
 	Volume      string `json:"volume"`       // 24小时成交量（基础资产）
@@ -76,12 +109,24 @@ type CoinSelection struct {
 	UpdatedAt time.Time `json:"updated_at"` // 更新时间
 }
 
+// CoinSelectionChange 自动选币策略一次调整选中币种的记录
+type CoinSelectionChange struct {
+	TopN      int       `json:"top_n"`
+	Added     []string  `json:"added"`    // 新选中的币种
+	Removed   []string  `json:"removed"`  // 取消选中的币种
+	Kept      []string  `json:"kept"`     // 已跌出前N但有持仓或监听中预估而保留的币种
+	Selected  int       `json:"selected"` // 调整后的选中数量
+	DryRun    bool      `json:"dry_run"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
 // PriceEstimate 价格预估
 type PriceEstimate struct {
 	ID           string  `json:"id"`
 	Symbol       string  `json:"symbol"`        // MarketID (统一使用MarketID)
+	Exchange     string  `json:"exchange"`      // 价格来源交易所，为空时使用主交易所
 	Side         string  `json:"side"`          // 方向：long, short
-	ActionType   string  `json:"action_type"`   // 操作类型：open(开仓), addition(加仓), take_profit(止盈)
+	ActionType   string  `json:"action_type"`   // 操作类型：open(开仓), addition(加仓), take_profit(止盈), stop_loss(止损)
 	TargetPrice  float64 `json:"target_price"`  // 目标价格
 	Percentage   float64 `json:"percentage"`    // 仓位比例 (0-100)
 	Leverage     int     `json:"leverage"`      // 杠杆倍数
@@ -94,9 +139,24 @@ type PriceEstimate struct {
 	Amount       float64 `json:"amount"`        // 交易数量 (币的数量), 用于平仓时指定具体数量
 	ErrorMessage string  `json:"error_message"` // 失败原因（仅在status=failed时有值）
 	// CreatedBy字段已移除，改用ActionType明确标识操作类型
-	TriggerType string    `json:"trigger_type"` // 触发条件：immediate(立即执行), condition(条件触发)
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	TriggerType        string           `json:"trigger_type"`                   // 触发条件：immediate(立即执行), condition(条件触发)
+	PriceSource        string           `json:"price_source,omitempty"`         // 触发价格来源，为空时按方向使用实时买卖价
+	Condition          string           `json:"condition,omitempty"`            // 跨币种触发条件，如 BTCUSDT > 70000 AND ETHUSDT < 3000，设置后替代目标价格判断
+	ExpiresAt          *time.Time       `json:"expires_at,omitempty"`           // 到期时间，为空表示不过期
+	ExecutionLatencyMs int64            `json:"execution_latency_ms,omitempty"` // 从满足触发条件的tick到下单请求完成的耗时
+	OrderStrategy      *OrderStrategy   `json:"order_strategy,omitempty"`       // 拆单执行策略，为空时一次性下单
+	VolatilityGuard    *VolatilityGuard `json:"volatility_guard,omitempty"`     // 波动保护，为空时不检查
+	ExecutedSlices     int              `json:"executed_slices,omitempty"`      // 拆单已完成笔数
+	GridID             string           `json:"grid_id,omitempty"`              // 所属网格，同一网格的预估可以一起暂停或删除
+	BotName            string           `json:"bot_name,omitempty"`             // 下单的Freqtrade实例，为空时按方向路由或使用主实例
+	NotifyWithinPct    float64          `json:"notify_within_pct,omitempty"`    // 价格距目标价格在该百分比以内时提前提醒，每次进入范围提醒一次，0 表示不提醒
+	CreatedAt          time.Time        `json:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at"`
+}
+
+// IsExpired 预估是否已过期
+func (e *PriceEstimate) IsExpired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
 }
 
 type PriceData struct {
@@ -139,6 +199,17 @@ type Position struct {
 	UpdatedAt         time.Time `json:"updated_at"`
 }
 
+// AccountSnapshot 账户概览，由持仓缓存、持仓资金费和模拟账户汇总
+type AccountSnapshot struct {
+	Positions     int           `json:"positions"` // 持仓数量
+	Notional      float64       `json:"notional"`
+	InitialMargin float64       `json:"initial_margin"`
+	UnrealizedPnl float64       `json:"unrealized_pnl"`
+	OpenFunding   float64       `json:"open_funding"`    // 未平仓持仓累计资金费
+	Paper         *PaperAccount `json:"paper,omitempty"` // 模拟账户，未使用模拟交易时为空
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
 // Balance 余额信息
 type Balance struct {
 	Asset     string    `json:"asset"`  // 资产名称
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// 禁止交易名单匹配方式
+const (
+	DenylistTypeSymbol  = "symbol"  // 按MarketID精确匹配，如 LUNAUSDT
+	DenylistTypeBase    = "base"    // 按基础资产匹配，如 LUNA
+	DenylistTypePattern = "pattern" // 按MarketID通配符匹配，如 *UPUSDT、*DOWNUSDT
+)
+
+// CoinStatusDenied 命中禁止交易名单的币种状态
+const CoinStatusDenied = "denied"
+
+// DenylistEntry 禁止交易名单条目，命中的币种不能被选中、不进入白名单，也不能开仓或加仓
+type DenylistEntry struct {
+	Type      string    `json:"type"`  // symbol, base, pattern
+	Value     string    `json:"value"` // 统一为大写
+	Reason    string    `json:"reason"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
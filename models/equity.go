@@ -0,0 +1,12 @@
+package models
+
+// EquitySnapshot 账户权益快照
+type EquitySnapshot struct {
+	Source      string             `json:"source"`         // freqtrade 或 paper，与盈亏账本来源一致
+	Currency    string             `json:"currency"`       // 计价货币
+	Equity      float64            `json:"equity"`         // 所有实例权益之和
+	Bots        map[string]float64 `json:"bots,omitempty"` // 各Freqtrade实例的权益
+	Peak        float64            `json:"peak"`           // 截至本快照的权益峰值
+	DrawdownPct float64            `json:"drawdown_pct"`   // 距峰值回撤百分比
+	Timestamp   int64              `json:"timestamp"`      // 毫秒时间戳
+}
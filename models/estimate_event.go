@@ -0,0 +1,25 @@
+package models
+
+// 预估时间线事件类型
+const (
+	EstimateEventCreated        = "created"         // 创建预估
+	EstimateEventUpdated        = "updated"         // 修改预估
+	EstimateEventEnabled        = "enabled"         // 启用监听
+	EstimateEventDisabled       = "disabled"        // 暂停监听
+	EstimateEventNearTrigger    = "near_trigger"    // 价格接近目标价格
+	EstimateEventSkipped        = "skipped"         // 满足评估条件但被跳过
+	EstimateEventTriggered      = "triggered"       // 满足触发条件，开始下单
+	EstimateEventOrderSubmitted = "order_submitted" // 订单已提交到Freqtrade
+	EstimateEventOrderFilled    = "order_filled"    // 持仓变化已确认
+	EstimateEventOrderFailed    = "order_failed"    // 下单失败或持仓变化不符合预期
+	EstimateEventExpired        = "expired"         // 超过有效期未触发
+)
+
+// EstimateEvent 预估时间线上的一条事件，用于排查预估为何触发或未触发
+type EstimateEvent struct {
+	EstimateID string                 `json:"estimate_id"`
+	Type       string                 `json:"type"`
+	Message    string                 `json:"message,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	Timestamp  int64                  `json:"timestamp"` // 毫秒
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// 预估执行记录状态
+const (
+	ExecutionStateExecuting = "executing" // 正在下单
+	ExecutionStateSucceeded = "succeeded" // 下单成功
+	ExecutionStateFailed    = "failed"    // 下单失败
+)
+
+// ExecutionResponse Freqtrade 下单接口的原始响应
+type ExecutionResponse struct {
+	Path       string    `json:"path"`
+	Body       string    `json:"body"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// EstimateExecution 预估的执行记录，同一预估只允许一个实例取得执行权
+// 进程在下单过程中退出时，根据记录中的响应判断订单是否已提交，避免重复下单
+type EstimateExecution struct {
+	EstimateID string              `json:"estimate_id"`
+	Token      string              `json:"token"` // 执行令牌，只有持有令牌的实例可以更新记录
+	Owner      string              `json:"owner"` // 执行实例，hostname:pid
+	State      string              `json:"state"`
+	Responses  []ExecutionResponse `json:"responses,omitempty"`
+	Error      string              `json:"error,omitempty"`
+	StartedAt  time.Time           `json:"started_at"`
+	UpdatedAt  time.Time           `json:"updated_at"` // 最近一次更新，执行中的记录超过执行超时未更新视为中断
+	FinishedAt *time.Time          `json:"finished_at,omitempty"`
+}
@@ -37,6 +37,13 @@ type ForceSellPayload struct {
 	Amount    string `json:"amount"`    // 卖出数量，可以是 "half", "all" 或具体数字
 }
 
+// ForceExitPayload 强制平仓载荷，Amount 为空时全部平仓
+type ForceExitPayload struct {
+	TradeId   string   `json:"tradeid"`          // 交易ID
+	OrderType string   `json:"ordertype"`        // market, limit
+	Amount    *float64 `json:"amount,omitempty"` // 部分平仓数量（币的数量）
+}
+
 // PositionStatus 持仓状态
 type PositionStatus struct {
 	DryRun          bool   `json:"dry_run"`
@@ -71,6 +78,8 @@ type TradePosition struct {
 	CloseRate          *float64         `json:"close_rate"`
 	CloseOrderType     *string          `json:"close_order_type"`
 	CloseFee           *float64         `json:"close_fee"`
+	FeeOpenCost        *float64         `json:"fee_open_cost"` // 开仓手续费金额
+	FeeCloseCost       *float64         `json:"fee_close_cost"` // 平仓手续费金额
 	CloseProfit        *float64         `json:"close_profit"`
 	CloseProfitAbs     *float64         `json:"close_profit_abs"`
 	TradeDirection     string           `json:"trade_direction"` // long, short
@@ -98,6 +107,7 @@ type TradePosition struct {
 	HasOpenOrders      bool             `json:"has_open_orders"`
 	Orders             []FreqtradeOrder `json:"orders"`
 	GrindSummary       *TradeGrindSummary `json:"grind_summary,omitempty"` // grind 状态汇总
+	FundingTracked     *PositionFunding   `json:"funding_tracked,omitempty"` // 本地跟踪的累计资金费
 }
 
 // GrindStatus grind 状态信息
@@ -142,3 +152,21 @@ type FreqtradeOrder struct {
 	Fee                  *float64 `json:"fee"`
 	IsOpen               bool     `json:"is_open"`
 }
+
+// FreqtradeShowConfig Freqtrade /api/v1/show_config 返回的运行配置（仅包含用到的字段）
+type FreqtradeShowConfig struct {
+	BotName     string `json:"bot_name"`
+	State       string `json:"state"` // running, stopped, reload_config
+	Strategy    string `json:"strategy"`
+	DryRun      bool   `json:"dry_run"`
+	TradingMode string `json:"trading_mode"`
+	StakeAmount any    `json:"stake_amount"` // 每笔投入金额，数字或 "unlimited"
+}
+
+// FreqtradeBalance Freqtrade /api/v1/balance 返回的账户余额（仅包含用到的字段）
+type FreqtradeBalance struct {
+	Total           float64 `json:"total"`     // 账户总权益（按计价货币折算）
+	TotalBot        float64 `json:"total_bot"` // 机器人管理的权益
+	Stake           string  `json:"stake"`     // 计价货币
+	StartingCapital float64 `json:"starting_capital"`
+}
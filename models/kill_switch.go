@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// KillSwitchState 全局交易紧急停止状态，开启后所有预估暂停执行，需要显式解除
+type KillSwitchState struct {
+	Active          bool       `json:"active"`
+	Reason          string     `json:"reason,omitempty"`
+	ActivatedBy     string     `json:"activated_by,omitempty"` // api 或 telegram:<用户>
+	ActivatedAt     *time.Time `json:"activated_at,omitempty"`
+	CancelledOrders int        `json:"cancelled_orders"` // 开启时撤销的Freqtrade未成交限价开仓单数量
+	ReleasedBy      string     `json:"released_by,omitempty"`
+	ReleasedAt      *time.Time `json:"released_at,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// ExecutionLatency 单次触发的执行延迟记录
+type ExecutionLatency struct {
+	EstimateID  string `json:"estimate_id"`
+	Symbol      string `json:"symbol"`
+	Exchange    string `json:"exchange,omitempty"`
+	ActionType  string `json:"action_type"`
+	Result      string `json:"result"`       // success, error
+	DetectionMs int64  `json:"detection_ms"` // 从满足条件的tick开始到发起下单的耗时
+	ExecutionMs int64  `json:"execution_ms"` // 下单请求耗时
+	TotalMs     int64  `json:"total_ms"`     // 总耗时
+	Timestamp   int64  `json:"timestamp"`    // 毫秒
+}
+
+// LatencyStats 执行延迟分位数统计（毫秒）
+type LatencyStats struct {
+	Count int   `json:"count"`
+	P50   int64 `json:"p50"`
+	P90   int64 `json:"p90"`
+	P99   int64 `json:"p99"`
+	Max   int64 `json:"max"`
+	Mean  int64 `json:"mean"`
+	SLOMs int64 `json:"slo_ms"`         // 当前SLO，0 表示未设置
+	Over  int   `json:"slo_violations"` // 超过SLO的次数
+}
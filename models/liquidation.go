@@ -0,0 +1,40 @@
+package models
+
+// 被强平的持仓方向常量
+const (
+	LiquidationSideLong  = "long"  // 多头被强平（强平卖单）
+	LiquidationSideShort = "short" // 空头被强平（强平买单）
+)
+
+// LiquidationBucket 一分钟内的强平汇总
+type LiquidationBucket struct {
+	Symbol        string  `json:"symbol"`
+	Minute        int64   `json:"minute"`         // 分钟开始时间（毫秒）
+	LongNotional  float64 `json:"long_notional"`  // 多头被强平成交额 (USDT)
+	ShortNotional float64 `json:"short_notional"` // 空头被强平成交额 (USDT)
+	LongCount     int     `json:"long_count"`
+	ShortCount    int     `json:"short_count"`
+}
+
+// LiquidationSummary 一段时间内的强平汇总和分钟明细
+type LiquidationSummary struct {
+	Symbol        string               `json:"symbol"`
+	Exchange      string               `json:"exchange"`
+	Minutes       int                  `json:"minutes"`
+	LongNotional  float64              `json:"long_notional"`
+	ShortNotional float64              `json:"short_notional"`
+	LongCount     int                  `json:"long_count"`
+	ShortCount    int                  `json:"short_count"`
+	Buckets       []*LiquidationBucket `json:"buckets"` // 按时间正序，没有强平的分钟不返回
+}
+
+// LiquidationAlert 单分钟单方向强平成交额超过阈值的告警
+type LiquidationAlert struct {
+	Symbol    string  `json:"symbol"`
+	Exchange  string  `json:"exchange"`
+	Side      string  `json:"side"` // 被强平的持仓方向: long, short
+	Minute    int64   `json:"minute"`
+	Notional  float64 `json:"notional"`
+	Count     int     `json:"count"`
+	Threshold float64 `json:"threshold"`
+}
@@ -0,0 +1,32 @@
+package models
+
+// OpenInterestPoint 一次持仓量采样
+type OpenInterestPoint struct {
+	Timestamp    int64   `json:"timestamp"`
+	OpenInterest float64 `json:"open_interest"` // 持仓量（合约数量）
+	Value        float64 `json:"value"`         // 持仓价值 (USDT)
+	Price        float64 `json:"price"`         // 采样时的标记价格，便于与持仓量叠加绘图
+}
+
+// OpenInterestSummary 持仓量时间序列和变化率
+type OpenInterestSummary struct {
+	Symbol      string               `json:"symbol"`
+	Exchange    string               `json:"exchange"`
+	Latest      *OpenInterestPoint   `json:"latest"`
+	Change5mPct *float64             `json:"change_5m_pct"` // 最近5分钟持仓量变化(%)，历史不足时为空
+	Change1hPct *float64             `json:"change_1h_pct"` // 最近1小时持仓量变化(%)，历史不足时为空
+	Points      []*OpenInterestPoint `json:"points"`        // 按时间正序
+}
+
+// OpenInterestAlert 持仓量在统计窗口内变化超过阈值的告警
+type OpenInterestAlert struct {
+	Symbol       string  `json:"symbol"`
+	Exchange     string  `json:"exchange"`
+	Window       string  `json:"window"` // 5m, 1h
+	ChangePct    float64 `json:"change_pct"`
+	Threshold    float64 `json:"threshold"`
+	OpenInterest float64 `json:"open_interest"`
+	Value        float64 `json:"value"`
+	Price        float64 `json:"price"`
+	Timestamp    int64   `json:"timestamp"`
+}
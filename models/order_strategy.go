@@ -0,0 +1,20 @@
+package models
+
+// 下单执行策略常量
+const (
+	OrderStrategyTWAP    = "twap"    // 按固定间隔拆分为多笔，每笔使用预估的订单类型
+	OrderStrategyIceberg = "iceberg" // 拆分为小额限价单，每笔按最新盘口价格追价
+)
+
+// OrderStrategy 触发后的拆单执行策略，为空时一次性下单
+type OrderStrategy struct {
+	Type            string  `json:"type"`             // twap, iceberg
+	Slices          int     `json:"slices"`           // 拆分笔数
+	IntervalSeconds int     `json:"interval_seconds"` // 每笔之间的间隔秒数
+	MaxChasePct     float64 `json:"max_chase_pct"`    // 最新价偏离触发价超过该比例(%)时停止剩余拆单，0 表示不限制
+}
+
+// IsSliced 是否需要拆单执行
+func (s *OrderStrategy) IsSliced() bool {
+	return s != nil && s.Type != "" && s.Slices > 1
+}
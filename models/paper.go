@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// PaperAccount 模拟交易账户
+type PaperAccount struct {
+	InitialBalance float64   `json:"initial_balance"` // 初始资金
+	Balance        float64   `json:"balance"`         // 可用余额（不含持仓保证金）
+	RealizedPnl    float64   `json:"realized_pnl"`    // 累计已实现盈亏
+	Fees           float64   `json:"fees"`            // 累计手续费
+	TradeCount     int       `json:"trade_count"`     // 累计成交笔数
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// PaperPosition 模拟持仓
+type PaperPosition struct {
+	Symbol        string    `json:"symbol"`             // MarketID
+	Exchange      string    `json:"exchange,omitempty"` // 价格来源交易所
+	Side          string    `json:"side"`               // long, short
+	Amount        float64   `json:"amount"`             // 持仓数量
+	EntryPrice    float64   `json:"entry_price"`
+	Leverage      int       `json:"leverage"`
+	Margin        float64   `json:"margin"`         // 占用保证金
+	InitialMargin float64   `json:"initial_margin"` // 首次开仓保证金，加仓比例以此为基准
+	MarkPrice     float64   `json:"mark_price"`     // 查询时的标记价格
+	UnrealizedPnl float64   `json:"unrealized_pnl"` // 查询时的未实现盈亏
+	RealizedPnl   float64   `json:"realized_pnl"`   // 该持仓已实现盈亏
+	OpenedAt      time.Time `json:"opened_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// PaperFill 模拟成交记录
+type PaperFill struct {
+	ID          string  `json:"id"`
+	EstimateID  string  `json:"estimate_id"`
+	Symbol      string  `json:"symbol"`
+	Side        string  `json:"side"`
+	ActionType  string  `json:"action_type"`
+	Price       float64 `json:"price"`
+	Amount      float64 `json:"amount"`
+	Notional    float64 `json:"notional"`
+	Fee         float64 `json:"fee"`
+	RealizedPnl float64 `json:"realized_pnl"`
+	Timestamp   int64   `json:"timestamp"` // 毫秒
+}
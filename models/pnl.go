@@ -0,0 +1,55 @@
+package models
+
+// 盈亏账本的数据来源
+const (
+	PnLSourceFreqtrade = "freqtrade" // Freqtrade 已平仓交易
+	PnLSourcePaper     = "paper"     // 模拟成交
+)
+
+// PnLEntry 按来源、币种和日期汇总的已实现盈亏，RealizedPnl 为扣除手续费后的净值
+type PnLEntry struct {
+	Source      string  `json:"source"`
+	Date        string  `json:"date"`   // 2006-01-02，按服务器时区
+	Symbol      string  `json:"symbol"` // MarketID
+	RealizedPnl float64 `json:"realized_pnl"`
+	Fees        float64 `json:"fees"`
+	FundingFees float64 `json:"funding_fees"`
+	Trades      int     `json:"trades"` // 平仓笔数
+	Wins        int     `json:"wins"`
+	Losses      int     `json:"losses"`
+}
+
+// PnLRecord 一笔计入账本的成交或平仓
+type PnLRecord struct {
+	Source      string
+	ID          string // 来源内唯一，用于去重
+	Symbol      string
+	Date        string
+	RealizedPnl float64 // 净盈亏
+	Fees        float64
+	FundingFees float64
+	Closed      bool // 是否为平仓，只有平仓计入笔数和胜率
+}
+
+// PnLSummary 盈亏聚合结果
+type PnLSummary struct {
+	Key         string  `json:"key"` // 日期、周（2006-W01）或币种，总计时为空
+	RealizedPnl float64 `json:"realized_pnl"`
+	Fees        float64 `json:"fees"`
+	FundingFees float64 `json:"funding_fees"`
+	Trades      int     `json:"trades"`
+	Wins        int     `json:"wins"`
+	Losses      int     `json:"losses"`
+	WinRate     float64 `json:"win_rate"` // 0-1
+}
+
+// PositionFunding 未平仓持仓累计的资金费，Total 为正表示收到，为负表示支付
+type PositionFunding struct {
+	Symbol          string  `json:"symbol"` // MarketID
+	Side            string  `json:"side"`   // LONG, SHORT
+	Total           float64 `json:"total"`
+	Payments        int     `json:"payments"`          // 结算次数
+	LastRate        float64 `json:"last_rate"`         // 最近一次结算的资金费率
+	LastAmount      float64 `json:"last_amount"`       // 最近一次结算的资金费
+	LastFundingTime int64   `json:"last_funding_time"` // 最近一次结算时间（毫秒）
+}
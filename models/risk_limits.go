@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// RiskLimit 下单前风控限制，0 表示不限制
+type RiskLimit struct {
+	MaxOpenNotional  float64 `json:"max_open_notional"`   // 持仓名义价值上限（含本次下单）
+	MaxPositions     int     `json:"max_positions"`       // 同时持仓数量上限（含本次开仓）
+	MaxLeverage      int     `json:"max_leverage"`        // 杠杆倍数上限
+	MaxStakePerTrade float64 `json:"max_stake_per_trade"` // 单笔下单保证金上限
+}
+
+// IsEmpty 是否未设置任何限制
+func (l RiskLimit) IsEmpty() bool {
+	return l.MaxOpenNotional <= 0 && l.MaxPositions <= 0 && l.MaxLeverage <= 0 && l.MaxStakePerTrade <= 0
+}
+
+// RiskLimits 全局和按币种的下单前风控限制，开仓和加仓触发时检查
+type RiskLimits struct {
+	Global    RiskLimit            `json:"global"`
+	Symbols   map[string]RiskLimit `json:"symbols"` // MarketID -> 该币种的限制，与全局限制同时生效
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// IsEmpty 是否未设置任何限制
+func (l *RiskLimits) IsEmpty() bool {
+	if l == nil {
+		return true
+	}
+	if !l.Global.IsEmpty() {
+		return false
+	}
+	for _, limit := range l.Symbols {
+		if !limit.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
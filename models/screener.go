@@ -0,0 +1,16 @@
+package models
+
+// ScreenerRow 选币器中一个币种的排序指标，全部来自已缓存的行情数据
+type ScreenerRow struct {
+	Symbol        string   `json:"symbol"`
+	Price         float64  `json:"price"`            // 最新价，优先使用实时推送的价格
+	ChangePct     float64  `json:"change_pct"`       // 24小时涨跌幅(%)
+	QuoteVolume   float64  `json:"quote_volume"`     // 24小时成交额 (USDT)
+	FundingRate   *float64 `json:"funding_rate"`     // 当前资金费率，没有标记价格数据时为空
+	High          float64  `json:"high"`             // 24小时最高价
+	Low           float64  `json:"low"`              // 24小时最低价
+	FromHighPct   *float64 `json:"from_high_pct"`    // 距24小时最高价的跌幅(%)
+	FromLowPct    *float64 `json:"from_low_pct"`     // 距24小时最低价的涨幅(%)
+	OIChange1hPct *float64 `json:"oi_change_1h_pct"` // 1小时持仓量变化(%)，只有开启持仓量跟踪的选中币种有值
+	IsSelected    bool     `json:"is_selected"`
+}
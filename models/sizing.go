@@ -0,0 +1,23 @@
+package models
+
+// OrderSizing 按价格、杠杆和币种限制计算出的下单数量
+type OrderSizing struct {
+	Price       float64  `json:"price"`        // 计算使用的价格
+	Leverage    int      `json:"leverage"`     // 计算使用的杠杆倍数
+	RawQuantity float64  `json:"raw_quantity"` // 按步长取整前的数量
+	Quantity    float64  `json:"quantity"`     // 按步长取整后的下单数量，0表示无法计算
+	Notional    float64  `json:"notional"`     // 名义价值
+	Margin      float64  `json:"margin"`       // 所需保证金
+	Violations  []string `json:"violations"`   // 违反的币种限制，为空表示满足
+	Note        string   `json:"note,omitempty"`
+}
+
+// EstimatePreview 预估在当前行情下的试算结果，不会保存
+type EstimatePreview struct {
+	Estimate     *PriceEstimate `json:"estimate"`      // 精度格式化后的预估
+	MarkPrice    float64        `json:"mark_price"`    // 当前标记价格
+	CurrentPrice float64        `json:"current_price"` // 按价格来源选择的触发价格
+	DistancePct  float64        `json:"distance_pct"`  // 目标价相对当前价的距离（%），立即触发时为0
+	WouldTrigger bool           `json:"would_trigger"` // 按当前价格是否会立即触发
+	Sizing       *OrderSizing   `json:"sizing"`
+}
@@ -0,0 +1,25 @@
+package models
+
+// 预估跳过原因常量
+const (
+	SkipReasonStalePrice   = "stale_price"   // 价格数据缺失或长时间未更新
+	SkipReasonInvalidPrice = "invalid_price" // 买卖价和标记价格均无效
+	SkipReasonGuardBlocked = "guard_blocked" // 已满足触发条件但被风控检查拦截
+	SkipReasonDuplicate    = "duplicate"     // 已满足触发条件但该预估已有执行记录
+	SkipReasonVolatility   = "volatility"    // 已满足触发条件但波动率或价差超过波动保护阈值
+	SkipReasonCircuitOpen  = "circuit_open"  // 已满足触发条件但下单的Freqtrade实例处于熔断状态
+)
+
+// EvaluationSkip 满足评估条件但被跳过的预估记录
+type EvaluationSkip struct {
+	ID          string  `json:"id"` // Redis Stream 条目ID
+	EstimateID  string  `json:"estimate_id"`
+	Symbol      string  `json:"symbol"`
+	Exchange    string  `json:"exchange,omitempty"`
+	Side        string  `json:"side"`
+	ActionType  string  `json:"action_type"`
+	TargetPrice float64 `json:"target_price"`
+	Reason      string  `json:"reason"`           // 跳过原因
+	Detail      string  `json:"detail,omitempty"` // 详细说明
+	Timestamp   int64   `json:"timestamp"`        // 毫秒
+}
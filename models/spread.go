@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// 价差计算方式常量
+const (
+	SpreadModeRatio      = "ratio"      // 比值: A / B
+	SpreadModeDifference = "difference" // 价差: A - k * B
+)
+
+// 价差触发条件常量
+const (
+	SpreadConditionAbove = "above" // 价差 >= 目标值时触发
+	SpreadConditionBelow = "below" // 价差 <= 目标值时触发
+)
+
+// SpreadLeg 价差监控触发后单腿要创建的价格预估
+type SpreadLeg struct {
+	Symbol      string  `json:"symbol"`       // MarketID
+	Side        string  `json:"side"`         // long, short
+	ActionType  string  `json:"action_type"`  // open, addition, take_profit, stop_loss
+	StakeAmount float64 `json:"stake_amount"` // 开仓金额 (USDT 保证金)
+	Percentage  float64 `json:"percentage"`   // 仓位比例 (加仓/止盈)
+	Amount      float64 `json:"amount"`       // 交易数量 (止盈)
+	Leverage    int     `json:"leverage"`
+}
+
+// SpreadMonitor 双币种价差监控（配对交易）
+type SpreadMonitor struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Exchange     string     `json:"exchange"`     // 价格来源交易所，为空时使用主交易所
+	LegA         SpreadLeg  `json:"leg_a"`        // 第一腿（分子/被减数）
+	LegB         SpreadLeg  `json:"leg_b"`        // 第二腿（分母/减数）
+	Mode         string     `json:"mode"`         // ratio, difference
+	HedgeRatio   float64    `json:"hedge_ratio"`  // difference 模式下的系数 k
+	Condition    string     `json:"condition"`    // above, below
+	TargetValue  float64    `json:"target_value"` // 目标价差
+	Status       string     `json:"status"`       // listening, triggered, failed
+	Enabled      bool       `json:"enabled"`
+	Tag          string     `json:"tag"`
+	TriggerValue float64    `json:"trigger_value,omitempty"` // 触发时的价差
+	EstimateIDs  []string   `json:"estimate_ids,omitempty"`  // 触发后创建的两腿价格预估
+	ErrorMessage string     `json:"error_message"`
+	TriggeredAt  *time.Time `json:"triggered_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
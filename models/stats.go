@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// SymbolStats 币种滚动统计，由保存的K线计算，用于触发保护和前端展示
+type SymbolStats struct {
+	Symbol                string    `json:"symbol"`
+	Exchange              string    `json:"exchange"`
+	Timeframe             string    `json:"timeframe"`               // 计算VWAP和波动率使用的K线周期
+	SessionStart          time.Time `json:"session_start"`           // 当前交易时段开始时间（UTC 0点）
+	VWAP                  float64   `json:"vwap"`                    // 时段成交量加权均价，按 (最高+最低+收盘)/3 计算
+	SessionVolume         float64   `json:"session_volume"`          // 时段成交量
+	RealizedVolatilityPct float64   `json:"realized_volatility_pct"` // 最近24小时已实现波动率(%)
+	VolatilitySamples     int       `json:"volatility_samples"`      // 参与计算的收益率个数
+	ATRTimeframe          string    `json:"atr_timeframe"`           // 计算ATR使用的K线周期
+	ATRPeriod             int       `json:"atr_period"`              // ATR周期数
+	ATR                   float64   `json:"atr"`                     // 平均真实波幅
+	ATRPct                float64   `json:"atr_pct"`                 // ATR占最新收盘价的比例(%)
+	LastClose             float64   `json:"last_close"`              // 最新收盘价
+	UpdatedAt             time.Time `json:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// Telegram指令用户角色
+const (
+	TelegramRoleViewer = "viewer" // 只能查询
+	TelegramRoleTrader = "trader" // 可查询和下达交易指令
+)
+
+// TelegramAudit Telegram指令审计记录
+type TelegramAudit struct {
+	ChatID    int64  `json:"chat_id"`
+	UserID    int64  `json:"user_id"`
+	Username  string `json:"username,omitempty"`
+	Role      string `json:"role,omitempty"` // 为空表示未授权
+	Command   string `json:"command"`
+	Allowed   bool   `json:"allowed"`
+	Result    string `json:"result"`    // 回复给用户的第一条消息
+	Timestamp int64  `json:"timestamp"` // 毫秒
+}
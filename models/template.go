@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// EstimateTemplate 价格预估模板，保存除币种和价格外的下单配置，用于一键创建预估
+type EstimateTemplate struct {
+	Name          string         `json:"name"`           // 模板名，如 long_scalp，唯一
+	Side          string         `json:"side"`           // long, short
+	ActionType    string         `json:"action_type"`    // open, addition, take_profit, stop_loss
+	Leverage      int            `json:"leverage"`       // 杠杆倍数
+	Percentage    float64        `json:"percentage"`     // 仓位比例 (加仓/止盈/止损)
+	StakeAmount   float64        `json:"stake_amount"`   // 开仓保证金 (USDT)
+	Amount        float64        `json:"amount"`         // 交易数量 (币的数量)
+	OrderType     string         `json:"order_type"`     // market, limit
+	MarginMode    string         `json:"margin_mode"`    // CROSS, ISOLATED
+	TriggerType   string         `json:"trigger_type"`   // 指定价格时的触发类型，未指定价格时立即执行
+	TTLSeconds    int64          `json:"ttl_seconds"`    // 创建的预估有效期秒数，0 表示使用默认值
+	OrderStrategy *OrderStrategy `json:"order_strategy"` // 拆单执行策略（可选）
+	Tag           string         `json:"tag"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
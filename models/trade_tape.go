@@ -0,0 +1,14 @@
+package models
+
+// LargeTradeAlert 大额成交告警，单笔成交名义价值超过阈值时产生
+type LargeTradeAlert struct {
+	Symbol    string  `json:"symbol"`
+	Exchange  string  `json:"exchange"`
+	TradeID   string  `json:"trade_id"`
+	Side      string  `json:"side"` // 主动成交方向: buy, sell
+	Price     float64 `json:"price"`
+	Amount    float64 `json:"amount"`
+	Notional  float64 `json:"notional"`  // 成交额 (USDT)
+	Threshold float64 `json:"threshold"` // 告警阈值 (USDT)
+	Timestamp int64   `json:"timestamp"`
+}
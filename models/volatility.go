@@ -0,0 +1,13 @@
+package models
+
+// VolatilityGuard 触发时的波动保护，短时波动率或买卖价差超过阈值时推迟触发，避免在插针时成交
+type VolatilityGuard struct {
+	MaxVolatilityPct float64 `json:"max_volatility_pct"` // 最近窗口（默认1分钟）已实现波动率上限(%)，0 表示不检查
+	MaxSpreadPct     float64 `json:"max_spread_pct"`     // 买卖价差占中间价的比例上限(%)，0 表示不检查
+	DeferSeconds     int     `json:"defer_seconds"`      // 超过阈值后推迟评估的秒数，0 使用默认值
+}
+
+// IsEnabled 是否启用了波动保护
+func (g *VolatilityGuard) IsEnabled() bool {
+	return g != nil && (g.MaxVolatilityPct > 0 || g.MaxSpreadPct > 0)
+}
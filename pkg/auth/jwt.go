@@ -10,15 +10,17 @@ import (
 
 type Claims struct {
 	Username string `json:"username"`
+	Role     string `json:"role"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken 生成JWT token
-func GenerateToken(username string) (string, error) {
+// GenerateToken 生成JWT token，有效期由 AUTH_TOKEN_TTL 配置
+func GenerateToken(username, role string) (string, error) {
 	claims := Claims{
 		Username: username,
+		Role:     role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // 24小时过期
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.GlobalConfig.AuthTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "trading-assistant",
@@ -50,15 +52,12 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		// 引入角色前签发的token只有管理员能登录获得
+		if claims.Role == "" {
+			claims.Role = RoleTrader
+		}
 		return claims, nil
 	}
 
 	return nil, fmt.Errorf("无效的token")
 }
-
-// ValidateCredentials 验证用户名密码
-func ValidateCredentials(username, password string) bool {
-	return username == config.GlobalConfig.AdminUsername &&
-		password == config.GlobalConfig.AdminPassword &&
-		config.GlobalConfig.AdminPassword != "" // 确保密码不为空
-}
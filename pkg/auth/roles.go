@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"sync"
+	"trading_assistant/pkg/config"
+)
+
+// 接口访问角色，与Telegram指令角色一致
+const (
+	RoleViewer = "viewer" // 只读：只能查询
+	RoleTrader = "trader" // 交易：可以创建、修改、删除预估和操作Freqtrade
+)
+
+// APIKey 接口密钥，通过 X-API-Key 请求头认证，供脚本和外部系统调用
+type APIKey struct {
+	Name string
+	Key  string
+	Role string
+}
+
+var (
+	apiKeys   []APIKey
+	apiKeysMu sync.RWMutex
+)
+
+// ParseAPIKeys 解析接口密钥列表，格式为 名称:密钥[:角色]，角色省略时为 viewer
+func ParseAPIKeys(entries []string) ([]APIKey, error) {
+	keys := make([]APIKey, 0, len(entries))
+	names := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("无效的接口密钥配置: %s，格式应为 名称:密钥[:角色]", entry)
+		}
+		key := APIKey{
+			Name: strings.TrimSpace(parts[0]),
+			Key:  strings.TrimSpace(parts[1]),
+			Role: RoleViewer,
+		}
+		if len(parts) == 3 {
+			key.Role = strings.ToLower(strings.TrimSpace(parts[2]))
+		}
+		if key.Name == "" || key.Key == "" {
+			return nil, fmt.Errorf("接口密钥名称和密钥不能为空: %s", entry)
+		}
+		if key.Role != RoleViewer && key.Role != RoleTrader {
+			return nil, fmt.Errorf("无效的接口密钥角色: %s，必须是 %s 或 %s", entry, RoleViewer, RoleTrader)
+		}
+		if names[key.Name] {
+			return nil, fmt.Errorf("接口密钥名称重复: %s", key.Name)
+		}
+		names[key.Name] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// InitAPIKeys 解析并加载配置的接口密钥
+func InitAPIKeys(entries []string) error {
+	keys, err := ParseAPIKeys(entries)
+	if err != nil {
+		return err
+	}
+	apiKeysMu.Lock()
+	apiKeys = keys
+	apiKeysMu.Unlock()
+	return nil
+}
+
+// ValidateAPIKey 验证接口密钥，返回对应的密钥配置
+func ValidateAPIKey(key string) (*APIKey, bool) {
+	apiKeysMu.RLock()
+	defer apiKeysMu.RUnlock()
+
+	for i := range apiKeys {
+		if subtle.ConstantTimeCompare([]byte(apiKeys[i].Key), []byte(key)) == 1 {
+			return &apiKeys[i], true
+		}
+	}
+	return nil, false
+}
+
+// ValidateCredentials 验证用户名密码，返回登录用户的角色
+// 管理员为交易角色，配置了 VIEWER_USERNAME/VIEWER_PASSWORD 时可使用只读账号登录
+func ValidateCredentials(username, password string) (string, bool) {
+	cfg := config.GlobalConfig
+	if cfg.AdminPassword != "" && // 确保密码不为空
+		username == cfg.AdminUsername && password == cfg.AdminPassword {
+		return RoleTrader, true
+	}
+	if cfg.ViewerUsername != "" && cfg.ViewerPassword != "" &&
+		username == cfg.ViewerUsername && password == cfg.ViewerPassword {
+		return RoleViewer, true
+	}
+	return "", false
+}
+
+// RoleAllows 判断角色是否满足要求，交易角色拥有所有权限
+func RoleAllows(role, required string) bool {
+	if role == RoleTrader {
+		return true
+	}
+	return role == required
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"trading_assistant/pkg/models"
+)
+
+// Migration 数据库迁移，Version 递增且发布后不可修改
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+}
+
+// schemaMigration 已执行的迁移记录
+type schemaMigration struct {
+	Version   int    `gorm:"primarykey;autoIncrement:false"`
+	Name      string `gorm:"size:128"`
+	AppliedAt time.Time
+}
+
+// TableName 表名
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// migrations 按版本顺序执行的迁移，新增表或字段时在末尾追加
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_history_tables",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(
+				&models.EstimateHistory{},
+				&models.TriggerEvent{},
+				&models.TelegramAuditHistory{},
+				&models.PnLDaily{},
+			)
+		},
+	},
+}
+
+// RunMigrations 执行尚未执行的迁移，每个迁移在独立事务中执行并记录版本
+func RunMigrations(db *gorm.DB) error {
+	if db == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return fmt.Errorf("创建迁移记录表失败: %v", err)
+	}
+
+	var applied []schemaMigration
+	if err := db.Find(&applied).Error; err != nil {
+		return fmt.Errorf("获取迁移记录失败: %v", err)
+	}
+	done := make(map[int]bool, len(applied))
+	for _, migration := range applied {
+		done[migration.Version] = true
+	}
+
+	for _, migration := range migrations {
+		if done[migration.Version] {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("执行迁移 %d_%s 失败: %v", migration.Version, migration.Name, err)
+		}
+		logrus.Infof("数据库迁移已执行: %d_%s", migration.Version, migration.Name)
+	}
+	return nil
+}
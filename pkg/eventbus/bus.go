@@ -0,0 +1,309 @@
+package eventbus
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+	"trading_assistant/pkg/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Topic 事件主题
+type Topic string
+
+// 事件主题常量
+const (
+	TopicMarkPrice         Topic = "markprice"          // 一轮价格获取完成，Payload 为 *MarkPriceBatch
+	TopicKline             Topic = "kline"              // K线已保存，Payload 为 *KlineBatch
+	TopicOrder             Topic = "order"              // 订单已提交（含模拟成交），Payload 为 *models.PriceEstimate
+	TopicPosition          Topic = "position"           // 持仓缓存已刷新，Payload 为 []*models.Position
+	TopicEstimateTriggered Topic = "estimate_triggered" // 价格预估触发完成，Payload 为 *models.PriceEstimate
+)
+
+// defaultBuffer 订阅者默认缓冲区大小
+const defaultBuffer = 256
+
+// Event 事件
+type Event struct {
+	Topic     Topic
+	Exchange  string // 来源交易所，主交易所为空
+	Payload   interface{}
+	Timestamp int64 // 毫秒
+}
+
+// Handler 事件处理函数
+type Handler func(event *Event)
+
+// MergeFunc 合并同一来源的两个事件，返回合并后的事件，incoming 比 pending 新
+type MergeFunc func(pending, incoming *Event) *Event
+
+// SubscriberStats 订阅者队列统计
+type SubscriberStats struct {
+	Topic    Topic  `json:"topic"`
+	Name     string `json:"name"`
+	Queued   int    `json:"queued"`   // 队列中待处理的事件数
+	Capacity int    `json:"capacity"` // 队列容量
+	Pending  int    `json:"pending"`  // 等待合并处理的事件数（按来源交易所）
+	Handled  int64  `json:"handled"`
+	Merged   int64  `json:"merged"`  // 队列已满时合并到待处理事件的次数
+	Dropped  int64  `json:"dropped"` // 队列已满且主题不支持合并时丢弃的事件数
+}
+
+// subscription 单个订阅者，每个订阅者在独立的goroutine中按顺序处理事件
+type subscription struct {
+	name    string
+	topic   Topic
+	events  chan *Event
+	handler Handler
+	merge   MergeFunc
+
+	// 队列已满后的事件按来源交易所合并，队列清空后再处理，保证不会先处理新事件再处理旧事件
+	mu      sync.Mutex
+	pending map[string]*Event
+
+	handled atomic.Int64
+	merged  atomic.Int64
+	dropped atomic.Int64
+}
+
+// Bus 进程内事件总线，生产者只负责发布，消费者按主题独立订阅
+// 订阅者处理不过来时，注册了合并策略的主题合并积压事件，其余主题丢弃新事件，不会阻塞生产者
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[Topic][]*subscription
+	merges map[Topic]MergeFunc
+	closed bool
+	wg     sync.WaitGroup
+}
+
+var (
+	globalBus *Bus
+	busOnce   sync.Once
+)
+
+// NewBus 创建事件总线
+func NewBus() *Bus {
+	return &Bus{
+		subs: make(map[Topic][]*subscription),
+		merges: map[Topic]MergeFunc{
+			TopicMarkPrice: mergeMarkPriceEvents,
+		},
+	}
+}
+
+// GetBus 获取全局事件总线
+func GetBus() *Bus {
+	busOnce.Do(func() {
+		globalBus = NewBus()
+	})
+	return globalBus
+}
+
+// Subscribe 订阅主题，buffer 小于等于0时使用默认缓冲区，返回取消订阅函数
+func (b *Bus) Subscribe(topic Topic, name string, buffer int, handler Handler) func() {
+	if buffer <= 0 {
+		buffer = defaultBuffer
+	}
+	sub := &subscription{
+		name:    name,
+		topic:   topic,
+		events:  make(chan *Event, buffer),
+		handler: handler,
+		pending: make(map[string]*Event),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return func() {}
+	}
+	sub.merge = b.merges[topic]
+	b.subs[topic] = append(b.subs[topic], sub)
+
+	b.wg.Add(1)
+	go b.consume(sub)
+
+	logrus.Debugf("事件订阅: %s -> %s", topic, name)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { b.unsubscribe(sub) })
+	}
+}
+
+// Publish 发布事件，不会阻塞
+func (b *Bus) Publish(topic Topic, exchange string, payload interface{}) {
+	event := &Event{
+		Topic:     topic,
+		Exchange:  exchange,
+		Payload:   payload,
+		Timestamp: time.Now().UnixMilli(),
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+
+	metrics.EventBusPublished.WithLabelValues(string(topic)).Inc()
+	for _, sub := range b.subs[topic] {
+		sub.offer(event)
+	}
+}
+
+// SetMergePolicy 设置主题队列已满时的合并策略，只影响之后的订阅，merge 为nil时恢复为丢弃新事件
+func (b *Bus) SetMergePolicy(topic Topic, merge MergeFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if merge == nil {
+		delete(b.merges, topic)
+		return
+	}
+	b.merges[topic] = merge
+}
+
+// Stats 获取所有订阅者的队列统计，按主题和名称排序
+func (b *Bus) Stats() []SubscriberStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	stats := make([]SubscriberStats, 0)
+	for _, subs := range b.subs {
+		for _, sub := range subs {
+			sub.mu.Lock()
+			pending := len(sub.pending)
+			sub.mu.Unlock()
+			stats = append(stats, SubscriberStats{
+				Topic:    sub.topic,
+				Name:     sub.name,
+				Queued:   len(sub.events),
+				Capacity: cap(sub.events),
+				Pending:  pending,
+				Handled:  sub.handled.Load(),
+				Merged:   sub.merged.Load(),
+				Dropped:  sub.dropped.Load(),
+			})
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Topic != stats[j].Topic {
+			return stats[i].Topic < stats[j].Topic
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
+
+// Close 关闭事件总线，等待订阅者处理完已入队的事件
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for _, subs := range b.subs {
+		for _, sub := range subs {
+			close(sub.events)
+		}
+	}
+	b.subs = make(map[Topic][]*subscription)
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
+
+// unsubscribe 取消订阅
+func (b *Bus) unsubscribe(sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.subs[sub.topic]
+	for i := range subs {
+		if subs[i] == sub {
+			b.subs[sub.topic] = append(subs[:i], subs[i+1:]...)
+			close(sub.events)
+			return
+		}
+	}
+}
+
+// consume 订阅者处理循环
+func (b *Bus) consume(sub *subscription) {
+	defer b.wg.Done()
+
+	for event := range sub.events {
+		b.handle(sub, event)
+		if len(sub.events) == 0 {
+			b.flushPending(sub)
+		}
+	}
+	b.flushPending(sub)
+}
+
+// offer 事件入队，队列已满或已有待合并事件时按策略合并，不支持合并的主题丢弃新事件
+func (sub *subscription) offer(event *Event) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.merge != nil && len(sub.pending) > 0 {
+		sub.mergePending(event)
+		return
+	}
+
+	select {
+	case sub.events <- event:
+		return
+	default:
+	}
+
+	if sub.merge != nil {
+		sub.mergePending(event)
+		return
+	}
+	sub.dropped.Add(1)
+	metrics.EventBusDropped.WithLabelValues(string(sub.topic), sub.name).Inc()
+	logrus.Warnf("事件订阅者 %s 处理不过来，丢弃 %s 事件", sub.name, sub.topic)
+}
+
+// mergePending 合并到同一来源的待处理事件，调用方需持有 sub.mu
+func (sub *subscription) mergePending(event *Event) {
+	pending, exists := sub.pending[event.Exchange]
+	if !exists {
+		sub.pending[event.Exchange] = event
+		return
+	}
+	sub.pending[event.Exchange] = sub.merge(pending, event)
+	sub.merged.Add(1)
+	metrics.EventBusMerged.WithLabelValues(string(sub.topic), sub.name).Inc()
+}
+
+// flushPending 处理队列已满期间合并的事件
+func (b *Bus) flushPending(sub *subscription) {
+	sub.mu.Lock()
+	if len(sub.pending) == 0 {
+		sub.mu.Unlock()
+		return
+	}
+	pending := sub.pending
+	sub.pending = make(map[string]*Event)
+	sub.mu.Unlock()
+
+	for _, event := range pending {
+		b.handle(sub, event)
+	}
+}
+
+// handle 处理单个事件，处理函数异常不影响后续事件
+func (b *Bus) handle(sub *subscription, event *Event) {
+	defer sub.handled.Add(1)
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("事件订阅者 %s 处理 %s 事件异常: %v", sub.name, event.Topic, r)
+		}
+	}()
+	sub.handler(event)
+}
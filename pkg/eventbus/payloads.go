@@ -0,0 +1,38 @@
+package eventbus
+
+import "trading_assistant/pkg/exchanges/types"
+
+// MarkPriceBatch 一轮价格获取的结果
+type MarkPriceBatch struct {
+	Primary bool                             // 是否为主交易所
+	Prices  map[string]*types.WatchMarkPrice // MarketID -> 价格
+}
+
+// mergeMarkPriceEvents 合并积压的价格事件，每个币种只保留最新价格
+func mergeMarkPriceEvents(pending, incoming *Event) *Event {
+	older, ok := pending.Payload.(*MarkPriceBatch)
+	newer, ok2 := incoming.Payload.(*MarkPriceBatch)
+	if !ok || !ok2 {
+		return incoming
+	}
+
+	prices := make(map[string]*types.WatchMarkPrice, len(older.Prices)+len(newer.Prices))
+	for symbol, price := range older.Prices {
+		prices[symbol] = price
+	}
+	for symbol, price := range newer.Prices {
+		prices[symbol] = price
+	}
+
+	merged := *incoming
+	merged.Payload = &MarkPriceBatch{Primary: newer.Primary, Prices: prices}
+	return &merged
+}
+
+// KlineBatch 一次保存的K线
+type KlineBatch struct {
+	Symbol    string
+	Timeframe string
+	Klines    []*types.Kline
+	Synthetic bool // 是否由1m K线在本地聚合生成，聚合K线只包含已收盘的K线
+}
@@ -0,0 +1,13 @@
+package exchange_factory
+
+// 引入交易所适配器包以触发其 init() 注册，新增交易所只需在此添加一行导入
+import (
+	_ "trading_assistant/pkg/exchanges/binance"
+	_ "trading_assistant/pkg/exchanges/bitget"
+	_ "trading_assistant/pkg/exchanges/bybit"
+	_ "trading_assistant/pkg/exchanges/fake"
+	_ "trading_assistant/pkg/exchanges/hyperliquid"
+	_ "trading_assistant/pkg/exchanges/kraken"
+	_ "trading_assistant/pkg/exchanges/mexc"
+	_ "trading_assistant/pkg/exchanges/okx"
+)
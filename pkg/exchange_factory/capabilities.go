@@ -0,0 +1,254 @@
+package exchange_factory
+
+import (
+	"context"
+	"fmt"
+
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// 交易能力名称，用于 NotSupported 错误和能力矩阵
+const (
+	CapabilityOrderBook    = "fetchOrderBook"
+	CapabilityCreateOrder  = "createOrder"
+	CapabilityPositions    = "fetchPositions"
+	CapabilityLeverage     = "setLeverage"
+	CapabilityMarginMode   = "setMarginMode"
+	CapabilityStreaming    = "watchMarkPrices"
+	CapabilityTradeStream  = "watchTrades"
+	CapabilityLiquidation  = "watchLiquidations"
+	CapabilityOpenInterest = "fetchOpenInterest"
+)
+
+// OrderCreator 支持下单的交易所（可选能力）
+type OrderCreator interface {
+	CreateOrder(ctx context.Context, symbol, orderType, side string, amount, price float64, params map[string]interface{}) (*types.Order, error)
+	CancelOrder(ctx context.Context, id, symbol string) error
+}
+
+// PositionFetcher 支持查询持仓的交易所（可选能力）
+type PositionFetcher interface {
+	FetchPositions(ctx context.Context, symbols []string) ([]*types.Position, error)
+}
+
+// LeverageSetter 支持设置杠杆的交易所（可选能力）
+type LeverageSetter interface {
+	SetLeverage(ctx context.Context, symbol string, leverage int) error
+}
+
+// MarginModeSetter 支持设置保证金模式的交易所（可选能力），部分交易所切换时需要同时指定杠杆
+type MarginModeSetter interface {
+	SetMarginMode(ctx context.Context, symbol, marginMode string, leverage int) error
+}
+
+// MarkPriceStreamer 支持通过WebSocket推送标记价格的交易所（可选能力）
+// 未实现或推送中断时价格管理器以REST轮询 FetchBookTickers/FetchMarkPrices 获取价格
+type MarkPriceStreamer interface {
+	WatchMarkPrices(ctx context.Context, symbols []string) (<-chan *types.WatchMarkPrice, error)
+}
+
+// TradeStreamer 支持通过WebSocket推送逐笔成交的交易所（可选能力），Side 为主动成交方向
+type TradeStreamer interface {
+	WatchTrades(ctx context.Context, symbols []string) (<-chan *types.Trade, error)
+}
+
+// LiquidationStreamer 支持通过WebSocket推送强平订单的交易所（可选能力）
+type LiquidationStreamer interface {
+	WatchLiquidations(ctx context.Context, symbols []string) (<-chan *types.Liquidation, error)
+}
+
+// OpenInterestFetcher 支持查询合约持仓量的交易所（可选能力）
+type OpenInterestFetcher interface {
+	FetchOpenInterest(ctx context.Context, symbol string) (*types.OpenInterest, error)
+}
+
+// apiChecker 可按方法名查询能力开关的交易所，如未配置API密钥时关闭私有能力
+type apiChecker interface {
+	HasAPI(method string) bool
+}
+
+// Capabilities 交易所能力矩阵，市场数据为必备能力，其余为可选能力
+type Capabilities struct {
+	Exchange     string `json:"exchange"`
+	MarketType   string `json:"market_type"`
+	QuoteAsset   string `json:"quote_asset"`
+	Testnet      bool   `json:"testnet"` // 是否使用测试网或模拟交易账户
+	MarketData   bool   `json:"market_data"`
+	OrderBook    bool   `json:"order_book"`
+	CreateOrder  bool   `json:"create_order"`
+	Positions    bool   `json:"positions"`
+	Leverage     bool   `json:"leverage"`
+	MarginMode   bool   `json:"margin_mode"`
+	Streaming    bool   `json:"streaming"`     // 是否支持价格推送，否则使用REST轮询
+	TradeStream  bool   `json:"trade_stream"`  // 是否支持逐笔成交推送
+	Liquidation  bool   `json:"liquidation"`   // 是否支持强平订单推送
+	OpenInterest bool   `json:"open_interest"` // 是否支持查询合约持仓量
+}
+
+// GetCapabilities 通过可选接口探测交易所支持的能力
+func GetCapabilities(exchange ExchangeInterface) Capabilities {
+	quoteAsset := "USDT"
+	if provider, ok := exchange.(QuoteAssetProvider); ok {
+		quoteAsset = provider.GetQuoteAsset()
+	}
+
+	return Capabilities{
+		Exchange:     exchange.GetID(),
+		MarketType:   exchange.GetMarketType(),
+		QuoteAsset:   quoteAsset,
+		Testnet:      exchange.IsTestnet(),
+		MarketData:   true,
+		OrderBook:    SupportsOrderBook(exchange),
+		CreateOrder:  SupportsCreateOrder(exchange),
+		Positions:    SupportsPositions(exchange),
+		Leverage:     SupportsLeverage(exchange),
+		MarginMode:   SupportsMarginMode(exchange),
+		Streaming:    SupportsStreaming(exchange),
+		TradeStream:  SupportsTradeStream(exchange),
+		Liquidation:  SupportsLiquidationStream(exchange),
+		OpenInterest: SupportsOpenInterest(exchange),
+	}
+}
+
+// SupportsOrderBook 是否支持获取订单簿
+func SupportsOrderBook(exchange ExchangeInterface) bool {
+	_, ok := exchange.(OrderBookFetcher)
+	return ok
+}
+
+// SupportsCreateOrder 是否支持下单
+func SupportsCreateOrder(exchange ExchangeInterface) bool {
+	_, ok := exchange.(OrderCreator)
+	return ok
+}
+
+// SupportsPositions 是否支持查询持仓
+func SupportsPositions(exchange ExchangeInterface) bool {
+	_, ok := exchange.(PositionFetcher)
+	return ok
+}
+
+// SupportsLeverage 是否支持设置杠杆
+func SupportsLeverage(exchange ExchangeInterface) bool {
+	_, ok := exchange.(LeverageSetter)
+	return ok && apiEnabled(exchange, CapabilityLeverage)
+}
+
+// SupportsMarginMode 是否支持设置保证金模式
+func SupportsMarginMode(exchange ExchangeInterface) bool {
+	_, ok := exchange.(MarginModeSetter)
+	return ok && apiEnabled(exchange, CapabilityMarginMode)
+}
+
+// SupportsStreaming 是否支持WebSocket价格推送
+func SupportsStreaming(exchange ExchangeInterface) bool {
+	_, ok := exchange.(MarkPriceStreamer)
+	return ok && apiEnabled(exchange, CapabilityStreaming)
+}
+
+// SupportsTradeStream 是否支持WebSocket成交推送
+func SupportsTradeStream(exchange ExchangeInterface) bool {
+	_, ok := exchange.(TradeStreamer)
+	return ok && apiEnabled(exchange, CapabilityTradeStream)
+}
+
+// SupportsLiquidationStream 是否支持WebSocket强平订单推送
+func SupportsLiquidationStream(exchange ExchangeInterface) bool {
+	_, ok := exchange.(LiquidationStreamer)
+	return ok && apiEnabled(exchange, CapabilityLiquidation)
+}
+
+// SupportsOpenInterest 是否支持查询合约持仓量
+func SupportsOpenInterest(exchange ExchangeInterface) bool {
+	_, ok := exchange.(OpenInterestFetcher)
+	return ok && apiEnabled(exchange, CapabilityOpenInterest)
+}
+
+// apiEnabled 交易所实现了能力接口时，再检查该能力是否已启用（如市场类型、API密钥）
+func apiEnabled(exchange ExchangeInterface, capability string) bool {
+	if checker, ok := exchange.(apiChecker); ok {
+		return checker.HasAPI(capability)
+	}
+	return true
+}
+
+// AsOrderBookFetcher 获取订单簿能力，不支持时返回 NotSupported 错误
+func AsOrderBookFetcher(exchange ExchangeInterface) (OrderBookFetcher, error) {
+	if fetcher, ok := exchange.(OrderBookFetcher); ok {
+		return fetcher, nil
+	}
+	return nil, notSupported(exchange, CapabilityOrderBook)
+}
+
+// AsOrderCreator 获取下单能力，不支持时返回 NotSupported 错误
+func AsOrderCreator(exchange ExchangeInterface) (OrderCreator, error) {
+	if creator, ok := exchange.(OrderCreator); ok {
+		return creator, nil
+	}
+	return nil, notSupported(exchange, CapabilityCreateOrder)
+}
+
+// AsPositionFetcher 获取持仓查询能力，不支持时返回 NotSupported 错误
+func AsPositionFetcher(exchange ExchangeInterface) (PositionFetcher, error) {
+	if fetcher, ok := exchange.(PositionFetcher); ok {
+		return fetcher, nil
+	}
+	return nil, notSupported(exchange, CapabilityPositions)
+}
+
+// AsLeverageSetter 获取杠杆设置能力，不支持时返回 NotSupported 错误
+func AsLeverageSetter(exchange ExchangeInterface) (LeverageSetter, error) {
+	if setter, ok := exchange.(LeverageSetter); ok && apiEnabled(exchange, CapabilityLeverage) {
+		return setter, nil
+	}
+	return nil, notSupported(exchange, CapabilityLeverage)
+}
+
+// AsMarginModeSetter 获取保证金模式设置能力，不支持时返回 NotSupported 错误
+func AsMarginModeSetter(exchange ExchangeInterface) (MarginModeSetter, error) {
+	if setter, ok := exchange.(MarginModeSetter); ok && apiEnabled(exchange, CapabilityMarginMode) {
+		return setter, nil
+	}
+	return nil, notSupported(exchange, CapabilityMarginMode)
+}
+
+// AsMarkPriceStreamer 获取价格推送能力，不支持时返回 NotSupported 错误
+func AsMarkPriceStreamer(exchange ExchangeInterface) (MarkPriceStreamer, error) {
+	if streamer, ok := exchange.(MarkPriceStreamer); ok && apiEnabled(exchange, CapabilityStreaming) {
+		return streamer, nil
+	}
+	return nil, notSupported(exchange, CapabilityStreaming)
+}
+
+// AsTradeStreamer 获取成交推送能力，不支持时返回 NotSupported 错误
+func AsTradeStreamer(exchange ExchangeInterface) (TradeStreamer, error) {
+	if streamer, ok := exchange.(TradeStreamer); ok && apiEnabled(exchange, CapabilityTradeStream) {
+		return streamer, nil
+	}
+	return nil, notSupported(exchange, CapabilityTradeStream)
+}
+
+// AsLiquidationStreamer 获取强平订单推送能力，不支持时返回 NotSupported 错误
+func AsLiquidationStreamer(exchange ExchangeInterface) (LiquidationStreamer, error) {
+	if streamer, ok := exchange.(LiquidationStreamer); ok && apiEnabled(exchange, CapabilityLiquidation) {
+		return streamer, nil
+	}
+	return nil, notSupported(exchange, CapabilityLiquidation)
+}
+
+// AsOpenInterestFetcher 获取持仓量查询能力，不支持时返回 NotSupported 错误
+func AsOpenInterestFetcher(exchange ExchangeInterface) (OpenInterestFetcher, error) {
+	if fetcher, ok := exchange.(OpenInterestFetcher); ok && apiEnabled(exchange, CapabilityOpenInterest) {
+		return fetcher, nil
+	}
+	return nil, notSupported(exchange, CapabilityOpenInterest)
+}
+
+// notSupported 创建带交易所名称的 NotSupported 错误
+func notSupported(exchange ExchangeInterface, capability string) *exchanges.NotSupported {
+	err := exchanges.NewNotSupported(capability)
+	err.Exchange = exchange.GetID()
+	err.Message = fmt.Sprintf("交易所 %s 不支持 %s", exchange.GetID(), capability)
+	return err
+}
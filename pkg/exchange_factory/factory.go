@@ -3,14 +3,12 @@ package exchange_factory
 import (
 	"context"
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"trading_assistant/pkg/config"
-	"trading_assistant/pkg/exchanges/binance"
-	"trading_assistant/pkg/exchanges/bybit"
-	"trading_assistant/pkg/exchanges/mexc"
-	"trading_assistant/pkg/exchanges/okx"
+	"trading_assistant/pkg/exchanges"
 	"trading_assistant/pkg/exchanges/types"
 )
 
@@ -32,14 +30,28 @@ type ExchangeInterface interface {
 	FetchMarkPrices(ctx context.Context, symbols []string) (map[string]*types.MarkPrice, error)
 }
 
+// OrderBookFetcher 支持获取订单簿快照的交易所（可选能力）
+type OrderBookFetcher interface {
+	FetchOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error)
+}
+
+// QuoteAssetProvider 计价货币不是USDT的交易所（可选能力），如 Hyperliquid 以USDC计价
+type QuoteAssetProvider interface {
+	GetQuoteAsset() string
+}
+
 // ExchangeType 支持的交易所类型
 type ExchangeType string
 
 const (
-	ExchangeTypeBinance ExchangeType = "binance"
-	ExchangeTypeBybit   ExchangeType = "bybit"
-	ExchangeTypeOKX     ExchangeType = "okx"
-	ExchangeTypeMEXC    ExchangeType = "mexc"
+	ExchangeTypeBinance     ExchangeType = "binance"
+	ExchangeTypeBybit       ExchangeType = "bybit"
+	ExchangeTypeOKX         ExchangeType = "okx"
+	ExchangeTypeMEXC        ExchangeType = "mexc"
+	ExchangeTypeHyperliquid ExchangeType = "hyperliquid"
+	ExchangeTypeBitget      ExchangeType = "bitget"
+	ExchangeTypeKraken      ExchangeType = "kraken"
+	ExchangeTypeFake        ExchangeType = "fake" // 本地模拟交易所，不访问网络
 )
 
 // ExchangeFactory 交易所工厂
@@ -50,146 +62,226 @@ func NewExchangeFactory() *ExchangeFactory {
 	return &ExchangeFactory{}
 }
 
-// CreateExchange 根据配置创建交易所实例
+// CreateExchange 根据配置创建交易所实例，交易所由各适配器包在 init() 中注册
 func (f *ExchangeFactory) CreateExchange(exchangeType string, marketType string) (ExchangeInterface, error) {
-	exchangeType = strings.ToLower(strings.TrimSpace(exchangeType))
-
-	switch ExchangeType(exchangeType) {
-	case ExchangeTypeBinance:
-		return f.createBinanceExchange(marketType)
-	case ExchangeTypeBybit:
-		return f.createBybitExchange(marketType)
-	case ExchangeTypeOKX:
-		return f.createOKXExchange(marketType)
-	case ExchangeTypeMEXC:
-		return f.createMEXCExchange(marketType)
-	default:
+	descriptor, exists := exchanges.Lookup(exchangeType)
+	if !exists {
 		return nil, fmt.Errorf("不支持的交易所类型: %s", exchangeType)
 	}
+
+	instance, err := descriptor.Constructor(marketType)
+	if err != nil {
+		return nil, err
+	}
+
+	exchange, ok := instance.(ExchangeInterface)
+	if !ok {
+		return nil, fmt.Errorf("交易所 %s 未实现 ExchangeInterface", descriptor.ID)
+	}
+
+	if err := applyRateLimitConfig(descriptor.ID, exchange); err != nil {
+		return nil, err
+	}
+	if err := applyHTTPConfig(exchange); err != nil {
+		return nil, err
+	}
+	if err := applyWebSocketConfig(descriptor.ID, exchange); err != nil {
+		return nil, err
+	}
+	return exchange, nil
 }
 
-// CreateFromConfig 从全局配置创建交易所
-func (f *ExchangeFactory) CreateFromConfig() (ExchangeInterface, error) {
-	if config.GlobalConfig == nil {
-		return nil, fmt.Errorf("全局配置未初始化")
+// httpConfigurable 支持连接池和超时配置的交易所
+type httpConfigurable interface {
+	SetTimeout(timeout time.Duration)
+	GetHTTPTransportConfig() exchanges.HTTPTransportConfig
+	SetHTTPTransportConfig(config exchanges.HTTPTransportConfig) error
+}
+
+// applyHTTPConfig 应用全局的请求超时、代理和连接池配置
+func applyHTTPConfig(exchange ExchangeInterface) error {
+	client, ok := exchange.(httpConfigurable)
+	if !ok || config.GlobalConfig == nil {
+		return nil
 	}
 
-	exchangeType := config.GlobalConfig.ExchangeType
-	marketType := config.GlobalConfig.MarketType
-	if marketType == "" {
-		marketType = types.MarketTypeFuture // 默认期货市场
+	client.SetTimeout(config.GlobalConfig.ExchangeHTTPTimeout)
+
+	transport := client.GetHTTPTransportConfig()
+	if config.GlobalConfig.ExchangeHTTPProxy != "" {
+		transport.Proxy = config.GlobalConfig.ExchangeHTTPProxy
+	}
+	if config.GlobalConfig.ExchangeHTTPMaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.GlobalConfig.ExchangeHTTPMaxIdleConnsPerHost
 	}
+	transport.EnableHTTP2 = config.GlobalConfig.ExchangeHTTP2
+	return client.SetHTTPTransportConfig(transport)
+}
 
-	return f.CreateExchange(exchangeType, marketType)
+// wsConfigurable 支持推送连接配置的交易所
+type wsConfigurable interface {
+	GetWebSocketConfig() exchanges.WebSocketConfig
+	SetWebSocketConfig(config exchanges.WebSocketConfig)
 }
 
-// createBinanceExchange 创建 Binance 交易所实例
-func (f *ExchangeFactory) createBinanceExchange(marketType string) (*binance.Binance, error) {
-	config := binance.DefaultConfig()
+// applyWebSocketConfig 应用按交易所开启的推送压缩（EXCHANGE_WS_COMPRESSION）
+// 格式 交易所=流数量阈值，省略阈值时所有连接都协商压缩
+func applyWebSocketConfig(exchangeID string, exchange ExchangeInterface) error {
+	client, ok := exchange.(wsConfigurable)
+	if !ok || config.GlobalConfig == nil {
+		return nil
+	}
 
-	// 设置市场类型
-	config.MarketType = marketType
+	for _, entry := range config.GlobalConfig.ExchangeWSCompression {
+		name, threshold, found := strings.Cut(entry, "=")
+		if !strings.EqualFold(strings.TrimSpace(name), exchangeID) {
+			continue
+		}
 
-	// 设置测试网环境
-	if testnet := os.Getenv("BINANCE_TESTNET"); testnet == "true" {
-		config.TestNet = true
+		wsConfig := client.GetWebSocketConfig()
+		wsConfig.Compression = true
+		wsConfig.CompressionThreshold = 0
+		if found {
+			value, err := strconv.Atoi(strings.TrimSpace(threshold))
+			if err != nil || value < 0 {
+				return fmt.Errorf("无效的推送压缩配置 %q: 阈值必须为非负整数", entry)
+			}
+			wsConfig.CompressionThreshold = value
+		}
+		client.SetWebSocketConfig(wsConfig)
 	}
+	return nil
+}
 
-	return binance.New(config)
+// rateLimited 支持权重限流配置的交易所
+type rateLimited interface {
+	SetRateLimitEnabled(enabled bool)
+	SetRateLimitBudget(category string, capacity int, window time.Duration)
 }
 
-// createBybitExchange 创建 Bybit 交易所实例
-func (f *ExchangeFactory) createBybitExchange(marketType string) (*bybit.Bybit, error) {
-	config := bybit.DefaultConfig()
+// applyRateLimitConfig 应用全局限流开关和按交易所覆盖的权重预算（EXCHANGE_RATE_LIMITS）
+func applyRateLimitConfig(exchangeID string, exchange ExchangeInterface) error {
+	limiter, ok := exchange.(rateLimited)
+	if !ok || config.GlobalConfig == nil {
+		return nil
+	}
+
+	limiter.SetRateLimitEnabled(config.GlobalConfig.ExchangeRateLimitEnabled)
+	for _, entry := range config.GlobalConfig.ExchangeRateLimits {
+		name, budget, found := strings.Cut(entry, "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(name), exchangeID) {
+			continue
+		}
 
-	// 设置市场类型
-	if err := config.SetMarketType(marketType); err != nil {
-		return nil, fmt.Errorf("设置Bybit市场类型失败: %w", err)
+		capacity, window, err := parseRateLimitBudget(budget)
+		if err != nil {
+			return fmt.Errorf("无效的限流配置 %q: %w", entry, err)
+		}
+		limiter.SetRateLimitBudget("", capacity, window)
 	}
+	return nil
+}
 
-	// 设置测试网环境
-	if testnet := os.Getenv("BYBIT_TESTNET"); testnet == "true" {
-		config.TestNet = true
+// parseRateLimitBudget 解析 "权重/窗口" 格式的预算，如 2400/1m
+func parseRateLimitBudget(value string) (int, time.Duration, error) {
+	weight, window, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return 0, 0, fmt.Errorf("格式应为 权重/窗口")
 	}
 
-	return bybit.New(config)
+	capacity, err := strconv.Atoi(strings.TrimSpace(weight))
+	if err != nil || capacity <= 0 {
+		return 0, 0, fmt.Errorf("权重必须为正整数")
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil || duration <= 0 {
+		return 0, 0, fmt.Errorf("窗口必须为正的时间长度")
+	}
+	return capacity, duration, nil
 }
 
-// createOKXExchange 创建 OKX 交易所实例
-func (f *ExchangeFactory) createOKXExchange(marketType string) (*okx.OKX, error) {
-	config := okx.DefaultConfig()
+// CreateFromConfig 从全局配置创建交易所
+func (f *ExchangeFactory) CreateFromConfig() (ExchangeInterface, error) {
+	if config.GlobalConfig == nil {
+		return nil, fmt.Errorf("全局配置未初始化")
+	}
 
-	// 设置市场类型
-	if err := config.SetMarketType(marketType); err != nil {
-		return nil, fmt.Errorf("设置OKX市场类型失败: %w", err)
+	exchangeType := config.GlobalConfig.ExchangeType
+	marketType := config.GlobalConfig.MarketType
+	if marketType == "" {
+		marketType = types.MarketTypeFuture // 默认期货市场
 	}
 
-	return okx.New(config)
+	return f.CreateExchange(exchangeType, marketType)
 }
 
-// createMEXCExchange 创建 MEXC 交易所实例
-func (f *ExchangeFactory) createMEXCExchange(marketType string) (*mexc.MEXC, error) {
-	config := mexc.DefaultConfig()
-	config.MarketType = marketType
-	return mexc.New(config)
+// CreateSecondaryFromConfig 从全局配置创建同时运行的其他交易所
+func (f *ExchangeFactory) CreateSecondaryFromConfig() ([]ExchangeInterface, error) {
+	if config.GlobalConfig == nil {
+		return nil, fmt.Errorf("全局配置未初始化")
+	}
+
+	primary := strings.ToLower(strings.TrimSpace(config.GlobalConfig.ExchangeType))
+	marketType := config.GlobalConfig.MarketType
+	if marketType == "" {
+		marketType = types.MarketTypeFuture // 默认期货市场
+	}
+
+	var secondaries []ExchangeInterface
+	seen := map[string]bool{primary: true}
+	for _, exchangeType := range config.GlobalConfig.SecondaryExchanges {
+		exchangeType = strings.ToLower(strings.TrimSpace(exchangeType))
+		if seen[exchangeType] {
+			continue
+		}
+		seen[exchangeType] = true
+
+		exchange, err := f.CreateExchange(exchangeType, marketType)
+		if err != nil {
+			return nil, fmt.Errorf("创建交易所 %s 失败: %w", exchangeType, err)
+		}
+		secondaries = append(secondaries, exchange)
+	}
+
+	return secondaries, nil
 }
 
 // GetSupportedExchanges 获取支持的交易所列表
 func (f *ExchangeFactory) GetSupportedExchanges() []string {
-	return []string{
-		string(ExchangeTypeBinance),
-		string(ExchangeTypeBybit),
-		string(ExchangeTypeOKX),
-		string(ExchangeTypeMEXC),
+	descriptors := exchanges.Registered()
+	ids := make([]string, 0, len(descriptors))
+	for _, descriptor := range descriptors {
+		ids = append(ids, descriptor.ID)
 	}
+	return ids
+}
+
+// GetRegisteredExchanges 获取所有已注册交易所的描述信息
+func (f *ExchangeFactory) GetRegisteredExchanges() []exchanges.Descriptor {
+	return exchanges.Registered()
 }
 
 // ValidateExchangeType 验证交易所类型是否支持
 func (f *ExchangeFactory) ValidateExchangeType(exchangeType string) error {
-	exchangeType = strings.ToLower(strings.TrimSpace(exchangeType))
-
-	supportedExchanges := f.GetSupportedExchanges()
-	for _, supported := range supportedExchanges {
-		if exchangeType == supported {
-			return nil
-		}
+	if _, exists := exchanges.Lookup(exchangeType); exists {
+		return nil
 	}
-
-	return fmt.Errorf("不支持的交易所类型: %s, 支持的类型: %v", exchangeType, supportedExchanges)
+	return fmt.Errorf("不支持的交易所类型: %s, 支持的类型: %v", exchangeType, f.GetSupportedExchanges())
 }
 
 // GetExchangeInfo 获取交易所信息
 func (f *ExchangeFactory) GetExchangeInfo(exchangeType string) (map[string]interface{}, error) {
-	exchangeType = strings.ToLower(strings.TrimSpace(exchangeType))
-
-	switch ExchangeType(exchangeType) {
-	case ExchangeTypeBinance:
-		return map[string]interface{}{
-			"name": "Binance", "id": "binance", "countries": []string{"JP", "MT"},
-			"version": "v3", "website": "https://www.binance.com",
-			"spot": true, "futures": true,
-		}, nil
-	case ExchangeTypeBybit:
-		return map[string]interface{}{
-			"name": "Bybit", "id": "bybit", "countries": []string{"VG"},
-			"version": "v5", "website": "https://www.bybit.com",
-			"spot": true, "futures": true,
-		}, nil
-	case ExchangeTypeOKX:
-		return map[string]interface{}{
-			"name": "OKX", "id": "okx", "countries": []string{"SC"},
-			"version": "v5", "website": "https://www.okx.com",
-			"spot": true, "futures": true,
-		}, nil
-	case ExchangeTypeMEXC:
-		return map[string]interface{}{
-			"name": "MEXC", "id": "mexc", "countries": []string{"SG"},
-			"version": "v3", "website": "https://www.mexc.com",
-			"spot": true, "futures": false,
-		}, nil
-	default:
+	descriptor, exists := exchanges.Lookup(exchangeType)
+	if !exists {
 		return nil, fmt.Errorf("不支持的交易所类型: %s", exchangeType)
 	}
+
+	return map[string]interface{}{
+		"name": descriptor.Name, "id": descriptor.ID, "countries": descriptor.Countries,
+		"version": descriptor.Version, "website": descriptor.Website,
+		"spot":    descriptor.SupportsMarketType(types.MarketTypeSpot),
+		"futures": descriptor.SupportsMarketType(types.MarketTypeFuture),
+	}, nil
 }
 
 // CreateDefaultExchange 创建默认交易所
@@ -207,16 +299,9 @@ func CreateDefaultExchange() (ExchangeInterface, error) {
 
 // GetAvailableMarketTypes 获取交易所支持的市场类型
 func (f *ExchangeFactory) GetAvailableMarketTypes(exchangeType string) ([]string, error) {
-	exchangeType = strings.ToLower(strings.TrimSpace(exchangeType))
-
-	switch ExchangeType(exchangeType) {
-	case ExchangeTypeBinance, ExchangeTypeBybit:
-		return []string{types.MarketTypeSpot, types.MarketTypeFuture}, nil
-	case ExchangeTypeOKX:
-		return []string{types.MarketTypeSpot, types.MarketTypeFuture}, nil
-	case ExchangeTypeMEXC:
-		return []string{types.MarketTypeSpot}, nil
-	default:
+	descriptor, exists := exchanges.Lookup(exchangeType)
+	if !exists {
 		return nil, fmt.Errorf("不支持的交易所类型: %s", exchangeType)
 	}
+	return descriptor.MarketTypes, nil
 }
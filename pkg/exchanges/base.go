@@ -60,6 +60,9 @@ type BaseExchange struct {
 
 	// ========== 运行时状态 ==========
 	httpClient      *http.Client
+	transportConfig HTTPTransportConfig
+	wsConfig        WebSocketConfig
+	rateLimiter     *RateLimiter
 	lastRequestTime int64
 	requestCount    int64
 
@@ -288,7 +291,9 @@ func NewBaseExchange(id, name, version string, countries []string) *BaseExchange
 		tradingFees:     make(map[string]*types.TradingFee),
 		fundingFees:     make(map[string]*types.Currency),
 		options:         make(map[string]interface{}),
-		httpClient:      &http.Client{Timeout: 30 * time.Second},
+		transportConfig: DefaultHTTPTransportConfig(),
+		wsConfig:        DefaultWebSocketConfig(),
+		rateLimiter:     NewRateLimiter(),
 		markets:         make(map[string]*types.Market),
 		marketsLoaded:   false,
 		maxRetries:      3,
@@ -301,6 +306,13 @@ func NewBaseExchange(id, name, version string, countries []string) *BaseExchange
 	base.setDefaultCapabilities()
 	base.setDefaultTimeframes()
 
+	// 默认配置不含代理，不会创建失败；超时按单次请求通过 context 控制
+	transport, _ := newHTTPTransport(base.transportConfig)
+	base.httpClient = &http.Client{Transport: transport}
+
+	// 默认权重预算，各适配器按交易所规则覆盖
+	base.rateLimiter.SetBudget(DefaultRateLimitCategory, RateLimitBudget{Capacity: 1200, Window: time.Minute})
+
 	return base
 }
 
@@ -632,9 +644,28 @@ func (b *BaseExchange) Request(ctx context.Context, url string, method string, h
 		req.Header.Set(key, value)
 	}
 
+	// 按端点类别的权重预算等待
+	category, weight := requestWeightFrom(ctx)
+	if b.enableRateLimit {
+		if err := b.rateLimiter.Wait(ctx, category, weight); err != nil {
+			return nil, err
+		}
+	}
+
+	b.mutex.RLock()
+	client, timeout := b.httpClient, b.timeout
+	b.mutex.RUnlock()
+
+	// 限流等待之后才开始计算请求超时
+	reqCtx, cancel := context.WithTimeout(ctx, requestTimeoutFrom(ctx, timeout))
+	defer cancel()
+
 	// 使用HTTP客户端
-	httpResp, err := b.httpClient.Do(req)
+	httpResp, err := client.Do(req.WithContext(reqCtx))
 	if err != nil {
+		if reqCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+			return nil, NewRequestTimeout("HTTP request timed out")
+		}
 		return nil, NewNetworkError("HTTP request failed")
 	}
 
@@ -652,6 +683,14 @@ func (b *BaseExchange) Request(ctx context.Context, url string, method string, h
 		}
 	}
 
+	// 根据响应头校准权重，被限流时暂停后续请求
+	b.rateLimiter.Observe(category, response.Headers)
+	if response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusTeapot {
+		if retryAfter := parseRetryAfter(response.Headers); retryAfter > 0 {
+			b.rateLimiter.PauseFor(retryAfter)
+		}
+	}
+
 	// 读取body
 	if httpResp.Body != nil {
 		defer httpResp.Body.Close()
@@ -725,6 +764,66 @@ func (b *BaseExchange) SetCredentials(apiKey, secret, password, uid string) {
 	b.uid = uid
 }
 
+// SetTimeout 设置默认请求超时，可通过 WithRequestTimeout 按请求覆盖
+func (b *BaseExchange) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.timeout = timeout
+}
+
+// SetProxy 设置HTTP代理，为空时使用环境变量
+func (b *BaseExchange) SetProxy(proxy string) error {
+	b.mutex.RLock()
+	config := b.transportConfig
+	b.mutex.RUnlock()
+
+	config.Proxy = proxy
+	return b.SetHTTPTransportConfig(config)
+}
+
+// SetHTTPTransportConfig 按连接池配置重建HTTP客户端
+func (b *BaseExchange) SetHTTPTransportConfig(config HTTPTransportConfig) error {
+	transport, err := newHTTPTransport(config)
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if old, ok := b.httpClient.Transport.(*http.Transport); ok {
+		old.CloseIdleConnections()
+	}
+	b.transportConfig = config
+	b.httpProxy = config.Proxy
+	b.httpClient = &http.Client{Transport: transport}
+	return nil
+}
+
+// GetHTTPTransportConfig 获取当前连接池配置
+func (b *BaseExchange) GetHTTPTransportConfig() HTTPTransportConfig {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.transportConfig
+}
+
+// SetRateLimitEnabled 开启或关闭请求前的权重预算等待
+func (b *BaseExchange) SetRateLimitEnabled(enabled bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.enableRateLimit = enabled
+}
+
+// SetRateLimitBudget 设置端点类别的权重预算，category 为空时设置默认预算
+func (b *BaseExchange) SetRateLimitBudget(category string, capacity int, window time.Duration) {
+	if category == "" {
+		category = DefaultRateLimitCategory
+	}
+	b.rateLimiter.SetBudget(category, RateLimitBudget{Capacity: capacity, Window: window})
+}
+
 // ========== 签名方法的默认实现 ==========
 func (b *BaseExchange) Sign(path, api, method string, params map[string]interface{}, headers map[string]string, body interface{}) (string, map[string]string, interface{}, error) {
 	return path, headers, body, nil
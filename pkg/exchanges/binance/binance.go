@@ -33,12 +33,16 @@ func New(config *Config) (*Binance, error) {
 	}
 
 	base := exchanges.NewBaseExchange("binance", "Binance", "v3", []string{"JP", "MT"})
+	base.SetTimeout(time.Duration(config.Timeout) * time.Millisecond)
 	binance := &Binance{
 		BaseExchange: base,
 		config:       config.Clone(),
 		marketType:   config.MarketType,
 		endpoints:    make(map[string]string),
 	}
+	if config.HasCredentials() {
+		base.SetCredentials(config.APIKey, config.Secret, "", "")
+	}
 
 	// 设置基础信息
 	binance.setBasicInfo()
@@ -56,17 +60,30 @@ func New(config *Config) (*Binance, error) {
 func (b *Binance) setBasicInfo() {
 	b.BaseExchange.SetRetryConfig(3, 100*time.Millisecond, 10*time.Second, true)
 	b.BaseExchange.EnableRetry()
+
+	// IP权重预算：期货每分钟2400，现货每分钟6000
+	if b.marketType == types.MarketTypeFuture {
+		b.BaseExchange.SetRateLimitBudget("", FuturesWeightPerMinute, time.Minute)
+	} else {
+		b.BaseExchange.SetRateLimitBudget("", SpotWeightPerMinute, time.Minute)
+	}
 }
 
 // setCapabilities 设置支持的功能
 func (b *Binance) setCapabilities() {
 	capabilities := map[string]bool{
-		"fetchMarkets":    true,
-		"fetchTicker":     true,
-		"fetchBookTicker": true,
-		"fetchKline":      true,
-		"fetchMarkPrice":  b.marketType == types.MarketTypeFuture,
-		"fetchMarkPrices": b.marketType == types.MarketTypeFuture,
+		"fetchMarkets":      true,
+		"fetchTicker":       true,
+		"fetchBookTicker":   true,
+		"fetchKline":        true,
+		"fetchOrderBook":    true,
+		"fetchMarkPrice":    b.marketType == types.MarketTypeFuture,
+		"fetchMarkPrices":   b.marketType == types.MarketTypeFuture,
+		"setLeverage":       b.marketType == types.MarketTypeFuture && b.config.HasCredentials(),
+		"setMarginMode":     b.marketType == types.MarketTypeFuture && b.config.HasCredentials(),
+		"watchTrades":       b.marketType == types.MarketTypeFuture,
+		"watchLiquidations": b.marketType == types.MarketTypeFuture,
+		"fetchOpenInterest": b.marketType == types.MarketTypeFuture,
 	}
 
 	// 设置时间周期
@@ -110,6 +127,7 @@ func (b *Binance) setEndpoints() {
 	b.endpoints["ticker24hr"] = baseURL + EndpointTicker24hr
 	b.endpoints["bookTicker"] = baseURL + EndpointBookTicker
 	b.endpoints["klines"] = baseURL + EndpointKlines
+	b.endpoints["depth"] = baseURL + EndpointDepth
 
 	// 期货端点
 	if b.marketType == types.MarketTypeFuture {
@@ -117,7 +135,11 @@ func (b *Binance) setEndpoints() {
 		b.endpoints["futuresTicker24hr"] = futuresURL + EndpointFuturesTicker24hr
 		b.endpoints["futuresBookTicker"] = futuresURL + EndpointFuturesBookTicker
 		b.endpoints["futuresKlines"] = futuresURL + EndpointFuturesKlines
+		b.endpoints["futuresDepth"] = futuresURL + EndpointFuturesDepth
 		b.endpoints["futuresPremiumIndex"] = futuresURL + EndpointFuturesPremiumIndex
+		b.endpoints["futuresOpenInterest"] = futuresURL + EndpointFuturesOpenInterest
+		b.endpoints["futuresLeverage"] = futuresURL + EndpointFuturesLeverage
+		b.endpoints["futuresMarginType"] = futuresURL + EndpointFuturesMarginType
 	}
 }
 
@@ -133,6 +155,7 @@ func (b *Binance) FetchMarkets(ctx context.Context, params map[string]interface{
 		endpoint = b.endpoints["exchangeInfo"]
 	}
 
+	ctx = exchanges.WithRequestWeight(ctx, "", b.requestWeight("exchangeInfo", 0))
 	respStr, err := b.FetchWithRetry(ctx, endpoint, "GET", nil, "")
 	if err != nil {
 		return nil, err
@@ -285,6 +308,7 @@ func (b *Binance) FetchTickers(ctx context.Context, symbols []string, params map
 	}
 
 	// 不传symbol参数，获取所有ticker数据
+	ctx = exchanges.WithRequestWeight(ctx, "", b.requestWeight("ticker24hr", 0))
 	respStr, err := b.FetchWithRetry(ctx, endpoint, "GET", nil, "")
 	if err != nil {
 		return nil, err
@@ -341,59 +365,13 @@ func (b *Binance) FetchBookTickers(ctx context.Context, symbols []string, params
 	}
 
 	// 不传symbol参数，获取所有bookTicker数据
+	ctx = exchanges.WithRequestWeight(ctx, "", b.requestWeight("bookTicker", 0))
 	respStr, err := b.FetchWithRetry(ctx, endpoint, "GET", nil, "")
 	if err != nil {
 		return nil, err
 	}
 
-	// 尝试解析为数组（所有bookTicker）
-	var dataArray []interface{}
-	if err := json.Unmarshal([]byte(respStr), &dataArray); err != nil {
-		return nil, fmt.Errorf("解析bookTicker数组失败: %v", err)
-	}
-
-	// 转换为map，便于查找
-	tickers := make(map[string]*types.Ticker)
-	symbolsMap := make(map[string]bool)
-
-	// 如果指定了symbols，创建查找map
-	if len(symbols) > 0 {
-		for _, symbol := range symbols {
-			symbolsMap[symbol] = true
-		}
-	}
-
-	for _, tickerData := range dataArray {
-		tickerMap, ok := tickerData.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		// 获取symbol
-		symbol := b.SafeString(tickerMap, "symbol", "")
-		if symbol == "" {
-			continue
-		}
-
-		// 如果指定了symbols，只处理指定的symbols
-		if len(symbols) > 0 && !symbolsMap[symbol] {
-			continue
-		}
-
-		// 解析bookTicker数据
-		ticker := &types.Ticker{
-			Symbol:    symbol,
-			TimeStamp: b.SafeInteger(tickerMap, "time", time.Now().UnixMilli()),
-			Bid:       b.SafeFloat(tickerMap, "bidPrice", 0),
-			BidVolume: b.SafeFloat(tickerMap, "bidQty", 0),
-			Ask:       b.SafeFloat(tickerMap, "askPrice", 0),
-			AskVolume: b.SafeFloat(tickerMap, "askQty", 0),
-			Info:      tickerMap,
-		}
-		tickers[symbol] = ticker
-	}
-
-	return tickers, nil
+	return decodeBookTickers(respStr, symbols)
 }
 
 // FetchTickersBatch 分批获取ticker数据 - 避免超时
@@ -513,6 +491,7 @@ func (b *Binance) FetchKlines(ctx context.Context, symbol, interval string, sinc
 	}
 
 	// 发送请求
+	ctx = exchanges.WithRequestWeight(ctx, "", b.requestWeight("klines", limit))
 	respStr, err := b.FetchWithRetry(ctx, endpoint, "GET", nil, "")
 	if err != nil {
 		return nil, fmt.Errorf("获取K线数据失败: %w", err)
@@ -608,6 +587,86 @@ func (b *Binance) parseKline(data []interface{}, symbol, interval string) *types
 	}
 }
 
+// ========== 订单簿API ==========
+
+// orderBookLimits Binance支持的深度档位
+var orderBookLimits = []int{5, 10, 20, 50, 100, 500, 1000}
+
+// FetchOrderBook 获取订单簿快照
+func (b *Binance) FetchOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol不能为空")
+	}
+
+	// 向上取最接近的合法档位
+	depth := orderBookLimits[len(orderBookLimits)-1]
+	for _, l := range orderBookLimits {
+		if limit <= l {
+			depth = l
+			break
+		}
+	}
+
+	var endpoint string
+	if b.marketType == types.MarketTypeFuture {
+		endpoint = b.endpoints["futuresDepth"]
+	} else {
+		endpoint = b.endpoints["depth"]
+	}
+	endpoint += fmt.Sprintf("?symbol=%s&limit=%d", symbol, depth)
+
+	ctx = exchanges.WithRequestWeight(ctx, "", b.requestWeight("depth", depth))
+	respStr, err := b.FetchWithRetry(ctx, endpoint, "GET", nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("获取订单簿失败: %w", err)
+	}
+
+	var resp struct {
+		LastUpdateID int64      `json:"lastUpdateId"`
+		E            int64      `json:"E"`
+		Bids         [][]string `json:"bids"`
+		Asks         [][]string `json:"asks"`
+	}
+	if err := json.Unmarshal([]byte(respStr), &resp); err != nil {
+		return nil, fmt.Errorf("解析订单簿失败: %w", err)
+	}
+
+	timestamp := resp.E
+	if timestamp == 0 {
+		timestamp = time.Now().UnixMilli()
+	}
+
+	return &types.OrderBook{
+		Symbol:    symbol,
+		Bids:      parseOrderBookSide(resp.Bids),
+		Asks:      parseOrderBookSide(resp.Asks),
+		TimeStamp: timestamp,
+		Datetime:  time.UnixMilli(timestamp).UTC().Format(time.RFC3339Nano),
+		Nonce:     resp.LastUpdateID,
+	}, nil
+}
+
+// parseOrderBookSide 解析订单簿一侧的 [价格, 数量] 列表
+func parseOrderBookSide(levels [][]string) types.OrderBookSide {
+	side := types.OrderBookSide{
+		Price: make([]float64, 0, len(levels)),
+		Size:  make([]float64, 0, len(levels)),
+	}
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		price, err1 := strconv.ParseFloat(level[0], 64)
+		size, err2 := strconv.ParseFloat(level[1], 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		side.Price = append(side.Price, price)
+		side.Size = append(side.Size, size)
+	}
+	return side
+}
+
 // ========== 标记价格API ==========
 
 // FetchMarkPrice 获取单个交易对的标记价格
@@ -626,12 +685,12 @@ func (b *Binance) FetchMarkPrice(ctx context.Context, symbol string) (*types.Mar
 		return nil, err
 	}
 
-	var data map[string]interface{}
-	if err := json.Unmarshal([]byte(respStr), &data); err != nil {
+	var message premiumIndexMessage
+	if err := json.Unmarshal([]byte(respStr), &message); err != nil {
 		return nil, err
 	}
 
-	return b.parseMarkPrice(data), nil
+	return message.toMarkPrice(time.Now().UnixMilli()), nil
 }
 
 // FetchMarkPrices 获取多个交易对的标记价格
@@ -642,61 +701,116 @@ func (b *Binance) FetchMarkPrices(ctx context.Context, symbols []string) (map[st
 
 	endpoint := b.endpoints["futuresPremiumIndex"]
 
+	ctx = exchanges.WithRequestWeight(ctx, "", b.requestWeight("premiumIndex", 0))
 	respStr, err := b.FetchWithRetry(ctx, endpoint, "GET", nil, "")
 	if err != nil {
 		return nil, err
 	}
 
-	var dataArray []map[string]interface{}
-	if err := json.Unmarshal([]byte(respStr), &dataArray); err != nil {
-		return nil, err
-	}
+	return decodeMarkPrices(respStr, symbols)
+}
 
-	markPrices := make(map[string]*types.MarkPrice)
-	symbolsMap := make(map[string]bool)
+// ========== 持仓量API ==========
 
-	// 如果指定了symbols，创建查找map
-	if len(symbols) > 0 {
-		for _, symbol := range symbols {
-			symbolsMap[symbol] = true
-		}
+// FetchOpenInterest 获取合约当前持仓量
+func (b *Binance) FetchOpenInterest(ctx context.Context, symbol string) (*types.OpenInterest, error) {
+	if b.marketType != types.MarketTypeFuture {
+		return nil, fmt.Errorf("持仓量仅在期货模式下可用")
+	}
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol不能为空")
 	}
 
-	for _, data := range dataArray {
-		symbol := b.SafeString(data, "symbol", "")
-		if symbol == "" {
-			continue
-		}
-
-		// 如果指定了symbols，只处理指定的symbols
-		if len(symbols) > 0 && !symbolsMap[symbol] {
-			continue
-		}
-
-		markPrice := b.parseMarkPrice(data)
-		markPrices[symbol] = markPrice
+	endpoint := b.endpoints["futuresOpenInterest"] + "?symbol=" + symbol
+	respStr, err := b.FetchWithRetry(ctx, endpoint, "GET", nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓量失败: %w", err)
 	}
 
-	return markPrices, nil
-}
+	var resp struct {
+		Symbol       string  `json:"symbol"`
+		OpenInterest decimal `json:"openInterest"`
+		Time         int64   `json:"time"`
+	}
+	if err := json.Unmarshal([]byte(respStr), &resp); err != nil {
+		return nil, fmt.Errorf("解析持仓量失败: %w", err)
+	}
 
-// parseMarkPrice 解析标记价格数据
-func (b *Binance) parseMarkPrice(data map[string]interface{}) *types.MarkPrice {
-	return &types.MarkPrice{
-		Symbol:               b.SafeString(data, "symbol", ""),
-		MarkPrice:            b.SafeFloat(data, "markPrice", 0),
-		IndexPrice:           b.SafeFloat(data, "indexPrice", 0),
-		FundingRate:          b.SafeFloat(data, "lastFundingRate", 0),
-		NextFundingTime:      b.SafeInteger(data, "nextFundingTime", 0),
-		InterestRate:         b.SafeFloat(data, "interestRate", 0),
-		EstimatedSettlePrice: b.SafeFloat(data, "estimatedSettlePrice", 0),
-		Timestamp:            time.Now().UnixMilli(),
-		Info:                 data,
+	timestamp := resp.Time
+	if timestamp == 0 {
+		timestamp = time.Now().UnixMilli()
 	}
+	return &types.OpenInterest{
+		Symbol:       symbol,
+		OpenInterest: float64(resp.OpenInterest),
+		Timestamp:    timestamp,
+	}, nil
 }
 
 // ========== 实用方法 ==========
 
+// requestWeight 按Binance文档计算公共端点的IP权重，limit 用于K线和订单簿
+func (b *Binance) requestWeight(endpoint string, limit int) int {
+	futures := b.marketType == types.MarketTypeFuture
+	switch endpoint {
+	case "exchangeInfo":
+		if futures {
+			return 1
+		}
+		return 20
+	case "ticker24hr":
+		if futures {
+			return 40
+		}
+		return 80
+	case "bookTicker":
+		if futures {
+			return 5
+		}
+		return 4
+	case "premiumIndex":
+		return 10
+	case "klines":
+		if !futures {
+			return 2
+		}
+		switch {
+		case limit <= 0 || limit > 1000:
+			return 10
+		case limit < 100:
+			return 1
+		case limit < 500:
+			return 2
+		default:
+			return 5
+		}
+	case "depth":
+		if futures {
+			switch {
+			case limit <= 50:
+				return 2
+			case limit <= 100:
+				return 5
+			case limit <= 500:
+				return 10
+			default:
+				return 20
+			}
+		}
+		switch {
+		case limit <= 100:
+			return 5
+		case limit <= 500:
+			return 25
+		case limit <= 1000:
+			return 50
+		default:
+			return 250
+		}
+	}
+	return 1
+}
+
 // GetMarketType 获取市场类型
 func (b *Binance) GetMarketType() string {
 	return b.marketType
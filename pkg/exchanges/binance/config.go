@@ -17,6 +17,10 @@ type Config struct {
 
 	// 市场类型配置
 	MarketType string `json:"marketType"` // 市场类型: spot, futures
+
+	// API凭证，仅用于设置杠杆和保证金模式
+	APIKey string `json:"-"`
+	Secret string `json:"-"`
 }
 
 // DefaultConfig 返回默认配置
@@ -69,6 +73,14 @@ func (c *Config) GetFuturesURL() string {
 	return FuturesBaseURL
 }
 
+// GetFuturesStreamURL 获取期货WebSocket组合流地址
+func (c *Config) GetFuturesStreamURL() string {
+	if c.TestNet {
+		return TestNetFuturesStreamURL
+	}
+	return FuturesStreamURL
+}
+
 // IsSpot 是否现货
 func (c *Config) IsSpot() bool {
 	return c.MarketType == types.MarketTypeSpot
@@ -78,3 +90,8 @@ func (c *Config) IsSpot() bool {
 func (c *Config) IsFutures() bool {
 	return c.MarketType == types.MarketTypeFuture
 }
+
+// HasCredentials 是否配置了API凭证
+func (c *Config) HasCredentials() bool {
+	return c.APIKey != "" && c.Secret != ""
+}
@@ -10,6 +10,13 @@ const (
 	TestNetFuturesURL = "https://testnet.binancefuture.com"
 )
 
+// Binance WebSocket 组合流地址
+const (
+	FuturesStreamURL        = "wss://fstream.binance.com/stream"
+	TestNetFuturesStreamURL = "wss://stream.binancefuture.com/stream"
+	MaxStreamsPerConnection = 200 // 期货单个连接最多订阅的流数量
+)
+
 // ========== Binance REST API 端点 ==========
 
 // 现货公共端点
@@ -18,6 +25,7 @@ const (
 	EndpointTicker24hr   = "/api/v3/ticker/24hr"
 	EndpointBookTicker   = "/api/v3/ticker/bookTicker"
 	EndpointKlines       = "/api/v3/klines"
+	EndpointDepth        = "/api/v3/depth"
 	EndpointServerTime   = "/api/v3/time"
 )
 
@@ -27,7 +35,30 @@ const (
 	EndpointFuturesTicker24hr   = "/fapi/v1/ticker/24hr"
 	EndpointFuturesBookTicker   = "/fapi/v1/ticker/bookTicker"
 	EndpointFuturesKlines       = "/fapi/v1/klines"
+	EndpointFuturesDepth        = "/fapi/v1/depth"
 	EndpointFuturesPremiumIndex = "/fapi/v1/premiumIndex"
+	EndpointFuturesOpenInterest = "/fapi/v1/openInterest"
+)
+
+// 期货私有端点（需要签名）
+const (
+	EndpointFuturesLeverage   = "/fapi/v1/leverage"
+	EndpointFuturesMarginType = "/fapi/v1/marginType"
+)
+
+// IP权重预算（每分钟）
+const (
+	FuturesWeightPerMinute = 2400
+	SpotWeightPerMinute    = 6000
+)
+
+// 期货业务常量
+const (
+	MaxLeverage         = 125   // 最大杠杆倍数
+	RecvWindow          = 5000  // 签名请求有效时间窗口(毫秒)
+	ErrCodeNoNeedMargin = -4046 // 保证金模式无需变更
+	MarginTypeIsolated  = "ISOLATED"
+	MarginTypeCrossed   = "CROSSED"
 )
 
 // ========== K线时间间隔 ==========
@@ -0,0 +1,159 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// ========== 热路径类型化解析 ==========
+// premiumIndex 和 bookTicker 按价格更新间隔拉取全市场数据，使用类型化结构体流式解析，
+// 避免每个交易对生成 map[string]interface{}，这两个接口返回的数据不再保留原始 Info
+
+// decimal 兼容字符串和数字两种格式的数值字段，空字符串和null解析为0
+type decimal float64
+
+// UnmarshalJSON 解析数值字段
+func (d *decimal) UnmarshalJSON(data []byte) error {
+	if len(data) >= 2 && data[0] == '"' {
+		data = data[1 : len(data)-1]
+	}
+	if len(data) == 0 || string(data) == "null" {
+		*d = 0
+		return nil
+	}
+	value, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return err
+	}
+	*d = decimal(value)
+	return nil
+}
+
+// premiumIndexMessage /fapi/v1/premiumIndex 单个交易对
+type premiumIndexMessage struct {
+	Symbol               string  `json:"symbol"`
+	MarkPrice            decimal `json:"markPrice"`
+	IndexPrice           decimal `json:"indexPrice"`
+	EstimatedSettlePrice decimal `json:"estimatedSettlePrice"`
+	LastFundingRate      decimal `json:"lastFundingRate"`
+	InterestRate         decimal `json:"interestRate"`
+	NextFundingTime      int64   `json:"nextFundingTime"`
+}
+
+// toMarkPrice 转换为标记价格
+func (m *premiumIndexMessage) toMarkPrice(timestamp int64) *types.MarkPrice {
+	return &types.MarkPrice{
+		Symbol:               m.Symbol,
+		MarkPrice:            float64(m.MarkPrice),
+		IndexPrice:           float64(m.IndexPrice),
+		FundingRate:          float64(m.LastFundingRate),
+		NextFundingTime:      m.NextFundingTime,
+		InterestRate:         float64(m.InterestRate),
+		EstimatedSettlePrice: float64(m.EstimatedSettlePrice),
+		Timestamp:            timestamp,
+	}
+}
+
+// bookTickerMessage /api/v3/ticker/bookTicker 和 /fapi/v1/ticker/bookTicker 单个交易对
+type bookTickerMessage struct {
+	Symbol   string  `json:"symbol"`
+	BidPrice decimal `json:"bidPrice"`
+	BidQty   decimal `json:"bidQty"`
+	AskPrice decimal `json:"askPrice"`
+	AskQty   decimal `json:"askQty"`
+	Time     int64   `json:"time"` // 现货没有该字段
+}
+
+// toTicker 转换为最优买卖价，没有时间字段时使用 timestamp
+func (m *bookTickerMessage) toTicker(timestamp int64) *types.Ticker {
+	if m.Time > 0 {
+		timestamp = m.Time
+	}
+	return &types.Ticker{
+		Symbol:    m.Symbol,
+		TimeStamp: timestamp,
+		Bid:       float64(m.BidPrice),
+		BidVolume: float64(m.BidQty),
+		Ask:       float64(m.AskPrice),
+		AskVolume: float64(m.AskQty),
+	}
+}
+
+// symbolFilter 指定了交易对时只保留这些交易对，为空时保留全部
+func symbolFilter(symbols []string) func(string) bool {
+	if len(symbols) == 0 {
+		return func(string) bool { return true }
+	}
+	wanted := make(map[string]struct{}, len(symbols))
+	for _, symbol := range symbols {
+		wanted[symbol] = struct{}{}
+	}
+	return func(symbol string) bool {
+		_, ok := wanted[symbol]
+		return ok
+	}
+}
+
+// decodeArray 逐个解析JSON数组元素，每个元素解析到同一个 item 后回调
+func decodeArray[T any](body string, item *T, each func(*T)) error {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("期望JSON数组，实际为 %v", token)
+	}
+
+	var zero T
+	for decoder.More() {
+		*item = zero
+		if err := decoder.Decode(item); err != nil {
+			return err
+		}
+		each(item)
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+// decodeMarkPrices 解析全市场 premiumIndex 响应
+func decodeMarkPrices(body string, symbols []string) (map[string]*types.MarkPrice, error) {
+	keep := symbolFilter(symbols)
+	now := time.Now().UnixMilli()
+	markPrices := make(map[string]*types.MarkPrice, len(symbols))
+
+	var message premiumIndexMessage
+	err := decodeArray(body, &message, func(m *premiumIndexMessage) {
+		if m.Symbol != "" && keep(m.Symbol) {
+			markPrices[m.Symbol] = m.toMarkPrice(now)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("解析premiumIndex数组失败: %v", err)
+	}
+	return markPrices, nil
+}
+
+// decodeBookTickers 解析全市场 bookTicker 响应
+func decodeBookTickers(body string, symbols []string) (map[string]*types.Ticker, error) {
+	keep := symbolFilter(symbols)
+	now := time.Now().UnixMilli()
+	tickers := make(map[string]*types.Ticker, len(symbols))
+
+	var message bookTickerMessage
+	err := decodeArray(body, &message, func(m *bookTickerMessage) {
+		if m.Symbol != "" && keep(m.Symbol) {
+			tickers[m.Symbol] = m.toTicker(now)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("解析bookTicker数组失败: %v", err)
+	}
+	return tickers, nil
+}
@@ -0,0 +1,117 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// premiumIndexPayload 生成与全市场 premiumIndex 响应格式一致的数据
+func premiumIndexPayload(count int) string {
+	items := make([]string, count)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"symbol":"SYM%dUSDT","markPrice":"%d.12345678","indexPrice":"%d.12000000","estimatedSettlePrice":"%d.11800000","lastFundingRate":"0.00010000","interestRate":"0.00010000","nextFundingTime":1700000000000,"time":1699999999000}`, i, 100+i, 100+i, 100+i)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func TestDecodeMarkPrices(t *testing.T) {
+	body := `[{"symbol":"BTCUSDT","markPrice":"50000.5","indexPrice":"50001","estimatedSettlePrice":"","lastFundingRate":"","interestRate":"0.0001","nextFundingTime":1700000000000},` +
+		`{"symbol":"ETHUSDT","markPrice":"3000","indexPrice":"3001","lastFundingRate":"-0.0002","nextFundingTime":1700000000000}]`
+
+	markPrices, err := decodeMarkPrices(body, []string{"BTCUSDT"})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(markPrices) != 1 {
+		t.Fatalf("期望只保留1个交易对，实际 %d", len(markPrices))
+	}
+	btc := markPrices["BTCUSDT"]
+	if btc == nil || btc.MarkPrice != 50000.5 || btc.FundingRate != 0 || btc.NextFundingTime != 1700000000000 {
+		t.Errorf("解析结果错误: %+v", btc)
+	}
+
+	if _, err := decodeMarkPrices(`{"code":-1}`, nil); err == nil {
+		t.Error("非数组响应应返回错误")
+	}
+}
+
+func TestAggTradeMessage(t *testing.T) {
+	body := `{"stream":"btcusdt@aggTrade","data":{"e":"aggTrade","E":1700000000100,"s":"BTCUSDT","a":26129,"p":"50000.5","q":"12.5","f":100,"l":105,"T":1700000000000,"m":true}}`
+
+	var message struct {
+		Data aggTradeMessage `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(body), &message); err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	trade := message.Data.toTrade()
+	if trade.Symbol != "BTCUSDT" || trade.ID != "26129" || trade.Side != "sell" || trade.Cost != 625006.25 || trade.Timestamp != 1700000000000 {
+		t.Errorf("解析结果错误: %+v", trade)
+	}
+}
+
+func TestForceOrderMessage(t *testing.T) {
+	body := `{"stream":"btcusdt@forceOrder","data":{"e":"forceOrder","E":1568014460893,"o":{"s":"BTCUSDT","S":"SELL","o":"LIMIT","f":"IOC","q":"0.014","p":"9910","ap":"9900","X":"FILLED","l":"0.014","z":"0.014","T":1568014460893}}}`
+
+	var message struct {
+		Data forceOrderMessage `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(body), &message); err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	liquidation := message.Data.toLiquidation()
+	if liquidation.Symbol != "BTCUSDT" || liquidation.Side != "sell" || liquidation.Amount != 0.014 || liquidation.Notional != 9900*0.014 {
+		t.Errorf("解析结果错误: %+v", liquidation)
+	}
+}
+
+// BenchmarkDecodeMarkPrices 对比 map[string]interface{} 与类型化结构体解析全市场标记价格的分配
+// go test -run ^$ -bench DecodeMarkPrices -benchmem ./pkg/exchanges/binance/
+func BenchmarkDecodeMarkPrices(b *testing.B) {
+	body := premiumIndexPayload(600)
+	symbols := []string{"SYM1USDT", "SYM50USDT", "SYM100USDT", "SYM200USDT", "SYM300USDT", "SYM599USDT"}
+	base := &exchanges.BaseExchange{}
+
+	b.Run("map", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var dataArray []map[string]interface{}
+			if err := json.Unmarshal([]byte(body), &dataArray); err != nil {
+				b.Fatal(err)
+			}
+			wanted := make(map[string]bool, len(symbols))
+			for _, symbol := range symbols {
+				wanted[symbol] = true
+			}
+			markPrices := make(map[string]*types.MarkPrice)
+			for _, data := range dataArray {
+				symbol := base.SafeString(data, "symbol", "")
+				if !wanted[symbol] {
+					continue
+				}
+				markPrices[symbol] = &types.MarkPrice{
+					Symbol:          symbol,
+					MarkPrice:       base.SafeFloat(data, "markPrice", 0),
+					IndexPrice:      base.SafeFloat(data, "indexPrice", 0),
+					FundingRate:     base.SafeFloat(data, "lastFundingRate", 0),
+					NextFundingTime: base.SafeInteger(data, "nextFundingTime", 0),
+					Info:            data,
+				}
+			}
+		}
+	})
+
+	b.Run("typed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeMarkPrices(body, symbols); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package binance
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// ========== 期货私有API（杠杆/保证金模式）==========
+
+// SetLeverage 设置交易对的杠杆倍数
+func (b *Binance) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	if err := b.checkPrivateAccess("setLeverage"); err != nil {
+		return err
+	}
+	if leverage < 1 || leverage > MaxLeverage {
+		return exchanges.NewBadRequest(fmt.Sprintf("杠杆倍数必须在1-%d之间", MaxLeverage))
+	}
+
+	_, err := b.signedRequest(ctx, "POST", b.endpoints["futuresLeverage"], map[string]string{
+		"symbol":   symbol,
+		"leverage": strconv.Itoa(leverage),
+	})
+	return err
+}
+
+// SetMarginMode 设置交易对的保证金模式，leverage 参数Binance不需要
+func (b *Binance) SetMarginMode(ctx context.Context, symbol, marginMode string, leverage int) error {
+	if err := b.checkPrivateAccess("setMarginMode"); err != nil {
+		return err
+	}
+
+	marginType := MarginTypeCrossed
+	switch marginMode {
+	case types.MarginModeCross:
+	case types.MarginModeIsolated:
+		marginType = MarginTypeIsolated
+	default:
+		return exchanges.NewBadRequest("无效的保证金模式: " + marginMode)
+	}
+
+	_, err := b.signedRequest(ctx, "POST", b.endpoints["futuresMarginType"], map[string]string{
+		"symbol":     symbol,
+		"marginType": marginType,
+	})
+	if apiErr, ok := err.(*exchanges.ExchangeError); ok && apiErr.Code == ErrCodeNoNeedMargin {
+		return nil // 已是目标模式
+	}
+	return err
+}
+
+// checkPrivateAccess 检查是否可以调用期货私有API
+func (b *Binance) checkPrivateAccess(feature string) error {
+	if !b.config.IsFutures() {
+		return exchanges.NewNotSupported(feature)
+	}
+	if !b.config.HasCredentials() {
+		return exchanges.NewAuthenticationError("未配置Binance API密钥 (BINANCE_API_KEY / BINANCE_API_SECRET)")
+	}
+	return nil
+}
+
+// signedRequest 发送HMAC-SHA256签名请求，业务错误以 ExchangeError 返回（Code为Binance错误码）
+func (b *Binance) signedRequest(ctx context.Context, method, endpoint string, params map[string]string) (map[string]interface{}, error) {
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	query.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	query.Set("recvWindow", strconv.Itoa(RecvWindow))
+
+	payload := query.Encode()
+	mac := hmac.New(sha256.New, []byte(b.GetSecret()))
+	mac.Write([]byte(payload))
+	payload += "&signature=" + hex.EncodeToString(mac.Sum(nil))
+
+	headers := map[string]string{"X-MBX-APIKEY": b.GetApiKey()}
+	respStr, err := b.Fetch(ctx, endpoint+"?"+payload, method, headers, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(respStr), &data); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	// 出错时返回 {"code": -xxxx, "msg": "..."}，部分成功响应为 {"code": 200}
+	if code := b.SafeInteger(data, "code", 0); code < 0 {
+		apiErr := exchanges.NewExchangeError("binance api error: " + b.SafeString(data, "msg", ""))
+		apiErr.Code = int(code)
+		return nil, apiErr
+	}
+	return data, nil
+}
@@ -0,0 +1,38 @@
+package binance
+
+import (
+	"os"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+func init() {
+	exchanges.Register(exchanges.Descriptor{
+		ID:           "binance",
+		Name:         "Binance",
+		Version:      "v3",
+		Website:      "https://www.binance.com",
+		Countries:    []string{"JP", "MT"},
+		MarketTypes:  []string{types.MarketTypeSpot, types.MarketTypeFuture},
+		Capabilities: []string{"fetchMarkets", "fetchTicker", "fetchBookTicker", "fetchKline", "fetchOrderBook", "fetchMarkPrice"},
+		Constructor: func(marketType string) (interface{}, error) {
+			config := DefaultConfig()
+			config.MarketType = marketType
+
+			// 设置测试网环境
+			if testnet := os.Getenv("BINANCE_TESTNET"); testnet == "true" {
+				config.TestNet = true
+			}
+
+			// 设置API凭证（用于设置杠杆和保证金模式）
+			config.APIKey = os.Getenv("BINANCE_API_KEY")
+			config.Secret = os.Getenv("BINANCE_API_SECRET")
+
+			exchange, err := New(config)
+			if err != nil {
+				return nil, err
+			}
+			return exchange, nil
+		},
+	})
+}
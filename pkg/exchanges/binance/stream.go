@@ -0,0 +1,236 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+
+	"github.com/gorilla/websocket"
+)
+
+// ========== WebSocket 推送 ==========
+
+const (
+	streamReadTimeout   = 5 * time.Minute  // 期货服务端每3分钟发送ping，超过该时长没有任何消息视为连接失效
+	streamMaxBackoff    = 30 * time.Second // 重连最大等待时间
+	streamChannelBuffer = 1024
+	streamPingInterval  = 30 * time.Second // 主动发送ping测量往返延迟的间隔
+)
+
+// aggTradeMessage 期货归集成交推送
+type aggTradeMessage struct {
+	Symbol     string  `json:"s"`
+	ID         int64   `json:"a"`
+	Price      decimal `json:"p"`
+	Quantity   decimal `json:"q"`
+	TradeTime  int64   `json:"T"`
+	BuyerMaker bool    `json:"m"` // 买方为挂单方，即主动卖出
+}
+
+// toTrade 转换为成交记录，Side 为主动成交方向
+func (m *aggTradeMessage) toTrade() *types.Trade {
+	side := "buy"
+	if m.BuyerMaker {
+		side = "sell"
+	}
+	price, amount := float64(m.Price), float64(m.Quantity)
+	return &types.Trade{
+		ID:           strconv.FormatInt(m.ID, 10),
+		Symbol:       m.Symbol,
+		Side:         side,
+		Price:        price,
+		Amount:       amount,
+		Cost:         price * amount,
+		Timestamp:    m.TradeTime,
+		Datetime:     time.UnixMilli(m.TradeTime).UTC().Format(time.RFC3339Nano),
+		TakerOrMaker: "taker",
+	}
+}
+
+// forceOrderMessage 期货强平订单推送
+type forceOrderMessage struct {
+	Order struct {
+		Symbol       string  `json:"s"`
+		Side         string  `json:"S"` // SELL 为多头被强平，BUY 为空头被强平
+		Price        decimal `json:"p"`
+		AveragePrice decimal `json:"ap"`
+		Quantity     decimal `json:"z"` // 累计成交数量
+		TradeTime    int64   `json:"T"`
+	} `json:"o"`
+}
+
+// toLiquidation 转换为强平记录，名义价值按成交均价计算
+func (m *forceOrderMessage) toLiquidation() *types.Liquidation {
+	order := m.Order
+	price := float64(order.AveragePrice)
+	if price <= 0 {
+		price = float64(order.Price)
+	}
+	return &types.Liquidation{
+		Symbol:       order.Symbol,
+		Side:         strings.ToLower(order.Side),
+		Price:        float64(order.Price),
+		AveragePrice: float64(order.AveragePrice),
+		Amount:       float64(order.Quantity),
+		Notional:     price * float64(order.Quantity),
+		Timestamp:    order.TradeTime,
+	}
+}
+
+// WatchTrades 订阅期货归集成交(aggTrade)推送
+// 每个连接最多订阅200个交易对，断线后按指数退避自动重连，ctx 取消后关闭返回的通道
+func (b *Binance) WatchTrades(ctx context.Context, symbols []string) (<-chan *types.Trade, error) {
+	return watchStream(ctx, b, "watchTrades", symbols, "@aggTrade", func(m *aggTradeMessage) (*types.Trade, bool) {
+		return m.toTrade(), m.Symbol != ""
+	})
+}
+
+// WatchLiquidations 订阅期货强平订单(forceOrder)推送，交易所每个交易对每秒最多推送一条
+func (b *Binance) WatchLiquidations(ctx context.Context, symbols []string) (<-chan *types.Liquidation, error) {
+	return watchStream(ctx, b, "watchLiquidations", symbols, "@forceOrder", func(m *forceOrderMessage) (*types.Liquidation, bool) {
+		return m.toLiquidation(), m.Order.Symbol != ""
+	})
+}
+
+// watchStream 按交易对分组建立组合流连接，推送消息的 data 字段解析为 M 后由 convert 转换
+func watchStream[M any, T any](ctx context.Context, b *Binance, feature string, symbols []string, suffix string, convert func(*M) (T, bool)) (<-chan T, error) {
+	if b.marketType != types.MarketTypeFuture {
+		return nil, exchanges.NewNotSupported(feature)
+	}
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("订阅推送的交易对不能为空")
+	}
+
+	out := make(chan T, streamChannelBuffer)
+	var wg sync.WaitGroup
+	for start := 0; start < len(symbols); start += MaxStreamsPerConnection {
+		group := symbols[start:min(start+MaxStreamsPerConnection, len(symbols))]
+		streams := make([]string, len(group))
+		for i, symbol := range group {
+			streams[i] = strings.ToLower(symbol) + suffix
+		}
+		url := b.config.GetFuturesStreamURL() + "?streams=" + strings.Join(streams, "/")
+		stats := exchanges.RegisterStreamConnection(b.GetID(), feature, len(group))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer stats.Close()
+			maintainStream(ctx, b, url, stats, out, convert)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out, nil
+}
+
+// maintainStream 维持一个组合流连接直到 ctx 取消
+func maintainStream[M any, T any](ctx context.Context, b *Binance, url string, stats *exchanges.StreamConnection, out chan<- T, convert func(*M) (T, bool)) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		if received := readStream(ctx, b, url, stats, out, convert); received {
+			backoff = time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, streamMaxBackoff)
+	}
+}
+
+// readStream 建立连接并持续读取推送，连接断开时返回是否收到过数据
+// 每次重连重新读取推送连接配置，压缩开关在下次重连时生效
+func readStream[M any, T any](ctx context.Context, b *Binance, url string, stats *exchanges.StreamConnection, out chan<- T, convert func(*M) (T, bool)) bool {
+	dialer, err := b.WebSocketDialer(stats.Stats().Streams)
+	if err != nil {
+		return false
+	}
+	conn, resp, err := dialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	stats.MarkConnected(strings.Contains(resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate"))
+	defer stats.MarkDisconnected()
+
+	// ctx 取消时关闭连接，结束阻塞的读取
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(10*time.Second))
+	})
+	conn.SetPongHandler(func(data string) error {
+		if sentAt, err := strconv.ParseInt(data, 10, 64); err == nil {
+			stats.ObservePing(time.Since(time.Unix(0, sentAt)))
+		}
+		return nil
+	})
+	go probeStream(ctx, conn)
+
+	received := false
+	for {
+		conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return received
+		}
+		receivedAt := time.Now()
+
+		var message struct {
+			Data json.RawMessage `json:"data"`
+		}
+		var event struct {
+			EventTime int64 `json:"E"`
+		}
+		var payload M
+		if err := json.Unmarshal(data, &message); err != nil || json.Unmarshal(message.Data, &payload) != nil {
+			continue
+		}
+		json.Unmarshal(message.Data, &event)
+		stats.ObserveEvent(event.EventTime, receivedAt)
+
+		value, ok := convert(&payload)
+		if !ok {
+			continue
+		}
+
+		select {
+		case out <- value:
+			received = true
+		case <-ctx.Done():
+			return received
+		}
+	}
+}
+
+// probeStream 定时发送携带发送时间的ping，收到pong时计算往返延迟，连接关闭后写入失败退出
+func probeStream(ctx context.Context, conn *websocket.Conn) {
+	ticker := time.NewTicker(streamPingInterval)
+	defer ticker.Stop()
+
+	for {
+		payload := strconv.FormatInt(time.Now().UnixNano(), 10)
+		if err := conn.WriteControl(websocket.PingMessage, []byte(payload), time.Now().Add(10*time.Second)); err != nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,539 @@
+package bitget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// Bitget 实现交易所接口 (仅公共市场数据，U本位合约)
+type Bitget struct {
+	*exchanges.BaseExchange
+	config      *Config
+	productType string // 产品类型：USDT-FUTURES
+
+	endpoints map[string]string
+}
+
+// apiResponse Bitget 通用响应
+type apiResponse struct {
+	Code string          `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// New 创建新的Bitget实例
+func New(config *Config) (*Bitget, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	base := exchanges.NewBaseExchange("bitget", "Bitget", "v2", []string{"SC"})
+	base.SetTimeout(time.Duration(config.Timeout) * time.Millisecond)
+	bitget := &Bitget{
+		BaseExchange: base,
+		config:       config.Clone(),
+		productType:  config.ProductType,
+		endpoints:    make(map[string]string),
+	}
+
+	bitget.setCapabilities()
+	bitget.setEndpoints()
+	bitget.BaseExchange.SetRetryConfig(3, 100*time.Millisecond, 10*time.Second, true)
+	bitget.BaseExchange.EnableRetry()
+
+	return bitget, nil
+}
+
+// setCapabilities 设置支持的功能
+func (b *Bitget) setCapabilities() {
+	capabilities := map[string]bool{
+		"fetchMarkets":   true,
+		"fetchTicker":    true,
+		"fetchTickers":   true,
+		"fetchKline":     true,
+		"fetchOrderBook": true,
+		"fetchMarkPrice": true,
+	}
+
+	timeframes := map[string]string{
+		"1m": Interval1m, "3m": Interval3m, "5m": Interval5m,
+		"15m": Interval15m, "30m": Interval30m,
+		"1h": Interval1H, "4h": Interval4H, "6h": Interval6H, "12h": Interval12H,
+		"1d": Interval1D, "1w": Interval1W, "1M": Interval1M,
+	}
+
+	for k, v := range capabilities {
+		b.BaseExchange.Has()[k] = v
+	}
+	for k, v := range timeframes {
+		b.BaseExchange.GetTimeframes()[k] = v
+	}
+}
+
+// setEndpoints 设置API端点
+func (b *Bitget) setEndpoints() {
+	baseURL := b.config.GetBaseURL()
+	b.endpoints["base"] = baseURL
+	b.endpoints["contracts"] = baseURL + EndpointContracts
+	b.endpoints["tickers"] = baseURL + EndpointTickers
+	b.endpoints["ticker"] = baseURL + EndpointTicker
+	b.endpoints["klines"] = baseURL + EndpointKlines
+	b.endpoints["orderbook"] = baseURL + EndpointOrderBook
+}
+
+// buildQuery 构建查询字符串
+func (b *Bitget) buildQuery(params map[string]interface{}) string {
+	if len(params) == 0 {
+		return ""
+	}
+
+	var keys []string
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, params[k]))
+	}
+	return strings.Join(parts, "&")
+}
+
+// get 发送GET请求并将 data 字段解析到 result
+func (b *Bitget) get(ctx context.Context, endpoint string, params map[string]interface{}, result interface{}) error {
+	if query := b.buildQuery(params); query != "" {
+		endpoint += "?" + query
+	}
+
+	respStr, err := b.FetchWithRetry(ctx, endpoint, "GET", nil, "")
+	if err != nil {
+		return err
+	}
+
+	var resp apiResponse
+	if err := json.Unmarshal([]byte(respStr), &resp); err != nil {
+		return err
+	}
+	if resp.Code != ResponseCodeSuccess {
+		return fmt.Errorf("bitget api error: %s", resp.Msg)
+	}
+	return json.Unmarshal(resp.Data, result)
+}
+
+// GetMarketType 获取市场类型
+func (b *Bitget) GetMarketType() string {
+	return b.config.MarketType
+}
+
+// IsTestnet 是否测试网
+func (b *Bitget) IsTestnet() bool {
+	return false // Bitget公共API无测试网区分
+}
+
+// ========== 市场数据API ==========
+
+// FetchMarkets 获取市场信息
+// 支持 params["quote"] 筛选报价货币，如 params["quote"] = "USDT"
+func (b *Bitget) FetchMarkets(ctx context.Context, params map[string]interface{}) ([]*types.Market, error) {
+	// 获取筛选参数（在修改 params 之前）
+	var quoteFilter string
+	if params != nil {
+		if q, ok := params["quote"].(string); ok {
+			quoteFilter = q
+			delete(params, "quote") // 从 params 中删除，避免传给 API
+		}
+	}
+
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+	params["productType"] = b.productType
+
+	var data []map[string]interface{}
+	if err := b.get(ctx, b.endpoints["contracts"], params, &data); err != nil {
+		return nil, err
+	}
+
+	var markets []*types.Market
+	for _, item := range data {
+		market := b.parseMarket(item)
+		if market == nil {
+			continue
+		}
+		// 应用 quote 筛选
+		if quoteFilter != "" && market.Quote != quoteFilter {
+			continue
+		}
+		markets = append(markets, market)
+	}
+	return markets, nil
+}
+
+// parseMarket 解析市场信息
+func (b *Bitget) parseMarket(data map[string]interface{}) *types.Market {
+	symbol := b.SafeString(data, "symbol", "")
+	if symbol == "" {
+		return nil
+	}
+
+	status := b.SafeString(data, "symbolStatus", "")
+	if status != "normal" {
+		return nil
+	}
+
+	baseCoin := b.SafeString(data, "baseCoin", "")
+	quoteCoin := b.SafeString(data, "quoteCoin", "")
+	isSwap := b.SafeString(data, "symbolType", "") == "perpetual"
+
+	// 价格步长 = priceEndStep * 10^-pricePlace
+	pricePlace := b.SafeFloat(data, "pricePlace", 0)
+	priceStep := b.SafeFloat(data, "priceEndStep", 1) * math.Pow10(-int(pricePlace))
+
+	return &types.Market{
+		ID:           symbol,
+		Symbol:       fmt.Sprintf("%s/%s", baseCoin, quoteCoin),
+		Base:         baseCoin,
+		Quote:        quoteCoin,
+		Settle:       quoteCoin,
+		Type:         b.config.MarketType,
+		Active:       true,
+		Future:       true,
+		Swap:         isSwap,
+		Contract:     true,
+		Linear:       true,
+		Maker:        b.SafeFloat(data, "makerFeeRate", 0),
+		Taker:        b.SafeFloat(data, "takerFeeRate", 0),
+		ContractSize: b.SafeFloat(data, "sizeMultiplier", 0),
+		Info:         data,
+		Precision: types.MarketPrecision{
+			Price:  pricePlace,
+			Amount: b.SafeFloat(data, "volumePlace", 0),
+		},
+		Limits: types.MarketLimits{
+			Leverage: types.LimitRange{
+				Min: b.SafeFloat(data, "minLever", 0),
+				Max: b.SafeFloat(data, "maxLever", 0),
+			},
+			Amount: types.LimitRange{
+				Min:  b.SafeFloat(data, "minTradeNum", 0),
+				Step: b.SafeFloat(data, "sizeMultiplier", 0),
+			},
+			Price: types.LimitRange{
+				Step: priceStep,
+			},
+			Cost: types.LimitRange{
+				Min: b.SafeFloat(data, "minTradeUSDT", 0),
+			},
+		},
+	}
+}
+
+// FetchTickers 批量获取ticker
+func (b *Bitget) FetchTickers(ctx context.Context, symbols []string, params map[string]interface{}) (map[string]*types.Ticker, error) {
+	data, err := b.fetchRawTickers(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	tickers := make(map[string]*types.Ticker)
+	symbolsMap := make(map[string]bool)
+	for _, s := range symbols {
+		symbolsMap[s] = true
+	}
+
+	for _, item := range data {
+		symbol := b.SafeString(item, "symbol", "")
+		if symbol == "" {
+			continue
+		}
+		if len(symbols) > 0 && !symbolsMap[symbol] {
+			continue
+		}
+		tickers[symbol] = b.parseTicker(item)
+	}
+	return tickers, nil
+}
+
+// FetchBookTickers 获取最优买卖价
+func (b *Bitget) FetchBookTickers(ctx context.Context, symbols []string, params map[string]interface{}) (map[string]*types.Ticker, error) {
+	return b.FetchTickers(ctx, symbols, params)
+}
+
+// fetchRawTickers 获取全部合约的原始ticker数据
+func (b *Bitget) fetchRawTickers(ctx context.Context, params map[string]interface{}) ([]map[string]interface{}, error) {
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+	params["productType"] = b.productType
+
+	var data []map[string]interface{}
+	if err := b.get(ctx, b.endpoints["tickers"], params, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// parseTicker 解析ticker数据
+func (b *Bitget) parseTicker(data map[string]interface{}) *types.Ticker {
+	ts := b.SafeInteger(data, "ts", 0)
+	lastPrice := b.SafeFloat(data, "lastPr", 0)
+	openPrice := b.SafeFloat(data, "open24h", 0)
+
+	// 计算涨跌幅
+	change := lastPrice - openPrice
+	percentage := 0.0
+	if openPrice > 0 {
+		percentage = (change / openPrice) * 100
+	}
+
+	return &types.Ticker{
+		Symbol:      b.SafeString(data, "symbol", ""),
+		TimeStamp:   ts,
+		Datetime:    b.ISO8601(ts),
+		High:        b.SafeFloat(data, "high24h", 0),
+		Low:         b.SafeFloat(data, "low24h", 0),
+		Bid:         b.SafeFloat(data, "bidPr", 0),
+		BidVolume:   b.SafeFloat(data, "bidSz", 0),
+		Ask:         b.SafeFloat(data, "askPr", 0),
+		AskVolume:   b.SafeFloat(data, "askSz", 0),
+		Open:        openPrice,
+		Last:        lastPrice,
+		Close:       lastPrice,
+		Change:      change,
+		Percentage:  percentage,
+		BaseVolume:  b.SafeFloat(data, "baseVolume", 0),
+		QuoteVolume: b.SafeFloat(data, "quoteVolume", 0),
+		Info:        data,
+	}
+}
+
+// FetchKlines 获取K线数据
+func (b *Bitget) FetchKlines(ctx context.Context, symbol, interval string, since int64, limit int, params map[string]interface{}) ([]*types.Kline, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol不能为空")
+	}
+
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+	params["symbol"] = symbol
+	params["productType"] = b.productType
+	params["granularity"] = b.convertInterval(interval)
+
+	if limit > 0 {
+		if limit > 1000 {
+			limit = 1000 // Bitget最大限制
+		}
+		params["limit"] = limit
+	}
+
+	if since > 0 {
+		params["startTime"] = since
+	}
+
+	var data [][]interface{}
+	if err := b.get(ctx, b.endpoints["klines"], params, &data); err != nil {
+		return nil, err
+	}
+
+	// Bitget返回数据按时间升序排列
+	klines := make([]*types.Kline, 0, len(data))
+	for _, item := range data {
+		kline := b.parseKline(item, symbol, interval)
+		if kline != nil {
+			klines = append(klines, kline)
+		}
+	}
+	return klines, nil
+}
+
+// parseKline 解析K线数据
+func (b *Bitget) parseKline(data []interface{}, symbol, interval string) *types.Kline {
+	if len(data) < 6 {
+		return nil
+	}
+
+	// Bitget K线格式: [ts, open, high, low, close, baseVolume, quoteVolume]
+	return &types.Kline{
+		Symbol:    symbol,
+		Timeframe: interval,
+		Timestamp: int64(toFloat64(data[0])),
+		Open:      toFloat64(data[1]),
+		High:      toFloat64(data[2]),
+		Low:       toFloat64(data[3]),
+		Close:     toFloat64(data[4]),
+		Volume:    toFloat64(data[5]),
+		IsClosed:  true,
+	}
+}
+
+// convertInterval 转换时间周期格式
+func (b *Bitget) convertInterval(interval string) string {
+	if converted, ok := b.BaseExchange.GetTimeframes()[interval]; ok {
+		return converted
+	}
+	return interval
+}
+
+// ========== 订单簿API ==========
+
+// FetchOrderBook 获取订单簿
+func (b *Bitget) FetchOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol不能为空")
+	}
+
+	// Bitget 仅支持 1/5/15/50/max 档
+	depth := "max"
+	switch {
+	case limit <= 0:
+		depth = "50"
+	case limit <= 1:
+		depth = "1"
+	case limit <= 5:
+		depth = "5"
+	case limit <= 15:
+		depth = "15"
+	case limit <= 50:
+		depth = "50"
+	}
+
+	var data struct {
+		Asks [][]interface{} `json:"asks"`
+		Bids [][]interface{} `json:"bids"`
+		Ts   string          `json:"ts"`
+	}
+	params := map[string]interface{}{
+		"symbol":      symbol,
+		"productType": b.productType,
+		"limit":       depth,
+	}
+	if err := b.get(ctx, b.endpoints["orderbook"], params, &data); err != nil {
+		return nil, fmt.Errorf("获取订单簿失败: %w", err)
+	}
+
+	timestamp, _ := strconv.ParseInt(data.Ts, 10, 64)
+	if timestamp == 0 {
+		timestamp = time.Now().UnixMilli()
+	}
+
+	return &types.OrderBook{
+		Symbol:    symbol,
+		Bids:      parseOrderBookSide(data.Bids, limit),
+		Asks:      parseOrderBookSide(data.Asks, limit),
+		TimeStamp: timestamp,
+		Datetime:  time.UnixMilli(timestamp).UTC().Format(time.RFC3339Nano),
+	}, nil
+}
+
+// parseOrderBookSide 解析订单簿单边档位，limit 大于0时截断到指定档数
+func parseOrderBookSide(levels [][]interface{}, limit int) types.OrderBookSide {
+	if limit > 0 && len(levels) > limit {
+		levels = levels[:limit]
+	}
+
+	side := types.OrderBookSide{
+		Price: make([]float64, 0, len(levels)),
+		Size:  make([]float64, 0, len(levels)),
+	}
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		side.Price = append(side.Price, toFloat64(level[0]))
+		side.Size = append(side.Size, toFloat64(level[1]))
+	}
+	return side
+}
+
+// ========== 标记价格API ==========
+
+// FetchMarkPrice 获取单个交易对的标记价格
+func (b *Bitget) FetchMarkPrice(ctx context.Context, symbol string) (*types.MarkPrice, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol不能为空")
+	}
+
+	params := map[string]interface{}{
+		"symbol":      symbol,
+		"productType": b.productType,
+	}
+
+	var data []map[string]interface{}
+	if err := b.get(ctx, b.endpoints["ticker"], params, &data); err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("未找到交易对 %s 的标记价格", symbol)
+	}
+
+	return b.parseMarkPrice(data[0]), nil
+}
+
+// FetchMarkPrices 获取多个交易对的标记价格
+func (b *Bitget) FetchMarkPrices(ctx context.Context, symbols []string) (map[string]*types.MarkPrice, error) {
+	// 合约ticker中包含标记价格、指数价格和资金费率
+	data, err := b.fetchRawTickers(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*types.MarkPrice)
+	symbolsMap := make(map[string]bool)
+	for _, s := range symbols {
+		symbolsMap[s] = true
+	}
+
+	for _, item := range data {
+		symbol := b.SafeString(item, "symbol", "")
+		if symbol == "" {
+			continue
+		}
+		if len(symbols) > 0 && !symbolsMap[symbol] {
+			continue
+		}
+		result[symbol] = b.parseMarkPrice(item)
+	}
+	return result, nil
+}
+
+// parseMarkPrice 从ticker数据解析标记价格
+func (b *Bitget) parseMarkPrice(data map[string]interface{}) *types.MarkPrice {
+	timestamp := b.SafeInteger(data, "ts", 0)
+	if timestamp == 0 {
+		timestamp = time.Now().UnixMilli()
+	}
+
+	return &types.MarkPrice{
+		Symbol:      b.SafeString(data, "symbol", ""),
+		MarkPrice:   b.SafeFloat(data, "markPrice", 0),
+		IndexPrice:  b.SafeFloat(data, "indexPrice", 0),
+		FundingRate: b.SafeFloat(data, "fundingRate", 0),
+		Timestamp:   timestamp,
+		Info:        data,
+	}
+}
+
+// ========== 实用方法 ==========
+
+// toFloat64 将字符串或数字转换为float64
+func toFloat64(v interface{}) float64 {
+	switch val := v.(type) {
+	case string:
+		if n, err := strconv.ParseFloat(val, 64); err == nil {
+			return n
+		}
+	case float64:
+		return val
+	}
+	return 0
+}
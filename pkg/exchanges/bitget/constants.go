@@ -0,0 +1,43 @@
+package bitget
+
+// ========== Bitget API 基础URL ==========
+
+const (
+	BaseURL = "https://api.bitget.com"
+)
+
+// ========== Bitget 公共数据端点 (U本位合约) ==========
+
+const (
+	EndpointContracts = "/api/v2/mix/market/contracts"
+	EndpointTickers   = "/api/v2/mix/market/tickers"
+	EndpointTicker    = "/api/v2/mix/market/ticker"
+	EndpointKlines    = "/api/v2/mix/market/candles"
+	EndpointOrderBook = "/api/v2/mix/market/merge-depth"
+)
+
+// ========== Bitget 产品类型常数 ==========
+
+const (
+	ProductTypeUSDTFutures = "USDT-FUTURES" // U本位合约
+)
+
+// ResponseCodeSuccess 请求成功的返回码
+const ResponseCodeSuccess = "00000"
+
+// ========== Bitget 时间周期常数 ==========
+
+const (
+	Interval1m  = "1m"
+	Interval3m  = "3m"
+	Interval5m  = "5m"
+	Interval15m = "15m"
+	Interval30m = "30m"
+	Interval1H  = "1H"
+	Interval4H  = "4H"
+	Interval6H  = "6H"
+	Interval12H = "12H"
+	Interval1D  = "1D"
+	Interval1W  = "1W"
+	Interval1M  = "1M"
+)
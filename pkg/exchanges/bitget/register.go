@@ -0,0 +1,31 @@
+package bitget
+
+import (
+	"fmt"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+func init() {
+	exchanges.Register(exchanges.Descriptor{
+		ID:           "bitget",
+		Name:         "Bitget",
+		Version:      "v2",
+		Website:      "https://www.bitget.com",
+		Countries:    []string{"SC"},
+		MarketTypes:  []string{types.MarketTypeFuture},
+		Capabilities: []string{"fetchMarkets", "fetchTicker", "fetchTickers", "fetchKline", "fetchOrderBook", "fetchMarkPrice"},
+		Constructor: func(marketType string) (interface{}, error) {
+			config := DefaultConfig()
+			if err := config.SetMarketType(marketType); err != nil {
+				return nil, fmt.Errorf("设置Bitget市场类型失败: %w", err)
+			}
+
+			exchange, err := New(config)
+			if err != nil {
+				return nil, err
+			}
+			return exchange, nil
+		},
+	})
+}
@@ -34,12 +34,16 @@ func New(config *Config) (*Bybit, error) {
 	}
 
 	base := exchanges.NewBaseExchange("bybit", "Bybit", "v5", []string{"VG"})
+	base.SetTimeout(time.Duration(config.Timeout) * time.Millisecond)
 	bybit := &Bybit{
 		BaseExchange: base,
 		config:       config.Clone(),
 		category:     config.Category,
 		endpoints:    make(map[string]string),
 	}
+	if config.HasCredentials() {
+		base.SetCredentials(config.APIKey, config.Secret, "", "")
+	}
 
 	// 设置基础信息
 	bybit.setBasicInfo()
@@ -57,17 +61,24 @@ func New(config *Config) (*Bybit, error) {
 func (b *Bybit) setBasicInfo() {
 	b.BaseExchange.SetRetryConfig(3, 100*time.Millisecond, 10*time.Second, true)
 	b.BaseExchange.EnableRetry()
+
+	// Bybit 按IP限制每5秒600次请求，剩余次数通过 X-Bapi-Limit-Status 校准
+	b.BaseExchange.SetRateLimitBudget("", IPRequestsPer5s, 5*time.Second)
 }
 
 // setCapabilities 设置支持的功能
 func (b *Bybit) setCapabilities() {
 	capabilities := map[string]bool{
-		"fetchMarkets":    true,
-		"fetchTicker":     true,
-		"fetchBookTicker": true,
-		"fetchKline":      true,
-		"fetchMarkPrice":  b.config.IsFutures(),
-		"fetchMarkPrices": b.config.IsFutures(),
+		"fetchMarkets":      true,
+		"fetchTicker":       true,
+		"fetchBookTicker":   true,
+		"fetchKline":        true,
+		"fetchOrderBook":    true,
+		"fetchMarkPrice":    b.config.IsFutures(),
+		"fetchMarkPrices":   b.config.IsFutures(),
+		"setLeverage":       b.config.IsFutures() && b.config.HasCredentials(),
+		"setMarginMode":     b.config.IsFutures() && b.config.HasCredentials(),
+		"fetchOpenInterest": b.config.IsFutures(),
 	}
 
 	// 设置时间周期
@@ -106,6 +117,13 @@ func (b *Bybit) setEndpoints() {
 	b.endpoints["instrumentsInfo"] = baseURL + EndpointInstrumentsInfo
 	b.endpoints["tickers"] = baseURL + EndpointTickers
 	b.endpoints["kline"] = baseURL + EndpointKline
+	b.endpoints["orderbook"] = baseURL + EndpointOrderbook
+	b.endpoints["openInterest"] = baseURL + EndpointOpenInterest
+
+	// 持仓私有端点
+	privateURL := b.config.GetPrivateBaseURL()
+	b.endpoints["setLeverage"] = privateURL + EndpointSetLeverage
+	b.endpoints["switchIsolated"] = privateURL + EndpointSwitchIsolated
 }
 
 // buildQuery 构建查询字符串
@@ -542,6 +560,151 @@ func (b *Bybit) parseKline(data []interface{}, symbol, interval string) *types.K
 	}
 }
 
+// ========== 订单簿API ==========
+
+// FetchOrderBook 获取订单簿快照
+func (b *Bybit) FetchOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol不能为空")
+	}
+
+	// 现货最多200档，合约最多500档
+	maxLimit := 500
+	if !b.config.IsFutures() {
+		maxLimit = 200
+	}
+	if limit <= 0 {
+		limit = 25
+	} else if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	endpoint := b.endpoints["orderbook"] + "?" + b.buildQuery(map[string]interface{}{
+		"category": b.category,
+		"symbol":   symbol,
+		"limit":    limit,
+	})
+
+	respStr, err := b.FetchWithRetry(ctx, endpoint, "GET", nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("获取订单簿失败: %w", err)
+	}
+
+	var resp struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			Symbol string     `json:"s"`
+			Bids   [][]string `json:"b"`
+			Asks   [][]string `json:"a"`
+			Ts     int64      `json:"ts"`
+			U      int64      `json:"u"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(respStr), &resp); err != nil {
+		return nil, fmt.Errorf("解析订单簿失败: %w", err)
+	}
+
+	if resp.RetCode != 0 {
+		return nil, fmt.Errorf("bybit api error: %s", resp.RetMsg)
+	}
+
+	timestamp := resp.Result.Ts
+	if timestamp == 0 {
+		timestamp = time.Now().UnixMilli()
+	}
+
+	return &types.OrderBook{
+		Symbol:    symbol,
+		Bids:      parseOrderBookSide(resp.Result.Bids),
+		Asks:      parseOrderBookSide(resp.Result.Asks),
+		TimeStamp: timestamp,
+		Datetime:  time.UnixMilli(timestamp).UTC().Format(time.RFC3339Nano),
+		Nonce:     resp.Result.U,
+	}, nil
+}
+
+// parseOrderBookSide 解析订单簿一侧的 [价格, 数量] 列表
+func parseOrderBookSide(levels [][]string) types.OrderBookSide {
+	side := types.OrderBookSide{
+		Price: make([]float64, 0, len(levels)),
+		Size:  make([]float64, 0, len(levels)),
+	}
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		price, err1 := strconv.ParseFloat(level[0], 64)
+		size, err2 := strconv.ParseFloat(level[1], 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		side.Price = append(side.Price, price)
+		side.Size = append(side.Size, size)
+	}
+	return side
+}
+
+// ========== 持仓量API ==========
+
+// FetchOpenInterest 获取合约最新持仓量（5分钟统计周期的最新一条）
+func (b *Bybit) FetchOpenInterest(ctx context.Context, symbol string) (*types.OpenInterest, error) {
+	if !b.config.IsFutures() {
+		return nil, fmt.Errorf("持仓量仅在期货模式下可用")
+	}
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol不能为空")
+	}
+
+	endpoint := b.endpoints["openInterest"] + "?" + b.buildQuery(map[string]interface{}{
+		"category":     b.category,
+		"symbol":       symbol,
+		"intervalTime": "5min",
+		"limit":        1,
+	})
+
+	respStr, err := b.FetchWithRetry(ctx, endpoint, "GET", nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓量失败: %w", err)
+	}
+
+	var resp struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			List []struct {
+				OpenInterest string `json:"openInterest"`
+				Timestamp    string `json:"timestamp"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(respStr), &resp); err != nil {
+		return nil, fmt.Errorf("解析持仓量失败: %w", err)
+	}
+
+	if resp.RetCode != 0 {
+		return nil, fmt.Errorf("bybit api error: %s", resp.RetMsg)
+	}
+	if len(resp.Result.List) == 0 {
+		return nil, fmt.Errorf("未找到交易对 %s 的持仓量", symbol)
+	}
+
+	item := resp.Result.List[0]
+	openInterest, err := strconv.ParseFloat(item.OpenInterest, 64)
+	if err != nil {
+		return nil, fmt.Errorf("解析持仓量失败: %w", err)
+	}
+	timestamp, err := strconv.ParseInt(item.Timestamp, 10, 64)
+	if err != nil || timestamp == 0 {
+		timestamp = time.Now().UnixMilli()
+	}
+	return &types.OpenInterest{
+		Symbol:       symbol,
+		OpenInterest: openInterest,
+		Timestamp:    timestamp,
+	}, nil
+}
+
 // ========== 标记价格API ==========
 
 // FetchMarkPrice 获取单个交易对的标记价格
@@ -725,9 +888,9 @@ func (b *Bybit) GetCategory() string {
 	return b.category
 }
 
-// IsTestnet 是否测试网
+// IsTestnet 是否测试网或模拟交易账户
 func (b *Bybit) IsTestnet() bool {
-	return b.config.TestNet
+	return b.config.TestNet || b.config.Demo
 }
 
 // GetConfig 获取配置
@@ -11,6 +11,7 @@ import (
 type Config struct {
 	// 环境配置
 	TestNet bool `json:"testnet"` // 是否使用测试网
+	Demo    bool `json:"demo"`    // 是否使用模拟交易账户，行情仍使用主网
 
 	// 网络配置
 	Timeout int `json:"timeout"` // 超时时间(毫秒)
@@ -20,6 +21,10 @@ type Config struct {
 
 	// Bybit 特有配置
 	Category string `json:"category"` // 产品类型: spot, linear, inverse
+
+	// API凭证，仅用于设置杠杆和保证金模式
+	APIKey string `json:"-"`
+	Secret string `json:"-"`
 }
 
 // DefaultConfig 返回默认配置
@@ -37,6 +42,9 @@ func (c *Config) Validate() error {
 	if c.Timeout < 0 {
 		return fmt.Errorf("timeout cannot be negative")
 	}
+	if c.TestNet && c.Demo {
+		return fmt.Errorf("testnet and demo cannot be enabled at the same time")
+	}
 
 	// 验证市场类型
 	validTypes := map[string]bool{
@@ -112,6 +120,14 @@ func (c *Config) GetBaseURL() string {
 	return BaseURL
 }
 
+// GetPrivateBaseURL 获取私有API基础URL，模拟交易账户使用独立域名，API密钥需在主网的模拟交易页面创建
+func (c *Config) GetPrivateBaseURL() string {
+	if c.Demo {
+		return DemoBaseURL
+	}
+	return c.GetBaseURL()
+}
+
 // IsSpot 是否现货
 func (c *Config) IsSpot() bool {
 	return c.Category == CategorySpot
@@ -131,3 +147,8 @@ func (c *Config) IsLinear() bool {
 func (c *Config) IsInverse() bool {
 	return c.Category == CategoryInverse
 }
+
+// HasCredentials 是否配置了API凭证
+func (c *Config) HasCredentials() bool {
+	return c.APIKey != "" && c.Secret != ""
+}
@@ -6,6 +6,7 @@ package bybit
 const (
 	BaseURL        = "https://api.bybit.com"
 	TestNetBaseURL = "https://api-testnet.bybit.com"
+	DemoBaseURL    = "https://api-demo.bybit.com" // 模拟交易(Demo Trading)，只提供账户和交易相关的私有端点
 )
 
 // ========== Bybit REST API 端点 ==========
@@ -15,9 +16,29 @@ const (
 	EndpointInstrumentsInfo = "/v5/market/instruments-info" // 交易规则查询
 	EndpointTickers         = "/v5/market/tickers"          // 24小时价格统计
 	EndpointKline           = "/v5/market/kline"            // K线数据
+	EndpointOrderbook       = "/v5/market/orderbook"        // 订单簿深度
+	EndpointOpenInterest    = "/v5/market/open-interest"    // 合约持仓量
 	EndpointServerTime      = "/v5/market/time"             // 服务器时间
 )
 
+// 持仓私有端点（需要签名）
+const (
+	EndpointSetLeverage    = "/v5/position/set-leverage"    // 设置杠杆
+	EndpointSwitchIsolated = "/v5/position/switch-isolated" // 切换全仓/逐仓
+)
+
+// IPRequestsPer5s 每个IP每5秒允许的请求次数
+const IPRequestsPer5s = 600
+
+// 私有API常量
+const (
+	RecvWindow                 = "5000" // 签名请求有效时间窗口(毫秒)
+	RetCodeLeverageNotModified = 110043 // 杠杆无需变更
+	RetCodeMarginNotModified   = 110026 // 保证金模式无需变更
+	TradeModeCross             = 0      // 全仓
+	TradeModeIsolated          = 1      // 逐仓
+)
+
 // ========== Bybit 业务常量 ==========
 
 // 产品类型
@@ -0,0 +1,117 @@
+package bybit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// ========== 持仓私有API（杠杆/保证金模式）==========
+
+// SetLeverage 设置交易对的杠杆倍数（多空相同）
+func (b *Bybit) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	if err := b.checkPrivateAccess("setLeverage"); err != nil {
+		return err
+	}
+	if leverage < 1 {
+		return exchanges.NewBadRequest("杠杆倍数必须大于0")
+	}
+
+	err := b.signedPost(ctx, b.endpoints["setLeverage"], map[string]interface{}{
+		"category":     b.category,
+		"symbol":       symbol,
+		"buyLeverage":  strconv.Itoa(leverage),
+		"sellLeverage": strconv.Itoa(leverage),
+	})
+	if apiErr, ok := err.(*exchanges.ExchangeError); ok && apiErr.Code == RetCodeLeverageNotModified {
+		return nil // 已是目标杠杆
+	}
+	return err
+}
+
+// SetMarginMode 设置交易对的保证金模式，Bybit切换时需要同时指定杠杆
+func (b *Bybit) SetMarginMode(ctx context.Context, symbol, marginMode string, leverage int) error {
+	if err := b.checkPrivateAccess("setMarginMode"); err != nil {
+		return err
+	}
+	if leverage < 1 {
+		return exchanges.NewBadRequest("杠杆倍数必须大于0")
+	}
+
+	tradeMode := TradeModeCross
+	switch marginMode {
+	case types.MarginModeCross:
+	case types.MarginModeIsolated:
+		tradeMode = TradeModeIsolated
+	default:
+		return exchanges.NewBadRequest("无效的保证金模式: " + marginMode)
+	}
+
+	err := b.signedPost(ctx, b.endpoints["switchIsolated"], map[string]interface{}{
+		"category":     b.category,
+		"symbol":       symbol,
+		"tradeMode":    tradeMode,
+		"buyLeverage":  strconv.Itoa(leverage),
+		"sellLeverage": strconv.Itoa(leverage),
+	})
+	if apiErr, ok := err.(*exchanges.ExchangeError); ok && apiErr.Code == RetCodeMarginNotModified {
+		return nil // 已是目标模式
+	}
+	return err
+}
+
+// checkPrivateAccess 检查是否可以调用持仓私有API
+func (b *Bybit) checkPrivateAccess(feature string) error {
+	if !b.config.IsFutures() {
+		return exchanges.NewNotSupported(feature)
+	}
+	if !b.config.HasCredentials() {
+		return exchanges.NewAuthenticationError("未配置Bybit API密钥 (BYBIT_API_KEY / BYBIT_API_SECRET)")
+	}
+	return nil
+}
+
+// signedPost 发送v5签名POST请求，业务错误以 ExchangeError 返回（Code为retCode）
+// 签名串: timestamp + apiKey + recvWindow + body
+func (b *Bybit) signedPost(ctx context.Context, endpoint string, params map[string]interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(b.GetSecret()))
+	mac.Write([]byte(timestamp + b.GetApiKey() + RecvWindow + string(body)))
+
+	headers := map[string]string{
+		"X-BAPI-API-KEY":     b.GetApiKey(),
+		"X-BAPI-TIMESTAMP":   timestamp,
+		"X-BAPI-RECV-WINDOW": RecvWindow,
+		"X-BAPI-SIGN":        hex.EncodeToString(mac.Sum(nil)),
+	}
+	respStr, err := b.Fetch(ctx, endpoint, "POST", headers, string(body))
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+	}
+	if err := json.Unmarshal([]byte(respStr), &resp); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	if resp.RetCode != 0 {
+		apiErr := exchanges.NewExchangeError("bybit api error: " + resp.RetMsg)
+		apiErr.Code = resp.RetCode
+		return apiErr
+	}
+	return nil
+}
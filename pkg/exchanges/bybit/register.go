@@ -0,0 +1,45 @@
+package bybit
+
+import (
+	"fmt"
+	"os"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+func init() {
+	exchanges.Register(exchanges.Descriptor{
+		ID:           "bybit",
+		Name:         "Bybit",
+		Version:      "v5",
+		Website:      "https://www.bybit.com",
+		Countries:    []string{"VG"},
+		MarketTypes:  []string{types.MarketTypeSpot, types.MarketTypeFuture},
+		Capabilities: []string{"fetchMarkets", "fetchTicker", "fetchBookTicker", "fetchKline", "fetchOrderBook", "fetchMarkPrice"},
+		Constructor: func(marketType string) (interface{}, error) {
+			config := DefaultConfig()
+			if err := config.SetMarketType(marketType); err != nil {
+				return nil, fmt.Errorf("设置Bybit市场类型失败: %w", err)
+			}
+
+			// 设置测试网环境
+			if testnet := os.Getenv("BYBIT_TESTNET"); testnet == "true" {
+				config.TestNet = true
+			}
+			// 设置模拟交易账户，杠杆和保证金模式发送到模拟交易域名
+			if demo := os.Getenv("BYBIT_DEMO"); demo == "true" {
+				config.Demo = true
+			}
+
+			// 设置API凭证（用于设置杠杆和保证金模式）
+			config.APIKey = os.Getenv("BYBIT_API_KEY")
+			config.Secret = os.Getenv("BYBIT_API_SECRET")
+
+			exchange, err := New(config)
+			if err != nil {
+				return nil, err
+			}
+			return exchange, nil
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package exchanges
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -324,7 +325,8 @@ func NewInvalidPrice(price, min, max float64) *InvalidPrice {
 // NotSupported 功能不支持错误
 type NotSupported struct {
 	*BaseError
-	Feature string `json:"feature"`
+	Feature  string `json:"feature"`
+	Exchange string `json:"exchange,omitempty"`
 }
 
 func NewNotSupported(feature string) *NotSupported {
@@ -337,6 +339,12 @@ func NewNotSupported(feature string) *NotSupported {
 	}
 }
 
+// IsNotSupported 检查错误（含包装后的错误）是否为功能不支持
+func IsNotSupported(err error) bool {
+	var notSupported *NotSupported
+	return errors.As(err, &notSupported)
+}
+
 // ========== 参数和请求错误 ==========
 
 // BadRequest 错误请求
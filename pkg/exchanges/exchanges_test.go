@@ -3,9 +3,11 @@ package exchanges_test
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
+	"trading_assistant/pkg/exchanges"
 	"trading_assistant/pkg/exchanges/binance"
 	"trading_assistant/pkg/exchanges/bybit"
 	"trading_assistant/pkg/exchanges/mexc"
@@ -683,6 +685,64 @@ func printFirstN(markets []*types.Market, n int) {
 	}
 }
 
+// ========== 连接池基准测试 ==========
+
+// BenchmarkBinanceFetchMarkPricesBurst 对比连接复用与每主机仅保留1个空闲连接时的突发请求耗时
+// go test -run ^$ -bench FetchMarkPricesBurst ./pkg/exchanges/
+func BenchmarkBinanceFetchMarkPricesBurst(b *testing.B) {
+	symbols := []string{"BTCUSDT", "ETHUSDT", "SOLUSDT", "BNBUSDT", "XRPUSDT", "DOGEUSDT", "ADAUSDT", "LINKUSDT"}
+
+	pooled := exchanges.DefaultHTTPTransportConfig()
+	unpooled := exchanges.DefaultHTTPTransportConfig()
+	unpooled.MaxIdleConnsPerHost = 1
+	unpooled.TLSSessionCacheSize = 0
+
+	for _, bc := range []struct {
+		name      string
+		transport exchanges.HTTPTransportConfig
+	}{
+		{"pooled", pooled},
+		{"idle_per_host_1", unpooled},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			config := binance.DefaultConfig()
+			config.MarketType = types.MarketTypeFuture
+
+			exchange, err := binance.New(config)
+			if err != nil {
+				b.Fatalf("创建 Binance 期货实例失败: %v", err)
+			}
+			exchange.SetRateLimitEnabled(false)
+			if err := exchange.SetHTTPTransportConfig(bc.transport); err != nil {
+				b.Fatalf("设置连接池失败: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var wg sync.WaitGroup
+				errs := make(chan error, len(symbols))
+				for _, symbol := range symbols {
+					wg.Add(1)
+					go func(symbol string) {
+						defer wg.Done()
+						if _, err := exchange.FetchMarkPrice(ctx, symbol); err != nil {
+							errs <- err
+						}
+					}(symbol)
+				}
+				wg.Wait()
+				close(errs)
+				if err, ok := <-errs; ok {
+					b.Fatalf("获取标记价格失败: %v", err)
+				}
+			}
+		})
+	}
+}
+
 // ========== 辅助函数 ==========
 
 func min(a, b int) int {
@@ -0,0 +1,70 @@
+package fake
+
+import (
+	"fmt"
+	"time"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// Config 模拟交易所配置，行情完全在本地按脚本生成，不访问网络
+type Config struct {
+	MarketType     string            `json:"marketType"`
+	Markets        string            `json:"markets"`        // 交易对和初始价格，格式 BTC=65000,ETH=3200
+	Script         string            `json:"script"`         // 所有交易对默认的价格路径脚本
+	Scripts        map[string]string `json:"scripts"`        // 按基础货币覆盖的价格路径脚本
+	Loop           bool              `json:"loop"`           // 脚本结束后是否回到初始价格重新开始
+	Noise          float64           `json:"noise"`          // 叠加在路径上的随机波动幅度，如0.0005表示±0.05%
+	Spread         float64           `json:"spread"`         // 买卖价差占价格的比例
+	Seed           int64             `json:"seed"`           // 随机波动的种子，相同种子和脚本生成相同的行情
+	StreamInterval time.Duration     `json:"streamInterval"` // 推送间隔
+	StartTime      time.Time         `json:"startTime"`      // 脚本开始时间，为空时使用创建时间
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		MarketType:     types.MarketTypeFuture,
+		Markets:        DefaultMarkets,
+		Scripts:        make(map[string]string),
+		Loop:           true,
+		Noise:          0.0005,
+		Spread:         0.0002,
+		Seed:           1,
+		StreamInterval: defaultStreamInterval,
+	}
+}
+
+// Validate 验证配置，同时检查交易对和脚本能否解析
+func (c *Config) Validate() error {
+	if c.MarketType != types.MarketTypeSpot && c.MarketType != types.MarketTypeFuture {
+		return fmt.Errorf("invalid marketType: %s, must be 'spot' or 'future'", c.MarketType)
+	}
+	if c.Noise < 0 || c.Spread < 0 {
+		return fmt.Errorf("noise and spread cannot be negative")
+	}
+	if c.StreamInterval <= 0 {
+		return fmt.Errorf("streamInterval must be positive")
+	}
+	if _, err := parseMarkets(c.Markets); err != nil {
+		return err
+	}
+	if _, err := ParseScript(c.Script); err != nil {
+		return err
+	}
+	for base, script := range c.Scripts {
+		if _, err := ParseScript(script); err != nil {
+			return fmt.Errorf("%s 的价格路径脚本错误: %w", base, err)
+		}
+	}
+	return nil
+}
+
+// Clone 克隆配置
+func (c *Config) Clone() *Config {
+	clone := *c
+	clone.Scripts = make(map[string]string, len(c.Scripts))
+	for base, script := range c.Scripts {
+		clone.Scripts[base] = script
+	}
+	return &clone
+}
@@ -0,0 +1,45 @@
+package fake
+
+import "time"
+
+// ========== 默认行情 ==========
+
+const (
+	// DefaultMarkets 默认的交易对和初始价格，格式 基础货币=价格
+	DefaultMarkets = "BTC=65000,ETH=3200,SOL=150,DOGE=0.15"
+	// QuoteAsset 计价货币
+	QuoteAsset = "USDT"
+)
+
+// ========== 价格路径片段 ==========
+
+const (
+	SegmentRamp  = "ramp"  // 在时长内线性涨跌到目标幅度，如 ramp:+5%:10m
+	SegmentSpike = "spike" // 在时长内冲高/下探后回到原价，如 spike:-3%:30s
+	SegmentGap   = "gap"   // 瞬间跳空，如 gap:+2%
+	SegmentHold  = "hold"  // 保持价格不变，如 hold:5m
+)
+
+// ========== 推送和合成数据 ==========
+
+const (
+	streamChannelBuffer   = 1024
+	orderBookDepth        = 20    // 合成订单簿每侧档位数
+	maxKlines             = 1500  // 单次最多返回的K线数量
+	klineSamples          = 12    // 合成一根K线时在周期内采样的价格点数
+	liquidationMoveRatio  = 0.005 // 相邻两次推送价格变化超过该比例时合成一笔强平订单
+	fundingInterval       = 8 * time.Hour
+	defaultFundingRate    = 0.0001
+	defaultStreamInterval = time.Second
+)
+
+// ========== 时间周期 ==========
+
+// intervalDurations 支持的K线周期
+var intervalDurations = map[string]time.Duration{
+	"1m": time.Minute, "3m": 3 * time.Minute, "5m": 5 * time.Minute,
+	"15m": 15 * time.Minute, "30m": 30 * time.Minute,
+	"1h": time.Hour, "2h": 2 * time.Hour, "4h": 4 * time.Hour,
+	"6h": 6 * time.Hour, "8h": 8 * time.Hour, "12h": 12 * time.Hour,
+	"1d": 24 * time.Hour, "3d": 72 * time.Hour, "1w": 7 * 24 * time.Hour,
+}
@@ -0,0 +1,424 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// Fake 模拟交易所，行情按脚本化的价格路径在本地生成，用于集成测试和无网络的本地开发
+// 实现 ExchangeInterface 以及订单簿、持仓量、价格/成交/强平推送、下单、持仓、杠杆和保证金模式等可选能力
+type Fake struct {
+	*exchanges.BaseExchange
+	config *Config
+	paths  map[string]*PricePath // MarketID -> 价格路径
+	bases  map[string]string     // MarketID -> 基础货币
+
+	mu          sync.Mutex
+	orders      map[string]*types.Order
+	positions   map[string]*types.Position // MarketID -> 单向净持仓
+	leverages   map[string]int
+	marginModes map[string]string
+	orderSeq    int64
+}
+
+// New 创建新的模拟交易所实例
+func New(config *Config) (*Fake, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	config = config.Clone()
+	if config.StartTime.IsZero() {
+		config.StartTime = time.Now()
+	}
+
+	markets, _ := parseMarkets(config.Markets)
+	defaultSegments, _ := ParseScript(config.Script)
+
+	fake := &Fake{
+		BaseExchange: exchanges.NewBaseExchange("fake", "Fake Exchange", "v1", []string{}),
+		config:       config,
+		paths:        make(map[string]*PricePath, len(markets)),
+		bases:        make(map[string]string, len(markets)),
+		orders:       make(map[string]*types.Order),
+		positions:    make(map[string]*types.Position),
+		leverages:    make(map[string]int),
+		marginModes:  make(map[string]string),
+	}
+	for base, basePrice := range markets {
+		segments := defaultSegments
+		if script, exists := config.Scripts[base]; exists {
+			segments, _ = ParseScript(script)
+		}
+		marketID := base + QuoteAsset
+		fake.paths[marketID] = newPricePath(marketID, basePrice, segments, config.Loop, config.Noise, config.Seed, config.StartTime)
+		fake.bases[marketID] = base
+	}
+
+	fake.setCapabilities()
+	return fake, nil
+}
+
+// setCapabilities 设置支持的功能，期货相关能力只在期货模式下开启
+func (f *Fake) setCapabilities() {
+	futures := f.config.MarketType == types.MarketTypeFuture
+	capabilities := map[string]bool{
+		"fetchMarkets":      true,
+		"fetchTicker":       true,
+		"fetchTickers":      true,
+		"fetchKline":        true,
+		"fetchOrderBook":    true,
+		"fetchMarkPrice":    futures,
+		"watchMarkPrices":   true,
+		"watchTrades":       true,
+		"watchLiquidations": futures,
+		"fetchOpenInterest": futures,
+		"createOrder":       true,
+		"fetchPositions":    futures,
+		"setLeverage":       futures,
+		"setMarginMode":     futures,
+	}
+	for k, v := range capabilities {
+		f.BaseExchange.Has()[k] = v
+	}
+	for k := range intervalDurations {
+		f.BaseExchange.GetTimeframes()[k] = k
+	}
+}
+
+// GetMarketType 获取市场类型
+func (f *Fake) GetMarketType() string {
+	return f.config.MarketType
+}
+
+// IsTestnet 是否测试网，模拟交易所始终视为测试环境
+func (f *Fake) IsTestnet() bool {
+	return true
+}
+
+// GetConfig 获取配置
+func (f *Fake) GetConfig() *Config {
+	return f.config
+}
+
+// ========== 市场数据 ==========
+
+// FetchMarkets 获取配置的交易对
+func (f *Fake) FetchMarkets(ctx context.Context, params map[string]interface{}) ([]*types.Market, error) {
+	futures := f.config.MarketType == types.MarketTypeFuture
+	markets := make([]*types.Market, 0, len(f.paths))
+	for _, marketID := range f.marketIDs() {
+		path := f.paths[marketID]
+		base := f.bases[marketID]
+		tick := tickSize(path.basePrice)
+		step := stepSize(path.basePrice)
+
+		market := &types.Market{
+			ID:     marketID,
+			Symbol: fmt.Sprintf("%s/%s", base, QuoteAsset),
+			Base:   base,
+			Quote:  QuoteAsset,
+			Type:   f.config.MarketType,
+			Active: true,
+			Spot:   !futures,
+			Future: futures,
+			Swap:   futures,
+			Taker:  0.0005,
+			Maker:  0.0002,
+			Precision: types.MarketPrecision{
+				Amount: step,
+				Price:  tick,
+			},
+			Limits: types.MarketLimits{
+				Amount: types.LimitRange{Min: step, Max: step * 1e7, Step: step},
+				Price:  types.LimitRange{Min: tick, Max: path.basePrice * 1000, Step: tick},
+				Cost:   types.LimitRange{Min: 5},
+			},
+			Info: map[string]interface{}{"onboardDate": float64(f.config.StartTime.UnixMilli())},
+		}
+		if futures {
+			market.Settle = QuoteAsset
+			market.Contract = true
+			market.Linear = true
+			market.ContractSize = 1
+			market.Limits.Leverage = types.LimitRange{Min: 1, Max: 125}
+		}
+		markets = append(markets, market)
+	}
+	return markets, nil
+}
+
+// FetchTickers 批量获取24小时行情，symbols 为空时返回所有交易对
+func (f *Fake) FetchTickers(ctx context.Context, symbols []string, params map[string]interface{}) (map[string]*types.Ticker, error) {
+	now := time.Now()
+	tickers := make(map[string]*types.Ticker)
+	for _, marketID := range f.selectMarketIDs(symbols) {
+		tickers[marketID] = f.ticker(marketID, now)
+	}
+	return tickers, nil
+}
+
+// FetchBookTickers 获取最优买卖价
+func (f *Fake) FetchBookTickers(ctx context.Context, symbols []string, params map[string]interface{}) (map[string]*types.Ticker, error) {
+	return f.FetchTickers(ctx, symbols, params)
+}
+
+// ticker 合成单个交易对的行情，24小时统计按5分钟采样价格路径
+func (f *Fake) ticker(marketID string, now time.Time) *types.Ticker {
+	path := f.paths[marketID]
+	last := f.price(marketID, now)
+	bid, ask := f.bidAsk(marketID, last)
+
+	open := f.price(marketID, now.Add(-24*time.Hour))
+	high, low := math.Max(open, last), math.Min(open, last)
+	var volume float64
+	for t := now.Add(-24 * time.Hour); t.Before(now); t = t.Add(5 * time.Minute) {
+		price := f.price(marketID, t)
+		high, low = math.Max(high, price), math.Min(low, price)
+		volume += f.volumeAt(path, t, 5*time.Minute)
+	}
+
+	change := last - open
+	return &types.Ticker{
+		Symbol:      marketID,
+		TimeStamp:   now.UnixMilli(),
+		Datetime:    now.UTC().Format(time.RFC3339),
+		High:        high,
+		Low:         low,
+		Bid:         bid,
+		BidVolume:   f.levelSize(path, now, 0),
+		Ask:         ask,
+		AskVolume:   f.levelSize(path, now, 1),
+		Open:        open,
+		Close:       last,
+		Last:        last,
+		Change:      change,
+		Percentage:  change / open * 100,
+		Average:     (open + last) / 2,
+		BaseVolume:  volume,
+		QuoteVolume: volume * (open + last) / 2,
+		Info:        map[string]interface{}{},
+	}
+}
+
+// FetchKlines 按价格路径合成K线，since 为0时返回截至当前的最近 limit 根
+func (f *Fake) FetchKlines(ctx context.Context, symbol, interval string, since int64, limit int, params map[string]interface{}) ([]*types.Kline, error) {
+	path, err := f.path(symbol)
+	if err != nil {
+		return nil, err
+	}
+	duration, ok := intervalDurations[interval]
+	if !ok {
+		return nil, fmt.Errorf("不支持的时间周期: %s", interval)
+	}
+	if limit <= 0 || limit > maxKlines {
+		limit = maxKlines
+	}
+
+	now := time.Now()
+	step := duration.Milliseconds()
+	current := now.UnixMilli() / step * step
+	start := current - int64(limit-1)*step
+	if since > 0 {
+		start = (since + step - 1) / step * step
+	}
+
+	klines := make([]*types.Kline, 0, limit)
+	for openTime := start; openTime <= current && len(klines) < limit; openTime += step {
+		klines = append(klines, f.kline(path, interval, openTime, duration, now))
+	}
+	return klines, nil
+}
+
+// kline 合成单根K线，未收盘的K线只采样到当前时间
+func (f *Fake) kline(path *PricePath, interval string, openTime int64, duration time.Duration, now time.Time) *types.Kline {
+	begin := time.UnixMilli(openTime)
+	end := begin.Add(duration - time.Millisecond)
+	closed := !end.After(now)
+	if !closed {
+		end = now
+	}
+
+	open := f.price(path.symbol, begin)
+	high, low := open, open
+	for i := 1; i <= klineSamples; i++ {
+		price := f.price(path.symbol, begin.Add(end.Sub(begin)*time.Duration(i)/klineSamples))
+		high, low = math.Max(high, price), math.Min(low, price)
+	}
+	return &types.Kline{
+		Symbol:    path.symbol,
+		Timeframe: interval,
+		Timestamp: openTime,
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     f.price(path.symbol, end),
+		Volume:    f.volumeAt(path, begin, end.Sub(begin)),
+		IsClosed:  closed,
+	}
+}
+
+// FetchMarkPrice 获取标记价格和资金费率（仅期货）
+func (f *Fake) FetchMarkPrice(ctx context.Context, symbol string) (*types.MarkPrice, error) {
+	if f.config.MarketType != types.MarketTypeFuture {
+		return nil, fmt.Errorf("标记价格仅在期货模式下可用")
+	}
+	if _, err := f.path(symbol); err != nil {
+		return nil, err
+	}
+	return f.markPrice(strings.ToUpper(symbol), time.Now()), nil
+}
+
+// FetchMarkPrices 批量获取标记价格（仅期货），symbols 为空时返回所有交易对
+func (f *Fake) FetchMarkPrices(ctx context.Context, symbols []string) (map[string]*types.MarkPrice, error) {
+	if f.config.MarketType != types.MarketTypeFuture {
+		return nil, fmt.Errorf("标记价格仅在期货模式下可用")
+	}
+	now := time.Now()
+	result := make(map[string]*types.MarkPrice)
+	for _, marketID := range f.selectMarketIDs(symbols) {
+		result[marketID] = f.markPrice(marketID, now)
+	}
+	return result, nil
+}
+
+// markPrice 合成标记价格，指数价格略低于标记价格，资金费率围绕默认值小幅波动
+func (f *Fake) markPrice(marketID string, now time.Time) *types.MarkPrice {
+	path := f.paths[marketID]
+	price := f.price(marketID, now)
+	hour := now.Unix() / 3600
+	fundingRate := defaultFundingRate * (0.5 + path.random(hour, "funding"))
+	nextFunding := now.Truncate(fundingInterval).Add(fundingInterval)
+
+	return &types.MarkPrice{
+		Symbol:          marketID,
+		MarkPrice:       price,
+		IndexPrice:      roundTo(price*(1-fundingRate), tickSize(path.basePrice)),
+		FundingRate:     fundingRate,
+		NextFundingTime: nextFunding.UnixMilli(),
+		Timestamp:       now.UnixMilli(),
+		Info:            map[string]interface{}{},
+	}
+}
+
+// FetchOrderBook 以当前价格为中心合成订单簿
+func (f *Fake) FetchOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	path, err := f.path(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > orderBookDepth {
+		limit = orderBookDepth
+	}
+
+	now := time.Now()
+	bid, ask := f.bidAsk(path.symbol, f.price(path.symbol, now))
+	tick := tickSize(path.basePrice)
+	book := &types.OrderBook{
+		Symbol:    path.symbol,
+		TimeStamp: now.UnixMilli(),
+		Datetime:  now.UTC().Format(time.RFC3339),
+		Nonce:     now.UnixNano(),
+		Info:      map[string]interface{}{},
+	}
+	for i := 0; i < limit; i++ {
+		book.Bids.Price = append(book.Bids.Price, roundTo(bid-float64(i)*tick, tick))
+		book.Bids.Size = append(book.Bids.Size, f.levelSize(path, now, 2*i))
+		book.Asks.Price = append(book.Asks.Price, roundTo(ask+float64(i)*tick, tick))
+		book.Asks.Size = append(book.Asks.Size, f.levelSize(path, now, 2*i+1))
+	}
+	return book, nil
+}
+
+// FetchOpenInterest 合成合约持仓量，约为每小时成交量的10倍并随时间缓慢波动
+func (f *Fake) FetchOpenInterest(ctx context.Context, symbol string) (*types.OpenInterest, error) {
+	if f.config.MarketType != types.MarketTypeFuture {
+		return nil, fmt.Errorf("持仓量仅在期货模式下可用")
+	}
+	path, err := f.path(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	amount := f.volumeAt(path, now.Add(-time.Hour), time.Hour) * 10 * (0.9 + 0.2*path.random(now.Unix()/60, "oi"))
+	return &types.OpenInterest{
+		Symbol:       path.symbol,
+		OpenInterest: amount,
+		Value:        amount * f.price(path.symbol, now),
+		Timestamp:    now.UnixMilli(),
+	}, nil
+}
+
+// ========== 实用方法 ==========
+
+// path 查找交易对的价格路径
+func (f *Fake) path(symbol string) (*PricePath, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol不能为空")
+	}
+	path, exists := f.paths[strings.ToUpper(symbol)]
+	if !exists {
+		return nil, exchanges.NewMarketNotFound(symbol)
+	}
+	return path, nil
+}
+
+// price 按价格步长取整后的价格
+func (f *Fake) price(marketID string, t time.Time) float64 {
+	path := f.paths[marketID]
+	return roundTo(path.PriceAt(t), tickSize(path.basePrice))
+}
+
+// bidAsk 按配置的价差计算买卖价，至少相差一个价格步长
+func (f *Fake) bidAsk(marketID string, price float64) (float64, float64) {
+	tick := tickSize(f.paths[marketID].basePrice)
+	half := math.Max(price*f.config.Spread/2, tick/2)
+	bid := roundTo(price-half, tick)
+	ask := roundTo(price+half, tick)
+	if ask <= bid {
+		ask = roundTo(bid+tick, tick)
+	}
+	return bid, ask
+}
+
+// volumeAt 合成时间段内的成交量，约为每分钟1万USDT
+func (f *Fake) volumeAt(path *PricePath, start time.Time, duration time.Duration) float64 {
+	minutes := duration.Minutes()
+	return roundTo(10000/path.basePrice*minutes*(0.5+path.random(start.Unix()/60, "volume")), stepSize(path.basePrice))
+}
+
+// levelSize 合成订单簿某一档的数量
+func (f *Fake) levelSize(path *PricePath, now time.Time, level int) float64 {
+	size := 1000 / path.basePrice * (1 + 4*path.random(now.Unix()*100+int64(level), "book"))
+	return roundTo(size, stepSize(path.basePrice))
+}
+
+// marketIDs 按字母排序的所有交易对
+func (f *Fake) marketIDs() []string {
+	markets := make(map[string]float64, len(f.paths))
+	for marketID, path := range f.paths {
+		markets[marketID] = path.basePrice
+	}
+	return sortedKeys(markets)
+}
+
+// selectMarketIDs 过滤出已配置的交易对，symbols 为空时返回所有交易对
+func (f *Fake) selectMarketIDs(symbols []string) []string {
+	if len(symbols) == 0 {
+		return f.marketIDs()
+	}
+	result := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		marketID := strings.ToUpper(symbol)
+		if _, exists := f.paths[marketID]; exists {
+			result = append(result, marketID)
+		}
+	}
+	return result
+}
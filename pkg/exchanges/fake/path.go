@@ -0,0 +1,223 @@
+package fake
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Segment 价格路径片段
+type Segment struct {
+	Kind     string        // ramp, spike, gap, hold
+	Change   float64       // 相对片段开始时价格的涨跌幅，0.05表示+5%
+	Duration time.Duration // 片段时长，gap 为0
+}
+
+// ParseScript 解析价格路径脚本，片段以逗号分隔并按顺序执行，如 ramp:+5%:10m,hold:5m,spike:-3%:30s,gap:+2%
+func ParseScript(script string) ([]Segment, error) {
+	var segments []Segment
+	for _, item := range strings.Split(script, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		kind := strings.ToLower(parts[0])
+
+		var segment Segment
+		var err error
+		switch kind {
+		case SegmentRamp, SegmentSpike:
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("%s 格式应为 %s:涨跌幅:时长", item, kind)
+			}
+			if segment.Change, err = parseChange(parts[1]); err != nil {
+				return nil, fmt.Errorf("%s: %w", item, err)
+			}
+			if segment.Duration, err = time.ParseDuration(parts[2]); err != nil || segment.Duration <= 0 {
+				return nil, fmt.Errorf("%s: 时长必须为正的时间长度", item)
+			}
+		case SegmentGap:
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("%s 格式应为 gap:涨跌幅", item)
+			}
+			if segment.Change, err = parseChange(parts[1]); err != nil {
+				return nil, fmt.Errorf("%s: %w", item, err)
+			}
+		case SegmentHold:
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("%s 格式应为 hold:时长", item)
+			}
+			if segment.Duration, err = time.ParseDuration(parts[1]); err != nil || segment.Duration <= 0 {
+				return nil, fmt.Errorf("%s: 时长必须为正的时间长度", item)
+			}
+		default:
+			return nil, fmt.Errorf("未知的价格路径片段 %s，可选 %s/%s/%s/%s", parts[0], SegmentRamp, SegmentSpike, SegmentGap, SegmentHold)
+		}
+		segment.Kind = kind
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// parseChange 解析涨跌幅，支持 +5%、-0.03 两种写法
+func parseChange(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	percent := strings.HasSuffix(value, "%")
+	change, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("无效的涨跌幅 %s", value)
+	}
+	if percent {
+		change /= 100
+	}
+	if change <= -1 {
+		return 0, fmt.Errorf("跌幅不能达到或超过100%%")
+	}
+	return change, nil
+}
+
+// parseMarkets 解析交易对和初始价格，返回 基础货币 -> 初始价格
+func parseMarkets(value string) (map[string]float64, error) {
+	markets := make(map[string]float64)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		base, price, found := strings.Cut(item, "=")
+		if !found {
+			return nil, fmt.Errorf("交易对配置 %s 格式应为 基础货币=价格", item)
+		}
+		basePrice, err := strconv.ParseFloat(strings.TrimSpace(price), 64)
+		if err != nil || basePrice <= 0 {
+			return nil, fmt.Errorf("交易对 %s 的初始价格必须为正数", base)
+		}
+		markets[strings.ToUpper(strings.TrimSpace(base))] = basePrice
+	}
+	if len(markets) == 0 {
+		return nil, fmt.Errorf("至少需要配置一个交易对")
+	}
+	return markets, nil
+}
+
+// PricePath 单个交易对的确定性价格路径
+// 价格只取决于时间：脚本开始前保持初始价格，之后按片段依次变化，叠加按种子生成的随机波动
+type PricePath struct {
+	symbol    string
+	basePrice float64
+	segments  []Segment
+	total     time.Duration // 脚本总时长
+	loop      bool
+	noise     float64
+	seed      uint64
+	start     time.Time
+}
+
+// newPricePath 创建价格路径
+func newPricePath(symbol string, basePrice float64, segments []Segment, loop bool, noise float64, seed int64, start time.Time) *PricePath {
+	path := &PricePath{
+		symbol:    symbol,
+		basePrice: basePrice,
+		segments:  segments,
+		loop:      loop,
+		noise:     noise,
+		seed:      uint64(seed),
+		start:     start,
+	}
+	for _, segment := range segments {
+		path.total += segment.Duration
+	}
+	return path
+}
+
+// PriceAt 指定时间的价格
+func (p *PricePath) PriceAt(t time.Time) float64 {
+	return p.levelAt(t) * (1 + p.noiseAt(t))
+}
+
+// levelAt 不含随机波动的价格
+func (p *PricePath) levelAt(t time.Time) float64 {
+	elapsed := t.Sub(p.start)
+	if elapsed < 0 || len(p.segments) == 0 {
+		return p.basePrice
+	}
+	if p.loop && p.total > 0 {
+		elapsed %= p.total
+	}
+
+	level := p.basePrice
+	for _, segment := range p.segments {
+		if segment.Kind == SegmentGap {
+			level *= 1 + segment.Change
+			continue
+		}
+		if elapsed < segment.Duration {
+			progress := float64(elapsed) / float64(segment.Duration)
+			switch segment.Kind {
+			case SegmentRamp:
+				return level * (1 + segment.Change*progress)
+			case SegmentSpike:
+				// 前半段到达极值，后半段回到原价
+				return level * (1 + segment.Change*(1-math.Abs(2*progress-1)))
+			default:
+				return level
+			}
+		}
+		elapsed -= segment.Duration
+		if segment.Kind == SegmentRamp {
+			level *= 1 + segment.Change
+		}
+	}
+	return level
+}
+
+// noiseAt 按秒生成的确定性随机波动，范围 ±noise
+func (p *PricePath) noiseAt(t time.Time) float64 {
+	if p.noise <= 0 {
+		return 0
+	}
+	return p.noise * (2*p.random(t.Unix(), "noise") - 1)
+}
+
+// random 按交易对、时间和用途生成 [0,1) 的确定性随机数
+func (p *PricePath) random(bucket int64, purpose string) float64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d|%s|%s|%d", p.seed, p.symbol, purpose, bucket)
+	return float64(h.Sum64()>>11) / float64(1<<53)
+}
+
+// tickSize 按初始价格推算的价格步长，约为价格的十万分之一
+func tickSize(basePrice float64) float64 {
+	return math.Pow(10, math.Floor(math.Log10(basePrice))-5)
+}
+
+// stepSize 按初始价格推算的数量步长，约为10 USDT对应的数量
+func stepSize(basePrice float64) float64 {
+	return math.Min(1, math.Pow(10, math.Floor(math.Log10(10/basePrice))))
+}
+
+// roundTo 按步长四舍五入，并去掉浮点误差
+func roundTo(value, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	decimals := int(math.Max(0, -math.Floor(math.Log10(step))))
+	rounded := math.Round(value/step) * step
+	result, _ := strconv.ParseFloat(strconv.FormatFloat(rounded, 'f', decimals, 64), 64)
+	return result
+}
+
+// sortedKeys 按字母排序的键
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,266 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// ========== 模拟下单和持仓 ==========
+// 订单和持仓只保存在内存中，重启后清空；持仓按交易对单向净额计算
+
+// CreateOrder 下单，市价单和可立即成交的限价单按当前买卖价全部成交，其余限价单挂单等待价格到达
+func (f *Fake) CreateOrder(ctx context.Context, symbol, orderType, side string, amount, price float64, params map[string]interface{}) (*types.Order, error) {
+	path, err := f.path(symbol)
+	if err != nil {
+		return nil, err
+	}
+	side = strings.ToUpper(side)
+	if side != types.OrderSideBuy && side != types.OrderSideSell {
+		return nil, exchanges.NewInvalidOrder("无效的订单方向", side)
+	}
+	orderType = strings.ToLower(orderType)
+	if orderType != types.OrderTypeMarket && orderType != types.OrderTypeLimit {
+		return nil, exchanges.NewInvalidOrder("模拟交易所只支持市价单和限价单", orderType)
+	}
+	step := stepSize(path.basePrice)
+	if amount = roundTo(amount, step); amount < step {
+		return nil, exchanges.NewInvalidAmount(amount, step, 0)
+	}
+	if orderType == types.OrderTypeLimit && price <= 0 {
+		return nil, exchanges.NewInvalidPrice(price, tickSize(path.basePrice), 0)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	f.matchOpenOrders(now)
+
+	f.orderSeq++
+	order := &types.Order{
+		ID:        strconv.FormatInt(f.orderSeq, 10),
+		Timestamp: now.UnixMilli(),
+		Datetime:  now.UTC().Format(time.RFC3339),
+		Symbol:    path.symbol,
+		Type:      orderType,
+		Side:      side,
+		Amount:    amount,
+		Price:     roundTo(price, tickSize(path.basePrice)),
+		Remaining: amount,
+		Status:    types.OrderStatusOpen,
+		Info:      map[string]interface{}{},
+	}
+	if clientOrderID, ok := params["clientOrderId"].(string); ok {
+		order.ClientOrderId = clientOrderID
+	}
+	f.orders[order.ID] = order
+	f.tryFill(order, now)
+
+	copied := *order
+	return &copied, nil
+}
+
+// CancelOrder 撤销挂单
+func (f *Fake) CancelOrder(ctx context.Context, id, symbol string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	order, exists := f.orders[id]
+	if !exists || (symbol != "" && order.Symbol != strings.ToUpper(symbol)) {
+		return exchanges.NewOrderNotFound(id)
+	}
+	if order.Status != types.OrderStatusOpen {
+		return exchanges.NewInvalidOrder("订单已结束，无法撤销", order.Status)
+	}
+	order.Status = types.OrderStatusCanceled
+	return nil
+}
+
+// FetchPositions 查询持仓（仅期货），symbols 为空时返回所有持仓，标记价格和未实现盈亏按当前价格计算
+func (f *Fake) FetchPositions(ctx context.Context, symbols []string) ([]*types.Position, error) {
+	if f.config.MarketType != types.MarketTypeFuture {
+		return nil, exchanges.NewNotSupported("fetchPositions")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	f.matchOpenOrders(now)
+
+	wanted := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		wanted[strings.ToUpper(symbol)] = true
+	}
+
+	var positions []*types.Position
+	for _, marketID := range f.marketIDs() {
+		position := f.positions[marketID]
+		if position == nil || position.Contracts == 0 || (len(symbols) > 0 && !wanted[marketID]) {
+			continue
+		}
+		copied := *position
+		copied.Timestamp = now.UnixMilli()
+		copied.Datetime = now.UTC().Format(time.RFC3339)
+		copied.MarkPrice = f.price(marketID, now)
+		copied.NotionalValue = copied.Contracts * copied.MarkPrice
+		direction := 1.0
+		if copied.Side == types.PositionSideShort {
+			direction = -1.0
+		}
+		copied.UnrealizedPnl = (copied.MarkPrice - copied.EntryPrice) * copied.Contracts * direction
+		copied.InitialMargin = copied.NotionalValue / copied.Leverage
+		if copied.InitialMargin > 0 {
+			copied.RoiPercentage = copied.UnrealizedPnl / copied.InitialMargin * 100
+		}
+		positions = append(positions, &copied)
+	}
+	return positions, nil
+}
+
+// SetLeverage 设置交易对的杠杆倍数（仅期货）
+func (f *Fake) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	if f.config.MarketType != types.MarketTypeFuture {
+		return exchanges.NewNotSupported("setLeverage")
+	}
+	path, err := f.path(symbol)
+	if err != nil {
+		return err
+	}
+	if leverage < 1 || leverage > 125 {
+		return exchanges.NewBadRequest("杠杆倍数必须在1-125之间")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.leverages[path.symbol] = leverage
+	return nil
+}
+
+// SetMarginMode 设置交易对的保证金模式（仅期货）
+func (f *Fake) SetMarginMode(ctx context.Context, symbol, marginMode string, leverage int) error {
+	if f.config.MarketType != types.MarketTypeFuture {
+		return exchanges.NewNotSupported("setMarginMode")
+	}
+	path, err := f.path(symbol)
+	if err != nil {
+		return err
+	}
+	switch marginMode {
+	case types.MarginModeCross, types.MarginModeIsolated:
+	default:
+		return exchanges.NewBadRequest("无效的保证金模式: " + marginMode)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.marginModes[path.symbol] = marginMode
+	if leverage > 0 {
+		f.leverages[path.symbol] = leverage
+	}
+	return nil
+}
+
+// matchOpenOrders 撮合价格已到达的挂单，调用方需持有锁
+func (f *Fake) matchOpenOrders(now time.Time) {
+	for _, order := range f.orders {
+		if order.Status == types.OrderStatusOpen {
+			f.tryFill(order, now)
+		}
+	}
+}
+
+// tryFill 订单可成交时按成交价全部成交并更新持仓，调用方需持有锁
+func (f *Fake) tryFill(order *types.Order, now time.Time) {
+	bid, ask := f.bidAsk(order.Symbol, f.price(order.Symbol, now))
+
+	fillPrice := ask
+	if order.Side == types.OrderSideSell {
+		fillPrice = bid
+	}
+	if order.Type == types.OrderTypeLimit {
+		if (order.Side == types.OrderSideBuy && ask > order.Price) || (order.Side == types.OrderSideSell && bid < order.Price) {
+			return
+		}
+		// 挂单按限价成交，提交时已可成交的限价单按更优的对手价成交
+		if order.Timestamp < now.UnixMilli() {
+			fillPrice = order.Price
+		}
+	}
+
+	order.Status = types.OrderStatusClosed
+	order.Filled = order.Amount
+	order.Remaining = 0
+	order.Average = fillPrice
+	order.Cost = fillPrice * order.Amount
+	order.LastTradeTimestamp = now.UnixMilli()
+	order.Fee = types.Fee{Currency: QuoteAsset, Cost: order.Cost * 0.0005, Rate: 0.0005}
+
+	if f.config.MarketType == types.MarketTypeFuture {
+		f.applyFill(order.Symbol, order.Side, order.Amount, fillPrice)
+	}
+}
+
+// applyFill 按成交更新单向净持仓，反向成交先减仓，超出部分反向开仓
+func (f *Fake) applyFill(marketID, side string, amount, price float64) {
+	signed := amount
+	if side == types.OrderSideSell {
+		signed = -amount
+	}
+
+	position := f.positions[marketID]
+	current := 0.0
+	if position != nil {
+		current = position.Contracts
+		if position.Side == types.PositionSideShort {
+			current = -current
+		}
+	}
+
+	next := roundTo(current+signed, stepSize(f.paths[marketID].basePrice))
+	entry := price
+	switch {
+	case current != 0 && math.Signbit(current) == math.Signbit(signed):
+		// 加仓，按数量加权计算开仓均价
+		entry = (position.EntryPrice*math.Abs(current) + price*amount) / math.Abs(next)
+	case current != 0 && math.Abs(signed) <= math.Abs(current):
+		// 减仓，开仓均价不变
+		entry = position.EntryPrice
+	}
+
+	if next == 0 {
+		delete(f.positions, marketID)
+		return
+	}
+
+	leverage := float64(f.leverages[marketID])
+	if leverage < 1 {
+		leverage = 1
+	}
+	marginMode := f.marginModes[marketID]
+	if marginMode == "" {
+		marginMode = types.MarginModeCross
+	}
+	side = types.PositionSideLong
+	if next < 0 {
+		side = types.PositionSideShort
+	}
+	f.positions[marketID] = &types.Position{
+		ID:           fmt.Sprintf("%s-%s", marketID, side),
+		Symbol:       marketID,
+		Side:         side,
+		Size:         math.Abs(next),
+		Contracts:    math.Abs(next),
+		ContractSize: 1,
+		EntryPrice:   entry,
+		Leverage:     leverage,
+		MarginType:   marginMode,
+		Info:         map[string]interface{}{},
+	}
+}
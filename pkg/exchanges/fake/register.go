@@ -0,0 +1,82 @@
+package fake
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// scriptEnvPrefix 按基础货币覆盖价格路径脚本的环境变量前缀，如 FAKE_EXCHANGE_SCRIPT_BTC
+const scriptEnvPrefix = "FAKE_EXCHANGE_SCRIPT_"
+
+func init() {
+	exchanges.Register(exchanges.Descriptor{
+		ID:          "fake",
+		Name:        "Fake Exchange",
+		Version:     "v1",
+		Website:     "",
+		Countries:   []string{},
+		MarketTypes: []string{types.MarketTypeSpot, types.MarketTypeFuture},
+		Capabilities: []string{"fetchMarkets", "fetchTicker", "fetchTickers", "fetchKline", "fetchOrderBook", "fetchMarkPrice",
+			"watchMarkPrices", "watchTrades", "watchLiquidations", "fetchOpenInterest", "createOrder", "fetchPositions"},
+		Constructor: func(marketType string) (interface{}, error) {
+			config := DefaultConfig()
+			config.MarketType = marketType
+			if err := loadEnvConfig(config); err != nil {
+				return nil, fmt.Errorf("模拟交易所配置错误: %w", err)
+			}
+
+			exchange, err := New(config)
+			if err != nil {
+				return nil, err
+			}
+			return exchange, nil
+		},
+	})
+}
+
+// loadEnvConfig 从 FAKE_EXCHANGE_* 环境变量读取配置，未设置的保持默认值
+func loadEnvConfig(config *Config) error {
+	if value := os.Getenv("FAKE_EXCHANGE_MARKETS"); value != "" {
+		config.Markets = value
+	}
+	config.Script = os.Getenv("FAKE_EXCHANGE_SCRIPT")
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if base, found := strings.CutPrefix(key, scriptEnvPrefix); found && base != "" {
+			config.Scripts[strings.ToUpper(base)] = value
+		}
+	}
+
+	if value := os.Getenv("FAKE_EXCHANGE_LOOP"); value != "" {
+		config.Loop = value == "true"
+	}
+	for key, target := range map[string]*float64{"FAKE_EXCHANGE_NOISE": &config.Noise, "FAKE_EXCHANGE_SPREAD": &config.Spread} {
+		if value := os.Getenv(key); value != "" {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("%s 必须为数字", key)
+			}
+			*target = parsed
+		}
+	}
+	if value := os.Getenv("FAKE_EXCHANGE_SEED"); value != "" {
+		seed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("FAKE_EXCHANGE_SEED 必须为整数")
+		}
+		config.Seed = seed
+	}
+	if value := os.Getenv("FAKE_EXCHANGE_STREAM_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("FAKE_EXCHANGE_STREAM_INTERVAL 必须为时间长度，如 1s")
+		}
+		config.StreamInterval = interval
+	}
+	return nil
+}
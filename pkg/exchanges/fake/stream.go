@@ -0,0 +1,147 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// ========== 内存推送 ==========
+// 推送不经过网络，按推送间隔从价格路径生成消息，ctx 取消后关闭通道，与真实交易所的推送语义一致
+
+// WatchMarkPrices 订阅标记价格推送
+func (f *Fake) WatchMarkPrices(ctx context.Context, symbols []string) (<-chan *types.WatchMarkPrice, error) {
+	futures := f.config.MarketType == types.MarketTypeFuture
+	return watchFeed(ctx, f, symbols, func(marketID string, now, _ time.Time) (*types.WatchMarkPrice, bool) {
+		price := f.price(marketID, now)
+		bid, ask := f.bidAsk(marketID, price)
+		update := &types.WatchMarkPrice{
+			Symbol:    marketID,
+			TimeStamp: now.UnixMilli(),
+			MarkPrice: price,
+			BidPrice:  bid,
+			AskPrice:  ask,
+			LastPrice: price,
+		}
+		if futures {
+			mark := f.markPrice(marketID, now)
+			update.IndexPrice = mark.IndexPrice
+			update.FundingRate = mark.FundingRate
+			update.FundingTime = mark.NextFundingTime
+		}
+		return update, true
+	})
+}
+
+// WatchTrades 订阅逐笔成交推送，每个推送间隔每个交易对合成一笔成交，偶尔出现大额成交
+func (f *Fake) WatchTrades(ctx context.Context, symbols []string) (<-chan *types.Trade, error) {
+	return watchFeed(ctx, f, symbols, func(marketID string, now, previous time.Time) (*types.Trade, bool) {
+		path := f.paths[marketID]
+		price := f.price(marketID, now)
+		side := "buy"
+		if price < f.price(marketID, previous) {
+			side = "sell"
+		}
+
+		// 约1%的成交放大100倍，用于触发大额成交告警
+		notional := 1000 * (0.2 + path.random(now.UnixMilli(), "trade"))
+		if path.random(now.UnixMilli(), "whale") < 0.01 {
+			notional *= 100
+		}
+		amount := roundTo(notional/price, stepSize(path.basePrice))
+		if amount <= 0 {
+			return nil, false
+		}
+		return &types.Trade{
+			ID:           strconv.FormatInt(now.UnixNano(), 10),
+			Symbol:       marketID,
+			Side:         side,
+			Amount:       amount,
+			Price:        price,
+			Cost:         amount * price,
+			Timestamp:    now.UnixMilli(),
+			Datetime:     now.UTC().Format(time.RFC3339),
+			TakerOrMaker: "taker",
+		}, true
+	})
+}
+
+// WatchLiquidations 订阅强平订单推送（仅期货），相邻两次推送价格变化超过0.5%时合成一笔强平订单，
+// 下跌时为多头被强平，上涨时为空头被强平，脚本中的 spike 和 gap 片段会产生强平推送
+func (f *Fake) WatchLiquidations(ctx context.Context, symbols []string) (<-chan *types.Liquidation, error) {
+	if f.config.MarketType != types.MarketTypeFuture {
+		return nil, exchanges.NewNotSupported("watchLiquidations")
+	}
+	return watchFeed(ctx, f, symbols, func(marketID string, now, previous time.Time) (*types.Liquidation, bool) {
+		path := f.paths[marketID]
+		price := f.price(marketID, now)
+		last := f.price(marketID, previous)
+		move := (price - last) / last
+		if math.Abs(move) < liquidationMoveRatio {
+			return nil, false
+		}
+
+		side := "sell"
+		if move > 0 {
+			side = "buy"
+		}
+		notional := 50000 * math.Abs(move) / liquidationMoveRatio
+		amount := roundTo(notional/price, stepSize(path.basePrice))
+		return &types.Liquidation{
+			Symbol:       marketID,
+			Side:         side,
+			Price:        price,
+			AveragePrice: price,
+			Amount:       amount,
+			Notional:     amount * price,
+			Timestamp:    now.UnixMilli(),
+		}, true
+	})
+}
+
+// watchFeed 按推送间隔为每个交易对调用 build 生成消息，previous 为上一次推送的时间
+func watchFeed[T any](ctx context.Context, f *Fake, symbols []string, build func(marketID string, now, previous time.Time) (T, bool)) (<-chan T, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("订阅推送的交易对不能为空")
+	}
+	marketIDs := f.selectMarketIDs(symbols)
+	if len(marketIDs) == 0 {
+		return nil, exchanges.NewMarketNotFound(symbols[0])
+	}
+
+	out := make(chan T, streamChannelBuffer)
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(f.config.StreamInterval)
+		defer ticker.Stop()
+
+		previous := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				for _, marketID := range marketIDs {
+					message, ok := build(marketID, now, previous)
+					if !ok {
+						continue
+					}
+					select {
+					case out <- message:
+					case <-ctx.Done():
+						return
+					default:
+						// 消费者处理不过来时丢弃，与真实推送的缓冲区溢出行为一致
+					}
+				}
+				previous = now
+			}
+		}
+	}()
+	return out, nil
+}
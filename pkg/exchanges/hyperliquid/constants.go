@@ -0,0 +1,50 @@
+package hyperliquid
+
+// ========== Hyperliquid API 基础URL ==========
+
+const (
+	BaseURL        = "https://api.hyperliquid.xyz"
+	TestnetBaseURL = "https://api.hyperliquid-testnet.xyz"
+)
+
+// ========== Hyperliquid 公共数据端点 ==========
+
+const (
+	EndpointInfo = "/info" // 所有公共查询都通过 POST /info，按 type 区分
+)
+
+// ========== Hyperliquid info 请求类型 ==========
+
+const (
+	InfoTypeMetaAndAssetCtxs = "metaAndAssetCtxs" // 永续合约列表及标记价格、资金费率
+	InfoTypeCandleSnapshot   = "candleSnapshot"   // K线
+	InfoTypeL2Book           = "l2Book"           // 订单簿
+)
+
+// ========== Hyperliquid 合约常数 ==========
+
+const (
+	QuoteAsset      = "USDC"         // 永续合约以USDC计价结算
+	MaxCandles      = 5000           // 单次最多返回的K线数量
+	MaxBookLevels   = 20             // 订单簿单侧最多档位
+	FundingInterval = 60 * 60 * 1000 // 资金费率每小时结算（毫秒）
+)
+
+// ========== Hyperliquid 时间周期常数 ==========
+
+const (
+	Interval1m  = "1m"
+	Interval3m  = "3m"
+	Interval5m  = "5m"
+	Interval15m = "15m"
+	Interval30m = "30m"
+	Interval1h  = "1h"
+	Interval2h  = "2h"
+	Interval4h  = "4h"
+	Interval8h  = "8h"
+	Interval12h = "12h"
+	Interval1d  = "1d"
+	Interval3d  = "3d"
+	Interval1w  = "1w"
+	Interval1M  = "1M"
+)
@@ -0,0 +1,523 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// Hyperliquid 实现交易所接口 (仅公共市场数据)
+// 永续合约以USDC计价，MarketID 统一为 <币种>USDC，如 BTCUSDC
+type Hyperliquid struct {
+	*exchanges.BaseExchange
+	config *Config
+
+	endpoints map[string]string
+
+	mu    sync.RWMutex
+	coins map[string]string // MarketID -> Hyperliquid 币种名（区分大小写，如 kPEPE）
+}
+
+// assetMeta 永续合约元数据
+type assetMeta struct {
+	Name        string `json:"name"`
+	SzDecimals  int    `json:"szDecimals"`
+	MaxLeverage int    `json:"maxLeverage"`
+	IsDelisted  bool   `json:"isDelisted"`
+}
+
+// assetCtx 永续合约实时行情
+type assetCtx struct {
+	Funding      string   `json:"funding"`
+	OpenInterest string   `json:"openInterest"`
+	PrevDayPx    string   `json:"prevDayPx"`
+	DayNtlVlm    string   `json:"dayNtlVlm"`
+	DayBaseVlm   string   `json:"dayBaseVlm"`
+	Premium      *string  `json:"premium"`
+	OraclePx     string   `json:"oraclePx"`
+	MarkPx       string   `json:"markPx"`
+	MidPx        *string  `json:"midPx"`
+	ImpactPxs    []string `json:"impactPxs"`
+}
+
+// New 创建新的Hyperliquid实例
+func New(config *Config) (*Hyperliquid, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	base := exchanges.NewBaseExchange("hyperliquid", "Hyperliquid", "v1", []string{})
+	base.SetTimeout(time.Duration(config.Timeout) * time.Millisecond)
+	hl := &Hyperliquid{
+		BaseExchange: base,
+		config:       config.Clone(),
+		endpoints:    make(map[string]string),
+		coins:        make(map[string]string),
+	}
+
+	hl.setCapabilities()
+	hl.setEndpoints()
+	hl.BaseExchange.SetRetryConfig(3, 100*time.Millisecond, 10*time.Second, true)
+	hl.BaseExchange.EnableRetry()
+
+	return hl, nil
+}
+
+// setCapabilities 设置支持的功能
+func (h *Hyperliquid) setCapabilities() {
+	capabilities := map[string]bool{
+		"fetchMarkets":   true,
+		"fetchTicker":    true,
+		"fetchTickers":   true,
+		"fetchKline":     true,
+		"fetchOrderBook": true,
+		"fetchMarkPrice": true,
+	}
+
+	timeframes := map[string]string{
+		"1m": Interval1m, "3m": Interval3m, "5m": Interval5m,
+		"15m": Interval15m, "30m": Interval30m,
+		"1h": Interval1h, "2h": Interval2h, "4h": Interval4h,
+		"8h": Interval8h, "12h": Interval12h,
+		"1d": Interval1d, "3d": Interval3d, "1w": Interval1w, "1M": Interval1M,
+	}
+
+	for k, v := range capabilities {
+		h.BaseExchange.Has()[k] = v
+	}
+	for k, v := range timeframes {
+		h.BaseExchange.GetTimeframes()[k] = v
+	}
+}
+
+// setEndpoints 设置API端点
+func (h *Hyperliquid) setEndpoints() {
+	baseURL := h.config.GetBaseURL()
+	h.endpoints["base"] = baseURL
+	h.endpoints["info"] = baseURL + EndpointInfo
+}
+
+// GetMarketType 获取市场类型
+func (h *Hyperliquid) GetMarketType() string {
+	return h.config.MarketType
+}
+
+// IsTestnet 是否测试网
+func (h *Hyperliquid) IsTestnet() bool {
+	return h.config.TestNet
+}
+
+// GetQuoteAsset 获取计价货币
+func (h *Hyperliquid) GetQuoteAsset() string {
+	return QuoteAsset
+}
+
+// ========== 公共API方法 ==========
+
+// postInfo 发送 info 查询并解析响应
+func (h *Hyperliquid) postInfo(ctx context.Context, request map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	respStr, err := h.FetchWithRetry(ctx, h.endpoints["info"], "POST", nil, string(body))
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal([]byte(respStr), result); err != nil {
+		return fmt.Errorf("hyperliquid api error: %s", respStr)
+	}
+	return nil
+}
+
+// fetchMetaAndAssetCtxs 获取永续合约元数据和实时行情，并刷新 MarketID 映射
+func (h *Hyperliquid) fetchMetaAndAssetCtxs(ctx context.Context) ([]assetMeta, []assetCtx, error) {
+	var resp []json.RawMessage
+	if err := h.postInfo(ctx, map[string]interface{}{"type": InfoTypeMetaAndAssetCtxs}, &resp); err != nil {
+		return nil, nil, err
+	}
+	if len(resp) < 2 {
+		return nil, nil, fmt.Errorf("hyperliquid api error: unexpected metaAndAssetCtxs response")
+	}
+
+	var meta struct {
+		Universe []assetMeta `json:"universe"`
+	}
+	if err := json.Unmarshal(resp[0], &meta); err != nil {
+		return nil, nil, err
+	}
+
+	var ctxs []assetCtx
+	if err := json.Unmarshal(resp[1], &ctxs); err != nil {
+		return nil, nil, err
+	}
+	if len(ctxs) != len(meta.Universe) {
+		return nil, nil, fmt.Errorf("hyperliquid api error: universe and asset contexts length mismatch")
+	}
+
+	h.mu.Lock()
+	for _, asset := range meta.Universe {
+		h.coins[toMarketID(asset.Name)] = asset.Name
+	}
+	h.mu.Unlock()
+
+	return meta.Universe, ctxs, nil
+}
+
+// resolveCoin 将 MarketID 转换为 Hyperliquid 币种名
+func (h *Hyperliquid) resolveCoin(ctx context.Context, symbol string) (string, error) {
+	if symbol == "" {
+		return "", fmt.Errorf("symbol不能为空")
+	}
+
+	h.mu.RLock()
+	coin, exists := h.coins[strings.ToUpper(symbol)]
+	h.mu.RUnlock()
+	if exists {
+		return coin, nil
+	}
+
+	// 映射未加载时拉取一次合约列表
+	if _, _, err := h.fetchMetaAndAssetCtxs(ctx); err != nil {
+		return "", err
+	}
+
+	h.mu.RLock()
+	coin, exists = h.coins[strings.ToUpper(symbol)]
+	h.mu.RUnlock()
+	if !exists {
+		return "", fmt.Errorf("hyperliquid不支持交易对: %s", symbol)
+	}
+	return coin, nil
+}
+
+// FetchMarkets 获取市场信息
+func (h *Hyperliquid) FetchMarkets(ctx context.Context, params map[string]interface{}) ([]*types.Market, error) {
+	universe, _, err := h.fetchMetaAndAssetCtxs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	markets := make([]*types.Market, 0, len(universe))
+	for _, asset := range universe {
+		if asset.IsDelisted {
+			continue
+		}
+		markets = append(markets, h.parseMarket(asset))
+	}
+	return markets, nil
+}
+
+// parseMarket 解析市场信息
+// 数量精度为 szDecimals 位；永续合约价格最多 6-szDecimals 位小数
+func (h *Hyperliquid) parseMarket(asset assetMeta) *types.Market {
+	priceDecimals := max(6-asset.SzDecimals, 0)
+
+	return &types.Market{
+		ID:       toMarketID(asset.Name),
+		Symbol:   fmt.Sprintf("%s/%s:%s", asset.Name, QuoteAsset, QuoteAsset),
+		Base:     asset.Name,
+		Quote:    QuoteAsset,
+		Settle:   QuoteAsset,
+		Type:     h.config.MarketType,
+		Active:   !asset.IsDelisted,
+		Future:   true,
+		Swap:     true,
+		Contract: true,
+		Linear:   true,
+		Info: map[string]interface{}{
+			"name":        asset.Name,
+			"szDecimals":  asset.SzDecimals,
+			"maxLeverage": asset.MaxLeverage,
+		},
+		Precision: types.MarketPrecision{
+			Price:  float64(priceDecimals),
+			Amount: float64(asset.SzDecimals),
+		},
+		Limits: types.MarketLimits{
+			Leverage: types.LimitRange{Min: 1, Max: float64(asset.MaxLeverage)},
+			Amount:   types.LimitRange{Step: math.Pow10(-asset.SzDecimals)},
+			Price:    types.LimitRange{Step: math.Pow10(-priceDecimals)},
+		},
+	}
+}
+
+// FetchTickers 批量获取ticker
+func (h *Hyperliquid) FetchTickers(ctx context.Context, symbols []string, params map[string]interface{}) (map[string]*types.Ticker, error) {
+	universe, ctxs, err := h.fetchMetaAndAssetCtxs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	symbolsMap := make(map[string]bool)
+	for _, s := range symbols {
+		symbolsMap[strings.ToUpper(s)] = true
+	}
+
+	now := time.Now().UnixMilli()
+	tickers := make(map[string]*types.Ticker)
+	for i, asset := range universe {
+		marketID := toMarketID(asset.Name)
+		if len(symbols) > 0 && !symbolsMap[marketID] {
+			continue
+		}
+		tickers[marketID] = h.parseTicker(ctxs[i], marketID, now)
+	}
+	return tickers, nil
+}
+
+// FetchBookTickers 获取最优买卖价（使用冲击价格作为买卖价）
+func (h *Hyperliquid) FetchBookTickers(ctx context.Context, symbols []string, params map[string]interface{}) (map[string]*types.Ticker, error) {
+	return h.FetchTickers(ctx, symbols, params)
+}
+
+// parseTicker 解析ticker数据
+func (h *Hyperliquid) parseTicker(assetCtx assetCtx, marketID string, timestamp int64) *types.Ticker {
+	markPrice := parseFloat(assetCtx.MarkPx)
+	lastPrice := markPrice
+	if assetCtx.MidPx != nil {
+		if mid := parseFloat(*assetCtx.MidPx); mid > 0 {
+			lastPrice = mid
+		}
+	}
+	openPrice := parseFloat(assetCtx.PrevDayPx)
+
+	// 计算涨跌幅
+	change := lastPrice - openPrice
+	percentage := 0.0
+	if openPrice > 0 {
+		percentage = (change / openPrice) * 100
+	}
+
+	var bid, ask float64
+	if len(assetCtx.ImpactPxs) >= 2 {
+		bid = parseFloat(assetCtx.ImpactPxs[0])
+		ask = parseFloat(assetCtx.ImpactPxs[1])
+	}
+
+	return &types.Ticker{
+		Symbol:      marketID,
+		TimeStamp:   timestamp,
+		Datetime:    h.ISO8601(timestamp),
+		Bid:         bid,
+		Ask:         ask,
+		Open:        openPrice,
+		Last:        lastPrice,
+		Close:       lastPrice,
+		Change:      change,
+		Percentage:  percentage,
+		BaseVolume:  parseFloat(assetCtx.DayBaseVlm),
+		QuoteVolume: parseFloat(assetCtx.DayNtlVlm),
+		Info: map[string]interface{}{
+			"markPx":       assetCtx.MarkPx,
+			"oraclePx":     assetCtx.OraclePx,
+			"funding":      assetCtx.Funding,
+			"openInterest": assetCtx.OpenInterest,
+		},
+	}
+}
+
+// FetchKlines 获取K线数据
+func (h *Hyperliquid) FetchKlines(ctx context.Context, symbol, interval string, since int64, limit int, params map[string]interface{}) ([]*types.Kline, error) {
+	coin, err := h.resolveCoin(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	duration, ok := intervalDuration(interval)
+	if !ok {
+		return nil, fmt.Errorf("不支持的时间周期: %s", interval)
+	}
+
+	if limit <= 0 || limit > MaxCandles {
+		limit = MaxCandles
+	}
+
+	// candleSnapshot 按时间范围查询，未指定起点时按数量向前推算
+	endTime := time.Now().UnixMilli()
+	startTime := since
+	if startTime <= 0 {
+		startTime = endTime - int64(limit)*duration.Milliseconds()
+	} else if end := startTime + int64(limit)*duration.Milliseconds(); end < endTime {
+		endTime = end
+	}
+
+	request := map[string]interface{}{
+		"type": InfoTypeCandleSnapshot,
+		"req": map[string]interface{}{
+			"coin":      coin,
+			"interval":  interval,
+			"startTime": startTime,
+			"endTime":   endTime,
+		},
+	}
+
+	var resp []struct {
+		OpenTime  int64  `json:"t"`
+		CloseTime int64  `json:"T"`
+		Open      string `json:"o"`
+		High      string `json:"h"`
+		Low       string `json:"l"`
+		Close     string `json:"c"`
+		Volume    string `json:"v"`
+	}
+	if err := h.postInfo(ctx, request, &resp); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	klines := make([]*types.Kline, 0, len(resp))
+	for _, candle := range resp {
+		klines = append(klines, &types.Kline{
+			Symbol:    symbol,
+			Timeframe: interval,
+			Timestamp: candle.OpenTime,
+			Open:      parseFloat(candle.Open),
+			High:      parseFloat(candle.High),
+			Low:       parseFloat(candle.Low),
+			Close:     parseFloat(candle.Close),
+			Volume:    parseFloat(candle.Volume),
+			IsClosed:  candle.CloseTime < now,
+		})
+	}
+
+	if len(klines) > limit {
+		klines = klines[len(klines)-limit:]
+	}
+	return klines, nil
+}
+
+// FetchOrderBook 获取订单簿快照，Hyperliquid 单侧最多返回20档
+func (h *Hyperliquid) FetchOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	coin, err := h.resolveCoin(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Coin   string `json:"coin"`
+		Time   int64  `json:"time"`
+		Levels [][]struct {
+			Px string `json:"px"`
+			Sz string `json:"sz"`
+		} `json:"levels"`
+	}
+	if err := h.postInfo(ctx, map[string]interface{}{"type": InfoTypeL2Book, "coin": coin}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Levels) < 2 {
+		return nil, fmt.Errorf("hyperliquid api error: unexpected l2Book response")
+	}
+
+	if limit <= 0 || limit > MaxBookLevels {
+		limit = MaxBookLevels
+	}
+
+	parseSide := func(levels []struct {
+		Px string `json:"px"`
+		Sz string `json:"sz"`
+	}) types.OrderBookSide {
+		count := min(len(levels), limit)
+		side := types.OrderBookSide{
+			Price: make([]float64, 0, count),
+			Size:  make([]float64, 0, count),
+		}
+		for _, level := range levels[:count] {
+			side.Price = append(side.Price, parseFloat(level.Px))
+			side.Size = append(side.Size, parseFloat(level.Sz))
+		}
+		return side
+	}
+
+	return &types.OrderBook{
+		Symbol:    symbol,
+		Bids:      parseSide(resp.Levels[0]),
+		Asks:      parseSide(resp.Levels[1]),
+		TimeStamp: resp.Time,
+		Datetime:  time.UnixMilli(resp.Time).UTC().Format(time.RFC3339Nano),
+	}, nil
+}
+
+// FetchMarkPrice 获取单个交易对的标记价格
+func (h *Hyperliquid) FetchMarkPrice(ctx context.Context, symbol string) (*types.MarkPrice, error) {
+	markPrices, err := h.FetchMarkPrices(ctx, []string{symbol})
+	if err != nil {
+		return nil, err
+	}
+
+	markPrice, exists := markPrices[strings.ToUpper(symbol)]
+	if !exists {
+		return nil, fmt.Errorf("hyperliquid不支持交易对: %s", symbol)
+	}
+	return markPrice, nil
+}
+
+// FetchMarkPrices 获取多个交易对的标记价格和资金费率
+func (h *Hyperliquid) FetchMarkPrices(ctx context.Context, symbols []string) (map[string]*types.MarkPrice, error) {
+	universe, ctxs, err := h.fetchMetaAndAssetCtxs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	symbolsMap := make(map[string]bool)
+	for _, s := range symbols {
+		symbolsMap[strings.ToUpper(s)] = true
+	}
+
+	now := time.Now().UnixMilli()
+	nextFunding := (now/FundingInterval + 1) * FundingInterval
+
+	result := make(map[string]*types.MarkPrice)
+	for i, asset := range universe {
+		marketID := toMarketID(asset.Name)
+		if len(symbols) > 0 && !symbolsMap[marketID] {
+			continue
+		}
+		result[marketID] = &types.MarkPrice{
+			Symbol:          marketID,
+			MarkPrice:       parseFloat(ctxs[i].MarkPx),
+			IndexPrice:      parseFloat(ctxs[i].OraclePx),
+			FundingRate:     parseFloat(ctxs[i].Funding),
+			NextFundingTime: nextFunding,
+			Timestamp:       now,
+		}
+	}
+	return result, nil
+}
+
+// toMarketID 将 Hyperliquid 币种名转换为 MarketID
+func toMarketID(coin string) string {
+	return strings.ToUpper(coin) + QuoteAsset
+}
+
+// intervalDuration 时间周期对应的时长
+func intervalDuration(interval string) (time.Duration, bool) {
+	durations := map[string]time.Duration{
+		Interval1m: time.Minute, Interval3m: 3 * time.Minute, Interval5m: 5 * time.Minute,
+		Interval15m: 15 * time.Minute, Interval30m: 30 * time.Minute,
+		Interval1h: time.Hour, Interval2h: 2 * time.Hour, Interval4h: 4 * time.Hour,
+		Interval8h: 8 * time.Hour, Interval12h: 12 * time.Hour,
+		Interval1d: 24 * time.Hour, Interval3d: 72 * time.Hour, Interval1w: 7 * 24 * time.Hour,
+		Interval1M: 30 * 24 * time.Hour,
+	}
+	duration, ok := durations[interval]
+	return duration, ok
+}
+
+// parseFloat 解析字符串数值，失败返回0
+func parseFloat(value string) float64 {
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package hyperliquid
+
+import (
+	"fmt"
+	"os"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+func init() {
+	exchanges.Register(exchanges.Descriptor{
+		ID:           "hyperliquid",
+		Name:         "Hyperliquid",
+		Version:      "v1",
+		Website:      "https://hyperliquid.xyz",
+		Countries:    []string{},
+		MarketTypes:  []string{types.MarketTypeFuture},
+		Capabilities: []string{"fetchMarkets", "fetchTicker", "fetchTickers", "fetchKline", "fetchOrderBook", "fetchMarkPrice"},
+		Constructor: func(marketType string) (interface{}, error) {
+			config := DefaultConfig()
+			if err := config.SetMarketType(marketType); err != nil {
+				return nil, fmt.Errorf("设置Hyperliquid市场类型失败: %w", err)
+			}
+
+			// 设置测试网环境
+			if testnet := os.Getenv("HYPERLIQUID_TESTNET"); testnet == "true" {
+				config.TestNet = true
+			}
+
+			exchange, err := New(config)
+			if err != nil {
+				return nil, err
+			}
+			return exchange, nil
+		},
+	})
+}
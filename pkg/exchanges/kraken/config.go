@@ -0,0 +1,63 @@
+package kraken
+
+import (
+	"fmt"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// Config Kraken Futures 交易所配置 (仅公共市场数据)
+type Config struct {
+	// 环境配置
+	TestNet bool `json:"testnet"` // 是否使用测试网
+
+	// 网络配置
+	Timeout int `json:"timeout"` // 超时时间(毫秒)
+
+	// 市场类型配置，目前仅支持永续合约
+	MarketType string `json:"marketType"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		TestNet:    false,
+		Timeout:    30000, // 30秒
+		MarketType: types.MarketTypeFuture,
+	}
+}
+
+// Validate 验证配置
+func (c *Config) Validate() error {
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout cannot be negative")
+	}
+	if c.MarketType != types.MarketTypeFuture {
+		return fmt.Errorf("invalid marketType: %s, kraken only supports 'future'", c.MarketType)
+	}
+	return nil
+}
+
+// Clone 克隆配置
+func (c *Config) Clone() *Config {
+	clone := *c
+	return &clone
+}
+
+// SetMarketType 设置市场类型
+func (c *Config) SetMarketType(marketType string) error {
+	switch marketType {
+	case types.MarketTypeFuture, types.MarketTypeSwap:
+		c.MarketType = types.MarketTypeFuture
+		return nil
+	default:
+		return fmt.Errorf("不支持的市场类型: %s", marketType)
+	}
+}
+
+// GetBaseURL 获取基础URL
+func (c *Config) GetBaseURL() string {
+	if c.TestNet {
+		return TestnetBaseURL
+	}
+	return BaseURL
+}
@@ -0,0 +1,44 @@
+package kraken
+
+// ========== Kraken Futures API 基础URL ==========
+
+const (
+	BaseURL        = "https://futures.kraken.com"
+	TestnetBaseURL = "https://demo-futures.kraken.com"
+)
+
+// ========== Kraken Futures 公共数据端点 ==========
+
+const (
+	EndpointInstruments = "/derivatives/api/v3/instruments"
+	EndpointTickers     = "/derivatives/api/v3/tickers"
+	EndpointOrderBook   = "/derivatives/api/v3/orderbook"
+	EndpointCharts      = "/api/charts/v1" // /{tickType}/{symbol}/{resolution}
+)
+
+// ========== Kraken Futures 合约常数 ==========
+
+const (
+	PerpetualPrefix = "PF_"          // 线性永续合约(多抵押品)前缀，如 PF_XBTUSD
+	QuoteAsset      = "USD"          // 永续合约以USD计价
+	ResultSuccess   = "success"      // 请求成功的 result 字段
+	MaxCandles      = 5000           // 单次最多返回的K线数量
+	FundingInterval = 60 * 60 * 1000 // 资金费率每小时结算（毫秒）
+
+	ChartTickTypeTrade = "trade" // 成交价K线
+	ChartTickTypeMark  = "mark"  // 标记价格K线
+)
+
+// ========== Kraken Futures 时间周期常数 ==========
+
+const (
+	Interval1m  = "1m"
+	Interval5m  = "5m"
+	Interval15m = "15m"
+	Interval30m = "30m"
+	Interval1h  = "1h"
+	Interval4h  = "4h"
+	Interval12h = "12h"
+	Interval1d  = "1d"
+	Interval1w  = "1w"
+)
@@ -0,0 +1,497 @@
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// Kraken 实现交易所接口 (Kraken Futures 公共市场数据，线性永续合约)
+type Kraken struct {
+	*exchanges.BaseExchange
+	config *Config
+
+	endpoints map[string]string
+}
+
+// New 创建新的Kraken Futures实例
+func New(config *Config) (*Kraken, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	base := exchanges.NewBaseExchange("kraken", "Kraken Futures", "v3", []string{"US", "GB"})
+	base.SetTimeout(time.Duration(config.Timeout) * time.Millisecond)
+	kraken := &Kraken{
+		BaseExchange: base,
+		config:       config.Clone(),
+		endpoints:    make(map[string]string),
+	}
+
+	kraken.setCapabilities()
+	kraken.setEndpoints()
+	kraken.BaseExchange.SetRetryConfig(3, 100*time.Millisecond, 10*time.Second, true)
+	kraken.BaseExchange.EnableRetry()
+
+	return kraken, nil
+}
+
+// setCapabilities 设置支持的功能
+func (k *Kraken) setCapabilities() {
+	capabilities := map[string]bool{
+		"fetchMarkets":   true,
+		"fetchTicker":    true,
+		"fetchTickers":   true,
+		"fetchKline":     true,
+		"fetchOrderBook": true,
+		"fetchMarkPrice": true,
+	}
+
+	timeframes := map[string]string{
+		"1m": Interval1m, "5m": Interval5m, "15m": Interval15m, "30m": Interval30m,
+		"1h": Interval1h, "4h": Interval4h, "12h": Interval12h,
+		"1d": Interval1d, "1w": Interval1w,
+	}
+
+	for key, v := range capabilities {
+		k.BaseExchange.Has()[key] = v
+	}
+	for key, v := range timeframes {
+		k.BaseExchange.GetTimeframes()[key] = v
+	}
+}
+
+// setEndpoints 设置API端点
+func (k *Kraken) setEndpoints() {
+	baseURL := k.config.GetBaseURL()
+	k.endpoints["base"] = baseURL
+	k.endpoints["instruments"] = baseURL + EndpointInstruments
+	k.endpoints["tickers"] = baseURL + EndpointTickers
+	k.endpoints["orderbook"] = baseURL + EndpointOrderBook
+	k.endpoints["charts"] = baseURL + EndpointCharts
+}
+
+// buildQuery 构建查询字符串
+func (k *Kraken) buildQuery(params map[string]interface{}) string {
+	if len(params) == 0 {
+		return ""
+	}
+
+	var keys []string
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", key, params[key]))
+	}
+	return strings.Join(parts, "&")
+}
+
+// get 发送GET请求并解析响应，校验 result 字段
+func (k *Kraken) get(ctx context.Context, endpoint string, params map[string]interface{}, result interface{}) error {
+	if query := k.buildQuery(params); query != "" {
+		endpoint += "?" + query
+	}
+
+	respStr, err := k.FetchWithRetry(ctx, endpoint, "GET", nil, "")
+	if err != nil {
+		return err
+	}
+
+	var status struct {
+		Result string `json:"result"`
+		Error  string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(respStr), &status); err != nil {
+		return err
+	}
+	// K线接口没有 result 字段
+	if status.Result != "" && status.Result != ResultSuccess {
+		return fmt.Errorf("kraken api error: %s", status.Error)
+	}
+	return json.Unmarshal([]byte(respStr), result)
+}
+
+// GetMarketType 获取市场类型
+func (k *Kraken) GetMarketType() string {
+	return k.config.MarketType
+}
+
+// IsTestnet 是否测试网
+func (k *Kraken) IsTestnet() bool {
+	return k.config.TestNet
+}
+
+// GetQuoteAsset 获取计价货币
+func (k *Kraken) GetQuoteAsset() string {
+	return QuoteAsset
+}
+
+// isPerpetual 是否线性永续合约
+func isPerpetual(symbol string) bool {
+	return strings.HasPrefix(strings.ToUpper(symbol), PerpetualPrefix)
+}
+
+// commonCurrency 将Kraken币种代码转换为通用代码
+func commonCurrency(code string) string {
+	code = strings.ToUpper(code)
+	if code == "XBT" {
+		return "BTC"
+	}
+	return code
+}
+
+// ========== 市场数据API ==========
+
+// FetchMarkets 获取市场信息
+// 支持 params["quote"] 筛选报价货币，如 params["quote"] = "USD"
+func (k *Kraken) FetchMarkets(ctx context.Context, params map[string]interface{}) ([]*types.Market, error) {
+	var quoteFilter string
+	if params != nil {
+		if q, ok := params["quote"].(string); ok {
+			quoteFilter = q
+		}
+	}
+
+	var resp struct {
+		Instruments []map[string]interface{} `json:"instruments"`
+	}
+	if err := k.get(ctx, k.endpoints["instruments"], nil, &resp); err != nil {
+		return nil, err
+	}
+
+	var markets []*types.Market
+	for _, data := range resp.Instruments {
+		market := k.parseMarket(data)
+		if market == nil {
+			continue
+		}
+		// 应用 quote 筛选
+		if quoteFilter != "" && market.Quote != quoteFilter {
+			continue
+		}
+		markets = append(markets, market)
+	}
+	return markets, nil
+}
+
+// parseMarket 解析合约信息，仅保留可交易的线性永续合约
+func (k *Kraken) parseMarket(data map[string]interface{}) *types.Market {
+	symbol := strings.ToUpper(k.SafeString(data, "symbol", ""))
+	if !isPerpetual(symbol) {
+		return nil
+	}
+	if tradeable, ok := data["tradeable"].(bool); !ok || !tradeable {
+		return nil
+	}
+
+	base := commonCurrency(k.SafeString(data, "base", ""))
+	quote := commonCurrency(k.SafeString(data, "quote", ""))
+	if base == "" || quote == "" {
+		return nil
+	}
+
+	// contractValueTradePrecision 为数量小数位，可能为负数（如 -1 表示10的整数倍）
+	amountPrecision := k.SafeFloat(data, "contractValueTradePrecision", 0)
+	tickSize := k.SafeFloat(data, "tickSize", 0)
+
+	market := &types.Market{
+		ID:           symbol,
+		Symbol:       fmt.Sprintf("%s/%s", base, quote),
+		Base:         base,
+		Quote:        quote,
+		Settle:       quote,
+		Type:         k.config.MarketType,
+		Active:       true,
+		Future:       true,
+		Swap:         true,
+		Contract:     true,
+		Linear:       true,
+		ContractSize: k.SafeFloat(data, "contractSize", 1),
+		Info:         data,
+		Precision: types.MarketPrecision{
+			Amount: amountPrecision,
+		},
+		Limits: types.MarketLimits{
+			Price: types.LimitRange{Step: tickSize},
+			Amount: types.LimitRange{
+				Min:  math.Pow10(-int(amountPrecision)),
+				Max:  k.SafeFloat(data, "maxPositionSize", 0),
+				Step: math.Pow10(-int(amountPrecision)),
+			},
+		},
+	}
+	if tickSize > 0 {
+		// 价格精度位数由最小变动价位推算
+		market.Precision.Price = math.Max(0, math.Round(-math.Log10(tickSize)))
+	}
+
+	// 最大杠杆 = 1 / 第一档初始保证金率
+	if levels, ok := data["retailMarginLevels"].([]interface{}); ok && len(levels) > 0 {
+		if level, ok := levels[0].(map[string]interface{}); ok {
+			if initialMargin := k.SafeFloat(level, "initialMargin", 0); initialMargin > 0 {
+				market.Limits.Leverage = types.LimitRange{Min: 1, Max: math.Round(1 / initialMargin)}
+			}
+		}
+	}
+
+	return market
+}
+
+// FetchTickers 批量获取ticker
+func (k *Kraken) FetchTickers(ctx context.Context, symbols []string, params map[string]interface{}) (map[string]*types.Ticker, error) {
+	data, err := k.fetchRawTickers(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	tickers := make(map[string]*types.Ticker, len(data))
+	for symbol, item := range data {
+		tickers[symbol] = k.parseTicker(item)
+	}
+	return tickers, nil
+}
+
+// FetchBookTickers 获取最优买卖价
+func (k *Kraken) FetchBookTickers(ctx context.Context, symbols []string, params map[string]interface{}) (map[string]*types.Ticker, error) {
+	return k.FetchTickers(ctx, symbols, params)
+}
+
+// fetchRawTickers 获取永续合约的原始ticker数据，symbols 为空时返回全部
+func (k *Kraken) fetchRawTickers(ctx context.Context, symbols []string) (map[string]map[string]interface{}, error) {
+	var resp struct {
+		Tickers []map[string]interface{} `json:"tickers"`
+	}
+	if err := k.get(ctx, k.endpoints["tickers"], nil, &resp); err != nil {
+		return nil, err
+	}
+
+	symbolsMap := make(map[string]bool)
+	for _, s := range symbols {
+		symbolsMap[strings.ToUpper(s)] = true
+	}
+
+	result := make(map[string]map[string]interface{})
+	for _, data := range resp.Tickers {
+		symbol := strings.ToUpper(k.SafeString(data, "symbol", ""))
+		// 跳过指数、定期合约和已暂停的合约
+		if !isPerpetual(symbol) {
+			continue
+		}
+		if suspended, ok := data["suspended"].(bool); ok && suspended {
+			continue
+		}
+		if len(symbols) > 0 && !symbolsMap[symbol] {
+			continue
+		}
+		result[symbol] = data
+	}
+	return result, nil
+}
+
+// parseTicker 解析ticker数据
+func (k *Kraken) parseTicker(data map[string]interface{}) *types.Ticker {
+	ts := k.parseTime(k.SafeString(data, "lastTime", ""))
+	lastPrice := k.SafeFloat(data, "last", 0)
+	openPrice := k.SafeFloat(data, "open24h", 0)
+
+	// 计算涨跌幅
+	change := lastPrice - openPrice
+	percentage := 0.0
+	if openPrice > 0 {
+		percentage = (change / openPrice) * 100
+	}
+
+	return &types.Ticker{
+		Symbol:      strings.ToUpper(k.SafeString(data, "symbol", "")),
+		TimeStamp:   ts,
+		Datetime:    k.ISO8601(ts),
+		High:        k.SafeFloat(data, "high24h", 0),
+		Low:         k.SafeFloat(data, "low24h", 0),
+		Bid:         k.SafeFloat(data, "bid", 0),
+		BidVolume:   k.SafeFloat(data, "bidSize", 0),
+		Ask:         k.SafeFloat(data, "ask", 0),
+		AskVolume:   k.SafeFloat(data, "askSize", 0),
+		Open:        openPrice,
+		Last:        lastPrice,
+		Close:       lastPrice,
+		Change:      change,
+		Percentage:  percentage,
+		BaseVolume:  k.SafeFloat(data, "vol24h", 0),
+		QuoteVolume: k.SafeFloat(data, "volumeQuote", 0),
+		Info:        data,
+	}
+}
+
+// parseTime 解析ISO8601时间为毫秒时间戳，失败时返回当前时间
+func (k *Kraken) parseTime(value string) int64 {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UnixMilli()
+	}
+	return time.Now().UnixMilli()
+}
+
+// FetchKlines 获取K线数据
+func (k *Kraken) FetchKlines(ctx context.Context, symbol, interval string, since int64, limit int, params map[string]interface{}) ([]*types.Kline, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol不能为空")
+	}
+
+	resolution, ok := k.BaseExchange.GetTimeframes()[interval]
+	if !ok {
+		return nil, fmt.Errorf("不支持的时间周期: %s", interval)
+	}
+
+	tickType := ChartTickTypeTrade
+	if params != nil {
+		if t, ok := params["tickType"].(string); ok && t != "" {
+			tickType = t
+		}
+	}
+
+	query := make(map[string]interface{})
+	if limit > 0 {
+		if limit > MaxCandles {
+			limit = MaxCandles
+		}
+		query["count"] = limit
+	}
+	if since > 0 {
+		query["from"] = since / 1000 // 接口使用秒级时间戳
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/%s/%s", k.endpoints["charts"], tickType, url.PathEscape(strings.ToUpper(symbol)), resolution)
+
+	var resp struct {
+		Candles []map[string]interface{} `json:"candles"`
+	}
+	if err := k.get(ctx, endpoint, query, &resp); err != nil {
+		return nil, err
+	}
+
+	klines := make([]*types.Kline, 0, len(resp.Candles))
+	for _, candle := range resp.Candles {
+		klines = append(klines, &types.Kline{
+			Symbol:    symbol,
+			Timeframe: interval,
+			Timestamp: k.SafeInteger(candle, "time", 0),
+			Open:      k.SafeFloat(candle, "open", 0),
+			High:      k.SafeFloat(candle, "high", 0),
+			Low:       k.SafeFloat(candle, "low", 0),
+			Close:     k.SafeFloat(candle, "close", 0),
+			Volume:    k.SafeFloat(candle, "volume", 0),
+			IsClosed:  true,
+		})
+	}
+	return klines, nil
+}
+
+// ========== 订单簿API ==========
+
+// FetchOrderBook 获取订单簿
+func (k *Kraken) FetchOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol不能为空")
+	}
+
+	var resp struct {
+		OrderBook struct {
+			Bids [][]float64 `json:"bids"`
+			Asks [][]float64 `json:"asks"`
+		} `json:"orderBook"`
+		ServerTime string `json:"serverTime"`
+	}
+	params := map[string]interface{}{"symbol": strings.ToUpper(symbol)}
+	if err := k.get(ctx, k.endpoints["orderbook"], params, &resp); err != nil {
+		return nil, fmt.Errorf("获取订单簿失败: %w", err)
+	}
+
+	timestamp := k.parseTime(resp.ServerTime)
+	return &types.OrderBook{
+		Symbol:    symbol,
+		Bids:      parseOrderBookSide(resp.OrderBook.Bids, limit),
+		Asks:      parseOrderBookSide(resp.OrderBook.Asks, limit),
+		TimeStamp: timestamp,
+		Datetime:  time.UnixMilli(timestamp).UTC().Format(time.RFC3339Nano),
+	}, nil
+}
+
+// parseOrderBookSide 解析订单簿单边档位，limit 大于0时截断到指定档数
+func parseOrderBookSide(levels [][]float64, limit int) types.OrderBookSide {
+	if limit > 0 && len(levels) > limit {
+		levels = levels[:limit]
+	}
+
+	side := types.OrderBookSide{
+		Price: make([]float64, 0, len(levels)),
+		Size:  make([]float64, 0, len(levels)),
+	}
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		side.Price = append(side.Price, level[0])
+		side.Size = append(side.Size, level[1])
+	}
+	return side
+}
+
+// ========== 标记价格API ==========
+
+// FetchMarkPrice 获取单个交易对的标记价格
+func (k *Kraken) FetchMarkPrice(ctx context.Context, symbol string) (*types.MarkPrice, error) {
+	prices, err := k.FetchMarkPrices(ctx, []string{symbol})
+	if err != nil {
+		return nil, err
+	}
+
+	price, exists := prices[strings.ToUpper(symbol)]
+	if !exists {
+		return nil, fmt.Errorf("未找到交易对 %s 的标记价格", symbol)
+	}
+	return price, nil
+}
+
+// FetchMarkPrices 获取多个交易对的标记价格
+func (k *Kraken) FetchMarkPrices(ctx context.Context, symbols []string) (map[string]*types.MarkPrice, error) {
+	// ticker中包含标记价格、指数价格和资金费率
+	data, err := k.fetchRawTickers(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	nextFunding := (now/FundingInterval + 1) * FundingInterval
+
+	result := make(map[string]*types.MarkPrice, len(data))
+	for symbol, item := range data {
+		markPrice := k.SafeFloat(item, "markPrice", 0)
+
+		// Kraken返回的是每张合约的绝对资金费用，换算为相对标记价格的每小时费率
+		fundingRate := 0.0
+		if markPrice > 0 {
+			fundingRate = k.SafeFloat(item, "fundingRate", 0) / markPrice
+		}
+
+		result[symbol] = &types.MarkPrice{
+			Symbol:          symbol,
+			MarkPrice:       markPrice,
+			IndexPrice:      k.SafeFloat(item, "indexPrice", 0),
+			FundingRate:     fundingRate,
+			NextFundingTime: nextFunding,
+			Timestamp:       now,
+			Info:            item,
+		}
+	}
+	return result, nil
+}
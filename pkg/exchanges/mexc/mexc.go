@@ -25,6 +25,7 @@ func New(config *Config) (*MEXC, error) {
 	}
 
 	base := exchanges.NewBaseExchange("mexc", "MEXC", "v3", []string{"CN", "SG"})
+	base.SetTimeout(time.Duration(config.Timeout) * time.Millisecond)
 	mexc := &MEXC{
 		BaseExchange: base,
 		config:       config.Clone(),
@@ -0,0 +1,28 @@
+package mexc
+
+import (
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+func init() {
+	exchanges.Register(exchanges.Descriptor{
+		ID:           "mexc",
+		Name:         "MEXC",
+		Version:      "v3",
+		Website:      "https://www.mexc.com",
+		Countries:    []string{"SG"},
+		MarketTypes:  []string{types.MarketTypeSpot},
+		Capabilities: []string{"fetchMarkets", "fetchTicker", "fetchTickers", "fetchKline"},
+		Constructor: func(marketType string) (interface{}, error) {
+			config := DefaultConfig()
+			config.MarketType = marketType
+
+			exchange, err := New(config)
+			if err != nil {
+				return nil, err
+			}
+			return exchange, nil
+		},
+	})
+}
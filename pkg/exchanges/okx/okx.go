@@ -27,6 +27,12 @@ func New(config *Config) (*OKX, error) {
 	}
 
 	base := exchanges.NewBaseExchange("okx", "OKX", "v5", []string{"SC"})
+	base.SetTimeout(time.Duration(config.Timeout) * time.Millisecond)
+	if config.Proxy != "" {
+		if err := base.SetProxy(config.Proxy); err != nil {
+			return nil, err
+		}
+	}
 	okx := &OKX{
 		BaseExchange: base,
 		config:       config.Clone(),
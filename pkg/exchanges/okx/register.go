@@ -0,0 +1,31 @@
+package okx
+
+import (
+	"fmt"
+	"trading_assistant/pkg/exchanges"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+func init() {
+	exchanges.Register(exchanges.Descriptor{
+		ID:           "okx",
+		Name:         "OKX",
+		Version:      "v5",
+		Website:      "https://www.okx.com",
+		Countries:    []string{"SC"},
+		MarketTypes:  []string{types.MarketTypeSpot, types.MarketTypeFuture},
+		Capabilities: []string{"fetchMarkets", "fetchTicker", "fetchTickers", "fetchKline", "fetchMarkPrice"},
+		Constructor: func(marketType string) (interface{}, error) {
+			config := DefaultConfig()
+			if err := config.SetMarketType(marketType); err != nil {
+				return nil, fmt.Errorf("设置OKX市场类型失败: %w", err)
+			}
+
+			exchange, err := New(config)
+			if err != nil {
+				return nil, err
+			}
+			return exchange, nil
+		},
+	})
+}
@@ -0,0 +1,205 @@
+package exchanges
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimitCategory 未指定端点类别时使用的预算
+const DefaultRateLimitCategory = "default"
+
+// RateLimitBudget 端点类别在时间窗口内允许消耗的请求权重
+type RateLimitBudget struct {
+	Capacity int           `json:"capacity"` // 窗口内的总权重
+	Window   time.Duration `json:"window"`   // 窗口长度
+}
+
+// tokenBucket 令牌桶，令牌按 capacity/window 的速度匀速恢复
+type tokenBucket struct {
+	capacity float64
+	tokens   float64
+	rate     float64 // 每秒恢复的令牌数
+	updated  time.Time
+}
+
+// refill 按流逝时间恢复令牌
+func (t *tokenBucket) refill(now time.Time) {
+	t.tokens += now.Sub(t.updated).Seconds() * t.rate
+	if t.tokens > t.capacity {
+		t.tokens = t.capacity
+	}
+	t.updated = now
+}
+
+// RateLimiter 按端点类别划分的权重令牌桶，在请求前主动等待，避免触发交易所封禁
+type RateLimiter struct {
+	mu          sync.Mutex
+	buckets     map[string]*tokenBucket
+	pausedUntil time.Time // 收到429/418后暂停所有请求直到该时间
+}
+
+// NewRateLimiter 创建限流器
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// SetBudget 设置端点类别的权重预算，容量或窗口为0时移除该类别的限制
+func (r *RateLimiter) SetBudget(category string, budget RateLimitBudget) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if budget.Capacity <= 0 || budget.Window <= 0 {
+		delete(r.buckets, category)
+		return
+	}
+	capacity := float64(budget.Capacity)
+	r.buckets[category] = &tokenBucket{
+		capacity: capacity,
+		tokens:   capacity,
+		rate:     capacity / budget.Window.Seconds(),
+		updated:  time.Now(),
+	}
+}
+
+// bucket 获取类别对应的令牌桶，没有单独预算时使用默认预算
+func (r *RateLimiter) bucket(category string) *tokenBucket {
+	if bucket, exists := r.buckets[category]; exists {
+		return bucket
+	}
+	return r.buckets[DefaultRateLimitCategory]
+}
+
+// Wait 等待直到有足够的权重可用，ctx 取消时返回错误
+func (r *RateLimiter) Wait(ctx context.Context, category string, weight int) error {
+	if weight <= 0 {
+		weight = 1
+	}
+
+	for {
+		r.mu.Lock()
+		now := time.Now()
+		var delay time.Duration
+		if now.Before(r.pausedUntil) {
+			delay = r.pausedUntil.Sub(now)
+		} else if bucket := r.bucket(category); bucket != nil {
+			bucket.refill(now)
+			need := float64(weight)
+			if need > bucket.capacity {
+				need = bucket.capacity // 单次请求超过容量时至少等桶满
+			}
+			if bucket.tokens >= need {
+				bucket.tokens -= need
+			} else {
+				delay = time.Duration((need - bucket.tokens) / bucket.rate * float64(time.Second))
+			}
+		}
+		r.mu.Unlock()
+
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Observe 根据响应头校准剩余权重，支持 X-MBX-USED-WEIGHT-* (已用权重) 和 X-Bapi-Limit-Status / X-RateLimit-Remaining (剩余次数)
+func (r *RateLimiter) Observe(category string, headers map[string]string) {
+	remaining := -1.0
+	for key, value := range headers {
+		lower := strings.ToLower(key)
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(lower, "x-mbx-used-weight-"):
+			r.mu.Lock()
+			if bucket := r.bucket(category); bucket != nil {
+				remaining = bucket.capacity - number
+			}
+			r.mu.Unlock()
+		case lower == "x-bapi-limit-status", lower == "x-ratelimit-remaining":
+			remaining = number
+		}
+	}
+	if remaining < 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if bucket := r.bucket(category); bucket != nil {
+		bucket.refill(time.Now())
+		// 交易所的统计比本地估算更准确，只向下校准
+		if remaining < bucket.tokens {
+			bucket.tokens = remaining
+		}
+	}
+}
+
+// PauseFor 暂停所有请求一段时间，用于交易所返回429/418时
+func (r *RateLimiter) PauseFor(duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	until := time.Now().Add(duration)
+	if until.After(r.pausedUntil) {
+		r.pausedUntil = until
+	}
+	for _, bucket := range r.buckets {
+		bucket.tokens = 0
+		bucket.updated = until // 暂停结束后才开始恢复
+	}
+}
+
+// ========== 请求权重上下文 ==========
+
+// requestWeightKey 请求权重在 context 中的键
+type requestWeightKey struct{}
+
+// requestWeight 单次请求的端点类别和权重
+type requestWeight struct {
+	category string
+	weight   int
+}
+
+// WithRequestWeight 为请求指定端点类别和权重，未指定时按默认类别权重1计算
+func WithRequestWeight(ctx context.Context, category string, weight int) context.Context {
+	return context.WithValue(ctx, requestWeightKey{}, requestWeight{category: category, weight: weight})
+}
+
+// requestWeightFrom 从 context 中读取请求权重
+func requestWeightFrom(ctx context.Context) (string, int) {
+	if value, ok := ctx.Value(requestWeightKey{}).(requestWeight); ok {
+		if value.category == "" {
+			value.category = DefaultRateLimitCategory
+		}
+		return value.category, value.weight
+	}
+	return DefaultRateLimitCategory, 1
+}
+
+// parseRetryAfter 解析 Retry-After 响应头（秒）
+func parseRetryAfter(headers map[string]string) time.Duration {
+	for key, value := range headers {
+		if strings.EqualFold(key, "Retry-After") {
+			if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && seconds > 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return 0
+}
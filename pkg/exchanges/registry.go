@@ -0,0 +1,77 @@
+package exchanges
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Constructor 交易所构造函数，返回的实例需实现 exchange_factory.ExchangeInterface
+type Constructor func(marketType string) (interface{}, error)
+
+// Descriptor 交易所注册信息
+type Descriptor struct {
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	Version      string      `json:"version"`
+	Website      string      `json:"website"`
+	Countries    []string    `json:"countries"`
+	MarketTypes  []string    `json:"market_types"` // 支持的市场类型
+	Capabilities []string    `json:"capabilities"` // 支持的功能，如 fetchMarkPrice、fetchOrderBook
+	Constructor  Constructor `json:"-"`
+}
+
+// SupportsMarketType 是否支持指定市场类型
+func (d Descriptor) SupportsMarketType(marketType string) bool {
+	for _, supported := range d.MarketTypes {
+		if supported == marketType {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Descriptor)
+)
+
+// Register 注册交易所，由各交易所包在 init() 中调用
+func Register(descriptor Descriptor) {
+	id := strings.ToLower(strings.TrimSpace(descriptor.ID))
+	if id == "" || descriptor.Constructor == nil {
+		panic("交易所注册信息缺少ID或构造函数")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[id]; exists {
+		panic(fmt.Sprintf("交易所 %s 重复注册", id))
+	}
+	descriptor.ID = id
+	registry[id] = descriptor
+}
+
+// Lookup 查找已注册的交易所
+func Lookup(id string) (Descriptor, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	descriptor, exists := registry[strings.ToLower(strings.TrimSpace(id))]
+	return descriptor, exists
+}
+
+// Registered 获取所有已注册的交易所，按ID排序
+func Registered() []Descriptor {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	descriptors := make([]Descriptor, 0, len(registry))
+	for _, descriptor := range registry {
+		descriptors = append(descriptors, descriptor)
+	}
+	sort.Slice(descriptors, func(i, j int) bool { return descriptors[i].ID < descriptors[j].ID })
+	return descriptors
+}
@@ -0,0 +1,140 @@
+package exchanges
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// streamLagSmoothing 事件延迟指数平滑系数，单条消息的排队抖动不影响整体判断
+const streamLagSmoothing = 0.1
+
+// StreamConnectionStats 推送连接的延迟和时钟偏差统计
+type StreamConnectionStats struct {
+	ID            string     `json:"id"`
+	Exchange      string     `json:"exchange"`
+	Feature       string     `json:"feature"` // 订阅的推送，如 watchTrades
+	Streams       int        `json:"streams"` // 连接订阅的流数量
+	Connected     bool       `json:"connected"`
+	Compressed    bool       `json:"compressed"` // 服务端是否同意 permessage-deflate 压缩
+	ConnectedAt   *time.Time `json:"connected_at,omitempty"`
+	Reconnects    int        `json:"reconnects"`
+	Messages      int64      `json:"messages"`
+	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+	PingRTTMs     float64    `json:"ping_rtt_ms"` // 最近一次ping往返耗时
+	PingAt        *time.Time `json:"ping_at,omitempty"`
+	EventLagMs    float64    `json:"event_lag_ms"`  // 本地接收时间减去推送事件时间，含单程网络延迟和时钟偏差
+	ClockSkewMs   float64    `json:"clock_skew_ms"` // 估算的本地时钟偏差（事件延迟减去半个RTT），正值表示本地时钟快于交易所
+}
+
+// StreamConnection 单个推送连接的统计，由适配器在连接生命周期内更新
+type StreamConnection struct {
+	mu    sync.Mutex
+	stats StreamConnectionStats
+}
+
+var (
+	streamConnectionSeq int64
+	streamConnections   sync.Map // ID -> *StreamConnection
+)
+
+// RegisterStreamConnection 登记推送连接，连接不再重连时调用 Close 注销
+func RegisterStreamConnection(exchange, feature string, streams int) *StreamConnection {
+	id := fmt.Sprintf("%s-%s-%d", exchange, feature, atomic.AddInt64(&streamConnectionSeq, 1))
+	conn := &StreamConnection{stats: StreamConnectionStats{
+		ID:       id,
+		Exchange: exchange,
+		Feature:  feature,
+		Streams:  streams,
+	}}
+	streamConnections.Store(id, conn)
+	return conn
+}
+
+// GetStreamConnectionStats 所有推送连接的统计，按ID排序
+func GetStreamConnectionStats() []StreamConnectionStats {
+	var result []StreamConnectionStats
+	streamConnections.Range(func(_, value any) bool {
+		result = append(result, value.(*StreamConnection).Stats())
+		return true
+	})
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Close 注销连接
+func (c *StreamConnection) Close() {
+	streamConnections.Delete(c.stats.ID)
+}
+
+// Stats 当前统计的副本
+func (c *StreamConnection) Stats() StreamConnectionStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// MarkConnected 连接建立，第二次及之后的连接计为重连，延迟统计从新连接重新计算
+func (c *StreamConnection) MarkConnected(compressed bool) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stats.ConnectedAt != nil {
+		c.stats.Reconnects++
+	}
+	c.stats.Connected = true
+	c.stats.Compressed = compressed
+	c.stats.ConnectedAt = &now
+	c.stats.PingRTTMs = 0
+	c.stats.PingAt = nil
+	c.stats.EventLagMs = 0
+	c.stats.ClockSkewMs = 0
+}
+
+// MarkDisconnected 连接断开
+func (c *StreamConnection) MarkDisconnected() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Connected = false
+}
+
+// ObserveEvent 记录一条推送，eventTime 为推送中的事件时间（毫秒），0表示推送不带事件时间
+func (c *StreamConnection) ObserveEvent(eventTime int64, receivedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Messages++
+	c.stats.LastMessageAt = &receivedAt
+	if eventTime <= 0 {
+		return
+	}
+
+	lag := float64(receivedAt.UnixMicro()-eventTime*1000) / 1000
+	if c.stats.EventLagMs == 0 {
+		c.stats.EventLagMs = lag
+	} else {
+		c.stats.EventLagMs += streamLagSmoothing * (lag - c.stats.EventLagMs)
+	}
+	c.updateSkew()
+}
+
+// ObservePing 记录一次ping往返耗时
+func (c *StreamConnection) ObservePing(rtt time.Duration) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.PingRTTMs = float64(rtt.Microseconds()) / 1000
+	c.stats.PingAt = &now
+	c.updateSkew()
+}
+
+// updateSkew 按事件延迟和RTT估算时钟偏差，假设单程延迟为RTT的一半，调用方需持有锁
+func (c *StreamConnection) updateSkew() {
+	if c.stats.PingAt == nil || c.stats.EventLagMs == 0 {
+		return
+	}
+	c.stats.ClockSkewMs = c.stats.EventLagMs - c.stats.PingRTTMs/2
+}
@@ -0,0 +1,133 @@
+package exchanges
+
+import (
+	"strings"
+	"sync"
+	"trading_assistant/pkg/exchanges/types"
+)
+
+// SymbolMapper 基于已加载的市场数据在统一符号、交易所MarketID和显示符号之间转换
+//
+//	统一符号: BTC/USDT:USDT（与Freqtrade交易对格式一致）
+//	MarketID: BTCUSDT、BTC-USDT-SWAP 等交易所原始ID
+//	显示符号: BTCUSDT（基础货币+计价货币）
+type SymbolMapper struct {
+	mu         sync.RWMutex
+	byMarketID map[string]*types.Market
+	byKey      map[string]*types.Market // 规范化后的符号 -> 市场
+}
+
+var (
+	symbolMappers      = make(map[string]*SymbolMapper)
+	symbolMappersMutex sync.Mutex
+)
+
+// NewSymbolMapper 创建符号转换器
+func NewSymbolMapper() *SymbolMapper {
+	return &SymbolMapper{
+		byMarketID: make(map[string]*types.Market),
+		byKey:      make(map[string]*types.Market),
+	}
+}
+
+// SymbolMapperFor 获取指定交易所的符号转换器，不存在时创建空的转换器
+func SymbolMapperFor(exchangeID string) *SymbolMapper {
+	exchangeID = strings.ToLower(strings.TrimSpace(exchangeID))
+
+	symbolMappersMutex.Lock()
+	defer symbolMappersMutex.Unlock()
+
+	mapper, exists := symbolMappers[exchangeID]
+	if !exists {
+		mapper = NewSymbolMapper()
+		symbolMappers[exchangeID] = mapper
+	}
+	return mapper
+}
+
+// Load 用市场数据替换转换表
+func (m *SymbolMapper) Load(markets []*types.Market) {
+	byMarketID := make(map[string]*types.Market, len(markets))
+	byKey := make(map[string]*types.Market, len(markets)*2)
+	for _, market := range markets {
+		if market == nil || market.ID == "" {
+			continue
+		}
+		byMarketID[market.ID] = market
+		byKey[normalizeSymbolKey(market.Base+market.Quote)] = market
+		if market.Symbol != "" {
+			byKey[normalizeSymbolKey(market.Symbol)] = market
+		}
+	}
+
+	m.mu.Lock()
+	m.byMarketID = byMarketID
+	m.byKey = byKey
+	m.mu.Unlock()
+}
+
+// Loaded 是否已加载市场数据
+func (m *SymbolMapper) Loaded() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.byMarketID) > 0
+}
+
+// Resolve 将任意格式的符号解析为市场，支持 BTCUSDT、BTC/USDT、BTC/USDT:USDT、BTC-USDT-SWAP 等写法
+func (m *SymbolMapper) Resolve(symbol string) (*types.Market, bool) {
+	symbol = strings.TrimSpace(symbol)
+	if symbol == "" {
+		return nil, false
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if market, exists := m.byMarketID[symbol]; exists {
+		return market, true
+	}
+	if market, exists := m.byMarketID[strings.ToUpper(symbol)]; exists {
+		return market, true
+	}
+	market, exists := m.byKey[normalizeSymbolKey(symbol)]
+	return market, exists
+}
+
+// MarketID 转换为交易所MarketID，无法解析时原样返回
+func (m *SymbolMapper) MarketID(symbol string) string {
+	if market, ok := m.Resolve(symbol); ok {
+		return market.ID
+	}
+	return symbol
+}
+
+// Unified 转换为统一符号，无法解析时返回空
+func (m *SymbolMapper) Unified(symbol string) string {
+	if market, ok := m.Resolve(symbol); ok {
+		return market.Symbol
+	}
+	return ""
+}
+
+// Display 转换为显示符号，无法解析时原样返回
+func (m *SymbolMapper) Display(symbol string) string {
+	if market, ok := m.Resolve(symbol); ok {
+		return market.Base + market.Quote
+	}
+	return symbol
+}
+
+// normalizeSymbolKey 去掉分隔符、结算货币和合约后缀，得到 BASEQUOTE 形式的比较键
+func normalizeSymbolKey(symbol string) string {
+	key := strings.ToUpper(strings.TrimSpace(symbol))
+	if idx := strings.Index(key, ":"); idx > 0 {
+		key = key[:idx]
+	}
+	for _, suffix := range []string{"-SWAP", "_PERP", "-PERP", "PERP"} {
+		if strings.HasSuffix(key, suffix) && len(key) > len(suffix) {
+			key = strings.TrimSuffix(key, suffix)
+			break
+		}
+	}
+	return strings.NewReplacer("/", "", "-", "", "_", "").Replace(key)
+}
@@ -0,0 +1,87 @@
+package exchanges
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPTransportConfig 交易所HTTP连接池配置
+type HTTPTransportConfig struct {
+	MaxIdleConns        int           `json:"max_idle_conns"`          // 所有主机的最大空闲连接数
+	MaxIdleConnsPerHost int           `json:"max_idle_conns_per_host"` // 单个主机的最大空闲连接数，突发请求时复用连接
+	IdleConnTimeout     time.Duration `json:"idle_conn_timeout"`       // 空闲连接保持时间
+	TLSHandshakeTimeout time.Duration `json:"tls_handshake_timeout"`   // TLS握手超时
+	TLSSessionCacheSize int           `json:"tls_session_cache_size"`  // TLS会话缓存大小，重连时复用会话跳过完整握手，0表示关闭
+	EnableHTTP2         bool          `json:"enable_http2"`            // 是否尝试HTTP/2
+	Proxy               string        `json:"proxy"`                   // 代理地址，为空时使用环境变量 HTTP(S)_PROXY
+}
+
+// DefaultHTTPTransportConfig 默认连接池配置
+func DefaultHTTPTransportConfig() HTTPTransportConfig {
+	return HTTPTransportConfig{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 32,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		TLSSessionCacheSize: 64,
+		EnableHTTP2:         true,
+	}
+}
+
+// newHTTPTransport 根据配置创建支持长连接的 Transport
+func newHTTPTransport(config HTTPTransportConfig) (*http.Transport, error) {
+	proxy := http.ProxyFromEnvironment
+	if config.Proxy != "" {
+		proxyURL, err := url.Parse(config.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, NewBadRequest(fmt.Sprintf("无效的代理地址: %s", config.Proxy))
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	transport := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          config.MaxIdleConns,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+		ForceAttemptHTTP2:     config.EnableHTTP2,
+		TLSClientConfig:       &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+	if config.TLSSessionCacheSize > 0 {
+		transport.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(config.TLSSessionCacheSize)
+	}
+	if !config.EnableHTTP2 {
+		// 非nil的空映射会禁用HTTP/2协商
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+	return transport, nil
+}
+
+// ========== 请求超时上下文 ==========
+
+// requestTimeoutKey 单次请求超时在 context 中的键
+type requestTimeoutKey struct{}
+
+// WithRequestTimeout 为单次请求指定超时，覆盖交易所的默认超时
+func WithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, timeout)
+}
+
+// requestTimeoutFrom 读取单次请求超时，未指定时使用默认值
+func requestTimeoutFrom(ctx context.Context, defaultTimeout time.Duration) time.Duration {
+	if timeout, ok := ctx.Value(requestTimeoutKey{}).(time.Duration); ok && timeout > 0 {
+		return timeout
+	}
+	return defaultTimeout
+}
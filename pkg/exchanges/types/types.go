@@ -157,6 +157,25 @@ type Trade struct {
 	Info         map[string]interface{} `json:"info"`         // 原始信息
 }
 
+// OpenInterest 合约持仓量
+type OpenInterest struct {
+	Symbol       string  `json:"symbol"`        // 交易对
+	OpenInterest float64 `json:"open_interest"` // 持仓量（合约数量）
+	Value        float64 `json:"value"`         // 持仓价值，交易所未返回时为0
+	Timestamp    int64   `json:"timestamp"`     // 统计时间
+}
+
+// Liquidation 强平订单
+type Liquidation struct {
+	Symbol       string  `json:"symbol"`        // 交易对
+	Side         string  `json:"side"`          // 强平订单方向: sell 为多头被强平，buy 为空头被强平
+	Price        float64 `json:"price"`         // 委托价格
+	AveragePrice float64 `json:"average_price"` // 成交均价
+	Amount       float64 `json:"amount"`        // 成交数量
+	Notional     float64 `json:"notional"`      // 成交额
+	Timestamp    int64   `json:"timestamp"`     // 成交时间
+}
+
 // OrderBookSide 订单簿一侧
 type OrderBookSide struct {
 	Price []float64 `json:"price"` // 价格数组
@@ -377,6 +396,7 @@ type WatchMarkPrice struct {
 	EstimatedSettlePrice float64 `json:"estimated_settle_price"` // 预估结算价
 	BidPrice             float64 `json:"bid_price"`              // 最优买价（实时）
 	AskPrice             float64 `json:"ask_price"`              // 最优卖价（实时）
+	LastPrice            float64 `json:"last_price"`             // 最新成交价
 }
 
 // WatchBookTicker WebSocket 最优买卖价数据
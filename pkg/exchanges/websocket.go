@@ -0,0 +1,56 @@
+package exchanges
+
+import (
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketConfig 交易所推送连接配置
+type WebSocketConfig struct {
+	Compression          bool `json:"compression"`           // 是否协商 permessage-deflate 压缩
+	CompressionThreshold int  `json:"compression_threshold"` // 单个连接订阅的流数量达到该值时才协商压缩，0表示总是协商
+}
+
+// DefaultWebSocketConfig 默认推送连接配置，不压缩
+func DefaultWebSocketConfig() WebSocketConfig {
+	return WebSocketConfig{}
+}
+
+// UseCompression 订阅 streams 个流的连接是否协商压缩
+// 订阅少量流时消息小、频率低，压缩节省的带宽抵不上双方的CPU开销
+func (c WebSocketConfig) UseCompression(streams int) bool {
+	return c.Compression && streams >= c.CompressionThreshold
+}
+
+// SetWebSocketConfig 设置推送连接配置，对之后建立的连接生效
+func (b *BaseExchange) SetWebSocketConfig(config WebSocketConfig) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.wsConfig = config
+}
+
+// GetWebSocketConfig 获取当前推送连接配置
+func (b *BaseExchange) GetWebSocketConfig() WebSocketConfig {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.wsConfig
+}
+
+// WebSocketDialer 创建订阅 streams 个流的推送连接使用的拨号器
+// 代理与HTTP请求一致，未设置时使用环境变量 HTTP(S)_PROXY；压缩只在服务端同意时生效，不支持的服务端按未压缩连接处理
+func (b *BaseExchange) WebSocketDialer(streams int) (*websocket.Dialer, error) {
+	b.mutex.RLock()
+	wsConfig := b.wsConfig
+	proxy := b.transportConfig.Proxy
+	b.mutex.RUnlock()
+
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = wsConfig.UseCompression(streams)
+	if proxy != "" {
+		transport, err := newHTTPTransport(HTTPTransportConfig{Proxy: proxy})
+		if err != nil {
+			return nil, err
+		}
+		dialer.Proxy = transport.Proxy
+	}
+	return &dialer, nil
+}
@@ -1,7 +1,7 @@
 package freqtrade
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,6 +15,7 @@ import (
 )
 
 type Controller struct {
+	Name           string // 实例名称，由 Registry 设置
 	BaseUrl        string
 	Username       string
 	Password       string
@@ -26,15 +27,23 @@ type Controller struct {
 	TradeStatus    []models.TradePosition
 	redisClient    *redis.Client
 	messageChan    chan string
+	reconciler     *Reconciler
+	requestOptions RequestOptions
+	ctx            context.Context // 控制器生命周期，Stop() 时取消所有进行中的请求
+	cancel         context.CancelFunc
 }
 
 func NewController(baseUrl, username, password string, redisClient *redis.Client) *Controller {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Controller{
-		BaseUrl:     baseUrl,
-		Username:    username,
-		Password:    password,
-		redisClient: redisClient,
-		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		BaseUrl:        baseUrl,
+		Username:       username,
+		Password:       password,
+		redisClient:    redisClient,
+		httpClient:     &http.Client{},
+		requestOptions: DefaultRequestOptions(),
+		ctx:            ctx,
+		cancel:         cancel,
 	}
 }
 
@@ -42,11 +51,18 @@ func NewController(baseUrl, username, password string, redisClient *redis.Client
 func (fc *Controller) Stop() {
 	logrus.Info("正在停止Freqtrade控制器...")
 
+	// 中止进行中的请求，避免慢请求拖住优雅退出
+	fc.cancel()
+
 	if fc.stopChan != nil {
 		close(fc.stopChan)
 		fc.stopChan = nil
 	}
 
+	if fc.reconciler != nil {
+		fc.reconciler.Stop()
+	}
+
 	logrus.Info("Freqtrade控制器已停止")
 }
 
@@ -73,34 +89,22 @@ func (fc *Controller) startTokenRefresher() {
 	}()
 }
 
-func (fc *Controller) doRequest(method, url string, body io.Reader, useAccessToken bool) ([]byte, error) {
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return nil, err
-	}
-
-	if useAccessToken {
-		req.Header.Set("Authorization", "Bearer "+fc.AccessToken)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := fc.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	respBody, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%s %s 请求失败: %s", method, url, string(respBody))
+// metricsPath 提取用于监控标签的请求路径（去掉基础地址和查询参数）
+func (fc *Controller) metricsPath(url string) string {
+	path := strings.TrimPrefix(url, fc.BaseUrl)
+	if idx := strings.Index(path, "?"); idx >= 0 {
+		path = path[:idx]
 	}
-	return respBody, nil
+	return path
 }
 
 func (fc *Controller) Init(messageChan chan string) error {
 	fc.messageChan = messageChan
 	url := fmt.Sprintf("%v/api/v1/token/login", fc.BaseUrl)
-	req, err := http.NewRequest("POST", url, nil)
+	ctx, cancel := context.WithTimeout(fc.ctx, fc.timeoutFor(url))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
 	if err != nil {
 		return fmt.Errorf("创建登录请求失败: %v", err)
 	}
@@ -135,8 +139,10 @@ func (fc *Controller) Init(messageChan chan string) error {
 
 func (fc *Controller) refreshToken() {
 	url := fmt.Sprintf("%v/api/v1/token/refresh", fc.BaseUrl)
+	ctx, cancel := context.WithTimeout(fc.ctx, fc.timeoutFor(url))
+	defer cancel()
 
-	req, err := http.NewRequest("POST", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
 	if err != nil {
 		logrus.Errorf("创建刷新请求失败: %v", err)
 		return
@@ -166,7 +172,7 @@ func (fc *Controller) refreshToken() {
 	logrus.Info("刷新 token 成功")
 }
 
-func (fc *Controller) ForceBuy(payload models.ForceBuyPayload) error {
+func (fc *Controller) ForceBuy(ctx context.Context, payload models.ForceBuyPayload) error {
 	url := fmt.Sprintf("%s/api/v1/forcebuy", fc.BaseUrl)
 
 	body, err := json.Marshal(payload)
@@ -174,16 +180,17 @@ func (fc *Controller) ForceBuy(payload models.ForceBuyPayload) error {
 		return err
 	}
 
-	respBody, err := fc.doRequest("POST", url, bytes.NewReader(body), true)
+	respBody, err := fc.doRequest(ctx, "POST", url, body, true)
 	if err != nil {
 		return err
 	}
 
 	logrus.Infof("forcebuy 成功: %s", string(respBody))
+	fc.recordResponse(ctx, url, respBody)
 	return nil
 }
 
-func (fc *Controller) ForceAdjustBuy(pair string, price float64, side string, stakeAmount float64, entryTag string) error {
+func (fc *Controller) ForceAdjustBuy(ctx context.Context, pair string, price float64, side string, stakeAmount float64, entryTag string) error {
 	url := fmt.Sprintf("%s/api/v1/forcebuy", fc.BaseUrl)
 	payload := models.ForceAdjustBuyPayload{
 		Pair:        pair,
@@ -199,16 +206,17 @@ func (fc *Controller) ForceAdjustBuy(pair string, price float64, side string, st
 		return err
 	}
 
-	respBody, err := fc.doRequest("POST", url, bytes.NewReader(body), true)
+	respBody, err := fc.doRequest(ctx, "POST", url, body, true)
 	if err != nil {
 		return err
 	}
 
 	logrus.Infof("forceadjustbuy 成功: %s", string(respBody))
+	fc.recordResponse(ctx, url, respBody)
 	return nil
 }
 
-func (fc *Controller) ForceSell(tradeId string, orderType string, amount string) error {
+func (fc *Controller) ForceSell(ctx context.Context, tradeId string, orderType string, amount string) error {
 	url := fmt.Sprintf("%s/api/v1/forcesell", fc.BaseUrl)
 	payload := models.ForceSellPayload{
 		TradeId:   tradeId,
@@ -221,18 +229,137 @@ func (fc *Controller) ForceSell(tradeId string, orderType string, amount string)
 		return err
 	}
 
-	respBody, err := fc.doRequest("POST", url, bytes.NewReader(body), true)
+	respBody, err := fc.doRequest(ctx, "POST", url, body, true)
 	if err != nil {
 		return err
 	}
 
 	logrus.Infof("forcesell 成功: %s", string(respBody))
+	fc.recordResponse(ctx, url, respBody)
+	return nil
+}
+
+// ForceExit 强制平仓，amount 大于0时部分平仓，否则全部平仓
+func (fc *Controller) ForceExit(ctx context.Context, tradeID int, orderType string, amount float64) error {
+	url := fmt.Sprintf("%s/api/v1/forceexit", fc.BaseUrl)
+	payload := models.ForceExitPayload{
+		TradeId:   fmt.Sprintf("%d", tradeID),
+		OrderType: orderType,
+	}
+	if amount > 0 {
+		payload.Amount = &amount
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	respBody, err := fc.doRequest(ctx, "POST", url, body, true)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("forceexit 成功: %s", string(respBody))
+	fc.recordResponse(ctx, url, respBody)
+	return nil
+}
+
+// CancelOpenOrder 撤销交易当前未成交的订单
+func (fc *Controller) CancelOpenOrder(ctx context.Context, tradeID int) error {
+	url := fmt.Sprintf("%s/api/v1/trades/%d/open-order", fc.BaseUrl, tradeID)
+	respBody, err := fc.doRequest(ctx, "DELETE", url, nil, true)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("撤销交易 %d 未成交订单成功: %s", tradeID, string(respBody))
+	fc.recordResponse(ctx, url, respBody)
+	return nil
+}
+
+// Ping 检查Freqtrade API是否可访问
+func (fc *Controller) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("%s/api/v1/ping", fc.BaseUrl)
+	_, err := fc.doRequest(ctx, "GET", url, nil, false)
+	return err
+}
+
+// ShowConfig 获取Freqtrade运行配置，用于确认认证有效且机器人处于运行状态
+func (fc *Controller) ShowConfig(ctx context.Context) (*models.FreqtradeShowConfig, error) {
+	url := fmt.Sprintf("%s/api/v1/show_config", fc.BaseUrl)
+	body, err := fc.doRequest(ctx, "GET", url, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var showConfig models.FreqtradeShowConfig
+	if err := json.Unmarshal(body, &showConfig); err != nil {
+		return nil, err
+	}
+	return &showConfig, nil
+}
+
+// DefaultStakeAmount 获取Freqtrade配置的每笔投入金额，配置为 unlimited 时按可用余额动态计算，返回错误
+func (fc *Controller) DefaultStakeAmount(ctx context.Context) (float64, error) {
+	showConfig, err := fc.ShowConfig(ctx)
+	if err != nil {
+		return 0, err
+	}
+	stake, ok := showConfig.StakeAmount.(float64)
+	if !ok || stake <= 0 {
+		return 0, fmt.Errorf("Freqtrade实例 %s 的 stake_amount 不是固定金额: %v", fc.Name, showConfig.StakeAmount)
+	}
+	return stake, nil
+}
+
+// GetBalance 获取账户余额和权益
+func (fc *Controller) GetBalance(ctx context.Context) (*models.FreqtradeBalance, error) {
+	url := fmt.Sprintf("%s/api/v1/balance", fc.BaseUrl)
+	body, err := fc.doRequest(ctx, "GET", url, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var balance models.FreqtradeBalance
+	if err := json.Unmarshal(body, &balance); err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// GetWhitelist 获取Freqtrade当前的交易对白名单
+func (fc *Controller) GetWhitelist(ctx context.Context) ([]string, error) {
+	url := fmt.Sprintf("%s/api/v1/whitelist", fc.BaseUrl)
+	body, err := fc.doRequest(ctx, "GET", url, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Whitelist []string `json:"whitelist"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Whitelist, nil
+}
+
+// ReloadConfig 让Freqtrade重新加载配置（RemotePairList 会立即重新拉取交易对）
+func (fc *Controller) ReloadConfig(ctx context.Context) error {
+	url := fmt.Sprintf("%s/api/v1/reload_config", fc.BaseUrl)
+	respBody, err := fc.doRequest(ctx, "POST", url, nil, true)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("reload_config 成功: %s", string(respBody))
 	return nil
 }
 
-func (fc *Controller) getCount() error {
+func (fc *Controller) getCount(ctx context.Context) error {
 	url := fmt.Sprintf("%v/api/v1/count", fc.BaseUrl)
-	body, err := fc.doRequest("GET", url, nil, true)
+	body, err := fc.doRequest(ctx, "GET", url, nil, true)
 	if err != nil {
 		return err
 	}
@@ -245,9 +372,9 @@ func (fc *Controller) getCount() error {
 	return nil
 }
 
-func (fc *Controller) getStatus() error {
+func (fc *Controller) getStatus(ctx context.Context) error {
 	url := fmt.Sprintf("%s/api/v1/status", fc.BaseUrl)
-	body, err := fc.doRequest("GET", url, nil, true)
+	body, err := fc.doRequest(ctx, "GET", url, nil, true)
 	if err != nil {
 		return err
 	}
@@ -260,13 +387,13 @@ func (fc *Controller) getStatus() error {
 	return nil
 }
 
-func (fc *Controller) fetchTradeData() error {
-	err := fc.getStatus()
+func (fc *Controller) fetchTradeData(ctx context.Context) error {
+	err := fc.getStatus(ctx)
 	if err != nil {
 		return err
 	}
 	// 获取当前持仓数量
-	err = fc.getCount()
+	err = fc.getCount(ctx)
 	if err != nil {
 		return err
 	}
@@ -274,8 +401,8 @@ func (fc *Controller) fetchTradeData() error {
 }
 
 // GetTradeStatus 获取当前交易状态
-func (fc *Controller) GetTradeStatus() ([]models.TradePosition, error) {
-	err := fc.getStatus()
+func (fc *Controller) GetTradeStatus(ctx context.Context) ([]models.TradePosition, error) {
+	err := fc.getStatus(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -283,8 +410,8 @@ func (fc *Controller) GetTradeStatus() ([]models.TradePosition, error) {
 }
 
 // 检查是否可以强制买入
-func (fc *Controller) CheckForceBuy(pair string) bool {
-	err := fc.fetchTradeData()
+func (fc *Controller) CheckForceBuy(ctx context.Context, pair string) bool {
+	err := fc.fetchTradeData(ctx)
 	if err != nil {
 		logrus.Errorf("获取交易数据失败: %v", err)
 		return false
@@ -437,9 +564,9 @@ func calculateGrindSummary(orders []models.FreqtradeOrder, isShort bool, totalAm
 }
 
 // GetPositions 获取当前持仓数据，直接返回freqtrade格式
-func (fc *Controller) GetPositions() ([]models.TradePosition, error) {
+func (fc *Controller) GetPositions(ctx context.Context) ([]models.TradePosition, error) {
 	// 获取freqtrade交易状态
-	tradePositions, err := fc.GetTradeStatus()
+	tradePositions, err := fc.GetTradeStatus(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("获取freqtrade交易状态失败: %v", err)
 	}
@@ -0,0 +1,369 @@
+package freqtrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/models"
+	"trading_assistant/pkg/eventbus"
+	"trading_assistant/pkg/i18n"
+	"trading_assistant/pkg/notify"
+	"trading_assistant/pkg/utils"
+	"trading_assistant/pkg/websocket"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 对账差异类型
+const (
+	DiscrepancyOrphanEstimate  = "orphan_estimate"           // 加仓/止盈预估没有对应的持仓
+	DiscrepancyUntrackedTrade  = "position_without_estimate" // 持仓没有任何监听中的预估
+	DiscrepancyStalePosition   = "stale_position"            // Redis缓存的持仓在Freqtrade中已不存在
+	DiscrepancyClosedPosition  = "closed_position"           // 缓存的持仓已在Freqtrade中平仓
+	AlertTypeReconcile         = "reconcile"
+	reconcileRecentTradesLimit = 50
+)
+
+// Discrepancy 对账差异事件
+type Discrepancy struct {
+	Type       string `json:"type"`
+	Symbol     string `json:"symbol"` // MarketID
+	Side       string `json:"side"`   // long, short
+	TradeID    int    `json:"trade_id,omitempty"`
+	EstimateID string `json:"estimate_id,omitempty"`
+	Message    string `json:"message"`
+	DetectedAt int64  `json:"detected_at"`
+}
+
+// key 差异唯一标识，用于去重
+func (d *Discrepancy) key() string {
+	return fmt.Sprintf("%s:%s:%s:%d:%s", d.Type, d.Symbol, d.Side, d.TradeID, d.EstimateID)
+}
+
+// ReconcileReport 一轮对账结果
+type ReconcileReport struct {
+	OpenTrades    int            `json:"open_trades"`
+	Discrepancies []*Discrepancy `json:"discrepancies"`
+	CheckedAt     int64          `json:"checked_at"`
+}
+
+// Reconciler Freqtrade交易对账器
+// 定时拉取 /api/v1/status 和 /api/v1/trades，与Redis中缓存的持仓和价格预估对比
+type Reconciler struct {
+	fc       *Controller
+	interval time.Duration
+
+	mu         sync.Mutex
+	stopChan   chan struct{}
+	seen       map[string]bool // 上一轮已上报的差异，只上报新出现的差异
+	lastReport *ReconcileReport
+}
+
+// NewReconciler 创建对账器
+func NewReconciler(fc *Controller, interval time.Duration) *Reconciler {
+	return &Reconciler{
+		fc:       fc,
+		interval: interval,
+		seen:     make(map[string]bool),
+	}
+}
+
+// StartReconciler 启动交易对账器，interval 为 0 时不启动
+func (fc *Controller) StartReconciler(interval time.Duration) {
+	if interval <= 0 {
+		logrus.Info("Freqtrade交易对账已关闭")
+		return
+	}
+	if fc.reconciler == nil {
+		fc.reconciler = NewReconciler(fc, interval)
+	}
+	fc.reconciler.Start()
+}
+
+// StopReconciler 停止交易对账器，未启动时忽略
+func (fc *Controller) StopReconciler() {
+	if fc.reconciler != nil {
+		fc.reconciler.Stop()
+	}
+}
+
+// GetReconciler 获取交易对账器，未启动时返回nil
+func (fc *Controller) GetReconciler() *Reconciler {
+	return fc.reconciler
+}
+
+// Start 启动定时对账
+func (r *Reconciler) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopChan != nil || r.interval <= 0 {
+		return
+	}
+	r.stopChan = make(chan struct{})
+
+	go func(stopChan chan struct{}) {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := r.Reconcile(r.fc.ctx); err != nil {
+					logrus.Errorf("Freqtrade对账失败: %v", err)
+				}
+			case <-stopChan:
+				return
+			}
+		}
+	}(r.stopChan)
+
+	logrus.Infof("Freqtrade对账器已启动，间隔: %v", r.interval)
+}
+
+// Stop 停止定时对账
+func (r *Reconciler) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopChan != nil {
+		close(r.stopChan)
+		r.stopChan = nil
+		logrus.Info("Freqtrade对账器已停止")
+	}
+}
+
+// GetLastReport 获取最近一次对账结果
+func (r *Reconciler) GetLastReport() *ReconcileReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastReport
+}
+
+// Reconcile 执行一次对账，新出现的差异推送到WebSocket和通知通道
+func (r *Reconciler) Reconcile(ctx context.Context) (*ReconcileReport, error) {
+	openTrades, err := r.fc.GetTradeStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓状态失败: %w", err)
+	}
+	recentTrades, err := r.fc.GetRecentTrades(ctx, reconcileRecentTradesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("获取历史交易失败: %w", err)
+	}
+
+	discrepancies, err := r.diff(openTrades, recentTrades)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReconcileReport{
+		OpenTrades:    len(openTrades),
+		Discrepancies: discrepancies,
+		CheckedAt:     time.Now().UnixMilli(),
+	}
+
+	r.mu.Lock()
+	current := make(map[string]bool, len(discrepancies))
+	var fresh []*Discrepancy
+	for _, d := range discrepancies {
+		current[d.key()] = true
+		if !r.seen[d.key()] {
+			fresh = append(fresh, d)
+		}
+	}
+	r.seen = current
+	r.lastReport = report
+	r.mu.Unlock()
+
+	for _, d := range fresh {
+		r.emit(d)
+	}
+
+	// 用最新的持仓刷新缓存
+	r.refreshPositionCache(openTrades)
+
+	return report, nil
+}
+
+// diff 对比Freqtrade交易、缓存持仓和价格预估
+func (r *Reconciler) diff(openTrades, recentTrades []models.TradePosition) ([]*Discrepancy, error) {
+	now := time.Now().UnixMilli()
+	var discrepancies []*Discrepancy
+
+	// 按 MarketID+方向 索引未平仓交易
+	open := make(map[string]*models.TradePosition)
+	for i := range openTrades {
+		trade := &openTrades[i]
+		if trade.IsOpen {
+			open[tradeKey(trade)] = trade
+		}
+	}
+
+	// 最近平仓的交易
+	closed := make(map[string]*models.TradePosition)
+	for i := range recentTrades {
+		trade := &recentTrades[i]
+		if !trade.IsOpen {
+			closed[tradeKey(trade)] = trade
+		}
+	}
+
+	estimates, err := r.fc.redisClient.GetActiveEstimates()
+	if err != nil {
+		return nil, fmt.Errorf("获取价格预估失败: %w", err)
+	}
+
+	// 1. 加仓/止盈/止损预估没有对应持仓
+	tracked := make(map[string]bool)
+	for _, estimate := range estimates {
+		key := estimate.Symbol + ":" + estimate.Side
+		tracked[key] = true
+		if estimate.ActionType == models.ActionTypeOpen {
+			continue
+		}
+		if _, exists := open[key]; !exists {
+			discrepancies = append(discrepancies, &Discrepancy{
+				Type:       DiscrepancyOrphanEstimate,
+				Symbol:     estimate.Symbol,
+				Side:       estimate.Side,
+				EstimateID: estimate.ID,
+				Message:    fmt.Sprintf("%s %s %s 预估没有对应的持仓", estimate.Symbol, estimate.Side, estimate.ActionType),
+				DetectedAt: now,
+			})
+		}
+	}
+
+	// 2. 持仓没有任何监听中的预估
+	for key, trade := range open {
+		if tracked[key] {
+			continue
+		}
+		symbol, side := splitTradeKey(key)
+		discrepancies = append(discrepancies, &Discrepancy{
+			Type:       DiscrepancyUntrackedTrade,
+			Symbol:     symbol,
+			Side:       side,
+			TradeID:    trade.TradeId,
+			Message:    fmt.Sprintf("%s %s 持仓 (trade %d) 没有监听中的预估", symbol, side, trade.TradeId),
+			DetectedAt: now,
+		})
+	}
+
+	// 3. 缓存的持仓在Freqtrade中已不存在
+	cached, err := r.fc.redisClient.GetAllPositions()
+	if err != nil {
+		return nil, fmt.Errorf("获取缓存持仓失败: %w", err)
+	}
+	for _, position := range cached {
+		side := strings.ToLower(position.Side)
+		key := position.Symbol + ":" + side
+		if _, exists := open[key]; exists {
+			continue
+		}
+
+		d := &Discrepancy{
+			Type:       DiscrepancyStalePosition,
+			Symbol:     position.Symbol,
+			Side:       side,
+			Message:    fmt.Sprintf("缓存的 %s %s 持仓在Freqtrade中不存在", position.Symbol, side),
+			DetectedAt: now,
+		}
+		if trade, exists := closed[key]; exists {
+			d.Type = DiscrepancyClosedPosition
+			d.TradeID = trade.TradeId
+			d.Message = fmt.Sprintf("缓存的 %s %s 持仓已在Freqtrade中平仓 (trade %d)", position.Symbol, side, trade.TradeId)
+		}
+		discrepancies = append(discrepancies, d)
+	}
+
+	sort.Slice(discrepancies, func(i, j int) bool { return discrepancies[i].key() < discrepancies[j].key() })
+	return discrepancies, nil
+}
+
+// refreshPositionCache 用Freqtrade未平仓交易覆盖Redis持仓缓存
+func (r *Reconciler) refreshPositionCache(openTrades []models.TradePosition) {
+	if err := r.fc.redisClient.ClearAllPositions(); err != nil {
+		logrus.Errorf("清除持仓缓存失败: %v", err)
+		return
+	}
+
+	positions := make([]*models.Position, 0, len(openTrades))
+	for i := range openTrades {
+		trade := &openTrades[i]
+		if !trade.IsOpen {
+			continue
+		}
+		symbol, side := splitTradeKey(tradeKey(trade))
+
+		leverage := 1
+		if trade.Leverage != nil {
+			leverage = int(*trade.Leverage)
+		}
+		position := &models.Position{
+			Symbol:     symbol,
+			Side:       strings.ToUpper(side),
+			Size:       trade.Amount,
+			EntryPrice: trade.OpenRate,
+			Leverage:   leverage,
+			UpdatedAt:  time.Now(),
+		}
+		if err := r.fc.redisClient.SetPosition(position); err != nil {
+			logrus.Errorf("缓存 %s 持仓失败: %v", symbol, err)
+		}
+		positions = append(positions, position)
+	}
+
+	eventbus.GetBus().Publish(eventbus.TopicPosition, "", positions)
+}
+
+// emit 推送差异事件
+func (r *Reconciler) emit(d *Discrepancy) {
+	logrus.Warnf("Freqtrade对账差异 [%s]: %s", d.Type, d.Message)
+
+	if wsManager := websocket.GetGlobalWebSocketManager(); wsManager != nil {
+		wsManager.BroadcastAlert(AlertTypeReconcile, d)
+	}
+
+	notify.Send(notify.EventReconcile, i18n.T("notify.reconcile.title"), d.Message, map[string]interface{}{
+		"type":     d.Type,
+		"symbol":   d.Symbol,
+		"side":     d.Side,
+		"trade_id": d.TradeID,
+	})
+}
+
+// tradeKey 交易的 MarketID:方向 标识
+func tradeKey(trade *models.TradePosition) string {
+	side := "long"
+	if trade.IsShort || trade.TradeDirection == "short" {
+		side = "short"
+	}
+	return utils.ConvertSymbolToMarketID(trade.Pair) + ":" + side
+}
+
+// splitTradeKey 拆分 MarketID:方向 标识
+func splitTradeKey(key string) (symbol, side string) {
+	idx := strings.LastIndex(key, ":")
+	return key[:idx], key[idx+1:]
+}
+
+// GetRecentTrades 获取最近的交易记录（包含已平仓）
+func (fc *Controller) GetRecentTrades(ctx context.Context, limit int) ([]models.TradePosition, error) {
+	url := fmt.Sprintf("%s/api/v1/trades?limit=%d", fc.BaseUrl, limit)
+	body, err := fc.doRequest(ctx, "GET", url, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Trades []models.TradePosition `json:"trades"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Trades, nil
+}
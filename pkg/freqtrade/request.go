@@ -0,0 +1,167 @@
+package freqtrade
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"trading_assistant/pkg/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RequestOptions Freqtrade API 请求的超时和重试配置
+type RequestOptions struct {
+	Timeout          time.Duration            // 默认单次请求超时
+	EndpointTimeouts map[string]time.Duration // 按端点覆盖超时，键为 /api/v1 之后的路径，如 forcebuy
+	MaxRetries       int                      // 幂等请求遇到网络错误或5xx时的最大重试次数
+	RetryBackoff     time.Duration            // 首次重试等待时间，之后按2倍递增
+}
+
+// DefaultRequestOptions 默认请求配置
+func DefaultRequestOptions() RequestOptions {
+	return RequestOptions{
+		Timeout:      10 * time.Second,
+		MaxRetries:   2,
+		RetryBackoff: 500 * time.Millisecond,
+	}
+}
+
+// ParseEndpointTimeouts 解析 "端点=时长" 格式的超时配置，无效项会被忽略
+func ParseEndpointTimeouts(entries []string) map[string]time.Duration {
+	timeouts := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		endpoint, value, found := strings.Cut(entry, "=")
+		if !found {
+			logrus.Warnf("忽略无效的Freqtrade端点超时配置: %s", entry)
+			continue
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || timeout <= 0 {
+			logrus.Warnf("忽略无效的Freqtrade端点超时配置: %s", entry)
+			continue
+		}
+		timeouts[strings.Trim(strings.TrimSpace(endpoint), "/")] = timeout
+	}
+	return timeouts
+}
+
+// SetRequestOptions 设置请求超时和重试配置
+func (fc *Controller) SetRequestOptions(options RequestOptions) {
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultRequestOptions().Timeout
+	}
+	if options.MaxRetries < 0 {
+		options.MaxRetries = 0
+	}
+	fc.requestOptions = options
+}
+
+// timeoutFor 获取端点的请求超时
+func (fc *Controller) timeoutFor(url string) time.Duration {
+	endpoint := strings.TrimPrefix(fc.metricsPath(url), "/api/v1/")
+	if timeout, ok := fc.requestOptions.EndpointTimeouts[endpoint]; ok && timeout > 0 {
+		return timeout
+	}
+	return fc.requestOptions.Timeout
+}
+
+// statusError 非200响应
+type statusError struct {
+	method     string
+	url        string
+	statusCode int
+	body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s %s 请求失败: %s", e.method, e.url, e.body)
+}
+
+// retryable 是否为可重试的临时错误（网络错误或5xx），调用方取消或控制器停止时不重试
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// doRequest 发送请求，ctx 取消或控制器 Stop() 时立即中止
+// 只有GET请求会重试，避免重复下单
+func (fc *Controller) doRequest(ctx context.Context, method, url string, body []byte, useAccessToken bool) (respBody []byte, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.FreqtradeRequestDuration.
+			WithLabelValues(method, fc.metricsPath(url), metrics.ResultLabel(err)).
+			Observe(time.Since(start).Seconds())
+	}()
+
+	// 控制器停止时取消所有进行中的请求
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(fc.ctx, cancel)
+	defer stop()
+
+	maxRetries := 0
+	if method == http.MethodGet {
+		maxRetries = fc.requestOptions.MaxRetries
+	}
+
+	backoff := fc.requestOptions.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		respBody, err = fc.sendOnce(ctx, method, url, body, useAccessToken)
+		if err == nil || attempt >= maxRetries || !retryable(ctx, err) {
+			return respBody, err
+		}
+
+		logrus.Warnf("Freqtrade请求失败，%v 后第 %d 次重试: %v", backoff, attempt+1, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// sendOnce 按端点超时发送一次请求
+func (fc *Controller) sendOnce(ctx context.Context, method, url string, body []byte, useAccessToken bool) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, fc.timeoutFor(url))
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if useAccessToken {
+		req.Header.Set("Authorization", "Bearer "+fc.AccessToken)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := fc.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{method: method, url: url, statusCode: resp.StatusCode, body: string(respBody)}
+	}
+	return respBody, nil
+}
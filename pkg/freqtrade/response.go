@@ -0,0 +1,20 @@
+package freqtrade
+
+import "context"
+
+// ResponseRecorder 下单接口成功后的响应回调，path 为接口路径
+type ResponseRecorder func(path string, body []byte)
+
+type responseRecorderKey struct{}
+
+// WithResponseRecorder 返回携带响应回调的ctx，使用该ctx的下单请求成功后回调原始响应
+func WithResponseRecorder(ctx context.Context, recorder ResponseRecorder) context.Context {
+	return context.WithValue(ctx, responseRecorderKey{}, recorder)
+}
+
+// recordResponse 回调ctx中的响应记录函数
+func (fc *Controller) recordResponse(ctx context.Context, url string, body []byte) {
+	if recorder, ok := ctx.Value(responseRecorderKey{}).(ResponseRecorder); ok && recorder != nil {
+		recorder(fc.metricsPath(url), body)
+	}
+}
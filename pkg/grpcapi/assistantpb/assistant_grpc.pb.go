@@ -0,0 +1,402 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: assistant/v1/assistant.proto
+
+// 交易助手 gRPC 接口：价格预估管理、标记价格和持仓
+// 生成代码: make proto
+
+package assistantpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AssistantService_ListEstimates_FullMethodName    = "/assistant.v1.AssistantService/ListEstimates"
+	AssistantService_GetEstimate_FullMethodName      = "/assistant.v1.AssistantService/GetEstimate"
+	AssistantService_CreateEstimate_FullMethodName   = "/assistant.v1.AssistantService/CreateEstimate"
+	AssistantService_UpdateEstimate_FullMethodName   = "/assistant.v1.AssistantService/UpdateEstimate"
+	AssistantService_DeleteEstimate_FullMethodName   = "/assistant.v1.AssistantService/DeleteEstimate"
+	AssistantService_GetMarkPrice_FullMethodName     = "/assistant.v1.AssistantService/GetMarkPrice"
+	AssistantService_StreamMarkPrices_FullMethodName = "/assistant.v1.AssistantService/StreamMarkPrices"
+	AssistantService_ListPositions_FullMethodName    = "/assistant.v1.AssistantService/ListPositions"
+)
+
+// AssistantServiceClient is the client API for AssistantService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AssistantServiceClient interface {
+	// 价格预估
+	ListEstimates(ctx context.Context, in *ListEstimatesRequest, opts ...grpc.CallOption) (*ListEstimatesResponse, error)
+	GetEstimate(ctx context.Context, in *GetEstimateRequest, opts ...grpc.CallOption) (*Estimate, error)
+	CreateEstimate(ctx context.Context, in *CreateEstimateRequest, opts ...grpc.CallOption) (*Estimate, error)
+	UpdateEstimate(ctx context.Context, in *UpdateEstimateRequest, opts ...grpc.CallOption) (*Estimate, error)
+	DeleteEstimate(ctx context.Context, in *DeleteEstimateRequest, opts ...grpc.CallOption) (*DeleteEstimateResponse, error)
+	// 行情
+	GetMarkPrice(ctx context.Context, in *GetMarkPriceRequest, opts ...grpc.CallOption) (*MarkPrice, error)
+	// 订阅标记价格，每轮价格获取完成后推送变化的币种
+	StreamMarkPrices(ctx context.Context, in *StreamMarkPricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MarkPrice], error)
+	// 持仓
+	ListPositions(ctx context.Context, in *ListPositionsRequest, opts ...grpc.CallOption) (*ListPositionsResponse, error)
+}
+
+type assistantServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAssistantServiceClient(cc grpc.ClientConnInterface) AssistantServiceClient {
+	return &assistantServiceClient{cc}
+}
+
+func (c *assistantServiceClient) ListEstimates(ctx context.Context, in *ListEstimatesRequest, opts ...grpc.CallOption) (*ListEstimatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEstimatesResponse)
+	err := c.cc.Invoke(ctx, AssistantService_ListEstimates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assistantServiceClient) GetEstimate(ctx context.Context, in *GetEstimateRequest, opts ...grpc.CallOption) (*Estimate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Estimate)
+	err := c.cc.Invoke(ctx, AssistantService_GetEstimate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assistantServiceClient) CreateEstimate(ctx context.Context, in *CreateEstimateRequest, opts ...grpc.CallOption) (*Estimate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Estimate)
+	err := c.cc.Invoke(ctx, AssistantService_CreateEstimate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assistantServiceClient) UpdateEstimate(ctx context.Context, in *UpdateEstimateRequest, opts ...grpc.CallOption) (*Estimate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Estimate)
+	err := c.cc.Invoke(ctx, AssistantService_UpdateEstimate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assistantServiceClient) DeleteEstimate(ctx context.Context, in *DeleteEstimateRequest, opts ...grpc.CallOption) (*DeleteEstimateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteEstimateResponse)
+	err := c.cc.Invoke(ctx, AssistantService_DeleteEstimate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assistantServiceClient) GetMarkPrice(ctx context.Context, in *GetMarkPriceRequest, opts ...grpc.CallOption) (*MarkPrice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarkPrice)
+	err := c.cc.Invoke(ctx, AssistantService_GetMarkPrice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assistantServiceClient) StreamMarkPrices(ctx context.Context, in *StreamMarkPricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MarkPrice], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AssistantService_ServiceDesc.Streams[0], AssistantService_StreamMarkPrices_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamMarkPricesRequest, MarkPrice]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AssistantService_StreamMarkPricesClient = grpc.ServerStreamingClient[MarkPrice]
+
+func (c *assistantServiceClient) ListPositions(ctx context.Context, in *ListPositionsRequest, opts ...grpc.CallOption) (*ListPositionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPositionsResponse)
+	err := c.cc.Invoke(ctx, AssistantService_ListPositions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AssistantServiceServer is the server API for AssistantService service.
+// All implementations must embed UnimplementedAssistantServiceServer
+// for forward compatibility.
+type AssistantServiceServer interface {
+	// 价格预估
+	ListEstimates(context.Context, *ListEstimatesRequest) (*ListEstimatesResponse, error)
+	GetEstimate(context.Context, *GetEstimateRequest) (*Estimate, error)
+	CreateEstimate(context.Context, *CreateEstimateRequest) (*Estimate, error)
+	UpdateEstimate(context.Context, *UpdateEstimateRequest) (*Estimate, error)
+	DeleteEstimate(context.Context, *DeleteEstimateRequest) (*DeleteEstimateResponse, error)
+	// 行情
+	GetMarkPrice(context.Context, *GetMarkPriceRequest) (*MarkPrice, error)
+	// 订阅标记价格，每轮价格获取完成后推送变化的币种
+	StreamMarkPrices(*StreamMarkPricesRequest, grpc.ServerStreamingServer[MarkPrice]) error
+	// 持仓
+	ListPositions(context.Context, *ListPositionsRequest) (*ListPositionsResponse, error)
+	mustEmbedUnimplementedAssistantServiceServer()
+}
+
+// UnimplementedAssistantServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAssistantServiceServer struct{}
+
+func (UnimplementedAssistantServiceServer) ListEstimates(context.Context, *ListEstimatesRequest) (*ListEstimatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEstimates not implemented")
+}
+func (UnimplementedAssistantServiceServer) GetEstimate(context.Context, *GetEstimateRequest) (*Estimate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEstimate not implemented")
+}
+func (UnimplementedAssistantServiceServer) CreateEstimate(context.Context, *CreateEstimateRequest) (*Estimate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEstimate not implemented")
+}
+func (UnimplementedAssistantServiceServer) UpdateEstimate(context.Context, *UpdateEstimateRequest) (*Estimate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateEstimate not implemented")
+}
+func (UnimplementedAssistantServiceServer) DeleteEstimate(context.Context, *DeleteEstimateRequest) (*DeleteEstimateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteEstimate not implemented")
+}
+func (UnimplementedAssistantServiceServer) GetMarkPrice(context.Context, *GetMarkPriceRequest) (*MarkPrice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMarkPrice not implemented")
+}
+func (UnimplementedAssistantServiceServer) StreamMarkPrices(*StreamMarkPricesRequest, grpc.ServerStreamingServer[MarkPrice]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamMarkPrices not implemented")
+}
+func (UnimplementedAssistantServiceServer) ListPositions(context.Context, *ListPositionsRequest) (*ListPositionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPositions not implemented")
+}
+func (UnimplementedAssistantServiceServer) mustEmbedUnimplementedAssistantServiceServer() {}
+func (UnimplementedAssistantServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeAssistantServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AssistantServiceServer will
+// result in compilation errors.
+type UnsafeAssistantServiceServer interface {
+	mustEmbedUnimplementedAssistantServiceServer()
+}
+
+func RegisterAssistantServiceServer(s grpc.ServiceRegistrar, srv AssistantServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAssistantServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AssistantService_ServiceDesc, srv)
+}
+
+func _AssistantService_ListEstimates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEstimatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssistantServiceServer).ListEstimates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssistantService_ListEstimates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssistantServiceServer).ListEstimates(ctx, req.(*ListEstimatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssistantService_GetEstimate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEstimateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssistantServiceServer).GetEstimate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssistantService_GetEstimate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssistantServiceServer).GetEstimate(ctx, req.(*GetEstimateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssistantService_CreateEstimate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEstimateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssistantServiceServer).CreateEstimate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssistantService_CreateEstimate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssistantServiceServer).CreateEstimate(ctx, req.(*CreateEstimateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssistantService_UpdateEstimate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateEstimateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssistantServiceServer).UpdateEstimate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssistantService_UpdateEstimate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssistantServiceServer).UpdateEstimate(ctx, req.(*UpdateEstimateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssistantService_DeleteEstimate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteEstimateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssistantServiceServer).DeleteEstimate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssistantService_DeleteEstimate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssistantServiceServer).DeleteEstimate(ctx, req.(*DeleteEstimateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssistantService_GetMarkPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMarkPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssistantServiceServer).GetMarkPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssistantService_GetMarkPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssistantServiceServer).GetMarkPrice(ctx, req.(*GetMarkPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssistantService_StreamMarkPrices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamMarkPricesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AssistantServiceServer).StreamMarkPrices(m, &grpc.GenericServerStream[StreamMarkPricesRequest, MarkPrice]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AssistantService_StreamMarkPricesServer = grpc.ServerStreamingServer[MarkPrice]
+
+func _AssistantService_ListPositions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPositionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssistantServiceServer).ListPositions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssistantService_ListPositions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssistantServiceServer).ListPositions(ctx, req.(*ListPositionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AssistantService_ServiceDesc is the grpc.ServiceDesc for AssistantService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AssistantService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "assistant.v1.AssistantService",
+	HandlerType: (*AssistantServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListEstimates",
+			Handler:    _AssistantService_ListEstimates_Handler,
+		},
+		{
+			MethodName: "GetEstimate",
+			Handler:    _AssistantService_GetEstimate_Handler,
+		},
+		{
+			MethodName: "CreateEstimate",
+			Handler:    _AssistantService_CreateEstimate_Handler,
+		},
+		{
+			MethodName: "UpdateEstimate",
+			Handler:    _AssistantService_UpdateEstimate_Handler,
+		},
+		{
+			MethodName: "DeleteEstimate",
+			Handler:    _AssistantService_DeleteEstimate_Handler,
+		},
+		{
+			MethodName: "GetMarkPrice",
+			Handler:    _AssistantService_GetMarkPrice_Handler,
+		},
+		{
+			MethodName: "ListPositions",
+			Handler:    _AssistantService_ListPositions_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamMarkPrices",
+			Handler:       _AssistantService_StreamMarkPrices_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "assistant/v1/assistant.proto",
+}
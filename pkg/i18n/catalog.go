@@ -0,0 +1,258 @@
+package i18n
+
+// catalogs 各语言的消息目录，消息为 fmt 格式串
+var catalogs = map[string]map[string]string{
+	LocaleZhCN: {
+		// 交易动作和方向
+		"action.open":        "开仓",
+		"action.addition":    "加仓",
+		"action.take_profit": "止盈",
+		"action.stop_loss":   "止损",
+		"action.other":       "交易",
+		"side.long":          "做多",
+		"side.short":         "做空",
+		"side.unknown":       "未知",
+
+		// 通知
+		"notify.time":                      "时间: %s",
+		"notify.trigger.title":             "✅ 价格预估已触发",
+		"notify.trigger.message":           "%s %s%s, 目标价: %s, 成交参考价: %s",
+		"notify.failure.title":             "❌ 订单执行失败",
+		"notify.failure.message":           "%s %s%s, 当前价: %s, 错误: %v",
+		"notify.short_failure.title":       "❌ 做空触发失败",
+		"notify.expired.title":             "⌛ 价格预估已过期",
+		"notify.expired.message":           "%s %s%s 目标价 %s 到期未触发",
+		"notify.verify_mismatch.title":     "⚠️ 执行结果不一致",
+		"notify.execution_aborted.title":   "⚠️ 订单执行中断",
+		"notify.execution_aborted.message": "%s %s%s: %s",
+		"notify.latency.title":             "🐢 执行延迟超过SLO",
+		"notify.latency.message":           "%s %s%s 执行耗时 %v 超过SLO %v（检测 %dms, 下单 %dms）",
+		"notify.stale.title":               "⚠️ 价格数据长时间未更新",
+		"notify.stale.message":             "%s 价格自 %s 起未从价格订阅更新，已重启订阅仍未恢复，当前依赖REST补拉 (%d 次)",
+		"notify.stale_recovered.title":     "✅ 价格数据已恢复",
+		"notify.stale_recovered.message":   "%s 价格已恢复更新，过期持续 %v",
+		"notify.reconnect.title":           "🔌 数据流重连",
+		"notify.reconnect.message":         "%s 数据流已重连",
+		"notify.listed.title":              "🆕 新上市",
+		"notify.listed.message":            "%s 新上市 %d 个币种: %s",
+		"notify.delisted.title":            "⚠️ 币种下架",
+		"notify.delisted.message":          "%s 下架 %d 个币种: %s",
+		"notify.delisted.estimates":        "\n已停用监听中的预估 %d 个",
+		"notify.delisted.deselected":       "\n已取消选中: %s",
+		"notify.conn_slow.title":           "⚠️ 推送连接延迟过高",
+		"notify.conn_slow.message":         "%s 推送连接 %s ping往返 %.0fms，时钟偏差 %.0fms，超过告警阈值",
+		"notify.conn_recovered.title":      "✅ 推送连接延迟已恢复",
+		"notify.conn_recovered.message":    "%s 推送连接 %s ping往返 %.0fms，时钟偏差 %.0fms",
+		"notify.proximity.title":           "🔔 价格接近预估目标",
+		"notify.digest.title":              "📬 通知汇总 (%d)",
+		"notify.digest.type":               "%s: %d 条",
+		"notify.proximity.message":         "%s %s%s, 当前价: %s, 目标价: %s, 距离: %.2f%%",
+		"notify.selection.title":           "🔄 自动选币调整",
+		"notify.risk_warning.title":        "⚠️ 持仓接近强平",
+		"notify.risk_critical.title":       "🚨 持仓即将强平",
+		"notify.risk.message":              "%s %s %.0fx, 标记价格: %.6f, 强平价格: %.6f, 距强平: %.2f%%",
+		"notify.derisk.title":              "🛡️ 自动减仓",
+		"notify.derisk.message":            "%s %s 距强平 %.2f%%, 市价减仓 %.0f%%",
+		"notify.drawdown.title":            "📉 账户权益回撤告警",
+		"notify.drawdown.message":          "当前权益 %.2f %s，峰值 %.2f，回撤 %.2f%% (阈值 %.2f%%)",
+		"notify.kill_switch.title":         "🛑 交易紧急停止",
+		"notify.kill_switch.message":       "操作人: %s\n原因: %s\n所有价格预估已暂停执行，需要手动解除",
+		"notify.kill_switch.cancelled":     "\n已撤销未成交开仓单: %d",
+		"notify.kill_release.title":        "✅ 紧急停止已解除",
+		"notify.kill_release.message":      "操作人: %s\n价格预估恢复执行",
+		"notify.breaker_open.title":        "⛔ Freqtrade 熔断",
+		"notify.breaker_open.message":      "实例 %s 连续 %d 次健康检查失败，已暂停下单，满足触发条件的预估将在恢复后重试\n错误: %v",
+		"notify.breaker_closed.title":      "✅ Freqtrade 已恢复",
+		"notify.breaker_closed.message":    "实例 %s 健康检查恢复正常，已恢复下单，%d 个排队的预估将重新评估触发条件",
+		"notify.step_down.title":           "⚠️ 主节点降级",
+		"notify.step_down.message":         "实例 %s 无法续期主节点租约，已停止价格监控和下单",
+		"notify.leader.title":              "👑 主节点切换",
+		"notify.leader.message":            "实例 %s 已成为主节点，开始运行价格监控和下单",
+		"notify.price_failover.title":      "🔀 价格监控交易所切换",
+		"notify.price_failover.message":    "%s -> %s\n原因: %s",
+		"notify.liquidation.title":         "💥 集中强平",
+		"notify.large_trade.title":         "🐋 大额成交",
+		"notify.open_interest.title":       "📊 持仓量异动",
+		"notify.pnl_daily.title":           "📊 盈亏日报 %s",
+		"notify.pnl_weekly.title":          "📈 盈亏周报 %s ~ %s",
+		"notify.reconcile.title":           "⚠️ 对账差异",
+
+		// Telegram指令机器人
+		"telegram.help": `可用指令:
+/positions 查看当前持仓和未实现盈亏
+/show <预估ID> 查看预估及其时间线，ID可只输入前几位
+/ol /os <币种> <保证金> [价格|m] [杠杆] 开多/开空
+/al /as <币种> <仓位比例> [价格|m] 多单/空单加仓
+/tl /ts <币种> <数量> [价格|m] 多单/空单止盈
+/sl /ss <币种> <数量> [价格|m] 多单/空单止损
+/tpl <模板> <币种> [价格|m] 使用预估模板创建，不带参数时列出模板
+/panic [cancel] [原因] 紧急停止所有预估执行，带 cancel 时撤销未成交的限价开仓单
+/resume 解除紧急停止
+价格可相对当前标记价格: +2% -2% +150 -150 mp`,
+		"telegram.unauthorized":               "未授权",
+		"telegram.permission_denied":          "❌ 权限不足: %s 角色不能执行 %s",
+		"telegram.error":                      "❌ %s",
+		"telegram.redis_unavailable":          "❌ Redis服务不可用",
+		"telegram.format_failed":              "❌ 格式化精度失败: %s",
+		"telegram.save_failed":                "❌ 保存价格预估失败",
+		"telegram.created":                    "✅ 已创建 %s %s %s 预估，目标价: %s",
+		"telegram.created_immediate":          "✅ 已创建 %s %s %s 预估，将立即执行",
+		"telegram.panic_reason":               "Telegram紧急停止",
+		"telegram.panic_failed":               "❌ 开启紧急停止失败",
+		"telegram.panic_activated":            "🛑 紧急停止已开启，使用 /resume 解除",
+		"telegram.panic_cancelled":            "🛑 紧急停止已开启，已撤销 %d 个未成交开仓单，使用 /resume 解除",
+		"telegram.resume_failed":              "❌ 解除紧急停止失败",
+		"telegram.resumed":                    "✅ 紧急停止已解除，价格预估恢复执行",
+		"telegram.template_failed":            "❌ 获取模板失败",
+		"telegram.template_empty":             "暂无预估模板",
+		"telegram.template_header":            "预估模板:",
+		"telegram.template_usage":             "❌ 指令格式错误，应为: /tpl <模板> <币种> [价格|m]",
+		"telegram.template_created":           "✅ 已按模板 %s 创建 %s %s %s 预估，目标价: %s",
+		"telegram.template_created_immediate": "✅ 已按模板 %s 创建 %s %s %s 预估，将立即执行",
+		"telegram.positions_empty":            "📭 当前没有持仓",
+		"telegram.positions_header":           "📊 当前持仓 (%d)",
+		"telegram.positions_line":             "%s %s %gx | 开仓 %s | 标记 %s | %+.2f%% (%+.2f)",
+		"telegram.positions_summary":          "合计: 保证金 %.2f | 未实现盈亏 %+.2f (%+.2f%%)",
+		"telegram.positions_stale":            "⚠️ Freqtrade暂不可用，以上为缓存数据",
+		"telegram.show_usage":                 "❌ 指令格式错误，应为: /show <预估ID>",
+		"telegram.show_not_found":             "❌ 未找到预估 %s（ID前缀匹配到多个预估时请输入更长的前缀）",
+		"telegram.show_header":                "🔎 %s %s %s | 目标价: %s | 状态: %s | 启用: %v",
+		"telegram.show_no_events":             "暂无时间线事件",
+		"telegram.long":                       "多",
+		"telegram.short":                      "空",
+		"telegram.command_usage":              "指令格式错误，应为: /<指令> <币种> <数值> [价格] [杠杆]",
+		"telegram.command_unsupported":        "不支持的指令: %s",
+		"telegram.command_too_many":           "指令参数过多",
+		"telegram.invalid_value":              "数值参数无效: %s",
+		"telegram.invalid_price":              "价格参数无效: %s",
+		"telegram.invalid_leverage":           "杠杆参数无效: %s",
+		"telegram.mark_price_unavailable":     "获取 %s 当前价格失败，无法使用相对价格 %s",
+		"telegram.relative_price_invalid":     "相对价格 %s 换算后无效: %s",
+		"telegram.unknown_symbol":             "无法识别的币种: %s",
+	},
+	LocaleEnUS: {
+		"action.open":        "Open",
+		"action.addition":    "Add",
+		"action.take_profit": "Take profit",
+		"action.stop_loss":   "Stop loss",
+		"action.other":       "Trade",
+		"side.long":          "long",
+		"side.short":         "short",
+		"side.unknown":       "unknown",
+
+		"notify.time":                      "Time: %s",
+		"notify.trigger.title":             "✅ Price estimate triggered",
+		"notify.trigger.message":           "%s %s %s, target: %s, reference price: %s",
+		"notify.failure.title":             "❌ Order execution failed",
+		"notify.failure.message":           "%s %s %s, current price: %s, error: %v",
+		"notify.short_failure.title":       "❌ Short trigger rejected",
+		"notify.expired.title":             "⌛ Price estimate expired",
+		"notify.expired.message":           "%s %s %s target %s expired without triggering",
+		"notify.verify_mismatch.title":     "⚠️ Execution result mismatch",
+		"notify.execution_aborted.title":   "⚠️ Order execution interrupted",
+		"notify.execution_aborted.message": "%s %s %s: %s",
+		"notify.latency.title":             "🐢 Execution latency above SLO",
+		"notify.latency.message":           "%s %s %s took %v, above SLO %v (detection %dms, order %dms)",
+		"notify.stale.title":               "⚠️ Price data stale",
+		"notify.stale.message":             "%s price has not been updated by the stream since %s and resubscribing did not help, falling back to REST polling (%d times)",
+		"notify.stale_recovered.title":     "✅ Price data recovered",
+		"notify.stale_recovered.message":   "%s price updates resumed after %v",
+		"notify.reconnect.title":           "🔌 Data stream reconnected",
+		"notify.reconnect.message":         "%s data stream reconnected",
+		"notify.listed.title":              "🆕 New listings",
+		"notify.listed.message":            "%s listed %d symbols: %s",
+		"notify.delisted.title":            "⚠️ Delistings",
+		"notify.delisted.message":          "%s delisted %d symbols: %s",
+		"notify.delisted.estimates":        "\nDisabled %d watching estimates",
+		"notify.delisted.deselected":       "\nDeselected: %s",
+		"notify.conn_slow.title":           "⚠️ Stream connection latency high",
+		"notify.conn_slow.message":         "%s stream connection %s ping RTT %.0fms, clock skew %.0fms exceeds the alert threshold",
+		"notify.conn_recovered.title":      "✅ Stream connection latency recovered",
+		"notify.conn_recovered.message":    "%s stream connection %s ping RTT %.0fms, clock skew %.0fms",
+		"notify.proximity.title":           "🔔 Price approaching estimate target",
+		"notify.digest.title":              "📬 Notification digest (%d)",
+		"notify.digest.type":               "%s: %d",
+		"notify.proximity.message":         "%s %s %s, price: %s, target: %s, distance: %.2f%%",
+		"notify.selection.title":           "🔄 Auto selection rebalanced",
+		"notify.risk_warning.title":        "⚠️ Position near liquidation",
+		"notify.risk_critical.title":       "🚨 Position about to be liquidated",
+		"notify.risk.message":              "%s %s %.0fx, mark price: %.6f, liquidation price: %.6f, distance: %.2f%%",
+		"notify.derisk.title":              "🛡️ Auto de-risk",
+		"notify.derisk.message":            "%s %s %.2f%% from liquidation, reducing %.0f%% at market",
+		"notify.drawdown.title":            "📉 Equity drawdown alert",
+		"notify.drawdown.message":          "Equity %.2f %s, peak %.2f, drawdown %.2f%% (threshold %.2f%%)",
+		"notify.kill_switch.title":         "🛑 Trading kill switch activated",
+		"notify.kill_switch.message":       "Operator: %s\nReason: %s\nAll price estimates are paused until released manually",
+		"notify.kill_switch.cancelled":     "\nCancelled unfilled entry orders: %d",
+		"notify.kill_release.title":        "✅ Kill switch released",
+		"notify.kill_release.message":      "Operator: %s\nPrice estimates resumed",
+		"notify.breaker_open.title":        "⛔ Freqtrade circuit open",
+		"notify.breaker_open.message":      "Bot %s failed %d health checks in a row, orders are paused and triggered estimates will retry after recovery\nError: %v",
+		"notify.breaker_closed.title":      "✅ Freqtrade recovered",
+		"notify.breaker_closed.message":    "Bot %s is healthy again, orders resumed and %d queued estimates will be re-evaluated",
+		"notify.step_down.title":           "⚠️ Leader stepped down",
+		"notify.step_down.message":         "Instance %s could not renew the leader lease, price monitoring and order execution stopped",
+		"notify.leader.title":              "👑 Leader changed",
+		"notify.leader.message":            "Instance %s is now the leader and runs price monitoring and order execution",
+		"notify.price_failover.title":      "🔀 Price venue switched",
+		"notify.price_failover.message":    "%s -> %s\nReason: %s",
+		"notify.liquidation.title":         "💥 Liquidation cluster",
+		"notify.large_trade.title":         "🐋 Large trade",
+		"notify.open_interest.title":       "📊 Open interest spike",
+		"notify.pnl_daily.title":           "📊 Daily PnL report %s",
+		"notify.pnl_weekly.title":          "📈 Weekly PnL report %s ~ %s",
+		"notify.reconcile.title":           "⚠️ Reconciliation mismatch",
+
+		"telegram.help": `Available commands:
+/positions Show open positions and unrealized PnL
+/show <estimate ID> Show an estimate and its timeline, an ID prefix is enough
+/ol /os <symbol> <stake> [price|m] [leverage] Open long/short
+/al /as <symbol> <position %> [price|m] Add to long/short
+/tl /ts <symbol> <amount> [price|m] Take profit on long/short
+/sl /ss <symbol> <amount> [price|m] Stop loss on long/short
+/tpl <template> <symbol> [price|m] Create from an estimate template, lists templates without arguments
+/panic [cancel] [reason] Stop executing all estimates, with cancel also cancels unfilled limit entry orders
+/resume Release the kill switch
+Prices can be relative to the current mark price: +2% -2% +150 -150 mp`,
+		"telegram.unauthorized":               "unauthorized",
+		"telegram.permission_denied":          "❌ Permission denied: role %s cannot run %s",
+		"telegram.error":                      "❌ %s",
+		"telegram.redis_unavailable":          "❌ Redis is unavailable",
+		"telegram.format_failed":              "❌ Failed to apply precision: %s",
+		"telegram.save_failed":                "❌ Failed to save price estimate",
+		"telegram.created":                    "✅ Created %s %s %s estimate, target price: %s",
+		"telegram.created_immediate":          "✅ Created %s %s %s estimate, executing immediately",
+		"telegram.panic_reason":               "Telegram kill switch",
+		"telegram.panic_failed":               "❌ Failed to activate kill switch",
+		"telegram.panic_activated":            "🛑 Kill switch activated, use /resume to release",
+		"telegram.panic_cancelled":            "🛑 Kill switch activated, cancelled %d unfilled entry orders, use /resume to release",
+		"telegram.resume_failed":              "❌ Failed to release kill switch",
+		"telegram.resumed":                    "✅ Kill switch released, price estimates resumed",
+		"telegram.template_failed":            "❌ Failed to load templates",
+		"telegram.template_empty":             "No estimate templates",
+		"telegram.template_header":            "Estimate templates:",
+		"telegram.template_usage":             "❌ Invalid command, usage: /tpl <template> <symbol> [price|m]",
+		"telegram.template_created":           "✅ Created %[2]s %[3]s %[4]s estimate from template %[1]s, target price: %[5]s",
+		"telegram.template_created_immediate": "✅ Created %[2]s %[3]s %[4]s estimate from template %[1]s, executing immediately",
+		"telegram.positions_empty":            "📭 No open positions",
+		"telegram.positions_header":           "📊 Open positions (%d)",
+		"telegram.positions_line":             "%s %s %gx | entry %s | mark %s | %+.2f%% (%+.2f)",
+		"telegram.positions_summary":          "Total: margin %.2f | unrealized PnL %+.2f (%+.2f%%)",
+		"telegram.positions_stale":            "⚠️ Freqtrade is unavailable, showing cached data",
+		"telegram.show_usage":                 "❌ Invalid command, usage: /show <estimate ID>",
+		"telegram.show_not_found":             "❌ Estimate %s not found (use a longer prefix if it matches several estimates)",
+		"telegram.show_header":                "🔎 %s %s %s | target: %s | status: %s | enabled: %v",
+		"telegram.show_no_events":             "No timeline events yet",
+		"telegram.long":                       "L",
+		"telegram.short":                      "S",
+		"telegram.command_usage":              "Invalid command, usage: /<command> <symbol> <value> [price] [leverage]",
+		"telegram.command_unsupported":        "Unsupported command: %s",
+		"telegram.command_too_many":           "Too many arguments",
+		"telegram.invalid_value":              "Invalid value: %s",
+		"telegram.invalid_price":              "Invalid price: %s",
+		"telegram.invalid_leverage":           "Invalid leverage: %s",
+		"telegram.mark_price_unavailable":     "Failed to get the current price of %s, cannot use relative price %s",
+		"telegram.relative_price_invalid":     "Relative price %s resolves to an invalid value: %s",
+		"telegram.unknown_symbol":             "Unknown symbol: %s",
+	},
+}
@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 支持的输出语言
+const (
+	LocaleZhCN = "zh-CN"
+	LocaleEnUS = "en-US"
+)
+
+var (
+	mu       sync.RWMutex
+	locale   = LocaleZhCN
+	location = time.Local
+)
+
+// Init 设置输出语言和显示时区，timezone 为空时使用系统时区
+// 显示时区同时设置为进程本地时区，接口返回的时间、导出文件和按日统计都以此为准，需在启动其他组件前调用
+func Init(lang, timezone string) error {
+	normalized, err := normalizeLocale(lang)
+	if err != nil {
+		return err
+	}
+
+	loc := time.Local
+	if timezone != "" {
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			return fmt.Errorf("无法识别的时区 %s: %v", timezone, err)
+		}
+		time.Local = loc
+	}
+
+	mu.Lock()
+	locale = normalized
+	location = loc
+	mu.Unlock()
+	return nil
+}
+
+// Locale 当前输出语言
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// Location 当前显示时区
+func Location() *time.Location {
+	mu.RLock()
+	defer mu.RUnlock()
+	return location
+}
+
+// T 按当前语言渲染消息，当前语言缺少该消息时使用中文，都没有时返回 key
+func T(key string, args ...interface{}) string {
+	format, exists := catalogs[Locale()][key]
+	if !exists {
+		if format, exists = catalogs[LocaleZhCN][key]; !exists {
+			format = key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// FormatTime 按显示时区格式化时间
+func FormatTime(t time.Time) string {
+	return t.In(Location()).Format(time.DateTime)
+}
+
+// FormatMillis 按显示时区格式化毫秒时间戳
+func FormatMillis(ms int64) string {
+	return FormatTime(time.UnixMilli(ms))
+}
+
+// normalizeLocale 统一语言标识，兼容 zh、en、en_US 等写法
+func normalizeLocale(lang string) (string, error) {
+	switch strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-")) {
+	case "", "zh", "zh-cn":
+		return LocaleZhCN, nil
+	case "en", "en-us":
+		return LocaleEnUS, nil
+	default:
+		return "", fmt.Errorf("不支持的语言 %s，可选值: %s, %s", lang, LocaleZhCN, LocaleEnUS)
+	}
+}
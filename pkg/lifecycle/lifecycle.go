@@ -0,0 +1,164 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Component 受生命周期管理的组件
+// DependsOn 中的组件先于本组件启动、晚于本组件停止
+type Component struct {
+	Name      string
+	DependsOn []string
+	Start     func() error
+	Stop      func(ctx context.Context) error
+}
+
+// Manager 组件生命周期管理器，按依赖关系有序启动和关闭
+type Manager struct {
+	mu         sync.Mutex
+	components []*Component
+	byName     map[string]*Component
+	started    []*Component // 已启动的组件，按启动顺序
+}
+
+// NewManager 创建生命周期管理器
+func NewManager() *Manager {
+	return &Manager{
+		byName: make(map[string]*Component),
+	}
+}
+
+// Register 注册组件，同名组件不能重复注册
+func (m *Manager) Register(component Component) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if component.Name == "" {
+		return fmt.Errorf("组件名称不能为空")
+	}
+	if _, exists := m.byName[component.Name]; exists {
+		return fmt.Errorf("组件 %s 已注册", component.Name)
+	}
+
+	c := component
+	m.components = append(m.components, &c)
+	m.byName[c.Name] = &c
+	return nil
+}
+
+// StartAll 按依赖顺序启动所有组件，某个组件启动失败时停止已启动的组件并返回错误
+func (m *Manager) StartAll(stopTimeout time.Duration) error {
+	m.mu.Lock()
+	order, err := m.resolveOrder()
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, component := range order {
+		if component.Start != nil {
+			if err := component.Start(); err != nil {
+				logrus.Errorf("组件 %s 启动失败: %v", component.Name, err)
+				m.StopAll(stopTimeout)
+				return fmt.Errorf("组件 %s 启动失败: %w", component.Name, err)
+			}
+		}
+		m.mu.Lock()
+		m.started = append(m.started, component)
+		m.mu.Unlock()
+		logrus.Debugf("组件 %s 已启动", component.Name)
+	}
+	return nil
+}
+
+// StopAll 按启动的逆序停止已启动的组件，总耗时不超过 timeout
+// 超时后剩余组件不再等待，直接跳过
+func (m *Manager) StopAll(timeout time.Duration) {
+	m.mu.Lock()
+	started := m.started
+	m.started = nil
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for i := len(started) - 1; i >= 0; i-- {
+		component := started[i]
+		if component.Stop == nil {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			logrus.Warnf("关闭超时，跳过组件 %s", component.Name)
+			continue
+		}
+
+		start := time.Now()
+		done := make(chan error, 1)
+		go func() {
+			done <- component.Stop(ctx)
+		}()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				logrus.Errorf("组件 %s 停止失败: %v", component.Name, err)
+			} else {
+				logrus.Infof("组件 %s 已停止 (%v)", component.Name, time.Since(start).Round(time.Millisecond))
+			}
+		case <-ctx.Done():
+			logrus.Warnf("组件 %s 停止超时", component.Name)
+		}
+	}
+}
+
+// resolveOrder 按依赖关系拓扑排序，无依赖关系的组件保持注册顺序（调用方需持有锁）
+func (m *Manager) resolveOrder() ([]*Component, error) {
+	for _, component := range m.components {
+		for _, dep := range component.DependsOn {
+			if _, exists := m.byName[dep]; !exists {
+				return nil, fmt.Errorf("组件 %s 依赖的组件 %s 未注册", component.Name, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(m.components))
+	order := make([]*Component, 0, len(m.components))
+
+	var visit func(component *Component) error
+	visit = func(component *Component) error {
+		switch state[component.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("组件 %s 存在循环依赖", component.Name)
+		}
+
+		state[component.Name] = visiting
+		for _, dep := range component.DependsOn {
+			if err := visit(m.byName[dep]); err != nil {
+				return err
+			}
+		}
+		state[component.Name] = visited
+		order = append(order, component)
+		return nil
+	}
+
+	for _, component := range m.components {
+		if err := visit(component); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,246 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 日志输出格式
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Config 日志配置
+type Config struct {
+	Level        string   // 默认日志级别
+	Format       string   // text 或 json
+	ModuleLevels []string // 按模块覆盖日志级别，如 websocket=warn,monitor=debug
+	File         string   // 日志文件路径，为空时只输出到标准输出
+	MaxSizeMB    int      // 单个日志文件大小上限（MB），0 表示不滚动
+	MaxBackups   int      // 保留的滚动备份数
+}
+
+// Levels 当前日志级别
+type Levels struct {
+	Level   string            `json:"level"`   // 默认日志级别
+	Modules map[string]string `json:"modules"` // 按模块覆盖的日志级别
+}
+
+// moduleInfo 日志调用位置所属的模块：包名和文件名的第一个单词
+type moduleInfo struct {
+	pkg  string
+	file string
+}
+
+var (
+	mu           sync.RWMutex
+	defaultLevel = logrus.InfoLevel
+	moduleLevels = make(map[string]logrus.Level)
+	callerCache  sync.Map // 函数名 -> moduleInfo
+	logFile      *rotatingFile
+)
+
+// Init 按配置设置全局 logrus：输出格式、按模块的日志级别和日志文件滚动
+// 模块名匹配调用位置的包名（如 websocket、binance）或文件名的第一个单词（如 core/monitor_core.go 为 monitor），
+// 两者都有覆盖时以文件名为准
+func Init(cfg Config) error {
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		return fmt.Errorf("无效的日志级别 %q", cfg.Level)
+	}
+	modules, err := parseModuleLevels(cfg.ModuleLevels)
+	if err != nil {
+		return err
+	}
+
+	var base logrus.Formatter
+	switch strings.ToLower(cfg.Format) {
+	case "", FormatText:
+		base = &logrus.TextFormatter{FullTimestamp: true}
+	case FormatJSON:
+		base = &logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano}
+	default:
+		return fmt.Errorf("无效的日志格式 %q，可选 %s/%s", cfg.Format, FormatText, FormatJSON)
+	}
+
+	var out io.Writer = os.Stdout
+	if cfg.File != "" {
+		file, err := newRotatingFile(filepath.Clean(cfg.File), int64(cfg.MaxSizeMB)*1024*1024, cfg.MaxBackups)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		if logFile != nil {
+			logFile.Close()
+		}
+		logFile = file
+		mu.Unlock()
+		out = io.MultiWriter(os.Stdout, file)
+	}
+
+	mu.Lock()
+	defaultLevel = level
+	moduleLevels = modules
+	mu.Unlock()
+
+	logrus.SetOutput(out)
+	logrus.SetReportCaller(true)
+	logrus.SetFormatter(&moduleFormatter{base: base})
+	applyLoggerLevel()
+	return nil
+}
+
+// GetLevels 获取当前默认日志级别和按模块覆盖的级别
+func GetLevels() Levels {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	levels := Levels{Level: defaultLevel.String(), Modules: make(map[string]string, len(moduleLevels))}
+	for module, level := range moduleLevels {
+		levels.Modules[module] = level.String()
+	}
+	return levels
+}
+
+// SetLevel 运行时修改默认日志级别
+func SetLevel(level string) error {
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("无效的日志级别 %q", level)
+	}
+	mu.Lock()
+	defaultLevel = parsed
+	mu.Unlock()
+	applyLoggerLevel()
+	return nil
+}
+
+// SetModuleLevel 运行时修改模块的日志级别，level 为空时删除该模块的覆盖
+func SetModuleLevel(module, level string) error {
+	module = strings.ToLower(strings.TrimSpace(module))
+	if module == "" {
+		return fmt.Errorf("模块名不能为空")
+	}
+
+	mu.Lock()
+	if level == "" {
+		delete(moduleLevels, module)
+	} else {
+		parsed, err := logrus.ParseLevel(level)
+		if err != nil {
+			mu.Unlock()
+			return fmt.Errorf("模块 %s 的日志级别 %q 无效", module, level)
+		}
+		moduleLevels[module] = parsed
+	}
+	mu.Unlock()
+	applyLoggerLevel()
+	return nil
+}
+
+// parseModuleLevels 解析 模块=级别 格式的覆盖配置
+func parseModuleLevels(entries []string) (map[string]logrus.Level, error) {
+	modules := make(map[string]logrus.Level)
+	for _, entry := range entries {
+		module, level, found := strings.Cut(entry, "=")
+		module = strings.ToLower(strings.TrimSpace(module))
+		if !found || module == "" {
+			return nil, fmt.Errorf("模块日志级别 %q 格式应为 模块=级别", entry)
+		}
+		parsed, err := logrus.ParseLevel(strings.TrimSpace(level))
+		if err != nil {
+			return nil, fmt.Errorf("模块 %s 的日志级别 %q 无效", module, level)
+		}
+		modules[module] = parsed
+	}
+	return modules, nil
+}
+
+// applyLoggerLevel 全局级别取默认级别和所有模块覆盖中最详细的一个，具体是否输出由 moduleFormatter 按模块判断
+func applyLoggerLevel() {
+	mu.RLock()
+	level := defaultLevel
+	for _, moduleLevel := range moduleLevels {
+		if moduleLevel > level {
+			level = moduleLevel
+		}
+	}
+	mu.RUnlock()
+	logrus.SetLevel(level)
+}
+
+// moduleFormatter 按调用位置所属模块过滤日志级别，并在日志中附带 module 字段
+type moduleFormatter struct {
+	base logrus.Formatter
+}
+
+// Format 低于模块日志级别的日志返回空内容，不输出
+func (f *moduleFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	info := callerModule(entry.Caller)
+
+	mu.RLock()
+	level, exists := moduleLevels[info.file]
+	if !exists {
+		level, exists = moduleLevels[info.pkg]
+	}
+	if !exists {
+		level = defaultLevel
+	}
+	mu.RUnlock()
+	if entry.Level > level {
+		return nil, nil
+	}
+
+	// 不输出调用位置，只附带模块名
+	copied := *entry
+	copied.Caller = nil
+	if info.pkg != "" {
+		copied.Data = make(logrus.Fields, len(entry.Data)+1)
+		for key, value := range entry.Data {
+			copied.Data[key] = value
+		}
+		copied.Data["module"] = info.module()
+	}
+	return f.base.Format(&copied)
+}
+
+// module 日志中显示的模块名，如 core/monitor
+func (m moduleInfo) module() string {
+	if m.file == "" || m.file == m.pkg {
+		return m.pkg
+	}
+	return m.pkg + "/" + m.file
+}
+
+// callerModule 根据调用位置解析包名和文件名的第一个单词，按函数名缓存
+func callerModule(caller *runtime.Frame) moduleInfo {
+	if caller == nil {
+		return moduleInfo{}
+	}
+	if cached, ok := callerCache.Load(caller.Function); ok {
+		return cached.(moduleInfo)
+	}
+
+	// 函数名形如 trading_assistant/core.(*PriceMonitor).monitorLoop
+	function := caller.Function
+	if slash := strings.LastIndex(function, "/"); slash >= 0 {
+		function = function[slash+1:]
+	}
+	pkg, _, _ := strings.Cut(function, ".")
+
+	file := strings.TrimSuffix(filepath.Base(caller.File), ".go")
+	file, _, _ = strings.Cut(file, "_")
+
+	info := moduleInfo{pkg: strings.ToLower(pkg), file: strings.ToLower(file)}
+	callerCache.Store(caller.Function, info)
+	return info
+}
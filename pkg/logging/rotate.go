@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// rotatingFile 按大小滚动的日志文件
+// 当前文件超过 maxSize 时依次重命名为 .1、.2 …，超过 maxBackups 的旧文件删除
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// newRotatingFile 打开日志文件，已存在时追加写入
+func newRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("创建日志目录失败: %v", err)
+	}
+	rf := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// Write 写入日志，写入后超过大小上限的在写入前滚动
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		if err := rf.rotate(); err != nil {
+			// 滚动失败时继续写入当前文件，避免丢日志
+			fmt.Fprintf(os.Stderr, "日志文件滚动失败: %v\n", err)
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Close 关闭日志文件
+func (rf *rotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	return rf.file.Close()
+}
+
+// open 打开或创建当前日志文件
+func (rf *rotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("打开日志文件失败: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("读取日志文件失败: %v", err)
+	}
+	rf.file = file
+	rf.size = info.Size()
+	return nil
+}
+
+// rotate 关闭当前文件并依次重命名备份，调用方需持有锁
+func (rf *rotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+
+	if rf.maxBackups > 0 {
+		os.Remove(rf.backupPath(rf.maxBackups))
+		for i := rf.maxBackups - 1; i >= 1; i-- {
+			os.Rename(rf.backupPath(i), rf.backupPath(i+1))
+		}
+		if err := os.Rename(rf.path, rf.backupPath(1)); err != nil {
+			rf.open()
+			return err
+		}
+	} else if err := os.Remove(rf.path); err != nil {
+		rf.open()
+		return err
+	}
+	return rf.open()
+}
+
+// backupPath 第 n 个备份文件的路径
+func (rf *rotatingFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", rf.path, n)
+}
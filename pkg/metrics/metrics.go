@@ -0,0 +1,233 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "trading_assistant"
+
+var (
+	// ExchangeRequests 交易所行情请求次数
+	ExchangeRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "exchange_requests_total",
+		Help:      "交易所行情请求次数",
+	}, []string{"exchange", "endpoint", "result"})
+
+	// ExchangeReconnects 交易所数据流重连次数
+	ExchangeReconnects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "exchange_reconnects_total",
+		Help:      "交易所数据流重连次数",
+	}, []string{"exchange"})
+
+	// EstimateTriggers 价格预估触发次数
+	EstimateTriggers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "estimate_triggers_total",
+		Help:      "价格预估触发次数",
+	}, []string{"action_type", "result"})
+
+	// ExecutionVerifications 下单后持仓变化验证结果
+	ExecutionVerifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "execution_verifications_total",
+		Help:      "下单后持仓变化验证结果",
+	}, []string{"action_type", "result"})
+
+	// OrderSizingChecks 触发时下单数量按币种限制调整或拒绝的次数
+	OrderSizingChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "order_sizing_checks_total",
+		Help:      "触发时下单数量按币种限制调整或拒绝的次数",
+	}, []string{"action_type", "result"})
+
+	// RiskLimitBlocks 触发时被下单前风控限制拦截的次数
+	RiskLimitBlocks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "risk_limit_blocks_total",
+		Help:      "触发时被下单前风控限制拦截的次数",
+	}, []string{"limit"})
+
+	// MonitorSymbolLatency 币种在每轮监控中开始被评估的延迟
+	MonitorSymbolLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "monitor_symbol_eval_latency_seconds",
+		Help:      "币种在每轮监控中开始被评估的延迟",
+		Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"symbol"})
+
+	// ExecutionLatency 从满足触发条件的tick到下单请求完成的耗时
+	ExecutionLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "execution_latency_seconds",
+		Help:      "从满足触发条件的tick到下单请求完成的耗时",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 3, 5, 10, 30},
+	}, []string{"action_type", "result"})
+
+	// ExecutionLatencySLOViolations 执行延迟超过SLO的次数
+	ExecutionLatencySLOViolations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "execution_latency_slo_violations_total",
+		Help:      "执行延迟超过SLO的次数",
+	}, []string{"action_type"})
+
+	// MonitorSymbolDeferred 因时间预算耗尽而顺延到下一轮的预估数
+	MonitorSymbolDeferred = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "monitor_symbol_deferred_total",
+		Help:      "因时间预算耗尽而顺延到下一轮的预估数",
+	}, []string{"symbol"})
+
+	// MonitorSLOViolations 币种评估延迟超过SLO的次数
+	MonitorSLOViolations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "monitor_latency_slo_violations_total",
+		Help:      "币种评估延迟超过SLO的次数",
+	}, []string{"symbol"})
+
+	// MonitorSkips 满足评估条件但被跳过的预估次数
+	MonitorSkips = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "monitor_skips_total",
+		Help:      "满足评估条件但被跳过的预估次数",
+	}, []string{"reason"})
+
+	// EventBusPublished 事件总线发布的事件数
+	EventBusPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "eventbus_published_total",
+		Help:      "事件总线发布的事件数",
+	}, []string{"topic"})
+
+	// EventBusDropped 订阅者缓冲区已满而丢弃的事件数
+	EventBusDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "eventbus_dropped_total",
+		Help:      "订阅者缓冲区已满而丢弃的事件数",
+	}, []string{"topic", "subscriber"})
+
+	// EventBusMerged 订阅者缓冲区已满而合并的事件数
+	EventBusMerged = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "eventbus_merged_total",
+		Help:      "订阅者缓冲区已满而合并的事件数",
+	}, []string{"topic", "subscriber"})
+
+	// FreqtradeRequestDuration Freqtrade API 请求耗时
+	FreqtradeRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "freqtrade_request_duration_seconds",
+		Help:      "Freqtrade API 请求耗时",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "path", "result"})
+
+	// FreqtradeCircuitOpen Freqtrade 实例熔断状态，1 表示熔断中
+	FreqtradeCircuitOpen = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "freqtrade_circuit_open",
+		Help:      "Freqtrade 实例熔断状态，1 表示熔断中",
+	}, []string{"bot"})
+
+	// RedisErrors Redis 操作错误次数
+	RedisErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redis_errors_total",
+		Help:      "Redis 操作错误次数",
+	}, []string{"command"})
+
+	// HubClients WebSocket Hub 当前连接的客户端数
+	HubClients = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ws_hub_clients",
+		Help:      "WebSocket Hub 当前连接的客户端数",
+	})
+
+	// HubMessages WebSocket Hub 推送的消息数
+	HubMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ws_hub_messages_total",
+		Help:      "WebSocket Hub 推送的消息数",
+	}, []string{"data_type", "result"})
+
+	// HubInitialSnapshotDuration WebSocket 新订阅初始数据组装耗时
+	HubInitialSnapshotDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ws_hub_initial_snapshot_duration_seconds",
+		Help:      "WebSocket 新订阅初始数据组装耗时",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"data_type"})
+
+	// ExchangeStreamPingRTT 交易所推送连接最近一次ping往返延迟
+	ExchangeStreamPingRTT = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exchange_stream_ping_rtt_seconds",
+		Help:      "交易所推送连接最近一次ping往返延迟",
+	}, []string{"exchange", "connection"})
+
+	// ExchangeStreamEventLag 交易所推送事件时间到本地接收的平滑延迟，含时钟偏差
+	ExchangeStreamEventLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exchange_stream_event_lag_seconds",
+		Help:      "交易所推送事件时间到本地接收的平滑延迟，含时钟偏差",
+	}, []string{"exchange", "connection"})
+
+	// ExchangeStreamClockSkew 按推送事件时间和ping往返延迟估算的本地时钟偏差
+	ExchangeStreamClockSkew = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exchange_stream_clock_skew_seconds",
+		Help:      "按推送事件时间和ping往返延迟估算的本地时钟偏差",
+	}, []string{"exchange", "connection"})
+
+	// NotificationsSent 通知发送次数
+	NotificationsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_sent_total",
+		Help:      "通知发送次数",
+	}, []string{"notifier", "event_type", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		ExchangeRequests,
+		ExchangeReconnects,
+		EstimateTriggers,
+		ExecutionVerifications,
+		OrderSizingChecks,
+		RiskLimitBlocks,
+		MonitorSymbolLatency,
+		ExecutionLatency,
+		ExecutionLatencySLOViolations,
+		MonitorSymbolDeferred,
+		MonitorSLOViolations,
+		MonitorSkips,
+		EventBusPublished,
+		EventBusDropped,
+		EventBusMerged,
+		FreqtradeRequestDuration,
+		FreqtradeCircuitOpen,
+		RedisErrors,
+		HubClients,
+		HubMessages,
+		HubInitialSnapshotDuration,
+		NotificationsSent,
+		ExchangeStreamPingRTT,
+		ExchangeStreamEventLag,
+		ExchangeStreamClockSkew,
+	)
+}
+
+// ResultLabel 根据错误返回结果标签
+func ResultLabel(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// Handler 返回 /metrics 的 gin 处理函数
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
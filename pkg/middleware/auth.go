@@ -1,15 +1,17 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 	"trading_assistant/pkg/auth"
+	"trading_assistant/pkg/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
-// AuthMiddleware JWT认证中间件
+// AuthMiddleware 认证中间件，支持JWT和 X-API-Key 接口密钥，并按角色限制写接口
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 跳过健康检查、登录接口和静态文件
@@ -21,11 +23,36 @@ func AuthMiddleware() gin.HandlerFunc {
 			path == "/favicon.svg" ||
 			path == "/manifest.json" ||
 			path == "/" ||
-			(!strings.HasPrefix(path, "/api/") && path != "/ws") {
+			(!strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, "/debug/") && path != "/ws" && path != "/metrics") {
 			c.Next()
 			return
 		}
 
+		// Prometheus 使用配置的口令抓取监控指标
+		if path == "/metrics" && metricsTokenValid(c.GetHeader("Authorization")) {
+			authorize(c, "metrics", auth.RoleViewer)
+			return
+		}
+
+		// 优先使用接口密钥认证
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" || (path == "/ws" && c.Query("api_key") != "") {
+			if apiKey == "" {
+				apiKey = c.Query("api_key")
+			}
+			key, ok := auth.ValidateAPIKey(apiKey)
+			if !ok {
+				logrus.Warnf("接口密钥验证失败: %s %s", c.Request.Method, path)
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": "无效的接口密钥",
+					"code":  "INVALID_API_KEY",
+				})
+				c.Abort()
+				return
+			}
+			authorize(c, "apikey:"+key.Name, key.Role)
+			return
+		}
+
 		var tokenString string
 		if path == "/ws" {
 			tokenString = c.Query("token")
@@ -74,10 +101,62 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 将用户信息存储到上下文中
-		c.Set("username", claims.Username)
-		c.Next()
+		authorize(c, claims.Username, claims.Role)
+	}
+}
+
+// metricsTokenValid 校验 /metrics 的 Bearer 口令，未配置 METRICS_TOKEN 时始终返回false
+func metricsTokenValid(authHeader string) bool {
+	token := config.GlobalConfig.MetricsToken
+	if token == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(authHeader, "Bearer ")), []byte(token)) == 1
+}
+
+// readOnlyWriteRoutes 只做试算、不修改任何状态的非GET接口，只读角色也可以调用
+var readOnlyWriteRoutes = map[string]bool{
+	"POST /api/v1/estimates/preview": true,
+	"POST /api/v1/telegram/preview":  true,
+	"POST /api/v1/backtest/estimate": true,
+}
+
+// requiredRole 接口所需的最低角色：查询接口只读即可，创建、修改、删除预估和操作Freqtrade等写接口需要交易角色
+// 运行时诊断接口会暴露进程内部信息，CPU采集也有开销，同样需要交易角色
+func requiredRole(c *gin.Context) string {
+	if strings.HasPrefix(c.Request.URL.Path, "/debug/") {
+		return auth.RoleTrader
+	}
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return auth.RoleViewer
+	}
+	if readOnlyWriteRoutes[c.Request.Method+" "+c.FullPath()] {
+		return auth.RoleViewer
+	}
+	return auth.RoleTrader
+}
+
+// authorize 检查角色权限，通过后将用户信息存储到上下文中
+func authorize(c *gin.Context, username, role string) {
+	if !auth.RoleAllows(role, requiredRole(c)) {
+		logrus.Warnf("权限不足: %s (%s) 请求 %s %s", username, role, c.Request.Method, c.Request.URL.Path)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "权限不足，该操作需要交易权限",
+			"code":  "FORBIDDEN",
+		})
+		c.Abort()
+		return
 	}
+
+	c.Set("username", username)
+	c.Set("role", role)
+	c.Next()
+}
+
+// GetCurrentRole 从上下文中获取当前用户的角色
+func GetCurrentRole(c *gin.Context) string {
+	return c.GetString("role")
 }
 
 // GetCurrentUser 从上下文中获取当前用户
@@ -2,21 +2,40 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// Cors 处理跨域请求
-func Cors() gin.HandlerFunc {
+// OriginAllowed 判断来源是否在允许列表中，列表为空或包含 * 时允许所有来源
+func OriginAllowed(origin string, allowedOrigins []string) bool {
+	if len(allowedOrigins) == 0 {
+		return true
+	}
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimRight(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cors 处理跨域请求，allowedOrigins 为空时允许所有域名
+func Cors(allowedOrigins []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		method := c.Request.Method
 
-		// 设置允许跨域的域名，*表示允许所有域名
-		c.Header("Access-Control-Allow-Origin", "*")
+		// 设置允许跨域的域名，未配置时允许所有域名；配置后只回显允许的来源
+		if len(allowedOrigins) == 0 {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else if origin := c.GetHeader("Origin"); origin != "" && OriginAllowed(origin, allowedOrigins) {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Vary", "Origin")
+		}
 		// 设置允许的请求头
-		c.Header("Access-Control-Allow-Headers", "Content-Type,AccessToken,X-CSRF-Token, Authorization, Token")
+		c.Header("Access-Control-Allow-Headers", "Content-Type,AccessToken,X-CSRF-Token, Authorization, Token, X-API-Key")
 		// 设置允许的请求方法
-		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE, UPDATE")
+		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, PATCH, DELETE, UPDATE")
 		// 设置暴露的请求头
 		c.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin, Access-Control-Allow-Headers, Content-Type")
 		// 设置允许发送cookie
@@ -30,3 +49,27 @@ func Cors() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// StripBasePath 去掉反向代理的路径前缀（如 /assistant），使服务可以部署在子路径下
+// 不带前缀的请求原样处理，兼容代理已去掉前缀的部署方式
+func StripBasePath(basePath string, next http.Handler) http.Handler {
+	basePath = "/" + strings.Trim(basePath, "/")
+	if basePath == "/" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == basePath || strings.HasPrefix(r.URL.Path, basePath+"/") {
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = strings.TrimPrefix(r.URL.Path, basePath)
+			if r2.URL.Path == "" {
+				r2.URL.Path = "/"
+			}
+			if r.URL.RawPath != "" {
+				r2.URL.RawPath = strings.TrimPrefix(r.URL.RawPath, basePath)
+			}
+			next.ServeHTTP(w, r2)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EstimateHistory 对应 estimate_history 表，已结束并从Redis归档的价格预估
+type EstimateHistory struct {
+	ID                uint            `json:"id" gorm:"primarykey"`
+	EstimateID        string          `json:"estimate_id" gorm:"size:64;uniqueIndex"`
+	Symbol            string          `json:"symbol" gorm:"size:64;index"`
+	Exchange          string          `json:"exchange" gorm:"size:32"`
+	Side              string          `json:"side" gorm:"size:16"`
+	ActionType        string          `json:"action_type" gorm:"size:32"`
+	TriggerType       string          `json:"trigger_type" gorm:"size:32"`
+	TargetPrice       float64         `json:"target_price"`
+	Percentage        float64         `json:"percentage"`
+	Status            string          `json:"status" gorm:"size:32;index"`
+	ErrorMessage      string          `json:"error_message" gorm:"type:text"`
+	Tag               string          `json:"tag" gorm:"size:128"`
+	Data              json.RawMessage `json:"data" gorm:"type:json"` // 完整的预估JSON
+	EstimateCreatedAt time.Time       `json:"estimate_created_at"`
+	EstimateUpdatedAt time.Time       `json:"estimate_updated_at" gorm:"index"`
+	CreatedAt         time.Time       `json:"created_at"` // 归档时间
+}
+
+// TableName 表名
+func (EstimateHistory) TableName() string {
+	return "estimate_history"
+}
+
+// TriggerEvent 对应 trigger_events 表，单次触发的执行延迟和执行记录
+type TriggerEvent struct {
+	ID          uint            `json:"id" gorm:"primarykey"`
+	EstimateID  string          `json:"estimate_id" gorm:"size:64;uniqueIndex:idx_trigger_event"`
+	Symbol      string          `json:"symbol" gorm:"size:64;index"`
+	Exchange    string          `json:"exchange" gorm:"size:32"`
+	ActionType  string          `json:"action_type" gorm:"size:32"`
+	Result      string          `json:"result" gorm:"size:16"`
+	DetectionMs int64           `json:"detection_ms"`
+	ExecutionMs int64           `json:"execution_ms"`
+	TotalMs     int64           `json:"total_ms"`
+	Execution   json.RawMessage `json:"execution" gorm:"type:json"` // 执行记录（含Freqtrade响应），已过期时为空
+	TriggeredAt time.Time       `json:"triggered_at" gorm:"uniqueIndex:idx_trigger_event;index"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// TableName 表名
+func (TriggerEvent) TableName() string {
+	return "trigger_events"
+}
+
+// TelegramAuditHistory 对应 telegram_audits 表，Telegram指令审计记录
+type TelegramAuditHistory struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	ChatID     int64     `json:"chat_id" gorm:"uniqueIndex:idx_telegram_audit"`
+	UserID     int64     `json:"user_id" gorm:"uniqueIndex:idx_telegram_audit"`
+	Username   string    `json:"username" gorm:"size:64"`
+	Role       string    `json:"role" gorm:"size:16"`
+	Command    string    `json:"command" gorm:"type:text"`
+	Allowed    bool      `json:"allowed"`
+	Result     string    `json:"result" gorm:"type:text"`
+	ExecutedAt time.Time `json:"executed_at" gorm:"uniqueIndex:idx_telegram_audit;index"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (TelegramAuditHistory) TableName() string {
+	return "telegram_audits"
+}
+
+// PnLDaily 对应 pnl_daily 表，按来源、日期和币种汇总的已实现盈亏
+type PnLDaily struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	Source      string    `json:"source" gorm:"size:16;uniqueIndex:idx_pnl_daily"`
+	Date        string    `json:"date" gorm:"size:10;uniqueIndex:idx_pnl_daily;index"`
+	Symbol      string    `json:"symbol" gorm:"size:64;uniqueIndex:idx_pnl_daily"`
+	RealizedPnl float64   `json:"realized_pnl"`
+	Fees        float64   `json:"fees"`
+	FundingFees float64   `json:"funding_fees"`
+	Trades      int       `json:"trades"`
+	Wins        int       `json:"wins"`
+	Losses      int       `json:"losses"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (PnLDaily) TableName() string {
+	return "pnl_daily"
+}
@@ -0,0 +1,29 @@
+package notify
+
+import "context"
+
+// DiscordNotifier 通过 Discord Webhook 发送消息
+type DiscordNotifier struct {
+	webhookURL string
+}
+
+// NewDiscordNotifier 创建 Discord 通知渠道
+func NewDiscordNotifier(webhookURL string) *DiscordNotifier {
+	return &DiscordNotifier{webhookURL: webhookURL}
+}
+
+// Name 渠道名称
+func (d *DiscordNotifier) Name() string {
+	return "discord"
+}
+
+// Send 发送消息，Discord 单条消息最多2000字符
+func (d *DiscordNotifier) Send(ctx context.Context, event *Event) error {
+	content := []rune(event.Text())
+	if len(content) > 2000 {
+		content = content[:2000]
+	}
+	return postJSON(ctx, d.webhookURL, map[string]interface{}{
+		"content": string(content),
+	}, nil)
+}
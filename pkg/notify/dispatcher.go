@@ -0,0 +1,228 @@
+package notify
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	routeWildcard       = "*" // 未单独配置路由的事件类型
+	dispatchQueueSize   = 256 // 待发送事件队列长度
+	dispatchSendTimeout = 15 * time.Second
+)
+
+// Dispatcher 通知分发器，按事件类型将事件路由到对应渠道
+type Dispatcher struct {
+	notifiers map[string]Notifier
+	routes    map[string][]string // 事件类型 -> 渠道名称
+	policy    *Policy             // 通知策略，为空时所有事件直接发送
+
+	queue    chan *Event
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+var (
+	GlobalDispatcher *Dispatcher
+	dispatcherOnce   sync.Once
+)
+
+// NewDispatcher 创建通知分发器，routes 为空时所有事件发送到全部渠道
+func NewDispatcher(notifiers []Notifier, routes map[string][]string) *Dispatcher {
+	d := &Dispatcher{
+		notifiers: make(map[string]Notifier, len(notifiers)),
+		routes:    routes,
+		queue:     make(chan *Event, dispatchQueueSize),
+		stopChan:  make(chan struct{}),
+	}
+	for _, notifier := range notifiers {
+		d.notifiers[notifier.Name()] = notifier
+	}
+
+	if len(d.routes) == 0 {
+		names := d.Notifiers()
+		d.routes = map[string][]string{routeWildcard: names}
+	}
+	return d
+}
+
+// InitDispatcher 根据全局配置初始化并启动通知分发器
+func InitDispatcher() *Dispatcher {
+	dispatcherOnce.Do(func() {
+		cfg := config.GlobalConfig
+
+		var notifiers []Notifier
+		if cfg.NotifyTelegramToken != "" && cfg.NotifyTelegramChatID != "" {
+			notifiers = append(notifiers, NewTelegramNotifier(cfg.NotifyTelegramToken, cfg.NotifyTelegramChatID))
+		}
+		if cfg.NotifyDiscordWebhook != "" {
+			notifiers = append(notifiers, NewDiscordNotifier(cfg.NotifyDiscordWebhook))
+		}
+		if cfg.NotifySlackWebhook != "" {
+			notifiers = append(notifiers, NewSlackNotifier(cfg.NotifySlackWebhook))
+		}
+		if cfg.NotifyWebhookURL != "" {
+			notifiers = append(notifiers, NewWebhookNotifier(cfg.NotifyWebhookURL, cfg.NotifyWebhookSecret))
+		}
+
+		GlobalDispatcher = NewDispatcher(notifiers, ParseRoutes(cfg.NotifyRoutes))
+		GlobalDispatcher.policy = NewPolicy(PolicyConfig{
+			QuietHours:     cfg.NotifyQuietHours,
+			CriticalEvents: cfg.NotifyCriticalEvents,
+			RateLimits:     cfg.NotifyRateLimits,
+			DedupWindow:    cfg.NotifyDedupWindow,
+			DigestEvents:   cfg.NotifyDigestEvents,
+			DigestInterval: cfg.NotifyDigestInterval,
+		})
+		GlobalDispatcher.Start()
+
+		if len(notifiers) == 0 {
+			logrus.Info("未配置通知渠道，通知将被丢弃")
+		} else {
+			logrus.Infof("通知分发器已启动，渠道: %v", GlobalDispatcher.Notifiers())
+		}
+	})
+	return GlobalDispatcher
+}
+
+// ParseRoutes 解析路由配置，格式为 事件类型:渠道|渠道，如 trigger:telegram|discord、*:slack
+func ParseRoutes(items []string) map[string][]string {
+	routes := make(map[string][]string)
+	for _, item := range items {
+		eventType, targets, found := strings.Cut(item, ":")
+		eventType = strings.TrimSpace(eventType)
+		if !found || eventType == "" {
+			logrus.Warnf("忽略无效的通知路由: %s", item)
+			continue
+		}
+		for _, target := range strings.Split(targets, "|") {
+			if target = strings.ToLower(strings.TrimSpace(target)); target != "" {
+				routes[eventType] = append(routes[eventType], target)
+			}
+		}
+	}
+	return routes
+}
+
+// Notifiers 已配置的渠道名称
+func (d *Dispatcher) Notifiers() []string {
+	names := make([]string, 0, len(d.notifiers))
+	for name := range d.notifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start 启动发送协程，配置了汇总事件时同时定时发送汇总
+func (d *Dispatcher) Start() {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+
+		var digestTick <-chan time.Time
+		if d.policy != nil && d.policy.digestEvery() > 0 {
+			ticker := time.NewTicker(d.policy.digestEvery())
+			defer ticker.Stop()
+			digestTick = ticker.C
+		}
+
+		for {
+			select {
+			case event := <-d.queue:
+				d.dispatch(event)
+			case now := <-digestTick:
+				if digest := d.policy.flushDigest(now, false); digest != nil {
+					d.dispatch(digest)
+				}
+			case <-d.stopChan:
+				// 发送队列中剩余的事件和未发送的汇总
+				for {
+					select {
+					case event := <-d.queue:
+						d.dispatch(event)
+					default:
+						if d.policy != nil {
+							if digest := d.policy.flushDigest(time.Now(), true); digest != nil {
+								d.dispatch(digest)
+							}
+						}
+						return
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Stop 停止分发器，等待队列中的事件发送完成
+func (d *Dispatcher) Stop() {
+	close(d.stopChan)
+	d.wg.Wait()
+}
+
+// Notify 提交事件，队列已满时丢弃
+func (d *Dispatcher) Notify(event *Event) {
+	if d == nil || len(d.notifiers) == 0 {
+		return
+	}
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixMilli()
+	}
+
+	if d.policy != nil {
+		if result := d.policy.apply(event, time.UnixMilli(event.Timestamp)); result != policyDeliver {
+			metrics.NotificationsSent.WithLabelValues("policy", event.Type, result).Inc()
+			logrus.Debugf("通知策略未直接发送 %s 事件: %s", event.Type, result)
+			return
+		}
+	}
+
+	select {
+	case d.queue <- event:
+	default:
+		metrics.NotificationsSent.WithLabelValues("queue", event.Type, "dropped").Inc()
+		logrus.Warnf("通知队列已满，丢弃 %s 事件", event.Type)
+	}
+}
+
+// dispatch 将事件发送到路由匹配的渠道
+func (d *Dispatcher) dispatch(event *Event) {
+	targets, exists := d.routes[event.Type]
+	if !exists {
+		targets = d.routes[routeWildcard]
+	}
+
+	for _, name := range targets {
+		notifier, exists := d.notifiers[name]
+		if !exists {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), dispatchSendTimeout)
+		err := notifier.Send(ctx, event)
+		cancel()
+
+		metrics.NotificationsSent.WithLabelValues(name, event.Type, metrics.ResultLabel(err)).Inc()
+		if err != nil {
+			logrus.Errorf("通过 %s 发送 %s 通知失败: %v", name, event.Type, err)
+		}
+	}
+}
+
+// Send 通过全局分发器发送事件，未初始化时忽略
+func Send(eventType, title, message string, data map[string]interface{}) {
+	GlobalDispatcher.Notify(&Event{
+		Type:    eventType,
+		Title:   title,
+		Message: message,
+		Data:    data,
+	})
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+	"trading_assistant/pkg/i18n"
+)
+
+// 通知事件类型
+const (
+	EventTrigger      = "trigger"       // 价格预估触发并执行成功
+	EventFailure      = "failure"       // 执行失败、执行校验不一致等
+	EventReconnect    = "reconnect"     // 交易所数据流重连
+	EventReconcile    = "reconcile"     // Freqtrade 对账差异
+	EventFreqtrade    = "freqtrade"     // Freqtrade 控制器消息
+	EventExpired      = "expired"       // 价格预估到期未触发
+	EventRisk         = "risk"          // 持仓接近强平
+	EventLatency      = "latency"       // 执行延迟超过SLO
+	EventPnL          = "pnl"           // 盈亏日报/周报
+	EventStale        = "stale"         // 价格数据长时间未更新
+	EventFailover     = "failover"      // 高可用模式下主节点切换
+	EventLargeTrade   = "large_trade"   // 大额成交
+	EventLiquidation  = "liquidation"   // 集中强平
+	EventOpenInterest = "open_interest" // 持仓量异动
+	EventSelection    = "selection"     // 自动选币调整选中币种
+	EventListing      = "listing"       // 交易所新上市或下架币种
+	EventConnection   = "connection"    // 交易所推送连接延迟或时钟偏差过高
+	EventProximity    = "proximity"     // 价格接近预估目标价格
+)
+
+// Event 通知事件
+type Event struct {
+	Type      string                 `json:"type"`
+	Title     string                 `json:"title"`
+	Message   string                 `json:"message"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Timestamp int64                  `json:"timestamp"`
+}
+
+// Text 渲染为纯文本消息，末尾附带按显示时区格式化的事件时间
+func (e *Event) Text() string {
+	text := e.Message
+	if e.Title != "" {
+		text = e.Title + "\n" + e.Message
+	}
+	if e.Timestamp > 0 {
+		text += "\n" + i18n.T("notify.time", i18n.FormatMillis(e.Timestamp))
+	}
+	return text
+}
+
+// Notifier 通知渠道
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, event *Event) error
+}
+
+// httpClient 通知渠道共用的HTTP客户端
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// postJSON 发送JSON请求，非2xx响应视为失败
+func postJSON(ctx context.Context, url string, payload interface{}, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("状态码 %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"trading_assistant/pkg/i18n"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EventDigest 低优先级事件的定时汇总
+const EventDigest = "digest"
+
+// 策略处理结果，同时作为 notifications_sent_total 的 result 标签
+const (
+	policyDeliver     = "deliver"
+	policySuppressed  = "suppressed"   // 免打扰时段内的非关键事件
+	policyDuplicate   = "deduplicated" // 去重窗口内的重复事件
+	policyRateLimited = "rate_limited" // 超过事件类型的频率限制
+	policyDigested    = "digested"     // 加入汇总，定时合并发送
+)
+
+const (
+	digestMaxSamples = 5    // 汇总中每种事件保留的最近消息数
+	dedupMaxEntries  = 1000 // 去重记录超过该数量时清理过期条目
+)
+
+// PolicyConfig 通知策略配置
+type PolicyConfig struct {
+	QuietHours     string        // 免打扰时段，如 23:00-07:00，按显示时区计算，为空时不启用
+	CriticalEvents []string      // 关键事件类型，不受免打扰和汇总影响
+	RateLimits     []string      // 按事件类型限流，如 large_trade:5/1h
+	DedupWindow    time.Duration // 相同事件的去重窗口，0 表示不去重
+	DigestEvents   []string      // 合并为定时汇总发送的低优先级事件类型
+	DigestInterval time.Duration // 汇总发送间隔
+}
+
+// Policy 通知策略：免打扰时段、按事件类型限流、重复事件去重和低优先级事件汇总
+type Policy struct {
+	quietStart, quietEnd int // 免打扰时段起止，距0点的分钟数，相等时不启用
+	critical             map[string]bool
+	limits               map[string]rateLimit
+	dedupWindow          time.Duration
+	digestEvents         map[string]bool
+	digestInterval       time.Duration
+
+	mu      sync.Mutex
+	windows map[string]*rateWindow  // 事件类型 -> 当前限流窗口
+	seen    map[string]time.Time    // 事件内容 -> 最近发送时间
+	digest  map[string]*digestEntry // 事件类型 -> 待汇总事件
+}
+
+// rateLimit 事件类型在窗口内允许发送的次数
+type rateLimit struct {
+	count  int
+	window time.Duration
+}
+
+// rateWindow 限流窗口内已发送的次数
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// digestEntry 汇总中同一事件类型的计数和最近的消息
+type digestEntry struct {
+	count   int
+	samples []string
+}
+
+// NewPolicy 创建通知策略，无效的配置项记录警告后忽略
+func NewPolicy(cfg PolicyConfig) *Policy {
+	p := &Policy{
+		critical:       toSet(cfg.CriticalEvents),
+		limits:         parseRateLimits(cfg.RateLimits),
+		dedupWindow:    cfg.DedupWindow,
+		digestEvents:   toSet(cfg.DigestEvents),
+		digestInterval: cfg.DigestInterval,
+		windows:        make(map[string]*rateWindow),
+		seen:           make(map[string]time.Time),
+		digest:         make(map[string]*digestEntry),
+	}
+	if p.digestInterval <= 0 {
+		p.digestEvents = nil
+	}
+
+	if cfg.QuietHours != "" {
+		start, end, err := parseQuietHours(cfg.QuietHours)
+		if err != nil {
+			logrus.Warnf("忽略无效的免打扰时段 %s: %v", cfg.QuietHours, err)
+		} else {
+			p.quietStart, p.quietEnd = start, end
+		}
+	}
+	return p
+}
+
+// parseRateLimits 解析限流配置，格式为 事件类型:次数/窗口，如 large_trade:5/1h
+func parseRateLimits(items []string) map[string]rateLimit {
+	limits := make(map[string]rateLimit)
+	for _, item := range items {
+		eventType, spec, found := strings.Cut(item, ":")
+		eventType = strings.TrimSpace(eventType)
+		countText, windowText, hasWindow := strings.Cut(spec, "/")
+		count, countErr := strconv.Atoi(strings.TrimSpace(countText))
+		window, windowErr := time.ParseDuration(strings.TrimSpace(windowText))
+		if !found || eventType == "" || !hasWindow || countErr != nil || windowErr != nil || count <= 0 || window <= 0 {
+			logrus.Warnf("忽略无效的通知限流配置: %s", item)
+			continue
+		}
+		limits[eventType] = rateLimit{count: count, window: window}
+	}
+	return limits
+}
+
+// parseQuietHours 解析 HH:MM-HH:MM 格式的免打扰时段，结束时间早于开始时间表示跨越0点
+func parseQuietHours(value string) (int, int, error) {
+	startText, endText, found := strings.Cut(value, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("格式应为 HH:MM-HH:MM")
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(startText))
+	if err != nil {
+		return 0, 0, fmt.Errorf("开始时间无效: %v", err)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(endText))
+	if err != nil {
+		return 0, 0, fmt.Errorf("结束时间无效: %v", err)
+	}
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), nil
+}
+
+// toSet 事件类型列表转换为集合
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			set[item] = true
+		}
+	}
+	return set
+}
+
+// inQuietHours 判断时间是否处于免打扰时段
+func (p *Policy) inQuietHours(now time.Time) bool {
+	if p.quietStart == p.quietEnd {
+		return false
+	}
+	local := now.In(i18n.Location())
+	minute := local.Hour()*60 + local.Minute()
+	if p.quietStart < p.quietEnd {
+		return minute >= p.quietStart && minute < p.quietEnd
+	}
+	return minute >= p.quietStart || minute < p.quietEnd
+}
+
+// apply 按策略判断事件的处理方式：去重 -> 免打扰 -> 汇总 -> 限流
+func (p *Policy) apply(event *Event, now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.dedupWindow > 0 {
+		key := event.Type + "\x00" + event.Title + "\x00" + event.Message
+		if last, exists := p.seen[key]; exists && now.Sub(last) < p.dedupWindow {
+			return policyDuplicate
+		}
+		p.seen[key] = now
+		if len(p.seen) > dedupMaxEntries {
+			for k, t := range p.seen {
+				if now.Sub(t) >= p.dedupWindow {
+					delete(p.seen, k)
+				}
+			}
+		}
+	}
+
+	if !p.critical[event.Type] {
+		if p.inQuietHours(now) {
+			return policySuppressed
+		}
+		if p.digestEvents[event.Type] {
+			p.addDigest(event)
+			return policyDigested
+		}
+	}
+
+	if limit, exists := p.limits[event.Type]; exists {
+		window := p.windows[event.Type]
+		if window == nil || now.Sub(window.start) >= limit.window {
+			window = &rateWindow{start: now}
+			p.windows[event.Type] = window
+		}
+		if window.count >= limit.count {
+			return policyRateLimited
+		}
+		window.count++
+	}
+	return policyDeliver
+}
+
+// addDigest 将事件加入汇总，调用方需持有锁
+func (p *Policy) addDigest(event *Event) {
+	entry := p.digest[event.Type]
+	if entry == nil {
+		entry = &digestEntry{}
+		p.digest[event.Type] = entry
+	}
+	entry.count++
+
+	sample := event.Title
+	if event.Message != "" {
+		sample = strings.TrimSpace(sample + " " + strings.ReplaceAll(event.Message, "\n", " "))
+	}
+	entry.samples = append(entry.samples, sample)
+	if len(entry.samples) > digestMaxSamples {
+		entry.samples = entry.samples[len(entry.samples)-digestMaxSamples:]
+	}
+}
+
+// digestEvery 汇总发送间隔，未配置汇总事件时为0
+func (p *Policy) digestEvery() time.Duration {
+	if len(p.digestEvents) == 0 {
+		return 0
+	}
+	return p.digestInterval
+}
+
+// flushDigest 取出待汇总事件并生成汇总消息，没有待汇总事件或处于免打扰时段时返回nil
+// force 为 true 时忽略免打扰时段，用于停止前发送剩余的汇总
+func (p *Policy) flushDigest(now time.Time, force bool) *Event {
+	if !force && p.inQuietHours(now) {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.digest) == 0 {
+		return nil
+	}
+
+	types := make([]string, 0, len(p.digest))
+	total := 0
+	for eventType, entry := range p.digest {
+		types = append(types, eventType)
+		total += entry.count
+	}
+	sort.Strings(types)
+
+	var lines []string
+	data := make(map[string]interface{}, len(types))
+	for _, eventType := range types {
+		entry := p.digest[eventType]
+		data[eventType] = entry.count
+		lines = append(lines, i18n.T("notify.digest.type", eventType, entry.count))
+		for _, sample := range entry.samples {
+			lines = append(lines, "  - "+sample)
+		}
+	}
+	p.digest = make(map[string]*digestEntry)
+
+	return &Event{
+		Type:      EventDigest,
+		Title:     i18n.T("notify.digest.title", total),
+		Message:   strings.Join(lines, "\n"),
+		Data:      data,
+		Timestamp: now.UnixMilli(),
+	}
+}
@@ -0,0 +1,25 @@
+package notify
+
+import "context"
+
+// SlackNotifier 通过 Slack Incoming Webhook 发送消息
+type SlackNotifier struct {
+	webhookURL string
+}
+
+// NewSlackNotifier 创建 Slack 通知渠道
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{webhookURL: webhookURL}
+}
+
+// Name 渠道名称
+func (s *SlackNotifier) Name() string {
+	return "slack"
+}
+
+// Send 发送消息
+func (s *SlackNotifier) Send(ctx context.Context, event *Event) error {
+	return postJSON(ctx, s.webhookURL, map[string]interface{}{
+		"text": event.Text(),
+	}, nil)
+}
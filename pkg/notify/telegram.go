@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+)
+
+// TelegramNotifier 通过 Telegram Bot API 发送消息
+type TelegramNotifier struct {
+	token  string
+	chatID string
+}
+
+// NewTelegramNotifier 创建 Telegram 通知渠道
+func NewTelegramNotifier(token, chatID string) *TelegramNotifier {
+	return &TelegramNotifier{token: token, chatID: chatID}
+}
+
+// Name 渠道名称
+func (t *TelegramNotifier) Name() string {
+	return "telegram"
+}
+
+// Send 发送消息
+func (t *TelegramNotifier) Send(ctx context.Context, event *Event) error {
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.token)
+	return postJSON(ctx, url, map[string]interface{}{
+		"chat_id":                  t.chatID,
+		"text":                     event.Text(),
+		"disable_web_page_preview": true,
+	}, nil)
+}
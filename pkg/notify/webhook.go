@@ -0,0 +1,28 @@
+package notify
+
+import "context"
+
+// WebhookNotifier 将事件原样以JSON POST到自定义地址
+type WebhookNotifier struct {
+	url    string
+	secret string
+}
+
+// NewWebhookNotifier 创建通用 Webhook 通知渠道，secret 不为空时放在 X-Notify-Secret 头中
+func NewWebhookNotifier(url, secret string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, secret: secret}
+}
+
+// Name 渠道名称
+func (w *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// Send 发送事件
+func (w *WebhookNotifier) Send(ctx context.Context, event *Event) error {
+	var headers map[string]string
+	if w.secret != "" {
+		headers = map[string]string{"X-Notify-Secret": w.secret}
+	}
+	return postJSON(ctx, w.url, event, headers)
+}
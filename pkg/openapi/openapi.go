@@ -0,0 +1,367 @@
+package openapi
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Version 生成的OpenAPI文档版本
+const Version = "3.0.3"
+
+// Document OpenAPI 3 文档
+type Document struct {
+	OpenAPI    string                        `json:"openapi"`
+	Info       Info                          `json:"info"`
+	Servers    []Server                      `json:"servers,omitempty"`
+	Tags       []Tag                         `json:"tags,omitempty"`
+	Paths      map[string]map[string]*opSpec `json:"paths"`
+	Components Components                    `json:"components"`
+	Security   []map[string][]string         `json:"security,omitempty"`
+
+	schemas  map[reflect.Type]string // 已生成的结构体类型 -> 组件名称
+	seenTags map[string]bool         // 已加入的标签
+}
+
+// Info 文档信息
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server 服务地址
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Tag 接口分组
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components 可复用的结构定义和认证方式
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme 认证方式
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+// Schema JSON结构定义
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+}
+
+// Param 查询参数
+type Param struct {
+	Name        string
+	Type        string // string, integer, number, boolean
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+// Operation 一个接口的描述，由路由注册处声明
+type Operation struct {
+	Method      string
+	Path        string // gin 路由格式，如 /api/v1/estimates/:id
+	Tag         string
+	Summary     string
+	Description string
+	Query       []Param
+	Body        interface{} // 请求体示例类型，为空表示没有请求体
+	Response    interface{} // data 字段的类型，为空时只返回 message
+	List        bool        // 响应是否带 count 字段
+	Public      bool        // 是否不需要认证
+}
+
+// opSpec 序列化后的接口描述
+type opSpec struct {
+	Tags        []string               `json:"tags,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	OperationID string                 `json:"operationId"`
+	Parameters  []paramSpec            `json:"parameters,omitempty"`
+	RequestBody *requestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*response   `json:"responses"`
+	Security    *[]map[string][]string `json:"security,omitempty"`
+}
+
+type paramSpec struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*mediaType `json:"content"`
+}
+
+type response struct {
+	Description string                `json:"description"`
+	Content     map[string]*mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// ginParamPattern gin 路由参数，如 :id
+var ginParamPattern = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// NewDocument 创建文档，接口默认需要 Bearer JWT 或 X-API-Key 认证
+func NewDocument(title, description, version, serverURL string) *Document {
+	if serverURL == "" {
+		serverURL = "/"
+	}
+	return &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: title, Description: description, Version: version},
+		Servers: []Server{{URL: serverURL}},
+		Paths:   make(map[string]map[string]*opSpec),
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Error": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"error": {Type: "string", Description: "错误信息"},
+						"code":  {Type: "string", Description: "错误码（认证相关接口）"},
+					},
+				},
+			},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				"apiKeyAuth": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+			},
+		},
+		Security: []map[string][]string{{"bearerAuth": {}}, {"apiKeyAuth": {}}},
+		schemas:  make(map[reflect.Type]string),
+		seenTags: make(map[string]bool),
+	}
+}
+
+// AddTag 添加接口分组说明
+func (d *Document) AddTag(name, description string) {
+	if d.seenTags[name] {
+		return
+	}
+	d.seenTags[name] = true
+	d.Tags = append(d.Tags, Tag{Name: name, Description: description})
+}
+
+// Add 添加接口
+func (d *Document) Add(op Operation) {
+	path := ginParamPattern.ReplaceAllString(op.Path, "{$1}")
+	method := strings.ToLower(op.Method)
+
+	spec := &opSpec{
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: operationID(method, op.Path),
+		Responses:   make(map[string]*response),
+	}
+	if op.Tag != "" {
+		spec.Tags = []string{op.Tag}
+		d.AddTag(op.Tag, "")
+	}
+	if op.Public {
+		spec.Security = &[]map[string][]string{}
+	}
+
+	for _, match := range ginParamPattern.FindAllStringSubmatch(op.Path, -1) {
+		spec.Parameters = append(spec.Parameters, paramSpec{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	for _, param := range op.Query {
+		paramType := param.Type
+		if paramType == "" {
+			paramType = "string"
+		}
+		spec.Parameters = append(spec.Parameters, paramSpec{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      &Schema{Type: paramType, Enum: param.Enum},
+		})
+	}
+
+	if op.Body != nil {
+		spec.RequestBody = &requestBody{
+			Required: true,
+			Content:  map[string]*mediaType{"application/json": {Schema: d.SchemaOf(op.Body)}},
+		}
+	}
+
+	envelope := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	if op.Response != nil {
+		envelope.Properties["data"] = d.SchemaOf(op.Response)
+	}
+	if op.List {
+		envelope.Properties["count"] = &Schema{Type: "integer"}
+	}
+	if op.Method != "GET" {
+		envelope.Properties["message"] = &Schema{Type: "string"}
+	}
+	spec.Responses["200"] = &response{
+		Description: "成功",
+		Content:     map[string]*mediaType{"application/json": {Schema: envelope}},
+	}
+	spec.Responses["default"] = &response{
+		Description: "失败",
+		Content:     map[string]*mediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}}},
+	}
+	if d.Paths[path] == nil {
+		d.Paths[path] = make(map[string]*opSpec)
+	}
+	d.Paths[path][method] = spec
+}
+
+// operationID 按方法和路径生成唯一的接口ID，如 get_api_v1_estimates_id
+func operationID(method, path string) string {
+	replacer := strings.NewReplacer("/", "_", ":", "", "-", "_")
+	return method + strings.TrimRight(replacer.Replace(path), "_")
+}
+
+// SchemaOf 根据示例值的类型生成结构定义，结构体按 json 标签展开并放入 components
+func (d *Document) SchemaOf(v interface{}) *Schema {
+	return d.schemaFor(reflect.TypeOf(v))
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor 生成类型的结构定义
+func (d *Document) schemaFor(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	if t.Kind() == reflect.Ptr {
+		schema := d.schemaFor(t.Elem())
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaFor(t.Elem())}
+	case reflect.Struct:
+		return d.structSchema(t)
+	default:
+		// interface{} 等任意类型
+		return &Schema{}
+	}
+}
+
+// structSchema 具名结构体放入 components 并返回引用，匿名结构体直接展开
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return d.buildStruct(t)
+	}
+	if name, exists := d.schemas[t]; exists {
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	name := t.Name()
+	if _, exists := d.Components.Schemas[name]; exists {
+		// 不同包的同名类型加包名前缀
+		name = componentPrefix(t.PkgPath()) + name
+	}
+	d.schemas[t] = name
+	// 先占位，支持自引用类型
+	d.Components.Schemas[name] = &Schema{Type: "object"}
+	d.Components.Schemas[name] = d.buildStruct(t)
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentPrefix 包路径最后一段首字母大写，如 controllers -> Controllers
+func componentPrefix(pkgPath string) string {
+	last := pkgPath[strings.LastIndex(pkgPath, "/")+1:]
+	if last == "" {
+		return ""
+	}
+	return strings.ToUpper(last[:1]) + last[1:]
+}
+
+// buildStruct 按 json 标签展开结构体字段，匿名嵌入的结构体字段提升到外层
+func (d *Document) buildStruct(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			embedded := d.buildStruct(fieldType)
+			for propName, prop := range embedded.Properties {
+				schema.Properties[propName] = prop
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = d.schemaFor(field.Type)
+		if strings.Contains(field.Tag.Get("binding"), "required") && !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"trading_assistant/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 基差相关的Redis键
+const (
+	KeyBasisHistory = "basis"        // 基差历史（有序集合，score为时间戳）
+	KeyBasisAlerts  = "basis_alerts" // 基差告警列表
+
+	basisAlertsMaxLen = 500 // 保留的告警数量
+)
+
+// AddBasisPoint 保存基差数据点，并清理保留时长之外的数据
+func (c *Client) AddBasisPoint(point *models.BasisPoint, retention time.Duration) error {
+	key := c.nsKey(KeyBasisHistory, point.Symbol)
+	data, err := json.Marshal(point)
+	if err != nil {
+		return fmt.Errorf("序列化基差数据失败: %v", err)
+	}
+
+	pipe := c.rdb.TxPipeline()
+	pipe.ZAdd(c.ctx, key, redis.Z{Score: float64(point.Timestamp), Member: data})
+	if retention > 0 {
+		cutoff := time.Now().Add(-retention).UnixMilli()
+		pipe.ZRemRangeByScore(c.ctx, key, "-inf", strconv.FormatInt(cutoff, 10))
+	}
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return fmt.Errorf("保存基差数据失败: %v", err)
+	}
+	return nil
+}
+
+// GetBasisHistory 获取基差历史，按时间正序返回 since 之后的最近 limit 条
+func (c *Client) GetBasisHistory(symbol string, since int64, limit int64) ([]*models.BasisPoint, error) {
+	key := c.nsKey(KeyBasisHistory, symbol)
+	members, err := c.rdb.ZRevRangeByScore(c.ctx, key, &redis.ZRangeBy{
+		Min:   strconv.FormatInt(since, 10),
+		Max:   "+inf",
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取基差历史失败: %v", err)
+	}
+
+	points := make([]*models.BasisPoint, 0, len(members))
+	for i := len(members) - 1; i >= 0; i-- {
+		var point models.BasisPoint
+		if err := json.Unmarshal([]byte(members[i]), &point); err != nil {
+			continue
+		}
+		points = append(points, &point)
+	}
+	return points, nil
+}
+
+// AddBasisAlert 保存基差告警
+func (c *Client) AddBasisAlert(alert *models.BasisAlert) error {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("序列化基差告警失败: %v", err)
+	}
+
+	pipe := c.rdb.TxPipeline()
+	pipe.LPush(c.ctx, KeyBasisAlerts, data)
+	pipe.LTrim(c.ctx, KeyBasisAlerts, 0, basisAlertsMaxLen-1)
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return fmt.Errorf("保存基差告警失败: %v", err)
+	}
+	return nil
+}
+
+// GetBasisAlerts 获取最近的基差告警（新的在前）
+func (c *Client) GetBasisAlerts(limit int64) ([]*models.BasisAlert, error) {
+	if limit <= 0 {
+		limit = basisAlertsMaxLen
+	}
+	items, err := c.rdb.LRange(c.ctx, KeyBasisAlerts, 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取基差告警失败: %v", err)
+	}
+
+	alerts := make([]*models.BasisAlert, 0, len(items))
+	for _, item := range items {
+		var alert models.BasisAlert
+		if err := json.Unmarshal([]byte(item), &alert); err != nil {
+			continue
+		}
+		alerts = append(alerts, &alert)
+	}
+	return alerts, nil
+}
@@ -11,8 +11,9 @@ import (
 )
 
 type Client struct {
-	rdb *redis.Client
-	ctx context.Context
+	rdb       *redis.Client
+	ctx       context.Context
+	namespace string // 交易所命名空间，为空时使用原有键名
 }
 
 var GlobalRedisClient *Client
@@ -27,6 +28,9 @@ func InitRedis() error {
 
 	ctx := context.Background()
 
+	// 统计Redis操作错误
+	rdb.AddHook(metricsHook{})
+
 	// 测试连接
 	_, err := rdb.Ping(ctx).Result()
 	if err != nil {
@@ -42,6 +46,36 @@ func InitRedis() error {
 	return nil
 }
 
+// ForExchange 返回按交易所隔离市场和价格数据的客户端
+// 主交易所传入空字符串，保持原有键名；其他交易所的键名为 ex:<exchange>:<prefix>:<id>
+func (c *Client) ForExchange(exchange string) *Client {
+	return &Client{
+		rdb:       c.rdb,
+		ctx:       c.ctx,
+		namespace: exchange,
+	}
+}
+
+// GetNamespace 获取交易所命名空间
+func (c *Client) GetNamespace() string {
+	return c.namespace
+}
+
+// Ping 检查Redis连接，返回往返耗时
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	err := c.rdb.Ping(ctx).Err()
+	return time.Since(start), err
+}
+
+// nsKey 构建带交易所命名空间的键名
+func (c *Client) nsKey(prefix, id string) string {
+	if c.namespace == "" {
+		return fmt.Sprintf("%s:%s", prefix, id)
+	}
+	return fmt.Sprintf("%s:%s:%s:%s", KeyExchangeNamespace, c.namespace, prefix, id)
+}
+
 // Redis键名常量
 const (
 	KeyCoin          = "coin"
@@ -49,6 +83,8 @@ const (
 	KeyPriceEstimate = "price_estimate"
 	KeyPosition      = "position"
 
+	KeyExchangeNamespace = "ex" // 非主交易所的键名前缀
+
 	CacheKeyKLines = "cache:klines" // K线缓存
 	CacheKeyOrders = "cache:orders" // 订单缓存
 )
@@ -69,3 +105,11 @@ func (c *Client) Del(key string) *redis.IntCmd {
 func (c *Client) Info(section ...string) *redis.StringCmd {
 	return c.rdb.Info(c.ctx, section...)
 }
+
+// trimListTail 删除列表末尾（最旧）的 count 个元素，新元素从头部写入时不受影响
+func (c *Client) trimListTail(key string, count int) error {
+	if count <= 0 {
+		return nil
+	}
+	return c.rdb.LTrim(c.ctx, key, 0, int64(-count-1)).Err()
+}
@@ -10,7 +10,7 @@ import (
 
 // SetCoin 设置币种信息
 func (c *Client) SetCoin(coin *models.Coin) error {
-	key := fmt.Sprintf("%s:%s", KeyCoin, coin.MarketID)
+	key := c.nsKey(KeyCoin, coin.MarketID)
 	data, err := json.Marshal(coin)
 	if err != nil {
 		return err
@@ -20,7 +20,7 @@ func (c *Client) SetCoin(coin *models.Coin) error {
 
 // GetCoin 获取币种信息 (通过MarketID)
 func (c *Client) GetCoin(marketID string) (*models.Coin, error) {
-	key := fmt.Sprintf("%s:%s", KeyCoin, marketID)
+	key := c.nsKey(KeyCoin, marketID)
 	data, err := c.rdb.Get(c.ctx, key).Result()
 	if err != nil {
 		return nil, err
@@ -31,9 +31,42 @@ func (c *Client) GetCoin(marketID string) (*models.Coin, error) {
 	return &coin, err
 }
 
+// GetCoins 批量获取币种信息，使用一次MGET往返，不存在的币种不会出现在结果中
+func (c *Client) GetCoins(marketIDs []string) (map[string]*models.Coin, error) {
+	coins := make(map[string]*models.Coin, len(marketIDs))
+	if len(marketIDs) == 0 {
+		return coins, nil
+	}
+
+	keys := make([]string, len(marketIDs))
+	for i, marketID := range marketIDs {
+		keys[i] = c.nsKey(KeyCoin, marketID)
+	}
+
+	values, err := c.rdb.MGet(c.ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var coin models.Coin
+		if err := json.Unmarshal([]byte(data), &coin); err != nil {
+			logrus.Errorf("解析币种数据失败 %s: %v", keys[i], err)
+			continue
+		}
+		coins[marketIDs[i]] = &coin
+	}
+	return coins, nil
+}
+
 // GetAllCoins 获取所有币种信息
 func (c *Client) GetAllCoins() ([]*models.Coin, error) {
-	keys, err := c.rdb.Keys(c.ctx, fmt.Sprintf("%s:*", KeyCoin)).Result()
+	keys, err := c.rdb.Keys(c.ctx, c.nsKey(KeyCoin, "*")).Result()
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +92,7 @@ func (c *Client) GetAllCoins() ([]*models.Coin, error) {
 
 // DeleteCoin 删除币种信息 (通过MarketID)
 func (c *Client) DeleteCoin(marketID string) error {
-	key := fmt.Sprintf("%s:%s", KeyCoin, marketID)
+	key := c.nsKey(KeyCoin, marketID)
 	return c.rdb.Del(c.ctx, key).Err()
 }
 
@@ -70,7 +103,7 @@ func (c *Client) GetSelectedCoins() ([]*models.Coin, error) {
 
 // GetCoinBySymbol 通过Symbol获取币种信息
 func (c *Client) GetCoinBySymbol(symbol string) (*models.Coin, error) {
-	keys, err := c.rdb.Keys(c.ctx, fmt.Sprintf("%s:*", KeyCoin)).Result()
+	keys, err := c.rdb.Keys(c.ctx, c.nsKey(KeyCoin, "*")).Result()
 	if err != nil {
 		return nil, err
 	}
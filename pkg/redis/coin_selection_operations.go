@@ -186,3 +186,46 @@ func (c *Client) UpdateCoinTier(marketID string, tier string) error {
 
 	return nil
 }
+
+// KeyCoinSelectionChanges 自动选币策略的调整记录列表
+const KeyCoinSelectionChanges = "coin_selection_changes"
+
+// coinSelectionChangesMaxLen 保留的调整记录数量
+const coinSelectionChangesMaxLen = 200
+
+// AddCoinSelectionChange 保存自动选币调整记录
+func (c *Client) AddCoinSelectionChange(change *models.CoinSelectionChange) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("序列化选币调整记录失败: %v", err)
+	}
+
+	pipe := c.rdb.TxPipeline()
+	pipe.LPush(c.ctx, KeyCoinSelectionChanges, data)
+	pipe.LTrim(c.ctx, KeyCoinSelectionChanges, 0, coinSelectionChangesMaxLen-1)
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return fmt.Errorf("保存选币调整记录失败: %v", err)
+	}
+	return nil
+}
+
+// GetCoinSelectionChanges 获取最近的自动选币调整记录（新的在前）
+func (c *Client) GetCoinSelectionChanges(limit int64) ([]*models.CoinSelectionChange, error) {
+	if limit <= 0 {
+		limit = coinSelectionChangesMaxLen
+	}
+	items, err := c.rdb.LRange(c.ctx, KeyCoinSelectionChanges, 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取选币调整记录失败: %v", err)
+	}
+
+	changes := make([]*models.CoinSelectionChange, 0, len(items))
+	for _, item := range items {
+		var change models.CoinSelectionChange
+		if err := json.Unmarshal([]byte(item), &change); err != nil {
+			continue
+		}
+		changes = append(changes, &change)
+	}
+	return changes, nil
+}
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"trading_assistant/models"
+)
+
+// KeyDenylist 禁止交易名单（哈希，field为 类型:值）
+const KeyDenylist = "denylist"
+
+// denylistField 条目在哈希中的字段名
+func denylistField(entryType, value string) string {
+	return entryType + ":" + value
+}
+
+// SetDenylistEntry 保存禁止交易名单条目，相同类型和值的条目会被覆盖
+func (c *Client) SetDenylistEntry(entry *models.DenylistEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("序列化禁止交易名单失败: %v", err)
+	}
+	if err := c.rdb.HSet(c.ctx, KeyDenylist, denylistField(entry.Type, entry.Value), data).Err(); err != nil {
+		return fmt.Errorf("保存禁止交易名单失败: %v", err)
+	}
+	return nil
+}
+
+// DeleteDenylistEntry 删除禁止交易名单条目，返回条目是否存在
+func (c *Client) DeleteDenylistEntry(entryType, value string) (bool, error) {
+	deleted, err := c.rdb.HDel(c.ctx, KeyDenylist, denylistField(entryType, value)).Result()
+	if err != nil {
+		return false, fmt.Errorf("删除禁止交易名单失败: %v", err)
+	}
+	return deleted > 0, nil
+}
+
+// GetDenylist 获取所有禁止交易名单条目，按类型和值排序
+func (c *Client) GetDenylist() ([]*models.DenylistEntry, error) {
+	items, err := c.rdb.HGetAll(c.ctx, KeyDenylist).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取禁止交易名单失败: %v", err)
+	}
+
+	entries := make([]*models.DenylistEntry, 0, len(items))
+	for _, item := range items {
+		var entry models.DenylistEntry
+		if err := json.Unmarshal([]byte(item), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Type != entries[j].Type {
+			return entries[i].Type < entries[j].Type
+		}
+		return entries[i].Value < entries[j].Value
+	})
+	return entries, nil
+}
@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"trading_assistant/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyEquitySnapshots 权益快照（有序集合，score为时间戳），equity:<来源>
+const KeyEquitySnapshots = "equity"
+
+// equityKey 权益快照键名
+func equityKey(source string) string {
+	return fmt.Sprintf("%s:%s", KeyEquitySnapshots, source)
+}
+
+// AddEquitySnapshot 保存权益快照，并清理保留时长之外的数据
+func (c *Client) AddEquitySnapshot(snapshot *models.EquitySnapshot, retention time.Duration) error {
+	key := equityKey(snapshot.Source)
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("序列化权益快照失败: %v", err)
+	}
+
+	pipe := c.rdb.TxPipeline()
+	pipe.ZAdd(c.ctx, key, redis.Z{Score: float64(snapshot.Timestamp), Member: data})
+	if retention > 0 {
+		cutoff := time.Now().Add(-retention).UnixMilli()
+		pipe.ZRemRangeByScore(c.ctx, key, "-inf", strconv.FormatInt(cutoff, 10))
+	}
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return fmt.Errorf("保存权益快照失败: %v", err)
+	}
+	return nil
+}
+
+// GetEquitySnapshots 获取权益快照，按时间正序返回 since 之后的最近 limit 条
+func (c *Client) GetEquitySnapshots(source string, since int64, limit int64) ([]*models.EquitySnapshot, error) {
+	members, err := c.rdb.ZRevRangeByScore(c.ctx, equityKey(source), &redis.ZRangeBy{
+		Min:   strconv.FormatInt(since, 10),
+		Max:   "+inf",
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取权益快照失败: %v", err)
+	}
+
+	snapshots := make([]*models.EquitySnapshot, 0, len(members))
+	for i := len(members) - 1; i >= 0; i-- {
+		var snapshot models.EquitySnapshot
+		if err := json.Unmarshal([]byte(members[i]), &snapshot); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+	return snapshots, nil
+}
+
+// GetLatestEquitySnapshot 获取最近一次权益快照，没有记录时返回 nil
+func (c *Client) GetLatestEquitySnapshot(source string) (*models.EquitySnapshot, error) {
+	members, err := c.rdb.ZRevRange(c.ctx, equityKey(source), 0, 0).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取权益快照失败: %v", err)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	var snapshot models.EquitySnapshot
+	if err := json.Unmarshal([]byte(members[0]), &snapshot); err != nil {
+		return nil, fmt.Errorf("解析权益快照失败: %v", err)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+	"trading_assistant/models"
+)
+
+// KeyEstimateEvents 预估时间线，estimate_events:<预估ID>（按时间顺序）
+const KeyEstimateEvents = "estimate_events"
+
+// AddEstimateEvent 追加一条预估时间线事件，只保留最近 maxLen 条，整个时间线在 retention 后过期
+func (c *Client) AddEstimateEvent(event *models.EstimateEvent, maxLen int64, retention time.Duration) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("序列化预估事件失败: %v", err)
+	}
+
+	key := fmt.Sprintf("%s:%s", KeyEstimateEvents, event.EstimateID)
+	pipe := c.rdb.TxPipeline()
+	pipe.RPush(c.ctx, key, data)
+	if maxLen > 0 {
+		pipe.LTrim(c.ctx, key, -maxLen, -1)
+	}
+	if retention > 0 {
+		pipe.Expire(c.ctx, key, retention)
+	}
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return fmt.Errorf("保存预估事件失败: %v", err)
+	}
+	return nil
+}
+
+// GetEstimateEvents 获取预估的时间线（按时间顺序），limit 大于0时只返回最近的 limit 条
+func (c *Client) GetEstimateEvents(estimateID string, limit int64) ([]*models.EstimateEvent, error) {
+	start := int64(0)
+	if limit > 0 {
+		start = -limit
+	}
+	key := fmt.Sprintf("%s:%s", KeyEstimateEvents, estimateID)
+	items, err := c.rdb.LRange(c.ctx, key, start, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取预估事件失败: %v", err)
+	}
+
+	events := make([]*models.EstimateEvent, 0, len(items))
+	for _, item := range items {
+		var event models.EstimateEvent
+		if err := json.Unmarshal([]byte(item), &event); err != nil {
+			continue
+		}
+		events = append(events, &event)
+	}
+	return events, nil
+}
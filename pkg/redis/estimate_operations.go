@@ -2,13 +2,22 @@ package redis
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 	"trading_assistant/models"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	ErrEstimateNotFound = errors.New("价格预估不存在")
+	ErrEstimateConflict = errors.New("价格预估已被其他操作修改，请刷新后重试")
+)
+
 // SetPriceEstimate 设置价格预估
 func (c *Client) SetPriceEstimate(estimate *models.PriceEstimate) error {
 	key := fmt.Sprintf("%s:%s", KeyPriceEstimate, estimate.ID)
@@ -19,10 +28,62 @@ func (c *Client) SetPriceEstimate(estimate *models.PriceEstimate) error {
 	return c.rdb.Set(c.ctx, key, data, 0).Err()
 }
 
+// UpdatePriceEstimate 乐观并发更新价格预估：只有当前 UpdatedAt 与 expectedUpdatedAt 一致时才执行 update 并保存
+// 读取与写入之间被其他操作修改时返回 ErrEstimateConflict
+func (c *Client) UpdatePriceEstimate(id string, expectedUpdatedAt time.Time, update func(estimate *models.PriceEstimate) error) (*models.PriceEstimate, error) {
+	key := fmt.Sprintf("%s:%s", KeyPriceEstimate, id)
+
+	var updated *models.PriceEstimate
+	err := c.rdb.Watch(c.ctx, func(tx *redis.Tx) error {
+		data, err := tx.Get(c.ctx, key).Result()
+		if err == redis.Nil {
+			return ErrEstimateNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var estimate models.PriceEstimate
+		if err := json.Unmarshal([]byte(data), &estimate); err != nil {
+			return err
+		}
+		if !estimate.UpdatedAt.Equal(expectedUpdatedAt) {
+			return ErrEstimateConflict
+		}
+
+		if err := update(&estimate); err != nil {
+			return err
+		}
+		estimate.UpdatedAt = time.Now()
+
+		newData, err := json.Marshal(&estimate)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(c.ctx, key, newData, 0)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		updated = &estimate
+		return nil
+	}, key)
+
+	if errors.Is(err, redis.TxFailedErr) {
+		return nil, ErrEstimateConflict
+	}
+	return updated, err
+}
+
 // GetEstimateById 获取价格预估
 func (c *Client) GetEstimateById(id string) (*models.PriceEstimate, error) {
 	key := fmt.Sprintf("%s:%s", KeyPriceEstimate, id)
 	data, err := c.rdb.Get(c.ctx, key).Result()
+	if err == redis.Nil {
+		return nil, ErrEstimateNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -177,6 +238,25 @@ func (c *Client) GetAllEstimatesBySymbol(symbol string) ([]*models.PriceEstimate
 	return estimates, nil
 }
 
+// GetEstimatesByGridID 获取同一网格的所有价格预估，按目标价格升序
+func (c *Client) GetEstimatesByGridID(gridID string) ([]*models.PriceEstimate, error) {
+	estimates, err := c.GetAllEstimates()
+	if err != nil {
+		return nil, err
+	}
+
+	var grid []*models.PriceEstimate
+	for _, estimate := range estimates {
+		if estimate.GridID == gridID {
+			grid = append(grid, estimate)
+		}
+	}
+	sort.Slice(grid, func(i, j int) bool {
+		return grid[i].TargetPrice < grid[j].TargetPrice
+	})
+	return grid, nil
+}
+
 // GetListeningEstimateBySymbolSideAction 检查指定交易对、方向和操作类型的监听中估价
 func (c *Client) GetListeningEstimateBySymbolSideAction(symbol, side, actionType string) (*models.PriceEstimate, error) {
 	// 确保参数格式一致性：symbol大写，side小写
@@ -213,8 +293,17 @@ func (c *Client) GetListeningEstimateBySymbolSideAction(symbol, side, actionType
 	return nil, nil // 没有找到匹配的监听中估价
 }
 
-// DeletePriceEstimate 删除价格预估
+// DeletePriceEstimate 删除价格预估及其时间线，预估不存在时返回 ErrEstimateNotFound
 func (c *Client) DeletePriceEstimate(id string) error {
 	key := fmt.Sprintf("%s:%s", KeyPriceEstimate, id)
-	return c.rdb.Del(c.ctx, key).Err()
+	pipe := c.rdb.TxPipeline()
+	deleted := pipe.Del(c.ctx, key)
+	pipe.Del(c.ctx, fmt.Sprintf("%s:%s", KeyEstimateEvents, id))
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return err
+	}
+	if deleted.Val() == 0 {
+		return ErrEstimateNotFound
+	}
+	return nil
 }
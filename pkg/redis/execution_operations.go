@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"trading_assistant/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyEstimateExecution 预估执行记录，estimate_execution:<预估ID>
+const KeyEstimateExecution = "estimate_execution"
+
+// ErrExecutionTokenMismatch 执行记录已被其他实例持有
+var ErrExecutionTokenMismatch = errors.New("执行记录已被其他实例持有")
+
+// AcquireEstimateExecution 尝试取得预估的执行权（SETNX），已有执行记录时返回false
+func (c *Client) AcquireEstimateExecution(execution *models.EstimateExecution, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(execution)
+	if err != nil {
+		return false, err
+	}
+	key := fmt.Sprintf("%s:%s", KeyEstimateExecution, execution.EstimateID)
+	return c.rdb.SetNX(c.ctx, key, data, ttl).Result()
+}
+
+// UpdateEstimateExecution 更新执行记录并重置过期时间，记录不存在或令牌不一致时返回 ErrExecutionTokenMismatch
+func (c *Client) UpdateEstimateExecution(execution *models.EstimateExecution, ttl time.Duration) error {
+	key := fmt.Sprintf("%s:%s", KeyEstimateExecution, execution.EstimateID)
+	newData, err := json.Marshal(execution)
+	if err != nil {
+		return err
+	}
+
+	err = c.rdb.Watch(c.ctx, func(tx *redis.Tx) error {
+		current, err := getEstimateExecution(c, tx, key)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.Token != execution.Token {
+			return ErrExecutionTokenMismatch
+		}
+		_, err = tx.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(c.ctx, key, newData, ttl)
+			return nil
+		})
+		return err
+	}, key)
+
+	if errors.Is(err, redis.TxFailedErr) {
+		return ErrExecutionTokenMismatch
+	}
+	return err
+}
+
+// ReleaseEstimateExecution 释放未下单的执行权，只删除令牌一致的记录
+func (c *Client) ReleaseEstimateExecution(estimateID, token string) error {
+	key := fmt.Sprintf("%s:%s", KeyEstimateExecution, estimateID)
+	err := c.rdb.Watch(c.ctx, func(tx *redis.Tx) error {
+		current, err := getEstimateExecution(c, tx, key)
+		if err != nil || current == nil || current.Token != token {
+			return err
+		}
+		_, err = tx.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(c.ctx, key)
+			return nil
+		})
+		return err
+	}, key)
+
+	if errors.Is(err, redis.TxFailedErr) {
+		return nil
+	}
+	return err
+}
+
+// GetEstimateExecution 获取预估的执行记录，不存在时返回nil
+func (c *Client) GetEstimateExecution(estimateID string) (*models.EstimateExecution, error) {
+	key := fmt.Sprintf("%s:%s", KeyEstimateExecution, estimateID)
+	return getEstimateExecution(c, c.rdb, key)
+}
+
+// getEstimateExecution 读取执行记录
+func getEstimateExecution(c *Client, cmd redis.Cmdable, key string) (*models.EstimateExecution, error) {
+	data, err := cmd.Get(c.ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var execution models.EstimateExecution
+	if err := json.Unmarshal([]byte(data), &execution); err != nil {
+		return nil, err
+	}
+	return &execution, nil
+}
@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"trading_assistant/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyPositionFunding 持仓累计资金费哈希，字段为 SYMBOL:SIDE
+const KeyPositionFunding = "funding:position"
+
+// positionFundingField 持仓资金费字段名
+func positionFundingField(symbol, side string) string {
+	return fmt.Sprintf("%s:%s", symbol, strings.ToUpper(side))
+}
+
+// AddFundingPayment 为持仓累加一次资金费结算，同一结算时间只计入一次
+func (c *Client) AddFundingPayment(symbol, side string, amount, rate float64, fundingTime int64) (*models.PositionFunding, error) {
+	field := positionFundingField(symbol, side)
+	var funding *models.PositionFunding
+
+	err := c.rdb.Watch(c.ctx, func(tx *redis.Tx) error {
+		current := models.PositionFunding{Symbol: symbol, Side: strings.ToUpper(side)}
+		data, err := tx.HGet(c.ctx, KeyPositionFunding, field).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if err == nil {
+			if err := json.Unmarshal([]byte(data), &current); err != nil {
+				return fmt.Errorf("解析持仓资金费失败: %v", err)
+			}
+		}
+		if fundingTime <= current.LastFundingTime {
+			return nil
+		}
+
+		current.Total += amount
+		current.Payments++
+		current.LastRate = rate
+		current.LastAmount = amount
+		current.LastFundingTime = fundingTime
+
+		updated, err := json.Marshal(current)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(c.ctx, KeyPositionFunding, field, updated)
+			return nil
+		})
+		if err == nil {
+			funding = &current
+		}
+		return err
+	}, KeyPositionFunding)
+	if err != nil {
+		return nil, fmt.Errorf("记录资金费失败: %v", err)
+	}
+	return funding, nil
+}
+
+// GetAllPositionFunding 获取所有持仓的累计资金费，键为 SYMBOL:SIDE
+func (c *Client) GetAllPositionFunding() (map[string]*models.PositionFunding, error) {
+	items, err := c.rdb.HGetAll(c.ctx, KeyPositionFunding).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓资金费失败: %v", err)
+	}
+
+	result := make(map[string]*models.PositionFunding, len(items))
+	for field, data := range items {
+		var funding models.PositionFunding
+		if err := json.Unmarshal([]byte(data), &funding); err != nil {
+			continue
+		}
+		result[field] = &funding
+	}
+	return result, nil
+}
+
+// GetPositionFunding 获取单个持仓的累计资金费，没有记录时返回nil
+func (c *Client) GetPositionFunding(symbol, side string) (*models.PositionFunding, error) {
+	data, err := c.rdb.HGet(c.ctx, KeyPositionFunding, positionFundingField(symbol, side)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var funding models.PositionFunding
+	err = json.Unmarshal([]byte(data), &funding)
+	return &funding, err
+}
+
+// DeletePositionFunding 删除已平仓持仓的资金费记录
+func (c *Client) DeletePositionFunding(symbol, side string) error {
+	return c.rdb.HDel(c.ctx, KeyPositionFunding, positionFundingField(symbol, side)).Err()
+}
@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"trading_assistant/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyKillSwitch 全局交易紧急停止状态
+const KeyKillSwitch = "kill_switch"
+
+// SetKillSwitch 保存紧急停止状态
+func (c *Client) SetKillSwitch(state *models.KillSwitchState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("序列化紧急停止状态失败: %v", err)
+	}
+	return c.rdb.Set(c.ctx, KeyKillSwitch, data, 0).Err()
+}
+
+// GetKillSwitch 获取紧急停止状态，未设置过时返回未开启
+func (c *Client) GetKillSwitch() (*models.KillSwitchState, error) {
+	data, err := c.rdb.Get(c.ctx, KeyKillSwitch).Result()
+	if err == redis.Nil {
+		return &models.KillSwitchState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取紧急停止状态失败: %v", err)
+	}
+
+	var state models.KillSwitchState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("解析紧急停止状态失败: %v", err)
+	}
+	return &state, nil
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"trading_assistant/pkg/exchanges/types"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyKline K线历史相关的Redis键
+const (
+	KeyKline = "kline" // K线历史（有序集合，score为开盘时间戳）
+)
+
+// klineKey 构建K线历史键名
+func (c *Client) klineKey(symbol, timeframe string) string {
+	return c.nsKey(KeyKline, symbol+":"+timeframe)
+}
+
+// SaveKlines 保存K线，相同开盘时间的K线会被覆盖，并清理保留时长之外的数据
+func (c *Client) SaveKlines(symbol, timeframe string, klines []*types.Kline, retention time.Duration) error {
+	if len(klines) == 0 {
+		return nil
+	}
+
+	key := c.klineKey(symbol, timeframe)
+	pipe := c.rdb.TxPipeline()
+	for _, kline := range klines {
+		data, err := json.Marshal(kline)
+		if err != nil {
+			return fmt.Errorf("序列化K线失败: %v", err)
+		}
+		ts := strconv.FormatInt(kline.Timestamp, 10)
+		pipe.ZRemRangeByScore(c.ctx, key, ts, ts)
+		pipe.ZAdd(c.ctx, key, redis.Z{Score: float64(kline.Timestamp), Member: data})
+	}
+	if retention > 0 {
+		cutoff := time.Now().Add(-retention).UnixMilli()
+		pipe.ZRemRangeByScore(c.ctx, key, "-inf", "("+strconv.FormatInt(cutoff, 10))
+	}
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return fmt.Errorf("保存K线失败: %v", err)
+	}
+	return nil
+}
+
+// GetStoredKlines 获取开盘时间早于 before 的最近 limit 条K线（按时间正序），before 为0时从最新开始
+func (c *Client) GetStoredKlines(symbol, timeframe string, before int64, limit int64) ([]*types.Kline, error) {
+	max := "+inf"
+	if before > 0 {
+		max = "(" + strconv.FormatInt(before, 10)
+	}
+
+	members, err := c.rdb.ZRevRangeByScore(c.ctx, c.klineKey(symbol, timeframe), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   max,
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取K线历史失败: %v", err)
+	}
+
+	klines := make([]*types.Kline, 0, len(members))
+	for i := len(members) - 1; i >= 0; i-- {
+		var kline types.Kline
+		if err := json.Unmarshal([]byte(members[i]), &kline); err != nil {
+			continue
+		}
+		klines = append(klines, &kline)
+	}
+	return klines, nil
+}
+
+// GetLatestKlineTime 获取已保存的最新K线开盘时间，没有数据时返回0
+func (c *Client) GetLatestKlineTime(symbol, timeframe string) (int64, error) {
+	result, err := c.rdb.ZRevRangeWithScores(c.ctx, c.klineKey(symbol, timeframe), 0, 0).Result()
+	if err != nil {
+		return 0, fmt.Errorf("获取最新K线时间失败: %v", err)
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return int64(result[0].Score), nil
+}
+
+// GetKlinesBetween 获取开盘时间在 [from, to) 区间内的K线（按时间正序）
+func (c *Client) GetKlinesBetween(symbol, timeframe string, from, to int64) ([]*types.Kline, error) {
+	members, err := c.rdb.ZRangeByScore(c.ctx, c.klineKey(symbol, timeframe), &redis.ZRangeBy{
+		Min: strconv.FormatInt(from, 10),
+		Max: "(" + strconv.FormatInt(to, 10),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取K线区间失败: %v", err)
+	}
+
+	klines := make([]*types.Kline, 0, len(members))
+	for _, member := range members {
+		var kline types.Kline
+		if err := json.Unmarshal([]byte(member), &kline); err != nil {
+			continue
+		}
+		klines = append(klines, &kline)
+	}
+	return klines, nil
+}
@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"trading_assistant/models"
+)
+
+// 执行延迟相关的Redis键
+const (
+	KeyExecutionLatency = "execution_latency" // 执行延迟记录列表（新的在前）
+
+	executionLatencyMaxLen = 5000 // 保留的记录数量
+)
+
+// AddExecutionLatency 保存一条执行延迟记录
+func (c *Client) AddExecutionLatency(record *models.ExecutionLatency) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("序列化执行延迟失败: %v", err)
+	}
+
+	pipe := c.rdb.TxPipeline()
+	pipe.LPush(c.ctx, KeyExecutionLatency, data)
+	pipe.LTrim(c.ctx, KeyExecutionLatency, 0, executionLatencyMaxLen-1)
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return fmt.Errorf("保存执行延迟失败: %v", err)
+	}
+	return nil
+}
+
+// TrimOldestExecutionLatencies 删除最旧的 count 条执行延迟记录
+func (c *Client) TrimOldestExecutionLatencies(count int) error {
+	return c.trimListTail(KeyExecutionLatency, count)
+}
+
+// GetExecutionLatencies 获取最近的执行延迟记录（新的在前）
+func (c *Client) GetExecutionLatencies(limit int64) ([]*models.ExecutionLatency, error) {
+	if limit <= 0 || limit > executionLatencyMaxLen {
+		limit = executionLatencyMaxLen
+	}
+	items, err := c.rdb.LRange(c.ctx, KeyExecutionLatency, 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取执行延迟失败: %v", err)
+	}
+
+	records := make([]*models.ExecutionLatency, 0, len(items))
+	for _, item := range items {
+		var record models.ExecutionLatency
+		if err := json.Unmarshal([]byte(item), &record); err != nil {
+			continue
+		}
+		records = append(records, &record)
+	}
+	return records, nil
+}
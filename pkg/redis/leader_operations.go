@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyLeaderLease 主节点租约，值为持有租约的实例标识
+const KeyLeaderLease = "leader:lease"
+
+// AcquireLeaderLease 尝试获取主节点租约，已被其他实例持有时返回false
+func (c *Client) AcquireLeaderLease(owner string, ttl time.Duration) (bool, error) {
+	return c.rdb.SetNX(c.ctx, KeyLeaderLease, owner, ttl).Result()
+}
+
+// RenewLeaderLease 续期主节点租约，租约已过期或被其他实例持有时返回false
+func (c *Client) RenewLeaderLease(owner string, ttl time.Duration) (bool, error) {
+	renewed := false
+	err := c.rdb.Watch(c.ctx, func(tx *redis.Tx) error {
+		current, err := tx.Get(c.ctx, KeyLeaderLease).Result()
+		if err == redis.Nil || (err == nil && current != owner) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+			pipe.PExpire(c.ctx, KeyLeaderLease, ttl)
+			return nil
+		})
+		renewed = err == nil
+		return err
+	}, KeyLeaderLease)
+
+	if errors.Is(err, redis.TxFailedErr) {
+		return false, nil
+	}
+	return renewed, err
+}
+
+// ReleaseLeaderLease 释放主节点租约，只删除本实例持有的租约
+func (c *Client) ReleaseLeaderLease(owner string) error {
+	err := c.rdb.Watch(c.ctx, func(tx *redis.Tx) error {
+		current, err := tx.Get(c.ctx, KeyLeaderLease).Result()
+		if err == redis.Nil || (err == nil && current != owner) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(c.ctx, KeyLeaderLease)
+			return nil
+		})
+		return err
+	}, KeyLeaderLease)
+
+	if errors.Is(err, redis.TxFailedErr) {
+		return nil
+	}
+	return err
+}
+
+// GetLeaderLease 获取当前持有主节点租约的实例及剩余有效期，没有主节点时返回空字符串
+func (c *Client) GetLeaderLease() (string, time.Duration, error) {
+	owner, err := c.rdb.Get(c.ctx, KeyLeaderLease).Result()
+	if err == redis.Nil {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	ttl, err := c.rdb.PTTL(c.ctx, KeyLeaderLease).Result()
+	return owner, ttl, err
+}
@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"trading_assistant/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyLiquidation 强平分钟汇总（有序集合，score为分钟开始时间）
+const KeyLiquidation = "liquidation"
+
+// SaveLiquidationBucket 保存强平分钟汇总，覆盖同一分钟的数据并清理保留时长之外的数据
+func (c *Client) SaveLiquidationBucket(bucket *models.LiquidationBucket, retention time.Duration) error {
+	data, err := json.Marshal(bucket)
+	if err != nil {
+		return fmt.Errorf("序列化强平汇总失败: %v", err)
+	}
+
+	key := c.nsKey(KeyLiquidation, bucket.Symbol)
+	minute := strconv.FormatInt(bucket.Minute, 10)
+	pipe := c.rdb.TxPipeline()
+	pipe.ZRemRangeByScore(c.ctx, key, minute, minute)
+	pipe.ZAdd(c.ctx, key, redis.Z{Score: float64(bucket.Minute), Member: data})
+	if retention > 0 {
+		cutoff := time.Now().Add(-retention).UnixMilli()
+		pipe.ZRemRangeByScore(c.ctx, key, "-inf", "("+strconv.FormatInt(cutoff, 10))
+	}
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return fmt.Errorf("保存强平汇总失败: %v", err)
+	}
+	return nil
+}
+
+// GetLiquidationBuckets 获取 since 之后的强平分钟汇总（按时间正序）
+func (c *Client) GetLiquidationBuckets(symbol string, since int64) ([]*models.LiquidationBucket, error) {
+	members, err := c.rdb.ZRangeByScore(c.ctx, c.nsKey(KeyLiquidation, symbol), &redis.ZRangeBy{
+		Min: strconv.FormatInt(since, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取强平汇总失败: %v", err)
+	}
+
+	buckets := make([]*models.LiquidationBucket, 0, len(members))
+	for _, member := range members {
+		var bucket models.LiquidationBucket
+		if err := json.Unmarshal([]byte(member), &bucket); err != nil {
+			continue
+		}
+		buckets = append(buckets, &bucket)
+	}
+	return buckets, nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 	"trading_assistant/pkg/exchanges/types"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // KeyMarkPrice markPrice相关的Redis键
@@ -11,9 +13,12 @@ const (
 	KeyMarkPrice = "mark_price" // markPrice键前缀
 )
 
+// markPriceFields 标记价格哈希字段，顺序与 parseMarkPriceFields 一致
+var markPriceFields = []string{"symbol", "mark_price", "index_price", "funding_rate", "funding_time", "timestamp", "bid_price", "ask_price", "last_price"}
+
 // SetMarkPrice 保存标记价格数据
 func (c *Client) SetMarkPrice(markPrice *types.WatchMarkPrice) error {
-	key := fmt.Sprintf("%s:%s", KeyMarkPrice, markPrice.Symbol)
+	key := c.nsKey(KeyMarkPrice, markPrice.Symbol)
 
 	// 保存markPrice数据（包含实时买卖价）
 	err := c.rdb.HMSet(c.ctx, key, map[string]interface{}{
@@ -25,6 +30,7 @@ func (c *Client) SetMarkPrice(markPrice *types.WatchMarkPrice) error {
 		"timestamp":    markPrice.TimeStamp,
 		"bid_price":    markPrice.BidPrice, // 新增：最优买价
 		"ask_price":    markPrice.AskPrice, // 新增：最优卖价
+		"last_price":   markPrice.LastPrice,
 	}).Err()
 
 	if err != nil {
@@ -36,19 +42,55 @@ func (c *Client) SetMarkPrice(markPrice *types.WatchMarkPrice) error {
 
 // GetMarkPrice 获取标记价格数据
 func (c *Client) GetMarkPrice(marketID string) (*types.WatchMarkPrice, error) {
-	key := fmt.Sprintf("%s:%s", KeyMarkPrice, marketID)
+	key := c.nsKey(KeyMarkPrice, marketID)
 
 	// 获取markPrice数据（包含实时买卖价）
-	result, err := c.rdb.HMGet(c.ctx, key,
-		"symbol", "mark_price", "index_price", "funding_rate", "funding_time", "timestamp", "bid_price", "ask_price").Result()
+	result, err := c.rdb.HMGet(c.ctx, key, markPriceFields...).Result()
 	if err != nil {
 		return nil, fmt.Errorf("获取标记价格数据失败: %v", err)
 	}
 
-	// 检查数据是否存在
-	if result[0] == nil {
+	markPrice := parseMarkPriceFields(result)
+	if markPrice == nil {
 		return nil, fmt.Errorf("标记价格数据不存在")
 	}
+	return markPrice, nil
+}
+
+// GetMarkPrices 批量获取标记价格数据，使用一次Pipeline往返，不存在的币种不会出现在结果中
+func (c *Client) GetMarkPrices(marketIDs []string) (map[string]*types.WatchMarkPrice, error) {
+	result := make(map[string]*types.WatchMarkPrice, len(marketIDs))
+	if len(marketIDs) == 0 {
+		return result, nil
+	}
+
+	pipe := c.rdb.Pipeline()
+	cmds := make([]*redis.SliceCmd, len(marketIDs))
+	for i, marketID := range marketIDs {
+		cmds[i] = pipe.HMGet(c.ctx, c.nsKey(KeyMarkPrice, marketID), markPriceFields...)
+	}
+	if _, err := pipe.Exec(c.ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("批量获取标记价格数据失败: %v", err)
+	}
+
+	for i, cmd := range cmds {
+		fields, err := cmd.Result()
+		if err != nil {
+			continue
+		}
+		if markPrice := parseMarkPriceFields(fields); markPrice != nil {
+			result[marketIDs[i]] = markPrice
+		}
+	}
+	return result, nil
+}
+
+// parseMarkPriceFields 解析 HMGet 返回的标记价格字段，数据不存在时返回nil
+func parseMarkPriceFields(result []interface{}) *types.WatchMarkPrice {
+	// 检查数据是否存在
+	if len(result) < len(markPriceFields) || result[0] == nil {
+		return nil
+	}
 
 	// 解析数据
 	markPrice := &types.WatchMarkPrice{
@@ -113,12 +155,21 @@ func (c *Client) GetMarkPrice(marketID string) (*types.WatchMarkPrice, error) {
 		}
 	}
 
-	return markPrice, nil
+	// 解析最新成交价，旧数据没有该字段时为0
+	if result[8] != nil {
+		if lastPriceStr, ok := result[8].(string); ok {
+			if lastPriceFloat, err := parseFloat64(lastPriceStr); err == nil {
+				markPrice.LastPrice = lastPriceFloat
+			}
+		}
+	}
+
+	return markPrice
 }
 
 // DeleteMarkPrice 删除标记价格数据
 func (c *Client) DeleteMarkPrice(marketID string) error {
-	key := fmt.Sprintf("%s:%s", KeyMarkPrice, marketID)
+	key := c.nsKey(KeyMarkPrice, marketID)
 	return c.rdb.Del(c.ctx, key).Err()
 }
 
@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"trading_assistant/pkg/metrics"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// metricsHook 统计Redis操作错误的钩子
+type metricsHook struct{}
+
+// DialHook 透传连接钩子
+func (metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			metrics.RedisErrors.WithLabelValues("dial").Inc()
+		}
+		return conn, err
+	}
+}
+
+// ProcessHook 统计单条命令错误
+func (metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			metrics.RedisErrors.WithLabelValues(cmd.Name()).Inc()
+		}
+		return err
+	}
+}
+
+// ProcessPipelineHook 统计管道命令错误
+func (metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			metrics.RedisErrors.WithLabelValues("pipeline").Inc()
+		}
+		return err
+	}
+}
@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"trading_assistant/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyOpenInterest 持仓量时间序列（有序集合，score为采样时间）
+const KeyOpenInterest = "open_interest"
+
+// AddOpenInterestPoint 保存持仓量采样，相同时间的采样会被覆盖，并清理保留时长之外的数据
+func (c *Client) AddOpenInterestPoint(symbol string, point *models.OpenInterestPoint, retention time.Duration) error {
+	data, err := json.Marshal(point)
+	if err != nil {
+		return fmt.Errorf("序列化持仓量失败: %v", err)
+	}
+
+	key := c.nsKey(KeyOpenInterest, symbol)
+	ts := strconv.FormatInt(point.Timestamp, 10)
+	pipe := c.rdb.TxPipeline()
+	pipe.ZRemRangeByScore(c.ctx, key, ts, ts)
+	pipe.ZAdd(c.ctx, key, redis.Z{Score: float64(point.Timestamp), Member: data})
+	if retention > 0 {
+		cutoff := time.Now().Add(-retention).UnixMilli()
+		pipe.ZRemRangeByScore(c.ctx, key, "-inf", "("+strconv.FormatInt(cutoff, 10))
+	}
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return fmt.Errorf("保存持仓量失败: %v", err)
+	}
+	return nil
+}
+
+// GetOpenInterestPoints 获取 since 之后的持仓量采样（按时间正序）
+func (c *Client) GetOpenInterestPoints(symbol string, since int64) ([]*models.OpenInterestPoint, error) {
+	members, err := c.rdb.ZRangeByScore(c.ctx, c.nsKey(KeyOpenInterest, symbol), &redis.ZRangeBy{
+		Min: strconv.FormatInt(since, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓量失败: %v", err)
+	}
+
+	points := make([]*models.OpenInterestPoint, 0, len(members))
+	for _, member := range members {
+		var point models.OpenInterestPoint
+		if err := json.Unmarshal([]byte(member), &point); err != nil {
+			continue
+		}
+		points = append(points, &point)
+	}
+	return points, nil
+}
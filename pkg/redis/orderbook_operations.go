@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+	"trading_assistant/pkg/exchanges/types"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyOrderBook 订单簿相关的Redis键
+const (
+	KeyOrderBook = "orderbook" // 订单簿快照（top-N档位）
+)
+
+// SetOrderBook 保存订单簿快照
+func (c *Client) SetOrderBook(book *types.OrderBook, ttl time.Duration) error {
+	data, err := json.Marshal(book)
+	if err != nil {
+		return fmt.Errorf("序列化订单簿失败: %v", err)
+	}
+
+	if err := c.rdb.Set(c.ctx, c.nsKey(KeyOrderBook, book.Symbol), data, ttl).Err(); err != nil {
+		return fmt.Errorf("保存订单簿失败: %v", err)
+	}
+	return nil
+}
+
+// GetOrderBook 获取订单簿快照
+func (c *Client) GetOrderBook(symbol string) (*types.OrderBook, error) {
+	data, err := c.rdb.Get(c.ctx, c.nsKey(KeyOrderBook, symbol)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, fmt.Errorf("订单簿数据不存在")
+		}
+		return nil, fmt.Errorf("获取订单簿失败: %v", err)
+	}
+
+	var book types.OrderBook
+	if err := json.Unmarshal([]byte(data), &book); err != nil {
+		return nil, fmt.Errorf("解析订单簿失败: %v", err)
+	}
+	return &book, nil
+}
@@ -0,0 +1,150 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"trading_assistant/models"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// 模拟交易相关的Redis键
+const (
+	KeyPaperAccount  = "paper:account"
+	KeyPaperPosition = "paper:position"
+	KeyPaperFills    = "paper:fills" // 成交记录列表（新的在前）
+
+	paperFillsMaxLen = 1000 // 保留的成交记录数量
+)
+
+// GetPaperAccount 获取模拟账户，不存在时返回nil
+func (c *Client) GetPaperAccount() (*models.PaperAccount, error) {
+	data, err := c.rdb.Get(c.ctx, KeyPaperAccount).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var account models.PaperAccount
+	err = json.Unmarshal([]byte(data), &account)
+	return &account, err
+}
+
+// SetPaperAccount 保存模拟账户
+func (c *Client) SetPaperAccount(account *models.PaperAccount) error {
+	data, err := json.Marshal(account)
+	if err != nil {
+		return err
+	}
+	return c.rdb.Set(c.ctx, KeyPaperAccount, data, 0).Err()
+}
+
+// paperPositionKey 模拟持仓键名
+func paperPositionKey(symbol, side string) string {
+	return fmt.Sprintf("%s:%s:%s", KeyPaperPosition, symbol, strings.ToUpper(side))
+}
+
+// GetPaperPosition 获取模拟持仓，不存在时返回nil
+func (c *Client) GetPaperPosition(symbol, side string) (*models.PaperPosition, error) {
+	data, err := c.rdb.Get(c.ctx, paperPositionKey(symbol, side)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var position models.PaperPosition
+	err = json.Unmarshal([]byte(data), &position)
+	return &position, err
+}
+
+// SetPaperPosition 保存模拟持仓，数量为0时删除
+func (c *Client) SetPaperPosition(position *models.PaperPosition) error {
+	key := paperPositionKey(position.Symbol, position.Side)
+	if position.Amount <= 0 {
+		return c.rdb.Del(c.ctx, key).Err()
+	}
+
+	data, err := json.Marshal(position)
+	if err != nil {
+		return err
+	}
+	return c.rdb.Set(c.ctx, key, data, 0).Err()
+}
+
+// GetAllPaperPositions 获取所有模拟持仓
+func (c *Client) GetAllPaperPositions() ([]*models.PaperPosition, error) {
+	keys, err := c.rdb.Keys(c.ctx, fmt.Sprintf("%s:*", KeyPaperPosition)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]*models.PaperPosition, 0, len(keys))
+	for _, key := range keys {
+		data, err := c.rdb.Get(c.ctx, key).Result()
+		if err != nil {
+			logrus.Errorf("获取模拟持仓失败 %s: %v", key, err)
+			continue
+		}
+
+		var position models.PaperPosition
+		if err := json.Unmarshal([]byte(data), &position); err != nil {
+			logrus.Errorf("解析模拟持仓失败 %s: %v", key, err)
+			continue
+		}
+		positions = append(positions, &position)
+	}
+	return positions, nil
+}
+
+// AddPaperFill 保存模拟成交记录
+func (c *Client) AddPaperFill(fill *models.PaperFill) error {
+	data, err := json.Marshal(fill)
+	if err != nil {
+		return fmt.Errorf("序列化模拟成交失败: %v", err)
+	}
+
+	pipe := c.rdb.TxPipeline()
+	pipe.LPush(c.ctx, KeyPaperFills, data)
+	pipe.LTrim(c.ctx, KeyPaperFills, 0, paperFillsMaxLen-1)
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return fmt.Errorf("保存模拟成交失败: %v", err)
+	}
+	return nil
+}
+
+// GetPaperFills 获取最近的模拟成交记录（新的在前）
+func (c *Client) GetPaperFills(limit int64) ([]*models.PaperFill, error) {
+	if limit <= 0 || limit > paperFillsMaxLen {
+		limit = paperFillsMaxLen
+	}
+	items, err := c.rdb.LRange(c.ctx, KeyPaperFills, 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取模拟成交失败: %v", err)
+	}
+
+	fills := make([]*models.PaperFill, 0, len(items))
+	for _, item := range items {
+		var fill models.PaperFill
+		if err := json.Unmarshal([]byte(item), &fill); err != nil {
+			continue
+		}
+		fills = append(fills, &fill)
+	}
+	return fills, nil
+}
+
+// ResetPaperTrading 清空模拟账户、持仓和成交记录
+func (c *Client) ResetPaperTrading() error {
+	keys, err := c.rdb.Keys(c.ctx, fmt.Sprintf("%s:*", KeyPaperPosition)).Result()
+	if err != nil {
+		return err
+	}
+	keys = append(keys, KeyPaperAccount, KeyPaperFills)
+	return c.rdb.Del(c.ctx, keys...).Err()
+}
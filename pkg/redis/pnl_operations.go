@@ -0,0 +1,136 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"trading_assistant/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 盈亏账本相关的Redis键
+const (
+	KeyPnLLedger     = "pnl"             // pnl:<来源>:<日期> 哈希，字段为币种
+	KeyPnLRecorded   = "pnl:recorded"    // 已计入账本的记录ID集合，<来源>:<ID>
+	KeyPnLLastReport = "pnl:last_report" // 最近一次发送日报的日期
+)
+
+// pnlLedgerKey 盈亏账本键名
+func pnlLedgerKey(source, date string) string {
+	return fmt.Sprintf("%s:%s:%s", KeyPnLLedger, source, date)
+}
+
+// RecordPnL 将一笔记录累加到对应日期和币种的账本中，同一记录只计入一次
+// 返回 false 表示该记录已计入过
+func (c *Client) RecordPnL(record *models.PnLRecord) (bool, error) {
+	key := pnlLedgerKey(record.Source, record.Date)
+	member := fmt.Sprintf("%s:%s", record.Source, record.ID)
+	recorded := false
+
+	err := c.rdb.Watch(c.ctx, func(tx *redis.Tx) error {
+		exists, err := tx.SIsMember(c.ctx, KeyPnLRecorded, member).Result()
+		if err != nil || exists {
+			return err
+		}
+
+		entry := models.PnLEntry{Source: record.Source, Date: record.Date, Symbol: record.Symbol}
+		data, err := tx.HGet(c.ctx, key, record.Symbol).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if err == nil {
+			if err := json.Unmarshal([]byte(data), &entry); err != nil {
+				return fmt.Errorf("解析盈亏账本失败: %v", err)
+			}
+		}
+
+		entry.RealizedPnl += record.RealizedPnl
+		entry.Fees += record.Fees
+		entry.FundingFees += record.FundingFees
+		if record.Closed {
+			entry.Trades++
+			if record.RealizedPnl > 0 {
+				entry.Wins++
+			} else if record.RealizedPnl < 0 {
+				entry.Losses++
+			}
+		}
+
+		updated, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(c.ctx, key, record.Symbol, updated)
+			pipe.SAdd(c.ctx, KeyPnLRecorded, member)
+			return nil
+		})
+		if err == nil {
+			recorded = true
+		}
+		return err
+	}, key, KeyPnLRecorded)
+	if err != nil {
+		return false, fmt.Errorf("记录盈亏失败: %v", err)
+	}
+	return recorded, nil
+}
+
+// GetPnLEntries 获取指定来源在若干日期内的账本记录
+func (c *Client) GetPnLEntries(source string, dates []string) ([]*models.PnLEntry, error) {
+	pipe := c.rdb.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(dates))
+	for i, date := range dates {
+		cmds[i] = pipe.HGetAll(c.ctx, pnlLedgerKey(source, date))
+	}
+	if _, err := pipe.Exec(c.ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("获取盈亏账本失败: %v", err)
+	}
+
+	var entries []*models.PnLEntry
+	for _, cmd := range cmds {
+		for _, data := range cmd.Val() {
+			var entry models.PnLEntry
+			if err := json.Unmarshal([]byte(data), &entry); err != nil {
+				continue
+			}
+			entries = append(entries, &entry)
+		}
+	}
+	return entries, nil
+}
+
+// GetPnLLedgerDates 获取指定来源有账本记录的所有日期
+func (c *Client) GetPnLLedgerDates(source string) ([]string, error) {
+	prefix := pnlLedgerKey(source, "")
+	keys, err := c.rdb.Keys(c.ctx, prefix+"*").Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取盈亏账本日期失败: %v", err)
+	}
+
+	dates := make([]string, 0, len(keys))
+	for _, key := range keys {
+		dates = append(dates, strings.TrimPrefix(key, prefix))
+	}
+	return dates, nil
+}
+
+// DeletePnLLedger 删除指定来源和日期的账本
+func (c *Client) DeletePnLLedger(source, date string) error {
+	return c.rdb.Del(c.ctx, pnlLedgerKey(source, date)).Err()
+}
+
+// GetPnLLastReport 获取最近一次发送日报的日期，没有时返回空字符串
+func (c *Client) GetPnLLastReport() (string, error) {
+	date, err := c.rdb.Get(c.ctx, KeyPnLLastReport).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return date, err
+}
+
+// SetPnLLastReport 保存最近一次发送日报的日期
+func (c *Client) SetPnLLastReport(date string) error {
+	return c.rdb.Set(c.ctx, KeyPnLLastReport, date, 0).Err()
+}
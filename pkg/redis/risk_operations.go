@@ -0,0 +1,41 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"trading_assistant/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyRiskLimits 下单前风控限制
+const KeyRiskLimits = "risk_limits"
+
+// SetRiskLimits 保存下单前风控限制
+func (c *Client) SetRiskLimits(limits *models.RiskLimits) error {
+	data, err := json.Marshal(limits)
+	if err != nil {
+		return fmt.Errorf("序列化风控限制失败: %v", err)
+	}
+	return c.rdb.Set(c.ctx, KeyRiskLimits, data, 0).Err()
+}
+
+// GetRiskLimits 获取下单前风控限制，未设置时返回空限制
+func (c *Client) GetRiskLimits() (*models.RiskLimits, error) {
+	data, err := c.rdb.Get(c.ctx, KeyRiskLimits).Result()
+	if err == redis.Nil {
+		return &models.RiskLimits{Symbols: map[string]models.RiskLimit{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取风控限制失败: %v", err)
+	}
+
+	var limits models.RiskLimits
+	if err := json.Unmarshal([]byte(data), &limits); err != nil {
+		return nil, fmt.Errorf("解析风控限制失败: %v", err)
+	}
+	if limits.Symbols == nil {
+		limits.Symbols = map[string]models.RiskLimit{}
+	}
+	return &limits, nil
+}
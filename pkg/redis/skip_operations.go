@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"trading_assistant/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyMonitorSkips 预估跳过记录（Redis Stream，按条数封顶）
+const KeyMonitorSkips = "monitor_skips"
+
+// AddEvaluationSkip 追加一条跳过记录，超过 maxLen 的旧记录近似裁剪
+func (c *Client) AddEvaluationSkip(skip *models.EvaluationSkip, maxLen int64) error {
+	data, err := json.Marshal(skip)
+	if err != nil {
+		return fmt.Errorf("序列化跳过记录失败: %v", err)
+	}
+
+	err = c.rdb.XAdd(c.ctx, &redis.XAddArgs{
+		Stream: KeyMonitorSkips,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: map[string]interface{}{"data": data},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("保存跳过记录失败: %v", err)
+	}
+	return nil
+}
+
+// GetEvaluationSkips 获取最近的跳过记录（新的在前）
+func (c *Client) GetEvaluationSkips(limit int64) ([]*models.EvaluationSkip, error) {
+	messages, err := c.rdb.XRevRangeN(c.ctx, KeyMonitorSkips, "+", "-", limit).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取跳过记录失败: %v", err)
+	}
+
+	skips := make([]*models.EvaluationSkip, 0, len(messages))
+	for _, message := range messages {
+		data, ok := message.Values["data"].(string)
+		if !ok {
+			continue
+		}
+		var skip models.EvaluationSkip
+		if err := json.Unmarshal([]byte(data), &skip); err != nil {
+			continue
+		}
+		skip.ID = message.ID
+		skips = append(skips, &skip)
+	}
+	return skips, nil
+}
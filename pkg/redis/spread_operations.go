@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"trading_assistant/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// KeySpreadMonitor 价差监控的Redis键前缀
+const KeySpreadMonitor = "spread_monitor"
+
+// SetSpreadMonitor 保存价差监控
+func (c *Client) SetSpreadMonitor(monitor *models.SpreadMonitor) error {
+	key := fmt.Sprintf("%s:%s", KeySpreadMonitor, monitor.ID)
+	data, err := json.Marshal(monitor)
+	if err != nil {
+		return err
+	}
+	return c.rdb.Set(c.ctx, key, data, 0).Err()
+}
+
+// GetSpreadMonitor 获取价差监控
+func (c *Client) GetSpreadMonitor(id string) (*models.SpreadMonitor, error) {
+	key := fmt.Sprintf("%s:%s", KeySpreadMonitor, id)
+	data, err := c.rdb.Get(c.ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var monitor models.SpreadMonitor
+	err = json.Unmarshal([]byte(data), &monitor)
+	return &monitor, err
+}
+
+// GetAllSpreadMonitors 获取所有价差监控
+func (c *Client) GetAllSpreadMonitors() ([]*models.SpreadMonitor, error) {
+	keys, err := c.rdb.Keys(c.ctx, fmt.Sprintf("%s:*", KeySpreadMonitor)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	monitors := make([]*models.SpreadMonitor, 0, len(keys))
+	for i := range keys {
+		data, err := c.rdb.Get(c.ctx, keys[i]).Result()
+		if err != nil {
+			continue
+		}
+
+		var monitor models.SpreadMonitor
+		if err := json.Unmarshal([]byte(data), &monitor); err != nil {
+			logrus.Errorf("解析价差监控失败 %s: %v", keys[i], err)
+			continue
+		}
+		monitors = append(monitors, &monitor)
+	}
+	return monitors, nil
+}
+
+// DeleteSpreadMonitor 删除价差监控
+func (c *Client) DeleteSpreadMonitor(id string) error {
+	key := fmt.Sprintf("%s:%s", KeySpreadMonitor, id)
+	return c.rdb.Del(c.ctx, key).Err()
+}
@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+	"trading_assistant/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeySymbolStats 币种滚动统计的Redis键
+const KeySymbolStats = "stats"
+
+// SetSymbolStats 缓存币种滚动统计
+func (c *Client) SetSymbolStats(stats *models.SymbolStats, ttl time.Duration) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("序列化统计数据失败: %v", err)
+	}
+
+	if err := c.rdb.Set(c.ctx, c.nsKey(KeySymbolStats, stats.Symbol), data, ttl).Err(); err != nil {
+		return fmt.Errorf("保存统计数据失败: %v", err)
+	}
+	return nil
+}
+
+// GetSymbolStats 获取缓存的币种滚动统计，不存在时返回 nil
+func (c *Client) GetSymbolStats(symbol string) (*models.SymbolStats, error) {
+	data, err := c.rdb.Get(c.ctx, c.nsKey(KeySymbolStats, symbol)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("获取统计数据失败: %v", err)
+	}
+
+	var stats models.SymbolStats
+	if err := json.Unmarshal([]byte(data), &stats); err != nil {
+		return nil, fmt.Errorf("解析统计数据失败: %v", err)
+	}
+	return &stats, nil
+}
@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"trading_assistant/models"
+)
+
+// Telegram指令相关的Redis键
+const (
+	KeyTelegramAudit = "telegram:audit" // 指令审计记录列表（新的在前）
+
+	telegramAuditMaxLen = 2000 // 保留的记录数量
+)
+
+// AddTelegramAudit 保存一条指令审计记录
+func (c *Client) AddTelegramAudit(record *models.TelegramAudit) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("序列化指令审计记录失败: %v", err)
+	}
+
+	pipe := c.rdb.TxPipeline()
+	pipe.LPush(c.ctx, KeyTelegramAudit, data)
+	pipe.LTrim(c.ctx, KeyTelegramAudit, 0, telegramAuditMaxLen-1)
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return fmt.Errorf("保存指令审计记录失败: %v", err)
+	}
+	return nil
+}
+
+// TrimOldestTelegramAudits 删除最旧的 count 条指令审计记录
+func (c *Client) TrimOldestTelegramAudits(count int) error {
+	return c.trimListTail(KeyTelegramAudit, count)
+}
+
+// GetTelegramAudits 获取最近的指令审计记录（新的在前）
+func (c *Client) GetTelegramAudits(limit int64) ([]*models.TelegramAudit, error) {
+	if limit <= 0 || limit > telegramAuditMaxLen {
+		limit = telegramAuditMaxLen
+	}
+	items, err := c.rdb.LRange(c.ctx, KeyTelegramAudit, 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("获取指令审计记录失败: %v", err)
+	}
+
+	records := make([]*models.TelegramAudit, 0, len(items))
+	for _, item := range items {
+		var record models.TelegramAudit
+		if err := json.Unmarshal([]byte(item), &record); err != nil {
+			continue
+		}
+		records = append(records, &record)
+	}
+	return records, nil
+}
@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"trading_assistant/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// KeyEstimateTemplate 价格预估模板的Redis键前缀
+const KeyEstimateTemplate = "estimate_template"
+
+// SetEstimateTemplate 保存价格预估模板，同名模板会被覆盖
+func (c *Client) SetEstimateTemplate(template *models.EstimateTemplate) error {
+	key := fmt.Sprintf("%s:%s", KeyEstimateTemplate, template.Name)
+	data, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	return c.rdb.Set(c.ctx, key, data, 0).Err()
+}
+
+// GetEstimateTemplate 获取价格预估模板
+func (c *Client) GetEstimateTemplate(name string) (*models.EstimateTemplate, error) {
+	key := fmt.Sprintf("%s:%s", KeyEstimateTemplate, name)
+	data, err := c.rdb.Get(c.ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var template models.EstimateTemplate
+	err = json.Unmarshal([]byte(data), &template)
+	return &template, err
+}
+
+// GetAllEstimateTemplates 获取所有价格预估模板，按名称排序
+func (c *Client) GetAllEstimateTemplates() ([]*models.EstimateTemplate, error) {
+	keys, err := c.rdb.Keys(c.ctx, fmt.Sprintf("%s:*", KeyEstimateTemplate)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]*models.EstimateTemplate, 0, len(keys))
+	for i := range keys {
+		data, err := c.rdb.Get(c.ctx, keys[i]).Result()
+		if err != nil {
+			continue
+		}
+
+		var template models.EstimateTemplate
+		if err := json.Unmarshal([]byte(data), &template); err != nil {
+			logrus.Errorf("解析价格预估模板失败 %s: %v", keys[i], err)
+			continue
+		}
+		templates = append(templates, &template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// DeleteEstimateTemplate 删除价格预估模板
+func (c *Client) DeleteEstimateTemplate(name string) error {
+	key := fmt.Sprintf("%s:%s", KeyEstimateTemplate, name)
+	return c.rdb.Del(c.ctx, key).Err()
+}
@@ -0,0 +1,139 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// MaxMessageLength Telegram单条消息的最大字符数
+const MaxMessageLength = 4096
+
+// Update Telegram推送的更新
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message"`
+}
+
+// Message Telegram消息
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+	Date      int64  `json:"date"`
+}
+
+// User 消息发送者
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// Chat 消息所在会话
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+// apiResponse Bot API 通用响应
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	Description string          `json:"description"`
+}
+
+// Client Telegram Bot API 客户端
+type Client struct {
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient 创建Telegram Bot API客户端
+func NewClient(token string) *Client {
+	return &Client{
+		token: token,
+		// 长轮询会占用连接，超时需大于轮询时长
+		httpClient: &http.Client{Timeout: 90 * time.Second},
+	}
+}
+
+// GetUpdates 长轮询获取新的更新，offset 为上次处理的 UpdateID+1
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	var updates []Update
+	err := c.call(ctx, "getUpdates", map[string]interface{}{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}, &updates)
+	return updates, err
+}
+
+// SendMessage 向指定会话发送文本消息
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string) error {
+	return c.call(ctx, "sendMessage", map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}, nil)
+}
+
+// call 调用Bot API方法
+func (c *Client) call(ctx context.Context, method string, payload interface{}, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/%s", c.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("解析 %s 响应失败: %v", method, err)
+	}
+	if !apiResp.OK {
+		return fmt.Errorf("%s 调用失败: %s", method, apiResp.Description)
+	}
+	if result != nil {
+		return json.Unmarshal(apiResp.Result, result)
+	}
+	return nil
+}
+
+// SplitMessage 按行将长文本拆分为多条不超过 limit 字符的消息
+func SplitMessage(lines []string, limit int) []string {
+	var messages []string
+	var current []rune
+	for _, line := range lines {
+		runes := []rune(line)
+		// 单行超长时直接截断
+		if len(runes) > limit {
+			runes = runes[:limit]
+		}
+		if len(current) > 0 && len(current)+1+len(runes) > limit {
+			messages = append(messages, string(current))
+			current = current[:0]
+		}
+		if len(current) > 0 {
+			current = append(current, '\n')
+		}
+		current = append(current, runes...)
+	}
+	if len(current) > 0 {
+		messages = append(messages, string(current))
+	}
+	return messages
+}
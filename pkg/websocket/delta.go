@@ -0,0 +1,99 @@
+package websocket
+
+import (
+	"reflect"
+	"time"
+	"trading_assistant/pkg/config"
+)
+
+// defaultDeltaSnapshotInterval 未配置时的全量快照间隔
+const defaultDeltaSnapshotInterval = 30 * time.Second
+
+// deltaIgnoredFields 比较是否变化时忽略的字段，仅时间戳变化不需要推送
+var deltaIgnoredFields = map[string]bool{
+	"updateTime": true,
+}
+
+// deltaState 客户端在某个数据类型上的增量推送状态
+type deltaState struct {
+	last       map[string]interface{} // 客户端当前持有的数据，symbol -> entry
+	snapshotAt time.Time              // 上次发送全量快照的时间
+}
+
+// deltaSnapshotInterval 获取全量快照间隔
+func deltaSnapshotInterval() time.Duration {
+	if config.GlobalConfig != nil && config.GlobalConfig.WSDeltaSnapshotInterval > 0 {
+		return config.GlobalConfig.WSDeltaSnapshotInterval
+	}
+	return defaultDeltaSnapshotInterval
+}
+
+// deltaEnabled 客户端是否在指定数据类型上开启了增量推送
+func (c *Client) deltaEnabled(dataType string) bool {
+	c.subsMutex.RLock()
+	defer c.subsMutex.RUnlock()
+	return c.deltaStates[dataType] != nil
+}
+
+// diff 计算需要推送给客户端的数据
+// 到达快照间隔时返回全量数据（isDelta=false），否则只返回变化的币种；没有变化时 ok 为false
+func (c *Client) diff(dataType string, data interface{}, now time.Time) (changed interface{}, isDelta bool, ok bool) {
+	entries, isMap := data.(map[string]interface{})
+	if !isMap {
+		return data, false, true
+	}
+
+	c.subsMutex.Lock()
+	defer c.subsMutex.Unlock()
+
+	state := c.deltaStates[dataType]
+	if state == nil {
+		return data, false, true
+	}
+
+	// 定期发送全量快照，纠正客户端可能丢失的增量
+	if state.last == nil || now.Sub(state.snapshotAt) >= deltaSnapshotInterval() {
+		state.last = make(map[string]interface{}, len(entries))
+		for symbol, entry := range entries {
+			state.last[symbol] = entry
+		}
+		state.snapshotAt = now
+		return entries, false, true
+	}
+
+	delta := make(map[string]interface{})
+	for symbol, entry := range entries {
+		if prev, exists := state.last[symbol]; exists && sameEntry(prev, entry) {
+			continue
+		}
+		delta[symbol] = entry
+		state.last[symbol] = entry
+	}
+	if len(delta) == 0 {
+		return nil, true, false
+	}
+	return delta, true, true
+}
+
+// sameEntry 比较两条币种数据是否相同，忽略时间戳字段
+func sameEntry(a, b interface{}) bool {
+	am, aIsMap := a.(map[string]interface{})
+	bm, bIsMap := b.(map[string]interface{})
+	if !aIsMap || !bIsMap {
+		return reflect.DeepEqual(a, b)
+	}
+
+	if len(am) != len(bm) {
+		return false
+	}
+	for key, av := range am {
+		if deltaIgnoredFields[key] {
+			continue
+		}
+		bv, exists := bm[key]
+		if !exists || !reflect.DeepEqual(av, bv) {
+			return false
+		}
+	}
+	return true
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -14,8 +16,9 @@ var upgrades = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
 	CheckOrigin: func(r *http.Request) bool {
-		// 在生产环境中应该检查Origin
-		return true
+		// 非浏览器客户端不带Origin，浏览器来源按 CORS_ALLOWED_ORIGINS 检查
+		origin := r.Header.Get("Origin")
+		return origin == "" || middleware.OriginAllowed(origin, config.GlobalConfig.CORSAllowedOrigins)
 	},
 }
 
@@ -41,7 +44,7 @@ func (wsm *WebSocketManager) HandleWebSocket(c *gin.Context) {
 	// 升级HTTP连接为WebSocket
 	conn, err := upgrades.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
-		logrus.Errorf("WebSocket升级失败: %v", err)
+		logrus.Errorf("WebSocket升级失败 (%s): %v", c.ClientIP(), err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "WebSocket升级失败",
 			"details": err.Error(),
@@ -63,11 +66,17 @@ func (wsm *WebSocketManager) HandleWebSocket(c *gin.Context) {
 
 	logrus.WithFields(logrus.Fields{
 		"clientId":   clientID,
+		"clientIP":   c.ClientIP(),
 		"remoteAddr": c.Request.RemoteAddr,
 		"userAgent":  c.Request.UserAgent(),
 	}).Info("WebSocket连接已建立")
 }
 
+// Shutdown 关闭所有WebSocket连接
+func (wsm *WebSocketManager) Shutdown() {
+	wsm.hub.CloseAll()
+}
+
 // GetStats 获取WebSocket统计信息
 func (wsm *WebSocketManager) GetStats(c *gin.Context) {
 	stats := wsm.hub.GetStats()
@@ -91,3 +100,45 @@ func (wsm *WebSocketManager) BroadcastEstimates(data interface{}) {
 func (wsm *WebSocketManager) BroadcastPrices(data interface{}) {
 	wsm.hub.BroadcastToSubscribers(DataTypePrices, data)
 }
+
+// RegisterDataType 注册可订阅的数据类型及其初始数据提供者
+func (wsm *WebSocketManager) RegisterDataType(dataType string, provider SnapshotProvider) {
+	wsm.hub.RegisterDataType(dataType, provider)
+}
+
+// BroadcastPositions 广播持仓数据
+func (wsm *WebSocketManager) BroadcastPositions(data interface{}) {
+	wsm.hub.BroadcastToSubscribers(DataTypePositions, data)
+}
+
+// BroadcastOrders 广播订单数据
+func (wsm *WebSocketManager) BroadcastOrders(data interface{}) {
+	wsm.hub.BroadcastToSubscribers(DataTypeOrders, data)
+}
+
+// BroadcastAccount 广播账户概览
+func (wsm *WebSocketManager) BroadcastAccount(data interface{}) {
+	wsm.hub.BroadcastToSubscribers(DataTypeAccount, data)
+}
+
+// BroadcastKillSwitch 广播紧急停止状态
+func (wsm *WebSocketManager) BroadcastKillSwitch(data interface{}) {
+	wsm.hub.BroadcastToSubscribers(DataTypeKillSwitch, data)
+}
+
+// BroadcastQuality 广播交易所数据质量评分
+func (wsm *WebSocketManager) BroadcastQuality(data interface{}) {
+	wsm.hub.lastQualityMutex.Lock()
+	wsm.hub.lastQuality = data
+	wsm.hub.lastQualityMutex.Unlock()
+
+	wsm.hub.BroadcastToSubscribers(DataTypeQuality, data)
+}
+
+// BroadcastAlert 广播告警事件
+func (wsm *WebSocketManager) BroadcastAlert(alertType string, data interface{}) {
+	wsm.hub.BroadcastToSubscribers(DataTypeAlerts, map[string]interface{}{
+		"type":  alertType,
+		"alert": data,
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 	"trading_assistant/models"
+	"trading_assistant/pkg/metrics"
 	"trading_assistant/pkg/redis"
 
 	"github.com/gorilla/websocket"
@@ -33,6 +34,14 @@ type Hub struct {
 	// 订阅管理
 	subscriptions map[string]map[*Client]bool // dataType -> clients
 	subsMutex     sync.RWMutex
+
+	// 最近一次推送的数据质量评分，用于新订阅客户端的初始数据
+	lastQuality      interface{}
+	lastQualityMutex sync.RWMutex
+
+	// 支持订阅的数据类型及其初始数据提供者
+	snapshotProviders map[string]SnapshotProvider
+	providersMutex    sync.RWMutex
 }
 
 // Client 表示单个WebSocket客户端
@@ -50,6 +59,8 @@ type Client struct {
 
 	// 客户端订阅的数据类型
 	subscriptions map[string]bool
+	symbolFilters map[string]map[string]bool // dataType -> 关注的币种，未设置时推送全部
+	deltaStates   map[string]*deltaState     // dataType -> 增量推送状态，仅开启增量模式的订阅
 	subsMutex     sync.RWMutex
 
 	// 连接时间
@@ -65,11 +76,13 @@ type Client struct {
 
 // Message 表示WebSocket消息格式
 type Message struct {
-	Type      string      `json:"type"`      // message, subscribe, unsubscribe, ping, pong, error
-	DataType  string      `json:"dataType"`  // estimates, prices
-	Data      interface{} `json:"data"`      // 实际数据
-	Timestamp int64       `json:"timestamp"` // 时间戳
-	ClientID  string      `json:"clientId"`  // 客户端ID（仅用于调试）
+	Type      string      `json:"type"`              // message, subscribe, unsubscribe, ping, pong, error
+	DataType  string      `json:"dataType"`          // estimates, prices, quality, alerts, positions, orders, account
+	Symbols   []string    `json:"symbols,omitempty"` // 订阅参数：只接收指定币种的数据（prices、positions、orders支持）
+	Delta     bool        `json:"delta,omitempty"`   // 订阅参数：开启增量推送；推送消息中表示Data只包含变化的币种
+	Data      interface{} `json:"data"`              // 实际数据
+	Timestamp int64       `json:"timestamp"`         // 时间戳
+	ClientID  string      `json:"clientId"`          // 客户端ID（仅用于调试）
 }
 
 // ErrorMessage 错误消息格式
@@ -89,25 +102,34 @@ const (
 	MessageTypeError       = "error"
 
 	// 数据类型
-	DataTypeEstimates = "estimates"
-	DataTypePrices    = "prices"
+	DataTypeEstimates  = "estimates"
+	DataTypePrices     = "prices"
+	DataTypeQuality    = "quality"
+	DataTypeAlerts     = "alerts"
+	DataTypePositions  = "positions"
+	DataTypeOrders     = "orders"
+	DataTypeAccount    = "account"
+	DataTypeKillSwitch = "kill_switch"
 
 	// 时间常量
 	writeWait      = 10 * time.Second    // 写入等待时间
 	pongWait       = 60 * time.Second    // Pong等待时间
 	pingPeriod     = (pongWait * 9) / 10 // Ping发送周期
-	maxMessageSize = 512                 // 最大消息大小
+	maxMessageSize = 32 * 1024           // 最大消息大小，需容纳订阅时携带的币种列表
 )
 
 // NewHub 创建新的Hub
 func NewHub() *Hub {
-	return &Hub{
-		broadcast:     make(chan []byte),
-		register:      make(chan *Client),
-		unregister:    make(chan *Client),
-		clients:       make(map[*Client]bool),
-		subscriptions: make(map[string]map[*Client]bool),
-	}
+	h := &Hub{
+		broadcast:         make(chan []byte),
+		register:          make(chan *Client),
+		unregister:        make(chan *Client),
+		clients:           make(map[*Client]bool),
+		subscriptions:     make(map[string]map[*Client]bool),
+		snapshotProviders: make(map[string]SnapshotProvider),
+	}
+	h.registerBuiltinDataTypes()
+	return h
 }
 
 // Run 启动Hub
@@ -117,6 +139,7 @@ func (h *Hub) Run() {
 		case client := <-h.register:
 			h.clientsMutex.Lock()
 			h.clients[client] = true
+			metrics.HubClients.Set(float64(len(h.clients)))
 			h.clientsMutex.Unlock()
 			logrus.WithField("clientId", client.id).Info("客户端已连接")
 
@@ -153,6 +176,7 @@ func (h *Hub) Run() {
 
 				logrus.WithField("clientId", client.id).Info("客户端已断开")
 			}
+			metrics.HubClients.Set(float64(len(h.clients)))
 			h.clientsMutex.Unlock()
 
 		case message := <-h.broadcast:
@@ -190,19 +214,55 @@ func (h *Hub) GetStats() map[string]interface{} {
 	}
 }
 
+// CloseAll 向所有客户端发送关闭帧并断开连接
+func (h *Hub) CloseAll() {
+	h.clientsMutex.Lock()
+	clientList := make([]*Client, 0, len(h.clients))
+	for client := range h.clients {
+		clientList = append(clientList, client)
+		delete(h.clients, client)
+	}
+	metrics.HubClients.Set(0)
+	h.clientsMutex.Unlock()
+
+	h.subsMutex.Lock()
+	h.subscriptions = make(map[string]map[*Client]bool)
+	h.subsMutex.Unlock()
+
+	closeMessage := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for i := range clientList {
+		client := clientList[i]
+		// WriteControl 可与其他写操作并发调用
+		if err := client.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(writeWait)); err != nil {
+			logrus.Debugf("向客户端 %s 发送关闭帧失败: %v", client.id, err)
+		}
+		client.safeClose()
+	}
+
+	logrus.Infof("已关闭 %d 个WebSocket客户端连接", len(clientList))
+}
+
 // BroadcastToSubscribers 向订阅指定数据类型的客户端广播消息
 func (h *Hub) BroadcastToSubscribers(dataType string, data interface{}) {
+	now := time.Now()
 	message := Message{
 		Type:      MessageTypeMessage,
 		DataType:  dataType,
 		Data:      data,
-		Timestamp: time.Now().UnixMilli(),
+		Timestamp: now.UnixMilli(),
 	}
 
-	messageData, err := json.Marshal(message)
-	if err != nil {
-		logrus.Errorf("序列化广播消息失败: %v", err)
-		return
+	// 全量数据延迟到有未设置过滤的客户端时才序列化
+	var messageData []byte
+	marshalFull := func() ([]byte, error) {
+		if messageData == nil {
+			data, err := json.Marshal(message)
+			if err != nil {
+				return nil, err
+			}
+			messageData = data
+		}
+		return messageData, nil
 	}
 
 	h.subsMutex.RLock()
@@ -233,6 +293,41 @@ func (h *Hub) BroadcastToSubscribers(dataType string, data interface{}) {
 			continue
 		}
 
+		// 按客户端的币种过滤和增量模式只序列化其需要的数据
+		clientMessage := message
+		customized := false
+		if filter := client.symbolFilter(dataType); filter != nil {
+			filtered, ok := filterSymbols(clientMessage.Data, filter)
+			if !ok {
+				// 本次数据中没有客户端关注的币种
+				continue
+			}
+			clientMessage.Data = filtered
+			customized = true
+		}
+		if client.deltaEnabled(dataType) {
+			changed, isDelta, ok := client.diff(dataType, clientMessage.Data, now)
+			if !ok {
+				// 与上次推送相比没有变化
+				continue
+			}
+			clientMessage.Data = changed
+			clientMessage.Delta = isDelta
+			customized = true
+		}
+
+		var payload []byte
+		var err error
+		if customized {
+			payload, err = json.Marshal(clientMessage)
+		} else {
+			payload, err = marshalFull()
+		}
+		if err != nil {
+			logrus.Errorf("序列化广播消息失败: %v", err)
+			return
+		}
+
 		// 使用defer + recover来捕获panic
 		func() {
 			defer func() {
@@ -243,7 +338,7 @@ func (h *Hub) BroadcastToSubscribers(dataType string, data interface{}) {
 			}()
 
 			select {
-			case client.send <- messageData:
+			case client.send <- payload:
 				successCount++
 			default:
 				// 客户端发送缓冲区已满，标记为失败
@@ -252,6 +347,9 @@ func (h *Hub) BroadcastToSubscribers(dataType string, data interface{}) {
 		}()
 	}
 
+	metrics.HubMessages.WithLabelValues(dataType, "success").Add(float64(successCount))
+	metrics.HubMessages.WithLabelValues(dataType, "failed").Add(float64(len(failedClients)))
+
 	// 清理失败的客户端
 	for i := range failedClients {
 		client := failedClients[i]
@@ -262,8 +360,8 @@ func (h *Hub) BroadcastToSubscribers(dataType string, data interface{}) {
 		len(clientList), dataType, successCount, len(failedClients))
 }
 
-// Subscribe 客户端订阅数据类型
-func (h *Hub) Subscribe(client *Client, dataType string) {
+// Subscribe 客户端订阅数据类型，symbols 非空时只推送这些币种的数据，delta 开启增量推送，重复订阅会覆盖之前的参数
+func (h *Hub) Subscribe(client *Client, dataType string, symbols []string, delta bool) {
 	h.subsMutex.Lock()
 	defer h.subsMutex.Unlock()
 
@@ -274,11 +372,28 @@ func (h *Hub) Subscribe(client *Client, dataType string) {
 
 	client.subsMutex.Lock()
 	client.subscriptions[dataType] = true
+	if len(symbols) > 0 {
+		filter := make(map[string]bool, len(symbols))
+		for _, symbol := range symbols {
+			filter[symbol] = true
+		}
+		client.symbolFilters[dataType] = filter
+	} else {
+		delete(client.symbolFilters, dataType)
+	}
+	if delta {
+		// 重置状态，下一次推送为全量快照
+		client.deltaStates[dataType] = &deltaState{}
+	} else {
+		delete(client.deltaStates, dataType)
+	}
 	client.subsMutex.Unlock()
 
 	logrus.WithFields(logrus.Fields{
 		"clientId": client.id,
 		"dataType": dataType,
+		"symbols":  len(symbols),
+		"delta":    delta,
 	}).Info("客户端订阅数据类型")
 
 	// 立即推送该数据类型的当前数据
@@ -296,6 +411,8 @@ func (h *Hub) Unsubscribe(client *Client, dataType string) {
 
 	client.subsMutex.Lock()
 	delete(client.subscriptions, dataType)
+	delete(client.symbolFilters, dataType)
+	delete(client.deltaStates, dataType)
 	client.subsMutex.Unlock()
 
 	logrus.WithFields(logrus.Fields{
@@ -304,6 +421,38 @@ func (h *Hub) Unsubscribe(client *Client, dataType string) {
 	}).Info("客户端取消订阅数据类型")
 }
 
+// symbolFilterable 数据类型是否按币种组织，支持订阅时按币种过滤
+func symbolFilterable(dataType string) bool {
+	switch dataType {
+	case DataTypePrices, DataTypePositions, DataTypeOrders:
+		return true
+	}
+	return false
+}
+
+// symbolFilter 获取客户端在指定数据类型上的币种过滤，nil 表示不过滤
+func (c *Client) symbolFilter(dataType string) map[string]bool {
+	c.subsMutex.RLock()
+	defer c.subsMutex.RUnlock()
+	return c.symbolFilters[dataType]
+}
+
+// filterSymbols 只保留过滤集合中的币种，数据不是按币种组织时原样返回，没有匹配的币种时返回false
+func filterSymbols(data interface{}, filter map[string]bool) (interface{}, bool) {
+	entries, ok := data.(map[string]interface{})
+	if !ok {
+		return data, true
+	}
+
+	filtered := make(map[string]interface{}, len(filter))
+	for symbol := range filter {
+		if entry, exists := entries[symbol]; exists {
+			filtered[symbol] = entry
+		}
+	}
+	return filtered, len(filtered) > 0
+}
+
 // unregisterClient 注销客户端
 func (h *Hub) unregisterClient(client *Client) {
 	// 检查客户端是否已经关闭
@@ -350,6 +499,8 @@ func NewClient(hub *Hub, conn *websocket.Conn, id string) *Client {
 		send:          make(chan []byte, 256),
 		id:            id,
 		subscriptions: make(map[string]bool),
+		symbolFilters: make(map[string]map[string]bool),
+		deltaStates:   make(map[string]*deltaState),
 		connectedAt:   time.Now(),
 		lastActivity:  time.Now(),
 	}
@@ -461,12 +612,24 @@ func (c *Client) handleMessage(msg *Message) {
 			return
 		}
 
-		c.hub.Subscribe(c, msg.DataType)
+		// 币种过滤和增量推送只适用于按币种组织的数据
+		if len(msg.Symbols) > 0 && !symbolFilterable(msg.DataType) {
+			c.sendError("INVALID_SYMBOLS", "订阅失败", fmt.Sprintf("%s 不支持按币种过滤", msg.DataType))
+			return
+		}
+		if msg.Delta && msg.DataType != DataTypePrices {
+			c.sendError("INVALID_DELTA", "订阅失败", fmt.Sprintf("%s 不支持增量推送", msg.DataType))
+			return
+		}
+
+		c.hub.Subscribe(c, msg.DataType, msg.Symbols, msg.Delta)
 
 		// 发送订阅确认
 		response := Message{
 			Type:      MessageTypeMessage,
 			DataType:  "system",
+			Symbols:   msg.Symbols,
+			Delta:     msg.Delta,
 			Data:      map[string]string{"action": "subscribed", "dataType": msg.DataType},
 			Timestamp: time.Now().UnixMilli(),
 			ClientID:  c.id,
@@ -509,17 +672,8 @@ func (c *Client) handleMessage(msg *Message) {
 
 // isValidDataType 验证数据类型是否有效
 func (c *Client) isValidDataType(dataType string) bool {
-	validTypes := []string{
-		DataTypeEstimates,
-		DataTypePrices,
-	}
-
-	for _, validType := range validTypes {
-		if dataType == validType {
-			return true
-		}
-	}
-	return false
+	_, exists := c.hub.snapshotProvider(dataType)
+	return exists
 }
 
 // sendMessage 发送消息给客户端
@@ -568,20 +722,19 @@ func (c *Client) StartClient() {
 
 // sendInitialDataForType 为新订阅的客户端发送初始数据
 func (h *Hub) sendInitialDataForType(client *Client, dataType string) {
-	var data interface{}
-	var err error
-
-	switch dataType {
-	case DataTypePrices:
-		// 获取当前价格数据
-		data, err = h.getCurrentPricesData()
-	case DataTypeEstimates:
-		// 获取当前预估数据
-		data, err = h.getCurrentEstimatesData()
-	default:
+	provider, exists := h.snapshotProvider(dataType)
+	if !exists {
 		logrus.Warnf("未知的数据类型: %s", dataType)
 		return
 	}
+	if provider == nil {
+		// 只推送实时事件，没有初始数据
+		return
+	}
+
+	start := time.Now()
+	data, err := provider()
+	metrics.HubInitialSnapshotDuration.WithLabelValues(dataType).Observe(time.Since(start).Seconds())
 
 	if err != nil {
 		logrus.Errorf("获取 %s 初始数据失败: %v", dataType, err)
@@ -593,6 +746,15 @@ func (h *Hub) sendInitialDataForType(client *Client, dataType string) {
 		return
 	}
 
+	if filter := client.symbolFilter(dataType); filter != nil {
+		filtered, ok := filterSymbols(data, filter)
+		if !ok {
+			logrus.Debugf("客户端 %s 关注的币种没有可用的 %s 初始数据", client.id, dataType)
+			return
+		}
+		data = filtered
+	}
+
 	// 发送初始数据
 	message := Message{
 		Type:      MessageTypeMessage,
@@ -630,40 +792,47 @@ func (h *Hub) getCurrentPricesData() (interface{}, error) {
 		return nil, fmt.Errorf("获取选中币种失败: %v", err)
 	}
 
-	pricesData := make(map[string]interface{})
-	for i := range selectedMarketIDs {
-		marketID := selectedMarketIDs[i]
-		// 获取币种详情以得到价格变化信息
-		coin, err := redis.GlobalRedisClient.GetCoin(marketID)
-		if err != nil {
+	// 批量获取币种详情和标记价格，避免逐个币种往返Redis
+	coins, err := redis.GlobalRedisClient.GetCoins(selectedMarketIDs)
+	if err != nil {
+		return nil, fmt.Errorf("批量获取币种失败: %v", err)
+	}
+	markPrices, err := redis.GlobalRedisClient.GetMarkPrices(selectedMarketIDs)
+	if err != nil {
+		return nil, fmt.Errorf("批量获取标记价格失败: %v", err)
+	}
+
+	pricesData := make(map[string]interface{}, len(markPrices))
+	for _, marketID := range selectedMarketIDs {
+		coin, exists := coins[marketID]
+		if !exists {
+			continue
+		}
+		markPrice, exists := markPrices[marketID]
+		if !exists {
 			continue
 		}
 
-		// 直接使用MarketID获取标记价格
-		if markPrice, err := redis.GlobalRedisClient.GetMarkPrice(marketID); err == nil {
-			// 从Redis获取coin数据来获取价格变化信息
-			priceChange := 0.0
-			priceChangePercent := 0.0
-
-			// 我们已经有了coin对象，直接使用
-			if change, parseErr := strconv.ParseFloat(coin.PriceChange, 64); parseErr == nil {
-				priceChange = change
-			}
-			if changePercent, parseErr := strconv.ParseFloat(coin.PriceChangePercent, 64); parseErr == nil {
-				priceChangePercent = changePercent
-			}
+		// 从coin数据获取价格变化信息
+		priceChange := 0.0
+		priceChangePercent := 0.0
+		if change, parseErr := strconv.ParseFloat(coin.PriceChange, 64); parseErr == nil {
+			priceChange = change
+		}
+		if changePercent, parseErr := strconv.ParseFloat(coin.PriceChangePercent, 64); parseErr == nil {
+			priceChangePercent = changePercent
+		}
 
-			// 直接使用MarketID作为显示标识
-			pricesData[marketID] = map[string]interface{}{
-				"symbol":             marketID,
-				"markPrice":          markPrice.MarkPrice,
-				"indexPrice":         markPrice.IndexPrice,
-				"fundingRate":        markPrice.FundingRate,
-				"fundingTime":        markPrice.FundingTime,
-				"updateTime":         markPrice.TimeStamp,
-				"priceChange":        priceChange,
-				"priceChangePercent": priceChangePercent,
-			}
+		// 直接使用MarketID作为显示标识
+		pricesData[marketID] = map[string]interface{}{
+			"symbol":             marketID,
+			"markPrice":          markPrice.MarkPrice,
+			"indexPrice":         markPrice.IndexPrice,
+			"fundingRate":        markPrice.FundingRate,
+			"fundingTime":        markPrice.FundingTime,
+			"updateTime":         markPrice.TimeStamp,
+			"priceChange":        priceChange,
+			"priceChangePercent": priceChangePercent,
 		}
 	}
 
@@ -0,0 +1,35 @@
+package websocket
+
+// SnapshotProvider 获取数据类型的当前快照，客户端订阅时作为初始数据发送，返回nil表示暂无数据
+type SnapshotProvider func() (interface{}, error)
+
+// RegisterDataType 注册可订阅的数据类型，provider 为nil时该类型只推送实时数据
+// 重复注册时覆盖之前的提供者
+func (h *Hub) RegisterDataType(dataType string, provider SnapshotProvider) {
+	h.providersMutex.Lock()
+	defer h.providersMutex.Unlock()
+	h.snapshotProviders[dataType] = provider
+}
+
+// snapshotProvider 获取数据类型的初始数据提供者，第二个返回值表示数据类型是否已注册
+func (h *Hub) snapshotProvider(dataType string) (SnapshotProvider, bool) {
+	h.providersMutex.RLock()
+	defer h.providersMutex.RUnlock()
+	provider, exists := h.snapshotProviders[dataType]
+	return provider, exists
+}
+
+// registerBuiltinDataTypes 注册Hub内置的数据类型，持仓、订单等由业务层在启动时注册
+func (h *Hub) registerBuiltinDataTypes() {
+	h.RegisterDataType(DataTypePrices, h.getCurrentPricesData)
+	h.RegisterDataType(DataTypeEstimates, h.getCurrentEstimatesData)
+	h.RegisterDataType(DataTypeQuality, h.getLastQuality)
+	h.RegisterDataType(DataTypeAlerts, nil)
+}
+
+// getLastQuality 最近一次推送的数据质量评分
+func (h *Hub) getLastQuality() (interface{}, error) {
+	h.lastQualityMutex.RLock()
+	defer h.lastQualityMutex.RUnlock()
+	return h.lastQuality, nil
+}
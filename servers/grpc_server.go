@@ -0,0 +1,130 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"trading_assistant/controllers"
+	"trading_assistant/pkg/auth"
+	"trading_assistant/pkg/config"
+	"trading_assistant/pkg/grpcapi/assistantpb"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcViewerMethods 只读角色可以调用的方法，其他方法需要交易角色
+var grpcViewerMethods = map[string]bool{
+	assistantpb.AssistantService_ListEstimates_FullMethodName:    true,
+	assistantpb.AssistantService_GetEstimate_FullMethodName:      true,
+	assistantpb.AssistantService_GetMarkPrice_FullMethodName:     true,
+	assistantpb.AssistantService_StreamMarkPrices_FullMethodName: true,
+	assistantpb.AssistantService_ListPositions_FullMethodName:    true,
+}
+
+// GRPCServer gRPC服务器，认证方式与HTTP接口一致：metadata 中的 x-api-key 或 authorization: Bearer <token>
+type GRPCServer struct {
+	server *grpc.Server
+	port   string
+}
+
+// NewGRPCServer 创建gRPC服务器
+func NewGRPCServer(priceController *controllers.PriceController) *GRPCServer {
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(grpcUnaryAuth),
+		grpc.StreamInterceptor(grpcStreamAuth),
+	)
+	assistantpb.RegisterAssistantServiceServer(server, controllers.NewAssistantGRPCService(priceController))
+
+	return &GRPCServer{
+		server: server,
+		port:   config.GlobalConfig.GRPCPort,
+	}
+}
+
+// Start 启动gRPC服务器
+func (s *GRPCServer) Start() error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", s.port))
+	if err != nil {
+		return fmt.Errorf("gRPC服务器监听端口 %s 失败: %w", s.port, err)
+	}
+
+	logrus.Infof("gRPC服务器启动在端口 %s", s.port)
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			logrus.Errorf("gRPC服务器异常退出: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Shutdown 优雅关闭gRPC服务器，超时后强制断开价格订阅等长连接
+func (s *GRPCServer) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.server.Stop()
+	}
+	logrus.Info("gRPC服务器已关闭")
+	return nil
+}
+
+// grpcUnaryAuth 单次调用认证
+func grpcUnaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := grpcAuthorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// grpcStreamAuth 流式调用认证
+func grpcStreamAuth(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := grpcAuthorize(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// grpcAuthorize 验证接口密钥或JWT，并检查角色是否可以调用该方法
+func grpcAuthorize(ctx context.Context, method string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var username, role string
+	if keys := md.Get("x-api-key"); len(keys) > 0 && keys[0] != "" {
+		key, ok := auth.ValidateAPIKey(keys[0])
+		if !ok {
+			return status.Error(codes.Unauthenticated, "无效的接口密钥")
+		}
+		username, role = "apikey:"+key.Name, key.Role
+	} else {
+		values := md.Get("authorization")
+		if len(values) == 0 || !strings.HasPrefix(values[0], "Bearer ") {
+			return status.Error(codes.Unauthenticated, "缺少 x-api-key 或 authorization: Bearer <token>")
+		}
+		claims, err := auth.ValidateToken(strings.TrimPrefix(values[0], "Bearer "))
+		if err != nil {
+			return status.Error(codes.Unauthenticated, "无效的token")
+		}
+		username, role = claims.Username, claims.Role
+	}
+
+	required := auth.RoleTrader
+	if grpcViewerMethods[method] {
+		required = auth.RoleViewer
+	}
+	if !auth.RoleAllows(role, required) {
+		logrus.Warnf("gRPC权限不足: %s (%s) 调用 %s", username, role, method)
+		return status.Error(codes.PermissionDenied, "权限不足，该操作需要交易权限")
+	}
+	return nil
+}
@@ -1,7 +1,10 @@
 package servers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"trading_assistant/apis"
 	"trading_assistant/core"
 	"trading_assistant/pkg/config"
@@ -9,6 +12,7 @@ import (
 	"trading_assistant/pkg/exchange_factory"
 	"trading_assistant/pkg/freqtrade"
 	"trading_assistant/pkg/middleware"
+	"trading_assistant/pkg/websocket"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus" // Keep logrus for Start method
@@ -16,6 +20,7 @@ import (
 
 type HTTPServer struct {
 	engine              *gin.Engine
+	server              *http.Server
 	port                string
 	exchangeClient      exchange_factory.ExchangeInterface
 	marketManager       *core.MarketManager
@@ -35,15 +40,28 @@ func NewHTTPServer(exchangeClient exchange_factory.ExchangeInterface, marketMana
 	}
 
 	r := gin.New()
+	// 只信任配置的反向代理转发的客户端IP，REST接口和 /ws 都通过 ClientIP 识别客户端
+	if err := r.SetTrustedProxies(config.GlobalConfig.TrustedProxies); err != nil {
+		logrus.Fatalf("TRUSTED_PROXIES 配置错误: %v", err)
+	}
 	r.Use(gin.Recovery())
-	r.Use(middleware.Cors())
+	r.Use(middleware.Cors(config.GlobalConfig.CORSAllowedOrigins))
 
 	// Initialize routes
 	apis.SetupRoutes(r, exchangeClient, marketManager, freqtradeController)
 
+	port := config.GlobalConfig.HTTPPort
+	if port == "" {
+		port = "8080"
+	}
+
 	return &HTTPServer{
-		engine:              r,
-		port:                "8080",
+		engine: r,
+		server: &http.Server{
+			Addr:    fmt.Sprintf(":%s", port),
+			Handler: middleware.StripBasePath(config.GlobalConfig.BasePath, r),
+		},
+		port:                port,
 		exchangeClient:      exchangeClient,
 		marketManager:       marketManager,
 		freqtradeController: freqtradeController,
@@ -52,10 +70,27 @@ func NewHTTPServer(exchangeClient exchange_factory.ExchangeInterface, marketMana
 
 // Start 启动HTTP服务器
 func (s *HTTPServer) Start() {
-	addr := fmt.Sprintf(":%s", s.port)
 	logrus.Infof("HTTP服务器启动在端口 %s", s.port)
+	if basePath := config.GlobalConfig.BasePath; basePath != "" {
+		logrus.Infof("HTTP服务路径前缀: %s", basePath)
+	}
 
-	if err := s.engine.Run(addr); err != nil {
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		logrus.Fatalf("HTTP服务器启动失败: %v", err)
 	}
 }
+
+// Shutdown 优雅关闭HTTP服务器，停止接收新连接并等待进行中的请求完成
+func (s *HTTPServer) Shutdown(ctx context.Context) error {
+	// WebSocket连接已被劫持，http.Server.Shutdown 不会处理，需要单独发送关闭帧
+	if wsManager := websocket.GetGlobalWebSocketManager(); wsManager != nil {
+		wsManager.Shutdown()
+	}
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("HTTP服务器关闭失败: %w", err)
+	}
+
+	logrus.Info("HTTP服务器已关闭")
+	return nil
+}
@@ -14,7 +14,7 @@ const MonitoringCard = ({ estimate, currentPosition, onDelete, onToggle }) => {
   const actionText = getDetailedActionText(estimate.action_type, estimate.side);
 
   // 判断是否为基于仓位的操作
-  const isPositionBasedAction = ['take_profit', 'addition'].includes(estimate.action_type);
+  const isPositionBasedAction = ['take_profit', 'stop_loss', 'addition'].includes(estimate.action_type);
   
   // 计算实际交易数量(用于预估盈亏计算)
   const calculateActualQuantity = () => {
@@ -32,8 +32,8 @@ const MonitoringCard = ({ estimate, currentPosition, onDelete, onToggle }) => {
 
   // 计算预估盈利/止损
   const calculateEstimatedPnL = () => {
-    // 只有止盈操作才计算盈亏
-    if (estimate.action_type !== 'take_profit') {
+    // 只有止盈/止损操作才计算盈亏
+    if (estimate.action_type !== 'take_profit' && estimate.action_type !== 'stop_loss') {
       return 0;
     }
     
@@ -107,7 +107,7 @@ const MonitoringCard = ({ estimate, currentPosition, onDelete, onToggle }) => {
             <Switch
               size="small"
               checked={estimate.enabled}
-              onChange={(checked) => onToggle && onToggle(estimate.id, checked)}
+              onChange={(checked) => onToggle && onToggle(estimate.id, checked, estimate.updated_at)}
               title={estimate.enabled ? "关闭监听" : "开启监听"}
             />
           </div>
@@ -182,8 +182,8 @@ const MonitoringCard = ({ estimate, currentPosition, onDelete, onToggle }) => {
           </div>
         )}
         
-        {/* 预估盈亏 - 只对止盈操作显示 */}
-        {estimate.action_type === 'take_profit' && (
+        {/* 预估盈亏 - 只对止盈/止损操作显示 */}
+        {(estimate.action_type === 'take_profit' || estimate.action_type === 'stop_loss') && (
           <div className="detail-row">
             <span className="detail-label">预估盈亏</span>
             <span className={`detail-value ${isProfit ? 'profit' : 'loss'}`}>
@@ -76,9 +76,9 @@ const useEstimates = () => {
   }, [symbolEstimates]);
 
   // 切换预估启用/禁用状态
-  const toggleEstimate = useCallback(async (id, enabled) => {
+  const toggleEstimate = useCallback(async (id, enabled, updatedAt) => {
     try {
-      await toggleEstimateEnabled(id, enabled);
+      await toggleEstimateEnabled(id, enabled, updatedAt);
       // WebSocket会自动推送更新的数据
       return true;
     } catch (error) {
@@ -64,8 +64,8 @@ const usePriceData = () => {
       WebSocketManager.addConnectionListener(handleConnect);
       WebSocketManager.addDisconnectionListener(handleDisconnect);
 
-      // 订阅价格数据
-      WebSocketManager.subscribe('prices', handlePriceMessage);
+      // 订阅价格数据，增量推送的数据由 handlePriceMessage 合并
+      WebSocketManager.subscribe('prices', handlePriceMessage, { delta: true });
 
       // 检查初始连接状态
       const status = WebSocketManager.getStatus();
@@ -215,15 +215,18 @@ const Orders = () => {
           'open': '买入',
           'addition': '加仓',
           'take_profit': '卖出',
+          'stop_loss': '止损卖出',
         } : {
           'open': '开仓',
           'addition': '加仓',
           'take_profit': '止盈',
+          'stop_loss': '止损',
         };
         const colorMap = {
           'open': 'green',
           'addition': 'green',
           'take_profit': 'blue',
+          'stop_loss': 'red',
         };
         return <Tag color={colorMap[actionType]}>{typeMap[actionType] || actionType}</Tag>;
       }
@@ -237,18 +240,24 @@ const Orders = () => {
         const statusMap = {
           'listening': '监听中',
           'triggered': '已触发',
+          'verified': '已确认',
+          'execution_mismatch': '执行不一致',
+          'expired': '已过期',
           'failed': '失败'
         };
         const colorMap = {
           'listening': 'processing',
           'triggered': 'success',
+          'verified': 'success',
+          'execution_mismatch': 'warning',
+          'expired': 'default',
           'failed': 'error'
         };
         
         const tag = <Tag color={colorMap[status]}>{statusMap[status] || status}</Tag>;
         
         // 如果是失败状态且有错误信息，显示悬浮提示
-        if (status === 'failed' && record.error_message) {
+        if ((status === 'failed' || status === 'execution_mismatch') && record.error_message) {
           return (
             <Tooltip 
               title={record.error_message}
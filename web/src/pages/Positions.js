@@ -469,9 +469,9 @@ const Positions = () => {
     // 数据会通过全局estimates自动更新，无需手动刷新
   };
 
-  const handleToggleEstimate = async (estimateId, enabled) => {
+  const handleToggleEstimate = async (estimateId, enabled, updatedAt) => {
     try {
-      await toggleEstimateEnabled(estimateId, enabled);
+      await toggleEstimateEnabled(estimateId, enabled, updatedAt);
       message.success(`监听已${enabled ? '开启' : '关闭'}`);
       
       // 数据会通过全局estimates自动更新，无需手动刷新
@@ -588,9 +588,9 @@ const TradingPairs = () => {
     }
   };
 
-  const handleToggleEstimate = async (estimateId, enabled) => {
+  const handleToggleEstimate = async (estimateId, enabled, updatedAt) => {
     try {
-      await toggleEstimateEnabled(estimateId, enabled);
+      await toggleEstimateEnabled(estimateId, enabled, updatedAt);
       message.success(`监听已${enabled ? '开启' : '关闭'}`);
 
       // 重新获取当前币种的监控数据
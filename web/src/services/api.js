@@ -88,9 +88,9 @@ export const deleteEstimate = async (id) => {
 
 
 // 切换价格监听状态
-export const toggleEstimateEnabled = async (id, enabled) => {
+export const toggleEstimateEnabled = async (id, enabled, updatedAt) => {
   try {
-    const payload = { enabled };
+    const payload = { enabled, updated_at: updatedAt };
     const response = await api.put(`/estimates/${id}/toggle`, payload);
     return response.data;
   } catch (error) {
@@ -1,4 +1,4 @@
-// 操作类型常量（对应freqtrade的3种核心操作）
+// 操作类型常量（对应freqtrade的核心操作）
 export const ACTIONS = {
   open: {
     title: '开仓',
@@ -23,6 +23,14 @@ export const ACTIONS = {
     priceRange: { min: -100, max: 100 },
     priceBase: 'current', // 基于当前价格
     color: '#1890ff'
+  },
+  stop_loss: {
+    title: '止损',
+    priceLabel: '止损价格',
+    quantityLabel: '止损比例',
+    priceRange: { min: -100, max: 100 },
+    priceBase: 'current', // 基于当前价格
+    color: '#ff4d4f'
   }
 };
 
@@ -40,14 +48,16 @@ export const ORDER_STATUS_MAP = {
 export const ACTION_TYPE_COLORS = {
   'open': 'green',
   'addition': 'green',
-  'take_profit': 'blue'
+  'take_profit': 'blue',
+  'stop_loss': 'red'
 };
 
 // 操作类型文本映射
 export const ACTION_TYPE_TEXT = {
   'open': '开仓',
   'addition': '加仓',
-  'take_profit': '止盈'
+  'take_profit': '止盈',
+  'stop_loss': '止损'
 };
 
 // 详细操作类型文本映射
@@ -62,7 +72,11 @@ export const DETAILED_ACTION_TYPE_TEXT = {
   
   // 止盈类型
   'take_profit_long': '多头止盈',
-  'take_profit_short': '空头止盈'
+  'take_profit_short': '空头止盈',
+
+  // 止损类型
+  'stop_loss_long': '多头止损',
+  'stop_loss_short': '空头止损'
 };
 
 // 根据action_type和side获取详细操作类型文本
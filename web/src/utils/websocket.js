@@ -8,6 +8,7 @@ class WebSocketManager {
     this.reconnectAttempts = 0;
     this.maxReconnectAttempts = 5;
     this.subscriptions = new Map(); // dataType -> Set of callbacks
+    this.subscribeOptions = new Map(); // dataType -> 订阅参数 { symbols, delta }
     this.heartbeatInterval = null;
     this.heartbeatTimer = 30000; // 30秒
     
@@ -166,12 +167,14 @@ class WebSocketManager {
     }
   }
 
-  // 订阅数据类型
-  subscribe(dataType, callback) {
+  // 订阅数据类型，options 可选（目前仅 prices 支持）：
+  // symbols 仅接收指定币种的数据，delta 只接收变化的币种（需按 symbol 合并数据）
+  subscribe(dataType, callback, options = {}) {
     if (!this.subscriptions.has(dataType)) {
       this.subscriptions.set(dataType, new Set());
     }
     this.subscriptions.get(dataType).add(callback);
+    this.subscribeOptions.set(dataType, options);
 
     // 如果没有连接，尝试连接
     if (!this.isConnected && !this.isConnecting) {
@@ -182,14 +185,26 @@ class WebSocketManager {
 
     // 发送订阅消息到服务器
     this.send({
-      type: 'subscribe',
-      dataType: dataType,
+      ...this.buildSubscribeMessage(dataType),
       timestamp: Date.now()
     });
 
 
   }
 
+  // 构建订阅消息
+  buildSubscribeMessage(dataType) {
+    const { symbols, delta } = this.subscribeOptions.get(dataType) || {};
+    const message = { type: 'subscribe', dataType };
+    if (symbols && symbols.length > 0) {
+      message.symbols = symbols;
+    }
+    if (delta) {
+      message.delta = true;
+    }
+    return message;
+  }
+
   // 取消订阅数据类型
   unsubscribe(dataType, callback) {
     if (this.subscriptions.has(dataType)) {
@@ -198,6 +213,7 @@ class WebSocketManager {
       // 如果没有更多回调，从服务器取消订阅
       if (this.subscriptions.get(dataType).size === 0) {
         this.subscriptions.delete(dataType);
+        this.subscribeOptions.delete(dataType);
         this.send({
           type: 'unsubscribe',
           dataType: dataType,
@@ -297,8 +313,7 @@ class WebSocketManager {
   resubscribeAll() {
     this.subscriptions.forEach((callbacks, dataType) => {
       this.send({
-        type: 'subscribe',
-        dataType: dataType,
+        ...this.buildSubscribeMessage(dataType),
         timestamp: Date.now()
       });
     });